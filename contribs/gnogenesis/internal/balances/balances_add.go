@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
@@ -32,6 +33,10 @@ type balancesAddCfg struct {
 	balanceSheet  string
 	singleEntries commands.StringArr
 	parseExport   string
+
+	vestingStart    int64
+	vestingDuration time.Duration
+	vestingPeriods  int
 }
 
 // newBalancesAddCmd creates the genesis balances add subcommand
@@ -73,6 +78,27 @@ func (c *balancesAddCfg) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"the path to the transaction export containing a list of transactions (JSONL)",
 	)
+
+	fs.Int64Var(
+		&c.vestingStart,
+		"vesting-start",
+		0,
+		"the unix time at which the added balances start vesting (defaults to the genesis time)",
+	)
+
+	fs.DurationVar(
+		&c.vestingDuration,
+		"vesting-duration",
+		0,
+		"the duration over which the added balances vest; no vesting, if zero",
+	)
+
+	fs.IntVar(
+		&c.vestingPeriods,
+		"vesting-periods",
+		0,
+		"the number of periods at the end of which equal parts of the added balances vest; linear vesting, if zero",
+	)
 }
 
 func execBalancesAdd(ctx context.Context, cfg *balancesAddCfg, io commands.IO) error {
@@ -142,6 +168,21 @@ func execBalancesAdd(ctx context.Context, cfg *balancesAddCfg, io commands.IO) e
 
 	// Construct the initial genesis balance sheet
 	state := genesis.AppState.(gnoland.GnoGenesisState)
+
+	// Lock the added balances until they vest, if requested
+	if cfg.vestingDuration != 0 {
+		start := genesis.GenesisTime
+		if cfg.vestingStart != 0 {
+			start = time.Unix(cfg.vestingStart, 0)
+		}
+
+		vesting, err := getVestingFromBalances(finalBalances, start, cfg.vestingDuration, cfg.vestingPeriods)
+		if err != nil {
+			return err
+		}
+
+		state.Vesting = mergeVesting(vesting, state.Vesting)
+	}
 	genesisBalances, err := mapGenesisBalancesFromState(state)
 	if err != nil {
 		return err
@@ -284,6 +325,47 @@ func getBalancesFromTransactions(
 	return balances, nil
 }
 
+// getVestingFromBalances constructs the vesting schedules of the given balances
+func getVestingFromBalances(
+	balances gnoland.Balances,
+	start time.Time,
+	duration time.Duration,
+	periods int,
+) ([]gnoland.Vesting, error) {
+	vesting := make([]gnoland.Vesting, 0, len(balances))
+
+	for _, balance := range balances {
+		schedule, err := gnoland.NewVestingSchedule(balance.Amount, start, duration, periods)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vesting of %s, %w", balance.Address, err)
+		}
+
+		vesting = append(vesting, gnoland.Vesting{
+			Address:  balance.Address,
+			Schedule: schedule,
+		})
+	}
+
+	return vesting, nil
+}
+
+// mergeVesting merges the two vesting lists, with the first
+// one having precedence for the same address
+func mergeVesting(vesting, genesisVesting []gnoland.Vesting) []gnoland.Vesting {
+	present := make(map[types.Address]struct{}, len(vesting))
+	for _, v := range vesting {
+		present[v.Address] = struct{}{}
+	}
+
+	for _, v := range genesisVesting {
+		if _, ok := present[v.Address]; !ok {
+			vesting = append(vesting, v)
+		}
+	}
+
+	return vesting
+}
+
 // mapGenesisBalancesFromState extracts the initial account balances from the
 // genesis app state
 func mapGenesisBalancesFromState(state gnoland.GnoGenesisState) (gnoland.Balances, error) {
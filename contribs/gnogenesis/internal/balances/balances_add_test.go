@@ -142,6 +142,56 @@ func TestGenesis_Balances_Add(t *testing.T) {
 		}
 	})
 
+	t.Run("balances with vesting", func(t *testing.T) {
+		t.Parallel()
+
+		dummyKey := common.GetDummyKeys(t, 1)[0]
+
+		tempGenesis, cleanup := testutils.NewTestFile(t)
+		t.Cleanup(cleanup)
+
+		genesis := common.GetDefaultGenesis()
+		require.NoError(t, genesis.SaveAs(tempGenesis.Name()))
+
+		// Create the command
+		cmd := NewBalancesCmd(commands.NewTestIO())
+		args := []string{
+			"add",
+			"--genesis-path",
+			tempGenesis.Name(),
+			"--single",
+			fmt.Sprintf("%s=%s", dummyKey.Address().String(), ugnot.ValueString(2400)),
+			"--vesting-start",
+			"1000",
+			"--vesting-duration",
+			"24h",
+			"--vesting-periods",
+			"24",
+		}
+
+		// Run the command
+		cmdErr := cmd.ParseAndRun(context.Background(), args)
+		require.NoError(t, cmdErr)
+
+		// Validate the genesis was updated
+		genesis, loadErr := types.GenesisDocFromFile(tempGenesis.Name())
+		require.NoError(t, loadErr)
+
+		state, ok := genesis.AppState.(gnoland.GnoGenesisState)
+		require.True(t, ok)
+
+		require.Len(t, state.Vesting, 1)
+		assert.Equal(t, dummyKey.Address(), state.Vesting[0].Address)
+
+		schedule, ok := state.Vesting[0].Schedule.(std.PeriodicVesting)
+		require.True(t, ok)
+		assert.Equal(t, int64(1000), schedule.StartTime)
+		assert.Equal(t, int64(1000+24*3600), schedule.GetEndTime())
+		require.Len(t, schedule.Periods, 24)
+		assert.Equal(t, std.NewCoins(std.NewCoin(ugnot.Denom, 100)), schedule.Periods[0].Amount)
+		assert.Equal(t, std.NewCoins(std.NewCoin(ugnot.Denom, 2400)), schedule.GetOriginalVesting())
+	})
+
 	t.Run("balances from sheet", func(t *testing.T) {
 		t.Parallel()
 
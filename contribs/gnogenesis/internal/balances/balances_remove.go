@@ -80,11 +80,19 @@ func execBalancesRemove(cfg *balancesRemoveCfg, io commands.IO) error {
 		return errBalanceNotFound
 	}
 
-	// Drop the account pre-mine
+	// Drop the account pre-mine, and its vesting
 	delete(genesisBalances, address)
 
+	vesting := make([]gnoland.Vesting, 0, len(state.Vesting))
+	for _, v := range state.Vesting {
+		if v.Address != address {
+			vesting = append(vesting, v)
+		}
+	}
+
 	// Save the balances
 	state.Balances = genesisBalances.List()
+	state.Vesting = vesting
 	genesis.AppState = state
 
 	// Save the updated genesis
@@ -101,6 +101,13 @@ func execVerify(cfg *verifyCfg, io commands.IO) error {
 				return fmt.Errorf("invalid balance: %w", err)
 			}
 		}
+
+		// Validate the vesting schedules
+		for _, vesting := range state.Vesting {
+			if err := vesting.Verify(); err != nil {
+				return fmt.Errorf("invalid vesting: %w", err)
+			}
+		}
 	}
 
 	io.Printfln("Genesis at %s is valid", cfg.GenesisPath)
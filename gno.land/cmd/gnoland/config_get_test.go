@@ -193,6 +193,14 @@ func TestConfig_Get_Base(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"retained blocks fetched",
+			"retain_blocks",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RetainBlocks, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
 		{
 			"validator key fetched",
 			"priv_validator_key_file",
@@ -785,6 +793,30 @@ func TestConfig_Get_RPC(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"rpc max subscription clients",
+			"rpc.max_subscription_clients",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RPC.MaxSubscriptionClients, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
+		{
+			"rpc max subscriptions per client",
+			"rpc.max_subscriptions_per_client",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RPC.MaxSubscriptionsPerClient, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
+		{
+			"rpc subscription buffer size",
+			"rpc.subscription_buffer_size",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RPC.SubscriptionBufferSize, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
 		{
 			"tx commit broadcast timeout",
 			"rpc.timeout_broadcast_tx_commit",
@@ -926,3 +958,60 @@ func TestConfig_Get_Mempool(t *testing.T) {
 
 	verifyGetTestTableCommon(t, testTable)
 }
+
+func TestConfig_Get_StateSync(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testGetCase{
+		{
+			"enable flag",
+			"statesync.enable",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.Enable, unmarshalJSONCommon[bool](t, value))
+			},
+			false,
+		},
+		{
+			"trust height",
+			"statesync.trust_height",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.TrustHeight, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
+		{
+			"trust hash",
+			"statesync.trust_hash",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.TrustHash, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"trust period",
+			"statesync.trust_period",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.TrustPeriod, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
+		{
+			"discovery time",
+			"statesync.discovery_time",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.DiscoveryTime, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
+		{
+			"chunk request timeout",
+			"statesync.chunk_request_timeout",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.ChunkRequestTimeout, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
+	}
+
+	verifyGetTestTableCommon(t, testTable)
+}
@@ -185,6 +185,16 @@ func TestConfig_Set_Base(t *testing.T) {
 				assert.Equal(t, value, loadedCfg.DBPath)
 			},
 		},
+		{
+			"retained blocks updated",
+			[]string{
+				"retain_blocks",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RetainBlocks))
+			},
+		},
 		{
 			"validator key updated",
 			[]string{
@@ -678,6 +688,36 @@ func TestConfig_Set_RPC(t *testing.T) {
 				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.MaxOpenConnections))
 			},
 		},
+		{
+			"rpc max subscription clients updated",
+			[]string{
+				"rpc.max_subscription_clients",
+				"10",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.MaxSubscriptionClients))
+			},
+		},
+		{
+			"rpc max subscriptions per client updated",
+			[]string{
+				"rpc.max_subscriptions_per_client",
+				"10",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.MaxSubscriptionsPerClient))
+			},
+		},
+		{
+			"rpc subscription buffer size updated",
+			[]string{
+				"rpc.subscription_buffer_size",
+				"10",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.SubscriptionBufferSize))
+			},
+		},
 		{
 			"tx commit broadcast timeout updated",
 			[]string{
@@ -818,6 +858,65 @@ func TestConfig_Set_Mempool(t *testing.T) {
 	verifySetTestTableCommon(t, testTable)
 }
 
+func TestConfig_Set_StateSync(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testSetCase{
+		{
+			"trust height updated",
+			[]string{
+				"statesync.trust_height",
+				"100",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.StateSync.TrustHeight))
+			},
+		},
+		{
+			"trust hash updated",
+			[]string{
+				"statesync.trust_hash",
+				"0A0B0C",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.TrustHash)
+			},
+		},
+		{
+			"trust period updated",
+			[]string{
+				"statesync.trust_period",
+				"24h0m0s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.TrustPeriod.String())
+			},
+		},
+		{
+			"discovery time updated",
+			[]string{
+				"statesync.discovery_time",
+				"30s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.DiscoveryTime.String())
+			},
+		},
+		{
+			"chunk request timeout updated",
+			[]string{
+				"statesync.chunk_request_timeout",
+				"5s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.ChunkRequestTimeout.String())
+			},
+		},
+	}
+
+	verifySetTestTableCommon(t, testTable)
+}
+
 func TestConfig_Set_Application(t *testing.T) {
 	t.Parallel()
 
@@ -842,6 +941,26 @@ func TestConfig_Set_Application(t *testing.T) {
 				assert.Equal(t, types.PruneStrategy(value), loadedCfg.Application.PruneStrategy)
 			},
 		},
+		{
+			"snapshot interval updated",
+			[]string{
+				"application.snapshot_interval",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.SnapshotInterval))
+			},
+		},
+		{
+			"snapshot keep recent updated",
+			[]string{
+				"application.snapshot_keep_recent",
+				"5",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.SnapshotKeepRecent))
+			},
+		},
 	}
 
 	verifySetTestTableCommon(t, testTable)
@@ -0,0 +1,186 @@
+package gnoclient
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var ErrMissingProof = errors.New("missing proof in query result")
+
+// VMStoreName is the name of the store of the vm in gno.land, whose keys are
+// proven by the vm queries.
+const VMStoreName = "main"
+
+// QueryObjectWithProof returns the persisted object at the root of the view of
+// QueryObject for target: the object itself for an ObjectID, or the package
+// block of a realm for a realm path or variable. The object is verified with
+// VerifyQueryProof, against the chain chainID and the validator set vals; see
+// VerifyQueryProof.
+func (c *Client) QueryObjectWithProof(target, chainID string, vals *types.ValidatorSet) (gno.Object, *ctypes.ResultABCIQuery, error) {
+	qres, err := c.queryWithProof("vm/qobject", []byte(target))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var oo gno.Object
+	if err := amino.Unmarshal(qres.Response.Value, &oo); err != nil {
+		return nil, nil, errors.Wrap(err, "decoding object")
+	}
+	var oid gno.ObjectID
+	if err := oid.UnmarshalAmino(target); err != nil {
+		// <pkgpath>[.<name>], see vm/qobject.
+		pkgPath := target
+		if slash := strings.IndexByte(target, '/'); slash >= 0 {
+			if dot := strings.IndexByte(target[slash:], '.'); dot >= 0 {
+				pkgPath = target[:slash+dot]
+			}
+		}
+		if !isPackageBlock(oo, pkgPath) {
+			return nil, nil, fmt.Errorf("object %s is not the package block of %s", oo.GetObjectID(), pkgPath)
+		}
+		// The proof verifies that the value is the object oid.
+		oid = oo.GetObjectID()
+	}
+	if err := c.VerifyQueryProof(qres, chainID, VMStoreName, []byte(oid.String()), vals); err != nil {
+		return nil, nil, err
+	}
+
+	return oo, qres, nil
+}
+
+// isPackageBlock returns whether oo is the persisted package block of pkgPath.
+func isPackageBlock(oo gno.Object, pkgPath string) bool {
+	b, ok := oo.(*gno.Block)
+	if !ok || b.GetObjectID().PkgID != gno.PkgIDFromPkgPath(pkgPath) {
+		return false
+	}
+	ref, ok := b.Source.(gno.RefNode)
+	return ok && ref.PkgPath == pkgPath && ref.File == ""
+}
+
+// QueryMemPackageWithProof returns the files of the package pkgPath, verified
+// with VerifyQueryProof against the chain chainID and the validator set vals;
+// see VerifyQueryProof.
+func (c *Client) QueryMemPackageWithProof(pkgPath, chainID string, vals *types.ValidatorSet) (*std.MemPackage, *ctypes.ResultABCIQuery, error) {
+	qres, err := c.queryWithProof("vm/qfile", []byte(pkgPath))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.VerifyQueryProof(qres, chainID, VMStoreName, gno.MemPackageIAVLKey(pkgPath), vals); err != nil {
+		return nil, nil, err
+	}
+
+	var mpkg std.MemPackage
+	if err := amino.Unmarshal(qres.Response.Value, &mpkg); err != nil {
+		return nil, nil, errors.Wrap(err, "decoding package")
+	}
+	if mpkg.Path != pkgPath {
+		return nil, nil, fmt.Errorf("proven package %s is not %s", mpkg.Path, pkgPath)
+	}
+
+	return &mpkg, qres, nil
+}
+
+// queryWithProof makes a query requesting a proof at the height preceding the
+// latest block, whose header commits to the app hash of that height.
+func (c *Client) queryWithProof(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	latest, err := c.LatestBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	if latest < 3 {
+		return nil, fmt.Errorf("cannot query with proof before height 3, latest height is %d", latest)
+	}
+
+	return c.Query(QueryCfg{
+		Path: path,
+		Data: data,
+		ABCIQueryOptions: rpcclient.ABCIQueryOptions{
+			Height: latest - 1,
+			Prove:  true,
+		},
+	})
+}
+
+// VerifyQueryProof verifies the proof of the value of a query result, made at
+// the height h, against the app hash of the header of the block h+1 of the
+// chain chainID, which is fetched with the commit RPC. The header must be
+// signed by more than 2/3 of the validator set vals.
+//
+// The value must be proven at key in the store storeName, e.g. VMStoreName.
+// For the objects of vm/qobject, key is the ObjectID of the object, which is
+// proven with the chain of its owners, up to the one stored at the key of the
+// proof.
+//
+// vals should be trusted by the caller, like the validator set of the genesis
+// of a chain whose validators don't change. If it is nil, the validator set is
+// fetched from the same node, which then only proves that the node served a
+// value consistent with a header it also served.
+func (c *Client) VerifyQueryProof(qres *ctypes.ResultABCIQuery, chainID, storeName string, key []byte, vals *types.ValidatorSet) error {
+	if err := c.validateRPCClient(); err != nil {
+		return err
+	}
+	if chainID == "" {
+		return errors.New("missing ChainID")
+	}
+	if qres.Response.Error != nil {
+		return errors.Wrapf(qres.Response.Error, "query failed: log:%s", qres.Response.Log)
+	}
+	proof := qres.Response.Proof
+	if proof == nil || len(proof.Ops) == 0 {
+		return ErrMissingProof
+	}
+	if qres.Response.Height <= 0 {
+		return ErrInvalidBlockHeight
+	}
+
+	// The app hash of the height is in the header of the next block.
+	height := qres.Response.Height + 1
+	commit, err := c.RPCClient.Commit(&height)
+	if err != nil {
+		return fmt.Errorf("commit query failed: %w", err)
+	}
+	sh := commit.SignedHeader
+	if err := sh.ValidateBasic(chainID); err != nil {
+		return errors.Wrap(err, "invalid signed header")
+	}
+	if vals == nil {
+		res, err := c.RPCClient.Validators(&height)
+		if err != nil {
+			return fmt.Errorf("validators query failed: %w", err)
+		}
+		vals = types.NewValidatorSet(res.Validators)
+	}
+	if !bytes.Equal(sh.ValidatorsHash, vals.Hash()) {
+		return fmt.Errorf("header of height %d is not from the validator set %X", height, vals.Hash())
+	}
+	if err := vals.VerifyCommit(chainID, sh.Commit.BlockID, height, sh.Commit); err != nil {
+		return errors.Wrap(err, "invalid commit")
+	}
+
+	// The value must be proven at the expected key.
+	if !bytes.Equal(qres.Response.Key, key) {
+		return fmt.Errorf("result of key %q is not for key %q", qres.Response.Key, key)
+	}
+	keypath := merkle.KeyPath{}.AppendKey([]byte(storeName), merkle.KeyEncodingURL)
+	if proof.Ops[0].Type == vm.ProofOpObject && len(proof.Ops) > 1 {
+		keypath = keypath.AppendKey(proof.Ops[1].Key, merkle.KeyEncodingURL)
+	}
+	keypath = keypath.AppendKey(key, merkle.KeyEncodingURL)
+	if err := vm.ProofRuntime().VerifyValue(proof, sh.AppHash, keypath.String(), qres.Response.Value); err != nil {
+		return errors.Wrap(err, "invalid proof")
+	}
+
+	return nil
+}
@@ -126,6 +126,32 @@ func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.Resul
 	return string(qres.Response.Data), qres, nil
 }
 
+// QueryObject returns a JSON view of the persisted state of a realm. The target is
+// either a realm path like "gno.land/r/demo/boards", to list its package-level
+// variables, a variable of a realm like "gno.land/r/demo/boards.gBoards", or an
+// ObjectID like "2bd6...d4f1:12". The elements of arrays, slices, maps and AVL
+// trees are paginated with offset and limit; a zero limit uses the node default.
+func (c *Client) QueryObject(target string, offset, limit int) (string, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return "", nil, err
+	}
+
+	path := fmt.Sprintf("vm/qobject?offset=%d", offset)
+	if limit > 0 {
+		path += fmt.Sprintf("&limit=%d", limit)
+	}
+
+	qres, err := c.RPCClient.ABCIQuery(path, []byte(target))
+	if err != nil {
+		return "", nil, errors.Wrap(err, "query qobject")
+	}
+	if qres.Response.Error != nil {
+		return "", nil, errors.Wrapf(qres.Response.Error, "QueryObject failed: log:%s", qres.Response.Log)
+	}
+
+	return string(qres.Response.Data), qres, nil
+}
+
 // Block gets the latest block at height, if any
 // Height must be larger than 0
 func (c *Client) Block(height int64) (*ctypes.ResultBlock, error) {
@@ -164,6 +190,41 @@ func (c *Client) BlockResult(height int64) (*ctypes.ResultBlockResults, error) {
 	return blockResults, nil
 }
 
+// TxSearch searches for the transactions matching the query, e.g.
+// "tx.signer = 'g1...' AND message.type = 'exec'", at the page, of perPage
+// transactions, in the order ("asc" or "desc") of their height and index.
+// Zero values select the defaults.
+// The node must index its transactions (kv tx event store)
+func (c *Client) TxSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, ErrMissingRPCClient
+	}
+
+	result, err := c.RPCClient.TxSearch(query, page, perPage, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("tx search failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// BlockSearch searches for the blocks matching the query, e.g.
+// "block.height >= 10", at the page, of perPage blocks, in the order
+// ("asc" or "desc") of their height. Zero values select the defaults.
+// The node must index its blocks (kv tx event store)
+func (c *Client) BlockSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, ErrMissingRPCClient
+	}
+
+	result, err := c.RPCClient.BlockSearch(query, page, perPage, orderBy)
+	if err != nil {
+		return nil, fmt.Errorf("block search failed: %w", err)
+	}
+
+	return result, nil
+}
+
 // LatestBlockHeight gets the latest block height on the chain
 func (c *Client) LatestBlockHeight() (int64, error) {
 	if err := c.validateRPCClient(); err != nil {
@@ -62,6 +62,34 @@ func TestRender(t *testing.T) {
 	assert.Equal(t, data.Response.Data, expectedRender)
 }
 
+func TestQueryObject(t *testing.T) {
+	t.Parallel()
+	expectedObject := []byte(`{"kind":"package","pkgpath":"gno.land/r/demo/boards"}`)
+
+	client := Client{
+		Signer: &mockSigner{},
+		RPCClient: &mockRPCClient{
+			abciQuery: func(path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+				assert.Equal(t, "vm/qobject?offset=10&limit=5", path)
+				assert.Equal(t, "gno.land/r/demo/boards.gBoards", string(data))
+				res := &ctypes.ResultABCIQuery{
+					Response: abci.ResponseQuery{
+						ResponseBase: abci.ResponseBase{
+							Data: expectedObject,
+						},
+					},
+				}
+				return res, nil
+			},
+		},
+	}
+
+	res, data, err := client.QueryObject("gno.land/r/demo/boards.gBoards", 10, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expectedObject), res)
+	assert.Equal(t, expectedObject, data.Response.Data)
+}
+
 // Call tests
 func TestCallSingle(t *testing.T) {
 	t.Parallel()
@@ -1217,6 +1245,69 @@ func TestBlockResults(t *testing.T) {
 	assert.Equal(t, height, blockResult.Height)
 }
 
+func TestTxSearch(t *testing.T) {
+	t.Parallel()
+
+	const query = "message.type = 'exec'"
+
+	client := &Client{
+		Signer: &mockSigner{},
+		RPCClient: &mockRPCClient{
+			txSearch: func(q string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+				assert.Equal(t, query, q)
+				assert.Equal(t, 2, page)
+				assert.Equal(t, 10, perPage)
+				assert.Equal(t, "desc", orderBy)
+
+				return &ctypes.ResultTxSearch{
+					Txs:        []*ctypes.ResultTx{{Height: 5}},
+					TotalCount: 11,
+				}, nil
+			},
+		},
+	}
+
+	result, err := client.TxSearch(query, 2, 10, "desc")
+	require.NoError(t, err)
+	assert.Equal(t, 11, result.TotalCount)
+	require.Len(t, result.Txs, 1)
+	assert.Equal(t, int64(5), result.Txs[0].Height)
+
+	_, err = (&Client{}).TxSearch(query, 0, 0, "")
+	assert.ErrorIs(t, err, ErrMissingRPCClient)
+}
+
+func TestBlockSearch(t *testing.T) {
+	t.Parallel()
+
+	const query = "block.height > 4"
+
+	client := &Client{
+		Signer: &mockSigner{},
+		RPCClient: &mockRPCClient{
+			blockSearch: func(q string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+				assert.Equal(t, query, q)
+
+				return &ctypes.ResultBlockSearch{
+					Blocks: []*ctypes.ResultBlock{
+						{Block: &types.Block{Header: types.Header{Height: 5}}},
+					},
+					TotalCount: 1,
+				}, nil
+			},
+		},
+	}
+
+	result, err := client.BlockSearch(query, 0, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TotalCount)
+	require.Len(t, result.Blocks, 1)
+	assert.Equal(t, int64(5), result.Blocks[0].Block.Height)
+
+	_, err = (&Client{}).BlockSearch(query, 0, 0, "")
+	assert.ErrorIs(t, err, ErrMissingRPCClient)
+}
+
 func TestLatestBlockHeight(t *testing.T) {
 	t.Parallel()
 
@@ -1,8 +1,10 @@
 package gnoclient
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland"
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
@@ -11,7 +13,11 @@ import (
 	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/log"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
@@ -714,6 +720,143 @@ func newInMemorySigner(t *testing.T, chainid string) *SignerFromKeybase {
 	}
 }
 
+func TestSearch_Integration(t *testing.T) {
+	// Set up in-memory node, indexing the transactions and blocks
+	config := integration.TestingMinimalNodeConfig(gnoenv.RootDir())
+	config.TMConfig.TxEventStore.EventStoreType = kv.EventStoreType
+
+	node, remoteAddr := integration.TestingInMemoryNode(t, log.NewNoopLogger(), config)
+	defer node.Stop()
+
+	// Init Signer & RPCClient
+	signer := newInMemorySigner(t, "tendermint_test")
+	rpcClient, err := rpcclient.NewHTTPClient(remoteAddr)
+	require.NoError(t, err)
+
+	client := Client{
+		Signer:    signer,
+		RPCClient: rpcClient,
+	}
+
+	// Make Tx config
+	baseCfg := BaseTxCfg{
+		GasFee:         ugnot.ValueString(2100000),
+		GasWanted:      21000000,
+		AccountNumber:  0,
+		SequenceNumber: 0,
+		Memo:           "",
+	}
+
+	caller, err := client.Signer.Info()
+	require.NoError(t, err)
+
+	// Emit an event from a run transaction
+	msg := vm.MsgRun{
+		Caller: caller.GetAddress(),
+		Package: &std.MemPackage{
+			Name: "main",
+			Files: []*std.MemFile{
+				{
+					Name: "main.gno",
+					Body: `package main
+
+import "std"
+
+func main() {
+	std.Emit("Greeting", "to", "world")
+}`,
+				},
+			},
+		},
+	}
+
+	res, err := client.Run(baseCfg, msg)
+	require.NoError(t, err)
+
+	// The transactions are indexed asynchronously
+	query := fmt.Sprintf(
+		"tx.signer = '%s' AND message.type = 'run' AND Greeting.to = 'world'",
+		caller.GetAddress(),
+	)
+
+	var result *ctypes.ResultTxSearch
+	require.Eventually(t, func() bool {
+		result, err = client.TxSearch(query, 0, 0, "")
+		require.NoError(t, err)
+
+		return result.TotalCount == 1
+	}, 10*time.Second, 50*time.Millisecond)
+
+	require.Len(t, result.Txs, 1)
+	assert.Equal(t, res.Height, result.Txs[0].Height)
+	assert.Equal(t, res.Hash, result.Txs[0].Hash)
+
+	// The blocks are indexed too
+	blocks, err := client.BlockSearch(fmt.Sprintf("block.height = %d", res.Height), 0, 0, "")
+	require.NoError(t, err)
+	require.Len(t, blocks.Blocks, 1)
+	assert.Equal(t, res.Height, blocks.Blocks[0].Block.Height)
+}
+
+func TestQueryWithProof_Integration(t *testing.T) {
+	// Setup packages
+	rootdir := gnoenv.RootDir()
+	config := integration.TestingMinimalNodeConfig(gnoenv.RootDir())
+	meta := loadpkgs(t, rootdir, "gno.land/r/demo/deep/very/deep")
+	state := config.Genesis.AppState.(gnoland.GnoGenesisState)
+	state.Txs = append(state.Txs, meta...)
+	config.Genesis.AppState = state
+
+	node, remoteAddr := integration.TestingInMemoryNode(t, log.NewNoopLogger(), config)
+	defer node.Stop()
+
+	rpcClient, err := rpcclient.NewHTTPClient(remoteAddr)
+	require.NoError(t, err)
+	client := Client{RPCClient: rpcClient}
+
+	// Proofs are available from height 2, and checked with the next header.
+	require.Eventually(t, func() bool {
+		height, err := client.LatestBlockHeight()
+		return err == nil && height >= 3
+	}, 10*time.Second, 50*time.Millisecond)
+
+	const pkgPath = "gno.land/r/demo/deep/very/deep"
+	chainID := config.Genesis.ChainID
+	mpkg, qres, err := client.QueryMemPackageWithProof(pkgPath, chainID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, pkgPath, mpkg.Path)
+	assert.NotNil(t, mpkg.GetFile("render.gno"))
+
+	// The header must be of the expected chain.
+	key := gnolang.MemPackageIAVLKey(pkgPath)
+	require.NoError(t, client.VerifyQueryProof(qres, chainID, VMStoreName, key, nil))
+	assert.ErrorContains(t, client.VerifyQueryProof(qres, "other-chain", VMStoreName, key, nil), "another chain")
+
+	// The value must be proven at the expected key, in the expected store.
+	otherKey := gnolang.MemPackageIAVLKey("gno.land/r/demo/other")
+	assert.ErrorContains(t, client.VerifyQueryProof(qres, chainID, VMStoreName, otherKey, nil), "is not for key")
+	qres.Response.Key = otherKey
+	assert.ErrorContains(t, client.VerifyQueryProof(qres, chainID, VMStoreName, otherKey, nil), "Key mismatch")
+	qres.Response.Key = key
+	assert.ErrorContains(t, client.VerifyQueryProof(qres, chainID, "base", key, nil), "Key mismatch")
+
+	// A tampered value is rejected.
+	qres.Response.Value = append([]byte{}, qres.Response.Value...)
+	qres.Response.Value[len(qres.Response.Value)-1]++
+	assert.ErrorContains(t, client.VerifyQueryProof(qres, chainID, VMStoreName, key, nil), "invalid proof")
+
+	oo, qres, err := client.QueryObjectWithProof(pkgPath, chainID, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &gnolang.Block{}, oo)
+
+	// The header must be signed by the given validator set.
+	vals := types.NewValidatorSet([]*types.Validator{
+		types.NewValidator(ed25519.GenPrivKey().PubKey(), 1),
+	})
+	oid := []byte(oo.GetObjectID().String())
+	assert.ErrorContains(t, client.VerifyQueryProof(qres, chainID, VMStoreName, oid, vals), "validator set")
+}
+
 func loadpkgs(t *testing.T, rootdir string, paths ...string) []gnoland.TxWithMetadata {
 	t.Helper()
 
@@ -2,6 +2,7 @@
 package gnoland
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -27,6 +28,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
 
 	// Only goleveldb is supported for now.
@@ -44,6 +46,8 @@ type AppOptions struct {
 	InitChainerConfig                          // options related to InitChainer
 	MinGasPrices            string             // optional
 	PruneStrategy           types.PruneStrategy
+	SnapshotDir             string            // optional, state sync snapshots are not supported if empty
+	SnapshotOptions         snapshots.Options // options of the state sync snapshots
 }
 
 // TestAppOptions provides a "ready" default [AppOptions] for use with
@@ -94,6 +98,10 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 
 	appOpts = append(appOpts, sdk.SetPruningOptions(cfg.PruneStrategy.Options()))
 
+	if cfg.SnapshotDir != "" {
+		appOpts = append(appOpts, sdk.SetSnapshotOptions(cfg.SnapshotDir, cfg.SnapshotOptions))
+	}
+
 	// Create BaseApp.
 	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey, appOpts...)
 	baseApp.SetAppVersion("dev")
@@ -106,7 +114,7 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 
 	prmk := params.NewParamsKeeper(mainKey)
 	acck := auth.NewAccountKeeper(mainKey, prmk.ForModule(auth.ModuleName), ProtoGnoAccount)
-	bankk := bank.NewBankKeeper(acck, prmk.ForModule(bank.ModuleName))
+	bankk := bank.NewBankKeeper(mainKey, acck, prmk.ForModule(bank.ModuleName))
 	gpk := auth.NewGasPriceKeeper(mainKey)
 	vmk := vm.NewVMKeeper(baseKey, mainKey, acck, bankk, prmk)
 	vmk.Output = cfg.VMOutput
@@ -115,6 +123,11 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	prmk.Register(bank.ModuleName, bankk)
 	prmk.Register(vm.ModuleName, vmk)
 
+	// Register the invariants, checked by the EndBlocker.
+	ir := sdk.NewInvariantRouter()
+	bank.RegisterInvariants(ir, acck, bankk)
+	vm.RegisterInvariants(ir, vmk)
+
 	// Set InitChainer
 	icc := cfg.InitChainerConfig
 	icc.baseApp = baseApp
@@ -156,12 +169,25 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 		}
 	})
 
+	// Set the restore verifier, to verify the gno entries restored from
+	// a state sync snapshot, as the base store isn't in the app hash.
+	baseApp.SetRestoreVerifier(vmk.VerifyRestoredState)
+
+	// Set the restore hook, to initialize the VMKeeper again
+	// from the state restored from a state sync snapshot.
+	baseApp.SetRestoreHook(func(ctx sdk.Context) {
+		vmk.Reinitialize(cfg.Logger, ctx.MultiStore())
+	})
+
 	// Set up the event collector
 	c := newCollector[validatorUpdate](
 		cfg.EventSwitch,      // global event switch filled by the node
 		validatorEventFilter, // filter fn that keeps the collector valid
 	)
 
+	// Set BeginBlocker
+	baseApp.SetBeginBlocker(BeginBlocker(bankk, vmk, baseApp))
+
 	// Set EndBlocker
 	baseApp.SetEndBlocker(
 		EndBlocker(
@@ -169,6 +195,7 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 			acck,
 			gpk,
 			vmk,
+			ir,
 			baseApp,
 		),
 	)
@@ -227,6 +254,11 @@ func NewApp(
 		MinGasPrices:            appCfg.MinGasPrices,
 		SkipGenesisVerification: genesisCfg.SkipSigVerification,
 		PruneStrategy:           appCfg.PruneStrategy,
+		SnapshotDir:             filepath.Join(dataRootDir, "snapshots"),
+		SnapshotOptions: snapshots.Options{
+			Interval:   appCfg.SnapshotInterval,
+			KeepRecent: appCfg.SnapshotKeepRecent,
+		},
 	}
 	if genesisCfg.SkipFailingTxs {
 		cfg.GenesisTxResultHandler = NoopGenesisTxResultHandler
@@ -347,6 +379,14 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 			panic(err)
 		}
 	}
+	// Lock the genesis balances which vest.
+	for _, vesting := range state.Vesting {
+		if err := cfg.applyGenesisVesting(ctx, vesting); err != nil {
+			return nil, fmt.Errorf("invalid vesting of %s: %w", vesting.Address, err)
+		}
+	}
+	// The supply is that of the genesis balances.
+	cfg.bankk.InitSupply(ctx)
 	// The account keeper's initial genesis state must be set after genesis
 	// accounts are created in account keeeper with genesis balances
 	cfg.acck.InitGenesis(ctx, state.Auth)
@@ -414,6 +454,75 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 	return txResponses, nil
 }
 
+// applyGenesisVesting sets the vesting schedule of a genesis account, which
+// must hold the coins vesting.
+func (cfg InitChainerConfig) applyGenesisVesting(ctx sdk.Context, vesting Vesting) error {
+	if err := vesting.Verify(); err != nil {
+		return err
+	}
+
+	acc := cfg.acck.GetAccount(ctx, vesting.Address)
+	if acc == nil {
+		return errors.New("vesting account must be one of the genesis accounts")
+	}
+	if !acc.GetCoins().IsAllGTE(vesting.Schedule.GetOriginalVesting()) {
+		return fmt.Errorf("balance %s is lower than the vesting amount %s",
+			acc.GetCoins(), vesting.Schedule.GetOriginalVesting())
+	}
+
+	vacc, ok := acc.(std.VestingAccount)
+	if !ok {
+		return fmt.Errorf("account of type %T cannot vest", acc)
+	}
+	if err := vacc.SetVestingSchedule(vesting.Schedule); err != nil {
+		return err
+	}
+	cfg.acck.SetAccount(ctx, acc)
+	return nil
+}
+
+// BeginBlocker defines the logic executed before the transactions of every
+// block. On chains which ran before the supply was tracked, it initializes
+// the supply from the balances of the accounts, once. It reports the
+// validators which signed conflicting votes, as proven by the evidence
+// committed in the block, to the validator realm, before the transactions of
+// the block; the validator updates of the reports are picked up like those of
+// the transactions.
+func BeginBlocker(
+	bankk bank.BankKeeperI,
+	vmk vm.VMKeeperI,
+	app endBlockerApp,
+) func(
+	ctx sdk.Context,
+	req abci.RequestBeginBlock,
+) abci.ResponseBeginBlock {
+	return func(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+		if bankk != nil {
+			bankk.InitSupply(ctx)
+		}
+
+		var evs []abci.Event
+		for _, violation := range req.Violations {
+			ev, ok := violation.Evidence.(*bft.DuplicateVoteEvidence)
+			if !ok {
+				continue
+			}
+
+			args := []string{ev.Address().String(), strconv.FormatInt(ev.Height(), 10)}
+			callEvs, err := vmk.ChainCall(ctx, valRealm, valDoubleSignFn, args)
+			if err != nil {
+				app.Logger().Error("unable to report double sign", "evidence", ev, "err", err)
+				continue
+			}
+			evs = append(evs, callEvs...)
+		}
+
+		return abci.ResponseBeginBlock{
+			ResponseBase: abci.ResponseBase{Events: evs},
+		}
+	}
+}
+
 // endBlockerApp is the app abstraction required by any EndBlocker
 type endBlockerApp interface {
 	// LastBlockHeight returns the latest app height
@@ -424,13 +533,14 @@ type endBlockerApp interface {
 }
 
 // EndBlocker defines the logic executed after every block.
-// Currently, it parses events that happened during execution to calculate
-// validator set changes
+// It runs the scheduled realm calls which are due, and parses events that
+// happened during execution to calculate validator set changes
 func EndBlocker(
 	collector *collector[validatorUpdate],
 	acck auth.AccountKeeperI,
 	gpk auth.GasPriceKeeperI,
 	vmk vm.VMKeeperI,
+	ir *sdk.InvariantRouter,
 	app endBlockerApp,
 ) func(
 	ctx sdk.Context,
@@ -445,10 +555,19 @@ func EndBlocker(
 		if acck != nil && gpk != nil {
 			auth.EndBlocker(ctx, gpk)
 		}
+		// Run the scheduled calls, which may also change the valset
+		var evs []abci.Event
+		if vmk != nil {
+			evs = vmk.RunScheduledCalls(ctx)
+		}
+		// Check the invariants, on the state left by the block
+		if vmk != nil && ir != nil {
+			evs = append(evs, vmk.CheckInvariants(ctx, ir)...)
+		}
 		// Check if there was a valset change
-		if len(collector.getEvents()) == 0 {
+		if len(collector.getEvents()) == 0 && !hasValidatorUpdate(evs) {
 			// No valset updates
-			return abci.ResponseEndBlock{}
+			return abci.ResponseEndBlock{Events: evs}
 		}
 
 		// Run the VM to get the updates from the chain
@@ -460,7 +579,7 @@ func EndBlocker(
 		if err != nil {
 			app.Logger().Error("unable to call VM during EndBlocker", "err", err)
 
-			return abci.ResponseEndBlock{}
+			return abci.ResponseEndBlock{Events: evs}
 		}
 
 		// Extract the updates from the VM response
@@ -468,11 +587,12 @@ func EndBlocker(
 		if err != nil {
 			app.Logger().Error("unable to extract updates from response", "err", err)
 
-			return abci.ResponseEndBlock{}
+			return abci.ResponseEndBlock{Events: evs}
 		}
 
 		return abci.ResponseEndBlock{
 			ValidatorUpdates: updates,
+			Events:           evs,
 		}
 	}
 }
@@ -17,6 +17,7 @@ import (
 	bftCfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/events"
@@ -466,6 +467,134 @@ func TestInitChainer_MetadataTxs(t *testing.T) {
 	}
 }
 
+func TestInitChainer_Vesting(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesisTime = time.Unix(1_000_000, 0)
+
+		addr   = crypto.AddressFromPreimage([]byte("vesting"))
+		amount = std.NewCoins(std.NewCoin("ugnot", 1_000_000))
+	)
+
+	initChain := func(t *testing.T, vesting std.VestingSchedule) (*sdk.BaseApp, abci.ResponseInitChain) {
+		t.Helper()
+
+		app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+		require.NoError(t, err)
+		bapp := app.(*sdk.BaseApp)
+
+		appState := DefaultGenState()
+		appState.Balances = []Balance{{Address: addr, Amount: amount}}
+		appState.Vesting = []Vesting{{Address: addr, Schedule: vesting}}
+
+		resp := bapp.InitChain(abci.RequestInitChain{
+			Time:    genesisTime,
+			ChainID: "dev",
+			ConsensusParams: &abci.ConsensusParams{
+				Block: defaultBlockParams(),
+			},
+			AppState: appState,
+		})
+		return bapp, resp
+	}
+
+	t.Run("valid vesting", func(t *testing.T) {
+		t.Parallel()
+
+		vesting := std.NewContinuousVesting(std.NewCoins(std.NewCoin("ugnot", 800_000)), genesisTime.Unix(), genesisTime.Unix()+1000)
+		bapp, resp := initChain(t, vesting)
+		require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+		bapp.Commit()
+
+		qres := bapp.Query(abci.RequestQuery{Path: "auth/vesting/" + addr.String()})
+		require.True(t, qres.IsOK(), "Query response: %v", qres)
+
+		var status auth.VestingStatus
+		require.NoError(t, amino.UnmarshalJSON(qres.Data, &status))
+		assert.Equal(t, vesting, status.Schedule)
+		assert.Equal(t, vesting.OriginalVesting, status.Locked)
+		assert.Equal(t, std.NewCoins(std.NewCoin("ugnot", 200_000)), status.Spendable)
+	})
+
+	t.Run("vesting more than the balance", func(t *testing.T) {
+		t.Parallel()
+
+		vesting := std.NewContinuousVesting(std.NewCoins(std.NewCoin("ugnot", 2_000_000)), genesisTime.Unix(), genesisTime.Unix()+1000)
+		_, resp := initChain(t, vesting)
+		require.False(t, resp.IsOK())
+		assert.ErrorContains(t, resp.Error, "lower than the vesting amount")
+	})
+}
+
+func TestBeginBlocker(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no violations", func(t *testing.T) {
+		t.Parallel()
+
+		mockVMKeeper := &mockVMKeeper{
+			chainCallFn: func(sdk.Context, string, string, []string) ([]abci.Event, error) {
+				t.Fatal("unexpected call")
+				return nil, nil
+			},
+		}
+
+		// Create and run the BeginBlocker
+		bb := BeginBlocker(nil, mockVMKeeper, &mockEndBlockerApp{})
+		res := bb(sdk.Context{}, abci.RequestBeginBlock{})
+
+		assert.Equal(t, abci.ResponseBeginBlock{}, res)
+	})
+
+	t.Run("double sign reported", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			pubKey   = ed25519.GenPrivKey().PubKey()
+			evidence = &bft.DuplicateVoteEvidence{
+				PubKey: pubKey,
+				VoteA:  &bft.Vote{Height: 5},
+				VoteB:  &bft.Vote{Height: 5},
+			}
+
+			calls [][]string
+
+			removedEvent = gnostd.GnoEvent{
+				Type:    validatorRemovedEvent,
+				PkgPath: valRealm,
+			}
+
+			mockVMKeeper = &mockVMKeeper{
+				chainCallFn: func(ctx sdk.Context, pkgPath, fn string, args []string) ([]abci.Event, error) {
+					assert.Equal(t, valRealm, pkgPath)
+					assert.Equal(t, valDoubleSignFn, fn)
+					calls = append(calls, args)
+
+					return []abci.Event{removedEvent}, nil
+				},
+			}
+		)
+
+		// Create and run the BeginBlocker
+		bb := BeginBlocker(nil, mockVMKeeper, &mockEndBlockerApp{})
+		ctx := sdk.Context{}.WithBlockHeader(&bft.Header{Height: 10})
+		res := bb(ctx, abci.RequestBeginBlock{
+			Violations: []abci.Violation{
+				{Evidence: evidence, Height: 5},
+				{Evidence: bft.NewMockGoodEvidence(5, 0, pubKey.Address()), Height: 5}, // not a double sign
+			},
+		})
+
+		// Make sure the double sign is reported right away,
+		// and that its validator update is collected
+		require.Len(t, calls, 1)
+		assert.Equal(t, []string{pubKey.Address().String(), "5"}, calls[0])
+		assert.Equal(t, []abci.Event{removedEvent}, res.Events)
+		assert.Len(t, validatorEventFilter(bft.EventNewBlock{ResultBeginBlock: res}), 1)
+	})
+}
+
 func TestEndBlocker(t *testing.T) {
 	t.Parallel()
 
@@ -518,7 +647,7 @@ func TestEndBlocker(t *testing.T) {
 		c := newCollector[validatorUpdate](&mockEventSwitch{}, noFilter)
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, nil, nil, nil, &mockEndBlockerApp{})
+		eb := EndBlocker(c, nil, nil, nil, nil, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -558,7 +687,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(gnostd.GnoEvent{})
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, nil, nil, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, nil, nil, mockVMKeeper, nil, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -601,7 +730,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(gnostd.GnoEvent{})
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, nil, nil, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, nil, nil, mockVMKeeper, nil, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -669,7 +798,7 @@ func TestEndBlocker(t *testing.T) {
 		mockEventSwitch.FireEvent(txEvent)
 
 		// Create the EndBlocker
-		eb := EndBlocker(c, nil, nil, mockVMKeeper, &mockEndBlockerApp{})
+		eb := EndBlocker(c, nil, nil, mockVMKeeper, nil, &mockEndBlockerApp{})
 
 		// Run the EndBlocker
 		res := eb(sdk.Context{}, abci.RequestEndBlock{})
@@ -683,6 +812,86 @@ func TestEndBlocker(t *testing.T) {
 			assert.Equal(t, changes[index].Power, update.Power)
 		}
 	})
+
+	t.Run("scheduled call valset updates", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			changes = generateValidatorUpdates(t, 1)
+
+			scheduledEvents = []abci.Event{
+				gnostd.GnoEvent{
+					Type:    validatorAddedEvent,
+					PkgPath: valRealm,
+				},
+				gnostd.GnoEvent{
+					Type:    vm.EventScheduledCallExecuted,
+					PkgPath: valRealm,
+				},
+			}
+
+			mockVMKeeper = &mockVMKeeper{
+				queryFn: func(_ sdk.Context, pkgPath, expr string) (string, error) {
+					require.Equal(t, valRealm, pkgPath)
+					require.NotEmpty(t, expr)
+
+					return constructVMResponse(changes), nil
+				},
+				runScheduledCallsFn: func(_ sdk.Context) []abci.Event {
+					return scheduledEvents
+				},
+			}
+		)
+
+		// Create the collector, without tx events
+		c := newCollector[validatorUpdate](newCommonEvSwitch(), validatorEventFilter)
+
+		// Create the EndBlocker
+		eb := EndBlocker(c, nil, nil, mockVMKeeper, nil, &mockEndBlockerApp{})
+
+		// Run the EndBlocker
+		res := eb(sdk.Context{}, abci.RequestEndBlock{})
+
+		// Verify the events of the scheduled calls were returned,
+		// and the valset changes were fetched
+		assert.Equal(t, scheduledEvents, res.Events)
+		require.Len(t, res.ValidatorUpdates, len(changes))
+		assert.Equal(t, changes[0].Address, res.ValidatorUpdates[0].Address)
+	})
+
+	t.Run("broken invariants", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ir = sdk.NewInvariantRouter()
+
+			invariantEvents = []abci.Event{
+				gnostd.GnoEvent{
+					Type: vm.EventInvariantBroken,
+				},
+			}
+
+			mockVMKeeper = &mockVMKeeper{
+				checkInvariantsFn: func(_ sdk.Context, router *sdk.InvariantRouter) []abci.Event {
+					require.Same(t, ir, router)
+
+					return invariantEvents
+				},
+			}
+		)
+
+		// Create the collector, without events
+		c := newCollector[validatorUpdate](&mockEventSwitch{}, validatorEventFilter)
+
+		// Create the EndBlocker
+		eb := EndBlocker(c, nil, nil, mockVMKeeper, ir, &mockEndBlockerApp{})
+
+		// Run the EndBlocker
+		res := eb(sdk.Context{}, abci.RequestEndBlock{})
+
+		// Verify the events of the broken invariants were returned
+		assert.Equal(t, invariantEvents, res.Events)
+	})
 }
 
 func TestGasPriceUpdate(t *testing.T) {
@@ -848,7 +1057,7 @@ func newGasPriceTestApp(t *testing.T) abci.Application {
 	prmk := params.NewParamsKeeper(mainKey)
 	acck := auth.NewAccountKeeper(mainKey, prmk.ForModule(auth.ModuleName), ProtoGnoAccount)
 	gpk := auth.NewGasPriceKeeper(mainKey)
-	bankk := bank.NewBankKeeper(acck, prmk.ForModule(bank.ModuleName))
+	bankk := bank.NewBankKeeper(mainKey, acck, prmk.ForModule(bank.ModuleName))
 	vmk := vm.NewVMKeeper(baseKey, mainKey, acck, bankk, prmk)
 	prmk.Register(auth.ModuleName, acck)
 	prmk.Register(bank.ModuleName, bankk)
@@ -903,6 +1112,7 @@ func newGasPriceTestApp(t *testing.T) abci.Application {
 			acck,
 			gpk,
 			nil,
+			nil,
 			baseApp,
 		),
 	)
@@ -1082,6 +1292,7 @@ func TestPruneStrategyNothing(t *testing.T) {
 		filepath.Join(appDir, bftCfg.DefaultDBDir),
 	)
 	require.NoError(t, err)
+	defer db.Close()
 
 	var (
 		mainKey = store.NewStoreKey("main")
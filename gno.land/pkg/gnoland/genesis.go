@@ -74,6 +74,16 @@ func splitTypedName(typedName string) (name string, type_ string) {
 	}
 }
 
+// toStrings converts a toml array of strings to a []string.
+func toStrings(value any) []string {
+	vz := value.([]any)
+	sz := make([]string, len(vz))
+	for i, v := range vz {
+		sz[i] = v.(string)
+	}
+	return sz
+}
+
 // LoadGenesisParamsFile loads genesis params from the provided file path.
 func LoadGenesisParamsFile(path string, ggs *GnoGenesisState) error {
 	content, err := osm.ReadFile(path)
@@ -102,6 +112,18 @@ func LoadGenesisParamsFile(path string, ggs *GnoGenesisState) error {
 				ggs.VM.Params.ChainDomain = value.(string)
 			case "sysnames_pkgpath":
 				ggs.VM.Params.SysNamesPkgPath = value.(string)
+			case "storage_price":
+				ggs.VM.Params.StoragePrice = value.(string)
+			case "scheduled_calls_gas":
+				ggs.VM.Params.ScheduledCallsGas = value.(int64)
+			case "invariant_check_period":
+				ggs.VM.Params.InvariantCheckPeriod = value.(int64)
+			case "invariant_halt":
+				ggs.VM.Params.InvariantHalt = value.(bool)
+			case "invariant_realms":
+				ggs.VM.Params.InvariantRealms = toStrings(value)
+			case "paused_realms":
+				ggs.VM.Params.PausedRealms = toStrings(value)
 			default:
 				return errors.New("unexpected vm parameter " + name)
 			}
@@ -123,12 +145,7 @@ func LoadGenesisParamsFile(path string, ggs *GnoGenesisState) error {
 			for name, value := range values {
 				name, type_ := splitTypedName(name)
 				if type_ == "strings" {
-					vz := value.([]any)
-					sz := make([]string, len(vz))
-					for i, v := range vz {
-						sz[i] = v.(string)
-					}
-					value = sz
+					value = toStrings(value)
 				}
 				param := params.NewParam(realm+":"+name, value)
 				ggs.VM.RealmParams = append(ggs.VM.RealmParams, param)
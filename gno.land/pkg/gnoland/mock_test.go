@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/log"
@@ -52,6 +53,7 @@ func (m *mockEventSwitch) RemoveListener(listenerID string) {
 
 type mockVMKeeper struct {
 	addPackageFn                func(sdk.Context, vm.MsgAddPackage) error
+	upgradePackageFn            func(sdk.Context, vm.MsgUpgradePackage) error
 	callFn                      func(sdk.Context, vm.MsgCall) (string, error)
 	queryFn                     func(sdk.Context, string, string) (string, error)
 	runFn                       func(sdk.Context, vm.MsgRun) (string, error)
@@ -59,6 +61,9 @@ type mockVMKeeper struct {
 	loadStdlibCachedFn          func(sdk.Context, string)
 	makeGnoTransactionStoreFn   func(ctx sdk.Context) sdk.Context
 	commitGnoTransactionStoreFn func(ctx sdk.Context)
+	chainCallFn                 func(ctx sdk.Context, pkgPath, fn string, args []string) ([]abci.Event, error)
+	runScheduledCallsFn         func(ctx sdk.Context) []abci.Event
+	checkInvariantsFn           func(ctx sdk.Context, ir *sdk.InvariantRouter) []abci.Event
 }
 
 func (m *mockVMKeeper) AddPackage(ctx sdk.Context, msg vm.MsgAddPackage) error {
@@ -69,6 +74,14 @@ func (m *mockVMKeeper) AddPackage(ctx sdk.Context, msg vm.MsgAddPackage) error {
 	return nil
 }
 
+func (m *mockVMKeeper) UpgradePackage(ctx sdk.Context, msg vm.MsgUpgradePackage) error {
+	if m.upgradePackageFn != nil {
+		return m.upgradePackageFn(ctx, msg)
+	}
+
+	return nil
+}
+
 func (m *mockVMKeeper) Call(ctx sdk.Context, msg vm.MsgCall) (res string, err error) {
 	if m.callFn != nil {
 		return m.callFn(ctx, msg)
@@ -120,6 +133,27 @@ func (m *mockVMKeeper) CommitGnoTransactionStore(ctx sdk.Context) {
 
 func (m *mockVMKeeper) InitGenesis(ctx sdk.Context, gs vm.GenesisState) {}
 
+func (m *mockVMKeeper) ChainCall(ctx sdk.Context, pkgPath, fn string, args []string) ([]abci.Event, error) {
+	if m.chainCallFn != nil {
+		return m.chainCallFn(ctx, pkgPath, fn, args)
+	}
+	return nil, nil
+}
+
+func (m *mockVMKeeper) RunScheduledCalls(ctx sdk.Context) []abci.Event {
+	if m.runScheduledCallsFn != nil {
+		return m.runScheduledCallsFn(ctx)
+	}
+	return nil
+}
+
+func (m *mockVMKeeper) CheckInvariants(ctx sdk.Context, ir *sdk.InvariantRouter) []abci.Event {
+	if m.checkInvariantsFn != nil {
+		return m.checkInvariantsFn(ctx, ir)
+	}
+	return nil
+}
+
 type mockBankKeeper struct{}
 
 func (m *mockBankKeeper) InputOutputCoins(ctx sdk.Context, inputs []bank.Input, outputs []bank.Output) error {
@@ -144,6 +178,8 @@ func (m *mockBankKeeper) AddCoins(ctx sdk.Context, addr crypto.Address, amt std.
 
 func (m *mockBankKeeper) InitGenesis(ctx sdk.Context, data bank.GenesisState)     {}
 func (m *mockBankKeeper) GetParams(ctx sdk.Context) bank.Params                   { return bank.Params{} }
+func (m *mockBankKeeper) GetSupply(ctx sdk.Context) std.Coins                     { return nil }
+func (m *mockBankKeeper) InitSupply(ctx sdk.Context)                              {}
 func (m *mockBankKeeper) GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins { return nil }
 func (m *mockBankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) error {
 	return nil
@@ -11,6 +11,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 ).WithDependencies().WithTypes(
 	&GnoAccount{}, "Account",
 	GnoGenesisState{}, "GenesisState",
+	Vesting{}, "Vesting",
 	TxWithMetadata{}, "TxWithMetadata",
 	GnoTxMetadata{}, "GnoTxMetadata",
 ))
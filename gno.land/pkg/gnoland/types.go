@@ -41,11 +41,15 @@ func (bs BitSet) String() string {
 	return fmt.Sprintf("0x%016X", uint64(bs)) // Show all 64 bits
 }
 
-var _ std.AccountUnrestricter = &GnoAccount{}
+var (
+	_ std.AccountUnrestricter = &GnoAccount{}
+	_ std.VestingAccount      = &GnoAccount{}
+)
 
 type GnoAccount struct {
 	std.BaseAccount
-	Attributes BitSet `json:"attributes" yaml:"attributes"`
+	Attributes BitSet              `json:"attributes" yaml:"attributes"`
+	Vesting    std.VestingSchedule `json:"vesting,omitempty" yaml:"vesting,omitempty"`
 }
 
 // validFlags defines the set of all valid flags that can be used with BitSet.
@@ -90,12 +94,29 @@ func (ga *GnoAccount) IsUnrestricted() bool {
 	return ga.hasFlag(flagUnrestricted)
 }
 
+// GetVestingSchedule returns the vesting schedule of the account, or nil if
+// its coins don't vest.
+func (ga *GnoAccount) GetVestingSchedule() std.VestingSchedule {
+	return ga.Vesting
+}
+
+// SetVestingSchedule locks the coins of the account until they vest according
+// to vesting.
+func (ga *GnoAccount) SetVestingSchedule(vesting std.VestingSchedule) error {
+	ga.Vesting = vesting
+	return nil
+}
+
 // String implements fmt.Stringer
 func (ga *GnoAccount) String() string {
-	return fmt.Sprintf("%s\n  Attributes:	 %s",
+	s := fmt.Sprintf("%s\n  Attributes:	 %s",
 		ga.BaseAccount.String(),
 		ga.Attributes.String(),
 	)
+	if ga.Vesting != nil {
+		s += fmt.Sprintf("\n  Vesting:       %s", ga.Vesting.String())
+	}
+	return s
 }
 
 func ProtoGnoAccount() std.Account {
@@ -104,6 +125,7 @@ func ProtoGnoAccount() std.Account {
 
 type GnoGenesisState struct {
 	Balances []Balance         `json:"balances"`
+	Vesting  []Vesting         `json:"vesting,omitempty"`
 	Txs      []TxWithMetadata  `json:"txs"`
 	Auth     auth.GenesisState `json:"auth"`
 	Bank     bank.GenesisState `json:"bank"`
@@ -4,6 +4,7 @@ import (
 	"regexp"
 
 	gnovm "github.com/gnolang/gno/gnovm/stdlibs/std"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/events"
 )
@@ -12,6 +13,8 @@ const (
 	valRealm     = "gno.land/r/sys/validators/v2" // XXX: make it configurable from GovDAO
 	valChangesFn = "GetChanges"
 
+	valDoubleSignFn = "ReportDoubleSign"
+
 	validatorAddedEvent   = "ValidatorAdded"
 	validatorRemovedEvent = "ValidatorRemoved"
 )
@@ -27,14 +30,32 @@ type validatorUpdate struct{}
 // validatorEventFilter filters the given event to determine if it
 // is tied to a validator update
 func validatorEventFilter(event events.Event) []validatorUpdate {
-	// Make sure the event is a new TX event
-	txResult, ok := event.(types.EventTx)
-	if !ok {
+	// Make sure the event is a new TX event, or a new block event
+	// for the reports of the BeginBlocker
+	var evs []abci.Event
+	switch ev := event.(type) {
+	case types.EventTx:
+		evs = ev.Result.Response.Events
+	case types.EventNewBlock:
+		evs = ev.ResultBeginBlock.Events
+	default:
 		return nil
 	}
 
 	// Make sure an add / remove event happened
-	for _, ev := range txResult.Result.Response.Events {
+	if hasValidatorUpdate(evs) {
+		// We don't pass data around with the events, but a single
+		// notification is enough to "trigger" a VM scrape
+		return []validatorUpdate{{}}
+	}
+
+	return nil
+}
+
+// hasValidatorUpdate returns whether the given events contain
+// a validator add / remove event from `r/sys/validators`
+func hasValidatorUpdate(evs []abci.Event) bool {
+	for _, ev := range evs {
 		// Make sure the event is a GnoVM event
 		gnoEv, ok := ev.(gnovm.GnoEvent)
 		if !ok {
@@ -49,13 +70,11 @@ func validatorEventFilter(event events.Event) []validatorUpdate {
 		// Make sure the event is either an add / remove
 		switch gnoEv.Type {
 		case validatorAddedEvent, validatorRemovedEvent:
-			// We don't pass data around with the events, but a single
-			// notification is enough to "trigger" a VM scrape
-			return []validatorUpdate{{}}
+			return true
 		default:
 			continue
 		}
 	}
 
-	return nil
+	return false
 }
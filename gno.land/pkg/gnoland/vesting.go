@@ -0,0 +1,73 @@
+package gnoland
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var (
+	ErrVestingEmptyAddress  = errors.New("vesting address is empty")
+	ErrVestingEmptySchedule = errors.New("vesting schedule is empty")
+)
+
+// Vesting locks the balance of a genesis account until it vests according to
+// Schedule.
+type Vesting struct {
+	Address  bft.Address         `json:"address"`
+	Schedule std.VestingSchedule `json:"schedule"`
+}
+
+func (v *Vesting) Verify() error {
+	if v.Address.IsZero() {
+		return ErrVestingEmptyAddress
+	}
+
+	if v.Schedule == nil {
+		return ErrVestingEmptySchedule
+	}
+
+	return v.Schedule.ValidateBasic()
+}
+
+// NewVestingSchedule returns the schedule vesting amount over duration from
+// start: linearly if periods is zero, and otherwise by equal parts at the end
+// of each of the given number of periods.
+func NewVestingSchedule(amount std.Coins, start time.Time, duration time.Duration, periods int) (std.VestingSchedule, error) {
+	var (
+		startTime = start.Unix()
+		length    = int64(duration / time.Second)
+	)
+
+	if periods == 0 {
+		schedule := std.NewContinuousVesting(amount, startTime, startTime+length)
+		return schedule, schedule.ValidateBasic()
+	}
+
+	if periods < 0 || int64(periods) > length {
+		return nil, fmt.Errorf("invalid number of vesting periods %d", periods)
+	}
+
+	// Each period unlocks an equal part of the amount, the last period
+	// unlocking the remainder.
+	vestingPeriods := make([]std.VestingPeriod, periods)
+	for i := range vestingPeriods {
+		vestingPeriods[i].Length = length / int64(periods)
+		for _, coin := range amount {
+			part := coin.Amount / int64(periods)
+			if i == periods-1 {
+				part = coin.Amount - part*int64(periods-1)
+			}
+			if part > 0 {
+				vestingPeriods[i].Amount = append(vestingPeriods[i].Amount, std.NewCoin(coin.Denom, part))
+			}
+		}
+	}
+	vestingPeriods[periods-1].Length += length % int64(periods)
+
+	schedule := std.NewPeriodicVesting(startTime, vestingPeriods)
+	return schedule, schedule.ValidateBasic()
+}
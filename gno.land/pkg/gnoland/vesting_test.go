@@ -0,0 +1,71 @@
+package gnoland
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestVesting_Verify(t *testing.T) {
+	t.Parallel()
+
+	addr := crypto.AddressFromPreimage([]byte("vesting"))
+	schedule := std.NewContinuousVesting(std.NewCoins(std.NewCoin("ugnot", 10)), 0, 100)
+
+	v := Vesting{Address: addr, Schedule: schedule}
+	assert.NoError(t, v.Verify())
+
+	v = Vesting{Schedule: schedule}
+	assert.ErrorIs(t, v.Verify(), ErrVestingEmptyAddress)
+
+	v = Vesting{Address: addr}
+	assert.ErrorIs(t, v.Verify(), ErrVestingEmptySchedule)
+
+	v = Vesting{Address: addr, Schedule: std.NewContinuousVesting(schedule.OriginalVesting, 100, 0)}
+	assert.Error(t, v.Verify())
+}
+
+func TestNewVestingSchedule(t *testing.T) {
+	t.Parallel()
+
+	var (
+		amount = std.NewCoins(std.NewCoin("foo", 10), std.NewCoin("ugnot", 1000))
+		start  = time.Unix(1000, 0)
+	)
+
+	t.Run("continuous", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := NewVestingSchedule(amount, start, time.Hour, 0)
+		require.NoError(t, err)
+		assert.Equal(t, std.NewContinuousVesting(amount, 1000, 4600), schedule)
+	})
+
+	t.Run("periodic", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := NewVestingSchedule(amount, start, 100*time.Second, 3)
+		require.NoError(t, err)
+		assert.Equal(t, std.NewPeriodicVesting(1000, []std.VestingPeriod{
+			{Length: 33, Amount: std.NewCoins(std.NewCoin("foo", 3), std.NewCoin("ugnot", 333))},
+			{Length: 33, Amount: std.NewCoins(std.NewCoin("foo", 3), std.NewCoin("ugnot", 333))},
+			{Length: 34, Amount: std.NewCoins(std.NewCoin("foo", 4), std.NewCoin("ugnot", 334))},
+		}), schedule)
+		assert.Equal(t, amount, schedule.GetOriginalVesting())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewVestingSchedule(amount, start, 0, 0)
+		assert.Error(t, err)
+
+		_, err = NewVestingSchedule(amount, start, time.Second, 2)
+		assert.Error(t, err)
+	})
+}
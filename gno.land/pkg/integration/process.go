@@ -89,6 +89,9 @@ func RunNode(ctx context.Context, pcfg *ProcessNodeConfig, stdout, stderr io.Wri
 	nodecfg.DB = db
 	nodecfg.TMConfig.DBPath = pcfg.DBDir
 	nodecfg.TMConfig = pcfg.TMConfig
+	// WALDisabled isn't serialized with the config, disable it again so that
+	// the node doesn't write its WAL in the root dir
+	nodecfg.TMConfig.Consensus.WALDisabled = true
 	nodecfg.Genesis = pcfg.Genesis.ToGenesisDoc()
 	nodecfg.Genesis.Validators = []bft.GenesisValidator{
 		{
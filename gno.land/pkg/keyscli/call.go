@@ -7,6 +7,7 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys/client"
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -129,6 +130,7 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
+	tx.Fee.FeePayer = crypto.Bech32Address(cfg.RootCfg.FeePayer)
 
 	if cfg.RootCfg.Broadcast {
 		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
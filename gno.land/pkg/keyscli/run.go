@@ -11,6 +11,7 @@ import (
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys/client"
 	"github.com/gnolang/gno/tm2/pkg/errors"
@@ -125,6 +126,7 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
+	tx.Fee.FeePayer = crypto.Bech32Address(cfg.RootCfg.FeePayer)
 
 	if cfg.RootCfg.Broadcast {
 		err := client.ExecSignAndBroadcast(cfg.RootCfg, args, tx, cmdio)
@@ -81,36 +81,39 @@ func NewSDKParams(pmk ParamsKeeperI, ctx sdk.Context) *SDKParams {
 
 // The key has the format <module>:(<realm>:)?<paramname>.
 func (prm *SDKParams) SetString(key string, value string) {
-	prm.willSetKeeperParams(prm.ctx, key, value)
+	prm.assertRegisteredModule(key)
 	prm.pmk.SetString(prm.ctx, key, value)
 }
 
 func (prm *SDKParams) SetBool(key string, value bool) {
-	prm.willSetKeeperParams(prm.ctx, key, value)
+	prm.assertRegisteredModule(key)
 	prm.pmk.SetBool(prm.ctx, key, value)
 }
 
 func (prm *SDKParams) SetInt64(key string, value int64) {
-	prm.willSetKeeperParams(prm.ctx, key, value)
+	prm.assertRegisteredModule(key)
 	prm.pmk.SetInt64(prm.ctx, key, value)
 }
 
 func (prm *SDKParams) SetUint64(key string, value uint64) {
-	prm.willSetKeeperParams(prm.ctx, key, value)
+	prm.assertRegisteredModule(key)
 	prm.pmk.SetUint64(prm.ctx, key, value)
 }
 
 func (prm *SDKParams) SetBytes(key string, value []byte) {
-	prm.willSetKeeperParams(prm.ctx, key, value)
+	prm.assertRegisteredModule(key)
 	prm.pmk.SetBytes(prm.ctx, key, value)
 }
 
 func (prm *SDKParams) SetStrings(key string, value []string) {
-	prm.willSetKeeperParams(prm.ctx, key, value)
+	prm.assertRegisteredModule(key)
 	prm.pmk.SetStrings(prm.ctx, key, value)
 }
 
-func (prm *SDKParams) willSetKeeperParams(ctx sdk.Context, key string, value any) {
+// assertRegisteredModule panics if the module of the key is not registered.
+// The keeper of the module validates the value in WillSetParam, called by
+// the params keeper.
+func (prm *SDKParams) assertRegisteredModule(key string) {
 	parts := strings.Split(key, ":")
 	if len(parts) == 0 {
 		panic(fmt.Sprintf("SDKParams encountered invalid param key format: %s", key))
@@ -119,9 +122,30 @@ func (prm *SDKParams) willSetKeeperParams(ctx sdk.Context, key string, value any
 	if !prm.pmk.IsRegistered(mname) {
 		panic(fmt.Sprintf("module name <%s> not registered", mname))
 	}
-	kpr := prm.pmk.GetRegisteredKeeper(mname)
-	if kpr != nil {
-		subkey := key[len(mname)+1:]
-		kpr.WillSetParam(prm.ctx, subkey, value)
+}
+
+// ----------------------------------------
+// SDKScheduler
+
+// This implements SchedulerInterface,
+// which is available as ExecContext.Scheduler.
+
+type SDKScheduler struct {
+	vmk *VMKeeper
+	ctx sdk.Context
+}
+
+func NewSDKScheduler(vmk *VMKeeper, ctx sdk.Context) *SDKScheduler {
+	return &SDKScheduler{
+		vmk: vmk,
+		ctx: ctx,
 	}
 }
+
+func (sch *SDKScheduler) ScheduleCall(pkgPath string, height, timestamp int64, fn string, args []string) (uint64, error) {
+	return sch.vmk.scheduleCall(sch.ctx, pkgPath, height, timestamp, fn, args)
+}
+
+func (sch *SDKScheduler) CancelCall(pkgPath string, id uint64) bool {
+	return sch.vmk.cancelScheduledCall(sch.ctx, pkgPath, id)
+}
@@ -2,10 +2,14 @@ package vm
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/apd/v3"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
@@ -19,10 +23,16 @@ func assertNoPlusPrefix(s string) {
 
 // These convert string representations of public-facing arguments to GNO types.
 // The limited set of input types available should map 1:1 to types supported
-// in FunctionSignature{}.
+// in FunctionSignature{}: booleans, numbers and strings are given as is, byte
+// arrays and slices in base64, and the other arrays, slices, structs, maps and
+// pointers in JSON (see convertJSONToGno).
 // String representation of arg must be deterministic.
 // NOTE: very important that there is no malleability.
 func convertArgToGno(arg string, argT gno.Type) (tv gno.TypedValue) {
+	if isJSONArgType(argT) {
+		return convertJSONArgToGno(arg, argT)
+	}
+
 	tv.T = argT
 	switch bt := gno.BaseOf(argT).(type) {
 	case gno.PrimitiveType:
@@ -193,6 +203,364 @@ func convertArgToGno(arg string, argT gno.Type) (tv gno.TypedValue) {
 	}
 }
 
+// isJSONArgType returns true if the arguments of type t are given in JSON.
+func isJSONArgType(t gno.Type) bool {
+	switch bt := gno.BaseOf(t).(type) {
+	case *gno.ArrayType:
+		return bt.Elt != gno.Uint8Type
+	case *gno.SliceType:
+		return bt.Elt != gno.Uint8Type
+	case *gno.StructType, *gno.MapType, *gno.PointerType:
+		return true
+	default:
+		return false
+	}
+}
+
+func convertJSONArgToGno(arg string, argT gno.Type) gno.TypedValue {
+	dec := json.NewDecoder(strings.NewReader(arg))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		panic(fmt.Sprintf(
+			"error parsing JSON %q: %v",
+			arg, err))
+	}
+	if dec.More() {
+		panic(fmt.Sprintf(
+			"error parsing JSON %q: trailing data",
+			arg))
+	}
+	return convertJSONToGno(v, argT)
+}
+
+// convertJSONToGno converts a value decoded from JSON to a value of type t.
+// Within JSON, numbers are given as JSON numbers, byte arrays and slices as
+// base64 strings, and nil slices, maps and pointers as null. Structs are given
+// as objects keyed by the names of their exported fields, and maps as objects
+// keyed by the string representations of their keys, inserted in the order of
+// the sorted keys.
+func convertJSONToGno(v any, t gno.Type) (tv gno.TypedValue) {
+	tv.T = t
+	switch bt := gno.BaseOf(t).(type) {
+	case gno.PrimitiveType:
+		switch v := v.(type) {
+		case bool:
+			if bt == gno.BoolType {
+				tv.SetBool(v)
+				return
+			}
+		case string:
+			if bt == gno.StringType {
+				tv.SetString(gno.StringValue(v))
+				return
+			}
+		case json.Number:
+			if bt.Kind() != gno.BoolKind && bt.Kind() != gno.StringKind {
+				return convertArgToGno(string(v), t)
+			}
+		}
+	case *gno.ArrayType:
+		if bt.Elt == gno.Uint8Type {
+			if s, ok := v.(string); ok {
+				tv = convertArgToGno(s, t)
+				if len(tv.V.(*gno.ArrayValue).Data) != bt.Len {
+					panic(fmt.Sprintf(
+						"unexpected length of %s: %d",
+						t.String(), len(tv.V.(*gno.ArrayValue).Data)))
+				}
+				return
+			}
+			break
+		}
+		if l, ok := v.([]any); ok {
+			if len(l) != bt.Len {
+				panic(fmt.Sprintf(
+					"unexpected length of %s: %d",
+					t.String(), len(l)))
+			}
+			tv.V = &gno.ArrayValue{List: convertJSONListToGno(l, bt.Elt)}
+			return
+		}
+	case *gno.SliceType:
+		if v == nil {
+			return // nil slice
+		}
+		if bt.Elt == gno.Uint8Type {
+			if s, ok := v.(string); ok {
+				return convertArgToGno(s, t)
+			}
+			break
+		}
+		if l, ok := v.([]any); ok {
+			tv.V = &gno.SliceValue{
+				Base:   &gno.ArrayValue{List: convertJSONListToGno(l, bt.Elt)},
+				Offset: 0,
+				Length: len(l),
+				Maxcap: len(l),
+			}
+			return
+		}
+	case *gno.StructType:
+		if o, ok := v.(map[string]any); ok {
+			sv := &gno.StructValue{Fields: make([]gno.TypedValue, len(bt.Fields))}
+			for i, f := range bt.Fields {
+				sv.Fields[i] = gno.DefaultTypedValue(nil, f.Type)
+			}
+			for _, key := range sortedKeys(o) {
+				i := slices.IndexFunc(bt.Fields, func(f gno.FieldType) bool {
+					return string(f.Name) == key
+				})
+				if r, _ := utf8.DecodeRuneInString(key); i < 0 || !unicode.IsUpper(r) {
+					panic(fmt.Sprintf(
+						"unknown field %q in %s",
+						key, t.String()))
+				}
+				sv.Fields[i] = convertJSONToGno(o[key], bt.Fields[i].Type)
+			}
+			tv.V = sv
+			return
+		}
+	case *gno.MapType:
+		if v == nil {
+			return // nil map
+		}
+		if o, ok := v.(map[string]any); ok {
+			if _, ok := gno.BaseOf(bt.Key).(gno.PrimitiveType); !ok {
+				panic(fmt.Sprintf(
+					"unexpected key type of %s",
+					t.String()))
+			}
+			mv := &gno.MapValue{}
+			mv.MakeMap(len(o))
+			for _, key := range sortedKeys(o) {
+				ktv := convertArgToGno(key, bt.Key)
+				ptr := mv.GetPointerForKey(nil, nil, &ktv)
+				*ptr.TV = convertJSONToGno(o[key], bt.Value)
+			}
+			tv.V = mv
+			return
+		}
+	case *gno.PointerType:
+		if v == nil {
+			return // nil pointer
+		}
+		hiv := &gno.HeapItemValue{Value: convertJSONToGno(v, bt.Elt)}
+		tv.V = gno.PointerValue{
+			TV:    &hiv.Value,
+			Base:  hiv,
+			Index: 0,
+		}
+		return
+	default:
+		panic(fmt.Sprintf("unexpected type in contract arg: %v", t))
+	}
+	panic(fmt.Sprintf(
+		"unexpected JSON value for %s: %v",
+		t.String(), v))
+}
+
+func convertJSONListToGno(l []any, elt gno.Type) []gno.TypedValue {
+	list := make([]gno.TypedValue, len(l))
+	for i, v := range l {
+		list[i] = convertJSONToGno(v, elt)
+	}
+	return list
+}
+
+func sortedKeys(o map[string]any) []string {
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// stringifyJSONResults returns the results as a JSON array, with the encoding
+// of the arguments given in JSON (see convertGnoToJSON).
+func stringifyJSONResults(store gno.Store, tvs []gno.TypedValue) string {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(convertGnoListToJSON(store, tvs, nil)); err != nil {
+		panic(err)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// convertGnoToJSON converts tv to a value to be encoded in JSON, as the inverse
+// of convertJSONToGno. Interfaces are given as their concrete values, and the
+// values of other types, the non-finite floats and the values already visited
+// (cycles) as their string representations.
+func convertGnoToJSON(store gno.Store, tv *gno.TypedValue, seen []gno.Value) any {
+	if tv.T == nil {
+		return nil // nil interface
+	}
+	gno.FillValueTV(store, tv)
+	switch bt := gno.BaseOf(tv.T).(type) {
+	case gno.PrimitiveType:
+		switch bt {
+		case gno.BoolType:
+			return tv.GetBool()
+		case gno.StringType:
+			return tv.GetString()
+		case gno.Float32Type:
+			return convertFloatToJSON(float64(math.Float32frombits(tv.GetFloat32())), 32)
+		case gno.Float64Type:
+			return convertFloatToJSON(math.Float64frombits(tv.GetFloat64()), 64)
+		default:
+			return json.Number(tv.Sprint(nil))
+		}
+	case *gno.ArrayType:
+		av := tv.V.(*gno.ArrayValue)
+		if bt.Elt == gno.Uint8Type {
+			return base64.StdEncoding.EncodeToString(av.GetReadonlyBytes())
+		}
+		if slices.Contains(seen, gno.Value(av)) {
+			return tv.String() // cycle
+		}
+		return convertGnoListToJSON(store, av.List, append(seen, av))
+	case *gno.SliceType:
+		if tv.V == nil {
+			return nil // nil slice
+		}
+		sv := tv.V.(*gno.SliceValue)
+		av := sv.GetBase(store)
+		if bt.Elt == gno.Uint8Type {
+			return base64.StdEncoding.EncodeToString(av.GetReadonlyBytes()[sv.Offset : sv.Offset+sv.Length])
+		}
+		if slices.Contains(seen, gno.Value(av)) {
+			return tv.String() // cycle
+		}
+		return convertGnoListToJSON(store, av.List[sv.Offset:sv.Offset+sv.Length], append(seen, av))
+	case *gno.StructType:
+		sv := tv.V.(*gno.StructValue)
+		if slices.Contains(seen, gno.Value(sv)) {
+			return tv.String() // cycle
+		}
+		seen = append(seen, sv)
+		o := map[string]any{}
+		for i, f := range bt.Fields {
+			if r, _ := utf8.DecodeRuneInString(string(f.Name)); unicode.IsUpper(r) {
+				o[string(f.Name)] = convertGnoToJSON(store, &sv.Fields[i], seen)
+			}
+		}
+		return o
+	case *gno.MapType:
+		if tv.V == nil {
+			return nil // nil map
+		}
+		mv := tv.V.(*gno.MapValue)
+		if slices.Contains(seen, gno.Value(mv)) {
+			return tv.String() // cycle
+		}
+		seen = append(seen, mv)
+		o := map[string]any{}
+		for cur := mv.List.Head; cur != nil; cur = cur.Next {
+			o[cur.Key.Sprint(nil)] = convertGnoToJSON(store, &cur.Value, seen)
+		}
+		return o
+	case *gno.PointerType:
+		if tv.V == nil {
+			return nil // nil pointer
+		}
+		return convertGnoToJSON(store, tv.V.(gno.PointerValue).TV, seen)
+	default:
+		return tv.String()
+	}
+}
+
+func convertGnoListToJSON(store gno.Store, list []gno.TypedValue, seen []gno.Value) []any {
+	l := make([]any, len(list))
+	for i := range list {
+		l[i] = convertGnoToJSON(store, &list[i], seen)
+	}
+	return l
+}
+
+func convertFloatToJSON(f float64, precision int) any {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'g', -1, precision)
+	}
+	return json.Number(strconv.FormatFloat(f, 'g', -1, precision))
+}
+
+// jsonSchemaOf returns the JSON schema of the arguments of type t given in
+// JSON, as accepted by convertJSONToGno.
+func jsonSchemaOf(t gno.Type) string {
+	bz, err := json.Marshal(jsonSchemaMap(t, nil))
+	if err != nil {
+		panic(err)
+	}
+	return string(bz)
+}
+
+func jsonSchemaMap(t gno.Type, seen []gno.Type) map[string]any {
+	if dt, ok := t.(*gno.DeclaredType); ok {
+		if slices.Contains(seen, t) {
+			// recursive type.
+			return map[string]any{"description": dt.String()}
+		}
+		seen = append(seen, t)
+	}
+	switch bt := gno.BaseOf(t).(type) {
+	case gno.PrimitiveType:
+		switch {
+		case bt == gno.BoolType:
+			return map[string]any{"type": "boolean"}
+		case bt == gno.StringType:
+			return map[string]any{"type": "string"}
+		case bt == gno.Float32Type || bt == gno.Float64Type:
+			return map[string]any{"type": "number"}
+		default:
+			return map[string]any{"type": "integer"}
+		}
+	case *gno.ArrayType:
+		if bt.Elt == gno.Uint8Type {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+		return map[string]any{
+			"type":     "array",
+			"items":    jsonSchemaMap(bt.Elt, seen),
+			"minItems": bt.Len,
+			"maxItems": bt.Len,
+		}
+	case *gno.SliceType:
+		if bt.Elt == gno.Uint8Type {
+			return map[string]any{"type": []string{"string", "null"}, "contentEncoding": "base64"}
+		}
+		return map[string]any{
+			"type":  []string{"array", "null"},
+			"items": jsonSchemaMap(bt.Elt, seen),
+		}
+	case *gno.StructType:
+		props := map[string]any{}
+		for _, f := range bt.Fields {
+			if r, _ := utf8.DecodeRuneInString(string(f.Name)); unicode.IsUpper(r) {
+				props[string(f.Name)] = jsonSchemaMap(f.Type, seen)
+			}
+		}
+		return map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"additionalProperties": false,
+		}
+	case *gno.MapType:
+		return map[string]any{
+			"type":                 []string{"object", "null"},
+			"additionalProperties": jsonSchemaMap(bt.Value, seen),
+		}
+	case *gno.PointerType:
+		return map[string]any{"anyOf": []any{
+			jsonSchemaMap(bt.Elt, seen),
+			map[string]any{"type": "null"},
+		}}
+	default:
+		return map[string]any{"description": t.String()}
+	}
+}
+
 func convertFloat(value string, precision int) float64 {
 	assertNoPlusPrefix(value)
 	dec, _, err := apd.NewFromString(value)
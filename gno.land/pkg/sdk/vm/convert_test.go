@@ -2,6 +2,7 @@ package vm
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
@@ -37,3 +38,83 @@ func TestConvertEmptyNumbers(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertJSONArg(t *testing.T) {
+	pointT := &gnolang.DeclaredType{
+		PkgPath: "gno.land/r/test",
+		Name:    "Point",
+		Base: &gnolang.StructType{
+			PkgPath: "gno.land/r/test",
+			Fields: []gnolang.FieldType{
+				{Name: "X", Type: gnolang.IntType},
+				{Name: "Tags", Type: &gnolang.SliceType{Elt: gnolang.StringType}},
+				{Name: "private", Type: gnolang.IntType},
+			},
+		},
+	}
+	mapT := &gnolang.MapType{Key: gnolang.IntType, Value: &gnolang.PointerType{Elt: pointT}}
+
+	tv := convertArgToGno(`{"2":{"X":-2},"1":{"X":1,"Tags":["a","b"]},"3":null}`, mapT)
+	assert.Equal(t, `map{(1 int):(&(struct{(1 int),(slice[("a" string),("b" string)] []string),(0 int)} gno.land/r/test.Point) *gno.land/r/test.Point),`+
+		`(2 int):(&(struct{(-2 int),(nil []string),(0 int)} gno.land/r/test.Point) *gno.land/r/test.Point),`+
+		`(3 int):(nil *gno.land/r/test.Point)}`, tv.V.String())
+
+	// byte arrays are given in base64.
+	tv = convertArgToGno(`["AQI=","AwQ="]`, &gnolang.SliceType{Elt: &gnolang.ArrayType{Len: 2, Elt: gnolang.Uint8Type}})
+	assert.Equal(t, 2, tv.V.(*gnolang.SliceValue).Length)
+
+	for _, tc := range []struct {
+		arg  string
+		argT gnolang.Type
+		err  string
+	}{
+		{`{"private":1}`, pointT, `unknown field "private" in gno.land/r/test.Point`},
+		{`{"X":1.5}`, pointT, `error parsing int "1.5": strconv.ParseInt: parsing "1.5": invalid syntax`},
+		{`{"X":"1"}`, pointT, `unexpected JSON value for int: 1`},
+		{`{"X":1} {}`, pointT, `error parsing JSON "{\"X\":1} {}": trailing data`},
+		{`[1]`, &gnolang.ArrayType{Len: 2, Elt: gnolang.IntType}, `unexpected length of [2]int: 1`},
+	} {
+		assert.PanicsWithValue(t, tc.err, func() { convertArgToGno(tc.arg, tc.argT) }, tc.arg)
+	}
+
+	assert.Equal(t,
+		`{"additionalProperties":false,"properties":{"Tags":{"items":{"type":"string"},"type":["array","null"]},"X":{"type":"integer"}},"type":"object"}`,
+		jsonSchemaOf(pointT))
+}
+
+func TestConvertJSONResults(t *testing.T) {
+	pointT := &gnolang.DeclaredType{
+		PkgPath: "gno.land/r/test",
+		Name:    "Point",
+		Base: &gnolang.StructType{
+			PkgPath: "gno.land/r/test",
+			Fields: []gnolang.FieldType{
+				{Name: "X", Type: gnolang.IntType},
+				{Name: "Tags", Type: &gnolang.SliceType{Elt: gnolang.StringType}},
+				{Name: "private", Type: gnolang.IntType},
+			},
+		},
+	}
+	mapT := &gnolang.MapType{Key: gnolang.IntType, Value: &gnolang.PointerType{Elt: pointT}}
+
+	// The results are given like the arguments in JSON.
+	tv := convertArgToGno(`{"2":{"X":-2},"1":{"X":1,"Tags":["a","b"]},"3":null}`, mapT)
+	f := gnolang.TypedValue{T: gnolang.Float64Type}
+	f.SetFloat64(math.Float64bits(math.Inf(1)))
+	assert.Equal(t,
+		`[{"1":{"Tags":["a","b"],"X":1},"2":{"Tags":null,"X":-2},"3":null},"+Inf",null]`,
+		stringifyJSONResults(nil, []gnolang.TypedValue{tv, f, {}}))
+
+	// Cycles are given as strings.
+	nodeT := &gnolang.DeclaredType{PkgPath: "gno.land/r/test", Name: "Node"}
+	nodeT.Base = &gnolang.StructType{
+		PkgPath: "gno.land/r/test",
+		Fields:  []gnolang.FieldType{{Name: "Next", Type: &gnolang.PointerType{Elt: nodeT}}},
+	}
+	sv := &gnolang.StructValue{Fields: []gnolang.TypedValue{{T: &gnolang.PointerType{Elt: nodeT}}}}
+	hiv := &gnolang.HeapItemValue{Value: gnolang.TypedValue{T: nodeT, V: sv}}
+	sv.Fields[0].V = gnolang.PointerValue{TV: &hiv.Value, Base: hiv}
+	assert.Equal(t,
+		`[{"Next":`+fmt.Sprintf("%q", hiv.Value.String())+`}]`,
+		stringifyJSONResults(nil, []gnolang.TypedValue{hiv.Value}))
+}
@@ -21,6 +21,7 @@ type (
 	InvalidStmtError      struct{ abciError }
 	InvalidExprError      struct{ abciError }
 	UnauthorizedUserError struct{ abciError }
+	RealmPausedError      struct{ abciError }
 	TypeCheckError        struct {
 		abciError
 		Errors []string `json:"errors"`
@@ -33,6 +34,7 @@ func (e PkgExistError) Error() string         { return "package already exists"
 func (e InvalidStmtError) Error() string      { return "invalid statement" }
 func (e InvalidExprError) Error() string      { return "invalid expression" }
 func (e UnauthorizedUserError) Error() string { return "unauthorized user" }
+func (e RealmPausedError) Error() string      { return "realm is paused" }
 func (e TypeCheckError) Error() string {
 	var bld strings.Builder
 	bld.WriteString("invalid gno package; type check errors:\n")
@@ -48,6 +50,10 @@ func ErrUnauthorizedUser(msg string) error {
 	return errors.Wrap(UnauthorizedUserError{}, msg)
 }
 
+func ErrRealmPaused(msg string) error {
+	return errors.Wrap(RealmPausedError{}, msg)
+}
+
 func ErrInvalidPkgPath(msg string) error {
 	return errors.Wrap(InvalidPkgPathError{}, msg)
 }
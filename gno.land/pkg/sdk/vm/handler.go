@@ -27,6 +27,8 @@ func (vh vmHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 	switch msg := msg.(type) {
 	case MsgAddPackage:
 		return vh.handleMsgAddPackage(ctx, msg)
+	case MsgUpgradePackage:
+		return vh.handleMsgUpgradePackage(ctx, msg)
 	case MsgCall:
 		return vh.handleMsgCall(ctx, msg)
 	case MsgRun:
@@ -46,6 +48,15 @@ func (vh vmHandler) handleMsgAddPackage(ctx sdk.Context, msg MsgAddPackage) sdk.
 	return sdk.Result{}
 }
 
+// Handle MsgUpgradePackage.
+func (vh vmHandler) handleMsgUpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) sdk.Result {
+	err := vh.vm.UpgradePackage(ctx, msg)
+	if err != nil {
+		return abciResult(err)
+	}
+	return sdk.Result{}
+}
+
 // Handle MsgCall.
 func (vh vmHandler) handleMsgCall(ctx sdk.Context, msg MsgCall) (res sdk.Result) {
 	resstr, err := vh.vm.Call(ctx, msg)
@@ -71,12 +82,14 @@ func (vh vmHandler) handleMsgRun(ctx sdk.Context, msg MsgRun) (res sdk.Result) {
 
 // query paths
 const (
-	QueryRender = "qrender"
-	QueryFuncs  = "qfuncs"
-	QueryEval   = "qeval"
-	QueryFile   = "qfile"
-	QueryDoc    = "qdoc"
-	QueryPaths  = "qpaths"
+	QueryRender  = "qrender"
+	QueryFuncs   = "qfuncs"
+	QueryEval    = "qeval"
+	QueryFile    = "qfile"
+	QueryDoc     = "qdoc"
+	QueryPaths   = "qpaths"
+	QueryObject  = "qobject"
+	QueryStorage = "qstorage"
 )
 
 func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -98,6 +111,10 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Resp
 		res = vh.queryDoc(ctx, req)
 	case QueryPaths:
 		res = vh.queryPaths(ctx, req)
+	case QueryObject:
+		res = vh.queryObject(ctx, req)
+	case QueryStorage:
+		res = vh.queryStorage(ctx, req)
 	default:
 		return sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest(fmt.Sprintf(
@@ -179,6 +196,56 @@ func (vh vmHandler) queryPaths(ctx sdk.Context, req abci.RequestQuery) (res abci
 	return
 }
 
+// queryObject returns the JSON view of a realm's package-level variables, of
+// one of them, or of an object given its ObjectID. The elements of the
+// returned view are paginated with the offset and limit params.
+// If a proof is requested, res.Value is the persisted object at the root of
+// the view, and res.Key its ObjectID.
+func (vh vmHandler) queryObject(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	const defaultLimit = 100
+	const maxLimit = 1_000
+
+	target := string(req.Data)
+
+	var query string
+	if i := strings.IndexByte(req.Path, '?'); i >= 0 {
+		query = req.Path[i+1:]
+	}
+
+	params, _ := url.ParseQuery(query)
+
+	offset := 0
+	if o := params.Get("offset"); len(o) > 0 {
+		var err error
+		if offset, err = strconv.Atoi(o); err != nil || offset < 0 {
+			return sdk.ABCIResponseQueryFromError(fmt.Errorf("invalid offset argument"))
+		}
+	}
+	limit := defaultLimit
+	if l := params.Get("limit"); len(l) > 0 {
+		var err error
+		if limit, err = strconv.Atoi(l); err != nil || limit < 0 {
+			return sdk.ABCIResponseQueryFromError(fmt.Errorf("invalid limit argument"))
+		}
+
+		limit = min(limit, maxLimit) // cap to maxLimit
+	}
+
+	result, err := vh.vm.QueryObject(ctx, target, offset, limit)
+	if err != nil {
+		return sdk.ABCIResponseQueryFromError(err)
+	}
+	if req.Prove {
+		res.Key, res.Value, res.Proof, err = vh.vm.ProveObject(ctx, target)
+		if err != nil {
+			return sdk.ABCIResponseQueryFromError(err)
+		}
+	}
+
+	res.Data = []byte(result)
+	return
+}
+
 // queryEval evaluates any expression in readonly mode and returns the results.
 func (vh vmHandler) queryEval(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
 	pkgPath, expr := parseQueryEvalData(string(req.Data))
@@ -215,8 +282,7 @@ const (
 )
 
 // queryFile returns the file bytes, or list of files if directory.
-// if file, res.Value is []byte("file").
-// if dir, res.Value is []byte("dir").
+// If a proof is requested, res.Value is the amino encoding of the package.
 func (vh vmHandler) queryFile(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
 	filepath := string(req.Data)
 	result, err := vh.vm.QueryFile(ctx, filepath)
@@ -224,6 +290,14 @@ func (vh vmHandler) queryFile(ctx sdk.Context, req abci.RequestQuery) (res abci.
 		res = sdk.ABCIResponseQueryFromError(err)
 		return
 	}
+	if req.Prove {
+		dirpath, _ := std.SplitFilepath(filepath)
+		res.Key, res.Value, res.Proof, err = vh.vm.ProveMemPackage(ctx, dirpath)
+		if err != nil {
+			res = sdk.ABCIResponseQueryFromError(err)
+			return
+		}
+	}
 	res.Data = []byte(result)
 	return
 }
@@ -240,6 +314,17 @@ func (vh vmHandler) queryDoc(ctx sdk.Context, req abci.RequestQuery) (res abci.R
 	return
 }
 
+// queryStorage returns the storage usage of a realm, and its storage deposit.
+func (vh vmHandler) queryStorage(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	pkgPath := string(req.Data)
+	result, err := vh.vm.QueryStorage(ctx, pkgPath)
+	if err != nil {
+		return sdk.ABCIResponseQueryFromError(err)
+	}
+	res.Data = []byte(result)
+	return
+}
+
 // ----------------------------------------
 // misc
 
@@ -1,36 +1,101 @@
 package vm
 
 import (
+	"fmt"
+	"strings"
+
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 )
 
+const (
+	// EventInvariantBroken is the event type emitted by CheckInvariants for
+	// every broken invariant, if the chain doesn't halt.
+	EventInvariantBroken = "InvariantBroken"
+
+	// realmInvariantsFunc is the name of the function a realm exports to
+	// declare its invariants.
+	realmInvariantsFunc = "Invariants"
+	// realmInvariantsExpr evaluates to the error message of the invariants of
+	// a realm, or to an empty string if they hold.
+	realmInvariantsExpr = `func() string { if err := Invariants(); err != nil { return err.Error() }; return "" }()`
+)
+
 // RegisterInvariants registers the vm module invariants
-func RegisterInvariants(ir sdk.InvariantRegistry, vmk VMKeeper) {
-	// ir.RegisterRoute(ModuleName, "nonnegative-outstanding",
-	//	NonnegativeBalanceInvariant(acck))
+func RegisterInvariants(ir sdk.InvariantRegistry, vmk *VMKeeper) {
+	ir.RegisterRoute(ModuleName, "realm-invariants",
+		RealmInvariants(vmk))
 }
 
-/* TODO write new invariants for vm.
-// NonnegativeBalanceInvariant checks that all accounts in the application have non-negative balances
-func NonnegativeBalanceInvariant(acck auth.AccountKeeper) sdk.Invariant {
+// RealmInvariants checks the invariants declared by the realms of the
+// invariant_realms param, as an exported `func Invariants() error`. The
+// function is evaluated read-only, like with QueryEval, and the invariants are
+// broken if it returns an error or panics. The realms which don't declare it,
+// or which don't exist, are skipped.
+func RealmInvariants(vmk *VMKeeper) sdk.Invariant {
 	return func(ctx sdk.Context) (string, bool) {
 		var msg string
 		var count int
 
-		accts := acck.GetAllAccounts(ctx)
-		for _, acc := range accts {
-			coins := acc.GetCoins()
-			if coins.IsAnyNegative() {
+		for _, pkgPath := range vmk.getInvariantRealmsParam(ctx) {
+			if !vmk.declaresInvariants(ctx, pkgPath) {
+				continue
+			}
+			res, err := vmk.QueryEvalString(ctx, pkgPath, realmInvariantsExpr)
+			if err != nil {
+				// Only the first line of the error: VM panics include stacktraces.
+				res, _, _ = strings.Cut(err.Error(), "\n")
+			}
+			if res != "" {
 				count++
-				msg += fmt.Sprintf("\t%s has a negative denomination of %s\n",
-					acc.GetAddress().String(),
-					coins.String())
+				msg += fmt.Sprintf("\t%s: %s\n", pkgPath, res)
 			}
 		}
 		broken := count != 0
 
-		return sdk.FormatInvariant(ModuleName, "nonnegative-outstanding",
-			fmt.Sprintf("amount of negative accounts found %d\n%s", count, msg)), broken
+		return sdk.FormatInvariant(ModuleName, "realm-invariants",
+			fmt.Sprintf("amount of realms with broken invariants %d\n%s", count, msg)), broken
+	}
+}
+
+// declaresInvariants returns whether the realm at pkgPath declares an
+// Invariants function.
+func (vm *VMKeeper) declaresInvariants(ctx sdk.Context, pkgPath string) bool {
+	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	if gnostore.GetPackage(pkgPath, false) == nil {
+		return false
+	}
+	_, ok := gnostore.GetPackageNode(pkgPath).GetLocalIndex(realmInvariantsFunc)
+	return ok
+}
+
+// CheckInvariants checks the invariants registered in ir every
+// invariant_check_period blocks. If any is broken, it panics if the
+// invariant_halt param is set, halting the chain, and otherwise returns an
+// event for each of them.
+func (vm *VMKeeper) CheckInvariants(ctx sdk.Context, ir *sdk.InvariantRouter) []abci.Event {
+	period := vm.getInvariantCheckPeriodParam(ctx)
+	if period == 0 || ctx.BlockHeight()%period != 0 {
+		return nil
+	}
+
+	broken := ir.CheckInvariants(ctx)
+	if len(broken) == 0 {
+		return nil
+	}
+	if vm.getInvariantHaltParam(ctx) {
+		panic(fmt.Sprintf("broken invariants at height %d:\n%s",
+			ctx.BlockHeight(), strings.Join(broken, "")))
+	}
+	events := make([]abci.Event, len(broken))
+	for i, msg := range broken {
+		events[i] = gnostd.GnoEvent{
+			Type: EventInvariantBroken,
+			Attributes: []gnostd.GnoEventAttribute{
+				{Key: "message", Value: msg},
+			},
+		}
 	}
+	return events
 }
-*/
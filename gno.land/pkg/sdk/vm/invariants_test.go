@@ -0,0 +1,131 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestVMKeeperCheckInvariants(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	addPackage := func(pkgPath, body string) {
+		t.Helper()
+		files := []*std.MemFile{
+			{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+			{Name: "test.gno", Body: body},
+		}
+		require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+	}
+	addPackage("gno.land/r/counter", `package counter
+
+import "errors"
+
+var counter, checks int
+
+func Set(cur realm, n int) { counter = n }
+
+func Checks() int { return checks }
+
+func Invariants() error {
+	checks++ // not persisted.
+	if counter < 0 {
+		return errors.New("negative counter")
+	}
+	return nil
+}`)
+	addPackage("gno.land/r/panics", `package panics
+
+var broken bool
+
+func Break(cur realm) { broken = true }
+
+func Invariants() error {
+	if broken {
+		panic("broken")
+	}
+	return nil
+}`)
+	addPackage("gno.land/r/noinvariants", `package noinvariants
+
+func Hello() string { return "hello" }`)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	ir := sdk.NewInvariantRouter()
+	RegisterInvariants(ir, env.vmk)
+	endBlock := func(height int64) []string {
+		t.Helper()
+		ctx := env.ctx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Height: height})
+		var msgs []string
+		for _, evt := range env.vmk.CheckInvariants(ctx, ir) {
+			gevt := evt.(gnostd.GnoEvent)
+			require.Equal(t, EventInvariantBroken, gevt.Type)
+			msgs = append(msgs, gevt.Attributes[0].Value)
+		}
+		return msgs
+	}
+	call := func(pkgPath, fn string, args ...string) {
+		t.Helper()
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, fn, args))
+		require.NoError(t, err)
+		env.vmk.CommitGnoTransactionStore(ctx)
+	}
+
+	// The invariants are not checked by default.
+	call("gno.land/r/counter", "Set", "-1")
+	env.prmk.SetStrings(env.ctx, "vm:p:invariant_realms",
+		[]string{"gno.land/r/counter", "gno.land/r/panics", "gno.land/r/noinvariants", "gno.land/r/notexists"})
+	assert.Empty(t, endBlock(10))
+
+	// The invariants are checked every invariant_check_period blocks.
+	env.prmk.SetInt64(env.ctx, "vm:p:invariant_check_period", 5)
+	assert.Empty(t, endBlock(11))
+	msgs := endBlock(15)
+	require.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0], "vm: realm-invariants invariant")
+	assert.Contains(t, msgs[0], "amount of realms with broken invariants 1")
+	assert.Contains(t, msgs[0], "gno.land/r/counter: negative counter")
+
+	// Panicking invariants are broken too.
+	call("gno.land/r/counter", "Set", "1")
+	assert.Empty(t, endBlock(20))
+	call("gno.land/r/panics", "Break")
+	msgs = endBlock(25)
+	require.Len(t, msgs, 1)
+	assert.Contains(t, msgs[0], "gno.land/r/panics: broken")
+
+	// The invariants are checked read-only.
+	res, err := env.vmk.QueryEval(env.ctx, "gno.land/r/counter", "Checks()")
+	require.NoError(t, err)
+	assert.Equal(t, "(0 int)", res)
+
+	// The chain halts if invariant_halt is set.
+	env.prmk.SetBool(env.ctx, "vm:p:invariant_halt", true)
+	assert.PanicsWithValue(t, "broken invariants at height 30:\n"+msgs[0], func() {
+		endBlock(30)
+	})
+
+	// The invariant realms must be realm paths.
+	assert.Panics(t, func() {
+		env.prmk.SetStrings(env.ctx, "vm:p:invariant_realms", []string{"gno.land/p/demo/avl"})
+	})
+	assert.Panics(t, func() {
+		env.prmk.SetInt64(env.ctx, "vm:p:invariant_check_period", -1)
+	})
+}
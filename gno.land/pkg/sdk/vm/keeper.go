@@ -10,20 +10,29 @@ import (
 	"io"
 	"iter"
 	"log/slog"
+	"maps"
+	"math/big"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gnovm/pkg/doc"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/gnovm/stdlibs"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
@@ -45,6 +54,7 @@ const (
 // smart contracts programming (scripting).
 type VMKeeperI interface {
 	AddPackage(ctx sdk.Context, msg MsgAddPackage) error
+	UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) error
 	Call(ctx sdk.Context, msg MsgCall) (res string, err error)
 	QueryEval(ctx sdk.Context, pkgPath string, expr string) (res string, err error)
 	Run(ctx sdk.Context, msg MsgRun) (res string, err error)
@@ -53,6 +63,9 @@ type VMKeeperI interface {
 	MakeGnoTransactionStore(ctx sdk.Context) sdk.Context
 	CommitGnoTransactionStore(ctx sdk.Context)
 	InitGenesis(ctx sdk.Context, data GenesisState)
+	ChainCall(ctx sdk.Context, pkgPath, fn string, args []string) ([]abci.Event, error)
+	RunScheduledCalls(ctx sdk.Context) []abci.Event
+	CheckInvariants(ctx sdk.Context, ir *sdk.InvariantRouter) []abci.Event
 }
 
 var _ VMKeeperI = &VMKeeper{}
@@ -129,6 +142,24 @@ func (vm *VMKeeper) Initialize(
 	}
 }
 
+// Reinitialize discards the cached gno store, and initializes the VMKeeper
+// again from ms, e.g. after the state was restored from a snapshot.
+func (vm *VMKeeper) Reinitialize(
+	logger *slog.Logger,
+	ms store.MultiStore,
+) {
+	vm.gnoStore = nil
+	vm.Initialize(logger, ms)
+}
+
+// VerifyRestoredState verifies the gno entries of the base store restored
+// from a state sync snapshot, which are not covered by the app hash, against
+// the hashes committed in the iavl store. See gno.VerifyBackend. The state of
+// a chain with entries saved before their hashes were committed is rejected.
+func (vm *VMKeeper) VerifyRestoredState(ctx sdk.Context) error {
+	return gno.VerifyBackend(ctx.Store(vm.baseKey), ctx.Store(vm.iavlKey), sdk.IsBaseAppKey)
+}
+
 type stdlibCache struct {
 	dir  string
 	base store.Store
@@ -268,13 +299,57 @@ func (vm *VMKeeper) checkNamespacePermission(ctx sdk.Context, creator crypto.Add
 		return nil
 	}
 
+	// call sysNamesPkg.IsAuthorizedAddressForName("<user>")
+	// We only need to check by name here, as addresses have already been checked
+	useraddress := vm.evalSysNames(ctx, store, creator, "IsAuthorizedAddressForNamespace",
+		gno.Str(creator.String()),
+		gno.Str(namespace),
+	)
+	if useraddress.T.Kind() != gno.BoolKind {
+		panic("call: invalid response kind")
+	}
+
+	if isAuthorized := useraddress.GetBool(); !isAuthorized {
+		return ErrUnauthorizedUser(
+			fmt.Sprintf("%s is not authorized to deploy packages to namespace `%s`",
+				creator.String(),
+				namespace,
+			))
+	}
+
+	return nil
+}
+
+// isNamespaceCheckEnabled returns whether the sys/names package exists and
+// has its namespace check enabled, without which any address is authorized
+// on any namespace.
+func (vm *VMKeeper) isNamespaceCheckEnabled(ctx sdk.Context, store gno.Store, caller crypto.Address) bool {
+	sysNamesPkg := vm.getSysNamesPkgParam(ctx)
+	if sysNamesPkg == "" || store.GetPackage(sysNamesPkg, false) == nil {
+		return false
+	}
+
+	enabled := vm.evalSysNames(ctx, store, caller, "IsEnabled")
+	if enabled.T.Kind() != gno.BoolKind {
+		panic("call: invalid response kind")
+	}
+
+	return enabled.GetBool()
+}
+
+// evalSysNames calls the function fn of the sys/names package with args, on
+// behalf of caller, and returns its result.
+func (vm *VMKeeper) evalSysNames(ctx sdk.Context, store gno.Store, caller crypto.Address, fn string, args ...any) gno.TypedValue {
+	sysNamesPkg := vm.getSysNamesPkgParam(ctx)
+	chainDomain := vm.getChainDomainParam(ctx)
+
 	// Parse and run the files, construct *PV.
 	msgCtx := stdlibs.ExecContext{
 		ChainID:         ctx.ChainID(),
 		ChainDomain:     chainDomain,
 		Height:          ctx.BlockHeight(),
 		Timestamp:       ctx.BlockTime().Unix(),
-		OriginCaller:    creator.Bech32(),
+		OriginCaller:    caller.Bech32(),
 		OriginSendSpent: new(std.Coins),
 		// XXX: should we remove the banker ?
 		Banker:      NewSDKBanker(vm, ctx),
@@ -293,36 +368,17 @@ func (vm *VMKeeper) checkNamespacePermission(ctx sdk.Context, creator crypto.Add
 		})
 	defer m.Release()
 
-	// call sysNamesPkg.IsAuthorizedAddressForName("<user>")
-	// We only need to check by name here, as addresses have already been checked
 	mpv := gno.NewPackageNode("main", "main", nil).NewPackage()
 	m.SetActivePackage(mpv)
 	m.RunDeclaration(gno.ImportD("names", sysNamesPkg))
-	x := gno.Call(
-		gno.Sel(gno.Nx("names"), "IsAuthorizedAddressForNamespace"),
-		gno.Str(creator.String()),
-		gno.Str(namespace),
-	)
+	x := gno.Call(gno.Sel(gno.Nx("names"), fn), args...)
 
 	ret := m.Eval(x)
 	if len(ret) == 0 {
 		panic("call: invalid response length")
 	}
 
-	useraddress := ret[0]
-	if useraddress.T.Kind() != gno.BoolKind {
-		panic("call: invalid response kind")
-	}
-
-	if isAuthorized := useraddress.GetBool(); !isAuthorized {
-		return ErrUnauthorizedUser(
-			fmt.Sprintf("%s is not authorized to deploy packages to namespace `%s`",
-				creator.String(),
-				namespace,
-			))
-	}
-
-	return nil
+	return ret[0]
 }
 
 // AddPackage adds a package with given fileset.
@@ -394,6 +450,7 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 		Banker:          NewSDKBanker(vm, ctx),
 		Params:          NewSDKParams(vm.prmk, ctx),
 		EventLogger:     ctx.EventLogger(),
+		Scheduler:       NewSDKScheduler(vm, ctx),
 	}
 	// Parse and run the files, construct *PV.
 	m2 := gno.NewMachineWithOptions(
@@ -404,11 +461,20 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 			Alloc:    gnostore.GetAllocator(),
 			Context:  msgCtx,
 			GasMeter: ctx.GasMeter(),
+			OnRealm:  vm.pausedRealmsHook(ctx),
 		})
 	defer m2.Release()
 	defer doRecover(m2, &err)
 	m2.RunMemPackage(memPkg, true)
 
+	// Lock the storage deposit of the state of the realm.
+	if err := vm.processStorageDeposit(ctx, creator, gnostore); err != nil {
+		return err
+	}
+
+	// Record the creator, who may upgrade the package.
+	ctx.Store(vm.iavlKey).Set(pkgCreatorKey(pkgPath), creator.Bytes())
+
 	// Log the telemetry
 	logTelemetry(
 		m2.GasMeter.GasConsumed(),
@@ -422,10 +488,111 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 	return nil
 }
 
+// pkgCreatorKey returns the key of the creator of the package at pkgPath in
+// the iavl store.
+func pkgCreatorKey(pkgPath string) []byte {
+	return []byte("pkgcreator:" + pkgPath)
+}
+
+// UpgradePackage replaces the code of an existing realm, keeping its state.
+// Only the creator of the realm, or the owner of its namespace once sys/names
+// is enabled, may upgrade it. Realms added before creators were recorded have
+// no creator, and can only be upgraded by the owner of their namespace.
+func (vm *VMKeeper) UpgradePackage(ctx sdk.Context, msg MsgUpgradePackage) (err error) {
+	caller := msg.Caller
+	pkgPath := msg.Package.Path
+	memPkg := msg.Package
+	gnostore := vm.getGnoTransactionStore(ctx)
+	chainDomain := vm.getChainDomainParam(ctx)
+
+	// Validate arguments.
+	if caller.IsZero() {
+		return std.ErrInvalidAddress("missing caller address")
+	}
+	if err := gno.ValidateMemPackage(msg.Package); err != nil {
+		return ErrInvalidPkgPath(err.Error())
+	}
+	if !strings.HasPrefix(pkgPath, chainDomain+"/") {
+		return ErrInvalidPkgPath("invalid domain: " + pkgPath)
+	}
+	if !gno.IsRealmPath(pkgPath) {
+		return ErrInvalidPkgPath("package path must be a valid realm path")
+	}
+	if pv := gnostore.GetPackage(pkgPath, false); pv == nil {
+		return ErrInvalidPkgPath("package does not exist: " + pkgPath)
+	}
+
+	// Validate Gno syntax and type check.
+	_, err = gno.TypeCheckMemPackage(memPkg, gnostore, gno.ParseModeProduction, gno.TCLatestStrict)
+	if err != nil {
+		return ErrTypeCheck(err)
+	}
+
+	// Check that the caller is the creator, or owns the namespace. The
+	// namespace is only owned once sys/names is enabled: before that, any
+	// address is authorized on any namespace.
+	creator := ctx.Store(vm.iavlKey).Get(pkgCreatorKey(pkgPath))
+	if !bytes.Equal(creator, caller.Bytes()) {
+		if !vm.isNamespaceCheckEnabled(ctx, gnostore, caller) {
+			return ErrUnauthorizedUser(
+				fmt.Sprintf("%s is not authorized to upgrade package %s", caller, pkgPath))
+		}
+		if err := vm.checkNamespacePermission(ctx, caller, pkgPath); err != nil {
+			return err
+		}
+	}
+
+	// Parse and run the files, replacing the code of the *PV.
+	msgCtx := stdlibs.ExecContext{
+		ChainID:         ctx.ChainID(),
+		ChainDomain:     chainDomain,
+		Height:          ctx.BlockHeight(),
+		Timestamp:       ctx.BlockTime().Unix(),
+		OriginCaller:    caller.Bech32(),
+		OriginSendSpent: new(std.Coins),
+		Banker:          NewSDKBanker(vm, ctx),
+		Params:          NewSDKParams(vm.prmk, ctx),
+		EventLogger:     ctx.EventLogger(),
+		Scheduler:       NewSDKScheduler(vm, ctx),
+	}
+	m2 := gno.NewMachineWithOptions(
+		gno.MachineOptions{
+			PkgPath:  "",
+			Output:   vm.Output,
+			Store:    gnostore,
+			Alloc:    gnostore.GetAllocator(),
+			Context:  msgCtx,
+			GasMeter: ctx.GasMeter(),
+		})
+	defer m2.Release()
+	defer doRecover(m2, &err)
+	m2.UpgradeMemPackage(memPkg)
+
+	// Lock (or refund) the storage deposit of the state changes.
+	if err := vm.processStorageDeposit(ctx, caller, gnostore); err != nil {
+		return err
+	}
+
+	// Log the telemetry
+	logTelemetry(
+		m2.GasMeter.GasConsumed(),
+		m2.Cycles,
+		attribute.KeyValue{
+			Key:   "operation",
+			Value: attribute.StringValue("m_upgradepkg"),
+		},
+	)
+
+	return nil
+}
+
 // Call calls a public Gno function (for delivertx).
 func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 	pkgPath := msg.PkgPath // to import
 	fnc := msg.Func
+	if err := vm.checkNotPaused(ctx, pkgPath); err != nil {
+		return "", err
+	}
 	gnostore := vm.getGnoTransactionStore(ctx)
 	// Get the package and function type.
 	pv := gnostore.GetPackage(pkgPath, false)
@@ -489,6 +656,7 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 		Banker:          NewSDKBanker(vm, ctx),
 		Params:          NewSDKParams(vm.prmk, ctx),
 		EventLogger:     ctx.EventLogger(),
+		Scheduler:       NewSDKScheduler(vm, ctx),
 	}
 	// Construct machine and evaluate.
 	m := gno.NewMachineWithOptions(
@@ -499,6 +667,7 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 			Context:  msgCtx,
 			Alloc:    gnostore.GetAllocator(),
 			GasMeter: ctx.GasMeter(),
+			OnRealm:  vm.pausedRealmsHook(ctx),
 		})
 	defer m.Release()
 	m.SetActivePackage(mpv)
@@ -511,6 +680,11 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 		}
 	}
 
+	// Lock (or refund) the storage deposit of the state changes.
+	if err := vm.processStorageDeposit(ctx, caller, gnostore); err != nil {
+		return "", err
+	}
+
 	// Log the telemetry
 	logTelemetry(
 		m.GasMeter.GasConsumed(),
@@ -555,6 +729,11 @@ func doRecoverInternal(m *gno.Machine, e *error, r any, repanicOutOfGas bool) {
 			*e = oog
 			return
 		}
+		if goerrors.Is(err, RealmPausedError{}) {
+			// The execution crossed into a paused realm.
+			*e = err
+			return
+		}
 		var up gno.UnhandledPanicError
 		if goerrors.As(err, &up) {
 			// Common unhandled panic error, skip machine state.
@@ -595,6 +774,9 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	if err := gno.ValidateMemPackage(msg.Package); err != nil {
 		return "", ErrInvalidPkgPath(err.Error())
 	}
+	if err := vm.checkImportsNotPaused(ctx, memPkg); err != nil {
+		return "", err
+	}
 
 	// Validate Gno syntax and type check.
 	_, err = gno.TypeCheckMemPackage(memPkg, gnostore, gno.ParseModeProduction, gno.TCLatestRelaxed)
@@ -620,6 +802,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 		Banker:          NewSDKBanker(vm, ctx),
 		Params:          NewSDKParams(vm.prmk, ctx),
 		EventLogger:     ctx.EventLogger(),
+		Scheduler:       NewSDKScheduler(vm, ctx),
 	}
 
 	buf := new(bytes.Buffer)
@@ -639,6 +822,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 				Alloc:    gnostore.GetAllocator(),
 				Context:  msgCtx,
 				GasMeter: ctx.GasMeter(),
+				OnRealm:  vm.pausedRealmsHook(ctx),
 			})
 		defer m.Release()
 		defer doRecover(m, &err)
@@ -659,12 +843,37 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 			Alloc:    gnostore.GetAllocator(),
 			Context:  msgCtx,
 			GasMeter: ctx.GasMeter(),
+			OnRealm:  vm.pausedRealmsHook(ctx),
 		})
 	defer m2.Release()
 	m2.SetActivePackage(pv)
 	defer doRecover(m2, &err)
+	// The results of the Results function of the script, if declared, are
+	// given after its output, in JSON on their own line.
+	hasResults := false
+	pn := pv.GetPackageNode(gnostore)
+	if idx, ok := pn.GetLocalIndex("Results"); ok {
+		if ft, ok := pn.Types[idx].(*gno.FuncType); ok {
+			if len(ft.Params) > 0 {
+				return "", ErrInvalidExpr("function Results of the script cannot have parameters")
+			}
+			hasResults = true
+		}
+	}
 	m2.RunMain()
 	res = buf.String()
+	if hasResults {
+		rtvs := m2.Eval(gno.Call("Results"))
+		if res != "" && !strings.HasSuffix(res, "\n") {
+			res += "\n"
+		}
+		res += stringifyJSONResults(gnostore, rtvs) + "\n"
+	}
+
+	// Lock (or refund) the storage deposit of the state changes.
+	if err := vm.processStorageDeposit(ctx, caller, gnostore); err != nil {
+		return "", err
+	}
 
 	// Log the telemetry
 	logTelemetry(
@@ -679,6 +888,96 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	return res, nil
 }
 
+// StorageDepositAddr returns the address holding the storage deposit of the
+// realm pkgPath. Unlike the address of the realm, it cannot be spent from.
+func StorageDepositAddr(pkgPath string) crypto.Address {
+	// NOTE: must not collide with pubkey addrs.
+	return crypto.AddressFromPreimage([]byte("storageDeposit:" + pkgPath))
+}
+
+// RealmStorage is the storage usage of a realm, and the deposit locked for
+// it.
+type RealmStorage struct {
+	Storage int64 `json:"storage"` // bytes of the persisted objects.
+	Deposit int64 `json:"deposit"` // ugnot locked by storage deposits.
+}
+
+// realmStorageKey returns the key of the RealmStorage of the realm at pkgPath
+// in the iavl store.
+func realmStorageKey(pkgPath string) []byte {
+	return []byte("pkgstorage:" + pkgPath)
+}
+
+func (vm *VMKeeper) getRealmStorage(ctx sdk.Context, pkgPath string) (rs RealmStorage, ok bool) {
+	bz := ctx.Store(vm.iavlKey).Get(realmStorageKey(pkgPath))
+	if bz == nil {
+		return RealmStorage{}, false
+	}
+	amino.MustUnmarshal(bz, &rs)
+	return rs, true
+}
+
+func (vm *VMKeeper) setRealmStorage(ctx sdk.Context, pkgPath string, rs RealmStorage) {
+	ctx.Store(vm.iavlKey).Set(realmStorageKey(pkgPath), amino.MustMarshal(rs))
+}
+
+// processStorageDeposit settles the storage deposits of the realms whose
+// state was changed by a message, after it was run. For the bytes added to
+// the state of a realm, a deposit of the storage price per byte is locked from
+// the caller. For the bytes removed, the caller is refunded in proportion of
+// the deposit of the realm, as the price may have changed since the deposit.
+func (vm *VMKeeper) processStorageDeposit(ctx sdk.Context, caller crypto.Address, gnostore gno.Store) error {
+	diffs := gnostore.RealmStorageDiffs()
+	if len(diffs) == 0 {
+		return nil
+	}
+	price := vm.getStoragePriceParam(ctx)
+
+	// NOTE: sorted for determinism.
+	for _, rlmPath := range slices.Sorted(maps.Keys(diffs)) {
+		diff := diffs[rlmPath]
+		if diff == 0 {
+			continue
+		}
+		rs, _ := vm.getRealmStorage(ctx, rlmPath)
+		depositAddr := StorageDepositAddr(rlmPath)
+		if diff > 0 {
+			amount := overflow.Mulp(price.Amount, diff)
+			if amount > 0 {
+				deposit := std.Coins{std.NewCoin(ugnot.Denom, amount)}
+				err := vm.bank.SendCoinsUnrestricted(ctx, caller, depositAddr, deposit)
+				if err != nil {
+					return std.ErrInsufficientCoins(fmt.Sprintf(
+						"cannot lock storage deposit of %s for %d bytes in %s: %v",
+						deposit, diff, rlmPath, err))
+				}
+			}
+			rs.Storage = overflow.Addp(rs.Storage, diff)
+			rs.Deposit = overflow.Addp(rs.Deposit, amount)
+		} else {
+			released := min(-diff, rs.Storage)
+			if released == 0 {
+				continue
+			}
+			// refund = deposit * released / storage, without overflow.
+			refund := big.NewInt(rs.Deposit)
+			refund.Mul(refund, big.NewInt(released))
+			refund.Quo(refund, big.NewInt(rs.Storage))
+			if amount := refund.Int64(); amount > 0 {
+				err := vm.bank.SendCoinsUnrestricted(ctx, depositAddr, caller,
+					std.Coins{std.NewCoin(ugnot.Denom, amount)})
+				if err != nil {
+					return err
+				}
+			}
+			rs.Storage -= released
+			rs.Deposit -= refund.Int64()
+		}
+		vm.setRealmStorage(ctx, rlmPath, rs)
+	}
+	return nil
+}
+
 var reUserNamespace = regexp.MustCompile(`^[~_a-zA-Z0-9/]+$`)
 
 // QueryPaths returns public facing function signatures.
@@ -793,9 +1092,11 @@ func (vm *VMKeeper) QueryFuncs(ctx sdk.Context, pkgPath string) (fsigs FunctionS
 				pname = "_"
 			}
 			ptype := gno.BaseOf(param.Type).String()
-			fsig.Params = append(fsig.Params,
-				NamedType{Name: pname, Type: ptype},
-			)
+			nt := NamedType{Name: pname, Type: ptype}
+			if isJSONArgType(param.Type) {
+				nt.Schema = jsonSchemaOf(param.Type)
+			}
+			fsig.Params = append(fsig.Params, nt)
 		}
 		for _, result := range ft.Results {
 			rname := string(result.Name)
@@ -885,9 +1186,197 @@ func (vm *VMKeeper) queryEvalInternal(ctx sdk.Context, pkgPath string, expr stri
 		})
 	defer m.Release()
 	defer doRecoverQuery(m, &err)
+	convertJSONCallArgs(gnostore, pkgPath, xx)
 	return m.Eval(xx), err
 }
 
+// convertJSONCallArgs replaces the string literal arguments of a call to a
+// function of the package at pkgPath by their values given in JSON, for the
+// parameters of JSON types (see convertArgToGno).
+func convertJSONCallArgs(gnostore gno.Store, pkgPath string, xx gno.Expr) {
+	cx, ok := xx.(*gno.CallExpr)
+	if !ok {
+		return
+	}
+	nx, ok := cx.Func.(*gno.NameExpr)
+	if !ok {
+		return
+	}
+	pn := gnostore.GetPackageNode(pkgPath)
+	if _, ok := pn.GetLocalIndex(nx.Name); !ok {
+		return
+	}
+	ft, ok := pn.GetStaticTypeOf(gnostore, nx.Name).(*gno.FuncType)
+	if !ok {
+		return
+	}
+	for i, arg := range cx.Args {
+		if i >= len(ft.Params) || ft.HasVarg() && i >= len(ft.Params)-1 {
+			break
+		}
+		bx, ok := arg.(*gno.BasicLitExpr)
+		if !ok || bx.Kind != gno.STRING || !isJSONArgType(ft.Params[i].Type) {
+			continue
+		}
+		s, err := strconv.Unquote(bx.Value)
+		if err != nil {
+			panic(err)
+		}
+		cx.Args[i] = &gno.ConstExpr{
+			TypedValue: convertArgToGno(s, ft.Params[i].Type),
+		}
+	}
+}
+
+// QueryObject returns the JSON view of the persisted state at target: the
+// package-level variables of a realm for "<pkgpath>", one of them for
+// "<pkgpath>.<name>", or an object for "<objectid>". The elements of the
+// package block, of the objects and of the variables that are arrays, slices,
+// maps or AVL trees are paginated with offset and limit.
+func (vm *VMKeeper) QueryObject(ctx sdk.Context, target string, offset, limit int) (res string, err error) {
+	ctx = ctx.WithGasMeter(store.NewGasMeter(maxGasQuery))
+	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	ov := &objectViewer{store: gnostore, offset: offset, limit: limit}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid object %q: %v", target, r)
+		}
+	}()
+
+	// <objectid>
+	if !strings.Contains(target, "/") {
+		var oid gno.ObjectID
+		if err := oid.UnmarshalAmino(target); err != nil {
+			return "", ErrInvalidPkgPath(fmt.Sprintf(
+				"invalid object id: %s", target))
+		}
+		oo := gnostore.GetObjectSafe(oid)
+		if oo == nil {
+			return "", fmt.Errorf("object not found: %s", target)
+		}
+		return ov.objectView(oo).JSON(), nil
+	}
+
+	// <pkgpath>[.<name>]
+	pkgPath, name := target, ""
+	if !gno.IsRealmPath(pkgPath) {
+		pkgPath, name = parseQueryEvalData(target)
+	}
+	if !gno.IsRealmPath(pkgPath) {
+		return "", ErrInvalidPkgPath(fmt.Sprintf(
+			"package is not realm: %s", pkgPath))
+	}
+	pv := gnostore.GetPackage(pkgPath, false)
+	if pv == nil {
+		return "", ErrInvalidPkgPath(fmt.Sprintf(
+			"package not found: %s", pkgPath))
+	}
+	pn := gnostore.GetPackageNode(pkgPath)
+	pb := pv.GetBlock(gnostore)
+	if name == "" {
+		return ov.packageView(pn, pb).JSON(), nil
+	}
+	idx, ok := pn.GetLocalIndex(gno.Name(name))
+	if !ok {
+		return "", fmt.Errorf("variable not found: %s", target)
+	}
+	return ov.varView(varValue(pb, idx)).JSON(), nil
+}
+
+// ProveObject returns the ObjectID and the persisted bytes of the object at
+// the root of the view of QueryObject for target, with their merkle proof up
+// to the app hash. For a realm path or a realm variable, the proven object is
+// the package block of the realm. ctx must be the context of a query which
+// requests a proof.
+func (vm *VMKeeper) ProveObject(ctx sdk.Context, target string) (key, value []byte, proof *merkle.Proof, err error) {
+	prover, ok := ctx.Value(sdk.StoreProverContextKey{}).(sdk.StoreProver)
+	if !ok {
+		return nil, nil, nil, errors.New("proofs are not available")
+	}
+	ctx = ctx.WithGasMeter(store.NewGasMeter(maxGasQuery))
+	gnostore := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid object %q: %v", target, r)
+		}
+	}()
+
+	var oid gno.ObjectID
+	if !strings.Contains(target, "/") {
+		// <objectid>
+		if err := oid.UnmarshalAmino(target); err != nil {
+			return nil, nil, nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"invalid object id: %s", target))
+		}
+	} else {
+		// <pkgpath>[.<name>]
+		pkgPath := target
+		if !gno.IsRealmPath(pkgPath) {
+			pkgPath, _ = parseQueryEvalData(target)
+		}
+		if !gno.IsRealmPath(pkgPath) {
+			return nil, nil, nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"package is not realm: %s", pkgPath))
+		}
+		pv := gnostore.GetPackage(pkgPath, false)
+		if pv == nil {
+			return nil, nil, nil, ErrInvalidPkgPath(fmt.Sprintf(
+				"package not found: %s", pkgPath))
+		}
+		oid = pv.GetBlock(gnostore).GetObjectID()
+	}
+
+	chain, rootKey := gnostore.GetObjectProofChain(oid)
+	if chain == nil {
+		return nil, nil, nil, fmt.Errorf("object not found: %s", target)
+	}
+	rootHash, proof, err := prover(vm.iavlKey, rootKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if rootHash == nil {
+		// The hashes of package values are only committed since they are
+		// proven, so the packages saved before have none.
+		return nil, nil, nil, fmt.Errorf("no proof available for %s: the hash of package %s is not committed, as it was saved before package hashes were", target, rootKey)
+	}
+	// Objects are not versioned, unlike their committed hashes.
+	if !bytes.Equal(rootHash, gno.HashBytes(chain[len(chain)-1]).Bytes()) {
+		return nil, nil, nil, fmt.Errorf("object %s was updated after the query height", target)
+	}
+	op := NewObjectProofOp(oid, chain[1:])
+	proof.Ops = append([]merkle.ProofOp{op.ProofOp()}, proof.Ops...)
+	return op.GetKey(), chain[0], proof, nil
+}
+
+// ProveMemPackage returns the key and the amino encoding of the package
+// pkgPath in the iavl store, with their merkle proof up to the app hash. ctx
+// must be the context of a query which requests a proof.
+func (vm *VMKeeper) ProveMemPackage(ctx sdk.Context, pkgPath string) (key, value []byte, proof *merkle.Proof, err error) {
+	prover, ok := ctx.Value(sdk.StoreProverContextKey{}).(sdk.StoreProver)
+	if !ok {
+		return nil, nil, nil, errors.New("proofs are not available")
+	}
+	key = gno.MemPackageIAVLKey(pkgPath)
+	value, proof, err = prover(vm.iavlKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if value == nil {
+		return nil, nil, nil, fmt.Errorf("package %q is not available", pkgPath)
+	}
+	return key, value, proof, nil
+}
+
+// QueryStorage returns the bytes of persisted objects of the realm pkgPath,
+// and the storage deposit locked for them.
+func (vm *VMKeeper) QueryStorage(ctx sdk.Context, pkgPath string) (string, error) {
+	rs, ok := vm.getRealmStorage(ctx, pkgPath)
+	if !ok {
+		return "", ErrInvalidPkgPath(fmt.Sprintf("realm not found: %s", pkgPath))
+	}
+	return fmt.Sprintf("storage: %d, deposit: %s", rs.Storage, ugnot.ValueString(rs.Deposit)), nil
+}
+
 func (vm *VMKeeper) QueryFile(ctx sdk.Context, filepath string) (res string, err error) {
 	store := vm.newGnoTransactionStore(ctx) // throwaway (never committed)
 	dirpath, filename := std.SplitFilepath(filepath)
@@ -3,8 +3,12 @@ package vm
 // TODO: move most of the logic in ROOT/gno.land/...
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -12,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
@@ -150,6 +155,8 @@ func Echo(cur realm, msg string) string {
 	msg1 := NewMsgAddPackage(addr, pkgPath, files)
 	err := env.vmk.AddPackage(ctx, msg1)
 	assert.NoError(t, err)
+	// Refill the storage deposit of the package.
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
 
 	// Run Echo function.
 	coins := std.MustParseCoins(coinsString)
@@ -297,6 +304,8 @@ func Echo(cur realm, msg string) string {
 	msg1 := NewMsgAddPackage(addr, pkgPath, files)
 	err := env.vmk.AddPackage(ctx, msg1)
 	assert.NoError(t, err)
+	// Refill the storage deposit of the package.
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
 
 	// Run Echo function.
 	coins := std.MustParseCoins(coinsString)
@@ -621,6 +630,625 @@ func Echo(cur realm, msg string) string {
 	assert.Equal(t, `("echo:hello world" string)`+"\n\n", res)
 }
 
+func TestVMKeeperVerifyRestoredState(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package, with objects owned by other objects.
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "init.gno", Body: `
+package test
+
+type item struct {
+	Name string
+	Tags []string
+}
+
+var items []*item
+
+func Add(cur realm, name string) {
+	items = append(items, &item{Name: name, Tags: []string{name}})
+}`},
+	}
+
+	msg1 := NewMsgAddPackage(addr, pkgPath, files)
+	require.NoError(t, env.vmk.AddPackage(ctx, msg1))
+
+	msg2 := NewMsgCall(addr, nil, pkgPath, "Add", []string{"hello"})
+	_, err := env.vmk.Call(ctx, msg2)
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// The honest state, including the stdlibs, is verified.
+	require.NoError(t, env.vmk.VerifyRestoredState(env.ctx))
+
+	baseStore := env.ctx.Store(env.vmk.baseKey)
+
+	// The keys of the objects of the test realm.
+	var objects [][]byte
+	pkgID, _, _ := strings.Cut(gnolang.ObjectIDFromPkgPath(pkgPath).String(), ":")
+	iter := baseStore.Iterator(nil, nil)
+	for ; iter.Valid(); iter.Next() {
+		key := string(iter.Key())
+		if strings.HasPrefix(key, "oid:"+pkgID+":") && !strings.HasSuffix(key, "#realm") {
+			objects = append(objects, iter.Key())
+		}
+	}
+	iter.Close()
+	require.NotEmpty(t, objects)
+
+	// forgeObject replaces the name of the object with a valid hash.
+	forgeObject := func(value []byte) []byte {
+		bz := bytes.Replace(value[gnolang.HashSize:], []byte("hello"), []byte("HELLO"), 1)
+		return append(gnolang.HashBytes(bz).Bytes(), bz...)
+	}
+
+	realmKey := []byte("oid:" + gnolang.ObjectIDFromPkgPath(pkgPath).String() + "#realm")
+	require.True(t, baseStore.Has(realmKey))
+
+	testCases := []struct {
+		name        string
+		tamper      func(st types.Store)
+		errContains string
+	}{
+		{
+			"object not matching its hash",
+			func(st types.Store) {
+				value := st.Get(objects[0])
+				st.Set(objects[0], append(value[:len(value):len(value)], 0))
+			},
+			"does not match its hash",
+		},
+		{
+			"forged object",
+			func(st types.Store) {
+				for _, key := range objects {
+					if value := st.Get(key); bytes.Contains(value, []byte("hello")) {
+						st.Set(key, forgeObject(value))
+						return
+					}
+				}
+				t.Fatal("no object with the name")
+			},
+			"hash of object",
+		},
+		{
+			"missing object",
+			func(st types.Store) {
+				st.Delete(objects[len(objects)-1])
+			},
+			"missing entry " + string(objects[len(objects)-1]),
+		},
+		{
+			"forged realm",
+			func(st types.Store) {
+				st.Set(realmKey, append(st.Get(realmKey), 0))
+			},
+			"is not committed",
+		},
+		{
+			"missing package index",
+			func(st types.Store) {
+				st.Delete([]byte("pkgidx:counter"))
+			},
+			"missing entry pkgidx:counter",
+		},
+		{
+			"unexpected key",
+			func(st types.Store) {
+				st.Set([]byte("node:forged"), []byte("node"))
+			},
+			"unexpected key",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ms := env.ctx.MultiStore().MultiCacheWrap()
+			tc.tamper(ms.GetStore(env.vmk.baseKey))
+
+			err := env.vmk.VerifyRestoredState(env.ctx.WithMultiStore(ms))
+			assert.ErrorContains(t, err, tc.errContains)
+		})
+	}
+}
+
+func TestVMKeeperCallJSONArgs(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+import "std"
+
+type order struct {
+	Owner  std.Address
+	Items  []item
+	Labels map[string]int
+	Next   *order
+}
+
+type item struct {
+	Name string
+	Qty  uint8
+}
+
+var orders []order
+
+func Place(cur realm, o order, to []std.Address) string {
+	orders = append(orders, o)
+	s := string(o.Owner) + ":"
+	for _, it := range o.Items {
+		s += it.Name + "x" + string(rune('0'+it.Qty)) + ","
+	}
+	if o.Next != nil {
+		s += "next=" + o.Next.Items[0].Name + ","
+	}
+	return s + string(rune('0'+o.Labels["b"])) + ":" + string(to[1])
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+
+	arg := `{"Owner":"g1owner","Items":[{"Name":"apple","Qty":3},{"Name":"pear","Qty":1}],` +
+		`"Labels":{"b":2,"a":1},"Next":{"Items":[{"Name":"plum"}]}}`
+	msg := NewMsgCall(addr, nil, pkgPath, "Place", []string{arg, `["g1a","g1b"]`})
+	res, err := env.vmk.Call(ctx, msg)
+	require.NoError(t, err)
+	assert.Equal(t, `("g1owner:applex3,pearx1,next=plum,2:g1b" string)`+"\n\n", res)
+
+	// Unknown fields are rejected.
+	msg = NewMsgCall(addr, nil, pkgPath, "Place", []string{`{"Onwer":"g1owner"}`, `[]`})
+	assert.PanicsWithValue(t, `unknown field "Onwer" in gno.land/r/test.order`, func() {
+		env.vmk.Call(ctx, msg)
+	})
+
+	// The same arguments can be given to qeval.
+	env.vmk.CommitGnoTransactionStore(ctx)
+	res, err = env.vmk.QueryEval(ctx, pkgPath, fmt.Sprintf(`Place(cross, %q, %q)`, arg, `["g1a","g1c"]`))
+	require.NoError(t, err)
+	assert.Equal(t, `("g1owner:applex3,pearx1,next=plum,2:g1c" string)`, res)
+
+	// The signatures describe the JSON arguments.
+	fsigs, err := env.vmk.QueryFuncs(ctx, pkgPath)
+	require.NoError(t, err)
+	require.Len(t, fsigs, 1)
+	assert.Equal(t, `{"items":{"type":"string"},"type":["array","null"]}`, fsigs[0].Params[2].Schema)
+	assert.Contains(t, fsigs[0].Params[1].Schema, `"Qty":{"type":"integer"}`)
+}
+
+func TestVMKeeperRunJSONResults(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+type Item struct {
+	Name   string
+	Price  float64
+	Tags   []string
+	Next   *Item
+	hidden int
+}
+
+var items = []Item{
+	{Name: "apple", Price: 1.5, Tags: []string{"fruit"}},
+	{Name: "bread", Price: 2, Next: &Item{Name: "butter"}},
+}
+
+func Items() []Item { return items }
+
+func Find(name string) *Item {
+	for i := range items {
+		if items[i].Name == name {
+			return &items[i]
+		}
+	}
+	return nil
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// The results of Results follow the output, in JSON.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	files = []*std.MemFile{
+		{Name: "script.gno", Body: `package main
+
+import "gno.land/r/test"
+
+func main() {
+	print("listing")
+}
+
+func Results() (int, []test.Item, *test.Item, map[string]bool, []byte, error) {
+	return len(test.Items()), test.Items(), test.Find("cherry"), map[string]bool{"ok": true}, []byte("hi"), nil
+}`},
+	}
+	res, err := env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+	require.NoError(t, err)
+	assert.Equal(t, "listing\n"+
+		`[2,[{"Name":"apple","Next":null,"Price":1.5,"Tags":["fruit"]},`+
+		`{"Name":"bread","Next":{"Name":"butter","Next":null,"Price":0,"Tags":null},"Price":2,"Tags":null}],`+
+		`null,{"ok":true},"aGk=",null]`+"\n", res)
+
+	// Without Results, only the output is given.
+	files[0].Body = `package main
+
+func main() {
+	println("done")
+}`
+	res, err = env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+	require.NoError(t, err)
+	assert.Equal(t, "done\n", res)
+
+	// Results cannot have parameters.
+	files[0].Body = `package main
+
+func main() {}
+
+func Results(n int) int { return n }`
+	_, err = env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+	assert.ErrorIs(t, err, InvalidExprError{})
+}
+
+func TestVMKeeperQueryObject(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Add the avl package, and the test realm.
+	avlDir := filepath.Join(gnoenv.RootDir(), "examples", "gno.land", "p", "demo", "avl")
+	var avlFiles []*std.MemFile
+	for _, name := range []string{"gno.mod", "node.gno", "tree.gno"} {
+		bz, err := os.ReadFile(filepath.Join(avlDir, name))
+		require.NoError(t, err)
+		avlFiles = append(avlFiles, &std.MemFile{Name: name, Body: string(bz)})
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, "gno.land/p/demo/avl", avlFiles))
+	require.NoError(t, err)
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+import "gno.land/p/demo/avl"
+
+type user struct {
+	Name  string
+	Score int
+}
+
+var (
+	count = 3
+	admin = &user{Name: "admin", Score: 10}
+	names = []string{"a", "b", "c"}
+	users avl.Tree
+)
+
+func init() {
+	for _, n := range names {
+		users.Set(n, &user{Name: n})
+	}
+}`},
+	}
+	err = env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	query := func(target string, offset, limit int) map[string]any {
+		t.Helper()
+		res, err := env.vmk.QueryObject(env.ctx, target, offset, limit)
+		require.NoError(t, err)
+		var v map[string]any
+		require.NoError(t, json.Unmarshal([]byte(res), &v))
+		return v
+	}
+	elem := func(v map[string]any, i int) map[string]any {
+		return v["elements"].([]any)[i].(map[string]any)
+	}
+
+	// The package-level variables.
+	pkg := query(pkgPath, 0, 2)
+	assert.Equal(t, "package", pkg["kind"])
+	assert.Equal(t, "4", fmt.Sprint(pkg["total"]))
+	require.Len(t, pkg["elements"], 2)
+	assert.Equal(t, "count", elem(pkg, 0)["name"])
+	assert.Equal(t, "3", elem(pkg, 0)["value"])
+	admin := elem(pkg, 1)
+	assert.Equal(t, "*gno.land/r/test.user", admin["type"])
+	fields := admin["elem"].(map[string]any)["fields"].([]any)
+	assert.Equal(t, "admin", fields[0].(map[string]any)["value"])
+
+	// An object, given its ObjectID.
+	obj := query(admin["objectid"].(string), 0, 10)
+	assert.Equal(t, "heapitem", obj["kind"])
+	assert.Equal(t, "gno.land/r/test.user", elem(obj, 0)["type"])
+
+	// Paginated elements of a slice.
+	names := query(pkgPath+".names", 1, 10)
+	assert.Equal(t, "3", fmt.Sprint(names["total"]))
+	require.Len(t, names["elements"], 2)
+	assert.Equal(t, "b", elem(names, 0)["value"])
+
+	// Paginated entries of an AVL tree.
+	users := query(pkgPath+".users", 1, 1)
+	assert.Equal(t, "3", fmt.Sprint(users["total"]))
+	require.Len(t, users["elements"], 1)
+	assert.Equal(t, "b", elem(users, 0)["key"].(map[string]any)["value"])
+	assert.Equal(t, "*gno.land/r/test.user", elem(users, 0)["type"])
+
+	_, err = env.vmk.QueryObject(env.ctx, pkgPath+".missing", 0, 10)
+	assert.ErrorContains(t, err, "variable not found")
+	_, err = env.vmk.QueryObject(env.ctx, "0000000000000000000000000000000000000000:1", 0, 10)
+	assert.ErrorContains(t, err, "object not found")
+}
+
+func TestVMKeeperUpgradePackage(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// Create test package.
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+type Item struct {
+	Name string
+}
+
+func (it *Item) Label() string { return "item " + it.Name }
+
+var (
+	items []*Item
+	count int
+)
+
+func Add(cur realm, name string) {
+	items = append(items, &Item{Name: name})
+	count++
+}
+
+func Labels(cur realm) string {
+	s := ""
+	for _, it := range items {
+		s += it.Label() + ";"
+	}
+	return s
+}`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{"a"}))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	upgrade := func(caller crypto.Address, body string) error {
+		ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+		files := []*std.MemFile{
+			{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+			{Name: "test.gno", Body: body},
+		}
+		err := env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(caller, pkgPath, files))
+		if err == nil {
+			env.vmk.CommitGnoTransactionStore(ctx)
+		}
+		return err
+	}
+
+	// Only the creator may upgrade the package.
+	other := crypto.AddressFromPreimage([]byte("addr2"))
+	err = upgrade(other, files[1].Body)
+	assert.True(t, errors.Is(err, UnauthorizedUserError{}))
+
+	// The underlying type of stored types may not change.
+	err = upgrade(addr, strings.Replace(files[1].Body, "Name string", "Name, Desc string", 1))
+	assert.ErrorContains(t, err, "cannot change the underlying type of stored type gno.land/r/test.Item")
+
+	// Nor the type of variables.
+	err = upgrade(addr, strings.Replace(files[1].Body, "count int", "count int64", 1))
+	assert.ErrorContains(t, err, "cannot change the type of variable count")
+
+	// The state is kept, methods are replaced, and Migrate is called.
+	err = upgrade(addr, `package test
+
+type Item struct {
+	Name string
+}
+
+func (it *Item) Label() string { return "ITEM " + it.Name }
+
+var (
+	items    []*Item
+	count    int
+	migrated = "no"
+)
+
+func Migrate(cur realm) {
+	migrated = "yes"
+	count *= 10
+}
+
+func Add(cur realm, name string) {
+	items = append(items, &Item{Name: name})
+	count++
+}
+
+func Labels(cur realm) string {
+	s := ""
+	for _, it := range items {
+		s += it.Label() + ";"
+	}
+	return s
+}
+
+func Status(cur realm) string {
+	return migrated + " " + itoa(count)
+}
+
+func itoa(n int) string {
+	if n < 10 {
+		return string(rune('0' + n))
+	}
+	return itoa(n/10) + itoa(n%10)
+}`)
+	require.NoError(t, err)
+
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{"b"}))
+	require.NoError(t, err)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Labels", nil))
+	require.NoError(t, err)
+	assert.Equal(t, `("ITEM a;ITEM b;" string)`+"\n\n", res)
+	res, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Status", nil))
+	require.NoError(t, err)
+	assert.Equal(t, `("yes 11" string)`+"\n\n", res)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// The upgraded package is loaded after a restart.
+	env.vmk.gnoStore = nil
+	mcw := env.ctx.MultiStore().MultiCacheWrap()
+	env.vmk.Initialize(log.NewNoopLogger(), mcw)
+	mcw.MultiWrite()
+
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	res, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Labels", nil))
+	require.NoError(t, err)
+	assert.Equal(t, `("ITEM a;ITEM b;" string)`+"\n\n", res)
+}
+
+func TestVMKeeperUpgradePackageDecls(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// A realm counting the calls made to it.
+	const counterPath = "gno.land/r/counter"
+	files := []*std.MemFile{
+		{Name: "counter.gno", Body: `package counter
+
+var count int
+
+func Inc(cur realm) int {
+	count++
+	return count
+}
+
+func Get() int { return count }`},
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(counterPath)},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, counterPath, files)))
+
+	const pkgPath = "gno.land/r/test"
+	files = []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+import "gno.land/r/counter"
+
+type Box[T any] struct {
+	V T
+}
+
+var (
+	id    = counter.Inc(cross)
+	boxes = map[string]Box[int]{"a": {V: 1}}
+)
+
+func Get(cur realm) int { return id*10 + boxes["a"].V }`},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	upgrade := func(body string) error {
+		ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+		files := []*std.MemFile{
+			{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+			{Name: "test.gno", Body: body},
+		}
+		err := env.vmk.UpgradePackage(ctx, NewMsgUpgradePackage(addr, pkgPath, files))
+		if err == nil {
+			env.vmk.CommitGnoTransactionStore(ctx)
+		}
+		return err
+	}
+	counterCalls := func() string {
+		res, err := env.vmk.QueryEval(env.ctx, counterPath, "Get()")
+		require.NoError(t, err)
+		return res
+	}
+
+	// The struct types used by the variables may not change, even through
+	// composite and generic types.
+	err := upgrade(strings.Replace(files[1].Body, "V T\n", "V, W T\n", 1))
+	assert.ErrorContains(t, err, "cannot change the type of variable boxes")
+	assert.Equal(t, "(1 int)", counterCalls())
+
+	// The initializers of the kept variables are not run again, unlike those
+	// of the new ones.
+	err = upgrade(files[1].Body + `
+
+var id2 = counter.Inc(cross)
+
+func Get2(cur realm) int { return id2 }`)
+	require.NoError(t, err)
+	assert.Equal(t, "(2 int)", counterCalls())
+
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Get", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "(11 int)\n\n", res)
+	res, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Get2", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "(2 int)\n\n", res)
+}
+
 func Test_loadStdlibPackage(t *testing.T) {
 	mdb := memdb.NewMemDB()
 	cs := dbadapter.StoreConstructor(mdb, types.StoreOptions{})
@@ -633,3 +1261,84 @@ func Test_loadStdlibPackage(t *testing.T) {
 		loadStdlibPackage("emptystdlib", "./testdata", gs)
 	})
 }
+
+func TestVMKeeperStorageDeposit(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+var items []string
+
+func Add(cur realm, item string) {
+	items = append(items, item)
+}
+
+func Clear(cur realm) {
+	items = nil
+}`},
+	}
+	depositAddr := StorageDepositAddr(pkgPath)
+	balance := func(addr crypto.Address) int64 {
+		return env.bankk.GetCoins(ctx, addr).AmountOf(ugnot.Denom)
+	}
+	storage := func() (storage, deposit int64) {
+		res, err := env.vmk.QueryStorage(ctx, pkgPath)
+		require.NoError(t, err)
+		var denom string
+		_, err = fmt.Sscanf(res, "storage: %d, deposit: %d%s", &storage, &deposit, &denom)
+		require.NoError(t, err)
+		require.Equal(t, ugnot.Denom, denom)
+		return storage, deposit
+	}
+
+	// Adding the package locks a deposit for its state.
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	initStorage, initDeposit := storage()
+	assert.Positive(t, initStorage)
+	assert.Equal(t, initStorage*100, initDeposit) // storagePriceDefault
+	assert.Equal(t, initDeposit, balance(depositAddr))
+	assert.Equal(t, 10_000_000-initDeposit, balance(addr))
+
+	// Growing the state locks more.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+	require.NoError(t, err)
+	grownStorage, grownDeposit := storage()
+	assert.Greater(t, grownStorage, initStorage+1000)
+	assert.Equal(t, grownStorage*100, grownDeposit)
+	assert.Equal(t, 10_000_000-grownDeposit, balance(addr))
+
+	// Shrinking the state refunds the caller, in proportion of the deposit
+	// for the released bytes, whatever the price is now.
+	env.vmk.CommitGnoTransactionStore(ctx)
+	require.NoError(t, env.vmk.SetParams(ctx, NewParams(sysNamesPkgDefault, chainDomainDefault, "1ugnot", scheduledCallsGasDefault, invariantCheckPeriodDefault, invariantHaltDefault, nil, nil)))
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	other := crypto.AddressFromPreimage([]byte("addr2"))
+	env.acck.SetAccount(ctx, env.acck.NewAccountWithAddress(ctx, other))
+	env.bankk.SetCoins(ctx, other, std.MustParseCoins(ugnot.ValueString(1000)))
+	_, err = env.vmk.Call(ctx, NewMsgCall(other, nil, pkgPath, "Clear", nil))
+	require.NoError(t, err)
+	clearedStorage, clearedDeposit := storage()
+	assert.Less(t, clearedStorage, grownStorage-1000)
+	assert.Equal(t, clearedStorage*100, clearedDeposit)
+	assert.Equal(t, 1000+grownDeposit-clearedDeposit, balance(other))
+	assert.Equal(t, clearedDeposit, balance(depositAddr))
+
+	// Growing the state fails if the caller can't pay for it.
+	env.bankk.SetCoins(ctx, other, std.MustParseCoins(ugnot.ValueString(1000)))
+	_, err = env.vmk.Call(ctx, NewMsgCall(other, nil, pkgPath, "Add", []string{strings.Repeat("x", 1000)}))
+	assert.True(t, errors.Is(err, std.InsufficientCoinsError{}))
+
+	_, err = env.vmk.QueryStorage(ctx, "gno.land/r/missing")
+	assert.Error(t, err)
+}
@@ -78,6 +78,56 @@ func (msg MsgAddPackage) GetReceived() std.Coins {
 	return msg.Deposit
 }
 
+//----------------------------------------
+// MsgUpgradePackage
+
+// MsgUpgradePackage - replace the code of a realm, keeping its state
+type MsgUpgradePackage struct {
+	Caller  crypto.Address  `json:"caller" yaml:"caller"`
+	Package *std.MemPackage `json:"package" yaml:"package"`
+}
+
+var _ std.Msg = MsgUpgradePackage{}
+
+// NewMsgUpgradePackage - upload the new files of a realm.
+func NewMsgUpgradePackage(caller crypto.Address, pkgPath string, files []*std.MemFile) MsgUpgradePackage {
+	msg := NewMsgAddPackage(caller, pkgPath, files)
+	return MsgUpgradePackage{
+		Caller:  caller,
+		Package: msg.Package,
+	}
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Route() string { return RouterKey }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) Type() string { return "upgrade_package" }
+
+// Implements Msg.
+func (msg MsgUpgradePackage) ValidateBasic() error {
+	if msg.Caller.IsZero() {
+		return std.ErrInvalidAddress("missing caller address")
+	}
+	if msg.Package == nil || msg.Package.Path == "" {
+		return ErrInvalidPkgPath("missing package path")
+	}
+	if !gno.IsRealmPath(msg.Package.Path) {
+		return ErrInvalidPkgPath("pkgpath must be of a realm")
+	}
+	return nil
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// Implements Msg.
+func (msg MsgUpgradePackage) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Caller}
+}
+
 //----------------------------------------
 // MsgCall
 
@@ -143,6 +193,12 @@ func (msg MsgCall) GetReceived() std.Coins {
 	return msg.Send
 }
 
+// Implements auth.TargetedMsg, so that fee grants can be restricted to the
+// calls to a realm.
+func (msg MsgCall) Target() string {
+	return msg.PkgPath
+}
+
 //----------------------------------------
 // MsgRun
 
@@ -0,0 +1,417 @@
+package vm
+
+import (
+	"encoding/base64"
+	"math"
+	"strconv"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+// Public facing views of the persisted state of realms, returned by the
+// vm/qobject query.
+
+// ObjectJSON is the view of an object, or of the package block of a realm.
+type ObjectJSON struct {
+	ObjectID  string       `json:"objectid"`
+	OwnerID   string       `json:"ownerid,omitempty"`
+	RefCount  int          `json:"refcount"`
+	IsEscaped bool         `json:"escaped,omitempty"`
+	Kind      string       `json:"kind"`
+	PkgPath   string       `json:"pkgpath,omitempty"` // kind "package"
+	Name      string       `json:"name,omitempty"`    // kind "func"
+	Total     int          `json:"total"`             // number of elements
+	Elements  []*ValueJSON `json:"elements"`          // paginated
+}
+
+func (oj *ObjectJSON) JSON() string {
+	return string(amino.MustMarshalJSON(oj))
+}
+
+// ValueJSON is the view of a value. Primitive values are given as strings,
+// and byte arrays and slices in base64. Structs are expanded with their
+// fields, and pointers with the value they point to, up to a limited depth;
+// the other values only refer to their object.
+type ValueJSON struct {
+	Name     string       `json:"name,omitempty"`
+	Key      *ValueJSON   `json:"key,omitempty"` // map and AVL tree entries
+	Type     string       `json:"type"`
+	Value    string       `json:"value,omitempty"`
+	ObjectID string       `json:"objectid,omitempty"`
+	Index    int          `json:"index,omitempty"` // pointers and slices
+	Length   int          `json:"length,omitempty"`
+	Fields   []*ValueJSON `json:"fields,omitempty"`
+	Elem     *ValueJSON   `json:"elem,omitempty"`
+	Total    int          `json:"total,omitempty"`    // number of elements, if paginated
+	Elements []*ValueJSON `json:"elements,omitempty"` // paginated
+}
+
+func (vj *ValueJSON) JSON() string {
+	return string(amino.MustMarshalJSON(vj))
+}
+
+// depth up to which structs and pointers are expanded.
+const viewDepth = 3
+
+// objectViewer builds the views, loading objects from the store.
+type objectViewer struct {
+	store         gno.Store
+	offset, limit int
+}
+
+// packageView returns the view of the package-level variables of pn, stored
+// in the block pb.
+func (ov *objectViewer) packageView(pn *gno.PackageNode, pb *gno.Block) *ObjectJSON {
+	oj := ov.objectInfo(pb, "package")
+	oj.PkgPath = pn.PkgPath
+	var names []gno.Name
+	for _, fn := range pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			if vd, ok := d.(*gno.ValueDecl); ok && !vd.Const {
+				for _, nx := range vd.NameExprs {
+					if nx.Name != "_" {
+						names = append(names, nx.Name)
+					}
+				}
+			}
+		}
+	}
+	oj.Total = len(names)
+	oj.Elements = []*ValueJSON{}
+	for i := ov.offset; i < len(names) && i < ov.offset+ov.limit; i++ {
+		idx, _ := pn.GetLocalIndex(names[i])
+		vj := ov.valueView(varValue(pb, idx), viewDepth)
+		vj.Name = string(names[i])
+		oj.Elements = append(oj.Elements, vj)
+	}
+	return oj
+}
+
+// objectView returns the view of the object oo, whose elements are not
+// typed as a whole.
+func (ov *objectViewer) objectView(oo gno.Object) *ObjectJSON {
+	var oj *ObjectJSON
+	var tvs []gno.TypedValue
+	var names []gno.Name
+	switch cv := oo.(type) {
+	case *gno.ArrayValue:
+		oj = ov.objectInfo(cv, "array")
+		if cv.Data != nil {
+			oj.Total = 1
+			oj.Elements = []*ValueJSON{{Type: "[]uint8", Value: base64.StdEncoding.EncodeToString(cv.Data)}}
+			return oj
+		}
+		tvs = cv.List
+	case *gno.StructValue:
+		oj = ov.objectInfo(cv, "struct")
+		tvs = cv.Fields
+	case *gno.MapValue:
+		oj = ov.objectInfo(cv, "map")
+		oj.Total = cv.List.Size
+		oj.Elements = ov.mapElements(cv, viewDepth)
+		return oj
+	case *gno.Block:
+		oj = ov.objectInfo(cv, "block")
+		tvs = cv.Values
+		if bn := cv.GetSource(ov.store); bn != nil {
+			names = bn.GetBlockNames()
+		}
+	case *gno.HeapItemValue:
+		oj = ov.objectInfo(cv, "heapitem")
+		tvs = []gno.TypedValue{cv.Value}
+	case *gno.FuncValue:
+		oj = ov.objectInfo(cv, "func")
+		oj.Name = string(cv.Name)
+	case *gno.BoundMethodValue:
+		oj = ov.objectInfo(cv, "boundmethod")
+		oj.Name = string(cv.Func.Name)
+		tvs = []gno.TypedValue{cv.Receiver}
+	case *gno.PackageValue:
+		oj = ov.objectInfo(cv, "package")
+		oj.PkgPath = cv.PkgPath
+	default:
+		panic("unexpected object type")
+	}
+	oj.Total = len(tvs)
+	oj.Elements = []*ValueJSON{}
+	for i := ov.offset; i < len(tvs) && i < ov.offset+ov.limit; i++ {
+		vj := ov.valueView(&tvs[i], viewDepth)
+		if i < len(names) {
+			vj.Name = string(names[i])
+		}
+		oj.Elements = append(oj.Elements, vj)
+	}
+	return oj
+}
+
+func (ov *objectViewer) objectInfo(oo gno.Object, kind string) *ObjectJSON {
+	oj := &ObjectJSON{
+		ObjectID:  oo.GetObjectID().String(),
+		RefCount:  oo.GetRefCount(),
+		IsEscaped: oo.GetIsEscaped(),
+		Kind:      kind,
+	}
+	if oid := oo.GetOwnerID(); !oid.IsZero() {
+		oj.OwnerID = oid.String()
+	}
+	return oj
+}
+
+// varView returns the view of the value tv of a package-level variable,
+// expanded with its paginated elements if it is an array, a slice, a map or
+// an AVL tree.
+func (ov *objectViewer) varView(tv *gno.TypedValue) *ValueJSON {
+	vj := ov.valueView(tv, viewDepth)
+	if tv.V == nil {
+		return vj
+	}
+	switch cv := tv.V.(type) {
+	case *gno.ArrayValue:
+		if cv.Data == nil {
+			vj.Total = len(cv.List)
+			vj.Elements = ov.listElements(cv.List, viewDepth)
+		}
+	case *gno.SliceValue:
+		if av := ov.fill(&gno.TypedValue{V: cv.Base}).(*gno.ArrayValue); av.Data == nil {
+			vj.Total = cv.Length
+			vj.Elements = ov.listElements(av.List[cv.Offset:cv.Offset+cv.Length], viewDepth)
+		}
+	case *gno.MapValue:
+		vj.Total = cv.List.Size
+		vj.Elements = ov.mapElements(cv, viewDepth)
+	default:
+		if root, ok := ov.avlRoot(tv); ok {
+			vj.Total = 0
+			if root.V != nil {
+				vj.Total = int(ov.deref(root).(*gno.StructValue).Fields[3].GetInt())
+			}
+			offset := ov.offset
+			vj.Elements = []*ValueJSON{}
+			ov.avlElements(root, &offset, &vj.Elements)
+		}
+	}
+	return vj
+}
+
+func (ov *objectViewer) listElements(tvs []gno.TypedValue, depth int) []*ValueJSON {
+	vjs := []*ValueJSON{}
+	for i := ov.offset; i < len(tvs) && i < ov.offset+ov.limit; i++ {
+		vjs = append(vjs, ov.valueView(&tvs[i], depth))
+	}
+	return vjs
+}
+
+func (ov *objectViewer) mapElements(mv *gno.MapValue, depth int) []*ValueJSON {
+	vjs := []*ValueJSON{}
+	i := 0
+	for item := mv.List.Head; item != nil && i < ov.offset+ov.limit; item = item.Next {
+		if i >= ov.offset {
+			vj := ov.valueView(&item.Value, depth)
+			vj.Key = ov.valueView(&item.Key, depth)
+			vjs = append(vjs, vj)
+		}
+		i++
+	}
+	return vjs
+}
+
+// avlRoot returns the root node of tv, if it is an AVL tree, or a pointer
+// to one, as declared in gno.land/p/demo/avl.
+func (ov *objectViewer) avlRoot(tv *gno.TypedValue) (root gno.TypedValue, ok bool) {
+	t := tv.T
+	if pt, ok := gno.BaseOf(t).(*gno.PointerType); ok {
+		if tv.V == nil {
+			return root, false
+		}
+		t, tv = pt.Elt, ov.derefTV(*tv)
+	}
+	if dt, ok := t.(*gno.DeclaredType); !ok || dt.Name != "Tree" {
+		return root, false
+	}
+	st, ok := gno.BaseOf(t).(*gno.StructType)
+	if !ok || len(st.Fields) != 1 || st.Fields[0].Name != "node" {
+		return root, false
+	}
+	pt, ok := st.Fields[0].Type.(*gno.PointerType)
+	if !ok {
+		return root, false
+	}
+	nt, ok := gno.BaseOf(pt.Elt).(*gno.StructType)
+	if !ok || len(nt.Fields) != 6 {
+		return root, false
+	}
+	for i, name := range []gno.Name{"key", "value", "height", "size", "leftNode", "rightNode"} {
+		if nt.Fields[i].Name != name {
+			return root, false
+		}
+	}
+	sv := ov.fill(tv).(*gno.StructValue)
+	return sv.Fields[0], true
+}
+
+// avlElements appends the leaves of the AVL tree node in order, skipping the
+// first *offset ones.
+func (ov *objectViewer) avlElements(node gno.TypedValue, offset *int, vjs *[]*ValueJSON) {
+	if node.V == nil || len(*vjs) >= ov.limit {
+		return
+	}
+	sv := ov.deref(node).(*gno.StructValue)
+	if size := int(sv.Fields[3].GetInt()); *offset >= size {
+		*offset -= size
+		return
+	}
+	if sv.Fields[2].GetInt8() == 0 { // leaf
+		vj := ov.valueView(&sv.Fields[1], viewDepth)
+		vj.Key = ov.valueView(&sv.Fields[0], viewDepth)
+		*vjs = append(*vjs, vj)
+		return
+	}
+	ov.avlElements(sv.Fields[4], offset, vjs)
+	ov.avlElements(sv.Fields[5], offset, vjs)
+}
+
+// valueView returns the view of tv, expanding structs and pointers up to
+// depth.
+func (ov *objectViewer) valueView(tv *gno.TypedValue, depth int) *ValueJSON {
+	vj := &ValueJSON{}
+	if tv.T == nil {
+		vj.Type = "nil"
+		return vj
+	}
+	vj.Type = tv.T.String()
+	if tv.V == nil && !isPrimitive(tv.T) {
+		return vj // nil value
+	}
+	switch bt := gno.BaseOf(tv.T).(type) {
+	case gno.PrimitiveType:
+		vj.Value = primitiveString(tv)
+	case *gno.ArrayType:
+		av := ov.fill(tv).(*gno.ArrayValue)
+		vj.ObjectID = objectIDString(av)
+		vj.Length = av.GetLength()
+		if av.Data != nil {
+			vj.Value = base64.StdEncoding.EncodeToString(av.Data)
+		}
+	case *gno.SliceType:
+		sv := tv.V.(*gno.SliceValue)
+		av := ov.fill(&gno.TypedValue{V: sv.Base}).(*gno.ArrayValue)
+		sv.Base = av
+		vj.ObjectID = objectIDString(av)
+		vj.Index = sv.Offset
+		vj.Length = sv.Length
+		if av.Data != nil {
+			vj.Value = base64.StdEncoding.EncodeToString(av.Data[sv.Offset : sv.Offset+sv.Length])
+		}
+	case *gno.StructType:
+		sv := ov.fill(tv).(*gno.StructValue)
+		vj.ObjectID = objectIDString(sv)
+		if depth > 0 {
+			for i, f := range bt.Fields {
+				fj := ov.valueView(&sv.Fields[i], depth-1)
+				fj.Name = string(f.Name)
+				vj.Fields = append(vj.Fields, fj)
+			}
+		}
+	case *gno.MapType:
+		mv := ov.fill(tv).(*gno.MapValue)
+		vj.ObjectID = objectIDString(mv)
+		vj.Length = mv.GetLength()
+	case *gno.PointerType:
+		pv := ov.fill(tv).(gno.PointerValue)
+		if oo, ok := pv.Base.(gno.Object); ok {
+			vj.ObjectID = objectIDString(oo)
+		}
+		vj.Index = pv.Index
+		if depth > 0 && pv.TV != nil {
+			vj.Elem = ov.valueView(pv.TV, depth-1)
+		}
+	case *gno.FuncType:
+		switch fv := ov.fill(tv).(type) {
+		case *gno.FuncValue:
+			vj.ObjectID = objectIDString(fv)
+			vj.Value = string(fv.Name)
+		case *gno.BoundMethodValue:
+			vj.ObjectID = objectIDString(fv)
+			vj.Value = string(fv.Func.Name)
+		}
+	case *gno.PackageType:
+		if pv, ok := ov.fill(tv).(*gno.PackageValue); ok {
+			vj.Value = pv.PkgPath
+		}
+	default:
+		if oo, ok := ov.fill(tv).(gno.Object); ok {
+			vj.ObjectID = objectIDString(oo)
+		}
+	}
+	return vj
+}
+
+// fill loads the object of tv if needed, and returns its value.
+func (ov *objectViewer) fill(tv *gno.TypedValue) gno.Value {
+	return gno.FillValueTV(ov.store, tv).V
+}
+
+func (ov *objectViewer) deref(ptr gno.TypedValue) gno.Value {
+	return ov.fill(ov.derefTV(ptr))
+}
+
+func (ov *objectViewer) derefTV(ptr gno.TypedValue) *gno.TypedValue {
+	return ov.fill(&ptr).(gno.PointerValue).TV
+}
+
+// varValue returns the value of the variable at index idx in the block b.
+func varValue(b *gno.Block, idx uint16) *gno.TypedValue {
+	tv := &b.Values[idx]
+	if hiv, ok := tv.V.(*gno.HeapItemValue); ok {
+		return &hiv.Value
+	}
+	return tv
+}
+
+func objectIDString(oo gno.Object) string {
+	if oid := oo.GetObjectID(); !oid.IsZero() {
+		return oid.String()
+	}
+	return ""
+}
+
+func isPrimitive(t gno.Type) bool {
+	_, ok := gno.BaseOf(t).(gno.PrimitiveType)
+	return ok
+}
+
+func primitiveString(tv *gno.TypedValue) string {
+	switch tv.T.Kind() {
+	case gno.BoolKind:
+		return strconv.FormatBool(tv.GetBool())
+	case gno.StringKind:
+		return tv.GetString()
+	case gno.IntKind:
+		return strconv.FormatInt(tv.GetInt(), 10)
+	case gno.Int8Kind:
+		return strconv.FormatInt(int64(tv.GetInt8()), 10)
+	case gno.Int16Kind:
+		return strconv.FormatInt(int64(tv.GetInt16()), 10)
+	case gno.Int32Kind:
+		return strconv.FormatInt(int64(tv.GetInt32()), 10)
+	case gno.Int64Kind:
+		return strconv.FormatInt(tv.GetInt64(), 10)
+	case gno.UintKind:
+		return strconv.FormatUint(tv.GetUint(), 10)
+	case gno.Uint8Kind:
+		return strconv.FormatUint(uint64(tv.GetUint8()), 10)
+	case gno.Uint16Kind:
+		return strconv.FormatUint(uint64(tv.GetUint16()), 10)
+	case gno.Uint32Kind:
+		return strconv.FormatUint(uint64(tv.GetUint32()), 10)
+	case gno.Uint64Kind:
+		return strconv.FormatUint(tv.GetUint64(), 10)
+	case gno.Float32Kind:
+		return strconv.FormatFloat(float64(math.Float32frombits(tv.GetFloat32())), 'g', -1, 32)
+	case gno.Float64Kind:
+		return strconv.FormatFloat(math.Float64frombits(tv.GetFloat64()), 'g', -1, 64)
+	default:
+		return tv.String()
+	}
+}
@@ -15,6 +15,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	MsgCall{}, "m_call",
 	MsgRun{}, "m_run",
 	MsgAddPackage{}, "m_addpkg", // TODO rename both to MsgAddPkg?
+	MsgUpgradePackage{}, "m_upgradepkg",
 
 	// errors
 	InvalidPkgPathError{}, "InvalidPkgPathError",
@@ -24,4 +25,5 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	InvalidExprError{}, "InvalidExprError",
 	TypeCheckError{}, "TypeCheckError",
 	UnauthorizedUserError{}, "UnauthorizedUserError",
+	RealmPausedError{}, "RealmPausedError",
 ))
@@ -5,14 +5,22 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 const (
-	sysNamesPkgDefault = "gno.land/r/sys/names"
-	chainDomainDefault = "gno.land"
+	sysNamesPkgDefault  = "gno.land/r/sys/names"
+	chainDomainDefault  = "gno.land"
+	storagePriceDefault = "100ugnot" // per byte
+
+	scheduledCallsGasDefault int64 = 10_000_000 // per block
+
+	invariantCheckPeriodDefault int64 = 0 // disabled
+	invariantHaltDefault              = false
 )
 
 var ASCIIDomain = regexp.MustCompile(`^(?:[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)+[A-Za-z]{2,}$`)
@@ -21,19 +29,45 @@ var ASCIIDomain = regexp.MustCompile(`^(?:[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-
 type Params struct {
 	SysNamesPkgPath string `json:"sysnames_pkgpath" yaml:"sysnames_pkgpath"`
 	ChainDomain     string `json:"chain_domain" yaml:"chain_domain"`
+	// The deposit locked per byte of realm state; see processStorageDeposit.
+	StoragePrice string `json:"storage_price" yaml:"storage_price"`
+	// The gas budget of the scheduled calls executed in a block, which are
+	// disabled if it is zero; see RunScheduledCalls.
+	ScheduledCallsGas int64 `json:"scheduled_calls_gas" yaml:"scheduled_calls_gas"`
+	// The number of blocks between two checks of the invariants, which are
+	// not checked if it is zero; see CheckInvariants.
+	InvariantCheckPeriod int64 `json:"invariant_check_period" yaml:"invariant_check_period"`
+	// Whether the chain halts when an invariant is broken, instead of only
+	// emitting an event.
+	InvariantHalt bool `json:"invariant_halt" yaml:"invariant_halt"`
+	// The realms whose exported Invariants function is checked with the
+	// invariants of the modules; see RealmInvariants.
+	InvariantRealms []string `json:"invariant_realms" yaml:"invariant_realms"`
+	// The realms which reject the calls of MsgCall and MsgRun, in an
+	// emergency; see checkNotPaused.
+	PausedRealms []string `json:"paused_realms" yaml:"paused_realms"`
 }
 
 // NewParams creates a new Params object
-func NewParams(namesPkgPath, chainDomain string) Params {
+func NewParams(namesPkgPath, chainDomain, storagePrice string, scheduledCallsGas int64,
+	invariantCheckPeriod int64, invariantHalt bool, invariantRealms []string, pausedRealms []string,
+) Params {
 	return Params{
-		SysNamesPkgPath: namesPkgPath,
-		ChainDomain:     chainDomain,
+		SysNamesPkgPath:      namesPkgPath,
+		ChainDomain:          chainDomain,
+		StoragePrice:         storagePrice,
+		ScheduledCallsGas:    scheduledCallsGas,
+		InvariantCheckPeriod: invariantCheckPeriod,
+		InvariantHalt:        invariantHalt,
+		InvariantRealms:      invariantRealms,
+		PausedRealms:         pausedRealms,
 	}
 }
 
 // DefaultParams returns a default set of parameters.
 func DefaultParams() Params {
-	return NewParams(sysNamesPkgDefault, chainDomainDefault)
+	return NewParams(sysNamesPkgDefault, chainDomainDefault, storagePriceDefault, scheduledCallsGasDefault,
+		invariantCheckPeriodDefault, invariantHaltDefault, nil, nil)
 }
 
 // String implements the stringer interface.
@@ -42,6 +76,12 @@ func (p Params) String() string {
 	sb.WriteString("Params: \n")
 	sb.WriteString(fmt.Sprintf("SysUsersPkgPath: %q\n", p.SysNamesPkgPath))
 	sb.WriteString(fmt.Sprintf("ChainDomain: %q\n", p.ChainDomain))
+	sb.WriteString(fmt.Sprintf("StoragePrice: %q\n", p.StoragePrice))
+	sb.WriteString(fmt.Sprintf("ScheduledCallsGas: %d\n", p.ScheduledCallsGas))
+	sb.WriteString(fmt.Sprintf("InvariantCheckPeriod: %d\n", p.InvariantCheckPeriod))
+	sb.WriteString(fmt.Sprintf("InvariantHalt: %t\n", p.InvariantHalt))
+	sb.WriteString(fmt.Sprintf("InvariantRealms: %q\n", p.InvariantRealms))
+	sb.WriteString(fmt.Sprintf("PausedRealms: %q\n", p.PausedRealms))
 	return sb.String()
 }
 
@@ -52,9 +92,51 @@ func (p Params) Validate() error {
 	if p.ChainDomain != "" && !ASCIIDomain.MatchString(p.ChainDomain) {
 		return fmt.Errorf("invalid chain domain %q, failed to match %q", p.ChainDomain, ASCIIDomain)
 	}
+	if _, err := parseStoragePrice(p.StoragePrice); err != nil {
+		return err
+	}
+	if p.ScheduledCallsGas < 0 {
+		return fmt.Errorf("invalid scheduled calls gas %d, must not be negative", p.ScheduledCallsGas)
+	}
+	if p.InvariantCheckPeriod < 0 {
+		return fmt.Errorf("invalid invariant check period %d, must not be negative", p.InvariantCheckPeriod)
+	}
+	if err := validateRealmPaths("invariant", p.InvariantRealms); err != nil {
+		return err
+	}
+	if err := validateRealmPaths("paused", p.PausedRealms); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateRealmPaths checks that the realms of a param, of the given kind,
+// are realm paths.
+func validateRealmPaths(kind string, realms []string) error {
+	for _, realm := range realms {
+		if !gno.IsRealmPath(realm) {
+			return fmt.Errorf("invalid %s realm %q, must be a realm path", kind, realm)
+		}
+	}
 	return nil
 }
 
+// parseStoragePrice parses a storage price, which must be in ugnot. An empty
+// price is a zero price.
+func parseStoragePrice(price string) (std.Coin, error) {
+	if price == "" {
+		return std.NewCoin(ugnot.Denom, 0), nil
+	}
+	coin, err := std.ParseCoin(price)
+	if err != nil {
+		return std.Coin{}, fmt.Errorf("invalid storage price %q: %w", price, err)
+	}
+	if coin.Denom != ugnot.Denom || coin.IsNegative() {
+		return std.Coin{}, fmt.Errorf("invalid storage price %q, must be a non-negative amount of %s", price, ugnot.Denom)
+	}
+	return coin, nil
+}
+
 // Equals returns a boolean determining if two Params types are identical.
 func (p Params) Equals(p2 Params) bool {
 	return amino.DeepEqual(p, p2)
@@ -75,8 +157,17 @@ func (vm *VMKeeper) GetParams(ctx sdk.Context) Params {
 }
 
 const (
-	sysUsersPkgParamPath = "vm:p:sysnames_pkgpath"
-	chainDomainParamPath = "vm:p:chain_domain"
+	sysUsersPkgParamPath  = "vm:p:sysnames_pkgpath"
+	chainDomainParamPath  = "vm:p:chain_domain"
+	storagePriceParamPath = "vm:p:storage_price"
+
+	scheduledCallsGasParamPath = "vm:p:scheduled_calls_gas"
+
+	invariantCheckPeriodParamPath = "vm:p:invariant_check_period"
+	invariantHaltParamPath        = "vm:p:invariant_halt"
+	invariantRealmsParamPath      = "vm:p:invariant_realms"
+
+	pausedRealmsParamPath = "vm:p:paused_realms"
 )
 
 func (vm *VMKeeper) getChainDomainParam(ctx sdk.Context) string {
@@ -91,6 +182,71 @@ func (vm *VMKeeper) getSysNamesPkgParam(ctx sdk.Context) string {
 	return sysNamesPkg
 }
 
+func (vm *VMKeeper) getStoragePriceParam(ctx sdk.Context) std.Coin {
+	storagePrice := storagePriceDefault
+	vm.prmk.GetString(ctx, storagePriceParamPath, &storagePrice)
+	price, err := parseStoragePrice(storagePrice)
+	if err != nil {
+		panic(err) // see WillSetParam.
+	}
+	return price
+}
+
+func (vm *VMKeeper) getScheduledCallsGasParam(ctx sdk.Context) int64 {
+	scheduledCallsGas := scheduledCallsGasDefault
+	vm.prmk.GetInt64(ctx, scheduledCallsGasParamPath, &scheduledCallsGas)
+	return scheduledCallsGas
+}
+
+func (vm *VMKeeper) getInvariantCheckPeriodParam(ctx sdk.Context) int64 {
+	invariantCheckPeriod := invariantCheckPeriodDefault
+	vm.prmk.GetInt64(ctx, invariantCheckPeriodParamPath, &invariantCheckPeriod)
+	return invariantCheckPeriod
+}
+
+func (vm *VMKeeper) getInvariantHaltParam(ctx sdk.Context) bool {
+	invariantHalt := invariantHaltDefault
+	vm.prmk.GetBool(ctx, invariantHaltParamPath, &invariantHalt)
+	return invariantHalt
+}
+
+func (vm *VMKeeper) getInvariantRealmsParam(ctx sdk.Context) []string {
+	var invariantRealms []string
+	vm.prmk.GetStrings(ctx, invariantRealmsParamPath, &invariantRealms)
+	return invariantRealms
+}
+
+func (vm *VMKeeper) getPausedRealmsParam(ctx sdk.Context) []string {
+	var pausedRealms []string
+	vm.prmk.GetStrings(ctx, pausedRealmsParamPath, &pausedRealms)
+	return pausedRealms
+}
+
 func (vm *VMKeeper) WillSetParam(ctx sdk.Context, key string, value any) {
-	// XXX validate input?
+	switch key {
+	case "p:storage_price":
+		// An invalid price would make every transaction panic.
+		if _, err := parseStoragePrice(value.(string)); err != nil {
+			panic(err)
+		}
+	case "p:scheduled_calls_gas":
+		if value.(int64) < 0 {
+			panic(fmt.Sprintf("invalid scheduled calls gas %d, must not be negative", value))
+		}
+	case "p:invariant_check_period":
+		if value.(int64) < 0 {
+			panic(fmt.Sprintf("invalid invariant check period %d, must not be negative", value))
+		}
+	case "p:invariant_realms":
+		if err := validateRealmPaths("invariant", value.([]string)); err != nil {
+			panic(err)
+		}
+	case "p:paused_realms":
+		if err := validateRealmPaths("paused", value.([]string)); err != nil {
+			panic(err)
+		}
+		vm.emitPauseEvents(ctx, vm.getPausedRealmsParam(ctx), value.([]string))
+	default:
+		// XXX validate input?
+	}
 }
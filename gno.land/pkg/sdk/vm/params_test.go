@@ -10,15 +10,27 @@ import (
 // TestParamsString verifies the output of the String method.
 func TestParamsString(t *testing.T) {
 	p := Params{
-		SysNamesPkgPath: "gno.land/r/sys/names", // XXX what is this really for now
-		ChainDomain:     "example.com",
+		SysNamesPkgPath:      "gno.land/r/sys/names", // XXX what is this really for now
+		ChainDomain:          "example.com",
+		StoragePrice:         "100ugnot",
+		ScheduledCallsGas:    1_000_000,
+		InvariantCheckPeriod: 100,
+		InvariantHalt:        true,
+		InvariantRealms:      []string{"gno.land/r/demo/foo"},
+		PausedRealms:         []string{"gno.land/r/demo/bar"},
 	}
 	result := p.String()
 
 	// Construct the expected string.
 	expected := "Params: \n" +
 		fmt.Sprintf("SysUsersPkgPath: %q\n", p.SysNamesPkgPath) +
-		fmt.Sprintf("ChainDomain: %q\n", p.ChainDomain)
+		fmt.Sprintf("ChainDomain: %q\n", p.ChainDomain) +
+		fmt.Sprintf("StoragePrice: %q\n", p.StoragePrice) +
+		fmt.Sprintf("ScheduledCallsGas: %d\n", p.ScheduledCallsGas) +
+		fmt.Sprintf("InvariantCheckPeriod: %d\n", p.InvariantCheckPeriod) +
+		fmt.Sprintf("InvariantHalt: %t\n", p.InvariantHalt) +
+		fmt.Sprintf("InvariantRealms: %q\n", p.InvariantRealms) +
+		fmt.Sprintf("PausedRealms: %q\n", p.PausedRealms)
 
 	// Assert: check if the result matches the expected string.
 	if result != expected {
@@ -64,6 +76,25 @@ func TestWillSetParam(t *testing.T) {
 			isUpdated:   true,
 			isEqual:     true,
 		},
+		{
+			name:  "update storage_price",
+			key:   "storage_price",
+			value: "50ugnot",
+			getExpectedValue: func(prms Params) string {
+				return prms.StoragePrice
+			},
+			shouldPanic: false,
+			isUpdated:   true,
+			isEqual:     true,
+		},
+		{
+			name:        "invalid storage_price panics",
+			key:         "storage_price",
+			value:       "50foo",
+			shouldPanic: true,
+			isUpdated:   false,
+			isEqual:     false, // Not applicable
+		},
 		/* unknown parameter keys are OK
 		{
 			name:             "unknown parameter key panics",
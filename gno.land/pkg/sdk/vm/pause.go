@@ -0,0 +1,90 @@
+package vm
+
+import (
+	"slices"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/packages"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+const (
+	// Event types emitted when the paused_realms param pauses, or unpauses,
+	// a realm.
+	EventRealmPaused   = "RealmPaused"
+	EventRealmUnpaused = "RealmUnpaused"
+)
+
+// checkNotPaused returns an error if the realm at pkgPath is paused by the
+// paused_realms param. Paused realms reject the calls of MsgCall and MsgRun,
+// but can still be queried. The calls reaching them through other realms are
+// rejected by pausedRealmsHook.
+func (vm *VMKeeper) checkNotPaused(ctx sdk.Context, pkgPath string) error {
+	if slices.Contains(vm.getPausedRealmsParam(ctx), pkgPath) {
+		return ErrRealmPaused(pkgPath)
+	}
+	return nil
+}
+
+// pausedRealmsHook returns the machine hook which aborts the execution when
+// it crosses into a realm paused by the paused_realms param, e.g. through an
+// unpaused realm which imports it. It returns nil if no realm is paused.
+func (vm *VMKeeper) pausedRealmsHook(ctx sdk.Context) func(rlm *gno.Realm) {
+	paused := vm.getPausedRealmsParam(ctx)
+	if len(paused) == 0 {
+		return nil
+	}
+	return func(rlm *gno.Realm) {
+		if slices.Contains(paused, rlm.Path) {
+			panic(ErrRealmPaused(rlm.Path))
+		}
+	}
+}
+
+// checkImportsNotPaused returns an error if the package imports a realm which
+// is paused by the paused_realms param.
+func (vm *VMKeeper) checkImportsNotPaused(ctx sdk.Context, memPkg *std.MemPackage) error {
+	paused := vm.getPausedRealmsParam(ctx)
+	if len(paused) == 0 {
+		return nil
+	}
+	imports, err := packages.Imports(memPkg, nil)
+	if err != nil {
+		return ErrInvalidPkgPath(err.Error())
+	}
+	for _, imp := range imports[packages.FileKindPackageSource] {
+		if slices.Contains(paused, imp.PkgPath) {
+			return ErrRealmPaused(imp.PkgPath)
+		}
+	}
+	return nil
+}
+
+// emitPauseEvents emits an event for every realm paused, or unpaused, by the
+// change of the paused_realms param from old to new.
+func (vm *VMKeeper) emitPauseEvents(ctx sdk.Context, old, new []string) {
+	evl := ctx.EventLogger()
+	if evl == nil { // e.g. genesis.
+		return
+	}
+	for _, pkgPath := range new {
+		if !slices.Contains(old, pkgPath) {
+			evl.EmitEvent(pauseEvent(EventRealmPaused, pkgPath))
+		}
+	}
+	for _, pkgPath := range old {
+		if !slices.Contains(new, pkgPath) {
+			evl.EmitEvent(pauseEvent(EventRealmUnpaused, pkgPath))
+		}
+	}
+}
+
+func pauseEvent(typ, pkgPath string) gnostd.GnoEvent {
+	return gnostd.GnoEvent{
+		Type:       typ,
+		Attributes: []gnostd.GnoEventAttribute{},
+		PkgPath:    pkgPath,
+	}
+}
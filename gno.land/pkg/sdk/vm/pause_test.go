@@ -0,0 +1,155 @@
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestVMKeeperPausedRealms(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	const pkgPath = "gno.land/r/counter"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package counter
+
+var counter int
+
+func Inc(cur realm) int {
+	counter++
+	return counter
+}
+
+func Render(_ string) string { return "counter" }`},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+
+	// An unpaused realm, which calls the counter.
+	const proxyPath = "gno.land/r/proxy"
+	proxyFiles := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(proxyPath)},
+		{Name: "proxy.gno", Body: `package proxy
+
+import "gno.land/r/counter"
+
+func Inc(cur realm) int {
+	return counter.Inc(cross)
+}`},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, proxyPath, proxyFiles)))
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	call := func() error {
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+		return err
+	}
+	run := func() error {
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		files := []*std.MemFile{
+			{Name: "script.gno", Body: `package main
+
+import "gno.land/r/counter"
+
+func main() {
+	println(counter.Inc(cross))
+}`},
+		}
+		_, err := env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+		return err
+	}
+	callProxy := func() error {
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, proxyPath, "Inc", nil))
+		return err
+	}
+	runProxy := func() error {
+		ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+		files := []*std.MemFile{
+			{Name: "script.gno", Body: `package main
+
+import "gno.land/r/proxy"
+
+func main() {
+	println(proxy.Inc(cross))
+}`},
+		}
+		_, err := env.vmk.Run(ctx, NewMsgRun(addr, nil, files))
+		return err
+	}
+	setPaused := func(realms ...string) []string {
+		ctx := env.ctx.WithEventLogger(sdk.NewEventLogger())
+		env.prmk.SetStrings(ctx, pausedRealmsParamPath, realms)
+		var evts []string
+		for _, evt := range ctx.EventLogger().Events() {
+			gevt := evt.(gnostd.GnoEvent)
+			evts = append(evts, gevt.Type+" "+gevt.PkgPath)
+		}
+		return evts
+	}
+
+	require.NoError(t, call())
+	require.NoError(t, run())
+
+	// Paused realms reject calls, and runs which import them.
+	assert.Equal(t, []string{"RealmPaused gno.land/r/counter", "RealmPaused gno.land/r/other"},
+		setPaused(pkgPath, "gno.land/r/other"))
+	assert.ErrorIs(t, call(), RealmPausedError{})
+	assert.ErrorIs(t, run(), RealmPausedError{})
+
+	// Even through unpaused realms.
+	assert.ErrorIs(t, callProxy(), RealmPausedError{})
+	assert.ErrorIs(t, runProxy(), RealmPausedError{})
+
+	// And in scheduled calls, or calls of the chain.
+	_, err := env.vmk.scheduleCall(env.ctx, proxyPath, 10, 0, "Inc", nil)
+	require.NoError(t, err)
+	evs := env.vmk.RunScheduledCalls(env.ctx.WithBlockHeader(&bft.Header{
+		ChainID: "test-chain-id",
+		Height:  10,
+		Time:    time.Unix(1000, 0),
+	}))
+	require.Len(t, evs, 1)
+	failed := evs[0].(gnostd.GnoEvent)
+	assert.Equal(t, EventScheduledCallFailed, failed.Type)
+	assert.Contains(t, failed.Attributes[len(failed.Attributes)-1].Value, "realm is paused")
+	_, err = env.vmk.ChainCall(env.ctx, proxyPath, "Inc", nil)
+	assert.ErrorIs(t, err, RealmPausedError{})
+
+	// But they can still be queried.
+	res, err := env.vmk.QueryEvalString(env.ctx, pkgPath, `Render("")`)
+	require.NoError(t, err)
+	assert.Equal(t, "counter", res)
+	res, err = env.vmk.QueryEval(env.ctx, pkgPath, "counter")
+	require.NoError(t, err)
+	assert.Equal(t, "(2 int)", res)
+
+	// Unpaused realms accept calls again.
+	assert.Equal(t, []string{"RealmUnpaused gno.land/r/counter"}, setPaused("gno.land/r/other"))
+	require.NoError(t, call())
+	require.NoError(t, run())
+	require.NoError(t, callProxy())
+	require.NoError(t, runProxy())
+
+	// The paused realms must be realm paths.
+	assert.Panics(t, func() {
+		setPaused("gno.land/p/demo/avl")
+	})
+}
@@ -0,0 +1,144 @@
+package vm
+
+import (
+	"fmt"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+)
+
+// ProofOpObject is the type of the proof operations of ObjectProofOp.
+const ProofOpObject = "gno:object"
+
+// ObjectProofOp proves the persisted bytes of an object with the persisted
+// bytes of its owners, up to the first one whose hash is committed in the iavl
+// store: each owner references the previous object with its hash. Run returns
+// the hash of the last owner, which is then proven by an iavl proof operation.
+type ObjectProofOp struct {
+	// Encoded in ProofOp.Key, the ObjectID of the proven object.
+	key []byte
+
+	// To encode in ProofOp.Data.
+	Owners [][]byte `json:"owners"`
+}
+
+var _ merkle.ProofOperator = ObjectProofOp{}
+
+func NewObjectProofOp(oid gno.ObjectID, owners [][]byte) ObjectProofOp {
+	return ObjectProofOp{
+		key:    []byte(oid.String()),
+		Owners: owners,
+	}
+}
+
+// ObjectProofOpDecoder decodes an ObjectProofOp from a proof operation.
+func ObjectProofOpDecoder(pop merkle.ProofOp) (merkle.ProofOperator, error) {
+	if pop.Type != ProofOpObject {
+		return nil, errors.New("unexpected ProofOp.Type; got %v, want %v", pop.Type, ProofOpObject)
+	}
+	var op ObjectProofOp
+	err := amino.UnmarshalSized(pop.Data, &op)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding ProofOp.Data into ObjectProofOp")
+	}
+	op.key = pop.Key
+	return op, nil
+}
+
+func (op ObjectProofOp) ProofOp() merkle.ProofOp {
+	bz := amino.MustMarshalSized(op)
+	return merkle.ProofOp{
+		Type: ProofOpObject,
+		Key:  op.key,
+		Data: bz,
+	}
+}
+
+func (op ObjectProofOp) String() string {
+	return fmt.Sprintf("ObjectProofOp{%s}", op.key)
+}
+
+func (op ObjectProofOp) GetKey() []byte {
+	return op.key
+}
+
+// Run takes the persisted bytes of the object, and returns the hash of its
+// last owner.
+func (op ObjectProofOp) Run(args [][]byte) ([][]byte, error) {
+	if len(args) != 1 {
+		return nil, errors.New("Value size is not 1")
+	}
+	child, err := decodeObject(args[0])
+	if err != nil {
+		return nil, err
+	}
+	if oid := child.GetObjectID().String(); oid != string(op.key) {
+		return nil, errors.New("object id mismatch: expected %s but got %s", op.key, oid)
+	}
+	hash := gno.HashBytes(args[0])
+	for _, bz := range op.Owners {
+		owner, err := decodeObject(bz)
+		if err != nil {
+			return nil, err
+		}
+		if !hasChildRef(owner, child.GetObjectID(), hash) {
+			return nil, errors.New("object %s is not referenced by %s with hash %X",
+				child.GetObjectID(), owner.GetObjectID(), hash.Bytes())
+		}
+		child, hash = owner, gno.HashBytes(bz)
+	}
+	return [][]byte{hash.Bytes()}, nil
+}
+
+func decodeObject(bz []byte) (oo gno.Object, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.New("decoding object: %v", r)
+		}
+	}()
+	if err := amino.Unmarshal(bz, &oo); err != nil {
+		return nil, errors.Wrap(err, "decoding object")
+	}
+	if oo == nil {
+		return nil, errors.New("decoding object: nil object")
+	}
+	return oo, nil
+}
+
+func hasChildRef(owner gno.Object, oid gno.ObjectID, hash gno.Hashlet) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	for _, ref := range gno.GetChildRefs(owner) {
+		if ref.ObjectID == oid && ref.Hash.Hashlet == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// ProofRuntime returns the merkle proof runtime which verifies the proofs of
+// the vm queries, and of the store queries.
+func ProofRuntime() *merkle.ProofRuntime {
+	prt := rootmulti.DefaultProofRuntime()
+	prt.RegisterOpDecoder(ProofOpObject, ObjectProofOpDecoder)
+	return prt
+}
+
+// ProofKeyPath returns the key path of a proof, which lists the keys of its
+// operations from the root: for the proofs of the vm queries, the name of the
+// store, the key in the iavl store, and the ObjectID of an ObjectProofOp.
+func ProofKeyPath(proof *merkle.Proof) string {
+	var kp merkle.KeyPath
+	for i := len(proof.Ops) - 1; i >= 0; i-- {
+		if key := proof.Ops[i].Key; len(key) > 0 {
+			kp = kp.AppendKey(key, merkle.KeyEncodingURL)
+		}
+	}
+	return kp.String()
+}
@@ -0,0 +1,176 @@
+package vm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+func TestVMKeeperProve(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+type user struct {
+	Name    string
+	Balance int
+}
+
+var (
+	admin = &user{Name: "admin", Balance: 10}
+	users = []*user{admin, {Name: "bob", Balance: 20}}
+)`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// Commit, and prove the queries at the committed height.
+	cms := env.ctx.MultiStore().(store.CommitMultiStore)
+	cid := cms.Commit()
+	queryable := cms.(store.Queryable)
+	prover := sdk.StoreProver(func(storeKey store.StoreKey, key []byte) ([]byte, *merkle.Proof, error) {
+		res := queryable.Query(abci.RequestQuery{
+			Path:   "/" + storeKey.Name() + "/key",
+			Data:   key,
+			Height: cid.Version,
+			Prove:  true,
+		})
+		if res.Error != nil {
+			return nil, nil, res.Error
+		}
+		return res.Value, res.Proof, nil
+	})
+	ctx = env.ctx.WithValue(sdk.StoreProverContextKey{}, prover)
+	prt := ProofRuntime()
+
+	// The package block.
+	key, value, proof, err := env.vmk.ProveObject(ctx, pkgPath+".users")
+	require.NoError(t, err)
+	require.NoError(t, prt.VerifyValue(proof, cid.Hash, ProofKeyPath(proof), value))
+	var oo gnolang.Object
+	amino.MustUnmarshal(value, &oo)
+	assert.Equal(t, string(key), oo.GetObjectID().String())
+	assert.IsType(t, &gnolang.Block{}, oo)
+
+	// A struct escaped by two pointers, and a struct owned by a slice.
+	res, err := env.vmk.QueryObject(ctx, pkgPath+".users", 0, 10)
+	require.NoError(t, err)
+	var users struct {
+		Elements []struct {
+			Elem struct {
+				ObjectID string
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal([]byte(res), &users))
+	require.Len(t, users.Elements, 2)
+	for _, elem := range users.Elements {
+		oid := elem.Elem.ObjectID
+		key, value, proof, err := env.vmk.ProveObject(ctx, oid)
+		require.NoError(t, err)
+		assert.Equal(t, oid, string(key))
+		require.NoError(t, prt.VerifyValue(proof, cid.Hash, ProofKeyPath(proof), value))
+
+		// A tampered value, or proof, is rejected.
+		var sv gnolang.Object
+		amino.MustUnmarshal(value, &sv)
+		sv.(*gnolang.StructValue).Fields[1] = gnolang.TypedValue{T: gnolang.IntType}
+		assert.Error(t, prt.VerifyValue(proof, cid.Hash, ProofKeyPath(proof), amino.MustMarshalAny(sv)))
+		proof.Ops[0].Key = []byte(users.Elements[0].Elem.ObjectID + "0")
+		assert.Error(t, prt.VerifyValue(proof, cid.Hash, ProofKeyPath(proof), value))
+	}
+
+	// The package files.
+	key, value, proof, err = env.vmk.ProveMemPackage(ctx, pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, "/iavlCapKey/pkg:gno.land%2Fr%2Ftest", ProofKeyPath(proof))
+	assert.Equal(t, gnolang.MemPackageIAVLKey(pkgPath), key)
+	require.NoError(t, prt.VerifyValue(proof, cid.Hash, ProofKeyPath(proof), value))
+	var mpkg std.MemPackage
+	amino.MustUnmarshal(value, &mpkg)
+	assert.Equal(t, files[1].Body, mpkg.GetFile("test.gno").Body)
+
+	_, _, _, err = env.vmk.ProveMemPackage(ctx, "gno.land/r/missing")
+	assert.Error(t, err)
+	_, _, _, err = env.vmk.ProveObject(env.ctx, pkgPath)
+	assert.ErrorContains(t, err, "proofs are not available")
+}
+
+func TestVMKeeperProvePackageWithoutHash(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+var counter int`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// Remove the hash of the package value, like for the packages saved
+	// before the hashes of package values were committed.
+	pv := env.vmk.getGnoTransactionStore(ctx).GetPackage(pkgPath, false)
+	pvKey := []byte(pv.GetObjectID().String())
+	iavlStore := env.ctx.Store(env.vmk.iavlKey)
+	require.NotNil(t, iavlStore.Get(pvKey))
+	iavlStore.Delete(pvKey)
+
+	// Commit, and prove the queries at the committed height.
+	cms := env.ctx.MultiStore().(store.CommitMultiStore)
+	cid := cms.Commit()
+	queryable := cms.(store.Queryable)
+	prover := sdk.StoreProver(func(storeKey store.StoreKey, key []byte) ([]byte, *merkle.Proof, error) {
+		res := queryable.Query(abci.RequestQuery{
+			Path:   "/" + storeKey.Name() + "/key",
+			Data:   key,
+			Height: cid.Version,
+			Prove:  true,
+		})
+		if res.Error != nil {
+			return nil, nil, res.Error
+		}
+		return res.Value, res.Proof, nil
+	})
+	qctx := env.ctx.WithValue(sdk.StoreProverContextKey{}, prover)
+
+	// The package value cannot be proven, but its escaped package block and
+	// its files can.
+	_, _, _, err = env.vmk.ProveObject(qctx, string(pvKey))
+	assert.ErrorContains(t, err, "no proof available for "+string(pvKey))
+	_, _, _, err = env.vmk.ProveObject(qctx, pkgPath)
+	assert.NoError(t, err)
+	_, _, _, err = env.vmk.ProveMemPackage(qctx, pkgPath)
+	assert.NoError(t, err)
+}
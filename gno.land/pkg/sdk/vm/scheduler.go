@@ -0,0 +1,251 @@
+package vm
+
+import (
+	"encoding/binary"
+	goerrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+const (
+	// Event types of the scheduled calls run by RunScheduledCalls.
+	EventScheduledCallExecuted = "ScheduledCallExecuted"
+	EventScheduledCallFailed   = "ScheduledCallFailed"
+
+	// maxDueScheduledCalls bounds the number of due calls which are
+	// considered in a block; the other ones are left to the next blocks.
+	maxDueScheduledCalls = 1000
+
+	// maxGasChainCall is the gas limit of a call made with ChainCall.
+	maxGasChainCall = 10_000_000
+)
+
+// ScheduledCall is a call to a function of a realm, registered by the realm
+// with std.ScheduleCallAtHeight or std.ScheduleCallAtTime, and run by the
+// chain with RunScheduledCalls.
+type ScheduledCall struct {
+	ID        uint64   `json:"id"`
+	PkgPath   string   `json:"pkg_path"`
+	Func      string   `json:"func"`
+	Args      []string `json:"args"`
+	Height    int64    `json:"height"`    // if not zero, the call is due at this height.
+	Timestamp int64    `json:"timestamp"` // otherwise, the call is due at this time (seconds).
+}
+
+// Keys of the scheduled calls in the iavl store. The calls are queued by the
+// height, or the time, at which they are due, then by id.
+var (
+	scheduledCallNextIDKey         = []byte("schedcall:nextid")
+	scheduledCallPrefix            = []byte("schedcall:call:")
+	scheduledCallHeightQueuePrefix = []byte("schedcall:height:")
+	scheduledCallTimeQueuePrefix   = []byte("schedcall:time:")
+)
+
+func scheduledCallKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, scheduledCallPrefix...), id)
+}
+
+// scheduledCallQueueKey returns the key of call in its queue.
+func scheduledCallQueueKey(call ScheduledCall) []byte {
+	if call.Height != 0 {
+		return queueKey(scheduledCallHeightQueuePrefix, call.Height, call.ID)
+	}
+	return queueKey(scheduledCallTimeQueuePrefix, call.Timestamp, call.ID)
+}
+
+func queueKey(prefix []byte, due int64, id uint64) []byte {
+	key := append([]byte{}, prefix...)
+	key = binary.BigEndian.AppendUint64(key, uint64(due))
+	return binary.BigEndian.AppendUint64(key, id)
+}
+
+func getScheduledCall(stor store.Store, id uint64) (call ScheduledCall, ok bool) {
+	bz := stor.Get(scheduledCallKey(id))
+	if bz == nil {
+		return ScheduledCall{}, false
+	}
+	amino.MustUnmarshal(bz, &call)
+	return call, true
+}
+
+func deleteScheduledCall(stor store.Store, call ScheduledCall) {
+	stor.Delete(scheduledCallQueueKey(call))
+	stor.Delete(scheduledCallKey(call.ID))
+}
+
+// scheduleCall queues a call to fn of the realm pkgPath, due at height, or at
+// timestamp if height is zero; see SDKScheduler.
+func (vm *VMKeeper) scheduleCall(ctx sdk.Context, pkgPath string, height, timestamp int64, fn string, args []string) (uint64, error) {
+	if height < 0 || timestamp < 0 || height == 0 && timestamp == 0 {
+		return 0, fmt.Errorf("invalid scheduled call height %d or time %d", height, timestamp)
+	}
+	if height != 0 {
+		timestamp = 0
+	}
+	msg := NewMsgCall(gno.DerivePkgCryptoAddr(pkgPath), nil, pkgPath, fn, args)
+	if err := msg.ValidateBasic(); err != nil {
+		return 0, err
+	}
+
+	// NOTE: the gas of the queue is paid by the transaction.
+	stor := ctx.GasStore(vm.iavlKey)
+	var id uint64
+	if bz := stor.Get(scheduledCallNextIDKey); bz != nil {
+		id = binary.BigEndian.Uint64(bz)
+	}
+	id++
+	stor.Set(scheduledCallNextIDKey, binary.BigEndian.AppendUint64(nil, id))
+
+	call := ScheduledCall{
+		ID:        id,
+		PkgPath:   pkgPath,
+		Func:      fn,
+		Args:      args,
+		Height:    height,
+		Timestamp: timestamp,
+	}
+	stor.Set(scheduledCallKey(id), amino.MustMarshal(call))
+	stor.Set(scheduledCallQueueKey(call), []byte{})
+	return id, nil
+}
+
+// ChainCall calls fn of the realm pkgPath on behalf of the chain, e.g. to
+// notify the realm of what happened in a block. Like a scheduled call, it is
+// run like a MsgCall of the realm, and its state changes are discarded if it
+// fails; but it is run right away, with its own gas limit, outside of the
+// budget of the scheduled calls. It returns the events emitted by the call.
+func (vm *VMKeeper) ChainCall(ctx sdk.Context, pkgPath, fn string, args []string) ([]abci.Event, error) {
+	call := ScheduledCall{PkgPath: pkgPath, Func: fn, Args: args}
+	_, events, err := vm.runScheduledCall(ctx, call, maxGasChainCall)
+	return events, err
+}
+
+// cancelScheduledCall removes the pending call id from the queue, if it was
+// scheduled by the realm pkgPath.
+func (vm *VMKeeper) cancelScheduledCall(ctx sdk.Context, pkgPath string, id uint64) bool {
+	stor := ctx.GasStore(vm.iavlKey)
+	call, ok := getScheduledCall(stor, id)
+	if !ok || call.PkgPath != pkgPath {
+		return false
+	}
+	deleteScheduledCall(stor, call)
+	return true
+}
+
+// dueScheduledCalls returns the ids of the calls which are due at height, or
+// at timestamp: first those scheduled at a height, then those scheduled at a
+// time, in the order they are due.
+func dueScheduledCalls(stor store.Store, height, timestamp int64) []uint64 {
+	var ids []uint64
+	for _, queue := range []struct {
+		prefix []byte
+		due    int64
+	}{
+		{scheduledCallHeightQueuePrefix, height},
+		{scheduledCallTimeQueuePrefix, timestamp},
+	} {
+		end := queueKey(queue.prefix, queue.due+1, 0)
+		iter := stor.Iterator(queue.prefix, end)
+		for ; iter.Valid() && len(ids) < maxDueScheduledCalls; iter.Next() {
+			key := iter.Key()
+			ids = append(ids, binary.BigEndian.Uint64(key[len(key)-8:]))
+		}
+		iter.Close()
+	}
+	return ids
+}
+
+// RunScheduledCalls runs the scheduled calls which are due at the height, or
+// time, of the block of ctx, within the gas budget of the scheduled calls of a
+// block. It returns the events emitted by the calls, followed by an event for
+// every executed, or failed, call. Each call is run like a MsgCall of its
+// realm, and its state changes are discarded if it fails.
+//
+// A call which runs out of gas is delayed to the next block, where it runs
+// first; it fails if it runs out of gas with the whole budget.
+func (vm *VMKeeper) RunScheduledCalls(ctx sdk.Context) []abci.Event {
+	budget := vm.getScheduledCallsGasParam(ctx)
+	if budget == 0 {
+		return nil
+	}
+
+	stor := ctx.Store(vm.iavlKey)
+	var (
+		events []abci.Event
+		used   int64
+	)
+	for _, id := range dueScheduledCalls(stor, ctx.BlockHeight(), ctx.BlockTime().Unix()) {
+		call, ok := getScheduledCall(stor, id)
+		if !ok {
+			continue // canceled by a previous call.
+		}
+		gas := budget - used
+		gasUsed, evs, err := vm.runScheduledCall(ctx, call, gas)
+		if err != nil && gas < budget && goerrors.As(err, new(types.OutOfGasError)) {
+			break
+		}
+		used += gasUsed
+		deleteScheduledCall(stor, call)
+		events = append(events, evs...)
+		events = append(events, scheduledCallEvent(call, gasUsed, err))
+		if used >= budget {
+			break
+		}
+	}
+	return events
+}
+
+// runScheduledCall runs call with a gas limit, and commits its state changes
+// if it succeeds.
+func (vm *VMKeeper) runScheduledCall(ctx sdk.Context, call ScheduledCall, gas int64) (gasUsed int64, events []abci.Event, err error) {
+	gasMeter := store.NewGasMeter(gas)
+	cctx, writeCache := ctx.CacheContext()
+	cctx = vm.MakeGnoTransactionStore(cctx.WithGasMeter(gasMeter))
+	defer func() {
+		if r := recover(); r != nil {
+			if rerr, ok := r.(error); ok {
+				err = rerr
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+		gasUsed = gasMeter.GasConsumedToLimit()
+	}()
+
+	msg := NewMsgCall(gno.DerivePkgCryptoAddr(call.PkgPath), nil, call.PkgPath, call.Func, call.Args)
+	if _, err := vm.Call(cctx, msg); err != nil {
+		return 0, nil, err
+	}
+	vm.CommitGnoTransactionStore(cctx)
+	writeCache()
+	return 0, cctx.EventLogger().Events(), nil
+}
+
+// scheduledCallEvent returns the event of an executed, or failed, call.
+func scheduledCallEvent(call ScheduledCall, gasUsed int64, err error) abci.Event {
+	evt := gnostd.GnoEvent{
+		Type:    EventScheduledCallExecuted,
+		PkgPath: call.PkgPath,
+		Attributes: []gnostd.GnoEventAttribute{
+			{Key: "id", Value: strconv.FormatUint(call.ID, 10)},
+			{Key: "func", Value: call.Func},
+			{Key: "gas_used", Value: strconv.FormatInt(gasUsed, 10)},
+		},
+	}
+	if err != nil {
+		// Only the first line of the error: VM panics include stacktraces.
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		evt.Type = EventScheduledCallFailed
+		evt.Attributes = append(evt.Attributes, gnostd.GnoEventAttribute{Key: "error", Value: msg})
+	}
+	return evt
+}
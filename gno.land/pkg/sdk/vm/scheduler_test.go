@@ -0,0 +1,230 @@
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	gnostd "github.com/gnolang/gno/gnovm/stdlibs/std"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestVMKeeperScheduledCalls(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gno.mod", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `package test
+
+import (
+	"std"
+	"strconv"
+)
+
+var counter int
+
+func ScheduleAtHeight(cur realm, height int64, n string) uint64 {
+	return std.ScheduleCallAtHeight(height, "Tick", n)
+}
+
+func ScheduleAtTime(cur realm, timestamp int64, n string) uint64 {
+	return std.ScheduleCallAtTime(timestamp, "Tick", n)
+}
+
+func ScheduleFail(cur realm, height int64) uint64 {
+	return std.ScheduleCallAtHeight(height, "Fail")
+}
+
+func Cancel(cur realm, id uint64) bool {
+	return std.CancelScheduledCall(id)
+}
+
+func Tick(cur realm, n int) {
+	if std.PreviousRealm().Address() != std.CurrentRealm().Address() {
+		panic("not a scheduled call")
+	}
+	counter += n
+	std.Emit("Tick", "counter", strconv.Itoa(counter))
+}
+
+func Fail(cur realm) {
+	counter = -1
+	panic("failed")
+}
+
+func Counter() int { return counter }`},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	// The realm pays for the storage deposit of its scheduled calls.
+	env.bankk.SetCoins(ctx, gnolang.DerivePkgCryptoAddr(pkgPath), std.MustParseCoins(coinsString))
+
+	call := func(fn string, args ...string) string {
+		t.Helper()
+		res, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, fn, args))
+		require.NoError(t, err)
+		return res
+	}
+	call("ScheduleAtHeight", "10", "1")    // id 1
+	call("ScheduleAtHeight", "12", "10")   // id 2
+	call("ScheduleAtTime", "1000", "100")  // id 3
+	call("ScheduleFail", "10")             // id 4
+	call("ScheduleAtHeight", "10", "1000") // id 5
+	assert.Contains(t, call("Cancel", "5"), "(true bool)")
+	assert.Contains(t, call("Cancel", "5"), "(false bool)")
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	// A scheduled function can't be called by anyone else.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Tick", []string{"1"}))
+	assert.ErrorContains(t, err, "not a scheduled call")
+
+	endBlock := func(height int64, timestamp int64) []abci.Event {
+		t.Helper()
+		ctx := env.ctx.WithBlockHeader(&bft.Header{
+			ChainID: "test-chain-id",
+			Height:  height,
+			Time:    time.Unix(timestamp, 0),
+		})
+		return env.vmk.RunScheduledCalls(ctx)
+	}
+	counter := func() string {
+		t.Helper()
+		res, err := env.vmk.QueryEval(env.ctx, pkgPath, "Counter()")
+		require.NoError(t, err)
+		return res
+	}
+
+	// Nothing is due yet.
+	assert.Empty(t, endBlock(9, 900))
+	assert.Equal(t, "(0 int)", counter())
+
+	// Calls 1 and 4 are due; the failure of 4 is discarded.
+	evs := endBlock(10, 900)
+	require.Len(t, evs, 3)
+	assert.Equal(t, "Tick", evs[0].(gnostd.GnoEvent).Type)
+	executed := evs[1].(gnostd.GnoEvent)
+	assert.Equal(t, EventScheduledCallExecuted, executed.Type)
+	assert.Equal(t, pkgPath, executed.PkgPath)
+	assert.Equal(t, gnostd.GnoEventAttribute{Key: "id", Value: "1"}, executed.Attributes[0])
+	failed := evs[2].(gnostd.GnoEvent)
+	assert.Equal(t, EventScheduledCallFailed, failed.Type)
+	assert.Equal(t, gnostd.GnoEventAttribute{Key: "id", Value: "4"}, failed.Attributes[0])
+	assert.Contains(t, failed.Attributes[3].Value, "failed")
+	assert.Equal(t, "(1 int)", counter())
+
+	// Executed calls are removed from the queue; late calls still run.
+	assert.Empty(t, endBlock(11, 900))
+	evs = endBlock(13, 1000)
+	require.Len(t, evs, 4)
+	assert.Equal(t, "(111 int)", counter())
+
+	// A call which runs out of gas is delayed, then fails with the whole budget.
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	call("ScheduleAtHeight", "20", "1")
+	call("ScheduleAtHeight", "20", "2")
+	env.vmk.CommitGnoTransactionStore(ctx)
+	evs = endBlock(20, 2000)
+	require.Len(t, evs, 4)
+
+	// Each call uses about 2_700_000 gas.
+	env.vmk.prmk.SetInt64(env.ctx, scheduledCallsGasParamPath, 4_000_000)
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	call("ScheduleAtHeight", "21", "1")
+	call("ScheduleAtHeight", "21", "2")
+	env.vmk.CommitGnoTransactionStore(ctx)
+	evs = endBlock(21, 2100)
+	require.Len(t, evs, 2)
+	evs = endBlock(22, 2200)
+	require.Len(t, evs, 2)
+	assert.Equal(t, "(117 int)", counter())
+
+	env.vmk.prmk.SetInt64(env.ctx, scheduledCallsGasParamPath, 1000)
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	call("ScheduleAtHeight", "30", "1")
+	env.vmk.CommitGnoTransactionStore(ctx)
+	evs = endBlock(30, 3000)
+	require.Len(t, evs, 1)
+	assert.Equal(t, EventScheduledCallFailed, evs[0].(gnostd.GnoEvent).Type)
+	assert.Empty(t, endBlock(31, 3100))
+
+	// Scheduled calls are disabled with a zero budget.
+	env.vmk.prmk.SetInt64(env.ctx, scheduledCallsGasParamPath, 0)
+	ctx = env.vmk.MakeGnoTransactionStore(env.ctx)
+	call("ScheduleAtHeight", "40", "1")
+	env.vmk.CommitGnoTransactionStore(ctx)
+	assert.Empty(t, endBlock(40, 4000))
+
+	// The chain can call the realm right away, outside of the budget.
+	evs, err = env.vmk.ChainCall(env.ctx, pkgPath, "Tick", []string{"1000"})
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	assert.Equal(t, "Tick", evs[0].(gnostd.GnoEvent).Type)
+	assert.Equal(t, "(1117 int)", counter())
+	_, err = env.vmk.ChainCall(env.ctx, pkgPath, "Fail", nil)
+	assert.ErrorContains(t, err, "failed")
+	assert.Equal(t, "(1117 int)", counter())
+
+	// The call due at 40 runs once the budget is restored.
+	env.vmk.prmk.SetInt64(env.ctx, scheduledCallsGasParamPath, scheduledCallsGasDefault)
+	evs = endBlock(50, 5000)
+	require.Len(t, evs, 2)
+	assert.Equal(t, "(1118 int)", counter())
+}
+
+func TestVMKeeperScheduleCallErrors(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins(coinsString))
+
+	// MsgRun packages can't schedule calls.
+	msg := NewMsgRun(addr, nil, []*std.MemFile{
+		{Name: "main.gno", Body: `package main
+
+import "std"
+
+func main() {
+	std.ScheduleCallAtHeight(10, "Foo")
+}`},
+	})
+	_, err := env.vmk.Run(ctx, msg)
+	assert.ErrorContains(t, err, "scheduled calls can only be made by realms")
+
+	// Nor can queries.
+	_, err = env.vmk.QueryEval(ctx, "std", `ScheduleCallAtHeight(10, "Foo")`)
+	assert.Error(t, err)
+
+	// Invalid calls are rejected.
+	_, err = env.vmk.scheduleCall(ctx, "gno.land/r/test", 0, 0, "Foo", nil)
+	assert.Error(t, err)
+	_, err = env.vmk.scheduleCall(ctx, "gno.land/r/test", -1, 0, "Foo", nil)
+	assert.Error(t, err)
+	_, err = env.vmk.scheduleCall(ctx, "gno.land/r/test", 10, 0, "", nil)
+	assert.Error(t, err)
+	assert.False(t, env.vmk.cancelScheduledCall(ctx, "gno.land/r/test", 1))
+
+	// Only the scheduling realm can cancel a call.
+	id, err := env.vmk.scheduleCall(ctx, "gno.land/r/test", 10, 0, "Foo", nil)
+	require.NoError(t, err)
+	assert.False(t, env.vmk.cancelScheduledCall(ctx, "gno.land/r/other", id))
+	assert.True(t, env.vmk.cancelScheduledCall(ctx, "gno.land/r/test", id))
+	assert.Empty(t, env.vmk.RunScheduledCalls(ctx.WithBlockHeader(&bft.Header{Height: 10})))
+}
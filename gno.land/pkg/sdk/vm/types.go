@@ -17,6 +17,7 @@ type AccountKeeperI interface {
 type BankKeeperI interface {
 	GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins
 	SendCoins(ctx sdk.Context, fromAddr crypto.Address, toAddr crypto.Address, amt std.Coins) error
+	SendCoinsUnrestricted(ctx sdk.Context, fromAddr crypto.Address, toAddr crypto.Address, amt std.Coins) error
 	SubtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error)
 	AddCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error)
 }
@@ -41,6 +42,8 @@ type NamedType struct {
 	Name  string
 	Type  string
 	Value string
+	// JSON schema of the parameters given in JSON, if any.
+	Schema string `json:",omitempty"`
 }
 
 type FunctionSignatures []FunctionSignature
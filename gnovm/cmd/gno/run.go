@@ -18,11 +18,13 @@ import (
 )
 
 type runCmd struct {
-	verbose   bool
-	rootDir   string
-	expr      string
-	debug     bool
-	debugAddr string
+	verbose    bool
+	rootDir    string
+	expr       string
+	debug      bool
+	debugAddr  string
+	dap        bool
+	gasProfile string
 }
 
 func newRunCmd(cio commands.IO) *commands.Command {
@@ -76,13 +78,31 @@ func (c *runCmd) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.BoolVar(
+		&c.dap,
+		"dap",
+		false,
+		"serve the debugger with the Debug Adapter Protocol at -debug-addr, for IDEs",
+	)
+
+	fs.StringVar(
+		&c.gasProfile,
+		"gasprofile",
+		"",
+		"write a gas profile of the Gno functions to the file, for 'go tool pprof'",
+	)
 }
 
-func execRun(cfg *runCmd, args []string, cio commands.IO) error {
+func execRun(cfg *runCmd, args []string, cio commands.IO) (err error) {
 	if len(args) == 0 {
 		return flag.ErrHelp
 	}
 
+	if cfg.dap && cfg.debugAddr == "" {
+		return errors.New("-dap requires -debug-addr")
+	}
+
 	if cfg.rootDir == "" {
 		cfg.rootDir = gnoenv.RootDir()
 	}
@@ -125,12 +145,34 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 	defer m.Release()
 
 	// If the debug address is set, the debugger waits for a remote client to connect to it.
-	if cfg.debugAddr != "" {
+	if cfg.dap {
+		s, err := gno.ServeDAP(cfg.debugAddr)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		m.Debugger.EnableDAP(s, nil)
+	} else if cfg.debugAddr != "" {
 		if err := m.Debugger.Serve(cfg.debugAddr); err != nil {
 			return err
 		}
 	}
 
+	if cfg.gasProfile != "" {
+		m.Profile = gno.NewProfile()
+		if dir, err := filepath.Abs(args[0]); err == nil {
+			if s, err := os.Stat(dir); err == nil && !s.IsDir() {
+				dir = filepath.Dir(dir)
+			}
+			m.Profile.SetPackageDir(pkgPath, dir)
+		}
+		defer func() {
+			if perr := writeProfiles("", cfg.gasProfile, m.Profile); perr != nil && err == nil {
+				err = perr
+			}
+		}()
+	}
+
 	// run files
 	m.RunFiles(files...)
 	return runExpr(m, cfg.expr)
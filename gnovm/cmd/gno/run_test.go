@@ -78,6 +78,11 @@ func TestRunApp(t *testing.T) {
 			args:             []string{"run", "-debug-addr", "invalidhost:17538", "../../tests/integ/debugger/sample.gno"},
 			errShouldContain: "listen tcp",
 		},
+		{
+			args:                []string{"run", "-gasprofile", "/does_not_exist/gas.out", "../../tests/integ/run_main/main.gno"},
+			stdoutShouldContain: "hello world!",
+			errShouldContain:    "create profile",
+		},
 		{
 			args:                 []string{"run", "../../tests/integ/invalid_assign/main.gno"},
 			recoverShouldContain: "cannot use bool as main.C without explicit conversion",
@@ -8,6 +8,7 @@ import (
 	goio "io"
 	"io/fs"
 	"log"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/gnolang/gno/gnovm/pkg/gnomod"
 	"github.com/gnolang/gno/gnovm/pkg/test"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 type testCmd struct {
@@ -31,6 +33,15 @@ type testCmd struct {
 	printEvents         bool
 	debug               bool
 	debugAddr           string
+	dap                 bool
+	cover               bool
+	coverProfile        string
+	bench               string
+	benchTime           test.DurationOrCount
+	fuzz                string
+	fuzzTime            test.DurationOrCount
+	cpuProfile          string
+	gasProfile          string
 }
 
 func newTestCmd(io commands.IO) *commands.Command {
@@ -48,8 +59,8 @@ file pattern "*_test.gno" or "*_filetest.gno".
 
 The <package> can be directory or file path (relative or absolute).
 
-- "*_test.gno" files work like "*_test.go" files, but they contain only test
-functions. Benchmark and fuzz functions aren't supported yet. Similarly, only
+- "*_test.gno" files work like "*_test.go" files, but they contain only test,
+benchmark and fuzz functions. Similarly, only
 tests that belong to the same package are supported for now (no "xxx_test").
 
 The package path used to execute the "*_test.gno" file is fetched from the
@@ -90,6 +101,28 @@ of the GnoVM, acting as a "golden test":
 To speed up execution, imports of pure packages are processed separately from
 the execution of the tests. This makes testing faster, but means that the
 initialization of imported pure packages cannot be checked in filetests.
+
+The -cover flag reports the percentage of statements of each package that
+were executed by its tests, including its filetests. The -coverprofile flag
+additionally writes the count of executions of each statement to the given
+file, in the format of Go cover profiles, which can be rendered with
+'go tool cover -html=<file>'.
+
+The -bench flag runs the benchmarks matching the given regular expression,
+after the tests. Each benchmark runs for -benchtime, and reports the time,
+gas, allocated bytes and allocations of the GnoVM per iteration, in the
+format of Go benchmarks, which can be compared with benchstat.
+
+Fuzz tests run like tests, with their seed corpus and the inputs in
+testdata/fuzz/FuzzXxx. The -fuzz flag fuzzes the fuzz tests matching the
+given regular expression, for -fuzztime or until an input fails, by mutating
+the inputs which reach new statements of the package. Failing inputs are
+written to testdata/fuzz/FuzzXxx, so that they are run with the next tests.
+
+The -cpuprofile and -gasprofile flags sample the call stack of the GnoVM on
+each op and on each gas consumption, and write the time and the gas spent by
+each Gno function and line to the given file, in the pprof format, which can
+be read with 'go tool pprof <file>'.
 `,
 		},
 		cmd,
@@ -176,9 +209,71 @@ func (c *testCmd) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.BoolVar(
+		&c.dap,
+		"dap",
+		false,
+		"serve the debugger with the Debug Adapter Protocol at -debug-addr, for IDEs",
+	)
+
+	fs.BoolVar(
+		&c.cover,
+		"cover",
+		false,
+		"enable coverage analysis",
+	)
+
+	fs.StringVar(
+		&c.coverProfile,
+		"coverprofile",
+		"",
+		"write a coverage profile to the file after running all tests (sets -cover)",
+	)
+
+	fs.StringVar(
+		&c.bench,
+		"bench",
+		"",
+		"run only those benchmarks matching a regular expression",
+	)
+
+	c.benchTime = test.DurationOrCount{D: test.DefaultBenchTime}
+	fs.Var(
+		&c.benchTime,
+		"benchtime",
+		"run each benchmark for duration d, or N times if written as Nx",
+	)
+
+	fs.StringVar(
+		&c.fuzz,
+		"fuzz",
+		"",
+		"run the fuzz tests matching a regular expression with generated inputs",
+	)
+
+	fs.Var(
+		&c.fuzzTime,
+		"fuzztime",
+		"fuzz for duration d, or N inputs if written as Nx (default: until an input fails)",
+	)
+
+	fs.StringVar(
+		&c.cpuProfile,
+		"cpuprofile",
+		"",
+		"write a CPU profile of the Gno functions to the file after running all tests",
+	)
+
+	fs.StringVar(
+		&c.gasProfile,
+		"gasprofile",
+		"",
+		"write a gas profile of the Gno functions to the file after running all tests",
+	)
 }
 
-func execTest(cmd *testCmd, args []string, io commands.IO) error {
+func execTest(cmd *testCmd, args []string, io commands.IO) (err error) {
 	// Default to current directory if no args provided
 	if len(args) == 0 {
 		args = []string{"."}
@@ -224,6 +319,45 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 	opts.Events = cmd.printEvents
 	opts.Debug = cmd.debug
 	opts.FailfastFlag = cmd.failfast
+	opts.BenchFlag = cmd.bench
+	opts.BenchTime = cmd.benchTime
+	opts.FuzzFlag = cmd.fuzz
+	opts.FuzzTime = cmd.fuzzTime
+
+	if cmd.dap {
+		if cmd.debugAddr == "" {
+			return errors.New("-dap requires -debug-addr")
+		}
+		s, err := gno.ServeDAP(cmd.debugAddr)
+		if err != nil {
+			return err
+		}
+		defer s.Close()
+		opts.DebugSession = s
+	}
+
+	var cov *gno.Coverage
+	if cmd.cover || cmd.coverProfile != "" {
+		cov = gno.NewCoverage()
+		opts.Coverage = cov
+	}
+	if cmd.coverProfile != "" {
+		defer func() {
+			if perr := writeCoverProfile(cmd.coverProfile, cov); perr != nil && err == nil {
+				err = perr
+			}
+		}()
+	}
+
+	if cmd.cpuProfile != "" || cmd.gasProfile != "" {
+		prof := gno.NewProfile()
+		opts.Profile = prof
+		defer func() {
+			if perr := writeProfiles(cmd.cpuProfile, cmd.gasProfile, prof); perr != nil && err == nil {
+				err = perr
+			}
+		}()
+	}
 
 	buildErrCount := 0
 	testErrCount := 0
@@ -266,6 +400,15 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 
 		// Read MemPackage.
 		mpkg := gno.MustReadMemPackage(pkg.Dir, pkgPath)
+		if cov != nil {
+			addCoverFiles(cov, pkg.Dir, mpkg)
+		}
+		if opts.Profile != nil {
+			// absolute paths let 'go tool pprof' find the files.
+			if absDir, err := filepath.Abs(pkg.Dir); err == nil {
+				opts.Profile.SetPackageDir(mpkg.Path, absDir)
+			}
+		}
 
 		// Lint/typecheck/format.
 		// (gno.mod will be read again).
@@ -305,7 +448,7 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 				return fail()
 			}
 		} else {
-			io.ErrPrintfln("ok      %s \t%s", pkg.Dir, dstr)
+			io.ErrPrintfln("ok      %s \t%s%s", pkg.Dir, dstr, coverSummary(cov, pkgPath))
 		}
 	}
 	if testErrCount > 0 || buildErrCount > 0 {
@@ -315,6 +458,80 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 	return nil
 }
 
+// addCoverFiles adds the non-test files of mpkg, found in dir, to cov.
+func addCoverFiles(cov *gno.Coverage, dir string, mpkg *std.MemPackage) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_test.gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		fn, err := gno.ParseFile(mfile.Name, mfile.Body)
+		if err != nil {
+			// reported when running the tests.
+			continue
+		}
+		// absolute paths let 'go tool cover' find the files.
+		cov.AddFile(mpkg.Path, filepath.Join(absDir, mfile.Name), fn)
+	}
+}
+
+// coverSummary returns the coverage of pkgPath to append to its status line,
+// or an empty string if cov is nil.
+func coverSummary(cov *gno.Coverage, pkgPath string) string {
+	if cov == nil {
+		return ""
+	}
+	covered, total := cov.Stats(pkgPath)
+	if total == 0 {
+		return "\tcoverage: [no statements]"
+	}
+	return fmt.Sprintf("\tcoverage: %.1f%% of statements", 100*float64(covered)/float64(total))
+}
+
+func writeCoverProfile(path string, cov *gno.Coverage) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create coverage profile: %w", err)
+	}
+	defer f.Close()
+	if err := cov.WriteProfile(f); err != nil {
+		return fmt.Errorf("write coverage profile: %w", err)
+	}
+	return nil
+}
+
+// writeProfiles writes the CPU and gas profiles of prof to the non-empty paths.
+func writeProfiles(cpuPath, gasPath string, prof *gno.Profile) error {
+	for _, p := range []struct {
+		path string
+		kind gno.ProfileKind
+	}{
+		{cpuPath, gno.ProfileCPU},
+		{gasPath, gno.ProfileGas},
+	} {
+		if p.path == "" {
+			continue
+		}
+		f, err := os.Create(p.path)
+		if err != nil {
+			return fmt.Errorf("create profile: %w", err)
+		}
+		err = prof.WriteProfile(f, p.kind)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("write profile: %w", err)
+		}
+	}
+	return nil
+}
+
 func determinePkgPath(mod *gnomod.File, dir, rootDir string) (string, bool) {
 	if mod != nil {
 		return mod.Module.Mod.Path, true
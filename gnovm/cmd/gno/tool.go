@@ -27,6 +27,7 @@ func newToolCmd(io commands.IO) *commands.Command {
 		// gno specific commands:
 		//
 		// ast
+		newLSPCmd(io),
 		// publish/release
 		// render -- call render()?
 		newTranspileCmd(io),
@@ -569,8 +569,7 @@ func filterInvalidFiletest(cio commands.IO, mpkg *std.MemPackage) bool {
 		strings.Contains(dErr, "cannot import stdlib internal") ||
 		strings.Contains(dErr, "internal/ packages can only be") ||
 		strings.Contains(dErr, "cannot find branch label") ||
-		strings.Contains(dErr, "but is not natively defined") ||
-		strings.Contains(dErr, "goroutines are not permitted") {
+		strings.Contains(dErr, "but is not natively defined") {
 		cio.Printfln("skipping filetest with type-check-ish error %q", mfile.Name)
 		return true
 	}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/lsp"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type lspCfg struct {
+	rootDir string
+}
+
+func newLSPCmd(io commands.IO) *commands.Command {
+	cfg := &lspCfg{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "lsp",
+			ShortUsage: "lsp [flags]",
+			ShortHelp:  "runs the Gno language server",
+			LongHelp: `Runs a language server for Gno, speaking the Language Server Protocol over
+stdin and stdout, for use by editors.
+
+It reports the errors of the type checker and of the preprocessor in the open
+documents, and provides hover with documentation, go-to-definition, completion
+and formatting (like gno fmt). Imports are looked up in the standard libraries
+and examples of the gno root directory, and in the module cache of packages
+downloaded with gno mod download.`,
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execLSP(cfg, args, io)
+		},
+	)
+}
+
+func (c *lspCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.rootDir,
+		"root-dir",
+		"",
+		"clone location of github.com/gnolang/gno (gno tries to guess it)",
+	)
+}
+
+func execLSP(cfg *lspCfg, args []string, io commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+	if cfg.rootDir == "" {
+		cfg.rootDir = gnoenv.RootDir()
+	}
+
+	return lsp.NewServer(cfg.rootDir).Serve(io.In(), io.Out())
+}
@@ -12,6 +12,11 @@ type Allocator struct {
 	maxBytes int64
 	bytes    int64
 	collect  func() (left int64, ok bool) // gc callback
+
+	// totals since creation, unlike bytes which is recomputed on gc.
+	numAllocs  int64
+	totalBytes int64
+	collecting bool
 }
 
 // for gonative, which doesn't consider the allocator.
@@ -29,6 +34,8 @@ const (
 	_allocSliceValue       = 40
 	_allocFuncValue        = 312
 	_allocMapValue         = 144
+	_allocChanValue        = 136
+	_allocGoroutine        = 216
 	_allocBoundMethodValue = 176
 	_allocBlock            = 472
 	_allocPackageValue     = 240
@@ -59,6 +66,9 @@ const (
 	allocFunc        = _allocBase + _allocPointer + _allocFuncValue
 	allocMap         = _allocBase + _allocPointer + _allocMapValue
 	allocMapItem     = _allocTypedValue * 3 // XXX
+	allocChan        = _allocBase + _allocPointer + _allocChanValue
+	allocChanItem    = _allocTypedValue
+	allocGoroutine   = _allocBase + _allocPointer + _allocGoroutine
 	allocBoundMethod = _allocBase + _allocPointer + _allocBoundMethodValue
 	allocBlock       = _allocBase + _allocPointer + _allocBlock
 	allocBlockItem   = _allocTypedValue
@@ -95,6 +105,16 @@ func (alloc *Allocator) Status() (maxBytes int64, bytes int64) {
 	return alloc.maxBytes, alloc.bytes
 }
 
+// Totals returns the number of allocations and of bytes allocated since the
+// allocator was created, excluding the recounting of live values during
+// garbage collection.
+func (alloc *Allocator) Totals() (allocs int64, bytes int64) {
+	if alloc == nil {
+		return 0, 0
+	}
+	return alloc.numAllocs, alloc.totalBytes
+}
+
 func (alloc *Allocator) Reset() *Allocator {
 	if alloc == nil {
 		return nil
@@ -119,6 +139,10 @@ func (alloc *Allocator) Allocate(size int64) {
 		return
 	}
 
+	if !alloc.collecting {
+		alloc.numAllocs++
+		alloc.totalBytes += size
+	}
 	alloc.bytes += size
 	if alloc.bytes > alloc.maxBytes {
 		if left, ok := alloc.collect(); !ok {
@@ -176,6 +200,15 @@ func (alloc *Allocator) AllocateMapItem() {
 	alloc.Allocate(allocMapItem)
 }
 
+func (alloc *Allocator) AllocateChan(items int64) {
+	alloc.Allocate(allocChan + allocChanItem*items)
+}
+
+// NOTE: the initial op and value stacks are included.
+func (alloc *Allocator) AllocateGoroutine() {
+	alloc.Allocate(allocGoroutine + (1+allocTypedValue)*goroutineStackSize)
+}
+
 func (alloc *Allocator) AllocateBoundMethod() {
 	alloc.Allocate(allocBoundMethod)
 }
@@ -326,6 +359,15 @@ func (alloc *Allocator) NewMap(size int) *MapValue {
 	return mv
 }
 
+func (alloc *Allocator) NewChan(id int64, size int) *ChanValue {
+	alloc.AllocateChan(int64(size))
+	return &ChanValue{
+		ID:     id,
+		Cap:    size,
+		Buffer: make([]TypedValue, 0, size),
+	}
+}
+
 func (alloc *Allocator) NewBlock(source BlockNode, parent *Block) *Block {
 	alloc.AllocateBlock(int64(source.GetNumNames()))
 	return NewBlock(source, parent)
@@ -384,6 +426,10 @@ func (mv *MapValue) GetShallowSize() int64 {
 	return allocMap + allocMapItem*int64(mv.GetLength())
 }
 
+func (cv *ChanValue) GetShallowSize() int64 {
+	return allocChan + allocChanItem*int64(cv.Cap)
+}
+
 func (bmv *BoundMethodValue) GetShallowSize() int64 {
 	return allocBoundMethod
 }
@@ -18,6 +18,8 @@ func TestAllocSizes(t *testing.T) {
 	println("SliceValue{}", unsafe.Sizeof(SliceValue{}))
 	println("FuncValue{}", unsafe.Sizeof(FuncValue{}))
 	println("MapValue{}", unsafe.Sizeof(MapValue{}))
+	println("ChanValue{}", unsafe.Sizeof(ChanValue{}))
+	println("Goroutine{}", unsafe.Sizeof(Goroutine{}))
 	println("BoundMethodValue{}", unsafe.Sizeof(BoundMethodValue{}))
 	println("Block{}", unsafe.Sizeof(Block{}))
 	println("TypeValue{}", unsafe.Sizeof(TypeValue{}))
@@ -0,0 +1,173 @@
+package gnolang
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Coverage counts the statements executed by machines, for the files added
+// with [Coverage.AddFile]. It is enabled by setting [Machine.Coverage], and
+// it can be shared by many machines (but not concurrently).
+//
+// Each statement in a function body is counted on its own, excluding block
+// and empty statements, and profiles are written in the format of Go cover
+// profiles, with each statement as a block.
+//
+// Coverage also records the edges between consecutively executed statements,
+// which tell apart the branches taken to reach a statement; this is used as
+// feedback when fuzzing.
+type Coverage struct {
+	files map[coverKey]*coverFile
+	order []*coverFile
+	last  *coverFile // the file of the last hit, as a cache.
+	edges map[coverEdge]struct{}
+	prev  *coverStmt // the statement of the last hit.
+}
+
+type coverEdge struct {
+	from, to *coverStmt
+}
+
+type coverKey struct {
+	pkgPath string
+	file    string
+}
+
+type coverFile struct {
+	coverKey
+	path  string       // the file path used in profiles.
+	stmts []*coverStmt // in source order.
+	index map[Span]int // statement span -> index in stmts.
+}
+
+type coverStmt struct {
+	span  Span
+	count int64
+}
+
+// NewCoverage returns a new, empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		files: make(map[coverKey]*coverFile),
+		edges: make(map[coverEdge]struct{}),
+	}
+}
+
+// AddFile adds the statements of the file fn of package pkgPath, such that
+// their execution is counted. path is the file path used in profiles.
+func (c *Coverage) AddFile(pkgPath, path string, fn *FileNode) {
+	key := coverKey{pkgPath, fn.FileName}
+	if _, exists := c.files[key]; exists {
+		return
+	}
+	cf := &coverFile{
+		coverKey: key,
+		path:     path,
+		index:    make(map[Span]int),
+	}
+	Transcribe(fn, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		switch ftype {
+		case TRANS_FUNCLIT_BODY, TRANS_FUNC_BODY, TRANS_BLOCK_BODY,
+			TRANS_FOR_BODY, TRANS_IF_CASE_BODY, TRANS_RANGE_BODY,
+			TRANS_SELECTCASE_BODY, TRANS_SWITCHCASE_BODY:
+		default:
+			return n, TRANS_CONTINUE
+		}
+		switch n.(type) {
+		case *BlockStmt, *EmptyStmt:
+			return n, TRANS_CONTINUE
+		}
+		span := coverSpan(n.GetSpan())
+		if _, exists := cf.index[span]; !exists && !span.IsZero() {
+			cf.index[span] = len(cf.stmts)
+			cf.stmts = append(cf.stmts, &coverStmt{span: span})
+		}
+		return n, TRANS_CONTINUE
+	})
+	sort.Slice(cf.stmts, func(i, j int) bool {
+		return cf.stmts[i].span.Compare(cf.stmts[j].span) < 0
+	})
+	for i, cs := range cf.stmts {
+		cf.index[cs.span] = i
+	}
+	c.files[key] = cf
+	c.order = append(c.order, cf)
+}
+
+// Returns the span without its conflict number.
+func coverSpan(span Span) Span {
+	return Span{Pos: span.Pos, End: span.End}
+}
+
+// Records the execution of the statement s, in the current block of m.
+func (c *Coverage) hit(m *Machine, s Stmt) {
+	loc := m.LastBlock().GetSource(m.Store).GetLocation()
+	// instances of generics are located in "file.gno[targs]".
+	file := loc.File
+	if i := strings.IndexByte(file, '['); i >= 0 {
+		file = file[:i]
+	}
+	cf := c.last
+	if cf == nil || cf.pkgPath != loc.PkgPath || cf.file != file {
+		cf = c.files[coverKey{loc.PkgPath, file}]
+		if cf == nil {
+			return
+		}
+		c.last = cf
+	}
+	if i, ok := cf.index[coverSpan(s.GetSpan())]; ok {
+		cs := cf.stmts[i]
+		cs.count++
+		c.edges[coverEdge{c.prev, cs}] = struct{}{}
+		c.prev = cs
+	}
+}
+
+// Edges returns the number of distinct edges between consecutively executed
+// statements so far, which only grows as new paths are executed.
+func (c *Coverage) Edges() int {
+	return len(c.edges)
+}
+
+// Stats returns the number of statements of package pkgPath that were
+// executed at least once, and the total number of statements.
+func (c *Coverage) Stats(pkgPath string) (covered, total int) {
+	for _, cf := range c.order {
+		if cf.pkgPath != pkgPath {
+			continue
+		}
+		for _, cs := range cf.stmts {
+			if cs.count > 0 {
+				covered++
+			}
+		}
+		total += len(cf.stmts)
+	}
+	return covered, total
+}
+
+// WriteProfile writes the coverage profile to w, in the format of Go cover
+// profiles with mode "count".
+func (c *Coverage) WriteProfile(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "mode: count"); err != nil {
+		return err
+	}
+	for _, cf := range c.order {
+		for _, cs := range cf.stmts {
+			_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d 1 %d\n",
+				cf.path,
+				cs.span.Line, cs.span.Column,
+				cs.span.End.Line, cs.span.End.Column,
+				cs.count)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
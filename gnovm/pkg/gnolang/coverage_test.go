@@ -0,0 +1,69 @@
+package gnolang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	const src = `package cov
+
+func Max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func Pick[T any](ok bool, a, b T) T {
+	if ok {
+		return a
+	}
+	return b
+}
+`
+	cov := NewCoverage()
+	cov.AddFile("gno.land/p/cov", "cov.gno", MustParseFile("cov.gno", src))
+
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:  "gno.land/p/cov",
+		Store:    NewStore(nil, baseStore, iavlStore),
+		Coverage: cov,
+	})
+	defer m.Release()
+	m.RunMemPackage(&std.MemPackage{
+		Name:  "cov",
+		Path:  "gno.land/p/cov",
+		Files: []*std.MemFile{{Name: "cov.gno", Body: src}},
+	}, true)
+	m.RunStatement(StageRun, S(Call(X("Max"), 1, 2)))
+	m.RunStatement(StageRun, S(Call(X("Max"), 3, 2)))
+	m.RunStatement(StageRun, S(Call(X("Pick"), X("true"), 1, 2)))
+
+	covered, total := cov.Stats("gno.land/p/cov")
+	assert.Equal(t, 5, covered)
+	assert.Equal(t, 6, total)
+	assert.Equal(t, 6, cov.Edges())
+
+	var sb strings.Builder
+	require.NoError(t, cov.WriteProfile(&sb))
+	assert.Equal(t, `mode: count
+cov.gno:4.2,6.3 1 2
+cov.gno:5.3,5.11 1 1
+cov.gno:7.2,7.10 1 1
+cov.gno:11.2,13.3 1 1
+cov.gno:12.3,12.11 1 1
+cov.gno:14.2,14.10 1 0
+`, sb.String())
+}
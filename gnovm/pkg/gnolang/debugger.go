@@ -53,12 +53,26 @@ type Debugger struct {
 	loc         Location                    // source location of the current machine instruction
 	prevLoc     Location                    // source location of the previous machine instruction
 	nextLoc     Location                    // source location at the 'next' command
-	breakpoints []Location                  // list of breakpoints set by user, as source locations
+	breakpoints []*debugBreakpoint          // list of breakpoints and watchpoints set by user
 	call        []Location                  // for function tracking, ideally should be provided by machine frame
 	frameLevel  int                         // frame level of the current machine instruction
 	nextDepth   int                         // function call depth at the 'next' command
 	getSrc      func(string, string) string // helper to access source from repl or others
 	rootDir     string
+	dap         *DAPSession // DAP session, or nil when using the command line interface
+}
+
+// debugBreakpoint is a breakpoint at a source location, or a watchpoint on a
+// value when watch is set.
+type debugBreakpoint struct {
+	loc     Location // source location of a breakpoint
+	cond    string   // condition expression, if any
+	hitCond string   // condition on the hit count, like "> 5", if any
+	hits    int      // number of times the breakpoint was hit
+
+	watch string       // watched expression, for a watchpoint
+	ptr   PointerValue // pointer to the watched value
+	old   TypedValue   // last known watched value
 }
 
 // Enable makes the debugger d active, using in as input reader, out as output writer and f as a source helper.
@@ -92,6 +106,7 @@ func init() {
 		"break":       {debugBreak, breakUsage, breakShort, breakLong},
 		"breakpoints": {debugBreakpoints, breakpointsUsage, breakpointsShort, ""},
 		"clear":       {debugClear, clearUsage, clearShort, ""},
+		"condition":   {debugCondition, conditionUsage, conditionShort, conditionLong},
 		"continue":    {debugContinue, continueUsage, continueShort, ""},
 		"detach":      {debugDetach, detachUsage, detachShort, ""},
 		"down":        {debugDown, downUsage, downShort, ""},
@@ -105,6 +120,7 @@ func init() {
 		"stepi":       {debugContinue, stepiUsage, stepiShort, ""},
 		"stepout":     {debugContinue, stepoutUsage, stepoutShort, ""},
 		"up":          {debugUp, upUsage, upShort, ""},
+		"watch":       {debugWatch, watchUsage, watchShort, watchLong},
 	}
 
 	// Sort command names for help.
@@ -119,6 +135,7 @@ func init() {
 	debugCmds["bp"] = debugCmds["breakpoints"]
 	debugCmds["bt"] = debugCmds["stack"]
 	debugCmds["c"] = debugCmds["continue"]
+	debugCmds["cond"] = debugCmds["condition"]
 	debugCmds["h"] = debugCmds["help"]
 	debugCmds["l"] = debugCmds["list"]
 	debugCmds["n"] = debugCmds["next"]
@@ -137,10 +154,18 @@ loop:
 		switch m.Debugger.state {
 		case DebugAtInit:
 			debugUpdateLocation(m)
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.init(m)
+				continue loop
+			}
 			fmt.Fprintln(m.Debugger.out, "Welcome to the Gnovm debugger. Type 'help' for list of commands.")
 			m.Debugger.scanner = bufio.NewScanner(m.Debugger.in)
 			m.Debugger.state = DebugAtCmd
 		case DebugAtCmd:
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.cmd(m)
+				continue loop
+			}
 			if err := debugCmd(m); err != nil {
 				fmt.Fprintln(m.Debugger.out, "Command failed:", err)
 			}
@@ -148,6 +173,12 @@ loop:
 			if !m.Debugger.enabled {
 				break loop
 			}
+			if atWatch(m) {
+				m.Debugger.state = DebugAtCmd
+				m.Debugger.prevLoc = m.Debugger.loc
+				debugStopped(m, "data breakpoint")
+				continue loop
+			}
 			switch m.Debugger.lastCmd {
 			case "si", "stepi":
 				m.Debugger.state = DebugAtCmd
@@ -156,7 +187,7 @@ loop:
 				if m.Debugger.loc != m.Debugger.prevLoc && m.Debugger.loc.File != "" {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "step")
 					continue loop
 				}
 			case "n", "next":
@@ -164,21 +195,21 @@ loop:
 					(m.Debugger.nextDepth == 0 || !sameLine(m.Debugger.loc, m.Debugger.nextLoc) && callDepth(m) <= m.Debugger.nextDepth) {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "step")
 					continue loop
 				}
 			case "stepout", "so":
 				if callDepth(m) < m.Debugger.nextDepth {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "step")
 					continue loop
 				}
 			default:
 				if atBreak(m) {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "breakpoint")
 					continue loop
 				}
 			}
@@ -200,6 +231,16 @@ loop:
 	}
 }
 
+// debugStopped reports that the program stopped for the given reason, by
+// listing the source code around the current line, or by sending a DAP event.
+func debugStopped(m *Machine, reason string) {
+	if m.Debugger.dap != nil {
+		m.Debugger.dap.stopped(reason)
+		return
+	}
+	debugList(m, "")
+}
+
 // callDepth returns the function call depth.
 func callDepth(m *Machine) int {
 	n := 0
@@ -223,14 +264,126 @@ func atBreak(m *Machine) bool {
 	if loc == m.Debugger.prevLoc {
 		return false
 	}
-	for _, b := range m.Debugger.breakpoints {
-		if loc.File == b.File && loc.Line == b.Line {
-			return true
+	if m.Debugger.dap != nil {
+		return m.Debugger.dap.atBreak(m, loc)
+	}
+	for i, b := range m.Debugger.breakpoints {
+		if b.watch == "" && loc.File == b.loc.File && loc.Line == b.loc.Line {
+			hit, err := b.hit(m)
+			if err != nil {
+				fmt.Fprintf(m.Debugger.out, "Breakpoint %d condition failed: %v\n", i, err)
+			}
+			if hit {
+				return true
+			}
 		}
 	}
 	return false
 }
 
+// hit is called when the location of breakpoint b is reached. It evaluates
+// the conditions of b, and returns true if the program should stop, which is
+// also the case if a condition fails to evaluate.
+func (b *debugBreakpoint) hit(m *Machine) (bool, error) {
+	if b.cond != "" {
+		tv, err := debugEval(m, b.cond)
+		if err != nil {
+			return true, err
+		}
+		if tv.T == nil || tv.T.Kind() != BoolKind {
+			return true, fmt.Errorf("condition is not a boolean: %v", tv)
+		}
+		if !tv.GetBool() {
+			return false, nil
+		}
+	}
+	b.hits++
+	if b.hitCond != "" {
+		return evalHitCond(b.hitCond, b.hits)
+	}
+	return true, nil
+}
+
+// evalHitCond returns true if hits satisfies the hit count condition cond,
+// which is an integer optionally preceded by a comparison operator, or by %
+// to stop every n hits. A bare integer stops when the hit count is reached.
+func evalHitCond(cond string, hits int) (bool, error) {
+	cond = strings.TrimSpace(cond)
+	op := "=="
+	for _, o := range []string{"==", "!=", "<=", ">=", "<", ">", "%"} {
+		if strings.HasPrefix(cond, o) {
+			op, cond = o, strings.TrimSpace(cond[len(o):])
+			break
+		}
+	}
+	n, err := strconv.Atoi(cond)
+	if err != nil {
+		return true, fmt.Errorf("invalid hit count condition: %w", err)
+	}
+	switch op {
+	case "==":
+		return hits == n, nil
+	case "!=":
+		return hits != n, nil
+	case "<=":
+		return hits <= n, nil
+	case ">=":
+		return hits >= n, nil
+	case "<":
+		return hits < n, nil
+	case ">":
+		return hits > n, nil
+	default: // "%"
+		if n <= 0 {
+			return true, errors.New("invalid hit count condition: modulo must be positive")
+		}
+		return hits%n == 0, nil
+	}
+}
+
+// atWatch returns true if the value of a watchpoint changed since the last
+// instruction, after reporting the change.
+func atWatch(m *Machine) bool {
+	bps := m.Debugger.breakpoints
+	if m.Debugger.dap != nil {
+		bps = m.Debugger.dap.watchpoints
+	}
+	for i, b := range bps {
+		if b.watch == "" {
+			continue
+		}
+		tv := b.ptr.Deref()
+		if sameValue(tv, b.old) {
+			continue
+		}
+		b.hits++
+		debugOutput(m, fmt.Sprintf("Watchpoint %d: %s changed from %v to %v\n", i, b.watch, b.old, tv))
+		b.old = tv
+		return true
+	}
+	return false
+}
+
+// sameValue returns true if x and y are the same primitive values, or refer
+// to the same composite value.
+func sameValue(x, y TypedValue) (same bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			same = false // incomparable values
+		}
+	}()
+	return x.T == y.T && x.N == y.N && x.V == y.V
+}
+
+// debugOutput prints s to the debugger output, or sends it as a DAP event.
+func debugOutput(m *Machine, s string) {
+	if m.Debugger.dap != nil {
+		m.Debugger.dap.output(s)
+		return
+	}
+	fmt.Fprint(m.Debugger.out, s)
+}
+
 // debugCmd processes a debugger REPL command. It displays a prompt, then
 // reads and parses a command from the debugger input stream, then executes
 // the corresponding function or returns an error.
@@ -325,7 +478,7 @@ func debugUpdateLocation(m *Machine) {
 
 // ---------------------------------------
 const (
-	breakUsage = `break|b [locspec]`
+	breakUsage = `break|b [locspec] [if <condition>]`
 	breakShort = `Set a breakpoint.`
 	breakLong  = `
 The syntax accepted for locspec is:
@@ -333,22 +486,43 @@ The syntax accepted for locspec is:
 - <line> specifies the line in the current source file.
 - +<offset> specifies the line offset lines after the current one.
 - -<offset> specifies the line offset lines before the current one.
+
+If a condition is given, the program stops at the breakpoint only when the
+condition, a boolean expression evaluated in the current frame, is true.
 `
 )
 
 func debugBreak(m *Machine, arg string) error {
-	loc, err := parseLocSpec(m, arg)
+	spec, cond, _ := strings.Cut(arg, " if ")
+	loc, err := parseLocSpec(m, strings.TrimSpace(spec))
 	if err != nil {
 		return err
 	}
-	m.Debugger.breakpoints = append(m.Debugger.breakpoints, loc)
+	cond = strings.TrimSpace(cond)
+	if cond != "" {
+		if _, err := parser.ParseExpr(cond); err != nil {
+			return err
+		}
+	}
+	m.Debugger.breakpoints = append(m.Debugger.breakpoints, &debugBreakpoint{loc: loc, cond: cond})
 	printBreakpoint(m, len(m.Debugger.breakpoints)-1)
 	return nil
 }
 
 func printBreakpoint(m *Machine, i int) {
 	b := m.Debugger.breakpoints[i]
-	fmt.Fprintf(m.Debugger.out, "Breakpoint %d at %s %s\n", i, b.PkgPath, b)
+	if b.watch != "" {
+		fmt.Fprintf(m.Debugger.out, "Watchpoint %d on %s\n", i, b.watch)
+		return
+	}
+	s := fmt.Sprintf("Breakpoint %d at %s %s", i, b.loc.PkgPath, b.loc)
+	if b.cond != "" {
+		s += " if " + b.cond
+	}
+	if b.hitCond != "" {
+		s += " (hit count " + b.hitCond + ")"
+	}
+	fmt.Fprintln(m.Debugger.out, s)
 }
 
 func parseLocSpec(m *Machine, arg string) (loc Location, err error) {
@@ -404,8 +578,9 @@ const (
 )
 
 func debugBreakpoints(m *Machine, arg string) error {
-	for i := range m.Debugger.breakpoints {
+	for i, b := range m.Debugger.breakpoints {
 		printBreakpoint(m, i)
+		fmt.Fprintf(m.Debugger.out, "\thits: %d\n", b.hits)
 	}
 	return nil
 }
@@ -429,6 +604,56 @@ func debugClear(m *Machine, arg string) error {
 	return nil
 }
 
+// ---------------------------------------
+const (
+	conditionUsage = `condition|cond [-hitcount] <id> <condition>`
+	conditionShort = `Set the condition of a breakpoint.`
+	conditionLong  = `
+The program stops at the breakpoint only when the condition, a boolean
+expression evaluated in the current frame, is true. An empty condition
+removes it.
+
+With -hitcount, the condition applies to the number of times the breakpoint
+was hit, and is an integer preceded by one of the operators ==, !=, <, <=, >,
+>=, or by % to stop every n hits. For example:
+- condition 0 i > 10
+- condition -hitcount 0 > 5
+- condition -hitcount 0 % 2
+`
+)
+
+func debugCondition(m *Machine, arg string) error {
+	hitCount := false
+	if rest, ok := strings.CutPrefix(arg, "-hitcount"); ok {
+		hitCount, arg = true, trimLeftSpace(rest)
+	}
+	sid, cond, _ := strings.Cut(arg, " ")
+	cond = strings.TrimSpace(cond)
+	id, err := strconv.Atoi(sid)
+	if err != nil || id < 0 || id >= len(m.Debugger.breakpoints) || m.Debugger.breakpoints[id].watch != "" {
+		return fmt.Errorf("invalid breakpoint id: %v", sid)
+	}
+	b := m.Debugger.breakpoints[id]
+	switch {
+	case hitCount:
+		if cond != "" {
+			if _, err := evalHitCond(cond, 0); err != nil {
+				return err
+			}
+		}
+		b.hitCond = cond
+	case cond != "":
+		if _, err := parser.ParseExpr(cond); err != nil {
+			return err
+		}
+		b.cond = cond
+	default:
+		b.cond = ""
+	}
+	printBreakpoint(m, id)
+	return nil
+}
+
 // ---------------------------------------
 // NOTE: the difference between continue, next, step, stepi and stepout is handled within the Debug() loop.
 const (
@@ -626,12 +851,7 @@ func debugPrint(m *Machine, arg string) (err error) {
 	if arg == "" {
 		return errors.New("missing argument")
 	}
-	// Use the Go parser to get the AST representation of print argument as a Go expresssion.
-	ast, err := parser.ParseExpr(arg)
-	if err != nil {
-		return err
-	}
-	tv, err := debugEvalExpr(m, ast)
+	tv, err := debugEval(m, arg)
 	if err != nil {
 		return err
 	}
@@ -639,12 +859,33 @@ func debugPrint(m *Machine, arg string) (err error) {
 	return nil
 }
 
+// errExprNotSupported is returned by debugEvalExpr for expressions which
+// require debugEvalGno.
+var errExprNotSupported = errors.New("expression not supported")
+
+// debugEval evaluates the Go expression src in the context of the current
+// frame, and returns the corresponding typed value, or an error.
+// Names, selectors, index expressions and literals are evaluated directly
+// by debugEvalExpr, and any other expression by debugEvalGno.
+func debugEval(m *Machine, src string) (tv TypedValue, err error) {
+	// Use the Go parser to get the AST representation of src as a Go expresssion.
+	x, err := parser.ParseExpr(src)
+	if err != nil {
+		return tv, err
+	}
+	tv, err = debugEvalExpr(m, x)
+	if errors.Is(err, errExprNotSupported) {
+		return debugEvalGno(m, src)
+	}
+	return tv, err
+}
+
 // debugEvalExpr evaluates a Go expression in the context of the VM and returns
 // the corresponding typed value, or an error.
 // The supported expression syntax is a small subset of Go expressions:
 // basic literals, identifiers, selectors, index expressions, or a combination
-// of those are supported, but none of function calls, arithmetic, logic or
-// assign operations, type assertions of convertions.
+// of those are supported, which have no side effects. Other expressions
+// return errExprNotSupported.
 // This is sufficient for a debugger to perform 'print (*f).S[x][y]' for example.
 func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 	defer func() {
@@ -675,63 +916,225 @@ func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 			}
 			return typedString(s), nil
 		}
-		return tv, fmt.Errorf("invalid basic literal value: %s", n.Value)
+		return tv, errExprNotSupported
 	case *ast.Ident:
-		if tv, ok := debugLookup(m, n.Name); ok {
-			return tv, nil
+		if ptv, ok := debugLookup(m, n.Name); ok {
+			return *ptv, nil
 		}
 		return tv, fmt.Errorf("could not find symbol value for %s", n.Name)
 	case *ast.ParenExpr:
 		return debugEvalExpr(m, n.X)
+	case *ast.StarExpr, *ast.SelectorExpr, *ast.IndexExpr:
+		pv, err := debugEvalPointer(m, n)
+		if err != nil {
+			return tv, err
+		}
+		return pv.Deref(), nil
+	default:
+		return tv, fmt.Errorf("%w: %v", errExprNotSupported, n)
+	}
+}
+
+// debugEvalPointer returns a pointer to the value of a Go expression, which
+// is a name, a pointer indirection, a selector or an index expression.
+func debugEvalPointer(m *Machine, node ast.Node) (pv PointerValue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	switch n := node.(type) {
+	case *ast.Ident:
+		if ptv, ok := debugLookup(m, n.Name); ok {
+			return PointerValue{TV: ptv}, nil
+		}
+		return pv, fmt.Errorf("could not find symbol value for %s", n.Name)
+	case *ast.ParenExpr:
+		return debugEvalPointer(m, n.X)
 	case *ast.StarExpr:
 		x, err := debugEvalExpr(m, n.X)
 		if err != nil {
-			return tv, err
+			return pv, err
 		}
 		pv, ok := x.V.(PointerValue)
 		if !ok {
-			return tv, fmt.Errorf("Not a pointer value: %v", x)
+			return pv, fmt.Errorf("Not a pointer value: %v", x)
 		}
-		return pv.Deref(), nil
+		return pv, nil
 	case *ast.SelectorExpr:
 		x, err := debugEvalExpr(m, n.X)
 		if err != nil {
-			return tv, err
+			return pv, err
 		}
-		if pv, ok := x.V.(*PackageValue); ok {
-			if i, ok := pv.Block.(*Block).Source.GetLocalIndex(Name(n.Sel.Name)); ok {
-				return pv.Block.(*Block).Values[i], nil
+		if pkg, ok := x.V.(*PackageValue); ok {
+			b := pkg.Block.(*Block)
+			if i, ok := b.Source.GetLocalIndex(Name(n.Sel.Name)); ok {
+				return PointerValue{TV: &b.Values[i], Base: b, Index: int(i)}, nil
 			}
-			return tv, fmt.Errorf("invalid selector: %s", n.Sel.Name)
+			return pv, fmt.Errorf("invalid selector: %s", n.Sel.Name)
 		}
 		tr, _, _, _, _ := findEmbeddedFieldType(x.T.GetPkgPath(), x.T, Name(n.Sel.Name), nil)
 		if len(tr) == 0 {
-			return tv, fmt.Errorf("invalid selector: %s", n.Sel.Name)
+			return pv, fmt.Errorf("invalid selector: %s", n.Sel.Name)
 		}
-		for _, vp := range tr {
-			x = x.GetPointerToFromTV(m.Alloc, m.Store, vp).Deref()
+		for i, vp := range tr {
+			if i > 0 {
+				x = pv.Deref()
+			}
+			pv = x.GetPointerToFromTV(m.Alloc, m.Store, vp)
 		}
-		return x, nil
+		return pv, nil
 	case *ast.IndexExpr:
 		x, err := debugEvalExpr(m, n.X)
 		if err != nil {
-			return tv, err
+			return pv, err
 		}
 		index, err := debugEvalExpr(m, n.Index)
 		if err != nil {
-			return tv, err
+			return pv, err
 		}
-		return x.GetPointerAtIndex(m.Alloc, m.Store, &index).Deref(), nil
+		return x.GetPointerAtIndex(m.Alloc, m.Store, &index), nil
 	default:
-		err = fmt.Errorf("expression not supported: %v", n)
+		return pv, fmt.Errorf("%w: %v", errExprNotSupported, n)
 	}
-	return tv, err
 }
 
-// debugLookup returns the current VM value corresponding to name ident in
-// the current function call frame, or the global frame if not found.
+// debugEvalGno evaluates the Go expression src in the context of the current
+// frame with the machine, after preprocessing it like Gno code, so that any
+// expression can be evaluated, including function calls and operators. The
+// debugger and coverage are disabled during the evaluation, and the machine
+// state is restored if it panics.
+func debugEvalGno(m *Machine, src string) (tv TypedValue, err error) {
+	x, err := ParseExpr(src)
+	if err != nil {
+		return tv, err
+	}
+	var b *Block
+	if blocks := debugFrameBlocks(m, m.Debugger.frameLevel); len(blocks) > 0 {
+		b = blocks[0]
+	} else if len(m.Blocks) > 0 {
+		b = m.LastBlock()
+	} else {
+		return tv, errors.New("no block to evaluate the expression in")
+	}
+
+	saved := debugSaveMachine(m)
+	enabled, cov := m.Debugger.enabled, m.Coverage
+	m.Debugger.enabled, m.Coverage = false, nil
+	defer func() {
+		m.Debugger.enabled, m.Coverage = enabled, cov
+		if r := recover(); r != nil {
+			saved.restore(m)
+			// Strip the preprocess stack from preprocessing errors.
+			msg, _, _ := strings.Cut(fmt.Sprint(r), "\n--- preprocess stack ---")
+			err = errors.New(strings.TrimSuffix(msg, ":"))
+		}
+	}()
+
+	x = Preprocess(m.Store, b.GetSource(m.Store), x).(Expr)
+	m.PushBlock(b)
+	start := m.NumValues
+	m.PushOp(OpHalt)
+	m.PushExpr(x)
+	m.PushOp(OpEval)
+	m.Run(StageRun)
+	res := m.ReapValues(start)
+	m.PopBlock()
+	if len(res) != 1 {
+		return tv, fmt.Errorf("expression has %d values", len(res))
+	}
+	tv = res[0]
+	if isUntyped(tv.T) {
+		ConvertUntypedTo(&tv, defaultTypeOf(tv.T))
+	}
+	return tv, nil
+}
+
+// debugMachineState is a copy of the stacks of a machine.
+type debugMachineState struct {
+	ops       []Op
+	values    []TypedValue
+	exprs     []Expr
+	stmts     []Stmt
+	blocks    []*Block
+	frames    []Frame
+	pkg       *PackageValue
+	realm     *Realm
+	exception *Exception
+}
+
+func debugSaveMachine(m *Machine) *debugMachineState {
+	return &debugMachineState{
+		ops:       slices.Clone(m.Ops[:m.NumOps]),
+		values:    slices.Clone(m.Values[:m.NumValues]),
+		exprs:     slices.Clone(m.Exprs),
+		stmts:     slices.Clone(m.Stmts),
+		blocks:    slices.Clone(m.Blocks),
+		frames:    slices.Clone(m.Frames),
+		pkg:       m.Package,
+		realm:     m.Realm,
+		exception: m.Exception,
+	}
+}
+
+func (s *debugMachineState) restore(m *Machine) {
+	m.NumOps = copy(m.Ops, s.ops)
+	m.NumValues = copy(m.Values, s.values)
+	m.Exprs = append(m.Exprs[:0], s.exprs...)
+	m.Stmts = append(m.Stmts[:0], s.stmts...)
+	m.Blocks = append(m.Blocks[:0], s.blocks...)
+	m.Frames = append(m.Frames[:0], s.frames...)
+	m.Package = s.pkg
+	m.Realm = s.realm
+	m.Exception = s.exception
+}
+
+// debugLookup returns a pointer to the current VM value corresponding to name
+// ident in the current function call frame, or the global frame if not found.
 // Note: the commands 'up' and 'down' change the frame level to start from.
-func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
+func debugLookup(m *Machine, name string) (tv *TypedValue, ok bool) {
+	sblocks := debugFrameBlocks(m, m.Debugger.frameLevel)
+	if len(sblocks) == 0 {
+		return tv, false
+	}
+
+	// Search value in current frame level blocks, or main scope.
+	for _, b := range sblocks {
+		switch t := b.Source.(type) {
+		case *IfStmt:
+			for i, s := range ifBody(m, t).Source.GetBlockNames() {
+				if string(s) == name {
+					return debugBlockValue(b, i), true
+				}
+			}
+		}
+		for i, s := range b.Source.GetBlockNames() {
+			if string(s) == name {
+				return debugBlockValue(b, i), true
+			}
+		}
+	}
+	// Fallback: search a global value.
+	if v := sblocks[0].Source.GetSlot(m.Store, Name(name), true); v != nil {
+		return v, true
+	}
+	return tv, false
+}
+
+// debugBlockValue returns a pointer to the value i of block b, which is in a
+// heap item if it is captured by a closure.
+func debugBlockValue(b *Block, i int) *TypedValue {
+	tv := &b.Values[i]
+	if hiv, ok := tv.V.(*HeapItemValue); ok {
+		return &hiv.Value
+	}
+	return tv
+}
+
+// debugFrameBlocks returns the blocks of the function call frame at the given
+// level, from the innermost one, followed by the global block.
+func debugFrameBlocks(m *Machine, level int) []*Block {
 	// Position to the right frame.
 	ncall := 0
 	var i int
@@ -741,7 +1144,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		if m.Frames[i].Func != nil {
 			funBlock = m.Frames[i].Func.Source
 		}
-		if ncall == m.Debugger.frameLevel {
+		if ncall == level {
 			break
 		}
 		if m.Frames[i].Func != nil {
@@ -750,7 +1153,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// XXX The following logic isn't necessary and it isn't correct either.
@@ -784,7 +1187,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// get SourceBlocks in the same frame level.
@@ -798,28 +1201,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 	if i > 0 {
 		sblocks = append(sblocks, m.Blocks[0]) // Add global block
 	}
-
-	// Search value in current frame level blocks, or main scope.
-	for _, b := range sblocks {
-		switch t := b.Source.(type) {
-		case *IfStmt:
-			for i, s := range ifBody(m, t).Source.GetBlockNames() {
-				if string(s) == name {
-					return b.Values[i], true
-				}
-			}
-		}
-		for i, s := range b.Source.GetBlockNames() {
-			if string(s) == name {
-				return b.Values[i], true
-			}
-		}
-	}
-	// Fallback: search a global value.
-	if v := sblocks[0].Source.GetSlot(m.Store, Name(name), true); v != nil {
-		return *v, true
-	}
-	return tv, false
+	return sblocks
 }
 
 // ifBody returns the Then or Else body corresponding to the current location.
@@ -844,18 +1226,20 @@ func debugStack(m *Machine, arg string) error {
 		if ff == nil {
 			break
 		}
-		var fname string
-		if ff.IsMethod {
-			fname = fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, ff.Name)
-		} else {
-			fname = fmt.Sprintf("%v.%v", ff.PkgPath, ff.Name)
-		}
-		fmt.Fprintf(m.Debugger.out, "%d\tin %s\n\tat %s\n", i, fname, loc)
+		fmt.Fprintf(m.Debugger.out, "%d\tin %s\n\tat %s\n", i, debugFrameName(ff), loc)
 		i++
 	}
 	return nil
 }
 
+// debugFrameName returns the qualified name of the function of a frame.
+func debugFrameName(ff *FuncValue) string {
+	if ff.IsMethod {
+		return fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, ff.Name)
+	}
+	return fmt.Sprintf("%v.%v", ff.PkgPath, ff.Name)
+}
+
 func debugFrameFunc(m *Machine, n int) *FuncValue {
 	for ncall, i := 0, len(m.Frames)-1; i >= 0; i-- {
 		f := m.Frames[i]
@@ -877,6 +1261,38 @@ func debugFrameLoc(m *Machine, n int) Location {
 	return m.Debugger.call[len(m.Debugger.call)-n]
 }
 
+// ---------------------------------------
+const (
+	watchUsage = `watch <expression>`
+	watchShort = `Set a watchpoint.`
+	watchLong  = `
+The program stops when the value of the expression changes, which must be a
+variable, a struct field, like the field of a realm object, an element or a
+pointer indirection. For composite values, only a change of the value itself,
+not of its elements, is detected.
+`
+)
+
+func debugWatch(m *Machine, arg string) error {
+	if arg == "" {
+		return errors.New("missing argument")
+	}
+	x, err := parser.ParseExpr(arg)
+	if err != nil {
+		return err
+	}
+	pv, err := debugEvalPointer(m, x)
+	if errors.Is(err, errExprNotSupported) {
+		return fmt.Errorf("cannot watch expression: %s", arg)
+	}
+	if err != nil {
+		return err
+	}
+	m.Debugger.breakpoints = append(m.Debugger.breakpoints, &debugBreakpoint{watch: arg, ptr: pv, old: pv.Deref()})
+	printBreakpoint(m, len(m.Debugger.breakpoints)-1)
+	return nil
+}
+
 // ---------------------------------------
 const (
 	upUsage = `up [n]`
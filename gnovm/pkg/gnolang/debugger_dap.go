@@ -0,0 +1,724 @@
+package gnolang
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DAPSession is a debugger session with a client of the Debug Adapter
+// Protocol (DAP), such as an IDE, as an alternative to the command line
+// interface of the debugger.
+//
+// DAP requests are mapped onto the debugger states: the session handles
+// requests while the debugger is in DebugAtCmd, and the continue, next,
+// stepIn and stepOut requests move it to DebugAtRun, as the corresponding
+// commands do. A session may span several machines, like when running the
+// tests of a package, and keeps its breakpoints between them.
+//
+// See https://microsoft.github.io/debug-adapter-protocol/specification.
+type DAPSession struct {
+	conn io.ReadWriteCloser
+	r    *bufio.Reader
+	seq  int // sequence number of the last message sent
+
+	configured  bool                          // configurationDone was received
+	stopOnEntry bool                          // from the launch or attach request
+	detached    bool                          // the client disconnected
+	breakpoints map[string][]*debugBreakpoint // by absolute source path
+	watchpoints []*debugBreakpoint            // data breakpoints
+	dirs        map[string]string
+	paths       map[dapSource]string // cache of resolved source paths
+	vars        []dapVar             // variable references, reset at each stop
+}
+
+// dapSource identifies a source file of a machine location.
+type dapSource struct{ pkgPath, file string }
+
+// dapVar is the target of a variable reference, which is either the locals
+// or the globals of a frame, or a value to expand.
+type dapVar struct {
+	frame int
+	scope string // "locals", "globals" or "" for a value.
+	tv    TypedValue
+}
+
+const dapThreadID = 1 // the GnoVM runs a single thread of execution.
+
+// NewDAPSession returns a DAP session using conn to communicate with the
+// client.
+func NewDAPSession(conn io.ReadWriteCloser) *DAPSession {
+	return &DAPSession{
+		conn:        conn,
+		r:           bufio.NewReader(conn),
+		breakpoints: map[string][]*debugBreakpoint{},
+		dirs:        map[string]string{},
+		paths:       map[dapSource]string{},
+	}
+}
+
+// ServeDAP waits for a DAP client to connect to addr, and returns a session
+// using this connection. It returns an error if the connection can not be
+// established.
+func ServeDAP(addr string) (*DAPSession, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+	print("Waiting for DAP client to connect at ", addr)
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	println(" connected!")
+	return NewDAPSession(conn), nil
+}
+
+// SetPackageDir sets the directory of the source files of the package at
+// pkgPath, for packages which are not in the gno root directory, so that
+// their breakpoints and stack frames can be mapped to source paths.
+func (s *DAPSession) SetPackageDir(pkgPath, dir string) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	s.dirs[pkgPath] = dir
+	clear(s.paths)
+}
+
+// Close ends the session, after notifying the client that the program
+// terminated.
+func (s *DAPSession) Close() error {
+	if !s.detached {
+		s.event("terminated", nil)
+	}
+	return s.conn.Close()
+}
+
+// EnableDAP makes the debugger d active, using the DAP session s for IO and
+// f as a source helper.
+func (d *Debugger) EnableDAP(s *DAPSession, f func(string, string) string) {
+	if s.detached {
+		return
+	}
+	d.Enable(nil, nil, f)
+	d.dap = s
+}
+
+// ----------------------------------------
+// Messages
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type dapResponse struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+type dapSourceBody struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type dapStackFrame struct {
+	ID     int            `json:"id"`
+	Name   string         `json:"name"`
+	Source *dapSourceBody `json:"source,omitempty"`
+	Line   int            `json:"line"`
+	Column int            `json:"column"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// read reads the next request from the client.
+func (s *DAPSession) read() (*dapRequest, error) {
+	tp := textproto.NewReader(s.r)
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		return nil, err
+	}
+	req := &dapRequest{}
+	if err := json.Unmarshal(buf, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// send sends a message to the client, ignoring errors: a broken connection
+// is detected when reading the next request.
+func (s *DAPSession) send(msg any) {
+	buf, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(s.conn, "Content-Length: %d\r\n\r\n%s", len(buf), buf)
+}
+
+func (s *DAPSession) respond(req *dapRequest, body any) {
+	s.seq++
+	s.send(dapResponse{
+		Seq:        s.seq,
+		Type:       "response",
+		RequestSeq: req.Seq,
+		Success:    true,
+		Command:    req.Command,
+		Body:       body,
+	})
+}
+
+func (s *DAPSession) respondErr(req *dapRequest, err error) {
+	s.seq++
+	s.send(dapResponse{
+		Seq:        s.seq,
+		Type:       "response",
+		RequestSeq: req.Seq,
+		Command:    req.Command,
+		Message:    err.Error(),
+	})
+}
+
+func (s *DAPSession) event(name string, body any) {
+	s.seq++
+	s.send(dapEvent{
+		Seq:   s.seq,
+		Type:  "event",
+		Event: name,
+		Body:  body,
+	})
+}
+
+// output sends debugger output to the client console.
+func (s *DAPSession) output(out string) {
+	s.event("output", map[string]any{"category": "console", "output": out})
+}
+
+// ----------------------------------------
+// Debugger states
+
+// init is called in DebugAtInit. The first machine of the session awaits the
+// configuration of the client, while the following ones start running.
+func (s *DAPSession) init(m *Machine) {
+	if s.configured {
+		m.Debugger.lastCmd = "continue"
+		debugContinue(m, "")
+		return
+	}
+	m.Debugger.state = DebugAtCmd
+}
+
+// stopped is called when the debugger enters DebugAtCmd while running.
+func (s *DAPSession) stopped(reason string) {
+	s.vars = s.vars[:0]
+	s.event("stopped", map[string]any{
+		"reason":            reason,
+		"threadId":          dapThreadID,
+		"allThreadsStopped": true,
+	})
+}
+
+// atBreak returns true if loc matches a breakpoint.
+func (s *DAPSession) atBreak(m *Machine, loc Location) bool {
+	for _, b := range s.breakpoints[s.sourcePath(m, loc)] {
+		if b.loc.Line != loc.Line {
+			continue
+		}
+		hit, err := b.hit(m)
+		if err != nil {
+			s.output(fmt.Sprintf("Breakpoint condition failed: %v\n", err))
+		}
+		if hit {
+			return true
+		}
+	}
+	return false
+}
+
+// sourcePath returns the absolute path of the source file of loc, or an empty
+// string if it can't be found.
+func (s *DAPSession) sourcePath(m *Machine, loc Location) string {
+	if loc.File == "" {
+		return ""
+	}
+	key := dapSource{loc.PkgPath, loc.File}
+	if p, ok := s.paths[key]; ok {
+		return p
+	}
+	var candidates []string
+	if filepath.IsAbs(loc.File) {
+		candidates = append(candidates, loc.File)
+	}
+	if dir, ok := s.dirs[loc.PkgPath]; ok {
+		candidates = append(candidates, filepath.Join(dir, loc.File))
+	}
+	if root := m.Debugger.rootDir; root != "" && filepath.Base(loc.File) == loc.File {
+		candidates = append(candidates,
+			filepath.Join(root, loc.PkgPath, loc.File),
+			filepath.Join(root, "gnovm", "stdlibs", loc.PkgPath, loc.File),
+			filepath.Join(root, "examples", loc.PkgPath, loc.File))
+	}
+	candidates = append(candidates, loc.File)
+	p := ""
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			p, _ = filepath.Abs(c)
+			break
+		}
+	}
+	s.paths[key] = p
+	return p
+}
+
+// cmd is called in DebugAtCmd. It handles the next request of the client.
+func (s *DAPSession) cmd(m *Machine) {
+	req, err := s.read()
+	if err != nil {
+		// The client is gone, the target program resumes.
+		s.detached = true
+		m.Debugger.enabled = false
+		m.Debugger.state = DebugAtRun
+		return
+	}
+	var body any
+	switch req.Command {
+	case "initialize":
+		body = map[string]any{
+			"supportsConfigurationDoneRequest":  true,
+			"supportsEvaluateForHovers":         true,
+			"supportsConditionalBreakpoints":    true,
+			"supportsHitConditionalBreakpoints": true,
+			"supportsDataBreakpoints":           true,
+		}
+		s.respond(req, body)
+		s.event("initialized", nil)
+		return
+	case "launch", "attach":
+		var args struct {
+			StopOnEntry bool `json:"stopOnEntry"`
+		}
+		_ = json.Unmarshal(req.Arguments, &args)
+		s.stopOnEntry = args.StopOnEntry
+	case "setBreakpoints":
+		body, err = s.setBreakpoints(m, req)
+	case "setExceptionBreakpoints":
+	case "dataBreakpointInfo":
+		body, err = s.dataBreakpointInfo(req)
+	case "setDataBreakpoints":
+		body, err = s.setDataBreakpoints(m, req)
+	case "configurationDone":
+		s.configured = true
+		s.respond(req, nil)
+		if s.stopOnEntry {
+			s.stopped("entry")
+			return
+		}
+		m.Debugger.lastCmd = "continue"
+		debugContinue(m, "")
+		return
+	case "threads":
+		body = map[string]any{
+			"threads": []map[string]any{{"id": dapThreadID, "name": "main"}},
+		}
+	case "stackTrace":
+		body = s.stackTrace(m)
+	case "scopes":
+		body, err = s.scopes(m, req)
+	case "variables":
+		body, err = s.variables(m, req)
+	case "evaluate":
+		body, err = s.evaluate(m, req)
+	case "continue", "next", "stepIn", "stepOut":
+		m.Debugger.lastCmd = map[string]string{
+			"continue": "continue",
+			"next":     "next",
+			"stepIn":   "step",
+			"stepOut":  "stepout",
+		}[req.Command]
+		debugContinue(m, "")
+		if req.Command == "continue" {
+			body = map[string]any{"allThreadsContinued": true}
+		}
+	case "disconnect":
+		var args struct {
+			TerminateDebuggee bool `json:"terminateDebuggee"`
+		}
+		_ = json.Unmarshal(req.Arguments, &args)
+		s.respond(req, nil)
+		if args.TerminateDebuggee {
+			s.Close()
+			m.Debugger.state = DebugAtExit
+			return
+		}
+		s.detached = true
+		m.Debugger.enabled = false
+		m.Debugger.state = DebugAtRun
+		return
+	default:
+		err = errors.New("unsupported request: " + req.Command)
+	}
+	if err != nil {
+		s.respondErr(req, err)
+		return
+	}
+	s.respond(req, body)
+}
+
+// ----------------------------------------
+// Requests
+
+func (s *DAPSession) setBreakpoints(m *Machine, req *dapRequest) (any, error) {
+	var args struct {
+		Source      dapSourceBody `json:"source"`
+		Breakpoints []struct {
+			Line         int    `json:"line"`
+			Condition    string `json:"condition"`
+			HitCondition string `json:"hitCondition"`
+		} `json:"breakpoints"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, err
+	}
+	p, err := filepath.Abs(args.Source.Path)
+	if err != nil {
+		return nil, err
+	}
+	var breakpoints []*debugBreakpoint
+	bps := make([]map[string]any, 0, len(args.Breakpoints))
+	for i, b := range args.Breakpoints {
+		bp := map[string]any{"id": i, "verified": true, "line": b.Line}
+		err := error(nil)
+		if b.Condition != "" {
+			_, err = parser.ParseExpr(b.Condition)
+		}
+		if err == nil && b.HitCondition != "" {
+			_, err = evalHitCond(b.HitCondition, 0)
+		}
+		if err != nil {
+			bp["verified"], bp["message"] = false, err.Error()
+		} else {
+			breakpoints = append(breakpoints, &debugBreakpoint{
+				loc:     Location{File: p, Span: Span{Pos: Pos{Line: b.Line}}},
+				cond:    b.Condition,
+				hitCond: b.HitCondition,
+			})
+		}
+		bps = append(bps, bp)
+	}
+	s.breakpoints[p] = breakpoints
+	return map[string]any{"breakpoints": bps}, nil
+}
+
+func (s *DAPSession) dataBreakpointInfo(req *dapRequest) (any, error) {
+	var args struct {
+		VariablesReference int    `json:"variablesReference"`
+		Name               string `json:"name"`
+		FrameID            int    `json:"frameId"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, err
+	}
+	frame := args.FrameID
+	if ref := args.VariablesReference; ref > 0 {
+		if ref > len(s.vars) || s.vars[ref-1].scope == "" {
+			// XXX: support the elements of values.
+			return map[string]any{"dataId": nil, "description": "cannot watch an element of a value"}, nil
+		}
+		frame = s.vars[ref-1].frame
+	}
+	return map[string]any{
+		"dataId":      strconv.Itoa(frame) + ":" + args.Name,
+		"description": args.Name,
+		"accessTypes": []string{"write"},
+	}, nil
+}
+
+func (s *DAPSession) setDataBreakpoints(m *Machine, req *dapRequest) (any, error) {
+	var args struct {
+		Breakpoints []struct {
+			DataID string `json:"dataId"`
+		} `json:"breakpoints"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, err
+	}
+	s.watchpoints = nil
+	bps := make([]map[string]any, 0, len(args.Breakpoints))
+	for _, b := range args.Breakpoints {
+		pv, err := s.watchPointer(m, b.DataID)
+		if err != nil {
+			bps = append(bps, map[string]any{"verified": false, "message": err.Error()})
+			continue
+		}
+		_, expr, _ := strings.Cut(b.DataID, ":")
+		s.watchpoints = append(s.watchpoints, &debugBreakpoint{watch: expr, ptr: pv, old: pv.Deref()})
+		bps = append(bps, map[string]any{"verified": true})
+	}
+	return map[string]any{"breakpoints": bps}, nil
+}
+
+// watchPointer returns a pointer to the value of the data breakpoint dataID,
+// which is an expression evaluated in a frame, as "<frame>:<expression>".
+func (s *DAPSession) watchPointer(m *Machine, dataID string) (PointerValue, error) {
+	sframe, expr, _ := strings.Cut(dataID, ":")
+	frame, err := strconv.Atoi(sframe)
+	if err != nil {
+		return PointerValue{}, fmt.Errorf("invalid data id: %s", dataID)
+	}
+	x, err := parser.ParseExpr(expr)
+	if err != nil {
+		return PointerValue{}, err
+	}
+	level := m.Debugger.frameLevel
+	m.Debugger.frameLevel = frame
+	defer func() { m.Debugger.frameLevel = level }()
+	return debugEvalPointer(m, x)
+}
+
+func (s *DAPSession) stackTrace(m *Machine) any {
+	frames := []dapStackFrame{}
+	for i := 0; ; i++ {
+		ff := debugFrameFunc(m, i)
+		if ff == nil {
+			break
+		}
+		loc := debugFrameLoc(m, i)
+		frame := dapStackFrame{
+			ID:     i,
+			Name:   debugFrameName(ff),
+			Line:   loc.Line,
+			Column: loc.Column,
+		}
+		if loc.File != "" {
+			frame.Source = &dapSourceBody{
+				Name: filepath.Base(loc.File),
+				Path: s.sourcePath(m, loc),
+			}
+		}
+		frames = append(frames, frame)
+	}
+	return map[string]any{"stackFrames": frames, "totalFrames": len(frames)}
+}
+
+func (s *DAPSession) scopes(m *Machine, req *dapRequest) (any, error) {
+	var args struct {
+		FrameID int `json:"frameId"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, err
+	}
+	if debugFrameFunc(m, args.FrameID) == nil {
+		return nil, fmt.Errorf("invalid frame id: %d", args.FrameID)
+	}
+	s.vars = append(s.vars, dapVar{frame: args.FrameID, scope: "locals"})
+	locals := len(s.vars)
+	s.vars = append(s.vars, dapVar{frame: args.FrameID, scope: "globals"})
+	globals := len(s.vars)
+	return map[string]any{"scopes": []map[string]any{
+		{"name": "Locals", "presentationHint": "locals", "variablesReference": locals, "expensive": false},
+		{"name": "Globals", "variablesReference": globals, "expensive": false},
+	}}, nil
+}
+
+func (s *DAPSession) variables(m *Machine, req *dapRequest) (any, error) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, err
+	}
+	if args.VariablesReference <= 0 || args.VariablesReference > len(s.vars) {
+		return nil, fmt.Errorf("invalid variables reference: %d", args.VariablesReference)
+	}
+	v := s.vars[args.VariablesReference-1]
+	vars := []dapVariable{}
+	add := func(name string, tv TypedValue) {
+		vars = append(vars, s.variable(m, name, tv))
+	}
+	switch v.scope {
+	case "locals":
+		seen := map[Name]bool{}
+		for _, b := range debugFrameBlocks(m, v.frame) {
+			switch b.Source.(type) {
+			case *FileNode, *PackageNode:
+				continue
+			}
+			for i, n := range b.Source.GetBlockNames() {
+				if seen[n] || i >= len(b.Values) || !dapVisible(n) {
+					continue
+				}
+				seen[n] = true
+				add(string(n), b.Values[i])
+			}
+		}
+	case "globals":
+		ff := debugFrameFunc(m, v.frame)
+		b := m.Store.GetPackage(ff.PkgPath, false).GetBlock(m.Store)
+		for i, n := range b.Source.GetBlockNames() {
+			if i >= len(b.Values) || !dapVisible(n) {
+				continue
+			}
+			tv := b.Values[i]
+			if tv.T == nil || tv.T.Kind() == TypeKind {
+				continue
+			}
+			if fv, ok := tv.V.(*FuncValue); ok && fv.Name == n {
+				continue // function declaration
+			}
+			add(string(n), tv)
+		}
+	default:
+		err := dapExpand(m, v.tv, add)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return map[string]any{"variables": vars}, nil
+}
+
+func (s *DAPSession) evaluate(m *Machine, req *dapRequest) (any, error) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameID    int    `json:"frameId"`
+	}
+	if err := json.Unmarshal(req.Arguments, &args); err != nil {
+		return nil, err
+	}
+	level := m.Debugger.frameLevel
+	m.Debugger.frameLevel = args.FrameID
+	tv, err := debugEval(m, args.Expression)
+	m.Debugger.frameLevel = level
+	if err != nil {
+		return nil, err
+	}
+	v := s.variable(m, "", tv)
+	return map[string]any{
+		"result":             v.Value,
+		"type":               v.Type,
+		"variablesReference": v.VariablesReference,
+	}, nil
+}
+
+// ----------------------------------------
+// Variables
+
+// variable returns the DAP variable of tv, with a new variable reference if
+// it can be expanded.
+func (s *DAPSession) variable(m *Machine, name string, tv TypedValue) dapVariable {
+	if hiv, ok := tv.V.(*HeapItemValue); ok {
+		tv = hiv.Value
+	}
+	v := dapVariable{Name: name, Value: dapValueString(tv)}
+	if tv.T != nil {
+		v.Type = tv.T.String()
+	}
+	if dapExpand(m, tv, nil) == nil {
+		s.vars = append(s.vars, dapVar{tv: tv})
+		v.VariablesReference = len(s.vars)
+	}
+	return v
+}
+
+func dapVisible(n Name) bool {
+	return n != "" && n != blankIdentifier && !strings.HasPrefix(string(n), ".")
+}
+
+// dapValueString returns the value of tv, as shown by the client.
+func dapValueString(tv TypedValue) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = tv.String()
+		}
+	}()
+	if tv.T == nil {
+		return nilStr
+	}
+	if tv.T.Kind() == StringKind {
+		return strconv.Quote(tv.GetString())
+	}
+	return tv.ProtectedSprint(newSeenValues(), false)
+}
+
+// dapExpand calls add with each element of tv, if tv is a non-nil pointer,
+// struct, array, slice or map. Otherwise, it returns an error. If add is nil,
+// it only checks whether tv can be expanded.
+func dapExpand(m *Machine, tv TypedValue, add func(string, TypedValue)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	if tv.T == nil || tv.V == nil {
+		return errors.New("nil value")
+	}
+	if add == nil {
+		switch tv.T.Kind() {
+		case PointerKind, StructKind, ArrayKind, SliceKind, MapKind:
+			return nil
+		}
+		return errors.New("not expandable")
+	}
+	tv = *fillValueTV(m.Store, &tv)
+	switch bt := baseOf(tv.T).(type) {
+	case *PointerType:
+		x := tv.V.(PointerValue).Deref()
+		if x.T != nil && x.T.Kind() == StructKind {
+			return dapExpand(m, x, add)
+		}
+		add("*", x)
+	case *StructType:
+		sv := tv.V.(*StructValue)
+		for i, f := range bt.Fields {
+			add(string(f.Name), sv.GetPointerToInt(m.Store, i).Deref())
+		}
+	case *ArrayType, *SliceType:
+		for i := range tv.GetLength() {
+			index := typedInt(i)
+			add(fmt.Sprintf("[%d]", i), tv.GetPointerAtIndex(m.Alloc, m.Store, &index).Deref())
+		}
+	case *MapType:
+		for cur := tv.V.(*MapValue).List.Head; cur != nil; cur = cur.Next {
+			add("["+dapValueString(cur.Key)+"]", *fillValueTV(m.Store, &cur.Value))
+		}
+	default:
+		return errors.New("not expandable")
+	}
+	return nil
+}
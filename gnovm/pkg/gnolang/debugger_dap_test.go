@@ -0,0 +1,277 @@
+package gnolang_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/test"
+)
+
+type dapClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *textproto.Reader
+	br   *bufio.Reader
+	seq  int
+}
+
+type dapMsg struct {
+	Type       string          `json:"type"`
+	Command    string          `json:"command"`
+	Event      string          `json:"event"`
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+func (c *dapClient) read() dapMsg {
+	c.t.Helper()
+	header, err := c.r.ReadMIMEHeader()
+	require.NoError(c.t, err)
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	require.NoError(c.t, err)
+	buf := make([]byte, n)
+	_, err = io.ReadFull(c.br, buf)
+	require.NoError(c.t, err)
+	var msg dapMsg
+	require.NoError(c.t, json.Unmarshal(buf, &msg))
+	return msg
+}
+
+// request sends a request and returns its response, and the events received
+// before it.
+func (c *dapClient) request(cmd string, args any) (dapMsg, []string) {
+	c.t.Helper()
+	c.seq++
+	buf, err := json.Marshal(map[string]any{"seq": c.seq, "type": "request", "command": cmd, "arguments": args})
+	require.NoError(c.t, err)
+	fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(buf), buf)
+	var events []string
+	for {
+		msg := c.read()
+		if msg.Type == "event" {
+			events = append(events, msg.Event)
+			continue
+		}
+		require.Equal(c.t, c.seq, msg.RequestSeq)
+		require.Equal(c.t, cmd, msg.Command)
+		return msg, events
+	}
+}
+
+// event waits for the named event.
+func (c *dapClient) event(name string) dapMsg {
+	c.t.Helper()
+	for {
+		msg := c.read()
+		if msg.Type == "event" && msg.Event == name {
+			return msg
+		}
+	}
+}
+
+func runDAPTest(t *testing.T, file string, conn net.Conn) (out string) {
+	t.Helper()
+
+	bout := bytes.NewBufferString("")
+	output := test.OutputWithError(writeNopCloser{bout}, writeNopCloser{io.Discard})
+	_, testStore := test.Store(gnoenv.RootDir(), output)
+	f := gnolang.MustReadFile(file)
+	m := gnolang.NewMachineWithOptions(gnolang.MachineOptions{
+		PkgPath: string(f.PkgName),
+		Output:  output,
+		Store:   testStore,
+		Context: test.Context(test.DefaultCaller, string(f.PkgName), nil),
+	})
+	defer m.Release()
+
+	s := gnolang.NewDAPSession(conn)
+	defer s.Close()
+	m.Debugger.EnableDAP(s, nil)
+	m.RunFiles(f)
+	ex, _ := gnolang.ParseExpr("main()")
+	m.Eval(ex)
+	return bout.String()
+}
+
+func TestDAP(t *testing.T) {
+	server, client := net.Pipe()
+	done := make(chan string)
+	go func() { done <- runDAPTest(t, debugTarget, server) }()
+
+	c := &dapClient{t: t, conn: client, br: bufio.NewReader(client)}
+	c.r = textproto.NewReader(c.br)
+	target, err := filepath.Abs(debugTarget)
+	require.NoError(t, err)
+
+	resp, events := c.request("initialize", map[string]any{"adapterID": "gno"})
+	assert.True(t, resp.Success)
+	assert.Contains(t, string(resp.Body), "supportsConfigurationDoneRequest")
+	if len(events) == 0 {
+		c.event("initialized")
+	}
+	resp, _ = c.request("launch", map[string]any{})
+	assert.True(t, resp.Success)
+	resp, _ = c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []map[string]any{{"line": 7}},
+	})
+	assert.True(t, resp.Success)
+	assert.JSONEq(t, `{"breakpoints":[{"id":0,"line":7,"verified":true}]}`, string(resp.Body))
+	resp, _ = c.request("configurationDone", nil)
+	assert.True(t, resp.Success)
+
+	// Stopped at the breakpoint.
+	stopped := c.event("stopped")
+	assert.Contains(t, string(stopped.Body), `"reason":"breakpoint"`)
+
+	resp, _ = c.request("threads", nil)
+	assert.JSONEq(t, `{"threads":[{"id":1,"name":"main"}]}`, string(resp.Body))
+
+	resp, _ = c.request("stackTrace", map[string]any{"threadId": 1})
+	var st struct {
+		StackFrames []struct {
+			ID     int
+			Name   string
+			Line   int
+			Source struct{ Name, Path string }
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Body, &st))
+	require.Len(t, st.StackFrames, 3)
+	assert.Equal(t, "main.f", st.StackFrames[0].Name)
+	assert.Equal(t, 7, st.StackFrames[0].Line)
+	assert.Equal(t, target, st.StackFrames[0].Source.Path)
+	assert.Equal(t, "main.main", st.StackFrames[2].Name)
+
+	resp, _ = c.request("scopes", map[string]any{"frameId": 0})
+	var scopes struct {
+		Scopes []struct {
+			Name               string
+			VariablesReference int
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Body, &scopes))
+	require.Len(t, scopes.Scopes, 2)
+	assert.Equal(t, "Locals", scopes.Scopes[0].Name)
+
+	resp, _ = c.request("variables", map[string]any{"variablesReference": scopes.Scopes[0].VariablesReference})
+	assert.Contains(t, string(resp.Body), `{"name":"name","value":"\"hello\"","type":"string","variablesReference":0}`)
+	assert.Contains(t, string(resp.Body), `{"name":"i","value":"3","type":"int","variablesReference":0}`)
+
+	resp, _ = c.request("variables", map[string]any{"variablesReference": scopes.Scopes[1].VariablesReference})
+	assert.Contains(t, string(resp.Body), `{"name":"global","value":"\"test\"","type":"string","variablesReference":0}`)
+
+	resp, _ = c.request("evaluate", map[string]any{"expression": "i", "frameId": 0})
+	assert.JSONEq(t, `{"result":"3","type":"int","variablesReference":0}`, string(resp.Body))
+	resp, _ = c.request("evaluate", map[string]any{"expression": "i*2 + len(name)", "frameId": 0})
+	assert.JSONEq(t, `{"result":"11","type":"int","variablesReference":0}`, string(resp.Body))
+	resp, _ = c.request("evaluate", map[string]any{"expression": "nope", "frameId": 0})
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Message, "could not find symbol value for nope")
+
+	resp, _ = c.request("variables", map[string]any{"variablesReference": 42})
+	assert.False(t, resp.Success)
+	resp, _ = c.request("pause", nil)
+	assert.False(t, resp.Success)
+	assert.Equal(t, "unsupported request: pause", resp.Message)
+
+	// Step over the line, which returns to main.
+	c.request("next", map[string]any{"threadId": 1})
+	stopped = c.event("stopped")
+	assert.Contains(t, string(stopped.Body), `"reason":"step"`)
+	resp, _ = c.request("stackTrace", map[string]any{"threadId": 1})
+	require.NoError(t, json.Unmarshal(resp.Body, &st))
+	require.Len(t, st.StackFrames, 1)
+	assert.Equal(t, 39, st.StackFrames[0].Line)
+
+	// Expand a struct through a pointer.
+	resp, _ = c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []map[string]any{{"line": 21}},
+	})
+	assert.True(t, resp.Success)
+	resp, _ = c.request("continue", map[string]any{"threadId": 1})
+	assert.JSONEq(t, `{"allThreadsContinued":true}`, string(resp.Body))
+	c.event("stopped")
+	resp, _ = c.request("evaluate", map[string]any{"expression": "t", "frameId": 0})
+	var ev struct{ VariablesReference int }
+	require.NoError(t, json.Unmarshal(resp.Body, &ev))
+	require.NotZero(t, ev.VariablesReference)
+	resp, _ = c.request("variables", map[string]any{"variablesReference": ev.VariablesReference})
+	var vars struct {
+		Variables []struct {
+			Name               string
+			VariablesReference int
+		}
+	}
+	require.NoError(t, json.Unmarshal(resp.Body, &vars))
+	require.Len(t, vars.Variables, 1)
+	assert.Equal(t, "A", vars.Variables[0].Name)
+	resp, _ = c.request("variables", map[string]any{"variablesReference": vars.Variables[0].VariablesReference})
+	assert.Contains(t, string(resp.Body), `{"name":"[2]","value":"3","type":"int","variablesReference":0}`)
+
+	// Step out of the method.
+	c.request("stepOut", map[string]any{"threadId": 1})
+	c.event("stopped")
+	resp, _ = c.request("stackTrace", map[string]any{"threadId": 1})
+	require.NoError(t, json.Unmarshal(resp.Body, &st))
+	require.Len(t, st.StackFrames, 1)
+	assert.Equal(t, "main.main", st.StackFrames[0].Name)
+	assert.Equal(t, 40, st.StackFrames[0].Line)
+
+	// Conditional breakpoints.
+	resp, _ = c.request("setBreakpoints", map[string]any{
+		"source": map[string]any{"path": target},
+		"breakpoints": []map[string]any{
+			{"line": 43, "condition": "i == 3"},
+			{"line": 44, "condition": "i =="},
+			{"line": 45, "hitCondition": "x"},
+		},
+	})
+	assert.Contains(t, string(resp.Body), `{"id":0,"line":43,"verified":true}`)
+	assert.Contains(t, string(resp.Body), `{"id":1,"line":44,"message":"1:5: expected operand, found 'EOF'","verified":false}`)
+	assert.Contains(t, string(resp.Body), `"verified":false`)
+	c.request("continue", map[string]any{"threadId": 1})
+	c.event("stopped")
+	resp, _ = c.request("evaluate", map[string]any{"expression": "i", "frameId": 0})
+	assert.JSONEq(t, `{"result":"3","type":"int","variablesReference":0}`, string(resp.Body))
+
+	// Data breakpoints.
+	resp, _ = c.request("scopes", map[string]any{"frameId": 0})
+	require.NoError(t, json.Unmarshal(resp.Body, &scopes))
+	resp, _ = c.request("dataBreakpointInfo", map[string]any{"variablesReference": scopes.Scopes[0].VariablesReference, "name": "x"})
+	var info struct{ DataID string }
+	require.NoError(t, json.Unmarshal(resp.Body, &info))
+	assert.Equal(t, "0:x", info.DataID)
+	resp, _ = c.request("setDataBreakpoints", map[string]any{"breakpoints": []map[string]any{{"dataId": info.DataID}, {"dataId": "0:nope"}}})
+	assert.JSONEq(t, `{"breakpoints":[{"verified":true},{"message":"could not find symbol value for nope","verified":false}]}`, string(resp.Body))
+	c.request("setBreakpoints", map[string]any{"source": map[string]any{"path": target}})
+	c.request("continue", map[string]any{"threadId": 1})
+	output := c.event("output")
+	assert.Contains(t, string(output.Body), "Watchpoint 0: x changed from (2 int) to (3 int)")
+	stopped = c.event("stopped")
+	assert.Contains(t, string(stopped.Body), `"reason":"data breakpoint"`)
+	c.request("setDataBreakpoints", map[string]any{"breakpoints": []map[string]any{}})
+
+	// Clear the breakpoints and run to the end.
+	c.request("setBreakpoints", map[string]any{"source": map[string]any{"path": target}})
+	c.request("continue", map[string]any{"threadId": 1})
+	c.event("terminated")
+	out := <-done
+	assert.Contains(t, out, "hello 3")
+}
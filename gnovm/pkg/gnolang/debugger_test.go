@@ -123,8 +123,8 @@ func TestDebug(t *testing.T) {
 		{in: brk + "clear -1\n", out: "Command failed: invalid breakpoint id: -1"},
 		{in: brk + "clear\n", out: "dbg> "},
 		{in: "p\n", out: "Command failed: missing argument"},
-		{in: "p 1+2\n", out: "Command failed: expression not supported"},
-		{in: "p 1.2\n", out: "Command failed: invalid basic literal value: 1.2"},
+		{in: "p 1+2\n", out: "(3 int)"},
+		{in: "p 1.2\n", out: "(1.2 float64)"},
 		{in: "p 31212324222123123232123123123123123123123123123123\n", out: "value out of range"},
 		{in: "p 3)\n", out: "Command failed:"},
 		{in: "p (3)", out: "(3 int)"},
@@ -145,6 +145,30 @@ func TestDebug(t *testing.T) {
 		{in: "b 37\nc\nnext\n", out: "=>   39:"},
 		{in: "b 40\nc\nnext\n", out: "=>   41:"},
 		{in: "b 22\nc\nstepout\n", out: "=>   40:"},
+		{in: "b 40\nc\np t.get(2)\n", out: "(3 int)"},
+		{in: "b 40\nc\np len(t.A) + num*2\n", out: "(13 int)"},
+		{in: "b 21\nc\nup\np num+i\n", out: "name i not declared"},
+		{in: "b 21\nc\np t.A[i] * 10\n", out: "(20 int)"},
+		{in: "b 40\nc\np nope + 1\n", out: "Command failed: main/../../tests/integ/debugger/sample.gno:32:1-46:2: name nope not declared\n"},
+		{in: "b 40\nc\np t.A[5] + 1\nc\n", out: "Command failed: slice index out of bounds: 5 (len=3)"},
+		{in: "b 40\nc\np t.A[5] + 1\nc\n", out: "bye 4"},
+		{in: "b 43 if i == 2\n", out: "Breakpoint 0 at main main/../../tests/integ/debugger/sample.gno:43:5-46:2 if i == 2"},
+		{in: "b 43 if i == 2\nc\np i\n", out: "(2 int)"},
+		{in: "b 43 if i ==\n", out: "Command failed: 1:5: expected operand, found 'EOF'"},
+		{in: "b 43 if num\nc\n", out: "Breakpoint 0 condition failed: condition is not a boolean"},
+		{in: "b 43\ncond 0 x > 2\nc\np i\n", out: "(3 int)"},
+		{in: "b 43\ncond 0 x > 2\ncond 0\nc\np i\n", out: "(0 int)"},
+		{in: "b 43\ncond -hitcount 0 3\nc\np i\nbp\n", out: "(hit count 3)\n\thits: 3"},
+		{in: "b 43\ncond -hitcount 0 >= 5\nc\np i\n", out: "(2 int)"},
+		{in: "b 43\ncond -hitcount 0 % 0\n", out: "modulo must be positive"},
+		{in: "b 43\ncond -hitcount 0 x\n", out: "invalid hit count condition"},
+		{in: "cond 5 x\n", out: "Command failed: invalid breakpoint id: 5"},
+		{in: "b 42\nc\nwatch x\nclear 0\nc\np i\n", out: "Watchpoint 0: x changed from (0 int) to (1 int)"},
+		{in: "b 42\nc\nwatch x\nclear 0\nc\np i\n", out: "(1 int)"},
+		{in: "b 42\nc\nwatch x\nbp\n", out: "Watchpoint 1 on x"},
+		{in: "b 21\nc\nwatch t.A[1]\n", out: "Watchpoint 1 on t.A[1]"},
+		{in: "watch 1+2\n", out: "Command failed: cannot watch expression: 1+2"},
+		{in: "watch\n", out: "Command failed: missing argument"},
 	})
 
 	runDebugTest(t, "../../tests/files/a1.gno", []dtest{
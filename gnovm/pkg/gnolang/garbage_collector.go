@@ -40,6 +40,9 @@ func (m *Machine) GarbageCollect() (left int64, ok bool) {
 	defer func() {
 		gasCPU := overflow.Mulp(visitCount*VisitCpuFactor, GasFactorCPU)
 		visitCount = 0
+		if m.Profile != nil {
+			m.Profile.sampleGas(m, gasCPU)
+		}
 		if m.GasMeter != nil {
 			m.GasMeter.ConsumeGas(gasCPU, "GC")
 		}
@@ -52,6 +55,12 @@ func (m *Machine) GarbageCollect() (left int64, ok bool) {
 	// We don't need the old value anymore.
 	m.Alloc.Reset()
 
+	// Live values are recounted below, which are not new allocations.
+	m.Alloc.collecting = true
+	defer func() {
+		m.Alloc.collecting = false
+	}()
+
 	// This is the only place where it's bumped.
 	m.GCCycle += 1
 
@@ -77,6 +86,19 @@ func (m *Machine) GarbageCollect() (left int64, ok bool) {
 		}
 	}
 
+	// Visit goroutines that are not running
+	if m.sched != nil {
+		for _, g := range m.sched.goroutines {
+			if g == m.sched.current {
+				continue
+			}
+			stop := g.Visit(m.Alloc, vis)
+			if stop {
+				return -1, false
+			}
+		}
+	}
+
 	// Visit package
 	stop := vis(m.Package)
 	if stop {
@@ -134,6 +156,12 @@ func GCVisitorFn(gcCycle int64, alloc *Allocator, visitCount int64) Visitor {
 			if oo.GetLastGCCycle() == gcCycle {
 				return false // but don't stop
 			}
+		} else if cv, isChan := v.(*ChanValue); isChan {
+			// Channels are not objects, but may be cyclic too.
+			if cv.lastGCCycle == gcCycle {
+				return false // but don't stop
+			}
+			cv.lastGCCycle = gcCycle
 		}
 
 		visitCount++ // Count operations for gas calculation
@@ -218,6 +246,32 @@ func (sv *StructValue) VisitAssociated(vis Visitor) (stop bool) {
 	return
 }
 
+func (cv *ChanValue) VisitAssociated(vis Visitor) (stop bool) {
+	// Visit buffered values.
+	for i := 0; i < len(cv.Buffer); i++ {
+		v := cv.Buffer[i].V
+		if v == nil {
+			continue
+		}
+		stop = vis(v)
+		if stop {
+			return
+		}
+	}
+	// Visit values of blocked senders.
+	for _, w := range cv.sendq {
+		v := w.value.V
+		if v == nil {
+			continue
+		}
+		stop = vis(v)
+		if stop {
+			return
+		}
+	}
+	return
+}
+
 func (bmv *BoundMethodValue) VisitAssociated(vis Visitor) (stop bool) {
 	// bmv.Func cannot be a closure, it must be a method.
 	// So we do not visit it (for garbage collection).
@@ -428,3 +482,36 @@ func (e *Exception) Visit(alloc *Allocator, vis Visitor) (stop bool) {
 
 	return
 }
+
+func (g *Goroutine) Visit(alloc *Allocator, vis Visitor) (stop bool) {
+	alloc.Allocate(allocGoroutine)
+
+	// vis blocks
+	for _, block := range g.blocks {
+		if block == nil {
+			continue
+		}
+		stop = vis(block)
+		if stop {
+			return
+		}
+	}
+
+	// vis frames
+	for i := range g.frames {
+		stop = g.frames[i].Visit(alloc, vis)
+		if stop {
+			return
+		}
+	}
+
+	// vis exceptions
+	for e := g.exception; e != nil; e = e.Previous {
+		stop = e.Visit(alloc, vis)
+		if stop {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,947 @@
+package gnolang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Generic declarations (functions, types, and methods of generic types) are
+// never preprocessed nor run themselves. Instead, each use of a generic with
+// type arguments copies its declaration, substitutes the type parameters with
+// the type arguments, and preprocesses the copy (the instance) in the context
+// of the generic's file, as if it were declared there.
+//
+// Instances are registered on the package node of the generic by name, e.g.
+// "Tree[int]", so that each instance is only created once per package node.
+// Instances of generic types are *DeclaredTypes (saved to the store like any
+// other type), and instances of functions and methods are block nodes whose
+// location file name is suffixed with the type arguments, e.g.
+// "tree.gno[int]", so that they can be found when loading func values from the
+// store.
+
+// A generic declaration along with its package and file.
+type genericDecl struct {
+	Decl
+	pn *PackageNode
+	fn *FileNode
+}
+
+// An instance of a generic declaration.
+type genericInstance struct {
+	decl  Decl        // the generic declaration.
+	targs []Type      // type arguments.
+	tv    TypedValue  // the instance type or func value.
+	nodes []BlockNode // block nodes of func and method instances.
+}
+
+type instanceKey struct {
+	pn   *PackageNode
+	name Name
+}
+
+// An instanceQueue defers work on new instances, namely checking the type
+// arguments and preprocessing the bodies of function and method instances,
+// until all the declarations they may depend on are defined. A queue is shared
+// by all the package nodes involved in an instantiation (or predefinition of
+// a file set) and it is flushed by whoever opened it.
+type instanceQueue struct {
+	pns     []*PackageNode
+	tasks   []func()
+	created []instanceKey
+	done    bool
+}
+
+// Returns the queue of any of pns, or a new queue if none has one, and shares
+// it with all of pns. If opened is true, the caller must flush the queue upon
+// success, and close it in any case.
+func openInstances(pns ...*PackageNode) (q *instanceQueue, opened bool) {
+	for _, pn := range pns {
+		if pn.pending != nil {
+			q = pn.pending
+			break
+		}
+	}
+	if q == nil {
+		q = &instanceQueue{}
+		opened = true
+	}
+	for _, pn := range pns {
+		if pn.pending == nil {
+			pn.pending = q
+			q.pns = append(q.pns, pn)
+		}
+	}
+	return q, opened
+}
+
+func (q *instanceQueue) add(task func()) {
+	q.tasks = append(q.tasks, task)
+}
+
+func (q *instanceQueue) register(pn *PackageNode, name Name, gi *genericInstance) {
+	if pn.instances == nil {
+		pn.instances = make(map[Name]*genericInstance)
+	}
+	pn.instances[name] = gi
+	q.created = append(q.created, instanceKey{pn, name})
+}
+
+// Runs all deferred work, including any work deferred while flushing.
+func (q *instanceQueue) flush() {
+	for len(q.tasks) > 0 {
+		task := q.tasks[0]
+		q.tasks = q.tasks[1:]
+		task()
+	}
+	q.done = true
+}
+
+// Detaches q from its package nodes. If q was not flushed, e.g. due to a
+// preprocessing error, the instances created with it are forgotten.
+func (q *instanceQueue) close() {
+	for _, pn := range q.pns {
+		pn.pending = nil
+	}
+	if !q.done {
+		for _, k := range q.created {
+			delete(k.pn.instances, k.name)
+		}
+	}
+}
+
+// Returns true if n is a generic function or type, or a method of a generic
+// type.
+func isGenericDecl(n Node) bool {
+	switch d := n.(type) {
+	case *FuncDecl:
+		if d.IsMethod {
+			_, rxs := genericRecv(d.Recv.Type)
+			return len(rxs) > 0
+		}
+		return len(d.Type.TypeParams) > 0
+	case *TypeDecl:
+		return len(d.TypeParams) > 0
+	default:
+		return false
+	}
+}
+
+// Returns the base type name and type parameter names of a (possibly
+// generic) receiver type expression.
+func genericRecv(x Expr) (Name, Exprs) {
+	if sx, ok := x.(*StarExpr); ok {
+		x = sx.X
+	}
+	switch x := x.(type) {
+	case *NameExpr:
+		return x.Name, nil
+	case *IndexExpr:
+		if nx, ok := x.X.(*NameExpr); ok {
+			return nx.Name, Exprs{x.Index}
+		}
+	case *IndexListExpr:
+		if nx, ok := x.X.(*NameExpr); ok {
+			return nx.Name, x.Indices
+		}
+	}
+	return "", nil
+}
+
+// Returns the generic function or type declared as name in pn, if any.
+func (pn *PackageNode) getGenericDecl(name Name) *genericDecl {
+	if pn.FileSet == nil {
+		return nil
+	}
+	fn, d, ok := pn.FileSet.GetDeclForSafe(name)
+	if !ok || !isGenericDecl(*d) {
+		return nil
+	}
+	return &genericDecl{Decl: *d, pn: pn, fn: fn}
+}
+
+// Returns the generic function or type referred to by x in the context of
+// last, or nil if x does not refer to one.
+func lookupGeneric(store Store, last BlockNode, x Expr) *genericDecl {
+	switch x := x.(type) {
+	case *NameExpr:
+		if isUverseName(x.Name) {
+			return nil
+		}
+		for bn := last; bn != nil; bn = bn.GetParentNode(store) {
+			if _, ok := bn.GetLocalIndex(x.Name); ok {
+				return nil
+			}
+			if pn, ok := bn.(*PackageNode); ok {
+				return pn.getGenericDecl(x.Name)
+			}
+		}
+	case *SelectorExpr:
+		nx, ok := x.X.(*NameExpr)
+		if !ok {
+			return nil
+		}
+		tv := last.GetSlot(store, nx.Name, true)
+		if tv == nil {
+			return nil
+		}
+		pv, ok := tv.V.(*PackageValue)
+		if !ok {
+			return nil
+		}
+		pn := pv.GetPackageNode(store)
+		if _, ok := pn.GetLocalIndex(x.Sel); ok {
+			return nil
+		}
+		return pn.getGenericDecl(x.Sel)
+	}
+	return nil
+}
+
+// Returns the type parameters of g.
+func (g *genericDecl) typeParams() FieldTypeExprs {
+	switch d := g.Decl.(type) {
+	case *FuncDecl:
+		return d.Type.TypeParams
+	case *TypeDecl:
+		return d.TypeParams
+	default:
+		panic("should not happen")
+	}
+}
+
+// Returns the registered instance of g for targs, if any. As the store may
+// have been discarded since the instance was created (e.g. on a failed
+// transaction), its type and block nodes are saved again if missing.
+func (g *genericDecl) getInstance(store Store, name Name) *genericInstance {
+	gi := g.pn.instances[name]
+	if gi == nil || store == nil {
+		return gi
+	}
+	if tv, ok := gi.tv.V.(TypeValue); ok {
+		// not sealed yet if still being instantiated, in which case it
+		// is saved once complete.
+		dt := tv.Type.(*DeclaredType)
+		if dt.sealed && store.GetTypeSafe(dt.TypeID()) == nil {
+			store.SetType(dt)
+		}
+	}
+	if len(gi.nodes) > 0 && store.GetBlockNodeSafe(gi.nodes[0].GetLocation()) == nil {
+		for _, bn := range gi.nodes {
+			store.SetBlockNode(bn)
+		}
+	}
+	return gi
+}
+
+// Returns the instance name of name for type arguments targs.
+func instanceName(name Name, targs []Type) Name {
+	var sb strings.Builder
+	sb.WriteString(string(name))
+	sb.WriteString("[")
+	for i, t := range targs {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(t.TypeID().String())
+	}
+	sb.WriteString("]")
+	return Name(sb.String())
+}
+
+// Returns the file name of the instance named name of a generic in fn.
+func instanceFileName(fn *FileNode, name Name) string {
+	return fn.FileName + string(name[strings.IndexByte(string(name), '['):])
+}
+
+// Like n.Copy(), but also copies the spans, labels, and iota values.
+func copyWithSpans(n Node) Node {
+	type attrs struct {
+		span  Span
+		label Name
+		iota  any
+	}
+	var all []attrs
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			all = append(all, attrs{n.GetSpan(), n.GetLabel(), n.GetAttribute(ATTR_IOTA)})
+		}
+		return n, TRANS_CONTINUE
+	})
+	cpy := n.Copy()
+	i := 0
+	Transcribe(cpy, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			a := all[i]
+			i++
+			n.SetSpan(a.span)
+			n.SetLabel(a.label)
+			if a.iota != nil {
+				n.SetAttribute(ATTR_IOTA, a.iota)
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+	return cpy
+}
+
+// Substitutes the type parameter names in n with their type arguments.
+func substTypeParams(last BlockNode, n Node, subst map[Name]Type) Node {
+	return Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		nx, ok := n.(*NameExpr)
+		if !ok {
+			return n, TRANS_CONTINUE
+		}
+		switch ftype {
+		case TRANS_FIELDTYPE_NAME, TRANS_VAR_NAME, TRANS_COMPOSITE_KEY,
+			TRANS_ASSIGN_LHS, TRANS_RANGE_KEY, TRANS_RANGE_VALUE:
+			return n, TRANS_CONTINUE
+		}
+		if t, ok := subst[nx.Name]; ok {
+			return toConstTypeExpr(last, nx, t), TRANS_SKIP
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// Returns the substitution of tparams by targs.
+func typeParamSubst(tparams FieldTypeExprs, targs []Type) map[Name]Type {
+	subst := make(map[Name]Type, len(tparams))
+	for i, tp := range tparams {
+		subst[tp.Name] = targs[i]
+	}
+	return subst
+}
+
+// Preprocesses and evaluates the type arguments xs.
+func evalTypeArgs(store Store, last BlockNode, xs Exprs) []Type {
+	targs := make([]Type, len(xs))
+	for i := range xs {
+		xs[i] = Preprocess(store, last, xs[i]).(Expr)
+		targs[i] = evalStaticType(store, last, xs[i])
+	}
+	return targs
+}
+
+// Preprocesses and evaluates the type expression x of the generic file fn,
+// with its type parameters substituted.
+func evalTypeExpr(store Store, fn *FileNode, x Expr, subst map[Name]Type) Type {
+	if ctx, ok := x.(*constTypeExpr); ok {
+		return ctx.Type
+	}
+	cx := copyWithSpans(x).(Expr)
+	cx = substTypeParams(fn, cx, subst).(Expr)
+	cx = Preprocess(store, fn, cx).(Expr)
+	return evalStaticType(store, fn, cx)
+}
+
+// Returns a const expr for the func instance fv, in place of source.
+func instanceExpr(source Expr, fv *FuncValue) *ConstExpr {
+	cx := toConstExpr(source, TypedValue{T: fv.Type, V: fv})
+	setConstAttrs(cx)
+	// func values of instances are copied at runtime.
+	cx.SetAttribute(ATTR_GENERIC_INSTANCE, true)
+	return cx
+}
+
+// Instantiates the generic type or function indexed by x, if any. Returns a
+// type expression or a const expr of the instance, or nil if x is not an
+// instantiation.
+func instantiateIndex(store Store, last BlockNode, x Expr) Expr {
+	var gx Expr
+	var xs Exprs
+	switch x := x.(type) {
+	case *IndexExpr:
+		gx, xs = x.X, Exprs{x.Index}
+	case *IndexListExpr:
+		gx, xs = x.X, x.Indices
+	default:
+		panic("should not happen")
+	}
+	g := lookupGeneric(store, last, gx)
+	if g == nil {
+		return nil
+	}
+	targs := evalTypeArgs(store, last, xs)
+	switch g.Decl.(type) {
+	case *TypeDecl:
+		dt := instantiateType(store, last, x, g, targs)
+		return toConstTypeExpr(last, x, dt)
+	case *FuncDecl:
+		fv := instantiateFunc(store, last, x, g, targs)
+		return instanceExpr(x, fv)
+	default:
+		panic("should not happen")
+	}
+}
+
+// Instantiates the generic function called by n, if any, inferring any type
+// arguments not given from the call arguments. Returns nil if n does not
+// call a generic function.
+func instantiateCall(store Store, last BlockNode, n *CallExpr) Expr {
+	gx, xs := n.Func, Exprs(nil)
+	switch fx := n.Func.(type) {
+	case *IndexExpr:
+		gx, xs = fx.X, Exprs{fx.Index}
+	case *IndexListExpr:
+		gx, xs = fx.X, fx.Indices
+	}
+	g := lookupGeneric(store, last, gx)
+	if g == nil {
+		return nil
+	}
+	fd, ok := g.Decl.(*FuncDecl)
+	if !ok {
+		// a conversion to an instance type.
+		return nil
+	}
+	tparams := fd.Type.TypeParams
+	if len(xs) > len(tparams) {
+		panic(fmt.Sprintf(
+			"got %d type arguments but %s has %d type parameters",
+			len(xs), fd.Name, len(tparams)))
+	}
+	targs := evalTypeArgs(store, last, xs)
+	if len(targs) < len(tparams) {
+		targs = inferTypeArgs(store, last, g, n, targs)
+	}
+	fv := instantiateFunc(store, last, n, g, targs)
+	return instanceExpr(n.Func, fv)
+}
+
+// Asserts that the number of type arguments matches the type parameters of g.
+func assertNumTypeArgs(g *genericDecl, targs []Type) {
+	tparams := g.typeParams()
+	if len(targs) != len(tparams) {
+		panic(fmt.Sprintf(
+			"got %d type arguments but %s has %d type parameters",
+			len(targs), g.GetDeclNames()[0], len(tparams)))
+	}
+}
+
+// Returns the instance of the generic type g for type arguments targs, as
+// used by the expression x.
+func instantiateType(store Store, last BlockNode, x Expr, g *genericDecl, targs []Type) *DeclaredType {
+	td := g.Decl.(*TypeDecl)
+	assertNumTypeArgs(g, targs)
+	name := instanceName(td.Name, targs)
+	if gi := g.getInstance(store, name); gi != nil {
+		return gi.tv.GetType().(*DeclaredType)
+	}
+	if td.IsAlias {
+		panic(fmt.Sprintf("generic type alias %s is not supported", td.Name))
+	}
+	q, opened := openInstances(packageOf(last), g.pn)
+	if opened {
+		defer q.close()
+	}
+	// register before evaluating the base type, which may refer to the
+	// instance itself.
+	dt := declareWith(g.pn.PkgPath, g.fn, name, nil)
+	q.register(g.pn, name, &genericInstance{
+		decl:  td,
+		targs: targs,
+		tv:    asValue(dt),
+	})
+	q.add(func() {
+		defer doRecover([]BlockNode{last}, x)
+		assertTypeArgs(store, g, td.TypeParams, targs)
+	})
+	subst := typeParamSubst(td.TypeParams, targs)
+	dt.Base = baseOf(evalTypeExpr(store, g.fn, td.Type, subst))
+	dt.Seal()
+	// instantiate the methods declared on the generic type.
+	for _, fn := range g.pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			if fd, ok := d.(*FuncDecl); ok && fd.IsMethod {
+				if rn, rxs := genericRecv(fd.Recv.Type); rn == td.Name && len(rxs) > 0 {
+					instantiateMethod(store, &genericDecl{fd, g.pn, fn}, dt, targs)
+				}
+			}
+		}
+	}
+	if store != nil {
+		store.SetType(dt)
+	}
+	if opened {
+		q.flush()
+	}
+	return dt
+}
+
+// Defines the instance of the generic method g on the instance type dt.
+func instantiateMethod(store Store, g *genericDecl, dt *DeclaredType, targs []Type) {
+	fd := g.Decl.(*FuncDecl)
+	_, rxs := genericRecv(fd.Recv.Type)
+	if len(rxs) != len(targs) {
+		panic(fmt.Sprintf(
+			"got %d type parameters in receiver of %s but %s has %d type parameters",
+			len(rxs), fd.Name, dt.Name, len(targs)))
+	}
+	inst := copyWithSpans(fd).(*FuncDecl)
+	subst := make(map[Name]Type, len(rxs))
+	for i, rx := range rxs {
+		if nx, ok := rx.(*NameExpr); ok && nx.Name != blankIdentifier {
+			subst[nx.Name] = targs[i]
+		}
+	}
+	// replace the generic receiver type with the instance type.
+	if sx, ok := inst.Recv.Type.(*StarExpr); ok {
+		sx.X = toConstTypeExpr(g.fn, sx.X, dt)
+	} else {
+		inst.Recv.Type = toConstTypeExpr(g.fn, inst.Recv.Type, dt)
+	}
+	inst = substTypeParams(g.fn, inst, subst).(*FuncDecl)
+	inst.SetAttribute(ATTR_GENERIC_INSTANCE, true)
+	setNodeLocations(g.pn.PkgPath, instanceFileName(g.fn, dt.Name), inst)
+	initStaticBlocks(store, g.fn, inst)
+	inst.Recv = *Preprocess(store, g.fn, &inst.Recv).(*FieldTypeExpr)
+	inst.Type = *Preprocess(store, g.fn, &inst.Type).(*FuncTypeExpr)
+	rft := evalStaticType(store, g.fn, &inst.Recv).(FieldType)
+	ft := evalStaticType(store, g.fn, &inst.Type).(*FuncType)
+	// the body is set lazily from the source, after preprocessing.
+	if !dt.TryDefineMethod(&FuncValue{
+		Type:     ft.UnboundType(rft),
+		IsMethod: true,
+		Source:   inst,
+		Name:     inst.Name,
+		FileName: g.fn.FileName,
+		PkgPath:  g.pn.PkgPath,
+		Crossing: ft.IsCrossing(),
+	}) {
+		panic(fmt.Sprintf("redeclaration of method %s.%s",
+			dt.Name, inst.Name))
+	}
+	inst.SetAttribute(ATTR_PREDEFINED, true)
+	gi := g.pn.instances[dt.Name]
+	gi.nodes = append(gi.nodes, setInstanceNodes(store, inst)...)
+	g.pn.pending.add(func() {
+		Preprocess(store, g.fn, inst)
+	})
+}
+
+// Returns the instance of the generic function g for type arguments targs,
+// as used by the expression x.
+func instantiateFunc(store Store, last BlockNode, x Expr, g *genericDecl, targs []Type) *FuncValue {
+	fd := g.Decl.(*FuncDecl)
+	assertNumTypeArgs(g, targs)
+	name := instanceName(fd.Name, targs)
+	if gi := g.getInstance(store, name); gi != nil {
+		return gi.tv.V.(*FuncValue)
+	}
+	if fd.Body == nil {
+		panic(fmt.Sprintf("generic function %s must have a body", fd.Name))
+	}
+	q, opened := openInstances(packageOf(last), g.pn)
+	if opened {
+		defer q.close()
+	}
+	inst := copyWithSpans(fd).(*FuncDecl)
+	inst.Name = name
+	inst.Type.TypeParams = nil
+	inst = substTypeParams(g.fn, inst, typeParamSubst(fd.Type.TypeParams, targs)).(*FuncDecl)
+	inst.SetAttribute(ATTR_GENERIC_INSTANCE, true)
+	setNodeLocations(g.pn.PkgPath, instanceFileName(g.fn, name), inst)
+	initStaticBlocks(store, g.fn, inst)
+	inst.Type = *Preprocess(store, g.fn, &inst.Type).(*FuncTypeExpr)
+	ft := evalStaticType(store, g.fn, &inst.Type).(*FuncType)
+	// the body is set lazily from the source, after preprocessing.
+	fv := &FuncValue{
+		Type:     ft,
+		Source:   inst,
+		Name:     name,
+		FileName: g.fn.FileName,
+		PkgPath:  g.pn.PkgPath,
+		Crossing: ft.IsCrossing(),
+	}
+	inst.SetAttribute(ATTR_PREDEFINED, true)
+	// register before preprocessing the body, which may refer to the
+	// instance itself.
+	q.register(g.pn, name, &genericInstance{
+		decl:  fd,
+		targs: targs,
+		tv:    TypedValue{T: ft, V: fv},
+		nodes: setInstanceNodes(store, inst),
+	})
+	q.add(func() {
+		defer doRecover([]BlockNode{last}, x)
+		assertTypeArgs(store, g, fd.Type.TypeParams, targs)
+	})
+	q.add(func() {
+		Preprocess(store, g.fn, inst)
+	})
+	if opened {
+		q.flush()
+	}
+	return fv
+}
+
+// Saves all block nodes of the instance n, and returns them.
+func setInstanceNodes(store Store, n Node) (bns []BlockNode) {
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok {
+			bns = append(bns, bn)
+			if store != nil {
+				store.SetBlockNode(bn)
+			}
+		}
+		return n, TRANS_CONTINUE
+	})
+	return bns
+}
+
+// Returns the block nodes of all instances of the generic declaration d.
+func (pn *PackageNode) getInstanceNodes(d Decl) (bns []BlockNode) {
+	for _, gi := range pn.instances {
+		if gi.decl == d {
+			bns = append(bns, gi.nodes...)
+		}
+	}
+	return bns
+}
+
+// ----------------------------------------
+// Type argument inference
+
+type typeArgInference struct {
+	store Store
+	g     *genericDecl
+	names []Name
+	targs map[Name]Type
+}
+
+func (inf *typeArgInference) isTypeParam(name Name) bool {
+	_, ok := inf.targs[name]
+	return ok
+}
+
+// Infers the type arguments of the type parameters in the parameter type
+// expression px from the argument type t.
+func (inf *typeArgInference) unify(px Expr, t Type) {
+	if t == nil {
+		return
+	}
+	switch px := px.(type) {
+	case *NameExpr:
+		if inf.isTypeParam(px.Name) && inf.targs[px.Name] == nil {
+			inf.targs[px.Name] = t
+		}
+	case *StarExpr:
+		if pt, ok := baseOf(t).(*PointerType); ok {
+			inf.unify(px.X, pt.Elt)
+		}
+	case *SliceTypeExpr:
+		if st, ok := baseOf(t).(*SliceType); ok {
+			inf.unify(px.Elt, st.Elt)
+		}
+	case *ArrayTypeExpr:
+		if at, ok := baseOf(t).(*ArrayType); ok {
+			inf.unify(px.Elt, at.Elt)
+		}
+	case *MapTypeExpr:
+		if mt, ok := baseOf(t).(*MapType); ok {
+			inf.unify(px.Key, mt.Key)
+			inf.unify(px.Value, mt.Value)
+		}
+	case *ChanTypeExpr:
+		if ct, ok := baseOf(t).(*ChanType); ok {
+			inf.unify(px.Value, ct.Elt)
+		}
+	case *FuncTypeExpr:
+		if ft, ok := baseOf(t).(*FuncType); ok {
+			for i := 0; i < len(px.Params) && i < len(ft.Params); i++ {
+				inf.unify(px.Params[i].Type, ft.Params[i].Type)
+			}
+			for i := 0; i < len(px.Results) && i < len(ft.Results); i++ {
+				inf.unify(px.Results[i].Type, ft.Results[i].Type)
+			}
+		}
+	case *IndexExpr:
+		inf.unifyInstance(px.X, Exprs{px.Index}, t)
+	case *IndexListExpr:
+		inf.unifyInstance(px.X, px.Indices, t)
+	}
+}
+
+// Like unify, but for a generic type instance with type argument
+// expressions xs.
+func (inf *typeArgInference) unifyInstance(gx Expr, xs Exprs, t Type) {
+	dt, ok := t.(*DeclaredType)
+	if !ok {
+		return
+	}
+	g := lookupGeneric(inf.store, inf.g.fn, gx)
+	if g == nil || dt.PkgPath != g.pn.PkgPath {
+		return
+	}
+	gi := g.pn.instances[dt.Name]
+	if gi == nil || gi.decl != g.Decl {
+		return
+	}
+	for i := 0; i < len(xs) && i < len(gi.targs); i++ {
+		inf.unify(xs[i], gi.targs[i])
+	}
+}
+
+// Returns true if the type expression x mentions any type parameter.
+func (inf *typeArgInference) mentionsTypeParam(x Expr) (found bool) {
+	Transcribe(x, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if nx, ok := n.(*NameExpr); ok && inf.isTypeParam(nx.Name) {
+			found = true
+			return n, TRANS_EXIT
+		}
+		return n, TRANS_CONTINUE
+	})
+	return found
+}
+
+// Returns the type expression of a single type term constraint like
+// `~[]E`, whether the term is an underlying (tilde) term, and whether
+// cx is such a constraint.
+func coreTerm(cx Expr) (x Expr, tilde bool, ok bool) {
+	switch cx := cx.(type) {
+	case *UnaryExpr:
+		if cx.Op == TILDE {
+			return cx.X, true, true
+		}
+	case *InterfaceTypeExpr:
+		if len(cx.Methods) == 0 && len(cx.Terms) == 1 {
+			return coreTerm(cx.Terms[0])
+		}
+	case *SliceTypeExpr, *ArrayTypeExpr, *MapTypeExpr, *ChanTypeExpr,
+		*FuncTypeExpr, *StarExpr, *StructTypeExpr:
+		return cx, false, true
+	}
+	return nil, false, false
+}
+
+// Returns the type arguments of the generic function g called by n, given
+// the first explicit type arguments targs.
+func inferTypeArgs(store Store, last BlockNode, g *genericDecl, n *CallExpr, targs []Type) []Type {
+	fd := g.Decl.(*FuncDecl)
+	tparams := fd.Type.TypeParams
+	inf := &typeArgInference{
+		store: store,
+		g:     g,
+		names: make([]Name, len(tparams)),
+		targs: make(map[Name]Type, len(tparams)),
+	}
+	for i, tp := range tparams {
+		inf.names[i] = tp.Name
+		inf.targs[tp.Name] = nil
+		if i < len(targs) {
+			inf.targs[tp.Name] = targs[i]
+		}
+	}
+	// Returns the parameter type expression for the i-th argument.
+	params := fd.Type.Params
+	paramAt := func(i int) Expr {
+		if len(params) == 0 {
+			return nil
+		}
+		lastp := params[len(params)-1].Type
+		if vx, ok := lastp.(*SliceTypeExpr); ok && vx.Vrd && i >= len(params)-1 {
+			if n.Varg {
+				return &SliceTypeExpr{Elt: vx.Elt}
+			}
+			return vx.Elt
+		}
+		if i < len(params) {
+			return params[i].Type
+		}
+		return nil
+	}
+	// Infer from typed arguments first.
+	type untypedArg struct {
+		px Expr
+		t  Type
+	}
+	var untyped []untypedArg
+	var ats []Type
+	if len(n.Args) == 1 && len(params) > 1 {
+		// f(g()) where g() returns multiple values.
+		n.Args[0] = Preprocess(store, last, n.Args[0]).(Expr)
+		if tt, ok := evalStaticTypeOfRaw(store, last, n.Args[0]).(*tupleType); ok {
+			ats = tt.Elts
+		} else {
+			ats = []Type{evalStaticTypeOf(store, last, n.Args[0])}
+		}
+	} else {
+		ats = make([]Type, len(n.Args))
+		for i, arg := range n.Args {
+			px := paramAt(i)
+			if px == nil || !inf.mentionsTypeParam(px) {
+				continue
+			}
+			if flx, ok := arg.(*FuncLitExpr); ok {
+				// only the signature is needed.
+				flx.Type = *Preprocess(store, last, &flx.Type).(*FuncTypeExpr)
+				ats[i] = evalStaticType(store, last, &flx.Type)
+				continue
+			}
+			n.Args[i] = Preprocess(store, last, arg).(Expr)
+			ats[i] = evalStaticTypeOf(store, last, n.Args[i])
+		}
+	}
+	for i, at := range ats {
+		px := paramAt(i)
+		if px == nil || at == nil {
+			continue
+		}
+		if isUntyped(at) {
+			untyped = append(untyped, untypedArg{px, at})
+			continue
+		}
+		inf.unify(px, at)
+	}
+	// Then, infer from the constraints of the form `~[]E`.
+	for changed := true; changed; {
+		changed = false
+		for _, tp := range tparams {
+			t := inf.targs[tp.Name]
+			if t == nil {
+				continue
+			}
+			x, tilde, ok := coreTerm(tp.Type)
+			if !ok {
+				continue
+			}
+			if tilde {
+				t = baseOf(t)
+			}
+			before := inf.numInferred()
+			inf.unify(x, t)
+			if inf.numInferred() > before {
+				changed = true
+			}
+		}
+	}
+	// Finally, use the default types of untyped constant arguments.
+	for _, ua := range untyped {
+		nx, ok := ua.px.(*NameExpr)
+		if !ok || !inf.isTypeParam(nx.Name) {
+			continue
+		}
+		if t := inf.targs[nx.Name]; t == nil || isUntyped(t) && untypedRank(ua.t) > untypedRank(t) {
+			inf.targs[nx.Name] = ua.t
+		}
+	}
+	res := make([]Type, len(tparams))
+	for i, name := range inf.names {
+		t := inf.targs[name]
+		if t == nil {
+			panic(fmt.Sprintf("in call to %s, cannot infer %s", fd.Name, name))
+		}
+		if isUntyped(t) {
+			t = defaultTypeOf(t)
+		}
+		res[i] = t
+	}
+	return res
+}
+
+func (inf *typeArgInference) numInferred() (num int) {
+	for _, t := range inf.targs {
+		if t != nil {
+			num++
+		}
+	}
+	return num
+}
+
+// Returns the rank of an untyped numeric constant type, such that mixing
+// untyped constants results in the default type of the highest rank.
+func untypedRank(t Type) int {
+	switch t {
+	case UntypedBigintType:
+		return 1
+	case UntypedRuneType:
+		return 2
+	case UntypedBigdecType:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Returns the first undefined name that the signatures of the generic g
+// depend on, if any, which must be defined before g can be instantiated.
+func findUndefinedGeneric(store Store, g *genericDecl, visited map[Decl]struct{}) Name {
+	if _, ok := visited[g.Decl]; ok {
+		return ""
+	}
+	visited[g.Decl] = struct{}{}
+	type sig struct {
+		fn      *FileNode
+		x       Expr
+		tparams Exprs
+	}
+	var sigs []sig
+	switch d := g.Decl.(type) {
+	case *TypeDecl:
+		var tps Exprs
+		for _, tp := range d.TypeParams {
+			tps = append(tps, Nx(tp.Name))
+		}
+		sigs = append(sigs, sig{g.fn, d.Type, tps})
+		for _, fn := range g.pn.FileSet.Files {
+			for _, d2 := range fn.Decls {
+				if fd, ok := d2.(*FuncDecl); ok && fd.IsMethod {
+					if rn, rxs := genericRecv(fd.Recv.Type); rn == d.Name && len(rxs) > 0 {
+						sigs = append(sigs, sig{fn, &fd.Type, rxs})
+					}
+				}
+			}
+		}
+	case *FuncDecl:
+		var tps Exprs
+		for _, tp := range d.Type.TypeParams {
+			tps = append(tps, Nx(tp.Name))
+		}
+		sigs = append(sigs, sig{g.fn, &d.Type, tps})
+	}
+	for _, sg := range sigs {
+		isTypeParam := func(name Name) bool {
+			for _, tp := range sg.tparams {
+				if nx, ok := tp.(*NameExpr); ok && nx.Name == name {
+					return true
+				}
+			}
+			return false
+		}
+		var un Name
+		Transcribe(sg.x, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+			if stage != TRANS_ENTER {
+				return n, TRANS_CONTINUE
+			}
+			nx, ok := n.(*NameExpr)
+			if !ok || isTypeParam(nx.Name) || isUverseName(nx.Name) {
+				return n, TRANS_CONTINUE
+			}
+			if sg.fn.GetSlot(store, nx.Name, true) != nil {
+				return n, TRANS_CONTINUE
+			}
+			if g2 := lookupGeneric(store, sg.fn, nx); g2 != nil {
+				un = findUndefinedGeneric(store, g2, visited)
+			} else {
+				un = nx.Name
+			}
+			if un != "" {
+				return n, TRANS_EXIT
+			}
+			return n, TRANS_CONTINUE
+		})
+		if un != "" {
+			return un
+		}
+	}
+	return ""
+}
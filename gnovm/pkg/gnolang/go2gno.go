@@ -355,8 +355,10 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			Vrd: true,
 		}
 	case *ast.InterfaceType:
+		methods, terms := toInterfaceElems(fs, gon.Methods)
 		return &InterfaceTypeExpr{
-			Methods: toFieldsFromList(fs, gon.Methods),
+			Methods: methods,
+			Terms:   terms,
 		}
 	case *ast.ChanType:
 		var dir ChanDir
@@ -372,8 +374,9 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		}
 	case *ast.FuncType:
 		return &FuncTypeExpr{
-			Params:  toFieldsFromList(fs, gon.Params),
-			Results: toFieldsFromList(fs, gon.Results),
+			TypeParams: toFieldsFromList(fs, gon.TypeParams),
+			Params:     toFieldsFromList(fs, gon.Params),
+			Results:    toFieldsFromList(fs, gon.Results),
 		}
 	case *ast.MapType:
 		return &MapTypeExpr{
@@ -546,12 +549,24 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 	case *ast.EmptyStmt:
 		return &EmptyStmt{}
 	case *ast.IndexListExpr:
-		if len(gon.Indices) > 1 {
-			panicWithPos("invalid operation: more than one index")
+		return &IndexListExpr{
+			X:       toExpr(fs, gon.X),
+			Indices: toExprs(fs, gon.Indices),
 		}
-		panicWithPos("invalid operation: indexList is not permitted in Gno")
 	case *ast.GoStmt:
-		panicWithPos("goroutines are not permitted")
+		cx := toExpr(fs, gon.Call).(*CallExpr)
+		return &GoStmt{
+			Call: *cx,
+		}
+	case *ast.SendStmt:
+		return &SendStmt{
+			Chan:  toExpr(fs, gon.Chan),
+			Value: toExpr(fs, gon.Value),
+		}
+	case *ast.SelectStmt:
+		return &SelectStmt{
+			Cases: toSelectCases(fs, gon.Body.List),
+		}
 	default:
 		panicWithPos("unknown Go type %v: %s\n",
 			reflect.TypeOf(gon),
@@ -617,6 +632,7 @@ var token2word = map[token.Token]Word{
 	token.LEQ:            LEQ,
 	token.GEQ:            GEQ,
 	token.DEFINE:         DEFINE,
+	token.TILDE:          TILDE,
 	token.BREAK:          BREAK,
 	token.CASE:           CASE,
 	token.CHAN:           CHAN,
@@ -724,9 +740,10 @@ func toDecls(fs *token.FileSet, gd *ast.GenDecl) (ds Decls) {
 			tipe := toExpr(fs, s.Type)
 			alias := s.Assign != 0
 			td := &TypeDecl{
-				NameExpr: NameExpr{Name: name},
-				Type:     tipe,
-				IsAlias:  alias,
+				NameExpr:   NameExpr{Name: name},
+				TypeParams: toFieldsFromList(fs, s.TypeParams),
+				Type:       tipe,
+				IsAlias:    alias,
 			}
 			setSpan(fs, s, td)
 			ds = append(ds, td)
@@ -853,6 +870,46 @@ func toFields(fs *token.FileSet, fields ...*ast.Field) (ftxs []FieldTypeExpr) {
 	return
 }
 
+// toInterfaceElems splits the elements of an interface type into methods
+// (and embedded interfaces), and type set terms which may only appear in
+// constraints, like `~int | ~string` or `comparable`.
+func toInterfaceElems(fs *token.FileSet, fl *ast.FieldList) (methods []FieldTypeExpr, terms []Expr) {
+	if fl == nil {
+		return nil, nil
+	}
+	var fields []*ast.Field
+	for _, f := range fl.List {
+		if len(f.Names) == 0 && isTypeTerm(f.Type) {
+			terms = append(terms, toExpr(fs, f.Type))
+		} else {
+			fields = append(fields, f)
+		}
+	}
+	methods = toFields(fs, fields...)
+	return methods, terms
+}
+
+func isTypeTerm(gon ast.Expr) bool {
+	switch gon := gon.(type) {
+	case *ast.Ident:
+		// predeclared non-interface types are terms.
+		switch gon.Name {
+		case "any", "error":
+			return false
+		case "comparable":
+			return true
+		default:
+			return isUverseName(Name(gon.Name))
+		}
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		// embedded (possibly generic) interface.
+		return false
+	default:
+		// unions, ~T, and type literals.
+		return true
+	}
+}
+
 func toKeyValueExprs(fs *token.FileSet, elts []ast.Expr) (kvxs KeyValueExprs) {
 	kvxs = make([]KeyValueExpr, len(elts))
 	for i, x := range elts {
@@ -902,3 +959,26 @@ func toSwitchClauseStmt(fs *token.FileSet, cc *ast.CaseClause) SwitchClauseStmt
 	setSpan(fs, cc, &scs)
 	return scs
 }
+
+// NOTE: unlike toClauses, the default clause is kept in place;
+// it is only ever chosen when no other case is ready.
+func toSelectCases(fs *token.FileSet, csz []ast.Stmt) []SelectCaseStmt {
+	res := make([]SelectCaseStmt, 0, len(csz))
+	hasDefault := false
+	for _, cs := range csz {
+		cc := cs.(*ast.CommClause)
+		if cc.Comm == nil {
+			if hasDefault {
+				panic("duplicate default clause")
+			}
+			hasDefault = true
+		}
+		scs := SelectCaseStmt{
+			Comm: toSimp(fs, cc.Comm),
+			Body: toStmts(fs, cc.Body),
+		}
+		setSpan(fs, cc, &scs)
+		res = append(res, scs)
+	}
+	return res
+}
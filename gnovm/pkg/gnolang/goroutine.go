@@ -0,0 +1,680 @@
+package gnolang
+
+import (
+	"fmt"
+)
+
+/*
+Goroutines and channels.
+
+Goroutines are scheduled cooperatively on a single machine, and the
+scheduling order is fully deterministic. The running goroutine is only
+switched out when:
+
+  - it blocks on a channel operation or a select statement,
+  - it calls runtime.Gosched(),
+  - it returns.
+
+Runnable goroutines are resumed in FIFO order, and a select statement
+picks the first ready case in source order (instead of a random one as in
+Go). Blocked goroutines are woken up in FIFO order too. Consequently, the
+same program always results in the same interleaving, and the same
+transaction always results in the same realm state.
+
+Only the state of the running goroutine lives in the Machine; the state of
+the others is saved in their *Goroutine. Switching goroutines swaps these
+stacks, and is charged OpCPUGoSwitch.
+
+Goroutines can only be switched by the outermost m.Run(); blocking within a
+nested m.Run(), e.g. within a String() method called by print, panics.
+
+Like in Go, goroutines which are still blocked or runnable when the main
+goroutine returns are abandoned. A program where all goroutines are
+blocked panics with a deadlock error, which cannot be recovered.
+*/
+
+// initial size of the op and value stacks of a new goroutine.
+const goroutineStackSize = 64
+
+type goroutineStatus int
+
+const (
+	goroutineRunnable goroutineStatus = iota
+	goroutineRunning
+	goroutineWaiting
+	goroutineDead
+)
+
+// Goroutine holds the state of a goroutine while it is not running.
+type Goroutine struct {
+	ID     int64
+	status goroutineStatus
+
+	ops        []Op
+	numOps     int
+	values     []TypedValue
+	numValues  int
+	exprs      []Expr
+	stmts      []Stmt
+	blocks     []*Block
+	frames     []Frame
+	pkg        *PackageValue
+	realm      *Realm
+	exception  *Exception
+	numResults int
+
+	// resume, if set, is called upon switching back
+	// to complete the operation the goroutine was
+	// blocked on.
+	resume func(m *Machine)
+}
+
+func (g *Goroutine) save(m *Machine) {
+	g.ops, g.numOps = m.Ops, m.NumOps
+	g.values, g.numValues = m.Values, m.NumValues
+	g.exprs = m.Exprs
+	g.stmts = m.Stmts
+	g.blocks = m.Blocks
+	g.frames = m.Frames
+	g.pkg = m.Package
+	g.realm = m.Realm
+	g.exception = m.Exception
+	g.numResults = m.NumResults
+}
+
+func (g *Goroutine) load(m *Machine) {
+	m.Ops, m.NumOps = g.ops, g.numOps
+	m.Values, m.NumValues = g.values, g.numValues
+	m.Exprs = g.exprs
+	m.Stmts = g.stmts
+	m.Blocks = g.blocks
+	m.Frames = g.frames
+	m.Package = g.pkg
+	m.Realm = g.realm
+	m.Exception = g.exception
+	m.NumResults = g.numResults
+	// release references held by the saved state.
+	*g = Goroutine{
+		ID:     g.ID,
+		status: g.status,
+		resume: g.resume,
+	}
+}
+
+// scheduler of the goroutines of a machine.
+// It is created upon the first go statement or
+// channel operation.
+type scheduler struct {
+	main       *Goroutine
+	current    *Goroutine
+	runq       []*Goroutine // runnable goroutines, FIFO.
+	goroutines []*Goroutine // goroutines that have not returned.
+	lastGoID   int64
+	lastChanID int64
+}
+
+func (m *Machine) getScheduler() *scheduler {
+	if m.sched == nil {
+		main := &Goroutine{
+			ID:     1,
+			status: goroutineRunning,
+		}
+		m.sched = &scheduler{
+			main:       main,
+			current:    main,
+			goroutines: []*Goroutine{main},
+			lastGoID:   1,
+		}
+	}
+	return m.sched
+}
+
+// Returns true if the running goroutine is not the main one.
+func (m *Machine) inGoroutine() bool {
+	return m.sched != nil && m.sched.current != m.sched.main
+}
+
+// NumGoroutine returns the number of goroutines that have not returned,
+// including the running one.
+func (m *Machine) NumGoroutine() int {
+	if m.sched == nil {
+		return 1
+	}
+	return len(m.sched.goroutines)
+}
+
+// GoroutineID returns the ID of the running goroutine.
+// The main goroutine has ID 1.
+func (m *Machine) GoroutineID() int64 {
+	if m.sched == nil {
+		return 1
+	}
+	return m.sched.current.ID
+}
+
+// Starts a new goroutine which will call fv with args upon being scheduled.
+// fv and args are the values evaluated for cx, as left by OpEval.
+func (m *Machine) startGoroutine(cx *CallExpr, fv TypedValue, args []TypedValue) {
+	s := m.getScheduler()
+	m.Alloc.AllocateGoroutine()
+	s.lastGoID++
+	g := &Goroutine{
+		ID:     s.lastGoID,
+		status: goroutineRunnable,
+	}
+	// The goroutine starts as if it had just evaluated cx's
+	// function and arguments, and halts once the call returns.
+	g.ops = make([]Op, goroutineStackSize)
+	g.ops[0] = OpHalt
+	g.ops[1] = OpPrecall
+	g.numOps = 2
+	g.values = make([]TypedValue, max(goroutineStackSize, len(args)+1))
+	g.values[0] = fv
+	copy(g.values[1:], args)
+	g.numValues = len(args) + 1
+	g.exprs = []Expr{cx}
+	g.blocks = []*Block{m.LastBlock()}
+	g.pkg = m.Package
+	g.realm = m.Realm
+	s.goroutines = append(s.goroutines, g)
+	s.runq = append(s.runq, g)
+}
+
+// Marks g, which was waiting, as runnable.
+func (s *scheduler) ready(g *Goroutine) {
+	if g.status != goroutineWaiting {
+		panic("should not happen")
+	}
+	g.status = goroutineRunnable
+	s.runq = append(s.runq, g)
+}
+
+// Saves the state of the running goroutine and switches to the next
+// runnable goroutine. Panics with a deadlock error if there is none.
+func (m *Machine) schedule() {
+	s := m.sched
+	if len(s.runq) == 0 {
+		// Leave the machine in the state of the main goroutine,
+		// so that the error refers to it.
+		if s.current != s.main && s.main.status != goroutineDead {
+			m.switchTo(s.main)
+		}
+		panic(DeadlockError{})
+	}
+	next := s.runq[0]
+	s.runq[0] = nil
+	s.runq = s.runq[1:]
+	m.switchTo(next)
+	next.status = goroutineRunning
+	if resume := next.resume; resume != nil {
+		next.resume = nil
+		resume(m)
+	}
+}
+
+func (m *Machine) switchTo(g *Goroutine) {
+	s := m.sched
+	m.incrCPU(OpCPUGoSwitch)
+	if s.current.status != goroutineDead {
+		s.current.save(m)
+	}
+	g.load(m)
+	s.current = g
+}
+
+// Blocks the running goroutine until it is made ready again by another
+// goroutine, then calls resume (if not nil) to complete the operation.
+func (m *Machine) park(resume func(m *Machine)) {
+	if m.runDepth != 1 {
+		panic("cannot block within a nested machine run")
+	}
+	s := m.getScheduler()
+	g := s.current
+	g.status = goroutineWaiting
+	g.resume = resume
+	m.schedule()
+}
+
+// Yields the processor, letting other runnable goroutines run first.
+func (m *Machine) Gosched() {
+	s := m.sched
+	if s == nil || len(s.runq) == 0 {
+		return
+	}
+	if m.runDepth != 1 {
+		// yielding is only a hint.
+		return
+	}
+	g := s.current
+	g.status = goroutineRunnable
+	s.runq = append(s.runq, g)
+	m.schedule()
+}
+
+// Called when the outermost run of a goroutine other than the main one
+// halts, i.e. when the goroutine returned.
+func (m *Machine) exitGoroutine() {
+	s := m.sched
+	g := s.current
+	g.status = goroutineDead
+	for i, gi := range s.goroutines {
+		if gi == g {
+			s.goroutines = append(s.goroutines[:i], s.goroutines[i+1:]...)
+			break
+		}
+	}
+	m.schedule()
+}
+
+// Switches back to the main goroutine if needed.
+// Used upon release of a machine that panicked
+// while running another goroutine.
+func (m *Machine) restoreMainGoroutine() {
+	s := m.sched
+	if s == nil || s.current == s.main {
+		return
+	}
+	s.current.save(m)
+	s.main.load(m)
+	s.current = s.main
+}
+
+// DeadlockError is raised when all goroutines are blocked.
+// Like in Go, it cannot be recovered.
+type DeadlockError struct{}
+
+func (DeadlockError) Error() string {
+	return "all goroutines are asleep - deadlock!"
+}
+
+func (e DeadlockError) String() string {
+	return e.Error()
+}
+
+//----------------------------------------
+// channels
+
+// chanWaiter is a goroutine blocked on a channel, either on a plain
+// send or receive, or on one of the cases of a select statement.
+type chanWaiter struct {
+	g     *Goroutine
+	sel   *selectWaiter // nil unless blocked on a select
+	index int           // select case index
+	value TypedValue    // value to send, or value received
+	ok    bool          // false if woken up by close
+}
+
+// selectWaiter is shared by the waiters of each case of a select.
+// Only the first case to become ready is completed; the waiters of
+// the other cases are lazily dropped from their channel queues.
+type selectWaiter struct {
+	done  bool
+	index int // index of the completed case
+}
+
+// Removes and returns the first live waiter of q, or nil.
+func dequeueWaiter(q *[]*chanWaiter) *chanWaiter {
+	for len(*q) > 0 {
+		w := (*q)[0]
+		(*q)[0] = nil
+		*q = (*q)[1:]
+		if w.sel != nil {
+			if w.sel.done {
+				continue
+			}
+			w.sel.done = true
+			w.sel.index = w.index
+		}
+		return w
+	}
+	return nil
+}
+
+func hasWaiter(q []*chanWaiter) bool {
+	for _, w := range q {
+		if w.sel == nil || !w.sel.done {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Machine) newChan(size int) *ChanValue {
+	s := m.getScheduler()
+	s.lastChanID++
+	return m.Alloc.NewChan(s.lastChanID, size)
+}
+
+// Returns true if a send on cv would not block.
+// Sending on a closed channel does not block; it panics.
+func (cv *ChanValue) canSend() bool {
+	return cv.Closed || hasWaiter(cv.recvq) || len(cv.Buffer) < cv.Cap
+}
+
+// Returns true if a receive on cv would not block.
+func (cv *ChanValue) canRecv() bool {
+	return cv.Closed || hasWaiter(cv.sendq) || len(cv.Buffer) > 0
+}
+
+// Sends v on cv, which must not block.
+func (m *Machine) chanSend(cv *ChanValue, v TypedValue) {
+	if cv.Closed {
+		m.Panic(typedString("send on closed channel"))
+	}
+	if w := dequeueWaiter(&cv.recvq); w != nil {
+		// hand off directly to the blocked receiver.
+		w.value, w.ok = v, true
+		m.sched.ready(w.g)
+		return
+	}
+	if len(cv.Buffer) < cv.Cap {
+		cv.Buffer = append(cv.Buffer, v)
+		return
+	}
+	panic("should not happen")
+}
+
+// Receives from cv, which must not block.
+// et is the element type of the channel.
+func (m *Machine) chanRecv(cv *ChanValue, et Type) (v TypedValue, ok bool) {
+	if len(cv.Buffer) > 0 {
+		v = cv.Buffer[0]
+		cv.Buffer[0] = TypedValue{}
+		cv.Buffer = cv.Buffer[1:]
+		// refill the buffer from a blocked sender.
+		if w := dequeueWaiter(&cv.sendq); w != nil {
+			cv.Buffer = append(cv.Buffer, w.value)
+			w.value, w.ok = TypedValue{}, true
+			m.sched.ready(w.g)
+		}
+		return v, true
+	}
+	if w := dequeueWaiter(&cv.sendq); w != nil {
+		// receive directly from the blocked sender.
+		v = w.value
+		w.value, w.ok = TypedValue{}, true
+		m.sched.ready(w.g)
+		return v, true
+	}
+	if cv.Closed {
+		return defaultTypedValue(m.Alloc, et), false
+	}
+	panic("should not happen")
+}
+
+// Closes cv, waking up all goroutines blocked on it.
+func (m *Machine) chanClose(cv *ChanValue, et Type) {
+	if cv.Closed {
+		m.Panic(typedString("close of closed channel"))
+	}
+	cv.Closed = true
+	for w := dequeueWaiter(&cv.recvq); w != nil; w = dequeueWaiter(&cv.recvq) {
+		w.value, w.ok = defaultTypedValue(m.Alloc, et), false
+		m.sched.ready(w.g)
+	}
+	for w := dequeueWaiter(&cv.sendq); w != nil; w = dequeueWaiter(&cv.sendq) {
+		w.ok = false // the sender panics upon resuming.
+		m.sched.ready(w.g)
+	}
+}
+
+// Pushes the result(s) of a receive.
+func (m *Machine) pushRecvResults(v TypedValue, ok bool, hasOK bool) {
+	m.PushValue(v)
+	if hasOK {
+		m.PushValue(untypedBool(ok))
+	}
+}
+
+func (m *Machine) doOpSend() {
+	m.PopStmt()
+	// the value is copied like in an assignment, on every path.
+	v := m.PopValue().Copy(m.Alloc)
+	xv := m.PopValue()
+	cv, _ := xv.V.(*ChanValue)
+	if cv == nil {
+		// sending on a nil channel blocks forever.
+		m.park(nil)
+		return
+	}
+	if cv.canSend() {
+		m.chanSend(cv, v)
+		return
+	}
+	w := &chanWaiter{
+		g:     m.getScheduler().current,
+		value: v,
+	}
+	cv.sendq = append(cv.sendq, w)
+	m.park(func(m *Machine) {
+		if !w.ok {
+			m.Panic(typedString("send on closed channel"))
+		}
+	})
+}
+
+func (m *Machine) doOpUrecv() {
+	ux := m.PopExpr().(*UnaryExpr)
+	xv := *m.PopValue()
+	cv, _ := xv.V.(*ChanValue)
+	if cv == nil {
+		// receiving from a nil channel blocks forever.
+		m.park(nil)
+		return
+	}
+	et := baseOf(xv.T).Elem()
+	if cv.canRecv() {
+		v, ok := m.chanRecv(cv, et)
+		m.pushRecvResults(v, ok, ux.HasOK)
+		return
+	}
+	w := &chanWaiter{
+		g: m.getScheduler().current,
+	}
+	cv.recvq = append(cv.recvq, w)
+	m.park(func(m *Machine) {
+		m.pushRecvResults(w.value, w.ok, ux.HasOK)
+	})
+}
+
+func (m *Machine) doOpGo() {
+	gs := m.PopStmt().(*GoStmt)
+	cx := &gs.Call
+	// copy the evaluated func and arguments,
+	// which will be consumed by OpPrecall.
+	vals := m.PopValues(cx.NumArgs + 1)
+	args := make([]TypedValue, len(vals)-1)
+	for i := range args {
+		args[i] = vals[i+1].Copy(m.Alloc)
+	}
+	fv := vals[0]
+	if fv.IsUndefined() {
+		m.Panic(typedString("go of nil func value"))
+	}
+	m.startGoroutine(cx, fv, args)
+}
+
+// A select case, as evaluated for OpSelect.
+type selectCase struct {
+	index  int
+	chtv   TypedValue
+	cv     *ChanValue // nil for nil channels.
+	isSend bool
+	send   TypedValue
+}
+
+// Returns the channel expression of the case, and the value
+// expression if it is a send case. Both are nil for the default case.
+func (cs *SelectCaseStmt) commExprs() (chx Expr, vx Expr) {
+	switch comm := cs.Comm.(type) {
+	case nil:
+		return nil, nil
+	case *SendStmt:
+		return comm.Chan, comm.Value
+	case *ExprStmt:
+		return comm.X.(*UnaryExpr).X, nil
+	case *AssignStmt:
+		return comm.Rhs[0].(*UnaryExpr).X, nil
+	default:
+		panic(fmt.Sprintf("unexpected select case %v", cs.Comm))
+	}
+}
+
+func (m *Machine) doOpSelect() {
+	// NOTE: the select statement is popped by OpPopFrameAndReset.
+	ss := m.PeekStmt1().(*SelectStmt)
+	// Collect the evaluated channels and values to send,
+	// which were pushed in source order.
+	numValues := 0
+	for i := range ss.Cases {
+		chx, vx := ss.Cases[i].commExprs()
+		if chx != nil {
+			numValues++
+		}
+		if vx != nil {
+			numValues++
+		}
+	}
+	vals := m.PopValues(numValues)
+	if len(ss.Cases) > 0 {
+		m.PopBlock() // see doOpExec.
+	}
+	cases := make([]selectCase, 0, len(ss.Cases))
+	dflt := -1
+	vi := 0
+	for i := range ss.Cases {
+		sc := selectCase{index: i}
+		chx, vx := ss.Cases[i].commExprs()
+		if chx == nil {
+			dflt = i
+			continue
+		}
+		sc.chtv = vals[vi]
+		vi++
+		if vx != nil {
+			sc.isSend = true
+			sc.send = vals[vi].Copy(m.Alloc)
+			vi++
+		}
+		sc.cv, _ = sc.chtv.V.(*ChanValue)
+		cases = append(cases, sc)
+	}
+	// Pick the first ready case in source order.
+	for _, sc := range cases {
+		if sc.cv == nil {
+			continue // never ready.
+		}
+		if sc.isSend {
+			if sc.cv.canSend() {
+				m.chanSend(sc.cv, sc.send)
+				m.doSelectCase(ss, sc.index, TypedValue{}, false)
+				return
+			}
+		} else {
+			if sc.cv.canRecv() {
+				v, ok := m.chanRecv(sc.cv, baseOf(sc.chtv.T).Elem())
+				m.doSelectCase(ss, sc.index, v, ok)
+				return
+			}
+		}
+	}
+	if dflt >= 0 {
+		m.doSelectCase(ss, dflt, TypedValue{}, false)
+		return
+	}
+	// Block on all cases.
+	g := m.getScheduler().current
+	sel := &selectWaiter{}
+	waiters := make([]*chanWaiter, len(ss.Cases))
+	numWaiters := 0
+	for _, sc := range cases {
+		if sc.cv == nil {
+			continue
+		}
+		w := &chanWaiter{
+			g:     g,
+			sel:   sel,
+			index: sc.index,
+		}
+		waiters[sc.index] = w
+		numWaiters++
+		if sc.isSend {
+			w.value = sc.send
+			sc.cv.sendq = append(sc.cv.sendq, w)
+		} else {
+			sc.cv.recvq = append(sc.cv.recvq, w)
+		}
+	}
+	if numWaiters == 0 {
+		// select {}, or only nil channels:
+		// blocks forever.
+		m.park(nil)
+		return
+	}
+	m.park(func(m *Machine) {
+		w := waiters[sel.index]
+		if _, ok := ss.Cases[sel.index].Comm.(*SendStmt); ok {
+			if !w.ok {
+				m.Panic(typedString("send on closed channel"))
+			}
+			m.doSelectCase(ss, sel.index, TypedValue{}, false)
+		} else {
+			m.doSelectCase(ss, sel.index, w.value, w.ok)
+		}
+	})
+}
+
+// Executes the body of the selected case, after assigning the received
+// value if the case is of the form `v, ok := <-ch` or `v, ok = <-ch`.
+func (m *Machine) doSelectCase(ss *SelectStmt, index int, v TypedValue, ok bool) {
+	cs := &ss.Cases[index]
+	b := m.Alloc.NewBlock(cs, m.LastBlock())
+	m.PushBlock(b)
+	m.PushOp(OpPopBlock)
+	b.bodyStmt = bodyStmt{
+		Body:          cs.Body,
+		BodyLen:       len(cs.Body),
+		NextBodyIndex: -2,
+	}
+	m.PushOp(OpBody)
+	m.PushStmt(b.GetBodyStmt())
+	as, isAssign := cs.Comm.(*AssignStmt)
+	if !isAssign {
+		return
+	}
+	switch as.Op {
+	case DEFINE:
+		m.PushOp(OpDefine)
+	case ASSIGN:
+		m.PushOp(OpAssign)
+	default:
+		panic("should not happen")
+	}
+	m.PushStmt(as)
+	// NOTE: the received values are pushed after the lhs
+	// are evaluated for assignment, like in doOpExec.
+	if len(as.Lhs) == 2 {
+		m.PushExpr(&ConstExpr{TypedValue: untypedBool(ok)})
+		m.PushOp(OpEval)
+	}
+	m.PushExpr(&ConstExpr{TypedValue: v})
+	m.PushOp(OpEval)
+	if as.Op == ASSIGN {
+		for i := len(as.Lhs) - 1; 0 <= i; i-- {
+			m.PushForPointer(as.Lhs[i])
+		}
+	}
+}
+
+// Implements close() for the uverse.
+func (m *Machine) closeChan(tv TypedValue) {
+	ct, ok := baseOf(tv.T).(*ChanType)
+	if !ok {
+		panic(fmt.Sprintf("invalid operation: close of non-channel %s", tv.T))
+	}
+	cv, _ := tv.V.(*ChanValue)
+	if cv == nil {
+		m.Panic(typedString("close of nil channel"))
+	}
+	m.chanClose(cv, ct.Elt)
+}
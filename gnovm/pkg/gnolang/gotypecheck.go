@@ -160,6 +160,38 @@ type TypeCheckOptions struct {
 	// custom cache, for retaining results across several runs of the type
 	// checker when the packages themselves won't change.
 	Cache TypeCheckCache
+	// if set, records the type information of the checked package, for
+	// tools like the language server.
+	Info *TypeCheckInfo
+}
+
+// TypeCheckInfo records the results of type-checking a package with
+// [TypeCheckMemPackageWithOptions], besides its errors.
+type TypeCheckInfo struct {
+	// the type information of the files of the package, and of its xxx_test
+	// package. Filetests are not recorded.
+	types.Info
+	// the Go ASTs of the files of the package, and of its xxx_test package.
+	Files []*ast.File
+	// the file sets of the package and of its imports, by package, to find
+	// the position of their objects. File names are "<pkgpath>/<file>".
+	Fsets map[*types.Package]*token.FileSet
+}
+
+// NewTypeCheckInfo returns a TypeCheckInfo recording all of the maps of
+// [types.Info].
+func NewTypeCheckInfo() *TypeCheckInfo {
+	return &TypeCheckInfo{
+		Info: types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Implicits:  make(map[ast.Node]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			Scopes:     make(map[ast.Node]*types.Scope),
+		},
+		Fsets: make(map[*types.Package]*token.FileSet),
+	}
 }
 
 // TypeCheckMemPackageWithOptions checks the given mpkg, configured using opts.
@@ -175,6 +207,7 @@ func TypeCheckMemPackageWithOptions(mpkg *std.MemPackage, getter MemPackageGette
 		tcmode:  opts.Mode,
 		getter:  gnoBuiltinsGetterWrapper{getter},
 		cache:   opts.Cache,
+		info:    opts.Info,
 		cfg: &types.Config{
 			Error: func(err error) {
 				gimp.Error(err)
@@ -183,12 +216,13 @@ func TypeCheckMemPackageWithOptions(mpkg *std.MemPackage, getter MemPackageGette
 		errors: nil,
 	}
 	gimp.cfg.Importer = gimp
-	pkg, errs = gimp.typeCheckMemPackage(mpkg, opts.ParseMode)
+	pkg, _, errs = gimp.typeCheckMemPackage(mpkg, opts.ParseMode)
 	return
 }
 
 type gnoImporterResult struct {
 	pkg     *types.Package
+	fset    *token.FileSet
 	err     error
 	pending bool // for cyclic import detection
 }
@@ -204,8 +238,9 @@ type gnoImporter struct {
 	getter  MemPackageGetter
 	cache   TypeCheckCache
 	cfg     *types.Config
-	errors  []error  // there may be many for a single import
-	stack   []string // stack of pkgpaths for cyclic import detection
+	info    *TypeCheckInfo // if set, records the results of the checked package.
+	errors  []error        // there may be many for a single import
+	stack   []string       // stack of pkgpaths for cyclic import detection
 }
 
 // Unused, but satisfies the Importer interface.
@@ -231,6 +266,7 @@ func (gimp *gnoImporter) ImportFrom(pkgPath, _ string, _ types.ImportMode) (*typ
 			result.err = err
 			return nil, err
 		} else {
+			gimp.recordFset(result.pkg, result.fset)
 			return result.pkg, result.err
 		}
 	}
@@ -261,18 +297,37 @@ func (gimp *gnoImporter) ImportFrom(pkgPath, _ string, _ types.ImportMode) (*typ
 		// file with package name xxx_test.
 		pmode = ParseModeIntegration
 	}
-	pkg, errs := gimp.typeCheckMemPackage(mpkg, pmode)
+	pkg, fset, errs := gimp.typeCheckMemPackage(mpkg, pmode)
 	if errs != nil {
 		result.err = errs
 		result.pending = false
 		return nil, errs
 	}
 	result.pkg = pkg
+	result.fset = fset
 	result.err = nil
 	result.pending = false
 	return pkg, errs
 }
 
+// recordFset records the file set of pkg, and of its imports, if gimp.info is
+// set.
+func (gimp *gnoImporter) recordFset(pkg *types.Package, fset *token.FileSet) {
+	if gimp.info == nil || pkg == nil {
+		return
+	}
+	if _, ok := gimp.info.Fsets[pkg]; ok {
+		return
+	}
+	gimp.info.Fsets[pkg] = fset
+	// cached imports are not imported again.
+	for _, imp := range pkg.Imports() {
+		if result, ok := gimp.cache[imp.Path()]; ok && result.pkg == imp {
+			gimp.recordFset(imp, result.fset)
+		}
+	}
+}
+
 // Minimal AST mutation(s) for Go.
 // For gno 0.0 there was nothing to do besides including .gnobuiltins.gno.
 // For gno 0.9 we need to support init(cur realm), main(cur realm) by
@@ -327,14 +382,14 @@ func prepareGoGno0p9(f *ast.File) (err error) {
 //   - pmode: ParseModeAll for type-checking all files.
 //     ParseModeProduction when type-checking imports.
 func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, pmode ParseMode) (
-	pkg *types.Package, errs error,
+	pkg *types.Package, gofset *token.FileSet, errs error,
 ) {
 	// See adr/pr4264_lint_transpile.md
 	// STEP 2: Check gno.mod version.
 	var gnoVersion string
 	mod, err := ParseCheckGnoMod(mpkg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if gimp.tcmode == TCLatestStrict {
 		if mod == nil {
@@ -365,7 +420,14 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, pmode ParseMo
 	// STEP 3: Parse the mem package to Go AST.
 	gofset, allgofs, gofs, _gofs, tgofs, errs := GoParseMemPackage(mpkg, pmode)
 	if errs != nil {
-		return nil, errs
+		return nil, nil, errs
+	}
+
+	// Only the package being checked is recorded, not its imports.
+	var info *types.Info
+	if gimp.info != nil && len(gimp.stack) == 0 {
+		info = &gimp.info.Info
+		gimp.info.Files = append(append(gimp.info.Files, gofs...), _gofs...)
 	}
 
 	// STEP 3: Prepare for Go type-checking.
@@ -400,7 +462,8 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, pmode ParseMo
 	// import failure the Go type checker will continue to try to import
 	// more imports, to collect more errors for the user to see.
 	numErrs := len(gimp.errors)
-	pkg, _ = gimp.cfg.Check(mpkg.Path, gofset, gofs, nil)
+	pkg, _ = gimp.cfg.Check(mpkg.Path, gofset, gofs, info)
+	gimp.recordFset(pkg, gofset)
 	/* NOTE: Uncomment to fail earlier.
 	if len(gimp.errors) != numErrs {
 		errs = multierr.Combine(gimp.errors...)
@@ -419,7 +482,8 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, pmode ParseMo
 	if !strings.HasPrefix(mpkg.Path, "gnobuiltins/") {
 		_gofs2 = append(_gofs, gmgof)
 	}
-	_, _ = gimp.cfg.Check(mpkg.Path+"_test", gofset, _gofs2, nil)
+	_pkg, _ := gimp.cfg.Check(mpkg.Path+"_test", gofset, _gofs2, info)
+	gimp.recordFset(_pkg, gofset)
 	/* NOTE: Uncomment to fail earlier.
 	if len(gimp.errors) != numErrs {
 		errs = multierr.Combine(gimp.errors...)
@@ -461,7 +525,7 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, pmode ParseMo
 		}
 		*/
 	}
-	return pkg, multierr.Combine(gimp.errors[numErrs:]...)
+	return pkg, gofset, multierr.Combine(gimp.errors[numErrs:]...)
 }
 
 func deleteOldIdents(idents map[string]func(), gof *ast.File) {
@@ -41,7 +41,16 @@ type Machine struct {
 	Stage         Stage         // pre for static eval, add for package init, run otherwise
 	ReviveEnabled bool          // true if revive() enabled (only in testing mode for now)
 
+	sched    *scheduler // goroutines, if any was started
+	runDepth int        // number of nested m.Run()
+
 	Debugger Debugger
+	Coverage *Coverage // counts executed statements, if set
+	Profile  *Profile  // samples the call stack, if set
+
+	// OnRealm, if set, is called when the execution switches to another
+	// realm, and may panic to abort it (e.g. for realms paused by a chain).
+	OnRealm func(rlm *Realm)
 
 	// Configuration
 	Output   io.Writer
@@ -80,6 +89,9 @@ type MachineOptions struct {
 	MaxAllocBytes int64      // or 0 for no limit.
 	GasMeter      store.GasMeter
 	ReviveEnabled bool
+	Coverage      *Coverage        // or nil to not count executed statements.
+	Profile       *Profile         // or nil to not sample the call stack.
+	OnRealm       func(rlm *Realm) // or nil; see Machine.OnRealm.
 }
 
 // the machine constructor gets spammed
@@ -142,6 +154,9 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	mm.Debugger.in = opts.Input
 	mm.Debugger.out = output
 	mm.ReviveEnabled = opts.ReviveEnabled
+	mm.Coverage = opts.Coverage
+	mm.Profile = opts.Profile
+	mm.OnRealm = opts.OnRealm
 
 	if pv != nil {
 		mm.SetActivePackage(pv)
@@ -163,6 +178,9 @@ var (
 // and m should not be used after this call. Only Machines initialized with this
 // package's constructors should be released.
 func (m *Machine) Release() {
+	// the op and value stacks of the main goroutine
+	// are the ones allocated with the machine.
+	m.restoreMainGoroutine()
 	// here we zero in the values for the next user
 	m.NumOps = 0
 	m.NumValues = 0
@@ -293,6 +311,285 @@ func (m *Machine) runMemPackage(mpkg *std.MemPackage, save, overrides bool) (*Pa
 	return pn, pv
 }
 
+// UpgradeMemPackage replaces the code of the stored realm at mpkg.Path with
+// the code of mpkg, while keeping its persisted objects.
+//
+// The package-level variables declared in both versions keep their values;
+// the initializers of the new code are only run for the new variables, which
+// see the zero values of the kept ones, and the init functions are not run
+// again. A kept variable must keep its type, and the declared types already
+// stored must be kept with the same underlying type, down to the declared
+// types of the realm they refer to, so that the objects of the realm remain
+// valid; their methods are replaced by the new ones. If the new code declares
+// a function Migrate(cur realm) (or Migrate()), it is called after the
+// upgrade, within the realm, and sees the kept values.
+//
+// NOTE: the function values stored in the objects of the realm, the types
+// declared within functions, and the packages preprocessed before the upgrade
+// still refer to the old code.
+// NOTE: Does not validate the mpkg. Caller must validate the mpkg before
+// calling.
+func (m *Machine) UpgradeMemPackage(mpkg *std.MemPackage) (*PackageNode, *PackageValue) {
+	if bm.OpsEnabled || bm.StorageEnabled {
+		bm.InitMeasure()
+	}
+	if bm.StorageEnabled {
+		defer bm.FinishStore()
+	}
+	// sort mpkg.
+	mpkg.Sort()
+	// get the stored package node.
+	opn, ok := m.Store.GetBlockNodeSafe(PackageNodeLocation(mpkg.Path)).(*PackageNode)
+	if !ok {
+		panic(fmt.Sprintf("package %q does not exist", mpkg.Path))
+	}
+	if !IsRealmPath(mpkg.Path) {
+		panic(fmt.Sprintf("package %q is not a realm", mpkg.Path))
+	}
+	if Name(mpkg.Name) != opn.PkgName {
+		panic(fmt.Sprintf("cannot change the name of package %q from %s to %s",
+			mpkg.Path, opn.PkgName, mpkg.Name))
+	}
+	ovars, otypes := packageDecls(m.Store, opn)
+	// the types of the old code, before the new one replaces them.
+	ovarIDs := make(map[Name]string, len(ovars))
+	for name, ot := range ovars {
+		ovarIDs[name] = upgradeTypeID(ot, mpkg.Path)
+	}
+	otypeIDs := make(map[Name]string, len(otypes))
+	for _, name := range otypes {
+		tid := DeclaredTypeID(mpkg.Path, Location{}, name)
+		if ot, ok := m.Store.GetTypeSafe(tid).(*DeclaredType); ok {
+			otypeIDs[name] = upgradeTypeID(ot.Base, mpkg.Path)
+		}
+	}
+	// run the declarations of the new code in a new package value, without
+	// the initializers of the kept variables.
+	files := ParseMemPackage(mpkg)
+	pn := NewPackageNode(Name(mpkg.Name), mpkg.Path, &FileSet{})
+	npv := pn.NewPackage()
+	m.Store.SetBlockNode(pn)
+	m.SetActivePackage(npv)
+	m.runFileDecls2(false, ovars, files.Files...)
+	npb := npv.GetBlock(m.Store)
+	nvars, _ := packageDecls(m.Store, pn)
+	// check that the stored types are kept, and replace them before
+	// loading the objects of the realm.
+	ntypes := make(map[Name]*DeclaredType)
+	for _, tv := range npb.Values {
+		if tvv, ok := tv.V.(TypeValue); ok {
+			if dt, ok := tvv.Type.(*DeclaredType); ok && dt.PkgPath == mpkg.Path {
+				ntypes[dt.Name] = dt
+			}
+		}
+	}
+	for _, name := range otypes {
+		tid := DeclaredTypeID(mpkg.Path, Location{}, name)
+		otid, ok := otypeIDs[name]
+		if !ok {
+			continue // not stored.
+		}
+		ot := m.Store.GetType(tid).(*DeclaredType)
+		nt, ok := ntypes[name]
+		if !ok {
+			panic(fmt.Sprintf("cannot remove stored type %s", tid))
+		}
+		if otid != upgradeTypeID(nt.Base, mpkg.Path) {
+			panic(fmt.Sprintf("cannot change the underlying type of stored type %s from %s to %s",
+				tid, ot.Base.String(), nt.Base.String()))
+		}
+	}
+	for _, name := range otypes {
+		if _, ok := otypeIDs[name]; ok {
+			m.Store.ReplaceCacheType(ntypes[name])
+		}
+	}
+	// get the stored package.
+	pv := m.Store.GetPackage(mpkg.Path, false)
+	rlm := pv.GetRealm()
+	pb := pv.GetBlock(m.Store)
+	values := make([]TypedValue, len(npb.Values))
+	copy(values, npb.Values)
+	for name, ot := range ovars {
+		nt, ok := nvars[name]
+		if !ok {
+			continue // removed.
+		}
+		if ovarIDs[name] != upgradeTypeID(nt, mpkg.Path) {
+			panic(fmt.Sprintf("cannot change the type of variable %s from %s to %s",
+				name, ot.String(), nt.String()))
+		}
+		oi, _ := opn.GetLocalIndex(name)
+		ni, _ := pn.GetLocalIndex(name)
+		fillValueTV(m.Store, &pb.Values[oi])
+		values[ni] = keepValue(pb.Values[oi], npb.Values[ni])
+	}
+	// replace the values and file blocks of the package.
+	opvos := getChildObjects2(m.Store, pv)
+	opbos := getChildObjects2(m.Store, pb)
+	pb.Values = values
+	pb.Source = pn
+	for _, fb := range npv.FBlocks {
+		fb.(*Block).Parent = pb
+	}
+	pv.FNames = npv.FNames
+	pv.FBlocks = npv.FBlocks
+	pv.fBlocksMap = npv.fBlocksMap
+	didUpdateChildren(rlm, pb, opbos, getChildObjects2(m.Store, pb))
+	didUpdateChildren(rlm, pv, opvos, getChildObjects2(m.Store, pv))
+	// run the migration.
+	m.SetActivePackage(pv)
+	if idx, ok := pn.GetLocalIndex("Migrate"); ok {
+		fv, ok := values[idx].V.(*FuncValue)
+		if !ok {
+			panic("Migrate must be a function")
+		}
+		ft := fv.GetType(m.Store)
+		if len(ft.Results) != 0 || len(ft.Params) != 0 && !ft.IsCrossing() {
+			panic("Migrate must have no parameters and results")
+		}
+		m.PushBlock(pv.GetFileBlock(m.Store, fv.FileName))
+		m.runFunc(StageAdd, "Migrate", true)
+		m.PopBlock()
+	}
+	// save package value and dependencies.
+	rlm.FinalizeRealmTransaction(m.Store)
+	m.Store.SetPackageRealm(rlm)
+	// save declared types.
+	for _, tv := range pb.Values {
+		if tvv, ok := tv.V.(TypeValue); ok {
+			if dt, ok := tvv.Type.(*DeclaredType); ok {
+				m.Store.SetType(dt)
+			}
+		}
+	}
+	// store mempackage.
+	m.Store.AddMemPackage(mpkg, MemPackageTypeAny)
+	return pn, pv
+}
+
+// packageDecls returns the static types of the package-level variables of
+// pn, and the names of its declared types.
+func packageDecls(store Store, pn *PackageNode) (vars map[Name]Type, types []Name) {
+	vars = make(map[Name]Type)
+	for _, fn := range pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			switch d := d.(type) {
+			case *ValueDecl:
+				if d.Const {
+					continue
+				}
+				for _, nx := range d.NameExprs {
+					if nx.Name != blankIdentifier {
+						vars[nx.Name] = pn.GetStaticTypeOf(store, nx.Name)
+					}
+				}
+			case *TypeDecl:
+				if !d.IsAlias && d.Name != blankIdentifier {
+					types = append(types, d.Name)
+				}
+			}
+		}
+	}
+	return vars, types
+}
+
+// upgradeTypeID returns the TypeID of t, followed by the underlying types of
+// the declared types of pkgPath it refers to, so that the types of two
+// versions of a realm are only equal if all of these are the same.
+func upgradeTypeID(t Type, pkgPath string) string {
+	var dts []*DeclaredType
+	seen := make(map[Type]struct{})
+	var visit func(t Type)
+	visitFields := func(fs []FieldType) {
+		for _, f := range fs {
+			visit(f.Type)
+		}
+	}
+	visit = func(t Type) {
+		if t == nil {
+			return
+		}
+		if _, ok := seen[t]; ok {
+			return
+		}
+		seen[t] = struct{}{}
+		switch t := t.(type) {
+		case *DeclaredType:
+			if t.PkgPath == pkgPath {
+				dts = append(dts, t)
+				visit(t.Base)
+			}
+		case *PointerType:
+			visit(t.Elt)
+		case *ArrayType:
+			visit(t.Elt)
+		case *SliceType:
+			visit(t.Elt)
+		case *ChanType:
+			visit(t.Elt)
+		case *MapType:
+			visit(t.Key)
+			visit(t.Value)
+		case *StructType:
+			visitFields(t.Fields)
+		case *FuncType:
+			visitFields(t.Params)
+			visitFields(t.Results)
+		case *InterfaceType:
+			visitFields(t.Methods)
+		}
+	}
+	visit(t)
+	var sb strings.Builder
+	sb.WriteString(t.TypeID().String())
+	for _, dt := range dts {
+		fmt.Fprintf(&sb, ";%s=%s", dt.TypeID().String(), dt.Base.TypeID().String())
+	}
+	return sb.String()
+}
+
+// keepValue returns the old value of a package-level variable, as a heap item
+// if the new variable is one.
+func keepValue(old, new TypedValue) TypedValue {
+	hiv, oldHeap := old.V.(*HeapItemValue)
+	_, newHeap := new.T.(heapItemType)
+	switch {
+	case oldHeap == newHeap:
+		return old
+	case oldHeap:
+		return hiv.Value
+	default:
+		return TypedValue{T: heapItemType{}, V: &HeapItemValue{Value: old}}
+	}
+}
+
+// didUpdateChildren updates the references of po from its old child objects
+// xos to its new child objects cos.
+func didUpdateChildren(rlm *Realm, po Object, xos, cos []Object) {
+	count := make(map[Object]int, len(cos))
+	for _, co := range cos {
+		count[co]++
+	}
+	for _, xo := range xos {
+		count[xo]--
+	}
+	// attach the new children first, so that the kept objects are not
+	// deleted.
+	for _, co := range cos {
+		if count[co] > 0 {
+			count[co]--
+			rlm.DidUpdate(po, nil, co)
+		}
+	}
+	for _, xo := range xos {
+		if count[xo] < 0 {
+			count[xo]++
+			rlm.DidUpdate(po, xo, nil)
+		}
+	}
+}
+
 type redeclarationErrors []Name
 
 func (r redeclarationErrors) Error() string {
@@ -328,7 +625,11 @@ func checkDuplicates(fset *FileSet) error {
 				}
 				name = d.Name
 				if d.IsMethod {
-					name = Name(destar(d.Recv.Type).String()) + "." + name
+					if rn, rxs := genericRecv(d.Recv.Type); len(rxs) > 0 {
+						name = rn + "." + name
+					} else {
+						name = Name(destar(d.Recv.Type).String()) + "." + name
+					}
 				}
 			case *TypeDecl:
 				name = d.Name
@@ -518,6 +819,12 @@ func (m *Machine) PreprocessFiles(pkgName, pkgPath string, fset *FileSet, save,
 // Returns the updated typed values of package.
 // m.Package must match fns's package path.
 func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValue {
+	return m.runFileDecls2(withOverrides, nil, fns...)
+}
+
+// runFileDecls2 is like runFileDecls, but does not run the declarations of
+// variables which are all in kept, which are left undefined.
+func (m *Machine) runFileDecls2(withOverrides bool, kept map[Name]Type, fns ...*FileNode) []TypedValue {
 	// Files' package names must match the machine's active one.
 	// if there is one.
 	for _, fn := range fns {
@@ -588,11 +895,27 @@ func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValu
 	// recursive function for var declarations.
 	var runDeclarationFor func(fn *FileNode, decl Decl)
 	runDeclarationFor = func(fn *FileNode, decl Decl) {
+		if isKeptDecl(decl, kept) {
+			for _, n := range decl.GetDeclNames() {
+				fdeclared[n] = struct{}{}
+			}
+			return
+		}
 		// get fileblock of fn.
 		// fb := pv.GetFileBlock(nil, fn.FileName)
 		// get dependencies of decl.
 		deps := make(map[Name]struct{})
-		findDependentNames(decl, deps)
+		if isGenericDecl(decl) {
+			// generics are not run, but their instances may
+			// depend on other declarations.
+			for _, bn := range pn.getInstanceNodes(decl) {
+				if fd, ok := bn.(*FuncDecl); ok {
+					findDependentNames(fd, deps)
+				}
+			}
+		} else {
+			findDependentNames(decl, deps)
+		}
 		for dep := range deps {
 			// if dep already defined as import, skip.
 			if _, ok := fn.GetLocalIndex(dep); ok {
@@ -631,10 +954,12 @@ func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValu
 			loopfindr = loopfindr[:len(loopfindr)-1]
 		}
 		// run declaration
-		fb := pv.GetFileBlock(m.Store, fn.FileName)
-		m.PushBlock(fb)
-		m.runDeclaration(decl)
-		m.PopBlock()
+		if !isGenericDecl(decl) {
+			fb := pv.GetFileBlock(m.Store, fn.FileName)
+			m.PushBlock(fb)
+			m.runDeclaration(decl)
+			m.PopBlock()
+		}
 		for _, n := range decl.GetDeclNames() {
 			fdeclared[n] = struct{}{}
 		}
@@ -654,6 +979,20 @@ func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValu
 	return updates
 }
 
+// isKeptDecl returns true if decl declares variables which are all in kept.
+func isKeptDecl(decl Decl, kept map[Name]Type) bool {
+	vd, ok := decl.(*ValueDecl)
+	if !ok || vd.Const || len(kept) == 0 {
+		return false
+	}
+	for _, nx := range vd.NameExprs {
+		if _, ok := kept[nx.Name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // Run new init functions.
 // Go spec: "To ensure reproducible initialization
 // behavior, build systems are encouraged to present
@@ -1067,6 +1406,7 @@ const (
 	OpDefine      Op = 0x8C // X... := Y...
 	OpInc         Op = 0x8D // X++
 	OpDec         Op = 0x8E // X--
+	OpSend        Op = 0x8F // X <- Y
 
 	/* Decl operators */
 	OpValueDecl Op = 0x90 // var/const ...
@@ -1081,6 +1421,7 @@ const (
 	OpRangeIterMap      Op = 0xD5
 	OpRangeIterArrayPtr Op = 0xD6
 	OpReturnCallDefers  Op = 0xD7 // XXX rename to OpCallDefers
+	OpRangeIterChan     Op = 0xD8
 	OpVoid              Op = 0xFF // For profiling simple operation
 )
 
@@ -1090,6 +1431,9 @@ const GasFactorCPU int64 = 1
 // "CPU" steps.
 
 func (m *Machine) incrCPU(cycles int64) {
+	if m.Profile != nil {
+		m.Profile.sampleGas(m, overflow.Mulp(cycles, GasFactorCPU))
+	}
 	if m.GasMeter != nil {
 		gasCPU := overflow.Mulp(cycles, GasFactorCPU)
 		m.GasMeter.ConsumeGas(gasCPU, "CPUCycles") // May panic if out of gas.
@@ -1097,6 +1441,12 @@ func (m *Machine) incrCPU(cycles int64) {
 	m.Cycles += cycles
 }
 
+// IncrCPU charges cycles to the machine, like the execution of its
+// operations. It is used by the natives whose cost depends on their input.
+func (m *Machine) IncrCPU(cycles int64) {
+	m.incrCPU(cycles)
+}
+
 const (
 	// CPU cycles
 	/* Control operators */
@@ -1110,8 +1460,9 @@ const (
 	OpCPUCallNativeBody      = 424
 	OpCPUDefer               = 64
 	OpCPUCallDeferNativeBody = 33
-	OpCPUGo                  = 1 // Not yet implemented
-	OpCPUSelect              = 1 // Not yet implemented
+	OpCPUGo                  = 386
+	OpCPUGoSwitch            = 100 // per goroutine switch
+	OpCPUSelect              = 198
 	OpCPUSwitchClause        = 38
 	OpCPUSwitchClauseCase    = 143
 	OpCPUTypeSwitch          = 171
@@ -1132,7 +1483,7 @@ const (
 	OpCPUUneg  = 25
 	OpCPUUnot  = 6
 	OpCPUUxor  = 14
-	OpCPUUrecv = 1 // Not yet implemented
+	OpCPUUrecv = 23
 	OpCPULor   = 26
 	OpCPULand  = 24
 	OpCPUEql   = 160
@@ -1203,6 +1554,7 @@ const (
 	OpCPUDefine      = 111
 	OpCPUInc         = 76
 	OpCPUDec         = 46
+	OpCPUSend        = 10
 
 	/* Decl operators */
 	OpCPUValueDecl = 113
@@ -1217,6 +1569,7 @@ const (
 	OpCPURangeIterMap      = 48
 	OpCPURangeIterArrayPtr = 46
 	OpCPUReturnCallDefers  = 78
+	OpCPURangeIterChan     = 17
 )
 
 //----------------------------------------
@@ -1230,6 +1583,21 @@ func (m *Machine) Run(st Stage) {
 			bm.FinishRun()
 		}()
 	}
+	m.runDepth++
+	defer func() {
+		m.runDepth--
+		if m.Profile != nil {
+			m.Profile.stop()
+		}
+	}()
+	for !m.run() {
+	}
+}
+
+// run runs ops until OpHalt, returning true.
+// It returns false if an exception was raised,
+// after it was pushed to be handled by the next run.
+func (m *Machine) run() (halted bool) {
 	defer func() {
 		r := recover()
 
@@ -1240,7 +1608,7 @@ func (m *Machine) Run(st Stage) {
 					r.Stacktrace = m.Stacktrace()
 				}
 				m.pushPanic(r.Value)
-				m.Run(st)
+				halted = false
 			default:
 				panic(r)
 			}
@@ -1251,6 +1619,9 @@ func (m *Machine) Run(st Stage) {
 		if m.Debugger.enabled {
 			m.Debug()
 		}
+		if m.Profile != nil {
+			m.Profile.sampleOp(m)
+		}
 		op := m.PopOp()
 		if bm.OpsEnabled {
 			// benchmark the operation.
@@ -1269,7 +1640,12 @@ func (m *Machine) Run(st Stage) {
 			if bm.OpsEnabled {
 				bm.StopOpCode()
 			}
-			return
+			if m.runDepth == 1 && m.inGoroutine() {
+				// a goroutine returned.
+				m.exitGoroutine()
+				continue
+			}
+			return true
 		case OpNoop:
 			m.incrCPU(OpCPUNoop)
 			continue
@@ -1313,10 +1689,10 @@ func (m *Machine) Run(st Stage) {
 			m.doOpCallDeferNativeBody()
 		case OpGo:
 			m.incrCPU(OpCPUGo)
-			panic("not yet implemented")
+			m.doOpGo()
 		case OpSelect:
 			m.incrCPU(OpCPUSelect)
-			panic("not yet implemented")
+			m.doOpSelect()
 		case OpSwitchClause:
 			m.incrCPU(OpCPUSwitchClause)
 			m.doOpSwitchClause()
@@ -1544,6 +1920,9 @@ func (m *Machine) Run(st Stage) {
 		case OpDec:
 			m.incrCPU(OpCPUDec)
 			m.doOpDec()
+		case OpSend:
+			m.incrCPU(OpCPUSend)
+			m.doOpSend()
 		/* Decl operators */
 		case OpValueDecl:
 			m.incrCPU(OpCPUValueDecl)
@@ -1564,6 +1943,9 @@ func (m *Machine) Run(st Stage) {
 		case OpRangeIterArrayPtr:
 			m.incrCPU(OpCPURangeIterArrayPtr)
 			m.doOpExec(op)
+		case OpRangeIterChan:
+			m.incrCPU(OpCPURangeIterChan)
+			m.doOpExec(op)
 		case OpRangeIterString:
 			m.incrCPU(OpCPURangeIterString)
 			m.doOpExec(op)
@@ -1925,7 +2307,7 @@ func (m *Machine) PushFrameCall(cx *CallExpr, fv *FuncValue, recv TypedValue, is
 				mrpath,
 			))
 		}
-		m.Realm = pv.GetRealm()
+		m.switchRealm(pv.GetRealm())
 		return
 	}
 
@@ -1978,7 +2360,7 @@ func (m *Machine) PushFrameCall(cx *CallExpr, fv *FuncValue, recv TypedValue, is
 				} else {
 					rlm = objpv.GetRealm()
 				}
-				m.Realm = rlm
+				m.switchRealm(rlm)
 				// DO NOT set DidCrossing here. Make
 				// DidCrossing only happen upon explicit
 				// cross(fn)(...) calls and subsequent calls to
@@ -1997,6 +2379,15 @@ func (m *Machine) PushFrameCall(cx *CallExpr, fv *FuncValue, recv TypedValue, is
 	}
 }
 
+// switchRealm sets the active realm of a call, calling m.OnRealm if it
+// differs from the current one.
+func (m *Machine) switchRealm(rlm *Realm) {
+	if m.OnRealm != nil && rlm != nil && rlm != m.Realm {
+		m.OnRealm(rlm)
+	}
+	m.Realm = rlm
+}
+
 func (m *Machine) PopFrame() Frame {
 	numFrames := len(m.Frames)
 	f := m.Frames[numFrames-1]
@@ -78,6 +78,7 @@ const (
 	LEQ    // <=
 	GEQ    // >=
 	DEFINE // :=
+	TILDE  // ~
 
 	// Keywords
 	BREAK
@@ -149,6 +150,7 @@ const (
 	ATTR_PACKAGE_DECL          GnoAttribute = "ATTR_PACKAGE_DECL"
 	ATTR_PACKAGE_PATH          GnoAttribute = "ATTR_PACKAGE_PATH" // if name expr refers to package.
 	ATTR_FIX_FROM              GnoAttribute = "ATTR_FIX_FROM"     // gno fix this version.
+	ATTR_GENERIC_INSTANCE      GnoAttribute = "ATTR_GENERIC_INSTANCE"
 )
 
 // Embedded in each Node.
@@ -233,6 +235,7 @@ func (*BasicLitExpr) assertNode()      {}
 func (*BinaryExpr) assertNode()        {}
 func (*CallExpr) assertNode()          {}
 func (*IndexExpr) assertNode()         {}
+func (*IndexListExpr) assertNode()     {}
 func (*SelectorExpr) assertNode()      {}
 func (*SliceExpr) assertNode()         {}
 func (*StarExpr) assertNode()          {}
@@ -285,6 +288,7 @@ var (
 	_ Node = &BinaryExpr{}
 	_ Node = &CallExpr{}
 	_ Node = &IndexExpr{}
+	_ Node = &IndexListExpr{}
 	_ Node = &SelectorExpr{}
 	_ Node = &SliceExpr{}
 	_ Node = &StarExpr{}
@@ -349,6 +353,7 @@ func (*BasicLitExpr) assertExpr()     {}
 func (*BinaryExpr) assertExpr()       {}
 func (*CallExpr) assertExpr()         {}
 func (*IndexExpr) assertExpr()        {}
+func (*IndexListExpr) assertExpr()    {}
 func (*SelectorExpr) assertExpr()     {}
 func (*SliceExpr) assertExpr()        {}
 func (*StarExpr) assertExpr()         {}
@@ -366,6 +371,7 @@ var (
 	_ Expr = &BinaryExpr{}
 	_ Expr = &CallExpr{}
 	_ Expr = &IndexExpr{}
+	_ Expr = &IndexListExpr{}
 	_ Expr = &SelectorExpr{}
 	_ Expr = &SliceExpr{}
 	_ Expr = &StarExpr{}
@@ -474,6 +480,12 @@ type IndexExpr struct { // X[Index]
 	HasOK bool // if true, is form: `value, ok := <X>[<Key>]
 }
 
+type IndexListExpr struct { // X[Indices...]
+	Attributes
+	X       Expr  // expression
+	Indices Exprs // type arguments
+}
+
 type SelectorExpr struct { // X.Sel
 	Attributes
 	X    Expr      // expression
@@ -515,8 +527,9 @@ type TypeAssertExpr struct { // X.(Type)
 // (referencing) are represented with RefExpr nodes.
 type UnaryExpr struct { // (Op X)
 	Attributes
-	X  Expr // operand
-	Op Word // operator
+	X     Expr // operand
+	Op    Word // operator
+	HasOK bool // if true, is form: `value, ok := <-<X>`
 }
 
 // MyType{<key>:<value>} struct, array, slice, and map
@@ -713,6 +726,7 @@ type InterfaceTypeExpr struct {
 	Attributes
 	Methods FieldTypeExprs // list of methods
 	Generic Name           // for uverse generics
+	Terms   Exprs          // type set terms of constraints, if any
 }
 
 type ChanDir int
@@ -734,8 +748,9 @@ type ChanTypeExpr struct {
 
 type FuncTypeExpr struct {
 	Attributes
-	Params  FieldTypeExprs // (incoming) parameters, if any.
-	Results FieldTypeExprs // (outgoing) results, if any.
+	TypeParams FieldTypeExprs // type parameters of generic functions, if any.
+	Params     FieldTypeExprs // (incoming) parameters, if any.
+	Results    FieldTypeExprs // (outgoing) results, if any.
 }
 
 type MapTypeExpr struct {
@@ -937,6 +952,7 @@ type RangeStmt struct {
 	IsMap      bool // if X is map type
 	IsString   bool // if X is string type
 	IsArrayPtr bool // if X is array-pointer type
+	IsChan     bool // if X is chan type
 }
 
 type ReturnStmt struct {
@@ -1186,8 +1202,9 @@ func (x *ValueDecl) GetDeclNames() []Name {
 type TypeDecl struct {
 	Attributes
 	NameExpr
-	Type    Expr // Name, SelectorExpr, StarExpr, or XxxTypes
-	IsAlias bool // type alias since Go 1.9
+	TypeParams FieldTypeExprs // type parameters of generic types, if any.
+	Type       Expr           // Name, SelectorExpr, StarExpr, or XxxTypes
+	IsAlias    bool           // type alias since Go 1.9
 }
 
 func (x *TypeDecl) GetDeclNames() []Name {
@@ -1543,6 +1560,9 @@ type PackageNode struct {
 	PkgPath  string
 	PkgName  Name
 	*FileSet // provides .GetDeclFor*()
+
+	instances map[Name]*genericInstance // instances of generic decls, by name.
+	pending   *instanceQueue            // see openInstances().
 }
 
 func PackageNodeLocation(path string) Location {
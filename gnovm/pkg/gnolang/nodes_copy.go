@@ -53,6 +53,13 @@ func (x *IndexExpr) Copy() Node {
 	}
 }
 
+func (x *IndexListExpr) Copy() Node {
+	return &IndexListExpr{
+		X:       x.X.Copy().(Expr),
+		Indices: copyExprs(x.Indices),
+	}
+}
+
 func (x *SelectorExpr) Copy() Node {
 	return &SelectorExpr{
 		X:   x.X.Copy().(Expr),
@@ -84,7 +91,7 @@ func (x *RefExpr) Copy() Node {
 func (x *TypeAssertExpr) Copy() Node {
 	return &TypeAssertExpr{
 		X:    x.X.Copy().(Expr),
-		Type: x.Type.Copy().(Expr),
+		Type: copyExpr(x.Type),
 	}
 }
 
@@ -97,7 +104,7 @@ func (x *UnaryExpr) Copy() Node {
 
 func (x *CompositeLitExpr) Copy() Node {
 	return &CompositeLitExpr{
-		Type: x.Type.Copy().(Expr),
+		Type: copyExpr(x.Type),
 		Elts: copyKVs(x.Elts),
 	}
 }
@@ -141,6 +148,7 @@ func (x *SliceTypeExpr) Copy() Node {
 func (x *InterfaceTypeExpr) Copy() Node {
 	return &InterfaceTypeExpr{
 		Methods: copyFTs(x.Methods),
+		Terms:   copyExprs(x.Terms),
 	}
 }
 
@@ -153,8 +161,9 @@ func (x *ChanTypeExpr) Copy() Node {
 
 func (x *FuncTypeExpr) Copy() Node {
 	return &FuncTypeExpr{
-		Params:  copyFTs(x.Params),
-		Results: copyFTs(x.Results),
+		TypeParams: copyFTs(x.TypeParams),
+		Params:     copyFTs(x.Params),
+		Results:    copyFTs(x.Results),
 	}
 }
 
@@ -275,7 +284,7 @@ func (x *SelectStmt) Copy() Node {
 
 func (x *SelectCaseStmt) Copy() Node {
 	return &SelectCaseStmt{
-		Comm: x.Comm.Copy().(Stmt),
+		Comm: copyStmt(x.Comm),
 		Body: copyStmts(x.Body),
 	}
 }
@@ -289,10 +298,11 @@ func (x *SendStmt) Copy() Node {
 
 func (x *SwitchStmt) Copy() Node {
 	return &SwitchStmt{
-		Init:    copyStmt(x.Init),
-		X:       x.X.Copy().(Expr),
-		Clauses: copyCaseClauses(x.Clauses),
-		VarName: x.VarName,
+		Init:         copyStmt(x.Init),
+		X:            copyExpr(x.X),
+		IsTypeSwitch: x.IsTypeSwitch,
+		Clauses:      copyCaseClauses(x.Clauses),
+		VarName:      x.VarName,
 	}
 }
 
@@ -334,9 +344,10 @@ func (x *ValueDecl) Copy() Node {
 
 func (x *TypeDecl) Copy() Node {
 	return &TypeDecl{
-		NameExpr: *(x.NameExpr.Copy().(*NameExpr)),
-		Type:     x.Type.Copy().(Expr),
-		IsAlias:  x.IsAlias,
+		NameExpr:   *(x.NameExpr.Copy().(*NameExpr)),
+		TypeParams: copyFTs(x.TypeParams),
+		Type:       x.Type.Copy().(Expr),
+		IsAlias:    x.IsAlias,
 	}
 }
 
@@ -45,6 +45,7 @@ var wordTokenStrings = map[Word]string{
 	LEQ:             "<=",
 	GEQ:             ">=",
 	DEFINE:          ":=",
+	TILDE:           "~",
 
 	// Branch operations
 	BREAK:       "break",
@@ -136,6 +137,10 @@ func (x IndexExpr) String() string {
 	return fmt.Sprintf("%s[%s]", x.X, x.Index)
 }
 
+func (x IndexListExpr) String() string {
+	return fmt.Sprintf("%s[%s]", x.X, x.Indices.String())
+}
+
 func (x SelectorExpr) String() string {
 	return fmt.Sprintf("%s.%s", x.X, x.Sel)
 }
@@ -224,6 +229,9 @@ func (x SliceTypeExpr) String() string {
 }
 
 func (x InterfaceTypeExpr) String() string {
+	if len(x.Terms) > 0 {
+		return fmt.Sprintf("interface { %v; %s }", x.Methods, x.Terms.String())
+	}
 	return fmt.Sprintf("interface { %v }", x.Methods)
 }
 
@@ -241,6 +249,10 @@ func (x ChanTypeExpr) String() string {
 }
 
 func (x FuncTypeExpr) String() string {
+	tparams := ""
+	if 0 < len(x.TypeParams) {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
 	params := ""
 	if 0 < len(x.Params) {
 		params = x.Params.String()
@@ -249,7 +261,7 @@ func (x FuncTypeExpr) String() string {
 	if 0 < len(x.Results) {
 		results = " " + x.Results.String()
 	}
-	return fmt.Sprintf("func(%s)%s", params, results)
+	return fmt.Sprintf("func%s(%s)%s", tparams, params, results)
 }
 
 func (x MapTypeExpr) String() string {
@@ -380,6 +392,9 @@ func (x SelectStmt) String() string {
 }
 
 func (x SelectCaseStmt) String() string {
+	if x.Comm == nil {
+		return fmt.Sprintf("default: %s", x.Body.String())
+	}
 	return fmt.Sprintf("case %v: %s", x.Comm.String(), x.Body.String())
 }
 
@@ -449,10 +464,14 @@ func (x ValueDecl) String() string {
 }
 
 func (x TypeDecl) String() string {
+	tparams := ""
+	if 0 < len(x.TypeParams) {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
 	if x.IsAlias {
-		return fmt.Sprintf("type %s = %s", x.Name, x.Type.String())
+		return fmt.Sprintf("type %s%s = %s", x.Name, tparams, x.Type.String())
 	}
-	return fmt.Sprintf("type %s %s", x.Name, x.Type.String())
+	return fmt.Sprintf("type %s%s %s", x.Name, tparams, x.Type.String())
 }
 
 func (x FileNode) String() string {
@@ -448,6 +448,9 @@ func isEql(store Store, lv, rv *TypedValue) bool {
 			}
 		}
 		return lv.V == rv.V
+	case ChanKind:
+		// channels are compared by identity.
+		return lv.V == rv.V
 	case FuncKind:
 		if debug {
 			if lv.V != nil && rv.V != nil {
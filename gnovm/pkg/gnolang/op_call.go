@@ -239,8 +239,10 @@ func (m *Machine) isRealmBoundary(cfr *Frame) bool {
 			// borrow-realms, the storage realm
 			// of a method's receiver.
 			return true
-		} else if m.NumFrames() == 1 {
+		} else if m.NumFrames() == 1 && !m.inGoroutine() {
 			// We are exiting the machine's realm.
+			// (Returning from a goroutine is not a
+			// boundary; main finalizes the realm.)
 			if m.Stage == StageAdd {
 				// Unless StageAdd, where functions are called
 				// during var decls. e.g.
@@ -557,6 +559,11 @@ func (m *Machine) doOpPanic2() {
 	}
 	cfr := m.PopUntilLastCallFrame()
 	if cfr == nil {
+		if m.inGoroutine() {
+			// Like in Go, an unrecovered panic in a
+			// goroutine aborts the whole program.
+			panic(m.makeUnhandledPanicError())
+		}
 		// panic(m.makeUnhandledPanicError())
 		panic("should not happen")
 	}
@@ -321,6 +321,10 @@ func (m *Machine) doOpEval() {
 		// (currently all nodes are cached, but we don't want to cache
 		// all packages too).
 		fillValueTV(m.Store, &tv)
+		if fv, ok := tv.V.(*FuncValue); ok && x.GetAttribute(ATTR_GENERIC_INSTANCE) == true {
+			// instances are shared by all call sites.
+			tv.V = fv.Copy(m.Alloc)
+		}
 		m.PushValue(tv)
 	case *constTypeExpr:
 		m.PopExpr()
@@ -39,7 +39,13 @@ SwitchStmt -> +block
   OpTypeSwitch
 
 SelectStmt ->
-  OpSelectCase +block
+  OpSelect +block
+
+GoStmt ->
+  OpGo
+
+SendStmt ->
+  OpSend
 
 */
 
@@ -334,6 +340,59 @@ func (m *Machine) doOpExec(op Op) {
 				panic("should not happen")
 			}
 		}
+	case OpRangeIterChan:
+		bs := s.(*bodyStmt)
+		switch bs.NextBodyIndex {
+		case -2: // init.
+			bs.NumOps = m.NumOps
+			bs.NumValues = m.NumValues
+			bs.NumExprs = len(m.Exprs)
+			bs.NumStmts = len(m.Stmts)
+			bs.NextBodyIndex++
+			fallthrough
+		case -1: // receive next element.
+			xv := m.PeekValue(1)
+			cv, _ := xv.V.(*ChanValue)
+			if cv == nil {
+				// ranging over a nil channel blocks forever.
+				m.park(nil)
+				return
+			}
+			if !cv.canRecv() {
+				w := &chanWaiter{
+					g: m.getScheduler().current,
+				}
+				cv.recvq = append(cv.recvq, w)
+				m.park(func(m *Machine) {
+					m.rangeChanNext(bs, w.value, w.ok)
+				})
+				return
+			}
+			v, ok := m.chanRecv(cv, baseOf(xv.T).Elem())
+			if !m.rangeChanNext(bs, v, ok) {
+				return
+			}
+			fallthrough
+		default:
+			if bs.NextBodyIndex < bs.BodyLen {
+				next := bs.Body[bs.NextBodyIndex]
+				bs.NextBodyIndex++
+				// continue onto exec stmt.
+				bs.Active = next
+				s = next // switch on bs.Active
+				goto EXEC_SWITCH
+			} else if bs.NextBodyIndex == bs.BodyLen {
+				// set up next assign if needed.
+				if bs.Op == ASSIGN && bs.Key != nil {
+					m.PushForPointer(bs.Key)
+				}
+				bs.NextBodyIndex = -1
+				bs.Active = nil
+				return // redo doOpExec:*bodyStmt
+			} else {
+				panic("should not happen")
+			}
+		}
 	case OpRangeIterMap:
 		bs := s.(*bodyStmt)
 		xv := m.PeekValue(1)
@@ -431,6 +490,9 @@ EXEC_SWITCH:
 	if debug {
 		debug.Printf("EXEC: %v\n", s)
 	}
+	if m.Coverage != nil {
+		m.Coverage.hit(m, s)
+	}
 	switch cs := s.(type) {
 	case *AssignStmt:
 		switch cs.Op {
@@ -587,6 +649,8 @@ EXEC_SWITCH:
 		// TODO: replace with "cs.Op".
 		if cs.IsMap {
 			m.PushOp(OpRangeIterMap)
+		} else if cs.IsChan {
+			m.PushOp(OpRangeIterChan)
 		} else if cs.IsString {
 			m.PushOp(OpRangeIterString)
 		} else if cs.IsArrayPtr {
@@ -623,7 +687,7 @@ EXEC_SWITCH:
 			for {
 				fr := m.LastFrame()
 				switch fr.Source.(type) {
-				case *ForStmt, *RangeStmt, *SwitchStmt:
+				case *ForStmt, *RangeStmt, *SwitchStmt, *SelectStmt:
 					if cs.Label != "" && cs.Label != fr.Label {
 						m.PopFrame()
 					} else {
@@ -725,6 +789,50 @@ EXEC_SWITCH:
 		// evaluate func
 		m.PushExpr(cs.Call.Func)
 		m.PushOp(OpEval)
+	case *GoStmt:
+		m.PushOp(OpGo)
+		// evaluate args
+		args := cs.Call.Args
+		for i := len(args) - 1; 0 <= i; i-- {
+			m.PushExpr(args[i])
+			m.PushOp(OpEval)
+		}
+		// evaluate func
+		m.PushExpr(cs.Call.Func)
+		m.PushOp(OpEval)
+	case *SendStmt:
+		m.PushOp(OpSend)
+		// evaluate value
+		m.PushExpr(cs.Value)
+		m.PushOp(OpEval)
+		// evaluate chan
+		m.PushExpr(cs.Chan)
+		m.PushOp(OpEval)
+	case *SelectStmt:
+		m.PushFrameBasic(cs)
+		m.PushOp(OpPopFrameAndReset)
+		m.PushOp(OpSelect)
+		if len(cs.Cases) == 0 {
+			return
+		}
+		// The case expressions were preprocessed within
+		// their case block, so they are evaluated within
+		// a block of the same depth, popped by OpSelect.
+		b := m.Alloc.NewBlock(&cs.Cases[0], m.LastBlock())
+		m.PushBlock(b)
+		// evaluate the channel and value to send
+		// of each case, in source order.
+		for i := len(cs.Cases) - 1; 0 <= i; i-- {
+			chx, vx := cs.Cases[i].commExprs()
+			if vx != nil {
+				m.PushExpr(vx)
+				m.PushOp(OpEval)
+			}
+			if chx != nil {
+				m.PushExpr(chx)
+				m.PushOp(OpEval)
+			}
+		}
 	case *SwitchStmt:
 		m.PushFrameBasic(cs)
 		m.PushOp(OpPopFrameAndReset)
@@ -974,3 +1082,28 @@ func (m *Machine) doOpSwitchClauseCase() {
 		}
 	}
 }
+
+// Assigns the element received by a range over a channel,
+// or ends the range if the channel was closed (!ok).
+// Returns true if the body is to be executed.
+func (m *Machine) rangeChanNext(bs *bodyStmt, v TypedValue, ok bool) bool {
+	if !ok {
+		// done with range.
+		m.PopFrameAndReset()
+		return false
+	}
+	if bs.Key != nil {
+		switch bs.Op {
+		case ASSIGN:
+			m.PopAsPointer(bs.Key).Assign2(m.Alloc, m.Store, m.Realm, v, false)
+		case DEFINE:
+			knx := bs.Key.(*NameExpr)
+			ptr := m.LastBlock().GetPointerToMaybeHeapDefine(m.Store, knx)
+			ptr.TV.Assign(m.Alloc, v, false)
+		default:
+			panic("should not happen")
+		}
+	}
+	bs.NextBodyIndex++
+	return true
+}
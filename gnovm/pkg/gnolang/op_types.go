@@ -427,8 +427,20 @@ func (m *Machine) doOpStaticTypeOf() {
 			m.PushOp(OpEval)
 		}
 	case *UnaryExpr:
-		m.PushExpr(x.X)
-		m.PushOp(OpStaticTypeOf)
+		if x.Op == ARROW {
+			// the static type of a receive is the element type,
+			// even for the `value, ok := <-ch` form.
+			start := m.NumValues
+			m.PushOp(OpHalt)
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+			m.Run(StageRun)
+			xt := m.ReapValues(start)[0].GetType()
+			m.PushValue(asValue(baseOf(xt).Elem()))
+		} else {
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+		}
 	case *CompositeLitExpr:
 		m.PushExpr(x.Type)
 		m.PushOp(OpEval)
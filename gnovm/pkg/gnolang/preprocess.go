@@ -40,6 +40,11 @@ func PredefineFileSet(store Store, pn *PackageNode, fset *FileSet) {
 		setNodeLocations(pn.PkgPath, fn.FileName, fn)
 		initStaticBlocks(store, pn, fn)
 	}
+	// Generic instances are completed after all declarations.
+	q, opened := openInstances(pn)
+	if opened {
+		defer q.close()
+	}
 	// NOTE: much of what follows is duplicated for a single *FileNode
 	// in the main Preprocess translation function.  Keep synced.
 
@@ -164,6 +169,9 @@ func PredefineFileSet(store Store, pn *PackageNode, fset *FileSet) {
 			}
 		}
 	}
+	if opened {
+		q.flush()
+	}
 }
 
 // Initialize static blocks, and also reserves all names.
@@ -185,6 +193,10 @@ func initStaticBlocks(store Store, ctx BlockNode, nn Node) {
 		switch stage {
 		// ----------------------------------------
 		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				// only instances are initialized.
+				return n, TRANS_SKIP
+			}
 			switch n := n.(type) {
 			case *AssignStmt:
 				if n.Op == DEFINE {
@@ -254,7 +266,7 @@ func initStaticBlocks(store Store, ctx BlockNode, nn Node) {
 						// NOTE: document somewhere.
 						n.Recv.Name = ".recv"
 					}
-				} else {
+				} else if n.GetAttribute(ATTR_GENERIC_INSTANCE) != true {
 					pkg := skipFile(last).(*PackageNode)
 					// special case: if n.Name == "init", assign unique suffix.
 					if n.Name == "init" {
@@ -582,7 +594,16 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				// but for testing convenience we allow
 				// importing directly onto the package.
 				// Uverse requires this.
-				if n.GetAttribute(ATTR_PREDEFINED) == true {
+				if isGenericDecl(n) {
+					switch last.(type) {
+					case *FileNode, *PackageNode:
+					default:
+						panic(fmt.Sprintf("generic type %s cannot be declared inside a function",
+							n.(*TypeDecl).Name))
+					}
+					// only instances are preprocessed; see generics.go.
+					return n, TRANS_SKIP
+				} else if n.GetAttribute(ATTR_PREDEFINED) == true {
 					// skip declarations already predefined
 					// (e.g. through recursion for a dependent)
 				} else {
@@ -600,6 +621,28 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					}
 				}
 
+			// TRANS_ENTER -----------------------
+			case *IndexExpr, *IndexListExpr:
+				// instance of a generic type or function.
+				if ix := instantiateIndex(store, last, n.(Expr)); ix != nil {
+					if ftype == TRANS_COMPOSITE_TYPE {
+						// elide composite lit element (nested) composite types.
+						clx := ns[len(ns)-1].(*CompositeLitExpr)
+						elideCompositeElements(last, clx, evalStaticType(store, last, ix))
+					}
+					return ix, TRANS_SKIP
+				}
+				if _, ok := n.(*IndexListExpr); ok {
+					panic("invalid operation: more than one index")
+				}
+
+			// TRANS_ENTER -----------------------
+			case *CallExpr:
+				// call of a generic function.
+				if fx := instantiateCall(store, last, n); fx != nil {
+					n.Func = fx
+				}
+
 			// TRANS_ENTER -----------------------
 			case *FuncTypeExpr:
 				for i := range n.Params {
@@ -687,10 +730,25 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					}
 					xt = xt.Elem()
 					n.IsArrayPtr = true
+				case ChanKind:
+					if n.Value != nil {
+						panic(fmt.Sprintf("range over %s permits only one iteration variable", n.X.String()))
+					}
+					if baseOf(xt).(*ChanType).Dir == SEND {
+						panic(fmt.Sprintf("invalid operation: range %s receive from send-only channel", n.X.String()))
+					}
+					n.IsChan = true
 				}
 				// key value if define.
 				if n.Op == DEFINE {
-					if xt.Kind() == MapKind {
+					if xt.Kind() == ChanKind {
+						// the received element is assigned to key.
+						if n.Key != nil {
+							et := xt.Elem()
+							kn := n.Key.(*NameExpr).Name
+							last.Define(kn, anyValue(et))
+						}
+					} else if xt.Kind() == MapKind {
 						if n.Key != nil {
 							kt := baseOf(xt).(*MapType).Key
 							kn := n.Key.(*NameExpr).Name
@@ -901,6 +959,12 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 			case *FileNode:
 				// only for imports.
 				pushInitBlock(n, &last, &stack)
+				// Generic instances are completed after all
+				// declarations.
+				q, opened := openInstances(ctxpn)
+				if opened {
+					defer q.close()
+				}
 				{
 					// This logic supports out-of-order
 					// declarations.  (this must happen
@@ -974,6 +1038,9 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 						}
 					}
 				}
+				if opened {
+					q.flush()
+				}
 
 			// TRANS_BLOCK -----------------------
 			default:
@@ -2506,8 +2573,15 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *SendStmt:
-				// Value consts become default *ConstExprs.
-				checkOrConvertType(store, last, n, &n.Value, nil, false)
+				ct, ok := baseOf(evalStaticTypeOf(store, last, n.Chan)).(*ChanType)
+				if !ok {
+					panic(fmt.Sprintf("invalid operation: cannot send to non-channel %s", n.Chan.String()))
+				}
+				if ct.Dir&SEND == 0 {
+					panic(fmt.Sprintf("invalid operation: cannot send to receive-only channel %s", n.Chan.String()))
+				}
+				// Value consts become *ConstExprs of the element type.
+				checkOrConvertType(store, last, n, &n.Value, ct.Elt, false)
 
 			// TRANS_LEAVE -----------------------
 			case *SelectCaseStmt:
@@ -2827,6 +2901,16 @@ func parseMultipleAssignFromOneExpr(
 		}
 		tuple = &tupleType{Elts: []Type{mt.Value, BoolType}}
 		expr.HasOK = true
+	case *UnaryExpr:
+		// Receive case:
+		// var a, b = <-ch
+		// a, b := <-ch
+		if expr.Op != ARROW {
+			panic(fmt.Sprintf("unexpected value expression %s", expr.String()))
+		}
+		et := evalStaticTypeOf(store, bn, expr)
+		tuple = &tupleType{Elts: []Type{et, BoolType}}
+		expr.HasOK = true
 	default:
 		panic(fmt.Sprintf("unexpected value expression type %T", expr))
 	}
@@ -3052,6 +3136,12 @@ func findHeapDefinesByUse(ctx BlockNode, bn BlockNode) {
 		}
 
 		switch stage {
+		// ----------------------------------------
+		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				return n, TRANS_SKIP
+			}
+
 		// ----------------------------------------
 		case TRANS_BLOCK:
 			pushInitBlock(n.(BlockNode), &last, &stack)
@@ -3320,6 +3410,9 @@ func findHeapUsesDemoteDefines(ctx BlockNode, bn BlockNode) {
 
 		// ----------------------------------------
 		case TRANS_ENTER:
+			if isGenericDecl(n) {
+				return n, TRANS_SKIP
+			}
 			switch n := n.(type) {
 			case *NameExpr:
 				// Ignore non-block type paths
@@ -3493,18 +3586,19 @@ func findPackageSelectors(bn BlockNode) {
 	})
 }
 
+// Also returns true for labeled select statements,
+// which like switch statements can only be broken out of.
 func isSwitchLabel(ns []Node, label Name) bool {
-	for {
-		swch := lastSwitch(ns)
-		if swch == nil {
-			break
-		}
-
-		if swch.GetLabel() == label && label != "" {
-			return true
+	if label == "" {
+		return false
+	}
+	for i := len(ns) - 1; 0 <= i; i-- {
+		switch n := ns[i].(type) {
+		case *SwitchStmt, *SelectStmt:
+			if n.GetLabel() == label {
+				return true
+			}
 		}
-
-		ns = ns[:len(ns)-1]
 	}
 
 	return false
@@ -3915,6 +4009,8 @@ func findBreakableNode(last BlockNode, store Store) {
 			return
 		case *SwitchClauseStmt:
 			return
+		case *SelectCaseStmt:
+			return
 		}
 
 		last = last.GetParentNode(store)
@@ -4292,6 +4388,11 @@ func findUndefinedAny(store Store, last BlockNode, x Expr, stack []Name, definin
 		if _, ok := UverseNode().GetLocalIndex(cx.Name); ok {
 			return
 		}
+		if g := lookupGeneric(store, last, cx); g != nil {
+			// generics are defined upon instantiation, so
+			// return any undefined dependencies instead.
+			return findUndefinedGeneric(store, g, make(map[Decl]struct{})), false
+		}
 		/*
 			if _, ok := defining[cx.Name]; !ok {
 				return cx.Name
@@ -4528,6 +4629,17 @@ func findUndefinedAny(store Store, last BlockNode, x Expr, stack []Name, definin
 		if un != "" {
 			return
 		}
+	case *IndexListExpr:
+		un, directR = findUndefinedV(store, last, cx.X, stack, defining, direct, nil)
+		if un != "" {
+			return
+		}
+		for i := range cx.Indices {
+			un, directR = findUndefinedV(store, last, cx.Indices[i], stack, defining, direct, nil)
+			if un != "" {
+				return
+			}
+		}
 	case *constTypeExpr:
 		return
 	case *ConstExpr:
@@ -4617,6 +4729,12 @@ func predefineRecursively(store Store, last BlockNode, d Decl) bool {
 func predefineRecursively2(store Store, last BlockNode, d Decl, stack []Name, defining map[Name]struct{}, direct bool) bool {
 	pkg := packageOf(last)
 
+	// Generic declarations are only predefined upon instantiation.
+	if isGenericDecl(d) {
+		d.SetAttribute(ATTR_PREDEFINED, true)
+		return true
+	}
+
 	// NOTE: predefine fileset breaks up circular definitions like
 	// `var a, b, c = 1, a, b` which is only legal at the file level.
 	for _, dn := range d.GetDeclNames() {
@@ -4820,6 +4938,15 @@ func tryPredefine(store Store, pkg *PackageNode, last BlockNode, d Decl, stack [
 				t = &StructType{}
 			case *StarExpr:
 				t = &PointerType{}
+			case *IndexExpr, *IndexListExpr:
+				// instance of a generic type.
+				un, directR = findUndefinedT(store, last, tx, stack, defining, d.IsAlias, direct)
+				if un != "" {
+					untype = true
+					return
+				}
+				d.Type = Preprocess(store, last, tx).(Expr)
+				t = evalStaticType(store, last, d.Type)
 			case *NameExpr:
 				// check for blank identifier in type
 				// e.g., `type T _`
@@ -5331,6 +5458,11 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 	case *IndexExpr:
 		findDependentNames(cn.X, dst)
 		findDependentNames(cn.Index, dst)
+	case *IndexListExpr:
+		findDependentNames(cn.X, dst)
+		for i := range cn.Indices {
+			findDependentNames(cn.Indices[i], dst)
+		}
 	case *FuncLitExpr:
 		findDependentNames(&cn.Type, dst)
 		for _, n := range cn.GetExternNames() {
@@ -5338,6 +5470,10 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 		}
 	case *constTypeExpr:
 	case *ConstExpr:
+		if cn.GetAttribute(ATTR_GENERIC_INSTANCE) == true {
+			// depends on the generic function.
+			findDependentNames(cn.Source, dst)
+		}
 	case *ImportDecl:
 	case *ValueDecl:
 		if cn.Type != nil {
@@ -0,0 +1,345 @@
+package gnolang
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Profile samples the Gno call stacks of machines, to attribute the ops
+// executed, their time, and the gas consumed to the functions and lines that
+// run them. It is enabled by setting [Machine.Profile], and it can be shared by
+// many machines (but not concurrently).
+//
+// The call stack is sampled before each op is executed, and each time gas is
+// consumed by the machine, whether or not it has a gas meter. Profiles are
+// written in the profile.proto format of pprof, so they can be read with
+// `go tool pprof`.
+type Profile struct {
+	root  profileNode
+	funcs map[BlockNode]*profileFunc
+	locs  map[profileKey]uint64 // location ids, starting at 1.
+	dirs  map[string]string     // package path -> directory of its files.
+	start time.Time
+
+	// the time until the next op is charged to the node of the last op.
+	last     *profileNode
+	lastTime time.Time
+}
+
+// ProfileKind selects the samples written by [Profile.WriteProfile].
+type ProfileKind int
+
+const (
+	ProfileCPU ProfileKind = iota // ops executed, and their time.
+	ProfileGas                    // gas consumed.
+)
+
+// profileKey is a line in a function: the line being run for the innermost
+// call, or the line of the call for its callers.
+type profileKey struct {
+	src  BlockNode
+	line int
+}
+
+type profileFunc struct {
+	id        uint64
+	name      string
+	file      string
+	startLine int
+}
+
+type profileNode struct {
+	key      profileKey
+	parent   *profileNode
+	children map[profileKey]*profileNode
+	ops      int64
+	nanos    int64
+	gas      int64
+}
+
+// NewProfile returns a new, empty Profile.
+func NewProfile() *Profile {
+	return &Profile{
+		funcs: make(map[BlockNode]*profileFunc),
+		locs:  make(map[profileKey]uint64),
+		dirs:  make(map[string]string),
+		start: time.Now(),
+	}
+}
+
+// SetPackageDir sets the directory of the files of pkgPath, such that pprof
+// can find their source. Otherwise, files are named after their package path.
+func (p *Profile) SetPackageDir(pkgPath, dir string) {
+	p.dirs[pkgPath] = dir
+}
+
+// sampleOp is called before each op is executed.
+func (p *Profile) sampleOp(m *Machine) {
+	now := time.Now()
+	if p.last != nil {
+		p.last.nanos += int64(now.Sub(p.lastTime))
+	}
+	p.last = p.node(m)
+	p.last.ops++
+	p.lastTime = now
+}
+
+// sampleGas is called when the machine consumes gas.
+func (p *Profile) sampleGas(m *Machine, gas int64) {
+	p.node(m).gas += gas
+}
+
+// stop is called when the machine stops running, such that the time until it
+// runs again is not charged to its last op.
+func (p *Profile) stop() {
+	if p.last != nil {
+		p.last.nanos += int64(time.Since(p.lastTime))
+		p.last = nil
+	}
+}
+
+// node returns the node of the current call stack of m.
+func (p *Profile) node(m *Machine) *profileNode {
+	n := &p.root
+	var last *Frame
+	for i := range m.Frames {
+		fr := &m.Frames[i]
+		if !fr.IsCall() {
+			continue
+		}
+		if last != nil {
+			n = n.child(p, m, last.Func.GetSource(m.Store), fr.Source.GetLine(), last.Func)
+		}
+		last = fr
+	}
+	if last == nil {
+		if len(m.Blocks) == 0 {
+			return n
+		}
+		// Package initialization, at the top level of a file.
+		src := m.LastBlock().GetSource(m.Store)
+		return n.child(p, m, src, profileLine(m, 0, 0, src), nil)
+	}
+	if last.Func.IsNative() {
+		return n.child(p, m, last.Func.GetSource(m.Store), 0, last.Func)
+	}
+	src := last.Func.GetSource(m.Store)
+	return n.child(p, m, src, profileLine(m, last.NumExprs, last.NumStmts, src), last.Func)
+}
+
+// profileLine returns the line being run by m, with the given numbers of
+// expressions and statements on the stacks of its caller, or the line of src.
+func profileLine(m *Machine, numExprs, numStmts int, src BlockNode) int {
+	if len(m.Exprs) > numExprs {
+		if line := m.PeekExpr(1).GetLine(); line > 0 {
+			return line
+		}
+	}
+	if len(m.Stmts) > numStmts {
+		var stmt Node = m.PeekStmt(1)
+		if bs, ok := stmt.(*bodyStmt); ok {
+			// the statement run in the body, or the loop itself.
+			switch {
+			case bs.Active != nil:
+				stmt = bs.Active
+			case bs.Cond != nil:
+				stmt = bs.Cond
+			}
+		}
+		if line := stmt.GetLine(); line > 0 {
+			return line
+		}
+	}
+	return src.GetLine()
+}
+
+func (n *profileNode) child(p *Profile, m *Machine, src BlockNode, line int, fv *FuncValue) *profileNode {
+	key := profileKey{src, line}
+	if c, ok := n.children[key]; ok {
+		return c
+	}
+	if _, ok := p.funcs[src]; !ok {
+		p.funcs[src] = &profileFunc{
+			id:        uint64(len(p.funcs) + 1),
+			name:      profileFuncName(m, src, fv),
+			file:      p.fileName(src, fv),
+			startLine: src.GetLine(),
+		}
+	}
+	if _, ok := p.locs[key]; !ok {
+		p.locs[key] = uint64(len(p.locs) + 1)
+	}
+	if n.children == nil {
+		n.children = make(map[profileKey]*profileNode)
+	}
+	c := &profileNode{key: key, parent: n}
+	n.children[key] = c
+	return c
+}
+
+func profileFuncName(m *Machine, src BlockNode, fv *FuncValue) string {
+	if fv == nil {
+		if m.Package == nil {
+			return "init"
+		}
+		return m.Package.PkgPath + ".init"
+	}
+	if fv.IsClosure || fv.Name == "" {
+		return fmt.Sprintf("%v.func@%d", fv.PkgPath, src.GetLine())
+	}
+	return debugFrameName(fv)
+}
+
+func (p *Profile) fileName(src BlockNode, fv *FuncValue) string {
+	pkgPath, file := src.GetLocation().PkgPath, src.GetLocation().File
+	if fv != nil {
+		pkgPath, file = fv.PkgPath, fv.FileName
+	}
+	if dir, ok := p.dirs[pkgPath]; ok {
+		return filepath.Join(dir, file)
+	}
+	return pkgPath + "/" + file
+}
+
+// WriteProfile writes the samples of kind as a gzipped profile.proto.
+func (p *Profile) WriteProfile(w io.Writer, kind ProfileKind) error {
+	var (
+		b       []byte
+		strs    = map[string]int64{"": 0}
+		strList = []string{""}
+	)
+	str := func(s string) int64 {
+		if i, ok := strs[s]; ok {
+			return i
+		}
+		strs[s] = int64(len(strList))
+		strList = append(strList, s)
+		return strs[s]
+	}
+	valueType := func(num protowire.Number, typ, unit string) {
+		var vt []byte
+		vt = protowire.AppendTag(vt, 1, protowire.VarintType)
+		vt = protowire.AppendVarint(vt, uint64(str(typ)))
+		vt = protowire.AppendTag(vt, 2, protowire.VarintType)
+		vt = protowire.AppendVarint(vt, uint64(str(unit)))
+		b = protowire.AppendTag(b, num, protowire.BytesType)
+		b = protowire.AppendBytes(b, vt)
+	}
+
+	// sample_type
+	var values func(n *profileNode) []int64
+	switch kind {
+	case ProfileCPU:
+		valueType(1, "samples", "count")
+		valueType(1, "cpu", "nanoseconds")
+		values = func(n *profileNode) []int64 { return []int64{n.ops, n.nanos} }
+	case ProfileGas:
+		valueType(1, "gas", "count")
+		values = func(n *profileNode) []int64 { return []int64{n.gas} }
+	default:
+		return fmt.Errorf("unknown profile kind %d", kind)
+	}
+
+	// sample
+	var walk func(n *profileNode)
+	walk = func(n *profileNode) {
+		if vs := values(n); n != &p.root && !allZero(vs) {
+			var s, ids, vals []byte
+			for c := n; c != &p.root; c = c.parent {
+				ids = protowire.AppendVarint(ids, p.locs[c.key])
+			}
+			for _, v := range vs {
+				vals = protowire.AppendVarint(vals, uint64(v))
+			}
+			s = protowire.AppendTag(s, 1, protowire.BytesType)
+			s = protowire.AppendBytes(s, ids)
+			s = protowire.AppendTag(s, 2, protowire.BytesType)
+			s = protowire.AppendBytes(s, vals)
+			b = protowire.AppendTag(b, 2, protowire.BytesType)
+			b = protowire.AppendBytes(b, s)
+		}
+		// in a deterministic order.
+		keys := make([]profileKey, 0, len(n.children))
+		for k := range n.children {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return p.locs[keys[i]] < p.locs[keys[j]] })
+		for _, k := range keys {
+			walk(n.children[k])
+		}
+	}
+	walk(&p.root)
+
+	// location
+	keys := make([]profileKey, len(p.locs))
+	for k, id := range p.locs {
+		keys[id-1] = k
+	}
+	for i, k := range keys {
+		var line, loc []byte
+		line = protowire.AppendTag(line, 1, protowire.VarintType)
+		line = protowire.AppendVarint(line, p.funcs[k.src].id)
+		line = protowire.AppendTag(line, 2, protowire.VarintType)
+		line = protowire.AppendVarint(line, uint64(k.line))
+		loc = protowire.AppendTag(loc, 1, protowire.VarintType)
+		loc = protowire.AppendVarint(loc, uint64(i+1))
+		loc = protowire.AppendTag(loc, 4, protowire.BytesType)
+		loc = protowire.AppendBytes(loc, line)
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, loc)
+	}
+
+	// function
+	funcs := make([]*profileFunc, len(p.funcs))
+	for _, f := range p.funcs {
+		funcs[f.id-1] = f
+	}
+	for _, f := range funcs {
+		var fn []byte
+		fn = protowire.AppendTag(fn, 1, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, f.id)
+		fn = protowire.AppendTag(fn, 2, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(str(f.name)))
+		fn = protowire.AppendTag(fn, 3, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(str(f.name)))
+		fn = protowire.AppendTag(fn, 4, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(str(f.file)))
+		fn = protowire.AppendTag(fn, 5, protowire.VarintType)
+		fn = protowire.AppendVarint(fn, uint64(f.startLine))
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendBytes(b, fn)
+	}
+
+	// time_nanos, duration_nanos
+	b = protowire.AppendTag(b, 9, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(p.start.UnixNano()))
+	b = protowire.AppendTag(b, 10, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(time.Since(p.start)))
+
+	// string_table, which must be last as str adds to it.
+	for _, s := range strList {
+		b = protowire.AppendTag(b, 6, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(b); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func allZero(vs []int64) bool {
+	for _, v := range vs {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
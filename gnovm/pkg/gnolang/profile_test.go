@@ -0,0 +1,173 @@
+package gnolang
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestProfile(t *testing.T) {
+	const src = `package prof
+
+func Sum(n int) int {
+	s := 0
+	for i := 0; i < n; i++ {
+		s += double(i)
+	}
+	return s
+}
+
+func double(i int) int {
+	return i * 2
+}
+`
+	prof := NewProfile()
+	db := memdb.NewMemDB()
+	baseStore := dbadapter.StoreConstructor(db, stypes.StoreOptions{})
+	iavlStore := iavl.StoreConstructor(db, stypes.StoreOptions{})
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath: "gno.land/p/prof",
+		Store:   NewStore(nil, baseStore, iavlStore),
+	})
+	defer m.Release()
+	m.RunMemPackage(&std.MemPackage{
+		Name:  "prof",
+		Path:  "gno.land/p/prof",
+		Files: []*std.MemFile{{Name: "prof.gno", Body: src}},
+	}, true)
+	m.Profile = prof
+	cycles := m.Cycles
+	m.RunStatement(StageRun, S(Call(X("Sum"), 10)))
+	cycles = m.Cycles - cycles
+
+	// gas, by stack from the leaf.
+	var buf bytes.Buffer
+	require.NoError(t, prof.WriteProfile(&buf, ProfileGas))
+	types, samples := decodeProfile(t, buf.Bytes())
+	assert.Equal(t, []string{"gas/count"}, types)
+	var total int64
+	for _, v := range samples {
+		total += v[0]
+	}
+	assert.Equal(t, cycles*GasFactorCPU, total)
+	assert.NotZero(t, samples["gno.land/p/prof.Sum:6"])
+	assert.NotZero(t, samples["gno.land/p/prof.double:12 gno.land/p/prof.Sum:6"])
+	assert.NotZero(t, samples["gno.land/p/prof.Sum:5"])
+	for stack := range samples {
+		assert.NotContains(t, stack, "double:0", "no line for %s", stack)
+	}
+
+	// ops and time.
+	buf.Reset()
+	require.NoError(t, prof.WriteProfile(&buf, ProfileCPU))
+	types, samples = decodeProfile(t, buf.Bytes())
+	assert.Equal(t, []string{"samples/count", "cpu/nanoseconds"}, types)
+	assert.Greater(t, samples["gno.land/p/prof.double:12 gno.land/p/prof.Sum:6"][0], int64(10))
+}
+
+// decodeProfile returns the sample types of a profile.proto, and the values of
+// its samples by stack, written as "func:line" from the leaf.
+func decodeProfile(t *testing.T, gz []byte) ([]string, map[string][]int64) {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(gz))
+	require.NoError(t, err)
+	b, err := io.ReadAll(zr)
+	require.NoError(t, err)
+
+	// fields reads the fields of a message, with varints as uint64.
+	fields := func(b []byte) (fs []struct {
+		num protowire.Number
+		v   any
+	},
+	) {
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			require.GreaterOrEqual(t, n, 0)
+			b = b[n:]
+			var v any
+			switch typ {
+			case protowire.VarintType:
+				v, n = protowire.ConsumeVarint(b)
+			case protowire.BytesType:
+				v, n = protowire.ConsumeBytes(b)
+			default:
+				t.Fatalf("unexpected wire type %v", typ)
+			}
+			require.GreaterOrEqual(t, n, 0)
+			b = b[n:]
+			fs = append(fs, struct {
+				num protowire.Number
+				v   any
+			}{num, v})
+		}
+		return fs
+	}
+	varints := func(b []byte) (vs []uint64) {
+		for len(b) > 0 {
+			v, n := protowire.ConsumeVarint(b)
+			require.GreaterOrEqual(t, n, 0)
+			vs = append(vs, v)
+			b = b[n:]
+		}
+		return vs
+	}
+
+	var (
+		strs      []string
+		types     [][2]uint64
+		rawSamps  [][2][]uint64
+		funcNames = map[uint64]uint64{}    // function id -> name
+		locs      = map[uint64][2]uint64{} // location id -> function id, line
+	)
+	for _, f := range fields(b) {
+		switch f.num {
+		case 1:
+			vt := fields(f.v.([]byte))
+			types = append(types, [2]uint64{vt[0].v.(uint64), vt[1].v.(uint64)})
+		case 2:
+			s := fields(f.v.([]byte))
+			rawSamps = append(rawSamps, [2][]uint64{varints(s[0].v.([]byte)), varints(s[1].v.([]byte))})
+		case 4:
+			l := fields(f.v.([]byte))
+			line := fields(l[1].v.([]byte))
+			locs[l[0].v.(uint64)] = [2]uint64{line[0].v.(uint64), line[1].v.(uint64)}
+		case 5:
+			fn := fields(f.v.([]byte))
+			funcNames[fn[0].v.(uint64)] = fn[1].v.(uint64)
+		case 6:
+			strs = append(strs, string(f.v.([]byte)))
+		}
+	}
+
+	var typeNames []string
+	for _, vt := range types {
+		typeNames = append(typeNames, strs[vt[0]]+"/"+strs[vt[1]])
+	}
+	samples := map[string][]int64{}
+	for _, s := range rawSamps {
+		var stack string
+		for i, id := range s[0] {
+			if i > 0 {
+				stack += " "
+			}
+			loc := locs[id]
+			stack += fmt.Sprintf("%s:%d", strs[funcNames[loc[0]]], loc[1])
+		}
+		for _, v := range s[1] {
+			samples[stack] = append(samples[stack], int64(v))
+		}
+	}
+	return typeNames, samples
+}
@@ -121,6 +121,8 @@ type Realm struct {
 	Path string
 	Time uint64
 
+	sumDiff int64 // bytes added (or removed) by the realm transaction.
+
 	newCreated []Object
 	newDeleted []Object
 	newEscaped []Object
@@ -381,6 +383,11 @@ func (rlm *Realm) FinalizeRealmTransaction(store Store) {
 	rlm.saveUnsavedObjects(store)
 	// delete all deleted objects.
 	rlm.removeDeletedObjects(store)
+	// account for the storage diff of the transaction.
+	if rlm.sumDiff != 0 {
+		store.AddRealmStorageDiff(rlm.Path, rlm.sumDiff)
+		rlm.sumDiff = 0
+	}
 	// reset realm state for new transaction.
 	rlm.clearMarks()
 }
@@ -808,7 +815,7 @@ func (rlm *Realm) saveObject(store Store, oo Object) {
 	}
 	// set object to store.
 	// NOTE: also sets the hash to object.
-	store.SetObject(oo)
+	rlm.sumDiff += store.SetObject(oo)
 	// set index.
 	if oo.GetIsEscaped() {
 		// XXX save oid->hash to iavl.
@@ -820,7 +827,7 @@ func (rlm *Realm) saveObject(store Store, oo Object) {
 
 func (rlm *Realm) removeDeletedObjects(store Store) {
 	for _, do := range rlm.deleted {
-		store.DelObject(do)
+		rlm.sumDiff += store.DelObject(do)
 	}
 }
 
@@ -945,6 +952,9 @@ func getChildObjects(val Value, more []Value) []Value {
 	case *HeapItemValue:
 		more = getSelfOrChildObjects(cv.Value.V, more)
 		return more
+	case *ChanValue:
+		// channels only live for the duration of a transaction.
+		panic("cannot persist channel values")
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
@@ -952,6 +962,22 @@ func getChildObjects(val Value, more []Value) []Value {
 	}
 }
 
+// GetChildRefs returns the references to the child objects of an object as
+// persisted, including the references to parent blocks of closures.
+func GetChildRefs(oo Object) []RefValue {
+	chos := getChildObjects(oo, nil)
+	if fv, ok := oo.(*FuncValue); ok {
+		chos = append(chos, fv.Parent)
+	}
+	refs := make([]RefValue, 0, len(chos))
+	for _, child := range chos {
+		if ref, ok := child.(RefValue); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
 // like getChildObjects() but loads RefValues into objects.
 func getChildObjects2(store Store, val Value) []Object {
 	chos := getChildObjects(val, nil)
@@ -1229,6 +1255,8 @@ func copyValueWithRefs(val Value) Value {
 			Func:       fnc,
 			Receiver:   rtv,
 		}
+	case *ChanValue:
+		panic("cannot persist channel values")
 	case *MapValue:
 		list := &MapList{}
 		for cur := cv.List.Head; cur != nil; cur = cur.Next {
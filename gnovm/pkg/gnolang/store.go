@@ -1,6 +1,7 @@
 package gnolang
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"iter"
@@ -45,11 +46,12 @@ type Store interface {
 	SetPackageRealm(*Realm)
 	GetObject(oid ObjectID) Object
 	GetObjectSafe(oid ObjectID) Object
-	SetObject(Object)
-	DelObject(Object)
+	SetObject(Object) int64 // returns the size difference of the object in bytes
+	DelObject(Object) int64 // returns the negative size of the object in bytes
 	GetType(tid TypeID) Type
 	GetTypeSafe(tid TypeID) Type
 	SetCacheType(Type)
+	ReplaceCacheType(Type) // for package upgrades
 	SetType(Type)
 	GetPackageNode(pkgPath string) *PackageNode
 	GetBlockNode(Location) BlockNode
@@ -65,6 +67,9 @@ type Store interface {
 	AddMemPackage(mpkg *std.MemPackage, mtype MemPackageType)
 	GetMemPackage(path string) *std.MemPackage
 	GetMemFile(path string, name string) *std.MemFile
+	GetObjectProofChain(oid ObjectID) (chain [][]byte, iavlKey []byte) // for proofs
+	AddRealmStorageDiff(rlmpath string, diff int64)                    // for storage deposits
+	RealmStorageDiffs() map[string]int64                               // for storage deposits
 	FindPathsByPrefix(prefix string) iter.Seq[string]
 	IterMemPackage() <-chan *std.MemPackage
 	ClearObjectCache() // run before processing a message
@@ -147,6 +152,9 @@ type defaultStore struct {
 	opslog  io.Writer // for logging store operations.
 	current []string  // for detecting import cycles.
 
+	// bytes added (or removed) per realm path in the transaction.
+	realmStorageDiffs map[string]int64
+
 	// gas
 	gasMeter  store.GasMeter
 	gasConfig GasConfig
@@ -167,6 +175,9 @@ func NewStore(alloc *Allocator, baseStore, iavlStore store.Store) *defaultStore
 		pkgGetter:      nil,
 		nativeResolver: nil,
 		gasConfig:      DefaultGasConfig(),
+
+		// transient
+		realmStorageDiffs: make(map[string]int64),
 	}
 	InitStoreCaches(ds)
 	return ds
@@ -200,8 +211,9 @@ func (ds *defaultStore) BeginTransaction(baseStore, iavlStore store.Store, gasMe
 		gasConfig: ds.gasConfig,
 
 		// transient
-		current: nil,
-		opslog:  nil,
+		current:           nil,
+		opslog:            nil,
+		realmStorageDiffs: make(map[string]int64),
 	}
 	ds2.SetCachePackage(Uverse())
 
@@ -385,7 +397,7 @@ func (ds *defaultStore) SetPackageRealm(rlm *Realm) {
 	bz := amino.MustMarshal(rlm)
 	gas := overflow.Mulp(ds.gasConfig.GasSetPackageRealm, store.Gas(len(bz)))
 	ds.consumeGas(gas, GasSetPackageRealmDesc)
-	ds.baseStore.Set([]byte(key), bz)
+	ds.setCommitted([]byte(key), bz)
 	size = len(bz)
 }
 
@@ -468,7 +480,7 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 
 // NOTE: unlike GetObject(), SetObject() is also used to persist updated
 // package values.
-func (ds *defaultStore) SetObject(oo Object) {
+func (ds *defaultStore) SetObject(oo Object) int64 {
 	if bm.OpsEnabled {
 		bm.PauseOpCode()
 		defer bm.ResumeOpCode()
@@ -527,13 +539,18 @@ func (ds *defaultStore) SetObject(oo Object) {
 		}
 	}
 	// save bytes to backend.
+	var diff int64
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
 		hashbz := make([]byte, len(hash)+len(bz))
 		copy(hashbz, hash.Bytes())
 		copy(hashbz[HashSize:], bz)
+		if !oo.GetIsNewReal() {
+			diff -= int64(len(ds.baseStore.Get([]byte(key))))
+		}
 		ds.baseStore.Set([]byte(key), hashbz)
 		size = len(hashbz)
+		diff += int64(size)
 	}
 	// save object to cache.
 	if debug {
@@ -549,13 +566,14 @@ func (ds *defaultStore) SetObject(oo Object) {
 		}
 	}
 	ds.cacheObjects[oid] = oo
-	// if escaped, add hash to iavl.
-	if oo.GetIsEscaped() && ds.iavlStore != nil {
+	// add hash to iavl, so the object can be verified, see VerifyBackend.
+	if ds.iavlStore != nil {
 		var key, value []byte
 		key = []byte(oid.String())
 		value = hash.Bytes()
 		ds.iavlStore.Set(key, value)
 	}
+	return diff
 }
 
 func (ds *defaultStore) loadForLog(oid ObjectID) Object {
@@ -570,7 +588,173 @@ func (ds *defaultStore) loadForLog(oid ObjectID) Object {
 	return oo
 }
 
-func (ds *defaultStore) DelObject(oo Object) {
+// setCommitted sets an entry of the base store which isn't an object, and
+// commits its hash in the iavl store, so the entry can be verified with
+// VerifyBackend.
+func (ds *defaultStore) setCommitted(key, bz []byte) {
+	ds.baseStore.Set(key, bz)
+	if ds.iavlStore != nil {
+		ds.iavlStore.Set([]byte(backendHashKey(string(key))), HashBytes(bz).Bytes())
+	}
+}
+
+// decodeObject decodes the persisted bytes of an object.
+func decodeObject(bz []byte) (oo Object, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decoding object: %v", r)
+		}
+	}()
+	if err := amino.Unmarshal(bz, &oo); err != nil {
+		return nil, fmt.Errorf("decoding object: %w", err)
+	}
+	if oo == nil {
+		return nil, fmt.Errorf("decoding object: nil object")
+	}
+	return oo, nil
+}
+
+// isProofRoot returns whether the hash of the object is committed in the iavl
+// store ever since it was saved. The hashes of the other objects are only
+// committed since restored states are verified, so they are proven by their
+// owner.
+func isProofRoot(oo Object) bool {
+	if _, ok := oo.(*PackageValue); ok {
+		return true
+	}
+	return oo.GetIsEscaped()
+}
+
+// GetObjectProofChain returns the persisted bytes of an object, followed by
+// the persisted bytes of its owners up to the first escaped object or package
+// value, whose hash is committed in the iavl store with iavlKey. Each object
+// is referenced with its hash by the next one, so the chain proves the object.
+// It returns nil if the object doesn't exist.
+func (ds *defaultStore) GetObjectProofChain(oid ObjectID) (chain [][]byte, iavlKey []byte) {
+	for {
+		hashbz := ds.baseStore.Get([]byte(backendObjectKey(oid)))
+		if hashbz == nil {
+			return nil, nil
+		}
+		bz := hashbz[HashSize:]
+		chain = append(chain, bz)
+		var oo Object
+		amino.MustUnmarshal(bz, &oo)
+		if isProofRoot(oo) {
+			return chain, []byte(oid.String())
+		}
+		oid = oo.GetObjectInfo().OwnerID // not loaded, see GetOwnerID.
+		if oid.IsZero() {
+			return nil, nil
+		}
+	}
+}
+
+// VerifyBackend verifies the entries of baseStore against the hashes committed
+// in iavlStore, e.g. once they are restored from a state sync snapshot. The
+// hashes of the objects are committed by SetObject, and the hashes of the
+// other entries by setCommitted. The keys
+// for which ignore returns true are not gno entries, and are not verified.
+func VerifyBackend(baseStore, iavlStore store.Store, ignore func(key []byte) bool) error {
+	iter := baseStore.Iterator(nil, nil)
+	defer iter.Close()
+
+	for ; iter.Valid(); iter.Next() {
+		key, value := string(iter.Key()), iter.Value()
+		if ignore != nil && ignore(iter.Key()) {
+			continue
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(key, "oid:") && !strings.HasSuffix(key, "#realm"):
+			err = verifyBackendObject(iavlStore, key, value)
+		case strings.HasPrefix(key, "oid:"), strings.HasPrefix(key, "tid:"), strings.HasPrefix(key, "pkgidx:"):
+			hash := iavlStore.Get([]byte(backendHashKey(key)))
+			if !bytes.Equal(hash, HashBytes(value).Bytes()) {
+				err = fmt.Errorf("hash of %s is not committed", key)
+			}
+		default:
+			err = fmt.Errorf("unexpected key %q", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// The entries with a committed hash must all be there.
+	hiter := iavlStore.Iterator(nil, nil)
+	defer hiter.Close()
+
+	for ; hiter.Valid(); hiter.Next() {
+		var key string
+		if oid, ok := parseObjectIAVLKey(hiter.Key()); ok {
+			key = backendObjectKey(oid)
+		} else if k, ok := strings.CutPrefix(string(hiter.Key()), backendHashKey("")); ok {
+			key = k
+		} else {
+			continue
+		}
+		if !baseStore.Has([]byte(key)) {
+			return fmt.Errorf("missing entry %s", key)
+		}
+	}
+
+	return nil
+}
+
+// parseObjectIAVLKey returns the object id of an iavl key set by SetObject.
+func parseObjectIAVLKey(key []byte) (oid ObjectID, ok bool) {
+	if len(key) <= HashSize*2 || key[HashSize*2] != ':' {
+		return oid, false
+	}
+	if err := oid.UnmarshalAmino(string(key)); err != nil {
+		return oid, false
+	}
+	return oid, oid.String() == string(key)
+}
+
+// verifyBackendObject verifies the object entry key of baseStore.
+func verifyBackendObject(iavlStore store.Store, key string, hashbz []byte) error {
+	var oid ObjectID
+	if err := oid.UnmarshalAmino(strings.TrimPrefix(key, "oid:")); err != nil {
+		return fmt.Errorf("invalid object key %s: %w", key, err)
+	}
+	if len(hashbz) < HashSize {
+		return fmt.Errorf("invalid object %s", oid)
+	}
+	hash, bz := NewHashlet(hashbz[:HashSize]), hashbz[HashSize:]
+	if HashBytes(bz) != hash {
+		return fmt.Errorf("object %s does not match its hash", oid)
+	}
+	oo, err := decodeObject(bz)
+	if err != nil {
+		return fmt.Errorf("invalid object %s: %w", oid, err)
+	}
+	if oo.GetObjectID() != oid {
+		return fmt.Errorf("object id mismatch: expected %s but got %s", oid, oo.GetObjectID())
+	}
+	committed := iavlStore.Get([]byte(oid.String()))
+	if !bytes.Equal(committed, hash.Bytes()) {
+		return fmt.Errorf("hash of object %s is not committed", oid)
+	}
+	return nil
+}
+
+// AddRealmStorageDiff is called by the realm rlmpath at the end of its
+// finalization, with the bytes added (or removed) by the saved and deleted
+// objects.
+func (ds *defaultStore) AddRealmStorageDiff(rlmpath string, diff int64) {
+	ds.realmStorageDiffs[rlmpath] += diff
+}
+
+// RealmStorageDiffs returns the bytes added (or removed) per realm path since
+// the object cache was last cleared, which is before processing a message.
+func (ds *defaultStore) RealmStorageDiffs() map[string]int64 {
+	return ds.realmStorageDiffs
+}
+
+func (ds *defaultStore) DelObject(oo Object) int64 {
 	if bm.OpsEnabled {
 		bm.PauseOpCode()
 		defer bm.ResumeOpCode()
@@ -587,14 +771,20 @@ func (ds *defaultStore) DelObject(oo Object) {
 	// delete from cache.
 	delete(ds.cacheObjects, oid)
 	// delete from backend.
+	var diff int64
 	if ds.baseStore != nil {
 		key := backendObjectKey(oid)
+		diff = -int64(len(ds.baseStore.Get([]byte(key))))
 		ds.baseStore.Delete([]byte(key))
 	}
+	if ds.iavlStore != nil {
+		ds.iavlStore.Delete([]byte(oid.String()))
+	}
 	// make realm op log entry
 	if ds.opslog != nil {
 		fmt.Fprintf(ds.opslog, "d[%v]\n", oo.GetObjectID())
 	}
+	return diff
 }
 
 // NOTE: not used quite yet.
@@ -657,6 +847,12 @@ func (ds *defaultStore) SetCacheType(tt Type) {
 	}
 }
 
+// ReplaceCacheType is like SetCacheType, but replaces the cached type with
+// the same TypeID if any.
+func (ds *defaultStore) ReplaceCacheType(tt Type) {
+	ds.cacheTypes.Set(tt.TypeID(), tt)
+}
+
 func (ds *defaultStore) SetType(tt Type) {
 	if bm.OpsEnabled {
 		bm.PauseOpCode()
@@ -686,7 +882,7 @@ func (ds *defaultStore) SetType(tt Type) {
 		bz := amino.MustMarshalAny(tcopy)
 		gas := overflow.Mulp(ds.gasConfig.GasSetType, store.Gas(len(bz)))
 		ds.consumeGas(gas, GasSetTypeDesc)
-		ds.baseStore.Set([]byte(key), bz)
+		ds.setCommitted([]byte(key), bz)
 		size = len(bz)
 	}
 	// save type to cache.
@@ -781,7 +977,7 @@ func (ds *defaultStore) incGetPackageIndexCounter() uint64 {
 	ctrbz := ds.baseStore.Get(ctrkey)
 	if ctrbz == nil {
 		nextbz := strconv.Itoa(1)
-		ds.baseStore.Set(ctrkey, []byte(nextbz))
+		ds.setCommitted(ctrkey, []byte(nextbz))
 		return 1
 	} else {
 		ctr, err := strconv.Atoi(string(ctrbz))
@@ -789,7 +985,7 @@ func (ds *defaultStore) incGetPackageIndexCounter() uint64 {
 			panic(err)
 		}
 		nextbz := strconv.Itoa(ctr + 1)
-		ds.baseStore.Set(ctrkey, []byte(nextbz))
+		ds.setCommitted(ctrkey, []byte(nextbz))
 		return uint64(ctr) + 1
 	}
 }
@@ -812,13 +1008,16 @@ func (ds *defaultStore) AddMemPackage(mpkg *std.MemPackage, mtype MemPackageType
 	if err != nil {
 		panic(fmt.Errorf("invalid mempackage: %w", err))
 	}
-	ctr := ds.incGetPackageIndexCounter()
-	idxkey := []byte(backendPackageIndexKey(ctr))
 	bz := amino.MustMarshal(mpkg)
 	gas := overflow.Mulp(ds.gasConfig.GasAddMemPackage, store.Gas(len(bz)))
 	ds.consumeGas(gas, GasAddMemPackageDesc)
-	ds.baseStore.Set(idxkey, []byte(mpkg.Path))
 	pathkey := []byte(backendPackagePathKey(mpkg.Path))
+	// an upgraded package keeps its index.
+	if !ds.iavlStore.Has(pathkey) {
+		ctr := ds.incGetPackageIndexCounter()
+		idxkey := []byte(backendPackageIndexKey(ctr))
+		ds.setCommitted(idxkey, []byte(mpkg.Path))
+	}
 	ds.iavlStore.Set(pathkey, bz)
 	size = len(bz)
 }
@@ -946,6 +1145,7 @@ func (ds *defaultStore) ClearObjectCache() {
 	ds.alloc.Reset()
 	ds.cacheObjects = make(map[ObjectID]Object) // new cache.
 	ds.opslog = nil                             // new ops log.
+	ds.realmStorageDiffs = make(map[string]int64)
 	ds.SetCachePackage(Uverse())
 }
 
@@ -1024,6 +1224,17 @@ func backendRealmKey(oid ObjectID) string {
 	return "oid:" + oid.String() + "#realm"
 }
 
+// MemPackageIAVLKey returns the key of the memory package of path in the iavl
+// store, for proofs.
+func MemPackageIAVLKey(path string) []byte {
+	return []byte(backendPackagePathKey(path))
+}
+
+// key: key of a base store entry, whose hash is in the iavl store.
+func backendHashKey(key string) string {
+	return "hash:" + key
+}
+
 func backendTypeKey(tid TypeID) string {
 	return "tid:" + tid.String()
 }
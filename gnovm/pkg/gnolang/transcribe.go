@@ -178,6 +178,17 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		if stopOrSkip(nc, c) {
 			return
 		}
+	case *IndexListExpr:
+		cnn.X = transcribe(t, nns, TRANS_INDEX_X, 0, cnn.X, &c).(Expr)
+		if stopOrSkip(nc, c) {
+			return
+		}
+		for idx := range cnn.Indices {
+			cnn.Indices[idx] = transcribe(t, nns, TRANS_INDEX_INDEX, idx, cnn.Indices[idx], &c).(Expr)
+			if stopOrSkip(nc, c) {
+				return
+			}
+		}
 	case *SelectorExpr:
 		cnn.X = transcribe(t, nns, TRANS_SELECTOR_X, 0, cnn.X, &c).(Expr)
 		if stopOrSkip(nc, c) {
@@ -537,9 +548,11 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		} else {
 			cnn = cnn2.(*SelectCaseStmt)
 		}
-		cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
-		if stopOrSkip(nc, c) {
-			return
+		if cnn.Comm != nil {
+			cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
+			if stopOrSkip(nc, c) {
+				return
+			}
 		}
 		// iterate over Body; its length can change if a statement is decomposed.
 		for idx := 0; idx < len(cnn.Body); idx++ {
@@ -32,10 +32,11 @@ var (
 	}
 	// TODO: star, addressable
 	unaryChecker = map[Word]func(t Type) bool{
-		ADD: isNumeric,
-		SUB: isNumeric,
-		XOR: isIntNum,
-		NOT: isBoolean,
+		ADD:   isNumeric,
+		SUB:   isNumeric,
+		XOR:   isIntNum,
+		NOT:   isBoolean,
+		ARROW: isRecvChan,
 	}
 	IncDecStmtChecker = map[Word]func(t Type) bool{
 		INC: isNumeric,
@@ -108,6 +109,16 @@ func isBoolean(t Type) bool {
 	}
 }
 
+// only bidirectional and receive-only channels can be received from.
+func isRecvChan(t Type) bool {
+	switch t := baseOf(t).(type) {
+	case *ChanType:
+		return t.Dir&RECV != 0
+	default:
+		return false
+	}
+}
+
 // rune can be numeric and string
 func isNumeric(t Type) bool {
 	switch t := baseOf(t).(type) {
@@ -173,13 +184,14 @@ func assertComparable2(dt Type) {
 	case *StructType:
 		for _, f := range cdt.Fields {
 			switch cft := baseOf(f.Type).(type) {
-			case PrimitiveType, *PointerType, *InterfaceType, *ArrayType, *StructType:
+			case PrimitiveType, *PointerType, *InterfaceType, *ArrayType, *StructType, *ChanType:
 				assertComparable2(cft)
 			default:
 				panic(fmt.Sprintf("%v is not comparable", dt))
 			}
 		}
 	case *PointerType: // &a == &b
+	case *ChanType: // by identity
 	case *InterfaceType:
 	case *SliceType, *FuncType, *MapType:
 	default:
@@ -398,6 +410,13 @@ func checkValDefineMismatch(n Node) {
 				panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
 			}
 			return
+		case *UnaryExpr:
+			if values[0].(*UnaryExpr).Op == ARROW {
+				if numNames != 2 {
+					panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
+				}
+				return
+			}
 		}
 	}
 
@@ -631,11 +650,22 @@ func checkAssignableTo(n Node, xt, dt Type, autoNative bool) (err error) {
 			}
 			return nil
 		}
+	case *ChanType:
+		if ct, ok := xt.(*ChanType); ok {
+			if ct.TypeID() == cdt.TypeID() {
+				return nil // ok
+			}
+			// a bidirectional channel may be used
+			// as a send-only or receive-only one.
+			if ct.Dir == BOTH && checkSame(ct.Elt, cdt.Elt, "") == nil {
+				return nil // ok
+			}
+		}
 	case *InterfaceType:
 		panic("should not happen")
 	case *DeclaredType:
 		panic("should not happen")
-	case *FuncType, *StructType, *PackageType, *ChanType, *TypeType:
+	case *FuncType, *StructType, *PackageType, *TypeType:
 		if xt.TypeID() == cdt.TypeID() {
 			return nil // ok
 		}
@@ -870,6 +900,8 @@ func (x *RangeStmt) AssertCompatible(store Store, last BlockNode) {
 		if vt != nil {
 			assertAssignableTo(x, cxt.Elt, vt, false)
 		}
+	case *ChanType:
+		assertAssignableTo(x, cxt.Elt, kt, false)
 	case PrimitiveType:
 		if cxt.Kind() == StringKind {
 			if kt != nil && kt.Kind() != IntKind {
@@ -967,6 +999,29 @@ func (x *AssignStmt) AssertCompatible(store Store, last BlockNode) {
 					}
 				}
 				cx.HasOK = true
+			case *UnaryExpr: // must be a receive when len(Lhs) > len(Rhs)
+				if cx.Op != ARROW {
+					panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
+				}
+				if len(x.Lhs) != 2 {
+					panic("should not happen")
+				}
+				if x.Op == ASSIGN {
+					assertValidAssignLhs(store, last, x.Lhs[0])
+					if !isBlankIdentifier(x.Lhs[0]) {
+						lt := evalStaticTypeOf(store, last, x.Lhs[0])
+						rt := evalStaticTypeOf(store, last, cx)
+						assertAssignableTo(x, rt, lt, false)
+					}
+					assertValidAssignLhs(store, last, x.Lhs[1])
+					if !isBlankIdentifier(x.Lhs[1]) {
+						dt := evalStaticTypeOf(store, last, x.Lhs[1])
+						if dt != nil && dt.Kind() != BoolKind { // typed, not bool
+							panic(fmt.Sprintf("want bool type got %v", dt))
+						}
+					}
+				}
+				cx.HasOK = true
 			default:
 				panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
 			}
@@ -1164,3 +1219,179 @@ func isBlankIdentifier(x Expr) bool {
 	}
 	return false
 }
+
+// ----------------------------------------
+// Type parameter constraints
+
+// Asserts that the type arguments targs of an instance of the generic g
+// satisfy the constraints of its type parameters tparams.
+func assertTypeArgs(store Store, g *genericDecl, tparams FieldTypeExprs, targs []Type) {
+	subst := typeParamSubst(tparams, targs)
+	for i, tp := range tparams {
+		t := targs[i]
+		if !inTypeSet(store, g.fn, tp.Type, t, subst) {
+			panic(fmt.Sprintf("%s does not satisfy %s",
+				t.String(), constraintString(tp.Type)))
+		}
+		if err := checkConstraintMethods(store, g.fn, tp.Type, t, subst); err != nil {
+			panic(fmt.Sprintf("%s does not satisfy %s: %v",
+				t.String(), constraintString(tp.Type), err))
+		}
+	}
+}
+
+func constraintString(cx Expr) string {
+	if nx, ok := cx.(*NameExpr); ok {
+		return string(nx.Name)
+	}
+	return cx.String()
+}
+
+// Returns true if values of type t can be compared with ==.
+func isComparable(t Type) bool {
+	switch ct := baseOf(t).(type) {
+	case *SliceType, *FuncType, *MapType:
+		return false
+	case *ArrayType:
+		return isComparable(ct.Elt)
+	case *StructType:
+		for _, f := range ct.Fields {
+			if !isComparable(f.Type) {
+				return false
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// Returns the interface type expression of the constraint named by x, and
+// the file where it is declared, or nil if x does not name an interface.
+func lookupConstraint(store Store, fn *FileNode, x Expr) (*InterfaceTypeExpr, *FileNode) {
+	var pn *PackageNode
+	var name Name
+	switch x := x.(type) {
+	case *NameExpr:
+		pn, name = packageOf(fn), x.Name
+	case *SelectorExpr:
+		nx, ok := x.X.(*NameExpr)
+		if !ok {
+			return nil, nil
+		}
+		tv := fn.GetSlot(store, nx.Name, true)
+		if tv == nil {
+			return nil, nil
+		}
+		pv, ok := tv.V.(*PackageValue)
+		if !ok {
+			return nil, nil
+		}
+		pn, name = pv.GetPackageNode(store), x.Sel
+	default:
+		return nil, nil
+	}
+	if pn.FileSet == nil {
+		return nil, nil
+	}
+	dfn, d, ok := pn.FileSet.GetDeclForSafe(name)
+	if !ok {
+		return nil, nil
+	}
+	td, ok := (*d).(*TypeDecl)
+	if !ok || len(td.TypeParams) > 0 {
+		return nil, nil
+	}
+	itx, ok := unconst(td.Type).(*InterfaceTypeExpr)
+	if !ok {
+		return nil, nil
+	}
+	return itx, dfn
+}
+
+// Returns true if t is in the type set of the constraint cx, considering only
+// its type terms; constraints without type terms allow any type.
+func inTypeSet(store Store, fn *FileNode, cx Expr, t Type, subst map[Name]Type) bool {
+	switch cx := cx.(type) {
+	case *NameExpr, *SelectorExpr:
+		if nx, ok := cx.(*NameExpr); ok {
+			switch nx.Name {
+			case "any":
+				return true
+			case "comparable":
+				return isComparable(t)
+			}
+		}
+		if itx, cfn := lookupConstraint(store, fn, cx); itx != nil {
+			return inTypeSet(store, cfn, itx, t, nil)
+		}
+	case *InterfaceTypeExpr:
+		// embedded constraints.
+		for _, m := range cx.Methods {
+			if _, ok := m.Type.(*FuncTypeExpr); ok {
+				continue
+			}
+			if !inTypeSet(store, fn, unconst(m.Type), t, subst) {
+				return false
+			}
+		}
+		for _, term := range cx.Terms {
+			if !inTypeSet(store, fn, term, t, subst) {
+				return false
+			}
+		}
+		return true
+	case *BinaryExpr:
+		if cx.Op == BOR {
+			return inTypeSet(store, fn, cx.Left, t, subst) ||
+				inTypeSet(store, fn, cx.Right, t, subst)
+		}
+	case *UnaryExpr:
+		if cx.Op == TILDE {
+			tt := evalTypeExpr(store, fn, cx.X, subst)
+			return baseOf(t).TypeID() == baseOf(tt).TypeID()
+		}
+	}
+	// a single type term.
+	tt := evalTypeExpr(store, fn, cx, subst)
+	if tt.Kind() == InterfaceKind {
+		// its methods are checked by checkConstraintMethods.
+		return true
+	}
+	return t.TypeID() == tt.TypeID()
+}
+
+// Returns an error if t does not implement the methods of the constraint cx.
+func checkConstraintMethods(store Store, fn *FileNode, cx Expr, t Type, subst map[Name]Type) error {
+	switch cx := cx.(type) {
+	case *InterfaceTypeExpr:
+		var mx *InterfaceTypeExpr // only the methods.
+		for _, m := range cx.Methods {
+			if _, ok := m.Type.(*FuncTypeExpr); ok {
+				if mx == nil {
+					mx = &InterfaceTypeExpr{}
+					mx.SetSpan(cx.GetSpan())
+				}
+				mx.Methods = append(mx.Methods, m)
+			} else if err := checkConstraintMethods(store, fn, m.Type, t, subst); err != nil {
+				return err
+			}
+		}
+		if mx == nil {
+			return nil
+		}
+		it := baseOf(evalTypeExpr(store, fn, mx, subst)).(*InterfaceType)
+		return it.VerifyImplementedBy(t)
+	case *NameExpr:
+		if cx.Name == "any" || cx.Name == "comparable" {
+			return nil
+		}
+	case *BinaryExpr, *UnaryExpr:
+		// type terms.
+		return nil
+	}
+	if it, ok := baseOf(evalTypeExpr(store, fn, cx, subst)).(*InterfaceType); ok {
+		return it.VerifyImplementedBy(t)
+	}
+	return nil
+}
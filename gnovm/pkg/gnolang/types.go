@@ -1083,9 +1083,9 @@ func (ct *ChanType) TypeID() TypeID {
 		case SEND | RECV:
 			ct.typeid = typeidf("chan{%s}", ct.Elt.TypeID().String())
 		case SEND:
-			ct.typeid = typeidf("<-chan{%s}", ct.Elt.TypeID().String())
-		case RECV:
 			ct.typeid = typeidf("chan<-{%s}", ct.Elt.TypeID().String())
+		case RECV:
+			ct.typeid = typeidf("<-chan{%s}", ct.Elt.TypeID().String())
 		default:
 			panic("should not happen")
 		}
@@ -1098,9 +1098,9 @@ func (ct *ChanType) String() string {
 	case SEND | RECV:
 		return "chan " + ct.Elt.String()
 	case SEND:
-		return "<-chan " + ct.Elt.String()
-	case RECV:
 		return "chan<- " + ct.Elt.String()
+	case RECV:
+		return "<-chan " + ct.Elt.String()
 	default:
 		panic("should not happen")
 	}
@@ -556,6 +556,16 @@ func makeUverseNode() {
 			return
 		},
 	)
+	defNative("close",
+		Flds( // params
+			"c", AnyT(),
+		),
+		nil, // results
+		func(m *Machine) {
+			arg0 := m.LastBlock().GetParams1(m.Store)
+			m.closeChan(*arg0.TV)
+		},
+	)
 	defNative("copy",
 		Flds( // params
 			"dst", GenT("X", nil),
@@ -803,9 +813,22 @@ func makeUverseNode() {
 				}
 			case *ChanType:
 				if vargsl == 0 {
-					panic("not yet implemented")
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.newChan(0),
+					})
+					return
 				} else if vargsl == 1 {
-					panic("not yet implemented")
+					sv := vargs.TV.GetPointerAtIndexInt(m.Store, 0).Deref()
+					si := int(sv.ConvertGetInt())
+					if si < 0 {
+						m.Panic(typedString(`makechan: size out of range`))
+					}
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.newChan(si),
+					})
+					return
 				} else {
 					panic("make() of chan type takes 1 or 2 arguments")
 				}
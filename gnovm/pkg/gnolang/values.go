@@ -56,6 +56,7 @@ func (*PackageValue) assertValue()     {}
 func (*Block) assertValue()            {}
 func (RefValue) assertValue()          {}
 func (*HeapItemValue) assertValue()    {}
+func (*ChanValue) assertValue()        {}
 
 const (
 	nilStr       = "nil"
@@ -79,6 +80,7 @@ var (
 	_ Value = &Block{}
 	_ Value = RefValue{}
 	_ Value = &HeapItemValue{}
+	_ Value = &ChanValue{}
 )
 
 // ----------------------------------------
@@ -759,6 +761,32 @@ func (mv *MapValue) DeleteForKey(store Store, key *TypedValue) {
 	}
 }
 
+// ----------------------------------------
+// ChanValue
+
+// ChanValue is the runtime value of a channel.
+// Channels only live for the duration of a machine run;
+// they are never persisted to a realm.
+// See goroutine.go for the channel operations.
+type ChanValue struct {
+	ID     int64        // unique and deterministic within a machine
+	Cap    int          // buffer capacity, 0 if unbuffered
+	Buffer []TypedValue // buffered values, oldest first
+	Closed bool
+
+	sendq       []*chanWaiter // goroutines blocked on send
+	recvq       []*chanWaiter // goroutines blocked on receive
+	lastGCCycle int64
+}
+
+func (cv *ChanValue) GetLength() int {
+	return len(cv.Buffer)
+}
+
+func (cv *ChanValue) GetCapacity() int {
+	return cv.Cap
+}
+
 // ----------------------------------------
 // TypeValue
 
@@ -1585,7 +1613,12 @@ func (tv *TypedValue) ComputeMapKey(store Store, omitType bool) MapKey {
 		pv := tv.V.(*PackageValue)
 		bz = append(bz, []byte(strconv.Quote(pv.PkgPath))...)
 	case *ChanType:
-		panic("not yet implemented")
+		// channels are compared by identity.
+		var id int64
+		if cv, ok := tv.V.(*ChanValue); ok {
+			id = cv.ID
+		}
+		bz = append(bz, []byte(strconv.FormatInt(id, 10))...)
 	default:
 		panic(fmt.Sprintf(
 			"unexpected map key type %s",
@@ -1997,6 +2030,8 @@ func (tv *TypedValue) GetLength() int {
 			return 0
 		case *MapType:
 			return 0
+		case *ChanType:
+			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
 				return at.Len
@@ -2017,6 +2052,8 @@ func (tv *TypedValue) GetLength() int {
 		return cv.GetLength()
 	case *MapValue:
 		return cv.GetLength()
+	case *ChanValue:
+		return cv.GetLength()
 	case PointerValue:
 		if av, ok := cv.TV.V.(*ArrayValue); ok {
 			return av.GetLength()
@@ -2037,6 +2074,8 @@ func (tv *TypedValue) GetCapacity() int {
 			return bt.Len
 		case *SliceType:
 			return 0
+		case *ChanType:
+			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
 				return at.Len
@@ -2051,6 +2090,8 @@ func (tv *TypedValue) GetCapacity() int {
 		return cv.GetCapacity()
 	case *SliceValue:
 		return cv.GetCapacity()
+	case *ChanValue:
+		return cv.GetCapacity()
 	case PointerValue:
 		if av, ok := cv.TV.V.(*ArrayValue); ok {
 			return av.GetCapacity()
@@ -2543,6 +2584,11 @@ func defaultArrayValue(alloc *Allocator, at *ArrayType) *ArrayValue {
 	return av
 }
 
+// DefaultTypedValue returns the zero value of type t, allocated with alloc.
+func DefaultTypedValue(alloc *Allocator, t Type) TypedValue {
+	return defaultTypedValue(alloc, t)
+}
+
 func defaultTypedValue(alloc *Allocator, t Type) TypedValue {
 	switch ct := baseOf(t).(type) {
 	case nil:
@@ -2608,6 +2654,12 @@ func typedString(s string) TypedValue {
 	return tv
 }
 
+// FillValueTV loads from store the object or package tv refers to, if it is
+// not loaded yet, and returns tv.
+func FillValueTV(store Store, tv *TypedValue) *TypedValue {
+	return fillValueTV(store, tv)
+}
+
 // returns the same tv instance for convenience.
 func fillValueTV(store Store, tv *TypedValue) *TypedValue {
 	switch cv := tv.V.(type) {
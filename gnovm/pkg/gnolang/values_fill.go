@@ -63,6 +63,7 @@ func (sv *StructValue) DeepFill(store Store) Value {
 // XXX implement these too
 func (fv *FuncValue) DeepFill(store Store) Value         { panic("not yet implemented") }
 func (mv *MapValue) DeepFill(store Store) Value          { panic("not yet implemented") }
+func (cv *ChanValue) DeepFill(store Store) Value         { panic("not yet implemented") }
 func (bmv *BoundMethodValue) DeepFill(store Store) Value { panic("not yet implemented") }
 func (tv TypeValue) DeepFill(store Store) Value          { panic("not yet implemented") }
 func (pv *PackageValue) DeepFill(store Store) Value      { panic("not yet implemented") }
@@ -210,6 +210,10 @@ func (bmv *BoundMethodValue) String() string {
 		recvT, name, params, results)
 }
 
+func (cv *ChanValue) String() string {
+	return fmt.Sprintf("chan@%d", cv.ID)
+}
+
 func (mv *MapValue) String() string {
 	return mv.ProtectedString(newSeenValues())
 }
@@ -410,7 +414,10 @@ func (tv *TypedValue) ProtectedSprint(seen *seenValues, considerDeclaredType boo
 	case *PackageType:
 		return tv.V.(*PackageValue).String()
 	case *ChanType:
-		panic("not yet implemented")
+		if tv.V == nil {
+			return nilStr + " " + tv.T.String()
+		}
+		return tv.V.(*ChanValue).String()
 	case *TypeType:
 		return tv.V.(TypeValue).String()
 	default:
@@ -0,0 +1,209 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/types"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/test"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"go.uber.org/multierr"
+)
+
+// snapshot is the result of checking a package.
+type snapshot struct {
+	dir  string
+	mpkg *std.MemPackage
+	pkg  *types.Package     // nil if the package could not be parsed.
+	info *gno.TypeCheckInfo // the type information of pkg.
+}
+
+// check type-checks and preprocesses the package in dir, and returns the
+// diagnostics of its files by file path.
+func (s *Server) check(dir string) (*snapshot, map[string][]diagnostic) {
+	pkgPath := s.loader.packagePath(dir)
+	s.loader.modDirs[pkgPath] = dir
+	s.loader.dirs[pkgPath] = dir
+	snap := &snapshot{dir: dir}
+	diags := map[string][]diagnostic{}
+	mpkg, err := s.loader.readPackage(dir, pkgPath)
+	if err != nil {
+		return snap, diags
+	}
+	snap.mpkg = mpkg
+	add := func(err error) {
+		for _, err := range multierr.Errors(err) {
+			s.addDiagnostics(diags, snap, err)
+		}
+	}
+
+	// STEP 1: type-check, with the open documents of the imports.
+	delete(s.cache, pkgPath)
+	info := gno.NewTypeCheckInfo()
+	var pkg *types.Package
+	didPanic := catchPanic(add, func() {
+		pkg, err = gno.TypeCheckMemPackageWithOptions(mpkg, s.loader, gno.TypeCheckOptions{
+			ParseMode: gno.ParseModeAll,
+			Mode:      gno.TCLatestRelaxed,
+			Cache:     s.cache,
+			Info:      info,
+		})
+	})
+	if pkg != nil {
+		snap.pkg, snap.info = pkg, info
+	}
+	if didPanic || err != nil {
+		add(err)
+		return snap, diags
+	}
+
+	// STEP 2: preprocess, as done by gno lint.
+	catchPanic(add, func() {
+		if err := test.LoadImports(s.testStore, mpkg, true); err != nil {
+			add(err)
+			return
+		}
+		fset, _tests, err := parseFileSets(mpkg)
+		if err != nil {
+			add(err)
+			return
+		}
+		cw := s.baseStore.CacheWrap()
+		gs := s.testStore.BeginTransaction(cw, cw, nil)
+		tm := test.Machine(gs, io.Discard, pkgPath, false)
+		defer tm.Release()
+		tm.PreprocessFiles(mpkg.Name, mpkg.Path, fset, false, false, "")
+		if len(_tests.Files) > 0 {
+			cw = s.baseStore.CacheWrap()
+			tm.Store = s.testStore.BeginTransaction(cw, cw, nil)
+			tm.PreprocessFiles(mpkg.Name+"_test", mpkg.Path+"_test", _tests, false, false, "")
+		}
+	})
+	return snap, diags
+}
+
+// parseFileSets parses the normal and test files of mpkg, and the files of
+// its xxx_test package, like gno test does. Filetests are not preprocessed.
+func parseFileSets(mpkg *std.MemPackage) (fset, _tests *gno.FileSet, err error) {
+	fset, _tests = &gno.FileSet{}, &gno.FileSet{}
+	for _, mfile := range mpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") ||
+			strings.HasSuffix(mfile.Name, "_filetest.gno") {
+			continue
+		}
+		n, err := gno.ParseFile(mfile.Name, mfile.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.HasSuffix(mfile.Name, "_test.gno") &&
+			strings.HasSuffix(string(n.PkgName), "_test") {
+			_tests.AddFiles(n)
+		} else {
+			fset.AddFiles(n)
+		}
+	}
+	return fset, _tests, nil
+}
+
+// catchPanic runs action, reporting its panic errors to report.
+func catchPanic(report func(error), action func()) (didPanic bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			didPanic = true
+			if err, ok := r.(error); ok {
+				report(err)
+			} else {
+				report(fmt.Errorf("%v", r))
+			}
+		}
+	}()
+	action()
+	return
+}
+
+// addDiagnostics adds the diagnostics of err to the files of the package of
+// snap. Errors in other packages, such as imports, are reported at the
+// beginning of the package's first file.
+func (s *Server) addDiagnostics(diags map[string][]diagnostic, snap *snapshot, err error) {
+	var (
+		fname     string // "<pkgpath>/<file>", or a file path.
+		line, col int
+		msg       string
+	)
+	var perr *gno.PreprocessError
+	switch err := err.(type) {
+	case types.Error:
+		pos := err.Fset.Position(err.Pos)
+		fname, line, col, msg = pos.Filename, pos.Line, pos.Column, err.Msg
+	case scanner.ErrorList:
+		for _, err := range err {
+			s.addDiagnostics(diags, snap, err)
+		}
+		return
+	case *scanner.Error:
+		fname, line, col, msg = err.Pos.Filename, err.Pos.Line, err.Pos.Column, err.Msg
+	default:
+		if errors.As(err, &perr) {
+			err = perr.Unwrap()
+		}
+		msg = strings.TrimSpace(err.Error())
+		if match := gno.Re_errorLine.Match(msg); match != nil {
+			fname = match.Get("PATH")
+			line, _ = strconv.Atoi(match.Get("LINE"))
+			col, _ = strconv.Atoi(match.Get("COL"))
+			msg = strings.TrimSpace(match.Get("MSG"))
+		}
+	}
+
+	fpath := fname
+	if !filepath.IsAbs(fpath) {
+		fpath = s.loader.filePath(fname)
+	}
+	if fpath == "" || filepath.Dir(fpath) != snap.dir {
+		// not in the package: report at its first file.
+		if fname != "" {
+			msg = fmt.Sprintf("%s:%d:%d: %s", fname, line, col, msg)
+		}
+		fpath, line, col = snap.firstFile(), 1, 1
+		if fpath == "" {
+			return
+		}
+	}
+	text, _ := s.loader.text(fpath)
+	pos := positionAt(text, line, col)
+	// underline the word at the position, if any.
+	off := offsetOf(text, pos)
+	for off < len(text) && isWordByte(text[off]) {
+		off++
+	}
+	if off == offsetOf(text, pos) && off < len(text) && text[off] != '\n' {
+		off++ // at least a character.
+	}
+	end := positionOf(text, off)
+	diags[fpath] = append(diags[fpath], diagnostic{
+		Range:    rangeT{Start: pos, End: end},
+		Severity: severityError,
+		Source:   "gno",
+		Message:  msg,
+	})
+}
+
+// firstFile returns the path of the first .gno file of the package.
+func (snap *snapshot) firstFile() string {
+	for _, mfile := range snap.mpkg.Files {
+		if strings.HasSuffix(mfile.Name, ".gno") {
+			return filepath.Join(snap.dir, mfile.Name)
+		}
+	}
+	return ""
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
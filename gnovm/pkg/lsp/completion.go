@@ -0,0 +1,230 @@
+package lsp
+
+import (
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+var keywords = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else",
+	"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+	"map", "package", "range", "return", "select", "struct", "switch", "type",
+	"var",
+}
+
+// completion completes the identifier at a position: the members of a
+// package, the fields and methods of a value after a dot, or else the names in
+// scope and the keywords.
+func (s *Server) completion(params textDocumentPositionParams) (*completionList, error) {
+	fpath, text, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	off := offsetOf(text, params.Position)
+	start := off
+	for start > 0 && isWordByte(text[start-1]) {
+		start--
+	}
+	prefix := text[start:off]
+
+	var items []completionItem
+	if start > 0 && text[start-1] == '.' {
+		items = s.completeSelector(fpath, text, start)
+	} else {
+		items = s.completeScope(params, prefix)
+	}
+
+	list := &completionList{Items: []completionItem{}}
+	seen := map[string]bool{}
+	for _, item := range items {
+		if strings.HasPrefix(item.Label, prefix) && !seen[item.Label] {
+			seen[item.Label] = true
+			list.Items = append(list.Items, item)
+		}
+	}
+	sort.SliceStable(list.Items, func(i, j int) bool { return list.Items[i].Label < list.Items[j].Label })
+	return list, nil
+}
+
+// completeSelector returns the members of the operand of the selector whose
+// name starts at text[start]. As the selector may not have a name yet, it
+// type-checks the package again with a placeholder name.
+func (s *Server) completeSelector(fpath, text string, start int) []completionItem {
+	dir := filepath.Dir(fpath)
+	pkgPath := s.loader.packagePath(dir)
+	if start == len(text) || !isWordByte(text[start]) {
+		s.loader.docs[fpath] = text[:start] + "_" + text[start:]
+	}
+	mpkg, err := s.loader.readPackage(dir, pkgPath)
+	s.loader.docs[fpath] = text
+	if err != nil {
+		return nil
+	}
+	info := gno.NewTypeCheckInfo()
+	var pkg *types.Package
+	catchPanic(func(error) {}, func() {
+		pkg, _ = gno.TypeCheckMemPackageWithOptions(mpkg, s.loader, gno.TypeCheckOptions{
+			ParseMode: gno.ParseModeAll,
+			Mode:      gno.TCLatestRelaxed,
+			Cache:     s.cache,
+			Info:      info,
+		})
+	})
+	if pkg == nil {
+		return nil
+	}
+	snap := &snapshot{dir: dir, mpkg: mpkg, pkg: pkg, info: info}
+	f, tf := snap.file(filepath.Base(fpath))
+	if f == nil {
+		return nil
+	}
+	path, _ := astutil.PathEnclosingInterval(f, tf.Pos(start), tf.Pos(start))
+	if len(path) < 2 {
+		return nil
+	}
+	sel, ok := path[1].(*ast.SelectorExpr)
+	if !ok || sel.Sel != path[0] {
+		return nil
+	}
+
+	var items []completionItem
+	// members of an imported package.
+	if id, ok := sel.X.(*ast.Ident); ok {
+		if pn, ok := info.Uses[id].(*types.PkgName); ok {
+			scope := pn.Imported().Scope()
+			for _, name := range scope.Names() {
+				if obj := scope.Lookup(name); obj.Exported() {
+					items = append(items, completionItemOf(obj, pkg))
+				}
+			}
+			return items
+		}
+	}
+
+	// fields and methods of a value or a type.
+	typ := info.TypeOf(sel.X)
+	if typ == nil {
+		return nil
+	}
+	accessible := func(obj types.Object) bool {
+		return obj.Exported() || obj.Pkg() != nil && strings.TrimSuffix(obj.Pkg().Path(), "_test") == mpkg.Path
+	}
+	for _, field := range fieldsOf(typ, map[types.Type]bool{}) {
+		if accessible(field) {
+			items = append(items, completionItemOf(field, pkg))
+		}
+	}
+	mtyp := typ
+	if _, ok := typ.Underlying().(*types.Interface); !ok {
+		if _, ok := typ.(*types.Pointer); !ok {
+			mtyp = types.NewPointer(typ)
+		}
+	}
+	mset := types.NewMethodSet(mtyp)
+	for i := 0; i < mset.Len(); i++ {
+		if obj := mset.At(i).Obj(); accessible(obj) {
+			items = append(items, completionItemOf(obj, pkg))
+		}
+	}
+	return items
+}
+
+// fieldsOf returns the fields of the struct typ, or of the struct typ points
+// to, including those of its embedded fields.
+func fieldsOf(typ types.Type, seen map[types.Type]bool) []*types.Var {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok || seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+	var fields []*types.Var
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		fields = append(fields, field)
+		if field.Embedded() {
+			fields = append(fields, fieldsOf(field.Type(), seen)...)
+		}
+	}
+	return fields
+}
+
+// completeScope returns the names in scope at a position, and the keywords.
+func (s *Server) completeScope(params textDocumentPositionParams, prefix string) []completionItem {
+	var items []completionItem
+	for _, kw := range keywords {
+		items = append(items, completionItem{Label: kw, Kind: kindKeyword})
+	}
+	snap, f, pos, err := s.fileAt(params)
+	if err != nil || f == nil {
+		return items
+	}
+	scope := snap.info.Scopes[f]
+	if scope == nil {
+		return items
+	}
+	if inner := scope.Innermost(pos); inner != nil {
+		scope = inner
+	}
+	// from the innermost scope, as its names shadow those of the outer ones.
+	var names []completionItem
+	for ; scope != nil; scope = scope.Parent() {
+		isLocal := scope.Parent() != nil && scope.Parent() != types.Universe &&
+			scope != snap.info.Scopes[f]
+		for _, name := range scope.Names() {
+			obj := scope.Lookup(name)
+			if strings.HasPrefix(name, "_") || !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if isLocal && obj.Pos() > pos {
+				continue // declared after pos.
+			}
+			names = append(names, completionItemOf(obj, snap.pkg))
+		}
+	}
+	return append(names, items...)
+}
+
+func completionItemOf(obj types.Object, pkg *types.Package) completionItem {
+	item := completionItem{Label: obj.Name()}
+	switch obj := obj.(type) {
+	case *types.Func:
+		item.Kind = kindFunction
+		if obj.Type().(*types.Signature).Recv() != nil {
+			item.Kind = kindMethod
+		}
+	case *types.Var:
+		item.Kind = kindVariable
+		if obj.IsField() {
+			item.Kind = kindField
+		}
+	case *types.Const, *types.Nil:
+		item.Kind = kindConstant
+	case *types.PkgName:
+		item.Kind = kindModule
+		item.Detail = obj.Imported().Path()
+		return item
+	case *types.TypeName:
+		switch obj.Type().Underlying().(type) {
+		case *types.Struct:
+			item.Kind = kindStruct
+		case *types.Interface:
+			item.Kind = kindInterface
+		default:
+			item.Kind = kindType
+		}
+	case *types.Builtin:
+		item.Kind = kindFunction
+		return item
+	}
+	item.Detail = types.TypeString(obj.Type(), qualifier(pkg))
+	return item
+}
@@ -0,0 +1,75 @@
+package lsp
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnofmt"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// format formats a document and fixes its imports, like gno fmt.
+func (s *Server) format(params documentFormattingParams) ([]textEdit, error) {
+	fpath, text, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(fpath)
+	mpkg, err := s.loader.readPackage(dir, s.loader.packagePath(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	// A new processor, as it caches the files of the packages it formats;
+	// the resolver caches the packages to import.
+	if s.resolver == nil {
+		s.resolver = gnofmt.NewFSResolver()
+		for _, root := range []string{
+			filepath.Join(s.rootDir, "gnovm", "stdlibs"),
+			filepath.Join(s.rootDir, "examples"),
+		} {
+			// ignore the packages which fail to load.
+			_ = s.resolver.LoadPackages(root, func(string, error) error { return nil })
+		}
+	}
+	p := gnofmt.NewProcessor(s.resolver)
+	out, err := p.FormatPackageFile(memPackage{mpkg}, filepath.Base(fpath))
+	if err != nil {
+		return nil, err
+	}
+	if string(out) == text {
+		return []textEdit{}, nil
+	}
+	return []textEdit{{
+		Range:   rangeT{End: positionOf(text, len(text))},
+		NewText: string(out),
+	}}, nil
+}
+
+// memPackage is a [gnofmt.Package] reading the files of a MemPackage.
+type memPackage struct {
+	*std.MemPackage
+}
+
+func (p memPackage) Path() string { return p.MemPackage.Path }
+func (p memPackage) Name() string { return p.MemPackage.Name }
+
+func (p memPackage) Files() []string {
+	var names []string
+	for _, mfile := range p.MemPackage.Files {
+		if strings.HasSuffix(mfile.Name, ".gno") {
+			names = append(names, mfile.Name)
+		}
+	}
+	return names
+}
+
+func (p memPackage) Read(filename string) (io.ReadCloser, error) {
+	mfile := p.GetFile(filename)
+	if mfile == nil {
+		return nil, fmt.Errorf("file not found: %s", filename)
+	}
+	return io.NopCloser(strings.NewReader(mfile.Body)), nil
+}
@@ -0,0 +1,240 @@
+package lsp
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/doc"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// fileAt returns the Go AST of the document of params in the last check of
+// its package, and the position of params in it. It returns a nil file if the
+// package could not be parsed.
+func (s *Server) fileAt(params textDocumentPositionParams) (*snapshot, *ast.File, token.Pos, error) {
+	fpath, text, err := s.document(params.TextDocument.URI)
+	if err != nil {
+		return nil, nil, token.NoPos, err
+	}
+	snap := s.snapshots[filepath.Dir(fpath)]
+	if snap == nil || snap.info == nil {
+		return snap, nil, token.NoPos, nil
+	}
+	f, tf := snap.file(filepath.Base(fpath))
+	if f == nil {
+		return snap, nil, token.NoPos, nil
+	}
+	off := min(offsetOf(text, params.Position), tf.Size())
+	return snap, f, tf.Pos(off), nil
+}
+
+// file returns the Go AST of the file name of the package, and its
+// [token.File].
+func (snap *snapshot) file(name string) (*ast.File, *token.File) {
+	fset := snap.info.Fsets[snap.pkg]
+	fname := snap.mpkg.Path + "/" + name
+	for _, f := range snap.info.Files {
+		if tf := fset.File(f.FileStart); tf != nil && tf.Name() == fname {
+			return f, tf
+		}
+	}
+	return nil, nil
+}
+
+// objectAt returns the identifier at pos in f, and the object it denotes.
+func objectAt(info *types.Info, f *ast.File, pos token.Pos) (*ast.Ident, types.Object) {
+	path, _ := astutil.PathEnclosingInterval(f, pos, pos)
+	if len(path) == 0 {
+		return nil, nil
+	}
+	id, ok := path[0].(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+	if obj := info.Uses[id]; obj != nil {
+		return id, obj
+	}
+	return id, info.Defs[id]
+}
+
+// qualifier names the packages other than pkg by their name.
+func qualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// hover shows the declaration and the documentation of the identifier at a
+// position.
+func (s *Server) hover(params textDocumentPositionParams) (*hover, error) {
+	snap, f, pos, err := s.fileAt(params)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	id, obj := objectAt(&snap.info.Info, f, pos)
+	if obj == nil {
+		return nil, nil
+	}
+
+	var b strings.Builder
+	b.WriteString("```gno\n")
+	b.WriteString(types.ObjectString(obj, qualifier(obj.Pkg())))
+	if c, ok := obj.(*types.Const); ok {
+		b.WriteString(" = " + c.Val().ExactString())
+	}
+	b.WriteString("\n```")
+	if d := s.docOf(snap, obj); d != "" {
+		b.WriteString("\n\n")
+		b.WriteString(d)
+	}
+
+	_, text, _ := s.document(params.TextDocument.URI)
+	fset := snap.info.Fsets[snap.pkg]
+	start, end := fset.Position(id.Pos()), fset.Position(id.End())
+	return &hover{
+		Contents: markupContent{Kind: "markdown", Value: b.String()},
+		Range: &rangeT{
+			Start: positionAt(text, start.Line, start.Column),
+			End:   positionAt(text, end.Line, end.Column),
+		},
+	}, nil
+}
+
+// docOf returns the documentation of obj, in markdown, if it is declared at
+// the top level of a package, or if it is a method.
+func (s *Server) docOf(snap *snapshot, obj types.Object) string {
+	if pn, ok := obj.(*types.PkgName); ok {
+		if jdoc := s.packageDoc(snap, pn.Imported().Path()); jdoc != nil {
+			return jdoc.PackageDoc
+		}
+		return ""
+	}
+	if obj.Pkg() == nil {
+		return "" // universe.
+	}
+	var recv string
+	if fn, ok := obj.(*types.Func); ok {
+		if r := fn.Type().(*types.Signature).Recv(); r != nil {
+			t := r.Type()
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			named, ok := t.(*types.Named)
+			if !ok {
+				return "" // interface method.
+			}
+			recv = named.Obj().Name()
+		}
+	}
+	if recv == "" && obj.Parent() != obj.Pkg().Scope() {
+		return "" // local.
+	}
+
+	jdoc := s.packageDoc(snap, strings.TrimSuffix(obj.Pkg().Path(), "_test"))
+	if jdoc == nil {
+		return ""
+	}
+	switch obj.(type) {
+	case *types.Func:
+		for _, fn := range jdoc.Funcs {
+			if fn.Type == recv && fn.Name == obj.Name() {
+				return fn.Doc
+			}
+		}
+	case *types.TypeName:
+		for _, typ := range jdoc.Types {
+			if typ.Name == obj.Name() {
+				return typ.Doc
+			}
+		}
+	case *types.Const, *types.Var:
+		for _, decl := range jdoc.Values {
+			for _, v := range decl.Values {
+				if v.Name == obj.Name() {
+					if v.Doc != "" {
+						return v.Doc
+					}
+					return decl.Doc
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// packageDoc returns the documentation of the package pkgPath, or nil if it
+// cannot be loaded.
+func (s *Server) packageDoc(snap *snapshot, pkgPath string) *doc.JSONDocumentation {
+	mpkg := snap.mpkg
+	if pkgPath != mpkg.Path {
+		mpkg = s.loader.GetMemPackage(pkgPath)
+		if mpkg == nil {
+			return nil
+		}
+	}
+	d, err := doc.NewDocumentableFromMemPkg(mpkg, true, "", "")
+	if err != nil {
+		return nil
+	}
+	jdoc, err := d.WriteJSONDocumentation()
+	if err != nil {
+		return nil
+	}
+	return jdoc
+}
+
+// definition returns the location of the declaration of the identifier at a
+// position, which may be in another package.
+func (s *Server) definition(params textDocumentPositionParams) (*location, error) {
+	snap, f, pos, err := s.fileAt(params)
+	if err != nil || f == nil {
+		return nil, err
+	}
+	_, obj := objectAt(&snap.info.Info, f, pos)
+	if obj == nil {
+		return nil, nil
+	}
+
+	if pn, ok := obj.(*types.PkgName); ok {
+		// the first file of the imported package.
+		path := pn.Imported().Path()
+		mpkg := s.loader.GetMemPackage(path)
+		if mpkg == nil {
+			return nil, nil
+		}
+		for _, mfile := range mpkg.Files {
+			if strings.HasSuffix(mfile.Name, ".gno") {
+				fpath := s.loader.filePath(path + "/" + mfile.Name)
+				return &location{URI: pathToURI(fpath)}, nil
+			}
+		}
+		return nil, nil
+	}
+
+	fset := snap.info.Fsets[obj.Pkg()]
+	if fset == nil || !obj.Pos().IsValid() {
+		return nil, nil // universe.
+	}
+	p := fset.Position(obj.Pos())
+	fpath := s.loader.filePath(p.Filename)
+	if fpath == "" {
+		return nil, nil
+	}
+	text, err := s.loader.text(fpath)
+	if err != nil {
+		return nil, nil // .gnobuiltins.gno.
+	}
+	return &location{
+		URI: pathToURI(fpath),
+		Range: rangeT{
+			Start: positionAt(text, p.Line, p.Column),
+			End:   positionAt(text, p.Line, p.Column+len(obj.Name())),
+		},
+	}, nil
+}
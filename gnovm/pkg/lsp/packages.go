@@ -0,0 +1,198 @@
+package lsp
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/gnomod"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"golang.org/x/mod/module"
+)
+
+// loader finds the packages imported by the checked packages, in the gno root
+// directory (stdlibs and examples), in the directories of the open documents,
+// and in the module cache of packages downloaded from the chain. The files of
+// the open documents replace those on disk.
+//
+// It implements [gno.MemPackageGetter].
+type loader struct {
+	rootDir string
+	docs    map[string]string // open documents: file path -> text.
+	dirs    map[string]string // package path -> directory, of loaded packages.
+	modDirs map[string]string // package path -> directory, of open documents.
+}
+
+func newLoader(rootDir string) *loader {
+	return &loader{
+		rootDir: rootDir,
+		docs:    make(map[string]string),
+		dirs:    make(map[string]string),
+		modDirs: make(map[string]string),
+	}
+}
+
+// GetMemPackage returns the package at pkgPath, or nil if not found.
+func (l *loader) GetMemPackage(pkgPath string) *std.MemPackage {
+	if gno.IsStdlib(pkgPath) {
+		return l.stdlib(pkgPath)
+	}
+	dirs := []string{
+		l.modDirs[pkgPath],
+		filepath.Join(l.rootDir, "examples", filepath.FromSlash(pkgPath)),
+		gnomod.PackageDir("", module.Version{Path: pkgPath}),
+	}
+	for _, dir := range dirs {
+		if dir == "" || !isDir(dir) {
+			continue
+		}
+		mpkg, err := l.readPackage(dir, pkgPath)
+		if err != nil || mpkg.IsEmpty() {
+			continue
+		}
+		l.dirs[pkgPath] = dir
+		return mpkg
+	}
+	return nil
+}
+
+// stdlib reads the standard library pkgPath, along with the test overrides
+// of gnovm/tests/stdlibs, as done by the test store.
+func (l *loader) stdlib(pkgPath string) *std.MemPackage {
+	dirs := [...]string{
+		filepath.Join(l.rootDir, "gnovm", "stdlibs", filepath.FromSlash(pkgPath)),
+		filepath.Join(l.rootDir, "gnovm", "tests", "stdlibs", filepath.FromSlash(pkgPath)),
+	}
+	var files []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), ".gno") {
+				files = append(files, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	mpkg, err := gno.ReadMemPackageFromList(files, pkgPath, gno.MemPackageTypeStdlib)
+	if err != nil {
+		return nil
+	}
+	for _, fpath := range files {
+		if text, ok := l.docs[fpath]; ok {
+			if mfile := mpkg.GetFile(filepath.Base(fpath)); mfile != nil {
+				mfile.Body = text
+			}
+		}
+	}
+	l.dirs[pkgPath] = dirs[0]
+	return mpkg
+}
+
+// readPackage reads the package in dir, with the text of its open documents.
+// Unlike [gno.ReadMemPackage], it keeps files with syntax errors, which are
+// reported by the type checker.
+func (l *loader) readPackage(dir, pkgPath string) (*std.MemPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	mpkg := &std.MemPackage{Path: pkgPath}
+	seen := map[string]bool{}
+	add := func(name, body string) {
+		seen[name] = true
+		mpkg.Files = append(mpkg.Files, &std.MemFile{Name: name, Body: body})
+		if mpkg.Name == "" && strings.HasSuffix(name, ".gno") && !strings.HasSuffix(name, "_filetest.gno") {
+			pkgName, err := gno.PackageNameFromFileBody(path.Join(pkgPath, name), body)
+			if err == nil {
+				mpkg.Name = strings.TrimSuffix(string(pkgName), "_test")
+			}
+		}
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") ||
+			!strings.HasSuffix(name, ".gno") && name != "gno.mod" {
+			continue
+		}
+		fpath := filepath.Join(dir, name)
+		if text, ok := l.docs[fpath]; ok {
+			add(name, text)
+			continue
+		}
+		bz, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, err
+		}
+		add(name, string(bz))
+	}
+	// new files, not yet saved.
+	for fpath, text := range l.docs {
+		if name := filepath.Base(fpath); filepath.Dir(fpath) == dir && !seen[name] {
+			add(name, text)
+		}
+	}
+	mpkg.Sort()
+	return mpkg, nil
+}
+
+// packagePath returns the package path of the package in dir: the module of
+// its gno.mod, or its path in the gno root directory.
+func (l *loader) packagePath(dir string) string {
+	mod, err := gnomod.ParseFilepath(filepath.Join(dir, "gno.mod"))
+	if err == nil && mod.Module != nil {
+		return mod.Module.Mod.Path
+	}
+	for _, root := range []string{
+		filepath.Join(l.rootDir, "examples"),
+		filepath.Join(l.rootDir, "gnovm", "stdlibs"),
+		filepath.Join(l.rootDir, "gnovm", "tests", "stdlibs"),
+		gnomod.ModCachePath(),
+	} {
+		if rel, err := filepath.Rel(root, dir); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	// a deterministic path, as used by gno test.
+	return "gno.land/r/test"
+}
+
+// filePath returns the path on disk of a file name of a go/token position,
+// which is "<pkgpath>/<file>", or "" if its package was not loaded.
+func (l *loader) filePath(name string) string {
+	pkgPath, file := path.Split(name)
+	pkgPath = strings.TrimSuffix(pkgPath, "/")
+	dir, ok := l.dirs[pkgPath]
+	if !ok {
+		return ""
+	}
+	fpath := filepath.Join(dir, file)
+	if _, ok := l.docs[fpath]; ok {
+		return fpath
+	}
+	if _, err := os.Stat(fpath); err != nil && gno.IsStdlib(pkgPath) {
+		// an override of gnovm/tests/stdlibs.
+		fpath = filepath.Join(l.rootDir, "gnovm", "tests", "stdlibs", filepath.FromSlash(pkgPath), file)
+	}
+	return fpath
+}
+
+// text returns the text of the file at fpath, from its open document if any.
+func (l *loader) text(fpath string) (string, error) {
+	if text, ok := l.docs[fpath]; ok {
+		return text, nil
+	}
+	bz, err := os.ReadFile(fpath)
+	return string(bz), err
+}
+
+func isDir(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
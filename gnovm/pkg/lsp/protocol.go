@@ -0,0 +1,217 @@
+package lsp
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The subset of the Language Server Protocol used by the server.
+// See https://microsoft.github.io/language-server-protocol/specification.
+
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC error codes.
+const (
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+	codeRequestFailed  = -32803
+)
+
+type position struct {
+	Line      int `json:"line"`      // zero-based.
+	Character int `json:"character"` // zero-based, in UTF-16 code units.
+}
+
+type rangeT struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+type location struct {
+	URI   string `json:"uri"`
+	Range rangeT `json:"range"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type textDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+type textDocumentPositionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Position     position               `json:"position"`
+}
+
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Range *rangeT `json:"range,omitempty"`
+		Text  string  `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didSaveParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Text         *string                `json:"text,omitempty"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type documentFormattingParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type diagnostic struct {
+	Range    rangeT `json:"range"`
+	Severity int    `json:"severity"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+const severityError = 1
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []diagnostic `json:"diagnostics"`
+}
+
+type markupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type hover struct {
+	Contents markupContent `json:"contents"`
+	Range    *rangeT       `json:"range,omitempty"`
+}
+
+type completionItem struct {
+	Label  string `json:"label"`
+	Kind   int    `json:"kind,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Completion item kinds.
+const (
+	kindMethod    = 2
+	kindFunction  = 3
+	kindField     = 5
+	kindVariable  = 6
+	kindInterface = 8
+	kindModule    = 9
+	kindKeyword   = 14
+	kindConstant  = 21
+	kindStruct    = 22
+	kindType      = 25 // TypeParameter; used for other named types.
+)
+
+type completionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []completionItem `json:"items"`
+}
+
+type textEdit struct {
+	Range   rangeT `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// ----------------------------------------
+// URIs and positions
+
+// uriToPath returns the file path of a file:// URI.
+func uriToPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+// pathToURI returns the file:// URI of a file path.
+func pathToURI(path string) string {
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
+
+// offsetOf returns the byte offset of pos in text, clamped to its bounds.
+func offsetOf(text string, pos position) int {
+	off := 0
+	for line := 0; line < pos.Line; line++ {
+		i := strings.IndexByte(text[off:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		off += i + 1
+	}
+	for n := 0; n < pos.Character && off < len(text) && text[off] != '\n'; {
+		r, size := utf8.DecodeRuneInString(text[off:])
+		n += utf16.RuneLen(r)
+		off += size
+	}
+	return off
+}
+
+// positionOf returns the position of the byte offset off in text.
+func positionOf(text string, off int) position {
+	if off > len(text) {
+		off = len(text)
+	}
+	var pos position
+	start := 0
+	for i := 0; i < off; i++ {
+		if text[i] == '\n' {
+			pos.Line++
+			start = i + 1
+		}
+	}
+	for _, r := range text[start:off] {
+		pos.Character += utf16.RuneLen(r)
+	}
+	return pos
+}
+
+// positionAt returns the position of a one-based line and column in bytes,
+// like those of go/token, in text.
+func positionAt(text string, line, col int) position {
+	off := 0
+	for l := 1; l < line; l++ {
+		i := strings.IndexByte(text[off:], '\n')
+		if i < 0 {
+			break
+		}
+		off += i + 1
+	}
+	if col > 0 {
+		off += col - 1
+	}
+	return positionOf(text, off)
+}
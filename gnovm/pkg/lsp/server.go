@@ -0,0 +1,380 @@
+// Package lsp implements a language server for Gno, speaking the Language
+// Server Protocol (LSP) with editors.
+//
+// The server type-checks the packages of the open documents as the Go type
+// checker sees them (see [gno.TypeCheckMemPackageWithOptions]), then runs the
+// Gno preprocessor on them, to report diagnostics. It also provides hover
+// with documentation, go-to-definition, completion and formatting.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnofmt"
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/test"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// Server is a language server for Gno. It serves a single client.
+type Server struct {
+	rootDir string
+	r       *bufio.Reader
+	w       io.Writer
+
+	loader    *loader
+	cache     gno.TypeCheckCache   // type-checked imports.
+	snapshots map[string]*snapshot // the last check, by package directory.
+	published map[string][]string  // files with diagnostics, by package directory.
+	resolver  *gnofmt.FSResolver   // packages to import when formatting, lazily loaded.
+
+	// store in which to preprocess packages.
+	baseStore storetypes.CommitStore
+	testStore gno.Store
+
+	shutdown bool
+}
+
+// NewServer returns a server looking up the standard libraries and the
+// examples in rootDir.
+func NewServer(rootDir string) *Server {
+	s := &Server{
+		rootDir:   rootDir,
+		loader:    newLoader(rootDir),
+		snapshots: make(map[string]*snapshot),
+		published: make(map[string][]string),
+	}
+	s.reset()
+	return s
+}
+
+// reset clears the results which depend on the files of other packages, such
+// as after a file is saved.
+func (s *Server) reset() {
+	s.cache = gno.TypeCheckCache{}
+	s.baseStore, s.testStore = test.StoreWithOptions(
+		s.rootDir, io.Discard,
+		test.StoreOptions{PreprocessOnly: true},
+	)
+}
+
+// Serve reads requests from r and writes responses to w, until the client
+// sends the exit notification, or r is closed.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.r = bufio.NewReader(r)
+	s.w = w
+	for {
+		msg, err := s.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("exit without shutdown")
+			}
+			return nil
+		}
+		result, rerr := s.handle(msg)
+		if msg.ID == nil {
+			continue // notification.
+		}
+		resp := &message{JSONRPC: "2.0", ID: msg.ID, Result: result, Error: rerr}
+		if result == nil && rerr == nil {
+			resp.Result = json.RawMessage("null")
+		}
+		s.send(resp)
+	}
+}
+
+// read reads the next message from the client.
+func (s *Server) read() (*message, error) {
+	header, err := textproto.NewReader(s.r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(s.r, buf); err != nil {
+		return nil, err
+	}
+	msg := &message{}
+	if err := json.Unmarshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// send sends a message to the client, ignoring errors: a broken connection
+// is detected when reading the next message.
+func (s *Server) send(msg *message) {
+	buf, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n%s", len(buf), buf)
+}
+
+func (s *Server) notify(method string, params any) {
+	buf, err := json.Marshal(params)
+	if err != nil {
+		panic(err)
+	}
+	s.send(&message{JSONRPC: "2.0", Method: method, Params: buf})
+}
+
+// handle handles a request or a notification, and returns its result.
+func (s *Server) handle(msg *message) (result any, rerr *responseError) {
+	defer func() {
+		// don't bring the server down on a bug.
+		if r := recover(); r != nil {
+			result, rerr = nil, &responseError{Code: codeInternalError, Message: fmt.Sprint(r)}
+		}
+	}()
+
+	unmarshal := func(v any) *responseError {
+		if err := json.Unmarshal(msg.Params, v); err != nil {
+			return &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return nil
+	}
+	var err error
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    1, // full.
+					"save":      map[string]any{},
+				},
+				"hoverProvider":              true,
+				"definitionProvider":         true,
+				"completionProvider":         map[string]any{"triggerCharacters": []string{"."}},
+				"documentFormattingProvider": true,
+			},
+			"serverInfo": map[string]any{"name": "gno"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		err = s.didOpen(params)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		err = s.didChange(params)
+	case "textDocument/didSave":
+		var params didSaveParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		err = s.didSave(params)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		err = s.didClose(params)
+
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		result, err = s.hover(params)
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		result, err = s.definition(params)
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		result, err = s.completion(params)
+	case "textDocument/formatting":
+		var params documentFormattingParams
+		if rerr := unmarshal(&params); rerr != nil {
+			return nil, rerr
+		}
+		result, err = s.format(params)
+
+	default:
+		if msg.ID == nil {
+			return nil, nil // ignore unknown notifications.
+		}
+		return nil, &responseError{Code: codeMethodNotFound, Message: "unsupported method: " + msg.Method}
+	}
+	if err != nil {
+		return nil, &responseError{Code: codeRequestFailed, Message: err.Error()}
+	}
+	// avoid encoding typed nils as null results in a non-nil interface.
+	switch r := result.(type) {
+	case *hover:
+		if r == nil {
+			return nil, nil
+		}
+	case *location:
+		if r == nil {
+			return nil, nil
+		}
+	}
+	return result, nil
+}
+
+// ----------------------------------------
+// Documents
+
+func (s *Server) didOpen(params didOpenParams) error {
+	fpath, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	s.loader.docs[fpath] = params.TextDocument.Text
+	dir := filepath.Dir(fpath)
+	s.loader.modDirs[s.loader.packagePath(dir)] = dir
+	s.checkAndPublish(dir)
+	return nil
+}
+
+func (s *Server) didChange(params didChangeParams) error {
+	fpath, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	text, ok := s.loader.docs[fpath]
+	if !ok {
+		return fmt.Errorf("document not open: %s", params.TextDocument.URI)
+	}
+	for _, change := range params.ContentChanges {
+		if change.Range == nil {
+			text = change.Text
+			continue
+		}
+		start := offsetOf(text, change.Range.Start)
+		end := offsetOf(text, change.Range.End)
+		text = text[:start] + change.Text + text[end:]
+	}
+	s.loader.docs[fpath] = text
+	s.checkAndPublish(filepath.Dir(fpath))
+	return nil
+}
+
+func (s *Server) didSave(params didSaveParams) error {
+	fpath, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	if params.Text != nil {
+		s.loader.docs[fpath] = *params.Text
+	}
+	// the packages importing this one may have changed.
+	s.reset()
+	for dir := range s.snapshots {
+		s.checkAndPublish(dir)
+	}
+	return nil
+}
+
+func (s *Server) didClose(params didCloseParams) error {
+	fpath, err := uriToPath(params.TextDocument.URI)
+	if err != nil {
+		return err
+	}
+	delete(s.loader.docs, fpath)
+	dir := filepath.Dir(fpath)
+	if !s.hasDocs(dir) {
+		// stop tracking the package, and clear its diagnostics.
+		delete(s.snapshots, dir)
+		delete(s.loader.modDirs, s.loader.packagePath(dir))
+		s.publish(dir, nil)
+		return nil
+	}
+	s.checkAndPublish(dir)
+	return nil
+}
+
+// hasDocs returns whether there are open documents in dir.
+func (s *Server) hasDocs(dir string) bool {
+	for fpath := range s.loader.docs {
+		if filepath.Dir(fpath) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// document returns the path and text of an open document.
+func (s *Server) document(uri string) (string, string, error) {
+	fpath, err := uriToPath(uri)
+	if err != nil {
+		return "", "", err
+	}
+	text, ok := s.loader.docs[fpath]
+	if !ok {
+		return "", "", fmt.Errorf("document not open: %s", uri)
+	}
+	return fpath, text, nil
+}
+
+// checkAndPublish checks the package in dir, and publishes its diagnostics.
+func (s *Server) checkAndPublish(dir string) {
+	snap, diags := s.check(dir)
+	s.snapshots[dir] = snap
+	s.publish(dir, diags)
+}
+
+// publish publishes the diagnostics of the files of the package in dir. The
+// open documents, and the files which had diagnostics, are published even
+// without diagnostics, to clear them.
+func (s *Server) publish(dir string, diags map[string][]diagnostic) {
+	files := map[string]bool{}
+	for fpath := range diags {
+		files[fpath] = true
+	}
+	for fpath := range s.loader.docs {
+		if filepath.Dir(fpath) == dir {
+			files[fpath] = true
+		}
+	}
+	for _, fpath := range s.published[dir] {
+		files[fpath] = true
+	}
+	s.published[dir] = nil
+	for fpath := range files {
+		fdiags := diags[fpath]
+		if fdiags == nil {
+			fdiags = []diagnostic{}
+		} else {
+			s.published[dir] = append(s.published[dir], fpath)
+		}
+		s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         pathToURI(fpath),
+			Diagnostics: fdiags,
+		})
+	}
+}
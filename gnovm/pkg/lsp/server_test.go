@@ -0,0 +1,268 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lspClient struct {
+	t    *testing.T
+	conn net.Conn
+	br   *bufio.Reader
+	id   int
+
+	diags map[string][]diagnostic // last published, by URI.
+}
+
+func (c *lspClient) read() *message {
+	c.t.Helper()
+	header, err := textproto.NewReader(c.br).ReadMIMEHeader()
+	require.NoError(c.t, err)
+	n, err := strconv.Atoi(header.Get("Content-Length"))
+	require.NoError(c.t, err)
+	buf := make([]byte, n)
+	_, err = io.ReadFull(c.br, buf)
+	require.NoError(c.t, err)
+	msg := &message{}
+	require.NoError(c.t, json.Unmarshal(buf, msg))
+	if msg.Method == "textDocument/publishDiagnostics" {
+		var params publishDiagnosticsParams
+		require.NoError(c.t, json.Unmarshal(msg.Params, &params))
+		c.diags[params.URI] = params.Diagnostics
+	}
+	return msg
+}
+
+func (c *lspClient) write(msg map[string]any) {
+	c.t.Helper()
+	msg["jsonrpc"] = "2.0"
+	buf, err := json.Marshal(msg)
+	require.NoError(c.t, err)
+	fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(buf), buf)
+}
+
+// request sends a request, and decodes the result of its response into
+// result.
+func (c *lspClient) request(method string, params, result any) *responseError {
+	c.t.Helper()
+	c.id++
+	c.write(map[string]any{"id": c.id, "method": method, "params": params})
+	for {
+		msg := c.read()
+		if msg.ID == nil {
+			continue // notification.
+		}
+		require.Equal(c.t, strconv.Itoa(c.id), string(*msg.ID))
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			buf, err := json.Marshal(msg.Result)
+			require.NoError(c.t, err)
+			require.NoError(c.t, json.Unmarshal(buf, result))
+		}
+		return nil
+	}
+}
+
+// notify sends a notification, and waits for the diagnostics of uri.
+func (c *lspClient) notify(method string, params any, uri string) []diagnostic {
+	c.t.Helper()
+	c.write(map[string]any{"method": method, "params": params})
+	for {
+		msg := c.read()
+		if msg.Method == "textDocument/publishDiagnostics" && strings.Contains(string(msg.Params), `"uri":"`+uri+`"`) {
+			return c.diags[uri]
+		}
+	}
+}
+
+// at returns the position of the n-th byte of the first occurrence of substr
+// in text.
+func at(t *testing.T, text, substr string, n int) position {
+	t.Helper()
+	i := strings.Index(text, substr)
+	require.GreaterOrEqual(t, i, 0, "%q not found", substr)
+	return positionOf(text, i+n)
+}
+
+const testSource = `package hello
+
+import "strings"
+
+// Greeting is the greeting of Hello.
+const Greeting = "hello"
+
+type Point struct {
+	X, Y int
+}
+
+// Norm returns the Manhattan norm of p.
+func (p Point) Norm() int { return p.X + p.Y }
+
+// Hello greets name.
+func Hello(name string) string {
+	p := Point{1, 2}
+	_ = p.Norm()
+	return strings.ToUpper(Greeting + " " + name)
+}
+`
+
+func TestServer(t *testing.T) {
+	rootDir := gnoenv.RootDir()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gno.mod"), []byte("module gno.land/r/test/hello\ngno 0.9\n"), 0o644))
+	fpath := filepath.Join(dir, "hello.gno")
+	require.NoError(t, os.WriteFile(fpath, []byte(testSource), 0o644))
+	uri := pathToURI(fpath)
+	doc := textDocumentIdentifier{URI: uri}
+
+	sconn, cconn := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(rootDir).Serve(sconn, sconn)
+		sconn.Close()
+	}()
+	c := &lspClient{t: t, conn: cconn, br: bufio.NewReader(cconn), diags: map[string][]diagnostic{}}
+
+	var initResult struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	require.Nil(t, c.request("initialize", map[string]any{}, &initResult))
+	assert.Equal(t, true, initResult.Capabilities["hoverProvider"])
+
+	diags := c.notify("textDocument/didOpen", didOpenParams{
+		TextDocument: textDocumentItem{URI: uri, LanguageID: "gno", Version: 1, Text: testSource},
+	}, uri)
+	assert.Empty(t, diags)
+
+	t.Run("hover", func(t *testing.T) {
+		var h hover
+		require.Nil(t, c.request("textDocument/hover", textDocumentPositionParams{doc, at(t, testSource, "Hello(name", 1)}, &h))
+		assert.Contains(t, h.Contents.Value, "func Hello(name string) string")
+		assert.Contains(t, h.Contents.Value, "Hello greets name.")
+		require.NotNil(t, h.Range)
+		assert.Equal(t, at(t, testSource, "Hello(name", 0), h.Range.Start)
+
+		// documentation of another package.
+		require.Nil(t, c.request("textDocument/hover", textDocumentPositionParams{doc, at(t, testSource, "ToUpper", 0)}, &h))
+		assert.Contains(t, h.Contents.Value, "func ToUpper(s string) string")
+		assert.Contains(t, h.Contents.Value, "upper case")
+
+		// method and constant.
+		require.Nil(t, c.request("textDocument/hover", textDocumentPositionParams{doc, at(t, testSource, "Norm()\n", 0)}, &h))
+		assert.Contains(t, h.Contents.Value, "Manhattan norm")
+		require.Nil(t, c.request("textDocument/hover", textDocumentPositionParams{doc, at(t, testSource, "Greeting +", 0)}, &h))
+		assert.Contains(t, h.Contents.Value, `const Greeting untyped string = "hello"`)
+		assert.Contains(t, h.Contents.Value, "greeting of Hello")
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		var loc location
+		require.Nil(t, c.request("textDocument/definition", textDocumentPositionParams{doc, at(t, testSource, "Norm()\n", 0)}, &loc))
+		assert.Equal(t, uri, loc.URI)
+		assert.Equal(t, at(t, testSource, "Norm() int", 0), loc.Range.Start)
+
+		require.Nil(t, c.request("textDocument/definition", textDocumentPositionParams{doc, at(t, testSource, "ToUpper", 2)}, &loc))
+		path, err := uriToPath(loc.URI)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(rootDir, "gnovm", "stdlibs", "strings"), filepath.Dir(path))
+		bz, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(bz)[offsetOf(string(bz), loc.Range.Start):], "ToUpper("))
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		labels := func(list completionList) (ls []string) {
+			for _, item := range list.Items {
+				ls = append(ls, item.Label)
+			}
+			return ls
+		}
+
+		var list completionList
+		require.Nil(t, c.request("textDocument/completion", textDocumentPositionParams{doc, at(t, testSource, "ToUpper", 3)}, &list))
+		assert.Contains(t, labels(list), "ToUpper")
+		assert.NotContains(t, labels(list), "ToLower")
+
+		// an incomplete selector.
+		src := strings.Replace(testSource, "_ = p.Norm()", "_ = p.", 1)
+		diags := c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   doc,
+			"contentChanges": []map[string]any{{"text": src}},
+		}, uri)
+		assert.NotEmpty(t, diags)
+		require.Nil(t, c.request("textDocument/completion", textDocumentPositionParams{doc, at(t, src, "= p.\n", 4)}, &list))
+		assert.Equal(t, []string{"Norm", "X", "Y"}, labels(list))
+
+		// names in scope.
+		src = strings.Replace(testSource, "_ = p.Norm()", "_ = p.Norm()\n\tGr", 1)
+		c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   doc,
+			"contentChanges": []map[string]any{{"text": src}},
+		}, uri)
+		require.Nil(t, c.request("textDocument/completion", textDocumentPositionParams{doc, at(t, src, "Gr\n", 2)}, &list))
+		assert.Contains(t, labels(list), "Greeting")
+		require.Nil(t, c.request("textDocument/completion", textDocumentPositionParams{doc, at(t, src, "return strings", 2)}, &list))
+		assert.Equal(t, []string{"real", "realm", "recover", "return", "revive"}, labels(list))
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		src := strings.Replace(testSource, "return strings.ToUpper", "return 1 + strings.ToUpper", 1)
+		diags := c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   doc,
+			"contentChanges": []map[string]any{{"text": src}},
+		}, uri)
+		require.Len(t, diags, 1)
+		assert.Equal(t, at(t, src, "1 + strings", 0).Line, diags[0].Range.Start.Line)
+		assert.Contains(t, diags[0].Message, "mismatched types")
+
+		// an error of the preprocessor.
+		src = strings.Replace(testSource, "_ = p.Norm()", "_ = p.Norm()\n\tcrossing()", 1)
+		diags = c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   doc,
+			"contentChanges": []map[string]any{{"text": src}},
+		}, uri)
+		require.Len(t, diags, 1)
+		assert.Equal(t, at(t, src, "crossing()", 0).Line, diags[0].Range.Start.Line)
+
+		// fixed.
+		diags = c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   doc,
+			"contentChanges": []map[string]any{{"text": testSource}},
+		}, uri)
+		assert.Empty(t, diags)
+	})
+
+	t.Run("formatting", func(t *testing.T) {
+		src := strings.Replace(testSource, "import \"strings\"\n", "", 1)
+		src = strings.Replace(src, "_ = p.Norm()", "_ =   p.Norm()", 1)
+		c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   doc,
+			"contentChanges": []map[string]any{{"text": src}},
+		}, uri)
+		var edits []textEdit
+		require.Nil(t, c.request("textDocument/formatting", documentFormattingParams{doc}, &edits))
+		require.Len(t, edits, 1)
+		assert.Equal(t, testSource, edits[0].NewText)
+		assert.Equal(t, positionOf(src, len(src)), edits[0].Range.End)
+	})
+
+	assert.NotNil(t, c.request("textDocument/unknown", map[string]any{}, nil))
+	require.Nil(t, c.request("shutdown", nil, nil))
+	c.write(map[string]any{"method": "exit"})
+	require.NoError(t, <-done)
+}
@@ -0,0 +1,218 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"go.uber.org/multierr"
+)
+
+// DefaultBenchTime is the time each benchmark runs for, if
+// [TestOptions.BenchTime] is not set.
+const DefaultBenchTime = time.Second
+
+// DurationOrCount is the value of the -benchtime and -fuzztime flags, which
+// is either a duration or an exact number of iterations, written as "Nx"
+// (e.g. "100x"). It implements [flag.Value].
+type DurationOrCount struct {
+	D time.Duration
+	N int
+}
+
+func (dc DurationOrCount) String() string {
+	switch {
+	case dc.N > 0:
+		return fmt.Sprintf("%dx", dc.N)
+	case dc.D > 0:
+		return dc.D.String()
+	default:
+		return ""
+	}
+}
+
+func (dc *DurationOrCount) Set(s string) error {
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid count %q", s)
+		}
+		*dc = DurationOrCount{N: n}
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*dc = DurationOrCount{D: d}
+	return nil
+}
+
+// benchReport is a mirror of Gno's stdlibs/testing/base.B.report.
+type benchReport struct {
+	Failed  bool
+	Skipped bool
+	Results []benchResult
+}
+
+type benchResult struct {
+	Name     string
+	N        int64
+	T        int64 // total time in nanoseconds.
+	Gas      int64
+	Allocs   int64
+	Bytes    int64
+	SetBytes int64
+	Extra    map[string]float64
+}
+
+// runBenchmarks runs the benchmarks matching opts.BenchFlag in the package
+// pv, and prints their results in the format of Go benchmarks.
+func (opts *TestOptions) runBenchmarks(
+	mpkg *std.MemPackage,
+	pv *gno.PackageValue,
+	benches []testFunc,
+	gs gno.TransactionStore,
+) (errs error) {
+	benchTimeD := opts.BenchTime.D
+	if benchTimeD == 0 {
+		benchTimeD = DefaultBenchTime
+	}
+
+	for _, bf := range benches {
+		// Each benchmark has its own allocator and gas meter, for the
+		// memory and gas reported by testing.B.
+		m := Machine(gs, opts.WriterForStore(), mpkg.Path, false)
+		m.Alloc = gno.NewAllocator(math.MaxInt64)
+		m.GasMeter = storetypes.NewInfiniteGasMeter()
+		m.Coverage = opts.Coverage
+		m.Profile = opts.Profile
+		m.SetActivePackage(pv)
+
+		testingpv := m.Store.GetPackage("testing/base", false)
+		testingtv := gno.TypedValue{T: &gno.PackageType{}, V: testingpv}
+		testingcx := &gno.ConstExpr{TypedValue: testingtv}
+		benchfv := m.Eval(gno.Nx(bf.Name))[0].GetFunc()
+
+		var runBenchX gno.Expr
+		var runBench gno.TypedValue
+		var runBenchF string
+		var runBenchCur gno.Expr
+		if benchfv.IsCrossing() {
+			// As for tests, see runTestFiles.
+			m.SetActivePackage(testingpv)
+			runBenchX = gno.Nx("runBenchmark_cur")
+			runBench = m.Eval(runBenchX)[0]
+			runBenchF = "F_cur"
+			runBenchCur = gno.NewConstExpr(gno.Nx(".cur"), gno.NewConcreteRealm(mpkg.Path))
+			m.SetActivePackage(pv)
+		} else {
+			runBenchX = gno.Sel(testingcx, "RunBenchmark")
+			runBench = m.Eval(runBenchX)[0]
+			runBenchF = "F"
+			runBenchCur = gno.Nx("nil")
+		}
+		runBenchCX := gno.NewConstExpr(runBenchX, runBench)
+
+		eval := m.Eval(gno.Call(
+			runBenchCX,
+			gno.Str(opts.BenchFlag),
+			gno.Num(strconv.FormatInt(int64(benchTimeD), 10)),
+			gno.Num(strconv.Itoa(opts.BenchTime.N)),
+			gno.Nx(strconv.FormatBool(opts.Verbose)),
+			&gno.CompositeLitExpr{
+				Type: gno.Sel(testingcx, "InternalBenchmark"),
+				Elts: gno.KeyValueExprs{
+					{Key: gno.X("Name"), Value: gno.Str(bf.Name)},
+					{Key: gno.X(runBenchF), Value: gno.Nx(bf.Name)},
+					{Key: gno.X("Cur"), Value: runBenchCur},
+				},
+			},
+		))
+
+		var rep benchReport
+		if err := json.Unmarshal([]byte(eval[0].GetString()), &rep); err != nil {
+			errs = multierr.Append(errs, err)
+			fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]\n", bf.Name)
+			continue
+		}
+
+		for _, res := range rep.Results {
+			if !opts.benchHeader {
+				fmt.Fprintf(opts.Error, "pkg: %s\n", strings.TrimSuffix(mpkg.Path, "_test"))
+				opts.benchHeader = true
+			}
+			writeBenchResult(opts.Error, res)
+		}
+
+		if rep.Failed {
+			errs = multierr.Append(errs, fmt.Errorf("failed: %q", bf.Name))
+			if opts.FailfastFlag {
+				return errs
+			}
+		}
+	}
+
+	return errs
+}
+
+// writeBenchResult writes res as a line of Go benchmark output, which can be
+// read by tools such as benchstat.
+func writeBenchResult(w io.Writer, res benchResult) {
+	var sb strings.Builder
+	n := max(res.N, 1)
+	fmt.Fprintf(&sb, "%s\t%8d", res.Name, res.N)
+	if ns := float64(res.T) / float64(n); ns != 0 {
+		sb.WriteByte('\t')
+		prettyPrint(&sb, ns, "ns/op")
+	}
+	if res.SetBytes > 0 && res.T > 0 {
+		mbs := (float64(res.SetBytes) * float64(res.N) / 1e6) / (float64(res.T) / 1e9)
+		fmt.Fprintf(&sb, "\t%7.2f MB/s", mbs)
+	}
+	fmt.Fprintf(&sb, "\t%8d gas/op", res.Gas/n)
+	units := make([]string, 0, len(res.Extra))
+	for unit := range res.Extra {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		sb.WriteByte('\t')
+		prettyPrint(&sb, res.Extra[unit], unit)
+	}
+	fmt.Fprintf(&sb, "\t%8d B/op\t%8d allocs/op\n", res.Bytes/n, res.Allocs/n)
+	io.WriteString(w, sb.String())
+}
+
+// prettyPrint writes x and its unit with the precision used by Go's testing
+// package.
+func prettyPrint(w io.Writer, x float64, unit string) {
+	var format string
+	switch y := math.Abs(x); {
+	case y == 0 || y >= 999.95:
+		format = "%10.0f %s"
+	case y >= 99.995:
+		format = "%12.1f %s"
+	case y >= 9.9995:
+		format = "%13.2f %s"
+	case y >= 0.99995:
+		format = "%14.3f %s"
+	case y >= 0.099995:
+		format = "%15.4f %s"
+	case y >= 0.0099995:
+		format = "%16.5f %s"
+	case y >= 0.00099995:
+		format = "%17.6f %s"
+	default:
+		format = "%18.7f %s"
+	}
+	fmt.Fprintf(w, format, x, unit)
+}
@@ -63,6 +63,8 @@ func (opts *TestOptions) runFiletest(fname string, source []byte) (string, error
 		MaxAllocBytes: maxAlloc,
 		Debug:         opts.Debug,
 		ReviveEnabled: true,
+		Coverage:      opts.Coverage,
+		Profile:       opts.Profile,
 	})
 	defer m.Release()
 	result := opts.runTest(m, pkgPath, fname, source, opslog)
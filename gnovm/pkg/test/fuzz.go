@@ -0,0 +1,785 @@
+package test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"go.uber.org/multierr"
+)
+
+// fuzzFileHeader is the first line of the files of a fuzz corpus, which use
+// the format of Go's fuzz corpus files.
+const fuzzFileHeader = "go test fuzz v1"
+
+// maxFuzzLen is the maximum length of the strings and []byte generated by
+// mutations.
+const maxFuzzLen = 4096
+
+// runFuzzTargets runs the fuzz tests of the package pv matching opts.RunFlag
+// with their seed corpus and the corpus in testdata/fuzz, and fuzzes those
+// matching opts.FuzzFlag.
+func (opts *TestOptions) runFuzzTargets(
+	mpkg *std.MemPackage,
+	pv *gno.PackageValue,
+	targets []testFunc,
+	gs gno.TransactionStore,
+	fsDir string,
+) (errs error) {
+	runFilter := splitRegexp(opts.RunFlag)
+	var fuzzFilter filterMatch
+	if opts.FuzzFlag != "" {
+		fuzzFilter = splitRegexp(opts.FuzzFlag)
+	}
+
+	for _, target := range targets {
+		fuzz := fuzzFilter != nil && shouldRun(fuzzFilter, target.Name)
+		if !fuzz && !shouldRun(runFilter, target.Name) {
+			continue
+		}
+
+		fr := &fuzzRunner{opts: opts, target: target, fsDir: fsDir}
+		if err := fr.run(mpkg, pv, gs, fuzz); err != nil {
+			errs = multierr.Append(errs, err)
+			if opts.FailfastFlag {
+				return errs
+			}
+		}
+	}
+
+	return errs
+}
+
+// fuzzRunner runs a single fuzz test.
+type fuzzRunner struct {
+	opts   *TestOptions
+	target testFunc
+	fsDir  string
+
+	m         *gno.Machine
+	testingpv *gno.PackageValue
+	f         gno.TypedValue // the *testing.F of the fuzz test.
+	call      gno.TypedValue // calls the fuzz function with a list of arguments.
+	types     []reflect.Type // of the arguments of the fuzz function.
+}
+
+func (fr *fuzzRunner) run(mpkg *std.MemPackage, pv *gno.PackageValue, gs gno.TransactionStore, fuzz bool) error {
+	opts := fr.opts
+	name := fr.target.Name
+
+	m := Machine(gs, opts.WriterForStore(), mpkg.Path, false)
+	m.Coverage = opts.Coverage
+	m.Profile = opts.Profile
+	if fuzz {
+		m.Coverage = opts.fuzzCoverage
+	}
+	m.SetActivePackage(pv)
+	fr.m = m
+
+	fr.testingpv = m.Store.GetPackage("testing/base", false)
+	testingtv := gno.TypedValue{T: &gno.PackageType{}, V: fr.testingpv}
+	testingcx := &gno.ConstExpr{TypedValue: testingtv}
+	targetfv := m.Eval(gno.Nx(name))[0].GetFunc()
+
+	// Run the fuzz test, to get its seed corpus and fuzz function.
+	var runTargetX gno.Expr
+	var runTargetF string
+	var runTargetCur gno.Expr
+	if targetfv.IsCrossing() {
+		// As for tests, see runTestFiles.
+		m.SetActivePackage(fr.testingpv)
+		runTargetX = gno.Nx("runFuzzTarget_cur")
+		runTargetF = "F_cur"
+		runTargetCur = gno.NewConstExpr(gno.Nx(".cur"), gno.NewConcreteRealm(mpkg.Path))
+	} else {
+		runTargetX = gno.Sel(testingcx, "RunFuzzTarget")
+		runTargetF = "F"
+		runTargetCur = gno.Nx("nil")
+	}
+	runTargetCX := gno.NewConstExpr(runTargetX, m.Eval(runTargetX)[0])
+	m.SetActivePackage(pv)
+	ftv := m.Eval(gno.Call(
+		runTargetCX,
+		gno.Str(opts.RunFlag),
+		gno.Nx(strconv.FormatBool(opts.Verbose)),
+		&gno.CompositeLitExpr{
+			Type: gno.Sel(testingcx, "InternalFuzzTarget"),
+			Elts: gno.KeyValueExprs{
+				{Key: gno.X("Name"), Value: gno.Str(name)},
+				{Key: gno.X(runTargetF), Value: gno.Nx(name)},
+				{Key: gno.X("Cur"), Value: runTargetCur},
+			},
+		},
+	))[0]
+	fr.f = ftv
+
+	// The rest is done from testing/base, to access the unexported fields
+	// and functions of F.
+	m.SetActivePackage(fr.testingpv)
+
+	var names []string
+	var inputs [][]any
+	fntv := m.Eval(gno.Sel(fr.fx(), "fn"))[0]
+	if fntv.T != nil {
+		err := fr.setFunc(fntv)
+		if err == nil {
+			names, inputs, err = fr.corpus()
+		}
+		if err != nil {
+			fmt.Fprintf(opts.Error, "--- FAIL: %s\n    %v\n", name, err)
+			return fmt.Errorf("failed: %q", name)
+		}
+	}
+
+	rep, err := fr.runCorpus(names, inputs)
+	if err != nil {
+		return err
+	}
+	if rep.Failed {
+		return fmt.Errorf("failed: %q", name)
+	}
+	if !fuzz || rep.Skipped || fr.call.T == nil {
+		return nil
+	}
+	return fr.fuzz(inputs)
+}
+
+// setFunc checks the type of the fuzz function fntv, and creates fr.call to
+// call it with a list of arguments.
+func (fr *fuzzRunner) setFunc(fntv gno.TypedValue) error {
+	ft, ok := gno.BaseOf(fntv.T).(*gno.FuncType)
+	if !ok || ft.HasVarg() || len(ft.Results) > 0 || len(ft.Params) == 0 ||
+		!isTestingT(ft.Params[0].Type) {
+		return fmt.Errorf("fuzz function must be of the form func(*testing.T, ...), got %s", fntv.T.String())
+	}
+
+	// The arguments are converted from []any with type assertions.
+	var params, args []string
+	fr.types = nil
+	for i, p := range ft.Params[1:] {
+		rt := fuzzGoType(p.Type)
+		if rt == nil {
+			return fmt.Errorf("unsupported type of fuzz function argument: %s", p.Type.String())
+		}
+		fr.types = append(fr.types, rt)
+		params = append(params, rt.String())
+		args = append(args, fmt.Sprintf("args[%d].(%s)", i, rt.String()))
+	}
+	src := fmt.Sprintf(
+		"func(fn func(*T, %s)) func(*T, []any) { return func(t *T, args []any) { fn(t, %s) } }",
+		strings.Join(params, ", "), strings.Join(args, ", "))
+	if len(params) == 0 {
+		src = "func(fn func(*T)) func(*T, []any) { return func(t *T, args []any) { fn(t) } }"
+	}
+	fncx := &gno.ConstExpr{TypedValue: fntv}
+	fr.call = fr.m.Eval(gno.Call(gno.MustParseExpr(src), fncx))[0]
+	return nil
+}
+
+// fx and callx return new expressions of fr.f and fr.call, as expressions
+// can't be reused across evaluations.
+func (fr *fuzzRunner) fx() gno.Expr    { return &gno.ConstExpr{TypedValue: fr.f} }
+func (fr *fuzzRunner) callx() gno.Expr { return &gno.ConstExpr{TypedValue: fr.call} }
+
+func isTestingT(t gno.Type) bool {
+	pt, ok := t.(*gno.PointerType)
+	if !ok {
+		return false
+	}
+	dt, ok := pt.Elt.(*gno.DeclaredType)
+	return ok && dt.PkgPath == "testing/base" && dt.Name == "T"
+}
+
+// fuzzGoType returns the Go type of arguments of type t of fuzz functions,
+// or nil if t is not supported.
+func fuzzGoType(t gno.Type) reflect.Type {
+	switch t {
+	case gno.BoolType:
+		return reflect.TypeFor[bool]()
+	case gno.StringType:
+		return reflect.TypeFor[string]()
+	case gno.IntType:
+		return reflect.TypeFor[int]()
+	case gno.Int8Type:
+		return reflect.TypeFor[int8]()
+	case gno.Int16Type:
+		return reflect.TypeFor[int16]()
+	case gno.Int32Type:
+		return reflect.TypeFor[int32]()
+	case gno.Int64Type:
+		return reflect.TypeFor[int64]()
+	case gno.UintType:
+		return reflect.TypeFor[uint]()
+	case gno.Uint8Type:
+		return reflect.TypeFor[uint8]()
+	case gno.Uint16Type:
+		return reflect.TypeFor[uint16]()
+	case gno.Uint32Type:
+		return reflect.TypeFor[uint32]()
+	case gno.Uint64Type:
+		return reflect.TypeFor[uint64]()
+	case gno.Float32Type:
+		return reflect.TypeFor[float32]()
+	case gno.Float64Type:
+		return reflect.TypeFor[float64]()
+	}
+	if st, ok := t.(*gno.SliceType); ok && st.Elt == gno.Uint8Type {
+		return reflect.TypeFor[[]byte]()
+	}
+	return nil
+}
+
+// corpus returns the seed corpus added with F.Add, followed by the corpus in
+// testdata/fuzz/FuzzXxx, and the names of their inputs.
+func (fr *fuzzRunner) corpus() (names []string, inputs [][]any, err error) {
+	seeds := fr.m.Eval(gno.Sel(fr.fx(), "corpus"))[0]
+	for i := range seeds.GetLength() {
+		seed := seeds.GetPointerAtIndexInt(fr.m.Store, i).Deref()
+		input := make([]any, seed.GetLength())
+		var types []string
+		for j := range input {
+			arg := seed.GetPointerAtIndexInt(fr.m.Store, j).Deref()
+			if arg.T == nil {
+				types = append(types, "nil")
+				continue
+			}
+			types = append(types, arg.T.String())
+			if rt := fuzzGoType(arg.T); rt != nil {
+				rv := reflect.New(rt).Elem()
+				arg.DeepFill(fr.m.Store)
+				gno.Gno2GoValue(&arg, rv)
+				input[j] = rv.Interface()
+			}
+		}
+		if !fr.matchTypes(input) {
+			return nil, nil, fmt.Errorf("mismatched types in corpus entry: [%s], want %v", strings.Join(types, ", "), fr.types)
+		}
+		names = append(names, fmt.Sprintf("seed#%d", i))
+		inputs = append(inputs, input)
+	}
+
+	if fr.fsDir == "" {
+		return names, inputs, nil
+	}
+	dir := filepath.Join(fr.fsDir, "testdata", "fuzz", fr.target.Name)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return names, inputs, nil
+		}
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, nil, err
+		}
+		input, err := unmarshalCorpusFile(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if !fr.matchTypes(input) {
+			return nil, nil, fmt.Errorf("%s: mismatched types in corpus entry: %v", entry.Name(), input)
+		}
+		names = append(names, entry.Name())
+		inputs = append(inputs, input)
+	}
+	return names, inputs, nil
+}
+
+// matchTypes returns whether the types of input are those of the arguments of
+// the fuzz function.
+func (fr *fuzzRunner) matchTypes(input []any) bool {
+	if len(input) != len(fr.types) {
+		return false
+	}
+	for i, v := range input {
+		if v == nil || reflect.TypeOf(v) != fr.types[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// runCorpus runs the fuzz function with the inputs as subtests of the fuzz
+// test, and returns the report of the fuzz test.
+func (fr *fuzzRunner) runCorpus(names []string, inputs [][]any) (rep report, err error) {
+	var call gno.Expr = gno.Nx("nil") // if the fuzz test failed, or didn't call F.Fuzz.
+	if fr.call.T != nil {
+		call = fr.callx()
+	}
+	nameExprs := make([]gno.KeyValueExpr, len(names))
+	for i, name := range names {
+		nameExprs[i] = gno.KeyValueExpr{Value: gno.Str(name)}
+	}
+	inputExprs := make([]gno.KeyValueExpr, len(inputs))
+	for i, input := range inputs {
+		inputExprs[i] = gno.KeyValueExpr{Value: fr.inputExpr(input)}
+	}
+	ret := fr.m.Eval(gno.Call(
+		gno.Nx("runFuzzCorpus"),
+		fr.fx(),
+		call,
+		&gno.CompositeLitExpr{Type: gno.X("[]string"), Elts: nameExprs},
+		&gno.CompositeLitExpr{Type: gno.X("[][]any"), Elts: inputExprs},
+	))[0].GetString()
+	if err := json.Unmarshal([]byte(ret), &rep); err != nil {
+		fmt.Fprintf(fr.opts.Error, "--- FAIL: %s [internal gno testing error]\n", fr.target.Name)
+		return rep, err
+	}
+	return rep, nil
+}
+
+// inputExpr returns the expression of input as a []any.
+func (fr *fuzzRunner) inputExpr(input []any) gno.Expr {
+	elts := make([]gno.KeyValueExpr, len(input))
+	for i, v := range input {
+		var tv gno.TypedValue
+		if b, ok := v.([]byte); ok {
+			tv = gno.TypedValue{
+				T: &gno.SliceType{Elt: gno.Uint8Type},
+				V: fr.m.Alloc.NewSliceFromData(b),
+			}
+		} else {
+			tv = gno.Go2GnoValue(fr.m.Alloc, fr.m.Store, reflect.ValueOf(v))
+		}
+		elts[i] = gno.KeyValueExpr{Value: &gno.ConstExpr{TypedValue: tv}}
+	}
+	return &gno.CompositeLitExpr{Type: gno.X("[]any"), Elts: elts}
+}
+
+// fuzz runs the fuzz function with inputs mutated from the corpus, until it
+// fails or for opts.FuzzTime. Inputs which execute new edges between
+// statements of the package are added to the corpus.
+func (fr *fuzzRunner) fuzz(corpus [][]any) error {
+	opts := fr.opts
+	name := fr.target.Name
+	cov := fr.m.Coverage
+
+	if len(corpus) == 0 {
+		// Start from the zero values.
+		input := make([]any, len(fr.types))
+		for i, rt := range fr.types {
+			input[i] = reflect.Zero(rt).Interface()
+		}
+		corpus = append(corpus, input)
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	start := time.Now()
+	lastLog := start
+	execs, interesting := 0, 0
+	edges := cov.Edges()
+	logf := func() {
+		elapsed := time.Since(start)
+		fmt.Fprintf(opts.Error, "fuzz: elapsed: %s, execs: %d (%.0f/sec), new interesting: %d (total: %d)\n",
+			elapsed.Round(time.Second), execs, float64(execs)/elapsed.Seconds(), interesting, len(corpus))
+	}
+
+	for {
+		if (opts.FuzzTime.N > 0 && execs >= opts.FuzzTime.N) ||
+			(opts.FuzzTime.D > 0 && time.Since(start) >= opts.FuzzTime.D) {
+			logf()
+			return nil
+		}
+		if time.Since(lastLog) >= 3*time.Second {
+			logf()
+			lastLog = time.Now()
+		}
+
+		input := mutateInput(rng, corpus[rng.Intn(len(corpus))])
+		ret := fr.m.Eval(gno.Call(
+			gno.Nx("runFuzzInput"),
+			fr.fx(),
+			fr.callx(),
+			fr.inputExpr(input),
+		))[0].GetString()
+		execs++
+
+		var rep report
+		if err := json.Unmarshal([]byte(ret), &rep); err != nil {
+			fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]\n", name)
+			return err
+		}
+		if rep.Failed {
+			logf()
+			return fr.writeCrasher(input)
+		}
+		if n := cov.Edges(); n > edges {
+			edges = n
+			interesting++
+			corpus = append(corpus, input)
+		}
+	}
+}
+
+// writeCrasher writes the failing input to testdata/fuzz/FuzzXxx, so that it
+// is run with the corpus in the next runs of the fuzz test.
+func (fr *fuzzRunner) writeCrasher(input []any) error {
+	name := fr.target.Name
+	data := marshalCorpusFile(input)
+	if fr.fsDir == "" {
+		fmt.Fprintf(fr.opts.Error, "    Failing input:\n%s", data)
+		return fmt.Errorf("failed: %q", name)
+	}
+
+	file := fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+	dir := filepath.Join(fr.fsDir, "testdata", "fuzz", name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), data, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(fr.opts.Error, "    Failing input written to %s\n    To re-run:\n    gno test -run=%s/%s\n",
+		filepath.Join("testdata", "fuzz", name, file), name, file)
+	return fmt.Errorf("failed: %q", name)
+}
+
+// ----------------------------------------
+// Mutations
+
+// mutateInput returns a copy of input with one of its arguments mutated.
+func mutateInput(rng *rand.Rand, input []any) []any {
+	out := append([]any(nil), input...)
+	if len(out) == 0 {
+		return out
+	}
+	i := rng.Intn(len(out))
+	out[i] = mutateValue(rng, out[i])
+	return out
+}
+
+func mutateValue(rng *rand.Rand, v any) any {
+	switch v := v.(type) {
+	case bool:
+		return !v
+	case string:
+		return string(mutateBytes(rng, []byte(v)))
+	case []byte:
+		return mutateBytes(rng, v)
+	case int:
+		return int(mutateInt(rng, int64(v)))
+	case int8:
+		return int8(mutateInt(rng, int64(v)))
+	case int16:
+		return int16(mutateInt(rng, int64(v)))
+	case int32:
+		return int32(mutateInt(rng, int64(v)))
+	case int64:
+		return mutateInt(rng, v)
+	case uint:
+		return uint(mutateInt(rng, int64(v)))
+	case uint8:
+		return uint8(mutateInt(rng, int64(v)))
+	case uint16:
+		return uint16(mutateInt(rng, int64(v)))
+	case uint32:
+		return uint32(mutateInt(rng, int64(v)))
+	case uint64:
+		return uint64(mutateInt(rng, int64(v)))
+	case float32:
+		return float32(mutateFloat(rng, float64(v)))
+	case float64:
+		return mutateFloat(rng, v)
+	default:
+		panic(fmt.Sprintf("unexpected fuzz argument type %T", v))
+	}
+}
+
+// Values which often trigger edge cases; they are truncated to the size of
+// the integer type.
+var interestingInts = []int64{
+	0, 1, -1, 16, 32, 64, 100, 127, -128, 255, 256, 1024, 4096,
+	math.MaxInt16, math.MinInt16, math.MaxUint16,
+	math.MaxInt32, math.MinInt32, math.MaxUint32,
+	math.MaxInt64, math.MinInt64,
+}
+
+func mutateInt(rng *rand.Rand, v int64) int64 {
+	switch rng.Intn(5) {
+	case 0:
+		return v + 1 + rng.Int63n(16)
+	case 1:
+		return v - 1 - rng.Int63n(16)
+	case 2:
+		return v ^ 1<<rng.Intn(64)
+	case 3:
+		return interestingInts[rng.Intn(len(interestingInts))]
+	default:
+		return int64(rng.Uint64())
+	}
+}
+
+var interestingFloats = []float64{
+	0, math.Copysign(0, -1), 1, -1, 0.5, math.MaxFloat64, -math.MaxFloat64,
+	math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1), math.NaN(),
+}
+
+func mutateFloat(rng *rand.Rand, v float64) float64 {
+	switch rng.Intn(5) {
+	case 0:
+		return v + rng.NormFloat64()
+	case 1:
+		return v * (rng.Float64()*4 - 2)
+	case 2:
+		return math.Float64frombits(math.Float64bits(v) ^ 1<<rng.Intn(64))
+	case 3:
+		return interestingFloats[rng.Intn(len(interestingFloats))]
+	default:
+		return float64(mutateInt(rng, int64(v)))
+	}
+}
+
+var interestingBytes = []byte{0, 1, 0x7f, 0x80, 0xff, ' ', '0', '9', 'a', 'z', 'A', 'Z', '-', '.', '/', '\\', '"', '\n'}
+
+func mutateBytes(rng *rand.Rand, b []byte) []byte {
+	b = append([]byte(nil), b...)
+	if len(b) == 0 {
+		return append(b, byte(rng.Intn(256)))
+	}
+	switch i := rng.Intn(len(b)); rng.Intn(6) {
+	case 0: // flip a bit
+		b[i] ^= 1 << rng.Intn(8)
+	case 1: // set a random or interesting byte
+		if rng.Intn(2) == 0 {
+			b[i] = byte(rng.Intn(256))
+		} else {
+			b[i] = interestingBytes[rng.Intn(len(interestingBytes))]
+		}
+	case 2: // insert a byte
+		if len(b) < maxFuzzLen {
+			b = append(b[:i], append([]byte{byte(rng.Intn(256))}, b[i:]...)...)
+		}
+	case 3: // delete bytes
+		n := 1 + rng.Intn(min(4, len(b)-i))
+		b = append(b[:i], b[i+n:]...)
+	case 4: // duplicate bytes
+		n := 1 + rng.Intn(min(8, len(b)-i))
+		if len(b)+n <= maxFuzzLen {
+			b = append(b[:i+n], append(append([]byte(nil), b[i:i+n]...), b[i+n:]...)...)
+		}
+	default: // add a small delta to a byte
+		b[i] += byte(rng.Intn(35)) - 17
+	}
+	return b
+}
+
+// ----------------------------------------
+// Corpus files
+
+// marshalCorpusFile encodes the input in the format of Go's fuzz corpus files.
+func marshalCorpusFile(input []any) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(fuzzFileHeader + "\n")
+	for _, v := range input {
+		switch v := v.(type) {
+		case string:
+			fmt.Fprintf(&buf, "string(%q)\n", v)
+		case []byte:
+			fmt.Fprintf(&buf, "[]byte(%q)\n", v)
+		case float32:
+			if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				fmt.Fprintf(&buf, "math.Float32frombits(0x%x)\n", math.Float32bits(v))
+			} else {
+				fmt.Fprintf(&buf, "float32(%s)\n", strconv.FormatFloat(float64(v), 'g', -1, 32))
+			}
+		case float64:
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				fmt.Fprintf(&buf, "math.Float64frombits(0x%x)\n", math.Float64bits(v))
+			} else {
+				fmt.Fprintf(&buf, "float64(%s)\n", strconv.FormatFloat(v, 'g', -1, 64))
+			}
+		default:
+			fmt.Fprintf(&buf, "%T(%v)\n", v, v)
+		}
+	}
+	return buf.Bytes()
+}
+
+// unmarshalCorpusFile decodes an input in the format of Go's fuzz corpus
+// files.
+func unmarshalCorpusFile(b []byte) ([]any, error) {
+	lines := bytes.Split(b, []byte("\n"))
+	if len(lines) == 0 || string(bytes.TrimSpace(lines[0])) != fuzzFileHeader {
+		return nil, fmt.Errorf("must start with %q", fuzzFileHeader)
+	}
+	var input []any
+	for _, line := range lines[1:] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		v, err := parseCorpusValue(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("malformed line %q: %w", line, err)
+		}
+		input = append(input, v)
+	}
+	return input, nil
+}
+
+func parseCorpusValue(line string) (any, error) {
+	x, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, errors.New("expected a conversion of a single value")
+	}
+	arg := call.Args[0]
+
+	var typ string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		typ = fun.Name
+	case *ast.ArrayType:
+		if elt, ok := fun.Elt.(*ast.Ident); ok && fun.Len == nil && (elt.Name == "byte" || elt.Name == "uint8") {
+			typ = "[]byte"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok && pkg.Name == "math" {
+			typ = "math." + fun.Sel.Name
+		}
+	}
+
+	switch typ {
+	case "bool":
+		if id, ok := arg.(*ast.Ident); ok && (id.Name == "true" || id.Name == "false") {
+			return id.Name == "true", nil
+		}
+		return nil, errors.New("expected true or false")
+	case "string":
+		s, err := parseCorpusString(arg)
+		return s, err
+	case "[]byte":
+		s, err := parseCorpusString(arg)
+		return []byte(s), err
+	case "float32":
+		f, err := parseCorpusFloat(arg, 32)
+		return float32(f), err
+	case "float64":
+		return parseCorpusFloat(arg, 64)
+	case "math.Float32frombits":
+		n, err := parseCorpusUint(arg, 32)
+		return math.Float32frombits(uint32(n)), err
+	case "math.Float64frombits":
+		n, err := parseCorpusUint(arg, 64)
+		return math.Float64frombits(n), err
+	case "int":
+		n, err := parseCorpusInt(arg, 64)
+		return int(n), err
+	case "int8":
+		n, err := parseCorpusInt(arg, 8)
+		return int8(n), err
+	case "int16":
+		n, err := parseCorpusInt(arg, 16)
+		return int16(n), err
+	case "int32", "rune":
+		n, err := parseCorpusInt(arg, 32)
+		return int32(n), err
+	case "int64":
+		return parseCorpusInt(arg, 64)
+	case "uint":
+		n, err := parseCorpusUint(arg, 64)
+		return uint(n), err
+	case "uint8", "byte":
+		n, err := parseCorpusUint(arg, 8)
+		return uint8(n), err
+	case "uint16":
+		n, err := parseCorpusUint(arg, 16)
+		return uint16(n), err
+	case "uint32":
+		n, err := parseCorpusUint(arg, 32)
+		return uint32(n), err
+	case "uint64":
+		return parseCorpusUint(arg, 64)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", types.ExprString(call.Fun))
+	}
+}
+
+// parseCorpusLit returns the kind and the value of the literal x, which may
+// be negated.
+func parseCorpusLit(x ast.Expr) (token.Token, string, error) {
+	neg := ""
+	if ux, ok := x.(*ast.UnaryExpr); ok && ux.Op == token.SUB {
+		neg, x = "-", ux.X
+	}
+	lit, ok := x.(*ast.BasicLit)
+	if !ok {
+		return token.ILLEGAL, "", errors.New("expected a literal")
+	}
+	if lit.Kind == token.CHAR {
+		r, _, _, err := strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+		if err != nil {
+			return token.ILLEGAL, "", err
+		}
+		return token.INT, neg + strconv.Itoa(int(r)), nil
+	}
+	return lit.Kind, neg + lit.Value, nil
+}
+
+func parseCorpusString(x ast.Expr) (string, error) {
+	kind, val, err := parseCorpusLit(x)
+	if err != nil {
+		return "", err
+	}
+	if kind != token.STRING {
+		return "", errors.New("expected a string literal")
+	}
+	return strconv.Unquote(val)
+}
+
+func parseCorpusInt(x ast.Expr, bits int) (int64, error) {
+	kind, val, err := parseCorpusLit(x)
+	if err != nil {
+		return 0, err
+	}
+	if kind != token.INT {
+		return 0, errors.New("expected an integer literal")
+	}
+	return strconv.ParseInt(val, 0, bits)
+}
+
+func parseCorpusUint(x ast.Expr, bits int) (uint64, error) {
+	kind, val, err := parseCorpusLit(x)
+	if err != nil {
+		return 0, err
+	}
+	if kind != token.INT {
+		return 0, errors.New("expected an integer literal")
+	}
+	return strconv.ParseUint(val, 0, bits)
+}
+
+func parseCorpusFloat(x ast.Expr, bits int) (float64, error) {
+	kind, val, err := parseCorpusLit(x)
+	if err != nil {
+		return 0, err
+	}
+	if kind != token.INT && kind != token.FLOAT {
+		return 0, errors.New("expected a number literal")
+	}
+	return strconv.ParseFloat(val, bits)
+}
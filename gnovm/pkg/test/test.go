@@ -61,6 +61,7 @@ func Context(caller crypto.Bech32Address, pkgPath string, send std.Coins) *tests
 		Banker:          banker,
 		Params:          newTestParams(),
 		EventLogger:     sdk.NewEventLogger(),
+		Scheduler:       newTestScheduler(),
 	}
 	return &teststd.TestExecContext{
 		ExecContext: ctx,
@@ -111,6 +112,33 @@ func (tp *testParams) SetUint64(key string, val uint64)    { /* noop */ }
 func (tp *testParams) SetString(key string, val string)    { /* noop */ }
 func (tp *testParams) SetStrings(key string, val []string) { /* noop */ }
 
+// ----------------------------------------
+// testScheduler
+
+// testScheduler registers scheduled calls, which are never executed.
+type testScheduler struct {
+	lastID uint64
+	calls  map[uint64]string // id -> pkgPath
+}
+
+func newTestScheduler() *testScheduler {
+	return &testScheduler{calls: make(map[uint64]string)}
+}
+
+func (ts *testScheduler) ScheduleCall(pkgPath string, height, timestamp int64, fn string, args []string) (uint64, error) {
+	ts.lastID++
+	ts.calls[ts.lastID] = pkgPath
+	return ts.lastID, nil
+}
+
+func (ts *testScheduler) CancelCall(pkgPath string, id uint64) bool {
+	if ts.calls[id] != pkgPath {
+		return false
+	}
+	delete(ts.calls, id)
+	return true
+}
+
 // ----------------------------------------
 // main test function
 
@@ -145,9 +173,27 @@ type TestOptions struct {
 	Metrics bool
 	// Uses Error to print the events emitted.
 	Events bool
+	// Counts the statements executed by tests, if set.
+	Coverage *gno.Coverage
+	// Samples the call stacks of tests, if set.
+	Profile *gno.Profile
+	// Serves the debugger on unit tests to a DAP client, if set, instead of
+	// the interactive debugger enabled by Debug.
+	DebugSession *gno.DAPSession
+	// Flag to filter benchmarks to run; no benchmarks are run if empty.
+	BenchFlag string
+	// How long each benchmark runs for, or how many iterations.
+	BenchTime DurationOrCount
+	// Flag to filter fuzz tests to fuzz; no fuzzing is done if empty.
+	FuzzFlag string
+	// How long to fuzz each fuzz test for, or how many inputs to run;
+	// unlimited if zero.
+	FuzzTime DurationOrCount
 
 	filetestBuffer bytes.Buffer
 	outWriter      proxyWriter
+	benchHeader    bool          // whether the "pkg:" line of benchmarks was printed.
+	fuzzCoverage   *gno.Coverage // of the package being tested, when fuzzing.
 }
 
 // WriterForStore is the writer that should be passed to [Store], so that
@@ -217,6 +263,7 @@ func tee(ptr *io.Writer, dst io.Writer) (revert func()) {
 func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 	opts.outWriter.w = opts.Output
 	opts.outWriter.errW = opts.Error
+	opts.benchHeader = false
 
 	var errs error
 
@@ -231,6 +278,21 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 	// not necessarily integration tests, it's just for our internal reference.)
 	tset, itset, itfiles, ftfiles := parseMemPackageTests(mpkg)
 
+	// Fuzzing is guided by the coverage of the package.
+	opts.fuzzCoverage = nil
+	if opts.FuzzFlag != "" {
+		opts.fuzzCoverage = gno.NewCoverage()
+		for _, mfile := range mpkg.Files {
+			if !strings.HasSuffix(mfile.Name, ".gno") || strings.HasSuffix(mfile.Name, "_test.gno") ||
+				strings.HasSuffix(mfile.Name, "_filetest.gno") {
+				continue
+			}
+			if fn, err := gno.ParseFile(mfile.Name, mfile.Body); err == nil {
+				opts.fuzzCoverage.AddFile(mpkg.Path, mfile.Name, fn)
+			}
+		}
+	}
+
 	// Testing with *_test.gno
 	if len(tset.Files)+len(itset.Files) > 0 {
 		// Create a common cw/gs for both the `pkg` tests as well as the `pkg_test`
@@ -241,7 +303,7 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 
 		// Run test files in pkg.
 		if len(tset.Files) > 0 {
-			err := opts.runTestFiles(mpkg, tset, gs)
+			err := opts.runTestFiles(mpkg, tset, gs, fsDir)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -255,7 +317,7 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 				Files: itfiles,
 			}
 
-			err := opts.runTestFiles(itPkg, itset, gs)
+			err := opts.runTestFiles(itPkg, itset, gs, fsDir)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -310,6 +372,7 @@ func (opts *TestOptions) runTestFiles(
 	mpkg *std.MemPackage,
 	files *gno.FileSet,
 	gs gno.TransactionStore,
+	fsDir string,
 ) (errs error) {
 	var m *gno.Machine
 	defer func() {
@@ -325,7 +388,7 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}()
 
-	tests := loadTestFuncs(mpkg.Name, files)
+	tests := loadTestFuncs(mpkg.Name, "Test", files)
 
 	var alloc *gno.Allocator
 	if opts.Metrics {
@@ -337,6 +400,8 @@ func (opts *TestOptions) runTestFiles(
 	// Check if we already have the package - it may have been eagerly loaded.
 	m = Machine(gs, opts.WriterForStore(), mpkg.Path, opts.Debug)
 	m.Alloc = alloc
+	m.Coverage = opts.Coverage
+	m.Profile = opts.Profile
 	if gs.GetMemPackage(mpkg.Path) == nil {
 		m.RunMemPackage(mpkg, true)
 	} else {
@@ -358,6 +423,8 @@ func (opts *TestOptions) runTestFiles(
 		// - Wrap here.
 		m = Machine(gs, opts.WriterForStore(), mpkg.Path, opts.Debug)
 		m.Alloc = alloc.Reset()
+		m.Coverage = opts.Coverage
+		m.Profile = opts.Profile
 		m.SetActivePackage(pv)
 
 		testingpv := m.Store.GetPackage("testing/base", false)
@@ -404,7 +471,7 @@ func (opts *TestOptions) runTestFiles(
 		}
 		runTestCX := gno.NewConstExpr(runTestX, runTest)
 
-		if opts.Debug {
+		if opts.Debug || opts.DebugSession != nil {
 			fileContent := func(ppath, name string) string {
 				p := filepath.Join(opts.RootDir, ppath, name)
 				b, err := os.ReadFile(p)
@@ -418,7 +485,12 @@ func (opts *TestOptions) runTestFiles(
 				}
 				return string(b)
 			}
-			m.Debugger.Enable(os.Stdin, os.Stdout, fileContent)
+			if opts.DebugSession != nil {
+				opts.DebugSession.SetPackageDir(mpkg.Path, fsDir)
+				m.Debugger.EnableDAP(opts.DebugSession, fileContent)
+			} else {
+				m.Debugger.Enable(os.Stdin, os.Stdout, fileContent)
+			}
 		}
 
 		eval := m.Eval(gno.Call(
@@ -492,6 +564,23 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}
 
+	fuzzTargets := loadTestFuncs(mpkg.Name, "Fuzz", files)
+	if len(fuzzTargets) > 0 {
+		if err := opts.runFuzzTargets(mpkg, pv, fuzzTargets, gs, fsDir); err != nil {
+			errs = multierr.Append(errs, err)
+			if opts.FailfastFlag {
+				return errs
+			}
+		}
+	}
+
+	if opts.BenchFlag != "" {
+		benches := loadTestFuncs(mpkg.Name, "Benchmark", files)
+		if err := opts.runBenchmarks(mpkg, pv, benches, gs); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
 	return errs
 }
 
@@ -507,7 +596,8 @@ type testFunc struct {
 	Filename string
 }
 
-func loadTestFuncs(pkgName string, tfiles *gno.FileSet) (rt []testFunc) {
+// loadTestFuncs returns the functions in tfiles whose name starts with prefix.
+func loadTestFuncs(pkgName, prefix string, tfiles *gno.FileSet) (rt []testFunc) {
 	for _, tf := range tfiles.Files {
 		for _, d := range tf.Decls {
 			if fd, ok := d.(*gno.FuncDecl); ok {
@@ -515,7 +605,7 @@ func loadTestFuncs(pkgName string, tfiles *gno.FileSet) (rt []testFunc) {
 					continue
 				}
 				fname := string(fd.Name)
-				if strings.HasPrefix(fname, "Test") {
+				if strings.HasPrefix(fname, prefix) {
 					tf := testFunc{
 						Package:  pkgName,
 						Name:     fname,
@@ -0,0 +1,1050 @@
+package json
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// The CPU cycles charged per byte of JSON, which is read or written by the
+// natives. Scanning only checks the syntax, while encoding and decoding also
+// walk and allocate Gno values.
+const (
+	cpuPerByteScan  = 2
+	cpuPerByteCodec = 12
+)
+
+// maxDepth is the depth of pointers, slices and maps at which Marshal reports
+// a cycle.
+const maxDepth = 1000
+
+func X_marshal(m *gno.Machine, v gno.TypedValue) (b []byte, msg string) {
+	e := &encoder{m: m, store: m.Store, fields: fieldCache{}}
+	err := e.marshal(v)
+	e.charge()
+	if err != nil {
+		return nil, err.Error()
+	}
+	return e.buf.Bytes(), ""
+}
+
+func X_unmarshal(m *gno.Machine, data []byte, v gno.TypedValue) string {
+	m.IncrCPU(int64(len(data)) * cpuPerByteCodec)
+	if v.T == nil {
+		return "json: Unmarshal(nil)"
+	}
+	pt, ok := gno.BaseOf(v.T).(*gno.PointerType)
+	if !ok {
+		return "json: Unmarshal(non-pointer " + v.T.String() + ")"
+	}
+	if v.V == nil {
+		return "json: Unmarshal(nil " + v.T.String() + ")"
+	}
+	jv, err := parse(data)
+	if err != nil {
+		return err.Error()
+	}
+	d := &decoder{m: m, fields: fieldCache{}}
+	pv := gno.FillValueTV(m.Store, &v).V.(gno.PointerValue)
+	d.decode(pv, pt.Elt, jv)
+	if d.err != "" {
+		return d.err
+	}
+	return ""
+}
+
+func X_validate(m *gno.Machine, data []byte) (int64, string) {
+	m.IncrCPU(int64(len(data)) * cpuPerByteScan)
+	var buf bytes.Buffer
+	return syntaxError(json.Compact(&buf, data))
+}
+
+func X_indentJSON(m *gno.Machine, src []byte, prefix, indent string) ([]byte, int64, string) {
+	m.IncrCPU(int64(len(src)) * cpuPerByteScan)
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, src, prefix, indent); err != nil {
+		offset, msg := syntaxError(err)
+		return nil, offset, msg
+	}
+	m.IncrCPU(int64(buf.Len()) * cpuPerByteScan)
+	return buf.Bytes(), 0, ""
+}
+
+func X_compactJSON(m *gno.Machine, src []byte) ([]byte, int64, string) {
+	m.IncrCPU(int64(len(src)) * cpuPerByteScan)
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, src); err != nil {
+		offset, msg := syntaxError(err)
+		return nil, offset, msg
+	}
+	return buf.Bytes(), 0, ""
+}
+
+func syntaxError(err error) (int64, string) {
+	if err == nil {
+		return 0, ""
+	}
+	var serr *json.SyntaxError
+	if errors.As(err, &serr) {
+		return serr.Offset, serr.Error()
+	}
+	return 0, err.Error()
+}
+
+// ----------------------------------------
+// Fields
+
+// field is a field of a struct which is encoded and decoded, possibly
+// promoted from an embedded struct.
+type field struct {
+	name      string
+	index     []int // of the field in the struct, and in the embedded ones.
+	typ       gno.Type
+	tagged    bool // whether name comes from the tag.
+	omitEmpty bool
+	quoted    bool
+}
+
+// fieldCache caches the fields of the struct types, during a call.
+type fieldCache map[*gno.StructType][]field
+
+func (c fieldCache) get(st *gno.StructType) []field {
+	fields, ok := c[st]
+	if !ok {
+		fields = typeFields(st)
+		c[st] = fields
+	}
+	return fields
+}
+
+// typeFields returns the fields of st to encode and decode, in the order of
+// their index, applying the rules of Go for promoted fields: a name at a
+// shallower depth hides the same name at deeper ones, and a name at a same
+// depth is kept only if it is tagged and the others are not.
+func typeFields(st *gno.StructType) []field {
+	type level struct {
+		st    *gno.StructType
+		index []int
+	}
+	var fields []field
+	hidden := map[string]bool{}
+	visited := map[*gno.StructType]bool{}
+	for current := []level{{st: st}}; len(current) > 0; {
+		var next []level
+		var found []field
+		for _, l := range current {
+			if visited[l.st] {
+				continue
+			}
+			visited[l.st] = true
+			for i, ft := range l.st.Fields {
+				tag := reflect.StructTag(ft.Tag).Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				if !isValidTag(name) {
+					name = ""
+				}
+				index := append(slices.Clone(l.index), i)
+				if ft.Embedded {
+					et := ft.Type
+					if pt, ok := gno.BaseOf(et).(*gno.PointerType); ok {
+						et = pt.Elt
+					}
+					if est, ok := gno.BaseOf(et).(*gno.StructType); ok && name == "" {
+						// promote the fields of an embedded struct.
+						next = append(next, level{st: est, index: index})
+						continue
+					}
+				}
+				if !isExported(string(ft.Name)) {
+					continue
+				}
+				f := field{
+					name:      name,
+					index:     index,
+					typ:       ft.Type,
+					tagged:    name != "",
+					omitEmpty: hasOption(opts, "omitempty"),
+				}
+				if f.name == "" {
+					f.name = string(ft.Name)
+				}
+				if hasOption(opts, "string") {
+					switch gno.BaseOf(ft.Type).Kind() {
+					case gno.BoolKind, gno.StringKind,
+						gno.IntKind, gno.Int8Kind, gno.Int16Kind, gno.Int32Kind, gno.Int64Kind,
+						gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind,
+						gno.Float32Kind, gno.Float64Kind:
+						f.quoted = true
+					}
+				}
+				found = append(found, f)
+			}
+		}
+
+		// keep the dominant field of each name found at this depth.
+		byName := map[string][]field{}
+		var names []string
+		for _, f := range found {
+			if _, ok := byName[f.name]; !ok {
+				names = append(names, f.name)
+			}
+			byName[f.name] = append(byName[f.name], f)
+		}
+		for _, name := range names {
+			if hidden[name] {
+				continue
+			}
+			hidden[name] = true
+			fs := byName[name]
+			if len(fs) == 1 {
+				fields = append(fields, fs[0])
+				continue
+			}
+			var tagged []field
+			for _, f := range fs {
+				if f.tagged {
+					tagged = append(tagged, f)
+				}
+			}
+			if len(tagged) == 1 {
+				fields = append(fields, tagged[0])
+			}
+		}
+		current = next
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return slices.Compare(fields[i].index, fields[j].index) < 0
+	})
+	return fields
+}
+
+func isExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but
+			// otherwise any punctuation chars are allowed
+			// in a tag name.
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ----------------------------------------
+// Encoding
+
+type encoder struct {
+	m       *gno.Machine
+	store   gno.Store
+	fields  fieldCache
+	buf     bytes.Buffer
+	charged int // bytes of buf already charged.
+	depth   int
+}
+
+// jsonError is an error of encoding, which is recovered by marshal.
+type jsonError struct{ error }
+
+func (e *encoder) marshal(tv gno.TypedValue) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if je, ok := r.(jsonError); ok {
+				err = je.error
+			} else {
+				panic(r)
+			}
+		}
+	}()
+	e.encode(tv, false)
+	return nil
+}
+
+func unsupportedType(t gno.Type) jsonError {
+	return jsonError{fmt.Errorf("json: unsupported type: %s", t.String())}
+}
+
+// charge charges the bytes written since the last charge, so that encoding
+// runs out of gas while writing, rather than after building the output.
+func (e *encoder) charge() {
+	if n := e.buf.Len() - e.charged; n > 0 {
+		e.m.IncrCPU(int64(n) * cpuPerByteCodec)
+		e.charged = e.buf.Len()
+	}
+}
+
+func (e *encoder) encode(tv gno.TypedValue, quoted bool) {
+	e.charge()
+	if tv.T == nil {
+		e.buf.WriteString("null")
+		return
+	}
+	gno.FillValueTV(e.store, &tv)
+	bt := gno.BaseOf(tv.T)
+	switch bt.(type) {
+	case *gno.PointerType, *gno.SliceType, *gno.MapType:
+		// only these can be cyclic, including through interfaces.
+		if e.depth++; e.depth > maxDepth {
+			panic(jsonError{fmt.Errorf("json: unsupported value: encountered a cycle via %s", tv.T.String())})
+		}
+		defer func() { e.depth-- }()
+	}
+	switch bt := bt.(type) {
+	case gno.PrimitiveType:
+		e.encodePrimitive(tv, quoted)
+	case *gno.ArrayType:
+		av := tv.V.(*gno.ArrayValue)
+		e.buf.WriteByte('[')
+		for i := 0; i < bt.Len; i++ {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.encode(av.GetPointerAtIndexInt2(e.store, i, bt.Elt).Deref(), false)
+		}
+		e.buf.WriteByte(']')
+	case *gno.SliceType:
+		if tv.V == nil {
+			e.buf.WriteString("null")
+			return
+		}
+		sv := tv.V.(*gno.SliceValue)
+		if bt.Elt.Kind() == gno.Uint8Kind {
+			av := sv.GetBase(e.store)
+			var bz []byte
+			if av.Data != nil {
+				bz = av.Data[sv.Offset : sv.Offset+sv.Length]
+			} else {
+				bz = make([]byte, sv.Length)
+				for i := range bz {
+					bz[i] = av.List[sv.Offset+i].GetUint8()
+				}
+			}
+			e.buf.WriteByte('"')
+			e.buf.WriteString(base64.StdEncoding.EncodeToString(bz))
+			e.buf.WriteByte('"')
+			return
+		}
+		e.buf.WriteByte('[')
+		for i := 0; i < sv.Length; i++ {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.encode(sv.GetPointerAtIndexInt2(e.store, i, bt.Elt).Deref(), false)
+		}
+		e.buf.WriteByte(']')
+	case *gno.PointerType:
+		if tv.V == nil {
+			e.buf.WriteString("null")
+			return
+		}
+		e.encode(tv.V.(gno.PointerValue).Deref(), quoted)
+	case *gno.StructType:
+		e.buf.WriteByte('{')
+		first := true
+	FIELDS:
+		for _, f := range e.fields.get(bt) {
+			fv := tv
+			for _, i := range f.index {
+				if fv.T.Kind() == gno.PointerKind {
+					if fv.V == nil {
+						continue FIELDS // a field of a nil embedded pointer.
+					}
+					fv = gno.FillValueTV(e.store, &fv).V.(gno.PointerValue).Deref()
+				}
+				fv = *fv.V.(*gno.StructValue).GetPointerToInt(e.store, i).TV
+			}
+			if f.omitEmpty && isEmpty(fv) {
+				continue
+			}
+			if !first {
+				e.buf.WriteByte(',')
+			}
+			first = false
+			e.encodeString(f.name)
+			e.buf.WriteByte(':')
+			e.encode(fv, f.quoted)
+		}
+		e.buf.WriteByte('}')
+	case *gno.MapType:
+		switch bt.Key.Kind() {
+		case gno.StringKind,
+			gno.IntKind, gno.Int8Kind, gno.Int16Kind, gno.Int32Kind, gno.Int64Kind,
+			gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind:
+		default:
+			panic(unsupportedType(tv.T))
+		}
+		if tv.V == nil {
+			e.buf.WriteString("null")
+			return
+		}
+		type entry struct {
+			key   string
+			value *gno.TypedValue
+		}
+		var entries []entry
+		for item := tv.V.(*gno.MapValue).List.Head; item != nil; item = item.Next {
+			key := item.Key
+			var ks string
+			if key.T.Kind() == gno.StringKind {
+				ks = key.GetString()
+			} else if isUnsigned(key.T.Kind()) {
+				ks = strconv.FormatUint(getUint(&key), 10)
+			} else {
+				ks = strconv.FormatInt(getInt(&key), 10)
+			}
+			entries = append(entries, entry{ks, gno.FillValueTV(e.store, &item.Value)})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		e.buf.WriteByte('{')
+		for i, ent := range entries {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+			e.encodeString(ent.key)
+			e.buf.WriteByte(':')
+			e.encode(*ent.value, false)
+		}
+		e.buf.WriteByte('}')
+	default:
+		panic(unsupportedType(tv.T))
+	}
+}
+
+func (e *encoder) encodePrimitive(tv gno.TypedValue, quoted bool) {
+	if quoted && tv.T.Kind() != gno.StringKind {
+		e.buf.WriteByte('"')
+		defer e.buf.WriteByte('"')
+	}
+	switch kind := tv.T.Kind(); kind {
+	case gno.BoolKind:
+		e.buf.WriteString(strconv.FormatBool(tv.GetBool()))
+	case gno.StringKind:
+		if quoted {
+			b, _ := json.Marshal(tv.GetString())
+			e.encodeString(string(b))
+		} else {
+			e.encodeString(tv.GetString())
+		}
+	case gno.IntKind, gno.Int8Kind, gno.Int16Kind, gno.Int32Kind, gno.Int64Kind:
+		e.buf.WriteString(strconv.FormatInt(getInt(&tv), 10))
+	case gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind:
+		e.buf.WriteString(strconv.FormatUint(getUint(&tv), 10))
+	case gno.Float32Kind, gno.Float64Kind:
+		var f float64
+		var v any
+		if kind == gno.Float32Kind {
+			f32 := math.Float32frombits(tv.GetFloat32())
+			f, v = float64(f32), f32
+		} else {
+			f = math.Float64frombits(tv.GetFloat64())
+			v = f
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			panic(jsonError{fmt.Errorf("json: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, 64))})
+		}
+		// the formatting of Go, which depends on the size of the float.
+		b, _ := json.Marshal(v)
+		e.buf.Write(b)
+	default:
+		panic(unsupportedType(tv.T))
+	}
+}
+
+func (e *encoder) encodeString(s string) {
+	b, _ := json.Marshal(s)
+	e.buf.Write(b)
+}
+
+func isEmpty(tv gno.TypedValue) bool {
+	if tv.T == nil {
+		return true
+	}
+	switch bt := gno.BaseOf(tv.T).(type) {
+	case gno.PrimitiveType:
+		switch kind := bt.Kind(); {
+		case kind == gno.BoolKind:
+			return !tv.GetBool()
+		case kind == gno.StringKind:
+			return tv.GetString() == ""
+		case kind == gno.Float32Kind:
+			return math.Float32frombits(tv.GetFloat32()) == 0
+		case kind == gno.Float64Kind:
+			return math.Float64frombits(tv.GetFloat64()) == 0
+		case isUnsigned(kind):
+			return getUint(&tv) == 0
+		case isSigned(kind):
+			return getInt(&tv) == 0
+		}
+	case *gno.ArrayType:
+		return bt.Len == 0
+	case *gno.SliceType:
+		return tv.V == nil || tv.V.(*gno.SliceValue).GetLength() == 0
+	case *gno.MapType:
+		return tv.V == nil || tv.V.(*gno.MapValue).GetLength() == 0
+	case *gno.PointerType:
+		return tv.V == nil
+	}
+	return false
+}
+
+func isSigned(kind gno.Kind) bool {
+	switch kind {
+	case gno.IntKind, gno.Int8Kind, gno.Int16Kind, gno.Int32Kind, gno.Int64Kind:
+		return true
+	}
+	return false
+}
+
+func isUnsigned(kind gno.Kind) bool {
+	switch kind {
+	case gno.UintKind, gno.Uint8Kind, gno.Uint16Kind, gno.Uint32Kind, gno.Uint64Kind:
+		return true
+	}
+	return false
+}
+
+func getInt(tv *gno.TypedValue) int64 {
+	switch tv.T.Kind() {
+	case gno.IntKind:
+		return tv.GetInt()
+	case gno.Int8Kind:
+		return int64(tv.GetInt8())
+	case gno.Int16Kind:
+		return int64(tv.GetInt16())
+	case gno.Int32Kind:
+		return int64(tv.GetInt32())
+	default:
+		return tv.GetInt64()
+	}
+}
+
+func getUint(tv *gno.TypedValue) uint64 {
+	switch tv.T.Kind() {
+	case gno.UintKind:
+		return tv.GetUint()
+	case gno.Uint8Kind:
+		return uint64(tv.GetUint8())
+	case gno.Uint16Kind:
+		return uint64(tv.GetUint16())
+	case gno.Uint32Kind:
+		return uint64(tv.GetUint32())
+	default:
+		return tv.GetUint64()
+	}
+}
+
+// ----------------------------------------
+// Decoding
+
+// object is a decoded JSON object, which keeps the order of its keys, so
+// that the entries of maps are inserted in the order of the input.
+type object struct {
+	keys   []string
+	values []any
+}
+
+// parse parses data into nil, bool, json.Number, string, []any and *object
+// values.
+func parse(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	v, err := parseValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
+
+func parseValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			v, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		_, err := dec.Token()
+		return list, err
+	case json.Delim('{'):
+		obj := &object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := parseValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.keys = append(obj.keys, key.(string))
+			obj.values = append(obj.values, v)
+		}
+		_, err := dec.Token()
+		return obj, err
+	default:
+		return tok, nil
+	}
+}
+
+type decoder struct {
+	m      *gno.Machine
+	fields fieldCache
+	err    string // the first error of type, if any.
+
+	// the context of the value being decoded, for the errors.
+	structName string
+	fieldPath  []string
+}
+
+// set assigns tv to the value pv points to, as if in Gno.
+func (d *decoder) set(pv gno.PointerValue, tv gno.TypedValue) {
+	pv.Assign2(d.m.Alloc, d.m.Store, d.m.Realm, tv, true)
+}
+
+// typeError records an error of type, unless there is one already.
+func (d *decoder) typeError(what string, t gno.Type) {
+	if d.err != "" {
+		return
+	}
+	typ := "interface {}"
+	if t != nil {
+		typ = t.String()
+	}
+	if d.structName != "" && len(d.fieldPath) > 0 {
+		d.err = fmt.Sprintf("json: cannot unmarshal %s into Go struct field %s.%s of type %s",
+			what, d.structName, strings.Join(d.fieldPath, "."), typ)
+	} else {
+		d.err = fmt.Sprintf("json: cannot unmarshal %s into Go value of type %s", what, typ)
+	}
+}
+
+func kindOf(jv any) string {
+	switch jv.(type) {
+	case bool:
+		return "bool"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// newValue allocates a value of type t, like new(t).
+func (d *decoder) newValue(t gno.Type) gno.PointerValue {
+	hi := d.m.Alloc.NewHeapItem(gno.DefaultTypedValue(d.m.Alloc, t))
+	return gno.PointerValue{TV: &hi.Value, Base: hi, Index: 0}
+}
+
+// decode decodes jv into the value of type t pv points to.
+func (d *decoder) decode(pv gno.PointerValue, t gno.Type, jv any) {
+	if t == nil {
+		// a pointer to a nil interface has no element type: pick the
+		// concrete value, like for an empty interface.
+		d.set(pv, d.generic(jv))
+		return
+	}
+	if jv == nil {
+		// null only sets the values which can be nil.
+		switch gno.BaseOf(t).(type) {
+		case *gno.PointerType, *gno.SliceType, *gno.MapType, *gno.InterfaceType:
+			d.set(pv, gno.DefaultTypedValue(d.m.Alloc, t))
+		}
+		return
+	}
+	bt := gno.BaseOf(t)
+	if _, ok := bt.(gno.PrimitiveType); ok {
+		d.decodePrimitive(pv, t, jv)
+		return
+	}
+	gno.FillValueTV(d.m.Store, pv.TV)
+	switch bt := bt.(type) {
+	case *gno.PointerType:
+		if pv.TV.V == nil {
+			d.set(pv, gno.TypedValue{T: t, V: d.newValue(bt.Elt)})
+		}
+		d.decode(pv.TV.V.(gno.PointerValue), bt.Elt, jv)
+	case *gno.InterfaceType:
+		if cur := pv.TV; cur.T != nil && cur.T.Kind() == gno.PointerKind && cur.V != nil {
+			// decode into the value the interface points to.
+			d.decode(cur.V.(gno.PointerValue), gno.BaseOf(cur.T).(*gno.PointerType).Elt, jv)
+			return
+		}
+		if len(bt.Methods) > 0 {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		d.set(pv, d.generic(jv))
+	case *gno.ArrayType:
+		list, ok := jv.([]any)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		av := pv.TV.V.(*gno.ArrayValue)
+		for i := 0; i < bt.Len; i++ {
+			epv := av.GetPointerAtIndexInt2(d.m.Store, i, bt.Elt)
+			if i < len(list) {
+				d.decode(epv, bt.Elt, list[i])
+			} else {
+				d.set(epv, gno.DefaultTypedValue(d.m.Alloc, bt.Elt))
+			}
+		}
+	case *gno.SliceType:
+		if s, ok := jv.(string); ok && bt.Elt.Kind() == gno.Uint8Kind {
+			bz, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				if d.err == "" {
+					d.err = err.Error()
+				}
+				return
+			}
+			d.set(pv, gno.TypedValue{T: t, V: d.m.Alloc.NewSliceFromData(bz)})
+			return
+		}
+		list, ok := jv.([]any)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		av := d.m.Alloc.NewListArray(len(list))
+		for i := range av.List {
+			av.List[i] = gno.DefaultTypedValue(d.m.Alloc, bt.Elt)
+			d.decode(av.GetPointerAtIndexInt2(d.m.Store, i, bt.Elt), bt.Elt, list[i])
+		}
+		d.set(pv, gno.TypedValue{T: t, V: d.m.Alloc.NewSlice(av, 0, len(list), len(list))})
+	case *gno.StructType:
+		obj, ok := jv.(*object)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		d.decodeStruct(pv, t, bt, obj)
+	case *gno.MapType:
+		obj, ok := jv.(*object)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		d.decodeMap(pv, t, bt, obj)
+	default:
+		d.typeError(kindOf(jv), t)
+	}
+}
+
+func (d *decoder) decodeStruct(pv gno.PointerValue, t gno.Type, st *gno.StructType, obj *object) {
+	fields := d.fields.get(st)
+	structName, fieldPath := d.structName, d.fieldPath
+	defer func() { d.structName, d.fieldPath = structName, fieldPath }()
+	if dt, ok := t.(*gno.DeclaredType); ok {
+		d.structName = string(dt.Name)
+	} else {
+		d.structName = t.String()
+	}
+
+	for i, key := range obj.keys {
+		var f *field
+		for j := range fields {
+			if fields[j].name == key {
+				f = &fields[j]
+				break
+			}
+		}
+		if f == nil {
+			for j := range fields {
+				if strings.EqualFold(fields[j].name, key) {
+					f = &fields[j]
+					break
+				}
+			}
+		}
+		if f == nil {
+			continue // unknown keys are ignored.
+		}
+
+		// find the field, allocating the nil embedded pointers on the way.
+		fpv := pv
+		ftyp := t
+		for _, idx := range f.index {
+			if pt, ok := gno.BaseOf(ftyp).(*gno.PointerType); ok {
+				if gno.FillValueTV(d.m.Store, fpv.TV).V == nil {
+					d.set(fpv, gno.TypedValue{T: ftyp, V: d.newValue(pt.Elt)})
+				}
+				fpv = fpv.TV.V.(gno.PointerValue)
+				ftyp = pt.Elt
+			}
+			sv := gno.FillValueTV(d.m.Store, fpv.TV).V.(*gno.StructValue)
+			fpv = sv.GetPointerToInt(d.m.Store, idx)
+			ftyp = gno.BaseOf(ftyp).(*gno.StructType).Fields[idx].Type
+		}
+
+		d.fieldPath = append(fieldPath[:len(fieldPath):len(fieldPath)], f.name)
+		value := obj.values[i]
+		if f.quoted && value != nil {
+			s, ok := value.(string)
+			if ok && f.typ.Kind() == gno.StringKind {
+				var inner string
+				if err := json.Unmarshal([]byte(s), &inner); err != nil {
+					ok = false
+				}
+				value = inner
+			} else if ok {
+				var err error
+				if value, err = parse([]byte(s)); err != nil {
+					ok = false
+				}
+			}
+			if !ok {
+				if d.err == "" {
+					d.err = fmt.Sprintf("json: invalid use of ,string struct tag, trying to unmarshal %s into %s",
+						quoteValue(obj.values[i]), f.typ.String())
+				}
+				continue
+			}
+		}
+		d.decode(fpv, f.typ, value)
+	}
+}
+
+func quoteValue(jv any) string {
+	b, _ := json.Marshal(jv)
+	if len(b) == 0 {
+		return kindOf(jv)
+	}
+	return strconv.Quote(string(b))
+}
+
+func (d *decoder) decodeMap(pv gno.PointerValue, t gno.Type, mt *gno.MapType, obj *object) {
+	kind := mt.Key.Kind()
+	if kind != gno.StringKind && !isSigned(kind) && !isUnsigned(kind) {
+		d.typeError("object", t)
+		return
+	}
+	if pv.TV.V == nil {
+		d.set(pv, gno.TypedValue{T: t, V: d.m.Alloc.NewMap(len(obj.keys))})
+	}
+	mv := pv.TV.V.(*gno.MapValue)
+	for i, key := range obj.keys {
+		ktv := gno.TypedValue{T: mt.Key}
+		switch {
+		case kind == gno.StringKind:
+			ktv.V = d.m.Alloc.NewString(key)
+		case isSigned(kind):
+			n, err := strconv.ParseInt(key, 10, bitSize(kind))
+			if err != nil {
+				d.typeError("number "+key, mt.Key)
+				continue
+			}
+			setInt(&ktv, n)
+		default:
+			n, err := strconv.ParseUint(key, 10, bitSize(kind))
+			if err != nil {
+				d.typeError("number "+key, mt.Key)
+				continue
+			}
+			setUint(&ktv, n)
+		}
+		// decode into a new value, like Go.
+		elem := d.newValue(mt.Value)
+		d.decode(elem, mt.Value, obj.values[i])
+		d.set(mv.GetPointerForKey(d.m.Alloc, d.m.Store, &ktv), *elem.TV)
+	}
+}
+
+func (d *decoder) decodePrimitive(pv gno.PointerValue, t gno.Type, jv any) {
+	tv := gno.TypedValue{T: t}
+	switch kind := t.Kind(); {
+	case kind == gno.BoolKind:
+		b, ok := jv.(bool)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		tv.SetBool(b)
+	case kind == gno.StringKind:
+		s, ok := jv.(string)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		tv.V = d.m.Alloc.NewString(s)
+	case isSigned(kind), isUnsigned(kind), kind == gno.Float32Kind, kind == gno.Float64Kind:
+		num, ok := jv.(json.Number)
+		if !ok {
+			d.typeError(kindOf(jv), t)
+			return
+		}
+		s := string(num)
+		switch {
+		case isSigned(kind):
+			n, err := strconv.ParseInt(s, 10, bitSize(kind))
+			if err != nil {
+				d.typeError("number "+s, t)
+				return
+			}
+			setInt(&tv, n)
+		case isUnsigned(kind):
+			n, err := strconv.ParseUint(s, 10, bitSize(kind))
+			if err != nil {
+				d.typeError("number "+s, t)
+				return
+			}
+			setUint(&tv, n)
+		case kind == gno.Float32Kind:
+			f, err := strconv.ParseFloat(s, 32)
+			if err != nil {
+				d.typeError("number "+s, t)
+				return
+			}
+			tv.SetFloat32(math.Float32bits(float32(f)))
+		default:
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				d.typeError("number "+s, t)
+				return
+			}
+			tv.SetFloat64(math.Float64bits(f))
+		}
+	default:
+		d.typeError(kindOf(jv), t)
+		return
+	}
+	d.set(pv, tv)
+}
+
+// generic returns jv as the value of an empty interface: a bool, float64,
+// string, nil, []any or map[string]any.
+func (d *decoder) generic(jv any) gno.TypedValue {
+	alloc := d.m.Alloc
+	switch jv := jv.(type) {
+	case nil:
+		return gno.TypedValue{}
+	case bool:
+		tv := gno.TypedValue{T: gno.BoolType}
+		tv.SetBool(jv)
+		return tv
+	case json.Number:
+		f, err := strconv.ParseFloat(string(jv), 64)
+		if err != nil {
+			d.typeError("number "+string(jv), gno.Float64Type)
+		}
+		tv := gno.TypedValue{T: gno.Float64Type}
+		tv.SetFloat64(math.Float64bits(f))
+		return tv
+	case string:
+		return gno.TypedValue{T: gno.StringType, V: alloc.NewString(jv)}
+	case []any:
+		av := alloc.NewListArray(len(jv))
+		for i, v := range jv {
+			av.List[i] = d.generic(v)
+		}
+		return gno.TypedValue{
+			T: alloc.NewType(&gno.SliceType{Elt: &gno.InterfaceType{}}),
+			V: alloc.NewSlice(av, 0, len(jv), len(jv)),
+		}
+	case *object:
+		mv := alloc.NewMap(len(jv.keys))
+		for i, key := range jv.keys {
+			ktv := gno.TypedValue{T: gno.StringType, V: alloc.NewString(key)}
+			*mv.GetPointerForKey(alloc, d.m.Store, &ktv).TV = d.generic(jv.values[i])
+		}
+		return gno.TypedValue{
+			T: alloc.NewType(&gno.MapType{Key: gno.StringType, Value: &gno.InterfaceType{}}),
+			V: mv,
+		}
+	default:
+		panic("unexpected json value")
+	}
+}
+
+func bitSize(kind gno.Kind) int {
+	switch kind {
+	case gno.Int8Kind, gno.Uint8Kind:
+		return 8
+	case gno.Int16Kind, gno.Uint16Kind:
+		return 16
+	case gno.Int32Kind, gno.Uint32Kind:
+		return 32
+	default:
+		return 64
+	}
+}
+
+func setInt(tv *gno.TypedValue, n int64) {
+	switch tv.T.Kind() {
+	case gno.IntKind:
+		tv.SetInt(n)
+	case gno.Int8Kind:
+		tv.SetInt8(int8(n))
+	case gno.Int16Kind:
+		tv.SetInt16(int16(n))
+	case gno.Int32Kind:
+		tv.SetInt32(int32(n))
+	default:
+		tv.SetInt64(n)
+	}
+}
+
+func setUint(tv *gno.TypedValue, n uint64) {
+	switch tv.T.Kind() {
+	case gno.UintKind:
+		tv.SetUint(n)
+	case gno.Uint8Kind:
+		tv.SetUint8(uint8(n))
+	case gno.Uint16Kind:
+		tv.SetUint16(uint16(n))
+	case gno.Uint32Kind:
+		tv.SetUint32(uint32(n))
+	default:
+		tv.SetUint64(n)
+	}
+}
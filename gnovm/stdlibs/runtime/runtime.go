@@ -9,3 +9,11 @@ func GC(m *gno.Machine) {
 func MemStats(m *gno.Machine) string {
 	return m.Alloc.MemStats()
 }
+
+func Gosched(m *gno.Machine) {
+	m.Gosched()
+}
+
+func NumGoroutine(m *gno.Machine) int {
+	return m.NumGoroutine()
+}
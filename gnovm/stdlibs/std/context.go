@@ -19,6 +19,7 @@ type ExecContext struct {
 	Banker          BankerInterface
 	Params          ParamsInterface
 	EventLogger     *sdk.EventLogger
+	Scheduler       SchedulerInterface // may be nil, if unsupported.
 }
 
 // GetContext returns the execution context.
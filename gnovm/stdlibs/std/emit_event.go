@@ -55,6 +55,18 @@ type GnoEvent struct {
 
 func (e GnoEvent) AssertABCIEvent() {}
 
+// QueryAttributes returns the attributes of the event, keyed by
+// <type>.<key>, along with the path of the emitting realm as <type>.pkg_path.
+func (e GnoEvent) QueryAttributes() map[string][]string {
+	attrs := make(map[string][]string, len(e.Attributes)+1)
+	attrs[e.Type+".pkg_path"] = []string{e.PkgPath}
+	for _, attr := range e.Attributes {
+		key := e.Type + "." + attr.Key
+		attrs[key] = append(attrs[key], attr.Value)
+	}
+	return attrs
+}
+
 // XXX rename to std/events.Attribute?
 type GnoEventAttribute struct {
 	Key   string `json:"key"`
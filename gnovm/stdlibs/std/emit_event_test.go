@@ -201,3 +201,23 @@ func TestEmit_MultipleEvents(t *testing.T) {
 
 	assert.Equal(t, string(expectRes), string(res))
 }
+
+func TestGnoEvent_QueryAttributes(t *testing.T) {
+	t.Parallel()
+
+	evt := GnoEvent{
+		Type:    "Transfer",
+		PkgPath: pkgPath,
+		Attributes: []GnoEventAttribute{
+			{Key: "to", Value: "g1alice"},
+			{Key: "to", Value: "g1bob"},
+			{Key: "amount", Value: "10"},
+		},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"Transfer.pkg_path": {pkgPath},
+		"Transfer.to":       {"g1alice", "g1bob"},
+		"Transfer.amount":   {"10"},
+	}, evt.QueryAttributes())
+}
@@ -0,0 +1,64 @@
+package std
+
+import (
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// SchedulerInterface is the interface through which realms register calls to
+// be executed later by the chain; see std.ScheduleCallAtHeight.
+type SchedulerInterface interface {
+	// ScheduleCall registers the call to fn of the realm pkgPath, due at the
+	// given height, or at the given time if height is zero.
+	ScheduleCall(pkgPath string, height, timestamp int64, fn string, args []string) (id uint64, err error)
+	// CancelCall cancels the pending call with the given id, if it was
+	// scheduled by the realm pkgPath.
+	CancelCall(pkgPath string, id uint64) bool
+}
+
+func X_scheduleCall(m *gno.Machine, height, timestamp int64, fn string, args []string) uint64 {
+	switch {
+	case height < 0 || timestamp < 0:
+		m.Panic(typedString("invalid scheduled call height or time"))
+		return 0
+	case height == 0 && timestamp == 0:
+		m.Panic(typedString("scheduled call height or time is required"))
+		return 0
+	case fn == "":
+		m.Panic(typedString("scheduled call function is required"))
+		return 0
+	}
+	rlmPath, ok := schedulerRealm(m)
+	if !ok {
+		return 0
+	}
+
+	id, err := GetContext(m).Scheduler.ScheduleCall(rlmPath, height, timestamp, fn, args)
+	if err != nil {
+		m.Panic(typedString(err.Error()))
+		return 0
+	}
+	return id
+}
+
+func X_cancelScheduledCall(m *gno.Machine, id uint64) bool {
+	rlmPath, ok := schedulerRealm(m)
+	if !ok {
+		return false
+	}
+	return GetContext(m).Scheduler.CancelCall(rlmPath, id)
+}
+
+// schedulerRealm returns the path of the current realm, or panics if it can't
+// schedule calls: only persisted realms can.
+func schedulerRealm(m *gno.Machine) (rlmPath string, ok bool) {
+	if GetContext(m).Scheduler == nil {
+		m.Panic(typedString("scheduled calls are not supported"))
+		return "", false
+	}
+	_, rlmPath = currentRealm(m)
+	if _, isRun := gno.IsGnoRunPath(rlmPath); !gno.IsRealmPath(rlmPath) || isRun {
+		m.Panic(typedString("scheduled calls can only be made by realms"))
+		return "", false
+	}
+	return rlmPath, true
+}
@@ -16,3 +16,7 @@ func X_matchString(pat, str string) (bool, string) {
 func X_recoverWithStacktrace() (gnolang.TypedValue, string) {
 	panic("only available in testing stdlibs")
 }
+
+func X_benchStats() (gas, allocs, bytes int64) {
+	panic("only available in testing stdlibs")
+}
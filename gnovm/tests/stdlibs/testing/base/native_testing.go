@@ -26,3 +26,11 @@ func X_recoverWithStacktrace(m *gnolang.Machine) (gnolang.TypedValue, string) {
 	}
 	return exception.Value, exception.Stacktrace.String()
 }
+
+func X_benchStats(m *gnolang.Machine) (gas, allocs, bytes int64) {
+	if m.GasMeter != nil {
+		gas = m.GasMeter.GasConsumed()
+	}
+	allocs, bytes = m.Alloc.Totals()
+	return
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/blockchain"
 	"github.com/gnolang/gno/tm2/pkg/bft/consensus"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/consensus/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/evidence"
 	"github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	btypes "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/bitarray"
@@ -24,6 +25,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
 )
@@ -49,6 +51,7 @@ func execGen(_ context.Context, _ []string) error {
 		consensus.Package,
 		ctypes.Package,
 		mempool.Package,
+		evidence.Package,
 		ed25519.Package,
 		blockchain.Package,
 		hd.Package,
@@ -56,6 +59,7 @@ func execGen(_ context.Context, _ []string) error {
 		std.Package,
 		sdk.Package,
 		bank.Package,
+		auth.Package,
 		vm.Package,
 		gno.Package,
 		tests.Package,
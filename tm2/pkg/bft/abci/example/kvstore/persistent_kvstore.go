@@ -10,6 +10,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/bft/abci/example/errors"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	tmtypes "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/db"
 	_ "github.com/gnolang/gno/tm2/pkg/db/goleveldb"
@@ -121,7 +122,7 @@ func (app *PersistentKVStoreApplication) BeginBlock(req abci.RequestBeginBlock)
 	// reset valset changes
 	app.ValSetChanges = make([]abci.ValidatorUpdate, 0)
 
-	/* REMOVE
+	// punish validators who committed a violation
 	for _, vio := range req.Violations {
 		if _, ok := vio.Evidence.(*tmtypes.DuplicateVoteEvidence); ok {
 			for _, val := range vio.Validators {
@@ -137,7 +138,6 @@ func (app *PersistentKVStoreApplication) BeginBlock(req abci.RequestBeginBlock)
 			}
 		}
 	}
-	*/
 	return abci.ResponseBeginBlock{}
 }
 
@@ -146,6 +146,22 @@ func (app *PersistentKVStoreApplication) EndBlock(req abci.RequestEndBlock) abci
 	return abci.ResponseEndBlock{ValidatorUpdates: app.ValSetChanges}
 }
 
+func (app *PersistentKVStoreApplication) ListSnapshots(req abci.RequestListSnapshots) abci.ResponseListSnapshots {
+	return app.app.ListSnapshots(req)
+}
+
+func (app *PersistentKVStoreApplication) OfferSnapshot(req abci.RequestOfferSnapshot) abci.ResponseOfferSnapshot {
+	return app.app.OfferSnapshot(req)
+}
+
+func (app *PersistentKVStoreApplication) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) abci.ResponseLoadSnapshotChunk {
+	return app.app.LoadSnapshotChunk(req)
+}
+
+func (app *PersistentKVStoreApplication) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk {
+	return app.app.ApplySnapshotChunk(req)
+}
+
 // ---------------------------------------------
 // update validators
 
@@ -37,6 +37,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		RequestDeliverTx{},
 		RequestEndBlock{},
 		RequestCommit{},
+		RequestListSnapshots{},
+		RequestOfferSnapshot{},
+		RequestLoadSnapshotChunk{},
+		RequestApplySnapshotChunk{},
 
 		// response types
 		ResponseBase{},
@@ -52,19 +56,25 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ResponseDeliverTx{},
 		ResponseEndBlock{},
 		ResponseCommit{},
+		ResponseListSnapshots{},
+		ResponseOfferSnapshot{},
+		ResponseLoadSnapshotChunk{},
+		ResponseApplySnapshotChunk{},
 
 		// error types
 		StringError(""),
 
 		// misc types
 		ConsensusParams{},
+		Snapshot{},
 		BlockParams{},
 		ValidatorParams{},
+		EvidenceParams{},
 		ValidatorUpdate{},
 		LastCommitInfo{},
 		VoteInfo{},
-		// Validator{},
-		// Violation{},
+		Validator{},
+		Violation{},
 
 		// events
 		EventString(""),
@@ -32,6 +32,9 @@ func (params ConsensusParams) Update(params2 ConsensusParams) ConsensusParams {
 	if params2.Validator != nil {
 		res.Validator = amino.DeepCopy(params2.Validator).(*ValidatorParams)
 	}
+	if params2.Evidence != nil {
+		res.Evidence = amino.DeepCopy(params2.Evidence).(*EvidenceParams)
+	}
 
 	return res
 }
@@ -61,7 +61,7 @@ type RequestBeginBlock struct {
 	Hash           []byte
 	Header         Header
 	LastCommitInfo *LastCommitInfo
-	// Violations     []Violation
+	Violations     []Violation
 }
 
 type CheckTxType int
@@ -91,6 +91,33 @@ type RequestCommit struct {
 	RequestBase
 }
 
+type RequestListSnapshots struct {
+	RequestBase
+}
+
+// RequestOfferSnapshot offers a snapshot to the application, before
+// restoring it. AppHash is the trusted app hash at the snapshot height,
+// verified with the light client.
+type RequestOfferSnapshot struct {
+	RequestBase
+	Snapshot *Snapshot
+	AppHash  []byte
+}
+
+type RequestLoadSnapshotChunk struct {
+	RequestBase
+	Height int64
+	Format uint32
+	Chunk  uint32
+}
+
+type RequestApplySnapshotChunk struct {
+	RequestBase
+	Index  uint32
+	Chunk  []byte
+	Sender string // the ID of the peer which sent the chunk
+}
+
 // ----------------------------------------
 // Response types
 
@@ -195,6 +222,33 @@ type ResponseEndBlock struct {
 
 type ResponseCommit struct {
 	ResponseBase
+	RetainHeight int64 // blocks below this height can be pruned, if non-zero
+}
+
+type ResponseListSnapshots struct {
+	ResponseBase
+	Snapshots []*Snapshot
+}
+
+// ResponseOfferSnapshot has an Error if the snapshot is rejected,
+// in which case another snapshot is offered.
+type ResponseOfferSnapshot struct {
+	ResponseBase
+}
+
+type ResponseLoadSnapshotChunk struct {
+	ResponseBase
+	Chunk []byte
+}
+
+// ResponseApplySnapshotChunk has an Error if the snapshot can't be restored,
+// in which case another snapshot is offered.
+// Chunks which failed to apply (ie. invalid ones) are fetched again, from
+// other peers than the rejected senders.
+type ResponseApplySnapshotChunk struct {
+	ResponseBase
+	RefetchChunks []uint32
+	RejectSenders []string
 }
 
 // ----------------------------------------
@@ -209,6 +263,16 @@ type Event interface {
 	AssertABCIEvent()
 }
 
+// AttributedEvent is an Event with attributes, by which the transactions and
+// blocks that emit it can be searched (see the events/query package).
+type AttributedEvent interface {
+	Event
+
+	// QueryAttributes returns the values of the attributes of the event,
+	// keyed by <event type>.<attribute key>.
+	QueryAttributes() map[string][]string
+}
+
 type Header interface {
 	GetChainID() string
 	GetHeight() int64
@@ -216,6 +280,10 @@ type Header interface {
 	AssertABCIHeader()
 }
 
+type Evidence interface {
+	AssertABCIEvidence()
+}
+
 // ----------------------------------------
 // Error types
 
@@ -245,6 +313,17 @@ func (err EventString) Event() string {
 type ConsensusParams struct {
 	Block     *BlockParams
 	Validator *ValidatorParams
+	Evidence  *EvidenceParams
+}
+
+// Snapshot is a snapshot of the application state, which nodes can restore
+// instead of replaying all the blocks (state sync).
+type Snapshot struct {
+	Height   int64  // the height at which the snapshot was taken
+	Format   uint32 // the application-specific snapshot format
+	Chunks   uint32 // the number of chunks in the snapshot
+	Hash     []byte // the hash of the snapshot, only used to compare snapshots
+	Metadata []byte // arbitrary application metadata, eg. the chunk hashes
 }
 
 type BlockParams struct {
@@ -259,6 +338,10 @@ type ValidatorParams struct {
 	PubKeyTypeURLs []string
 }
 
+type EvidenceParams struct {
+	MaxAge int64 // in blocks, must be > 0
+}
+
 type ValidatorUpdate struct {
 	Address crypto.Address
 	PubKey  crypto.PubKey
@@ -277,7 +360,6 @@ type VoteInfo struct {
 	SignedLastBlock bool
 }
 
-/*
 // unstable
 type Validator struct {
 	Address crypto.Address
@@ -287,10 +369,9 @@ type Validator struct {
 
 // unstable
 type Violation struct {
-	Evidence
-	Validators       []Validator
-	Height           int64
-	Time             time.Time
-	TotalVotingPower int64
+	Evidence         Evidence
+	Validators       []Validator // the validators who committed the violation
+	Height           int64       // height of the violation
+	Time             time.Time   // time of the block including the evidence
+	TotalVotingPower int64       // of the validator set at Height
 }
-*/
@@ -277,16 +277,17 @@ func (pool *BlockPool) MaxPeerHeight() int64 {
 	return pool.maxPeerHeight
 }
 
-// SetPeerHeight sets the peer's alleged blockchain height.
-func (pool *BlockPool) SetPeerHeight(peerID p2pTypes.ID, height int64) {
+// SetPeerRange sets the peer's alleged blockchain base and height.
+func (pool *BlockPool) SetPeerRange(peerID p2pTypes.ID, base int64, height int64) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
 	peer := pool.peers[peerID]
 	if peer != nil {
+		peer.base = base
 		peer.height = height
 	} else {
-		peer = newBPPeer(pool, peerID, height)
+		peer = newBPPeer(pool, peerID, base, height)
 		peer.setLogger(pool.Logger.With("peer", peerID))
 		pool.peers[peerID] = peer
 	}
@@ -339,9 +340,9 @@ func (pool *BlockPool) updateMaxPeerHeight() {
 	pool.maxPeerHeight = maxVal
 }
 
-// Pick an available peer with at least the given minHeight.
+// Pick an available peer with the block at the given height.
 // If no peers are available, returns nil.
-func (pool *BlockPool) pickIncrAvailablePeer(minHeight int64) *bpPeer {
+func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
@@ -353,7 +354,7 @@ func (pool *BlockPool) pickIncrAvailablePeer(minHeight int64) *bpPeer {
 		if peer.numPending >= maxPendingRequestsPerPeer {
 			continue
 		}
-		if peer.height < minHeight {
+		if height < peer.base || height > peer.height {
 			continue
 		}
 		peer.incrPending()
@@ -427,6 +428,7 @@ type bpPeer struct {
 	id          p2pTypes.ID
 	recvMonitor *flow.Monitor
 
+	base       int64
 	height     int64
 	numPending int32
 	timeout    *time.Timer
@@ -435,10 +437,11 @@ type bpPeer struct {
 	logger *slog.Logger
 }
 
-func newBPPeer(pool *BlockPool, peerID p2pTypes.ID, height int64) *bpPeer {
+func newBPPeer(pool *BlockPool, peerID p2pTypes.ID, base int64, height int64) *bpPeer {
 	peer := &bpPeer{
 		pool:       pool,
 		id:         peerID,
+		base:       base,
 		height:     height,
 		numPending: 0,
 		logger:     log.NewNoopLogger(),
@@ -94,7 +94,7 @@ func TestBlockPoolBasic(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, 1, peer.height)
 		}
 	}()
 
@@ -151,7 +151,7 @@ func TestBlockPoolTimeout(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, 1, peer.height)
 		}
 	}()
 
@@ -210,7 +210,7 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	// add peers
 	for peerID, peer := range peers {
-		pool.SetPeerHeight(peerID, peer.height)
+		pool.SetPeerRange(peerID, 1, peer.height)
 	}
 	assert.EqualValues(t, 10, pool.MaxPeerHeight())
 
@@ -228,3 +228,37 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	assert.EqualValues(t, 0, pool.MaxPeerHeight())
 }
+
+func TestBlockPoolPeerRange(t *testing.T) {
+	t.Parallel()
+
+	pool := NewBlockPool(1, make(chan BlockRequest, 10), make(chan peerError, 10))
+	pool.SetLogger(log.NewTestingLogger(t))
+
+	// The first peer pruned its older blocks
+	pool.SetPeerRange("pruned", 50, 100)
+	pool.SetPeerRange("archive", 1, 10)
+	assert.EqualValues(t, 100, pool.MaxPeerHeight())
+
+	testTable := []struct {
+		height   int64
+		expected p2pTypes.ID
+	}{
+		{5, "archive"},
+		{20, ""},
+		{60, "pruned"},
+		{101, ""},
+	}
+
+	for _, testCase := range testTable {
+		peer := pool.pickIncrAvailablePeer(testCase.height)
+		if testCase.expected == "" {
+			assert.Nil(t, peer, "height %d", testCase.height)
+
+			continue
+		}
+
+		require.NotNil(t, peer, "height %d", testCase.height)
+		assert.Equal(t, testCase.expected, peer.id)
+	}
+}
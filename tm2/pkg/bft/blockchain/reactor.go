@@ -126,6 +126,27 @@ func (bcR *BlockchainReactor) OnStart() error {
 	return nil
 }
 
+// SwitchToFastSync is called once the state is restored by state sync,
+// to start fast syncing the blocks from the restored height.
+func (bcR *BlockchainReactor) SwitchToFastSync(state sm.State) error {
+	bcR.Logger.Info("SwitchToFastSync", "height", state.LastBlockHeight)
+
+	bcR.fastSync = true
+	bcR.initialState = state
+
+	bcR.pool.mtx.Lock()
+	bcR.pool.height = state.LastBlockHeight + 1
+	bcR.pool.mtx.Unlock()
+
+	if err := bcR.pool.Start(); err != nil {
+		return err
+	}
+
+	go bcR.poolRoutine()
+
+	return nil
+}
+
 // OnStop implements cmn.Service.
 func (bcR *BlockchainReactor) OnStop() {
 	bcR.pool.Stop()
@@ -146,12 +167,20 @@ func (bcR *BlockchainReactor) GetChannels() []*p2p.ChannelDescriptor {
 
 // AddPeer implements Reactor by sending our state to peer.
 func (bcR *BlockchainReactor) AddPeer(peer p2p.PeerConn) {
-	msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{bcR.store.Height()})
+	msgBytes := amino.MustMarshalAny(bcR.statusResponse())
 	peer.Send(BlockchainChannel, msgBytes)
 	// it's OK if send fails. will try later in poolRoutine
 
 	// peer is added to the pool once we receive the first
-	// bcStatusResponseMessage from the peer and call pool.SetPeerHeight
+	// bcStatusResponseMessage from the peer and call pool.SetPeerRange
+}
+
+// statusResponse returns the range of blocks the node can serve
+func (bcR *BlockchainReactor) statusResponse() *bcStatusResponseMessage {
+	return &bcStatusResponseMessage{
+		Height: bcR.store.Height(),
+		Base:   bcR.store.Base(),
+	}
 }
 
 // RemovePeer implements Reactor by removing peer from the pool.
@@ -202,11 +231,11 @@ func (bcR *BlockchainReactor) Receive(chID byte, src p2p.PeerConn, msgBytes []by
 		bcR.pool.AddBlock(src.ID(), msg.Block, len(msgBytes))
 	case *bcStatusRequestMessage:
 		// Send peer our state.
-		msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{bcR.store.Height()})
+		msgBytes := amino.MustMarshalAny(bcR.statusResponse())
 		src.TrySend(BlockchainChannel, msgBytes)
 	case *bcStatusResponseMessage:
 		// Got a peer status. Unverified.
-		bcR.pool.SetPeerHeight(src.ID(), msg.Height)
+		bcR.pool.SetPeerRange(src.ID(), msg.Base, msg.Height)
 	default:
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 	}
@@ -455,6 +484,7 @@ func (m *bcStatusRequestMessage) String() string {
 
 type bcStatusResponseMessage struct {
 	Height int64
+	Base   int64 // the lowest height available, or 0 if unknown
 }
 
 // ValidateBasic performs basic validation.
@@ -462,9 +492,15 @@ func (m *bcStatusResponseMessage) ValidateBasic() error {
 	if m.Height < 0 {
 		return errors.New("negative height")
 	}
+	if m.Base < 0 {
+		return errors.New("negative base")
+	}
+	if m.Base > m.Height {
+		return fmt.Errorf("base %v is higher than height %v", m.Base, m.Height)
+	}
 	return nil
 }
 
 func (m *bcStatusResponseMessage) String() string {
-	return fmt.Sprintf("[bcStatusResponseMessage %v]", m.Height)
+	return fmt.Sprintf("[bcStatusResponseMessage %v:%v]", m.Base, m.Height)
 }
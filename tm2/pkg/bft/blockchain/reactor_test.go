@@ -384,12 +384,16 @@ func TestBcStatusResponseMessageValidateBasic(t *testing.T) {
 
 	testCases := []struct {
 		testName       string
+		responseBase   int64
 		responseHeight int64
 		expectErr      bool
 	}{
-		{"Valid Response Message", 0, false},
-		{"Valid Response Message", 1, false},
-		{"Invalid Response Message", -1, true},
+		{"Valid Response Message", 0, 0, false},
+		{"Valid Response Message", 0, 1, false},
+		{"Valid Response Message with base", 1, 1, false},
+		{"Invalid Response Message", 0, -1, true},
+		{"Invalid Response Message base", -1, 1, true},
+		{"Invalid Response Message base above height", 2, 1, true},
 	}
 
 	for _, tc := range testCases {
@@ -397,7 +401,7 @@ func TestBcStatusResponseMessageValidateBasic(t *testing.T) {
 		t.Run(tc.testName, func(t *testing.T) {
 			t.Parallel()
 
-			response := bcStatusResponseMessage{Height: tc.responseHeight}
+			response := bcStatusResponseMessage{Base: tc.responseBase, Height: tc.responseHeight}
 			assert.Equal(t, tc.expectErr, response.ValidateBasic() != nil, "Validate Basic had an unexpected result")
 		})
 	}
@@ -414,7 +418,7 @@ func makeTxs(height int64) (txs []types.Tx) {
 }
 
 func makeBlock(height int64, state sm.State, lastCommit *types.Commit) *types.Block {
-	block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, state.Validators.GetProposer().Address)
+	block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, nil, state.Validators.GetProposer().Address)
 	return block
 }
 
@@ -14,6 +14,7 @@ import (
 	mem "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	rpc "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	eventstore "github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/types"
+	ss "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
 	"github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
@@ -32,6 +33,7 @@ var (
 	errInvalidPrivValidatorListenAddress = errors.New("invalid PrivValidator listen address")
 	errInvalidProfListenAddress          = errors.New("invalid profiling server listen address")
 	errInvalidNodeKeyPath                = errors.New("invalid p2p node key path")
+	errInvalidRetainBlocks               = errors.New("invalid number of retained blocks")
 )
 
 const (
@@ -54,6 +56,7 @@ type Config struct {
 	P2P          *p2p.P2PConfig       `json:"p2p" toml:"p2p" comment:"##### peer to peer configuration options #####"`
 	Mempool      *mem.MempoolConfig   `json:"mempool" toml:"mempool" comment:"##### mempool configuration options #####"`
 	Consensus    *cns.ConsensusConfig `json:"consensus" toml:"consensus" comment:"##### consensus configuration options #####"`
+	StateSync    *ss.StateSyncConfig  `json:"statesync" toml:"statesync" comment:"##### state sync configuration options #####"`
 	TxEventStore *eventstore.Config   `json:"tx_event_store" toml:"tx_event_store" comment:"##### event store #####"`
 	Telemetry    *telemetry.Config    `json:"telemetry" toml:"telemetry" comment:"##### node telemetry #####"`
 	Application  *sdk.AppConfig       `json:"application" toml:"application" comment:"##### app settings #####"`
@@ -67,6 +70,7 @@ func DefaultConfig() *Config {
 		P2P:          p2p.DefaultP2PConfig(),
 		Mempool:      mem.DefaultMempoolConfig(),
 		Consensus:    cns.DefaultConsensusConfig(),
+		StateSync:    ss.DefaultStateSyncConfig(),
 		TxEventStore: eventstore.DefaultEventStoreConfig(),
 		Telemetry:    telemetry.DefaultTelemetryConfig(),
 		Application:  sdk.DefaultAppConfig(),
@@ -184,6 +188,7 @@ func TestConfig() *Config {
 		P2P:          testP2PConfig(),
 		Mempool:      mem.TestMempoolConfig(),
 		Consensus:    cns.TestConsensusConfig(),
+		StateSync:    ss.TestStateSyncConfig(),
 		TxEventStore: eventstore.DefaultEventStoreConfig(),
 		Telemetry:    telemetry.DefaultTelemetryConfig(),
 		Application:  sdk.DefaultAppConfig(),
@@ -242,6 +247,9 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [consensus] section")
 	}
+	if err := cfg.StateSync.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "Error in [statesync] section")
+	}
 	if err := cfg.Application.ValidateBasic(); err != nil {
 		return errors.Wrap(err, "Error in [application] section")
 	}
@@ -317,6 +325,11 @@ type BaseConfig struct {
 	// Database directory
 	DBPath string `toml:"db_dir" comment:"Database directory"`
 
+	// Number of recent blocks to retain, the older blocks are pruned.
+	// The application can retain more blocks, with the RetainHeight of Commit.
+	// 0 retains all the blocks (archive node)
+	RetainBlocks int64 `toml:"retain_blocks" comment:"Number of recent blocks to retain, the older blocks are pruned.\n The application can retain more blocks, with the RetainHeight of Commit.\n 0 retains all the blocks (archive node)"`
+
 	// Path to the JSON file containing the private key to use as a validator in the consensus protocol
 	PrivValidatorKey string `toml:"priv_validator_key_file" comment:"Path to the JSON file containing the private key to use as a validator in the consensus protocol"`
 
@@ -424,6 +437,11 @@ func (cfg BaseConfig) ValidateBasic() error {
 		return errInvalidDBPath
 	}
 
+	// Verify the number of retained blocks
+	if cfg.RetainBlocks < 0 {
+		return errInvalidRetainBlocks
+	}
+
 	// Verify the validator private key path is set
 	if cfg.PrivValidatorKey == "" {
 		return errInvalidPrivValidatorKeyPath
@@ -131,6 +131,15 @@ func TestConfig_ValidateBaseConfig(t *testing.T) {
 		assert.ErrorIs(t, c.BaseConfig.ValidateBasic(), errInvalidDBPath)
 	})
 
+	t.Run("negative retained blocks", func(t *testing.T) {
+		t.Parallel()
+
+		c := DefaultConfig()
+		c.RetainBlocks = -1
+
+		assert.ErrorIs(t, c.BaseConfig.ValidateBasic(), errInvalidRetainBlocks)
+	})
+
 	t.Run("priv validator key path not set", func(t *testing.T) {
 		t.Parallel()
 
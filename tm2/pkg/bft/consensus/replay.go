@@ -342,6 +342,10 @@ func (h *Handshaker) ReplayBlocks(
 		// the app should never be ahead of the store (but this is under app's control)
 		return appHash, sm.AppBlockHeightTooHighError{CoreHeight: storeBlockHeight, AppHeight: appBlockHeight}
 
+	case appBlockHeight < h.store.Base()-1:
+		// the blocks the app is missing were pruned
+		return appHash, sm.AppBlockHeightTooLowError{CoreBase: h.store.Base(), AppHeight: appBlockHeight}
+
 	case storeBlockHeight < stateBlockHeight:
 		// the state should never be ahead of the store (this is under tendermint's control)
 		panic(fmt.Sprintf("StateBlockHeight (%d) > StoreBlockHeight (%d)", stateBlockHeight, storeBlockHeight))
@@ -99,6 +99,10 @@ type ConsensusState struct {
 	// create and execute blocks
 	blockExec *sm.BlockExecutor
 
+	// add evidence to the pool
+	// when it's detected
+	evpool sm.EvidencePool
+
 	// notify us if txs are available
 	txNotifier txNotifier
 
@@ -144,6 +148,14 @@ type ConsensusState struct {
 // StateOption sets an optional parameter on the ConsensusState.
 type StateOption func(*ConsensusState)
 
+// WithEvidencePool sets the evidence pool of the ConsensusState, to which the
+// conflicting votes of the validators are added.
+func WithEvidencePool(evpool sm.EvidencePool) StateOption {
+	return func(cs *ConsensusState) {
+		cs.evpool = evpool
+	}
+}
+
 // NewConsensusState returns a new ConsensusState.
 func NewConsensusState(
 	config *cnscfg.ConsensusConfig,
@@ -157,6 +169,7 @@ func NewConsensusState(
 		config:           config,
 		blockExec:        blockExec,
 		blockStore:       blockStore,
+		evpool:           sm.MockEvidencePool{},
 		txNotifier:       txNotifier,
 		peerMsgQueue:     make(chan msgInfo, msgQueueSize),
 		internalMsgQueue: make(chan msgInfo, msgQueueSize),
@@ -1051,7 +1064,7 @@ func (cs *ConsensusState) defaultDoPrevote(height int64, round int) {
 	}
 
 	// Validate proposal block
-	err := cs.state.ValidateBlock(cs.ProposalBlock)
+	err := cs.blockExec.ValidateBlock(cs.state, cs.ProposalBlock)
 	if err != nil {
 		// ProposalBlock is invalid, prevote nil.
 		logger.Error("enterPrevote: ProposalBlock is invalid", "err", err)
@@ -1164,7 +1177,7 @@ func (cs *ConsensusState) enterPrecommit(height int64, round int) {
 	if cs.ProposalBlock.HashesTo(blockID.Hash) {
 		logger.Info("enterPrecommit: +2/3 prevoted proposal block. Locking", "hash", blockID.Hash)
 		// Validate the block.
-		if err := cs.state.ValidateBlock(cs.ProposalBlock); err != nil {
+		if err := cs.blockExec.ValidateBlock(cs.state, cs.ProposalBlock); err != nil {
 			panic(fmt.Sprintf("enterPrecommit: +2/3 prevoted for an invalid block: %v", err))
 		}
 		cs.LockedRound = round
@@ -1313,7 +1326,7 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	if !block.HashesTo(blockID.Hash) {
 		panic("Cannot finalizeCommit, ProposalBlock does not hash to commit hash")
 	}
-	if err := cs.state.ValidateBlock(block); err != nil {
+	if err := cs.blockExec.ValidateBlock(cs.state, block); err != nil {
 		panic(fmt.Sprintf("+2/3 committed an invalid block: %v", err))
 	}
 
@@ -1518,21 +1531,19 @@ func (cs *ConsensusState) tryAddVote(vote *types.Vote, peerID p2pTypes.ID) (bool
 	added, err := cs.addVote(vote, peerID)
 	if err != nil {
 		// If the vote height is off, we'll just ignore it,
-		// But if it's a conflicting sig, add it to the cs.mempool.
+		// But if it's a conflicting sig, add it to the cs.evpool.
 		// If it's otherwise invalid, punish peer.
 		if goerrors.Is(err, ErrVoteHeightMismatch) {
 			return added, err
-		} else if _, ok := err.(*types.VoteConflictingVotesError); ok {
-			/* XXX
-			addr := cs.privValidator.GetPubKey().Address()
-			if bytes.Equal(vote.ValidatorAddress, addr) {
+		} else if voteErr, ok := err.(*types.VoteConflictingVotesError); ok {
+			if cs.privValidator != nil && vote.ValidatorAddress == cs.privValidator.GetPubKey().Address() {
 				cs.Logger.Error("Found conflicting vote from ourselves. Did you unsafe_reset a validator?", "height", vote.Height, "round", vote.Round, "type", vote.Type)
 				return added, err
 			}
-			cs.evpool.AddEvidence(voteErr.DuplicateVoteEvidence)
+			if err := cs.evpool.AddEvidence(voteErr.DuplicateVoteEvidence); err != nil {
+				cs.Logger.Info("Error adding evidence of conflicting votes", "err", err)
+			}
 			return added, err
-			*/
-			panic("not yet implemented")
 		} else {
 			// Either
 			// 1) bad peer OR
@@ -0,0 +1,24 @@
+package evidence
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// ErrEvidenceAlreadyStored is returned when the evidence was seen earlier
+var ErrEvidenceAlreadyStored = errors.New("evidence is already stored")
+
+// InvalidEvidenceError means the evidence failed its verification against
+// the state
+type InvalidEvidenceError struct {
+	ErrorValue error
+}
+
+func (e InvalidEvidenceError) Error() string {
+	return fmt.Sprintf("evidence is not valid: %v", e.ErrorValue)
+}
+
+func (e InvalidEvidenceError) Unwrap() error {
+	return e.ErrorValue
+}
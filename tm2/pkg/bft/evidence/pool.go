@@ -0,0 +1,167 @@
+package evidence
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// EvidencePool maintains a pool of valid evidence
+// in an EvidenceStore.
+type EvidencePool struct {
+	logger *slog.Logger
+
+	evidenceStore *EvidenceStore
+	evidenceList  *clist.CList // concurrent linked-list of evidence
+
+	// needed to load validators to verify evidence
+	stateDB dbm.DB
+
+	// latest state
+	mtx   sync.Mutex
+	state sm.State
+}
+
+var _ sm.EvidencePool = (*EvidencePool)(nil)
+
+// NewEvidencePool returns a new EvidencePool, which verifies the evidence
+// against the validator sets of stateDB, and persists it in evidenceDB.
+func NewEvidencePool(stateDB, evidenceDB dbm.DB) *EvidencePool {
+	evidenceStore := NewEvidenceStore(evidenceDB)
+	evpool := &EvidencePool{
+		stateDB:       stateDB,
+		state:         sm.LoadState(stateDB),
+		logger:        log.NewNoopLogger(),
+		evidenceStore: evidenceStore,
+		evidenceList:  clist.New(),
+	}
+
+	// Gossip again the evidence which was pending before a restart.
+	for _, ev := range evidenceStore.PendingEvidence(-1) {
+		evpool.evidenceList.PushBack(ev)
+	}
+	return evpool
+}
+
+// EvidenceFront returns the first evidence of the pool, to gossip it.
+func (evpool *EvidencePool) EvidenceFront() *clist.CElement {
+	return evpool.evidenceList.Front()
+}
+
+// EvidenceWaitChan returns a channel which is closed when the pool has
+// evidence.
+func (evpool *EvidencePool) EvidenceWaitChan() <-chan struct{} {
+	return evpool.evidenceList.WaitChan()
+}
+
+// SetLogger sets the Logger.
+func (evpool *EvidencePool) SetLogger(l *slog.Logger) {
+	evpool.logger = l
+}
+
+// PendingEvidence returns up to maxNum uncommitted evidence.
+// If maxNum is -1, all evidence is returned.
+func (evpool *EvidencePool) PendingEvidence(maxNum int64) []types.Evidence {
+	return evpool.evidenceStore.PendingEvidence(maxNum)
+}
+
+// State returns the current state of the evpool.
+func (evpool *EvidencePool) State() sm.State {
+	evpool.mtx.Lock()
+	defer evpool.mtx.Unlock()
+	return evpool.state
+}
+
+// Update loads the latest state, marks the evidence of the block as
+// committed, and removes the evidence which is now too old.
+func (evpool *EvidencePool) Update(block *types.Block, state sm.State) {
+	// sanity check
+	if state.LastBlockHeight != block.Height {
+		panic(fmt.Sprintf("Failed EvidencePool.Update sanity check: got state.Height=%d with block.Height=%d",
+			state.LastBlockHeight, block.Height))
+	}
+
+	// update the state
+	evpool.mtx.Lock()
+	evpool.state = state
+	evpool.mtx.Unlock()
+
+	// remove evidence from pending and mark committed
+	evpool.MarkEvidenceAsCommitted(block.Height, state.EvidenceMaxAge(), block.Evidence.Evidence)
+}
+
+// AddEvidence checks the evidence is valid and adds it to the pool.
+func (evpool *EvidencePool) AddEvidence(evidence types.Evidence) error {
+	if err := evidence.ValidateBasic(); err != nil {
+		return InvalidEvidenceError{err}
+	}
+
+	// check if evidence is already stored
+	if evpool.evidenceStore.Has(evidence) {
+		return ErrEvidenceAlreadyStored
+	}
+
+	if err := sm.VerifyEvidence(evpool.stateDB, evpool.State(), evidence); err != nil {
+		return InvalidEvidenceError{err}
+	}
+
+	if !evpool.evidenceStore.AddNewEvidence(evidence) {
+		return ErrEvidenceAlreadyStored
+	}
+
+	evpool.logger.Info("Verified new evidence of byzantine behaviour", "evidence", evidence)
+
+	// add evidence to clist
+	evpool.evidenceList.PushBack(evidence)
+
+	return nil
+}
+
+// MarkEvidenceAsCommitted marks all the evidence as committed, and removes
+// it, and the evidence older than maxAge, from the pool.
+func (evpool *EvidencePool) MarkEvidenceAsCommitted(height, maxAge int64, evidence []types.Evidence) {
+	// make a map of committed evidence to remove from the clist
+	blockEvidenceMap := make(map[string]struct{})
+	for _, ev := range evidence {
+		evpool.evidenceStore.MarkEvidenceAsCommitted(ev)
+		blockEvidenceMap[evMapKey(ev)] = struct{}{}
+	}
+
+	// remove committed evidence from the clist
+	evpool.removeEvidence(height, maxAge, blockEvidenceMap)
+}
+
+// IsCommitted returns true if we have already seen this exact evidence and it
+// is already marked as committed.
+func (evpool *EvidencePool) IsCommitted(evidence types.Evidence) bool {
+	ei := evpool.evidenceStore.GetInfo(evidence.Height(), evidence.Hash())
+	return ei.Evidence != nil && ei.Committed
+}
+
+func (evpool *EvidencePool) removeEvidence(height, maxAge int64, blockEvidenceMap map[string]struct{}) {
+	for e := evpool.evidenceList.Front(); e != nil; e = e.Next() {
+		ev := e.Value.(types.Evidence)
+
+		// Remove the evidence if it's already in a block,
+		// or if it's now too old to be.
+		_, committed := blockEvidenceMap[evMapKey(ev)]
+		if committed || ev.Height() < height-maxAge {
+			if !committed {
+				evpool.evidenceStore.RemovePendingEvidence(ev)
+			}
+			// remove from clist
+			evpool.evidenceList.Remove(e)
+			e.DetachPrev()
+		}
+	}
+}
+
+func evMapKey(ev types.Evidence) string {
+	return string(ev.Hash())
+}
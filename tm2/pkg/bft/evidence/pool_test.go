@@ -0,0 +1,160 @@
+package evidence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+const testMaxAge = 100
+
+// initializeValidatorState saves the states, with a single validator of
+// address valAddr, up to height.
+func initializeValidatorState(t *testing.T, height int64) (dbm.DB, crypto.Address) {
+	t.Helper()
+
+	stateDB := memdb.NewMemDB()
+
+	// create validator set and state
+	val := types.NewValidator(ed25519.GenPrivKey().PubKey(), 10)
+	valSet := types.NewValidatorSet([]*types.Validator{val})
+	params := types.DefaultConsensusParams()
+	params.Evidence = &abci.EvidenceParams{MaxAge: testMaxAge}
+	state := sm.State{
+		LastBlockHeight:             0,
+		LastBlockTime:               tmtime.Now(),
+		Validators:                  valSet,
+		NextValidators:              valSet.CopyIncrementProposerPriority(1),
+		LastValidators:              types.NewValidatorSet(nil),
+		LastHeightValidatorsChanged: 1,
+		ConsensusParams:             params,
+	}
+
+	// save all states up to height
+	for i := range height + 1 {
+		state.LastBlockHeight = i
+		sm.SaveState(stateDB, state)
+	}
+
+	return stateDB, val.Address
+}
+
+// updatePool updates the pool with an empty block, or a block including the
+// evidence, at height.
+func updatePool(pool *EvidencePool, height int64, evidence ...types.Evidence) {
+	state := pool.State()
+	state.LastBlockHeight = height
+	pool.Update(types.MakeBlock(height, nil, nil, evidence), state)
+}
+
+func TestEvidencePoolAddEvidence(t *testing.T) {
+	t.Parallel()
+
+	const height = 10
+	stateDB, valAddr := initializeValidatorState(t, height)
+	pool := NewEvidencePool(stateDB, memdb.NewMemDB())
+
+	goodEvidence := types.NewMockGoodEvidence(height, 0, valAddr)
+	require.NoError(t, pool.AddEvidence(goodEvidence))
+	assert.Equal(t, []types.Evidence{goodEvidence}, pool.PendingEvidence(-1))
+
+	// the evidence is gossiped
+	select {
+	case <-pool.EvidenceWaitChan():
+	default:
+		t.Fatal("expected the evidence to be available")
+	}
+	assert.Equal(t, goodEvidence, pool.EvidenceFront().Value)
+
+	// the same evidence is added only once
+	assert.ErrorIs(t, pool.AddEvidence(goodEvidence), ErrEvidenceAlreadyStored)
+
+	testCases := []struct {
+		name     string
+		evidence types.Evidence
+	}{
+		{"bad evidence", types.MockBadEvidence{MockGoodEvidence: types.NewMockGoodEvidence(height-1, 0, valAddr)}},
+		{"non-validator", types.NewMockGoodEvidence(height, 0, crypto.AddressFromPreimage([]byte("other")))},
+		{"unknown validator set", types.NewMockGoodEvidence(height+10, 0, valAddr)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := pool.AddEvidence(tc.evidence)
+			assert.ErrorAs(t, err, new(InvalidEvidenceError))
+		})
+	}
+}
+
+func TestEvidencePoolExpiredEvidence(t *testing.T) {
+	t.Parallel()
+
+	const height = testMaxAge + 10
+	stateDB, valAddr := initializeValidatorState(t, height)
+	pool := NewEvidencePool(stateDB, memdb.NewMemDB())
+
+	// evidence older than MaxAge is rejected
+	err := pool.AddEvidence(types.NewMockGoodEvidence(height-testMaxAge-1, 0, valAddr))
+	assert.ErrorAs(t, err, new(InvalidEvidenceError))
+
+	// and the pending evidence is removed once it expires
+	ev := types.NewMockGoodEvidence(height-testMaxAge, 0, valAddr)
+	require.NoError(t, pool.AddEvidence(ev))
+	updatePool(pool, height+1)
+
+	assert.Empty(t, pool.PendingEvidence(-1))
+	assert.Nil(t, pool.EvidenceFront())
+	assert.False(t, pool.IsCommitted(ev))
+}
+
+func TestEvidencePoolUpdate(t *testing.T) {
+	t.Parallel()
+
+	const height = 10
+	stateDB, valAddr := initializeValidatorState(t, height)
+	pool := NewEvidencePool(stateDB, memdb.NewMemDB())
+
+	ev1 := types.NewMockGoodEvidence(height-1, 0, valAddr)
+	ev2 := types.NewMockGoodEvidence(height, 0, valAddr)
+	require.NoError(t, pool.AddEvidence(ev1))
+	require.NoError(t, pool.AddEvidence(ev2))
+	assert.Equal(t, []types.Evidence{ev1}, pool.PendingEvidence(1))
+
+	// the evidence committed in a block is no more pending
+	updatePool(pool, height+1, ev1)
+
+	assert.True(t, pool.IsCommitted(ev1))
+	assert.False(t, pool.IsCommitted(ev2))
+	assert.Equal(t, []types.Evidence{ev2}, pool.PendingEvidence(-1))
+	assert.Equal(t, ev2, pool.EvidenceFront().Value)
+	assert.ErrorIs(t, pool.AddEvidence(ev1), ErrEvidenceAlreadyStored)
+}
+
+func TestEvidencePoolRestart(t *testing.T) {
+	t.Parallel()
+
+	const height = 10
+	stateDB, valAddr := initializeValidatorState(t, height)
+	evidenceDB := memdb.NewMemDB()
+	pool := NewEvidencePool(stateDB, evidenceDB)
+
+	ev := types.NewMockGoodEvidence(height, 0, valAddr)
+	require.NoError(t, pool.AddEvidence(ev))
+
+	// the pending evidence is gossiped again after a restart
+	pool = NewEvidencePool(stateDB, evidenceDB)
+	assert.Equal(t, []types.Evidence{ev}, pool.PendingEvidence(-1))
+	require.NotNil(t, pool.EvidenceFront())
+	assert.Equal(t, ev, pool.EvidenceFront().Value)
+}
@@ -0,0 +1,187 @@
+package evidence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	p2pTesting "github.com/gnolang/gno/tm2/pkg/internal/p2p"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pcfg "github.com/gnolang/gno/tm2/pkg/p2p/config"
+)
+
+// testP2PConfig returns a configuration for testing the peer-to-peer layer
+func testP2PConfig() *p2pcfg.P2PConfig {
+	cfg := p2pcfg.DefaultP2PConfig()
+	cfg.ListenAddress = "tcp://0.0.0.0:26656"
+	cfg.FlushThrottleTimeout = 10 * time.Millisecond
+
+	return cfg
+}
+
+type peerState struct {
+	height int64
+}
+
+func (ps peerState) GetHeight() int64 {
+	return ps.height
+}
+
+// connect N evidence reactors, sharing the same validator sets, through N
+// switches
+func makeAndConnectReactors(t *testing.T, stateDB dbm.DB, n int) []*Reactor {
+	t.Helper()
+
+	var (
+		reactors = make([]*Reactor, n)
+		logger   = log.NewNoopLogger()
+		options  = make(map[int][]p2p.SwitchOption)
+	)
+
+	for i := range n {
+		reactor := NewReactor(NewEvidencePool(stateDB, memdb.NewMemDB()))
+		reactor.SetLogger(logger.With("validator", i))
+
+		options[i] = []p2p.SwitchOption{
+			p2p.WithReactor("EVIDENCE", reactor),
+		}
+
+		reactors[i] = reactor
+	}
+
+	// "Simulate" the networking layer
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	cfg := p2pTesting.TestingConfig{
+		Count:         n,
+		P2PCfg:        testP2PConfig(),
+		SwitchOptions: options,
+		Channels:      []byte{EvidenceChannel},
+	}
+
+	p2pTesting.MakeConnectedPeers(t, ctx, cfg)
+
+	return reactors
+}
+
+// waitForEvidence waits for the evidence to be pending in the pools of all
+// the reactors.
+func waitForEvidence(t *testing.T, evidence []types.Evidence, reactors []*Reactor) {
+	t.Helper()
+
+	for i, reactor := range reactors {
+		assert.Eventuallyf(t, func() bool {
+			return len(reactor.evpool.PendingEvidence(-1)) == len(evidence)
+		}, 10*time.Second, 50*time.Millisecond, "evidence not received by reactor %d", i)
+
+		// the pending evidence is ordered by height
+		assert.Equal(t, evidence, reactor.evpool.PendingEvidence(-1))
+	}
+}
+
+func TestReactorBroadcastEvidence(t *testing.T) {
+	t.Parallel()
+
+	const (
+		height = 10
+		N      = 4
+	)
+
+	stateDB, valAddr := initializeValidatorState(t, height)
+	reactors := makeAndConnectReactors(t, stateDB, N)
+	t.Cleanup(func() {
+		for _, r := range reactors {
+			assert.NoError(t, r.Stop())
+		}
+	})
+
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{height})
+		}
+	}
+
+	// add evidence to the first reactor's pool,
+	// and wait for it to be received by the others
+	evidence := make([]types.Evidence, 0, height)
+	for h := int64(1); h <= height; h++ {
+		ev := types.NewMockGoodEvidence(h, 0, valAddr)
+		require.NoError(t, reactors[0].evpool.AddEvidence(ev))
+		evidence = append(evidence, ev)
+	}
+
+	waitForEvidence(t, evidence, reactors)
+}
+
+func TestReactorNoBroadcastToLaggingPeer(t *testing.T) {
+	t.Parallel()
+
+	const height = 10
+
+	stateDB, valAddr := initializeValidatorState(t, height)
+	reactors := makeAndConnectReactors(t, stateDB, 2)
+	t.Cleanup(func() {
+		for _, r := range reactors {
+			assert.NoError(t, r.Stop())
+		}
+	})
+
+	// the peer of the first reactor lags behind the evidence
+	for _, peer := range reactors[0].Switch.Peers().List() {
+		peer.Set(types.PeerStateKey, peerState{height - 5})
+	}
+
+	old := types.NewMockGoodEvidence(height-5, 0, valAddr)
+	recent := types.NewMockGoodEvidence(height, 0, valAddr)
+	require.NoError(t, reactors[0].evpool.AddEvidence(old))
+	require.NoError(t, reactors[0].evpool.AddEvidence(recent))
+
+	// only the evidence the peer can verify is sent
+	waitForEvidence(t, []types.Evidence{old}, reactors[1:])
+
+	time.Sleep(500 * time.Millisecond)
+	assert.Equal(t, []types.Evidence{old}, reactors[1].evpool.PendingEvidence(-1))
+}
+
+func TestEvidenceListMessageValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	val := ed25519.GenPrivKey()
+	badEvidence := &types.DuplicateVoteEvidence{
+		PubKey: val.PubKey(),
+		VoteA:  &types.Vote{Height: -1},
+		VoteB:  &types.Vote{Height: -1},
+	}
+
+	testCases := []struct {
+		name     string
+		evidence []types.Evidence
+		valid    bool
+	}{
+		{"empty", nil, true},
+		{"good evidence", []types.Evidence{types.NewMockGoodEvidence(1, 0, crypto.Address{})}, true},
+		{"invalid evidence", []types.Evidence{badEvidence}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg := &EvidenceListMessage{Evidence: tc.evidence}
+			if tc.valid {
+				assert.NoError(t, msg.ValidateBasic())
+			} else {
+				assert.Error(t, msg.ValidateBasic())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package evidence
+
+import (
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+/*
+Requirements:
+	- Valid new evidence must be persisted immediately and never forgotten
+	- Uncommitted evidence must be continuously broadcast
+	- Uncommitted evidence is ordered by height
+
+Impl:
+	- First commit atomically in pending and lookup.
+	- Once committed, atomically remove from pending and update lookup.
+
+Schema for indexing evidence (note you need both height and hash to find a piece of evidence):
+
+"evidence-lookup"/<evidence-height>/<evidence-hash> -> EvidenceInfo
+"evidence-pending"/<evidence-height>/<evidence-hash> -> EvidenceInfo
+*/
+
+const (
+	baseKeyLookup  = "evidence-lookup"  // all evidence
+	baseKeyPending = "evidence-pending" // not yet committed
+)
+
+// EvidenceInfo is the evidence stored by the EvidenceStore, with whether it
+// was committed in a block.
+type EvidenceInfo struct {
+	Committed bool
+	Evidence  types.Evidence
+}
+
+// bE encodes the height as a fixed-width hex string, so that the keys are
+// ordered by height.
+func bE(h int64) string {
+	return fmt.Sprintf("%0.16X", h)
+}
+
+func keyLookup(evidence types.Evidence) []byte {
+	return keyLookupFromHeightAndHash(evidence.Height(), evidence.Hash())
+}
+
+func keyLookupFromHeightAndHash(height int64, hash []byte) []byte {
+	return fmt.Appendf(nil, "%s/%s/%X", baseKeyLookup, bE(height), hash)
+}
+
+func keyPending(evidence types.Evidence) []byte {
+	return fmt.Appendf(nil, "%s/%s/%X", baseKeyPending, bE(evidence.Height()), evidence.Hash())
+}
+
+// EvidenceStore is a store of all the evidence we've seen, including
+// evidence that has been committed, and evidence pending to be committed.
+type EvidenceStore struct {
+	db dbm.DB
+}
+
+// NewEvidenceStore returns a new EvidenceStore persisted in db.
+func NewEvidenceStore(db dbm.DB) *EvidenceStore {
+	return &EvidenceStore{
+		db: db,
+	}
+}
+
+// PendingEvidence returns up to maxNum known, uncommitted evidence, ordered
+// by height. If maxNum is -1, all evidence is returned.
+func (store *EvidenceStore) PendingEvidence(maxNum int64) (evidence []types.Evidence) {
+	iter := dbm.IteratePrefix(store.db, []byte(baseKeyPending))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		if maxNum >= 0 && int64(len(evidence)) >= maxNum {
+			break
+		}
+		var ei EvidenceInfo
+		amino.MustUnmarshal(iter.Value(), &ei)
+		evidence = append(evidence, ei.Evidence)
+	}
+	return evidence
+}
+
+// GetInfo fetches the EvidenceInfo with the given height and hash.
+// If not found, ei.Evidence is nil.
+func (store *EvidenceStore) GetInfo(height int64, hash []byte) EvidenceInfo {
+	key := keyLookupFromHeightAndHash(height, hash)
+	val := store.db.Get(key)
+
+	if len(val) == 0 {
+		return EvidenceInfo{}
+	}
+	var ei EvidenceInfo
+	amino.MustUnmarshal(val, &ei)
+	return ei
+}
+
+// Has checks if the evidence is already stored
+func (store *EvidenceStore) Has(evidence types.Evidence) bool {
+	key := keyLookup(evidence)
+	return store.db.Has(key)
+}
+
+// AddNewEvidence adds the given evidence to the database.
+// It returns false if the evidence is already stored.
+func (store *EvidenceStore) AddNewEvidence(evidence types.Evidence) bool {
+	// check if we already have seen it
+	if store.Has(evidence) {
+		return false
+	}
+
+	ei := EvidenceInfo{
+		Committed: false,
+		Evidence:  evidence,
+	}
+	eiBytes := amino.MustMarshal(ei)
+
+	batch := store.db.NewBatch()
+	defer batch.Close()
+	batch.Set(keyPending(evidence), eiBytes)
+	batch.Set(keyLookup(evidence), eiBytes)
+	batch.WriteSync()
+
+	return true
+}
+
+// MarkEvidenceAsCommitted removes evidence from pending and sets the lookup
+// to committed.
+func (store *EvidenceStore) MarkEvidenceAsCommitted(evidence types.Evidence) {
+	ei := EvidenceInfo{
+		Committed: true,
+		Evidence:  evidence,
+	}
+
+	batch := store.db.NewBatch()
+	defer batch.Close()
+	batch.Delete(keyPending(evidence))
+	batch.Set(keyLookup(evidence), amino.MustMarshal(ei))
+	batch.WriteSync()
+}
+
+// RemovePendingEvidence removes evidence from pending, e.g. when it expired
+// before being committed. It stays in the lookup, so that it is not added
+// again.
+func (store *EvidenceStore) RemovePendingEvidence(evidence types.Evidence) {
+	store.db.DeleteSync(keyPending(evidence))
+}
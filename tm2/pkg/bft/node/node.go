@@ -4,6 +4,7 @@ package node
 // is enabled by the user by setting a profiling address
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/file"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
 	"github.com/gnolang/gno/tm2/pkg/p2p/conn"
 	"github.com/gnolang/gno/tm2/pkg/p2p/discovery"
 	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
@@ -23,6 +25,7 @@ import (
 	bc "github.com/gnolang/gno/tm2/pkg/bft/blockchain"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	cs "github.com/gnolang/gno/tm2/pkg/bft/consensus"
+	"github.com/gnolang/gno/tm2/pkg/bft/evidence"
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
@@ -32,6 +35,7 @@ import (
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	"github.com/gnolang/gno/tm2/pkg/bft/store"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
@@ -49,17 +53,21 @@ import (
 // to alert of connecting / disconnecting peers
 const (
 	mempoolReactorName    = "MEMPOOL"
+	evidenceReactorName   = "EVIDENCE"
 	blockchainReactorName = "BLOCKCHAIN"
 	consensusReactorName  = "CONSENSUS"
 	discoveryReactorName  = "DISCOVERY"
+	stateSyncReactorName  = "STATESYNC"
 )
 
 const (
 	mempoolModuleName    = "mempool"
+	evidenceModuleName   = "evidence"
 	blockchainModuleName = "blockchain"
 	consensusModuleName  = "consensus"
 	p2pModuleName        = "p2p"
 	discoveryModuleName  = "discovery"
+	stateSyncModuleName  = "statesync"
 )
 
 // ------------------------------------------------------------------------------
@@ -164,10 +172,16 @@ type Node struct {
 	bcReactor         p2p.Reactor       // for fast-syncing
 	mempoolReactor    *mempl.Reactor    // for gossipping transactions
 	mempool           mempl.Mempool
-	consensusState    *cs.ConsensusState   // latest consensus state
-	consensusReactor  *cs.ConsensusReactor // for participating in the consensus
-	proxyApp          appconn.AppConns     // connection to the application
-	rpcListeners      []net.Listener       // rpc servers
+	evidencePool      *evidence.EvidencePool // tracking evidence
+	pruner            *sm.Pruner             // pruning old blocks, if any
+	consensusState    *cs.ConsensusState     // latest consensus state
+	consensusReactor  *cs.ConsensusReactor   // for participating in the consensus
+	stateSyncReactor  *statesync.Reactor     // for restoring the state from snapshots
+	stateSync         bool                   // whether to state sync on start
+	fastSync          bool                   // whether to fast sync, after state syncing
+	stateSyncGenesis  sm.State               // the genesis state, to state sync from
+	proxyApp          appconn.AppConns       // connection to the application
+	rpcListeners      []net.Listener         // rpc servers
 	txEventStore      eventstore.TxEventStore
 	eventStoreService *eventstore.Service
 	firstBlockSignal  <-chan struct{}
@@ -200,6 +214,7 @@ func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger *slog
 
 func createAndStartEventStoreService(
 	cfg *cfg.Config,
+	dbProvider DBProvider,
 	evsw events.EventSwitch,
 	logger *slog.Logger,
 ) (*eventstore.Service, eventstore.TxEventStore, error) {
@@ -216,6 +231,14 @@ func createAndStartEventStoreService(
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to create file tx event store, %w", err)
 		}
+	case kv.EventStoreType:
+		// Transactions and blocks should be indexed in a database
+		db, err := dbProvider(&DBContext{"tx_index", cfg})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create kv tx event store, %w", err)
+		}
+
+		txEventStore = kv.NewTxEventStore(db)
 	default:
 		// Transaction event storing should be omitted
 		txEventStore = null.NewNullEventStore()
@@ -285,6 +308,21 @@ func createMempoolAndMempoolReactor(config *cfg.Config, proxyApp appconn.AppConn
 	return mempoolReactor, mempool
 }
 
+func createEvidenceReactor(config *cfg.Config, dbProvider DBProvider,
+	stateDB dbm.DB, logger *slog.Logger,
+) (*evidence.Reactor, *evidence.EvidencePool, error) {
+	evidenceDB, err := dbProvider(&DBContext{"evidence", config})
+	if err != nil {
+		return nil, nil, err
+	}
+	evidenceLogger := logger.With("module", evidenceModuleName)
+	evidencePool := evidence.NewEvidencePool(stateDB, evidenceDB)
+	evidencePool.SetLogger(evidenceLogger)
+	evidenceReactor := evidence.NewReactor(evidencePool)
+	evidenceReactor.SetLogger(evidenceLogger)
+	return evidenceReactor, evidencePool, nil
+}
+
 func createBlockchainReactor(
 	state sm.State,
 	blockExec *sm.BlockExecutor,
@@ -310,6 +348,7 @@ func createConsensusReactor(config *cfg.Config,
 	blockExec *sm.BlockExecutor,
 	blockStore sm.BlockStore,
 	mempool *mempl.CListMempool,
+	evidencePool *evidence.EvidencePool,
 	privValidator types.PrivValidator,
 	fastSync bool,
 	evsw events.EventSwitch,
@@ -321,6 +360,7 @@ func createConsensusReactor(config *cfg.Config,
 		blockExec,
 		blockStore,
 		mempool,
+		cs.WithEvidencePool(evidencePool),
 	)
 	consensusState.SetLogger(consensusLogger)
 	if privValidator != nil {
@@ -381,22 +421,28 @@ func NewNode(config *cfg.Config,
 	})
 
 	// Transaction event storing
-	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, evsw, logger)
+	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, dbProvider, evsw, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	// State sync new nodes only, restoring the app state from the snapshots of the peers.
+	// The handshake is skipped, as the app state is restored instead of initialized.
+	stateSync := config.StateSync.Enable && state.LastBlockHeight == 0 && blockStore.Height() == 0
+
 	// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
 	// and replays any blocks as necessary to sync tendermint with the app.
 	consensusLogger := logger.With("module", consensusModuleName)
-	if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
-		return nil, err
-	}
+	if !stateSync {
+		if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
+			return nil, err
+		}
 
-	// Reload the state. It will have the Version.Consensus.App set by the
-	// Handshake, and may have other modifications as well (ie. depending on
-	// what happened during block replay).
-	state = sm.LoadState(stateDB)
+		// Reload the state. It will have the Version.Consensus.App set by the
+		// Handshake, and may have other modifications as well (ie. depending on
+		// what happened during block replay).
+		state = sm.LoadState(stateDB)
+	}
 
 	// If an address is provided, listen on the socket for a connection from an
 	// external signing process.
@@ -423,26 +469,43 @@ func NewNode(config *cfg.Config,
 	// Make MempoolReactor
 	mempoolReactor, mempool := createMempoolAndMempoolReactor(config, proxyApp, state, logger)
 
+	// Make EvidenceReactor
+	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateDB, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prune the old blocks, unless all of them are retained
+	blockExecOptions := []sm.BlockExecutorOption{sm.WithEvidencePool(evidencePool)}
+
+	var pruner *sm.Pruner
+	if config.RetainBlocks > 0 {
+		pruner = sm.NewPruner(stateDB, blockStore, config.RetainBlocks, logger.With("module", "pruner"))
+		blockExecOptions = append(blockExecOptions, sm.WithPruner(pruner))
+	}
+
 	// make block executor for consensus and blockchain reactors to execute blocks
 	blockExec := sm.NewBlockExecutor(
 		stateDB,
 		logger.With("module", "state"),
 		proxyApp.Consensus(),
 		mempool,
+		blockExecOptions...,
 	)
 
-	// Make ConsensusReactor
+	// Make ConsensusReactor, which waits for the state and fast sync
 	consensusReactor, consensusState := createConsensusReactor(
-		config, state, blockExec, blockStore, mempool,
-		privValidator, fastSync, evsw, consensusLogger,
+		config, state, blockExec, blockStore, mempool, evidencePool,
+		privValidator, fastSync || stateSync, evsw, consensusLogger,
 	)
 
-	// Make BlockchainReactor
+	// Make BlockchainReactor, which fast syncs from the restored state
+	// after state syncing
 	bcReactor, err := createBlockchainReactor(
 		state,
 		blockExec,
 		blockStore,
-		fastSync,
+		fastSync && !stateSync,
 		consensusReactor.SwitchToConsensus,
 		logger,
 	)
@@ -450,6 +513,16 @@ func NewNode(config *cfg.Config,
 		return nil, errors.Wrap(err, "could not create blockchain reactor")
 	}
 
+	// Make StateSyncReactor, which serves the snapshots of the app to the peers
+	stateSyncReactor := statesync.NewReactor(
+		config.StateSync,
+		proxyApp.Snapshot(),
+		proxyApp.Query(),
+		stateDB,
+		blockStore,
+	)
+	stateSyncReactor.SetLogger(logger.With("module", stateSyncModuleName))
+
 	reactors := []nodeReactor{
 		{
 			mempoolReactorName, mempoolReactor,
@@ -460,6 +533,12 @@ func NewNode(config *cfg.Config,
 		{
 			consensusReactorName, consensusReactor,
 		},
+		{
+			evidenceReactorName, evidenceReactor,
+		},
+		{
+			stateSyncReactorName, stateSyncReactor,
+		},
 	}
 
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txEventStore, genDoc, state)
@@ -556,8 +635,14 @@ func NewNode(config *cfg.Config,
 		bcReactor:         bcReactor,
 		mempoolReactor:    mempoolReactor,
 		mempool:           mempool,
+		evidencePool:      evidencePool,
+		pruner:            pruner,
 		consensusState:    consensusState,
 		consensusReactor:  consensusReactor,
+		stateSyncReactor:  stateSyncReactor,
+		stateSync:         stateSync,
+		fastSync:          fastSync,
+		stateSyncGenesis:  state,
 		proxyApp:          proxyApp,
 		txEventStore:      txEventStore,
 		eventStoreService: eventStoreService,
@@ -627,6 +712,13 @@ func (n *Node) OnStart() error {
 		n.mempool.InitWAL() // no need to have the mempool wal during tests
 	}
 
+	// Start pruning the old blocks
+	if n.pruner != nil {
+		if err := n.pruner.Start(); err != nil {
+			return err
+		}
+	}
+
 	// Start the switch (the P2P server).
 	err = n.sw.Start()
 	if err != nil {
@@ -642,9 +734,60 @@ func (n *Node) OnStart() error {
 	// Dial the persistent peers
 	n.sw.DialPeers(peerAddrs...)
 
+	// Restore the state from the snapshots of the peers
+	if n.stateSync {
+		go n.startStateSync()
+	}
+
 	return nil
 }
 
+// startStateSync restores the state from the snapshots of the peers,
+// and then switches to fast sync, or directly to consensus
+func (n *Node) startStateSync() {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	// Stop syncing when the node stops
+	go func() {
+		select {
+		case <-n.Quit():
+			cancelFn()
+		case <-ctx.Done():
+		}
+	}()
+
+	state, lb, err := n.stateSyncReactor.Sync(ctx, n.stateSyncGenesis)
+	if err != nil {
+		if ctx.Err() == nil {
+			n.Logger.Error("State sync failed", "err", err)
+		}
+
+		return
+	}
+
+	// Save the restored state, and the block at the restored height
+	sm.BootstrapState(n.stateDB, state)
+
+	meta := &types.BlockMeta{
+		BlockID: lb.Commit.BlockID,
+		Header:  *lb.Header,
+	}
+	if err := n.blockStore.Bootstrap(meta, lb.Commit); err != nil {
+		n.Logger.Error("Unable to bootstrap the block store", "err", err)
+		return
+	}
+
+	if !n.fastSync {
+		n.consensusReactor.SwitchToConsensus(state, 0)
+		return
+	}
+
+	if err := n.bcReactor.(*bc.BlockchainReactor).SwitchToFastSync(state); err != nil {
+		n.Logger.Error("Unable to switch to fast sync", "err", err)
+	}
+}
+
 // OnStop stops the Node. It implements service.Service.
 func (n *Node) OnStop() {
 	n.BaseService.OnStop()
@@ -655,6 +798,10 @@ func (n *Node) OnStop() {
 	n.evsw.Stop()
 	n.eventStoreService.Stop()
 
+	if n.pruner != nil {
+		n.pruner.Stop()
+	}
+
 	// Stop the node p2p transport
 	if err := n.transport.Close(); err != nil {
 		n.Logger.Error("unable to gracefully close transport", "err", err)
@@ -695,6 +842,7 @@ func (n *Node) Ready() <-chan struct{} {
 func (n *Node) configureRPC() {
 	rpccore.SetStateDB(n.stateDB)
 	rpccore.SetBlockStore(n.blockStore)
+	rpccore.SetTxEventStore(n.txEventStore)
 	rpccore.SetConsensusState(n.consensusState)
 	rpccore.SetMempool(n.mempool)
 	rpccore.SetP2PPeers(n.sw)
@@ -840,6 +988,11 @@ func (n *Node) Mempool() mempl.Mempool {
 	return n.mempool
 }
 
+// EvidencePool returns the Node's EvidencePool.
+func (n *Node) EvidencePool() *evidence.EvidencePool {
+	return n.evidencePool
+}
+
 // PrivValidator returns the Node's PrivValidator.
 // XXX: for convenience only!
 func (n *Node) PrivValidator() types.PrivValidator {
@@ -906,6 +1059,8 @@ func makeNodeInfo(
 			bcChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
 			mempl.MempoolChannel,
+			evidence.EvidenceChannel,
+			statesync.SnapshotChannel, statesync.ChunkChannel, statesync.LightBlockChannel,
 		},
 		Moniker: config.Moniker,
 		Other: p2pTypes.NodeInfoOther{
@@ -139,6 +139,26 @@ func TestNodeReady(t *testing.T) {
 	require.GreaterOrEqual(t, n.BlockStore().Height(), int64(1))
 }
 
+func TestNodePruneBlocks(t *testing.T) {
+	config, genesisFile := cfg.ResetTestRoot("node_node_test")
+	defer os.RemoveAll(config.RootDir)
+
+	config.RetainBlocks = 2
+
+	n, err := DefaultNewNode(config, genesisFile, events.NewEventSwitch(), log.NewTestingLogger(t))
+	require.NoError(t, err)
+
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	// The blocks are pruned as the node produces new ones
+	require.Eventually(t, func() bool {
+		return n.BlockStore().Base() > 1
+	}, 30*time.Second, 50*time.Millisecond)
+
+	assert.Nil(t, n.BlockStore().LoadBlock(1))
+}
+
 func TestNodeSetAppVersion(t *testing.T) {
 	config, genesisFile := cfg.ResetTestRoot("node_app_version_test")
 	defer os.RemoveAll(config.RootDir)
@@ -457,6 +457,36 @@ func TestRPCBatch_Endpoints(t *testing.T) {
 				return castResult
 			},
 		},
+		{
+			txSearchMethod,
+			&ctypes.ResultTxSearch{
+				TotalCount: 10,
+			},
+			func(batch *RPCBatch) {
+				require.NoError(t, batch.TxSearch("tx.height = 10", 1, 10, "desc"))
+			},
+			func(result any) any {
+				castResult, ok := result.(*ctypes.ResultTxSearch)
+				require.True(t, ok)
+
+				return castResult
+			},
+		},
+		{
+			blockSearchMethod,
+			&ctypes.ResultBlockSearch{
+				TotalCount: 10,
+			},
+			func(batch *RPCBatch) {
+				require.NoError(t, batch.BlockSearch("block.height = 10", 0, 0, ""))
+			},
+			func(result any) any {
+				castResult, ok := result.(*ctypes.ResultBlockSearch)
+				require.True(t, ok)
+
+				return castResult
+			},
+		},
 		{
 			validatorsMethod,
 			&ctypes.ResultValidators{
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
@@ -38,7 +39,12 @@ const (
 	blockResultsMethod       = "block_results"
 	commitMethod             = "commit"
 	txMethod                 = "tx"
+	txSearchMethod           = "tx_search"
+	blockSearchMethod        = "block_search"
 	validatorsMethod         = "validators"
+	subscribeMethod          = "subscribe"
+	unsubscribeMethod        = "unsubscribe"
+	unsubscribeAllMethod     = "unsubscribe_all"
 )
 
 // RPCClient encompasses common RPC client methods
@@ -46,6 +52,9 @@ type RPCClient struct {
 	requestTimeout time.Duration
 
 	caller rpcclient.Client
+
+	subscriptions    map[string]*Subscription // query -> subscription
+	subscriptionsMux sync.Mutex
 }
 
 // NewRPCClient creates a new RPC client instance with the given caller
@@ -53,6 +62,7 @@ func NewRPCClient(caller rpcclient.Client, opts ...Option) *RPCClient {
 	c := &RPCClient{
 		requestTimeout: defaultTimeout,
 		caller:         caller,
+		subscriptions:  make(map[string]*Subscription),
 	}
 
 	for _, opt := range opts {
@@ -85,6 +95,9 @@ func NewHTTPClient(rpcURL string) (*RPCClient, error) {
 // Request batching is available for JSON RPC requests over WS, which conforms to
 // the JSON RPC specification (https://www.jsonrpc.org/specification#batch). See
 // the example for more details
+//
+// Unlike the HTTP client, the WS client can subscribe to the events of the
+// node, see Subscribe
 func NewWSClient(rpcURL string) (*RPCClient, error) {
 	wsClient, err := ws.NewClient(rpcURL)
 	if err != nil {
@@ -311,6 +324,24 @@ func (c *RPCClient) Tx(hash []byte) (*ctypes.ResultTx, error) {
 	)
 }
 
+func (c *RPCClient) TxSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	return sendRequestCommon[ctypes.ResultTxSearch](
+		c.caller,
+		c.requestTimeout,
+		txSearchMethod,
+		searchParams(query, page, perPage, orderBy),
+	)
+}
+
+func (c *RPCClient) BlockSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	return sendRequestCommon[ctypes.ResultBlockSearch](
+		c.caller,
+		c.requestTimeout,
+		blockSearchMethod,
+		searchParams(query, page, perPage, orderBy),
+	)
+}
+
 func (c *RPCClient) Validators(height *int64) (*ctypes.ResultValidators, error) {
 	params := map[string]any{}
 	if height != nil {
@@ -325,6 +356,28 @@ func (c *RPCClient) Validators(height *int64) (*ctypes.ResultValidators, error)
 	)
 }
 
+// searchParams returns the params of a search request,
+// omitting the default page, page size and order
+func searchParams(query string, page, perPage int, orderBy string) map[string]any {
+	params := map[string]any{
+		"query": query,
+	}
+
+	if page != 0 {
+		params["page"] = page
+	}
+
+	if perPage != 0 {
+		params["per_page"] = perPage
+	}
+
+	if orderBy != "" {
+		params["order_by"] = orderBy
+	}
+
+	return params
+}
+
 // newRequest creates a new request based on the method
 // and given params
 func newRequest(method string, params map[string]any) (rpctypes.RPCRequest, error) {
@@ -410,6 +410,45 @@ func TestRPCClient_E2E_Endpoints(t *testing.T) {
 				assert.Equal(t, expectedResult, result)
 			},
 		},
+		{
+			txSearchMethod,
+			&ctypes.ResultTxSearch{
+				Txs: []*ctypes.ResultTx{
+					{
+						Hash:   []byte("tx hash"),
+						Height: 10,
+					},
+				},
+				TotalCount: 1,
+			},
+			func(client *RPCClient, expectedResult any) {
+				result, err := client.TxSearch("tx.height = 10", 1, 10, "desc")
+				require.NoError(t, err)
+
+				assert.Equal(t, expectedResult, result)
+			},
+		},
+		{
+			blockSearchMethod,
+			&ctypes.ResultBlockSearch{
+				Blocks: []*ctypes.ResultBlock{
+					{
+						Block: &bfttypes.Block{
+							Header: bfttypes.Header{
+								Height: 10,
+							},
+						},
+					},
+				},
+				TotalCount: 1,
+			},
+			func(client *RPCClient, expectedResult any) {
+				result, err := client.BlockSearch("block.height = 10", 0, 0, "")
+				require.NoError(t, err)
+
+				assert.Equal(t, expectedResult, result)
+			},
+		},
 		{
 			validatorsMethod,
 			&ctypes.ResultValidators{
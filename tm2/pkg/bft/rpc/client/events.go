@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/client/ws"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+)
+
+var errSubscriptionsNotSupported = errors.New("subscriptions are only supported by the WS client")
+
+var _ EventsClient = (*RPCClient)(nil)
+
+// subscriber is an RPC caller which receives the events of subscriptions
+type subscriber interface {
+	Subscribe(context.Context, rpctypes.RPCRequest) (*ws.Subscription, *rpctypes.RPCResponse, error)
+	Unsubscribe(*ws.Subscription)
+}
+
+// Subscription is a subscription to the events matching a query
+type Subscription struct {
+	Query string
+
+	events chan ctypes.ResultEvent
+	sub    *ws.Subscription
+	err    error
+}
+
+// Events returns the channel of the subscription events.
+// It is closed when the subscription ends, see Err
+func (s *Subscription) Events() <-chan ctypes.ResultEvent {
+	return s.events
+}
+
+// Err returns the reason the subscription was canceled by the node, once
+// the events channel is closed. It is nil if the subscription was removed
+// by Unsubscribe, UnsubscribeAll, or by closing the client
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Subscribe subscribes to the events matching the query (see the
+// events/query package), e.g. "tm.event = 'Tx' AND tx.height = 5".
+// Subscriptions are only available over the WS client
+func (c *RPCClient) Subscribe(query string) (*Subscription, error) {
+	caller, ok := c.caller.(subscriber)
+	if !ok {
+		return nil, errSubscriptionsNotSupported
+	}
+
+	// Prepare the RPC request
+	request, err := newRequest(subscribeMethod, map[string]any{
+		"query": query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	ctx, cancelFn := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancelFn()
+
+	wsSub, response, err := caller.Subscribe(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("unable to call RPC method %s, %w", subscribeMethod, err)
+	}
+
+	if response.Error != nil {
+		return nil, response.Error
+	}
+
+	sub := &Subscription{
+		Query:  query,
+		events: make(chan ctypes.ResultEvent),
+		sub:    wsSub,
+	}
+
+	c.subscriptionsMux.Lock()
+	c.subscriptions[query] = sub
+	c.subscriptionsMux.Unlock()
+
+	go c.runSubscription(caller, sub)
+
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription to the query
+func (c *RPCClient) Unsubscribe(query string) error {
+	c.subscriptionsMux.Lock()
+	sub, ok := c.subscriptions[query]
+	delete(c.subscriptions, query)
+	c.subscriptionsMux.Unlock()
+
+	if ok {
+		c.caller.(subscriber).Unsubscribe(sub.sub)
+	}
+
+	_, err := sendRequestCommon[ctypes.ResultUnsubscribe](
+		c.caller,
+		c.requestTimeout,
+		unsubscribeMethod,
+		map[string]any{
+			"query": query,
+		},
+	)
+
+	return err
+}
+
+// UnsubscribeAll removes all the subscriptions
+func (c *RPCClient) UnsubscribeAll() error {
+	c.subscriptionsMux.Lock()
+	subs := c.subscriptions
+	c.subscriptions = make(map[string]*Subscription)
+	c.subscriptionsMux.Unlock()
+
+	for _, sub := range subs {
+		c.caller.(subscriber).Unsubscribe(sub.sub)
+	}
+
+	_, err := sendRequestCommon[ctypes.ResultUnsubscribe](
+		c.caller,
+		c.requestTimeout,
+		unsubscribeAllMethod,
+		map[string]any{},
+	)
+
+	return err
+}
+
+// runSubscription passes the events of the subscription to its channel,
+// until the subscription ends
+func (c *RPCClient) runSubscription(caller subscriber, sub *Subscription) {
+	defer func() {
+		c.subscriptionsMux.Lock()
+		if c.subscriptions[sub.Query] == sub {
+			delete(c.subscriptions, sub.Query)
+		}
+		c.subscriptionsMux.Unlock()
+
+		caller.Unsubscribe(sub.sub)
+		close(sub.events)
+	}()
+
+	for {
+		select {
+		case <-sub.sub.Done():
+			return
+		case response := <-sub.sub.Responses():
+			// The node canceled the subscription
+			if response.Error != nil {
+				sub.err = response.Error
+
+				return
+			}
+
+			event, err := unmarshalResponseBytes[ctypes.ResultEvent](response.Result)
+			if err != nil {
+				sub.err = err
+
+				return
+			}
+
+			select {
+			case sub.events <- *event:
+			case <-sub.sub.Done():
+				return
+			}
+		}
+	}
+}
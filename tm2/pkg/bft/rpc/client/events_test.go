@@ -0,0 +1,211 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	bfttypes "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventsWSHandler generates a WS test handler which answers the
+// subscription requests, and sends the events (followed by a
+// cancellation error, if cancel is set) to the subscriptions
+func eventsWSHandler(
+	t *testing.T,
+	events []bfttypes.TMEvent,
+	cancel bool,
+) http.HandlerFunc {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+
+		defer c.Close()
+
+		writeResponse := func(response types.RPCResponse) {
+			marshalledResponse, err := json.Marshal(response)
+			require.NoError(t, err)
+
+			require.NoError(t, c.WriteMessage(websocket.TextMessage, marshalledResponse))
+		}
+
+		for {
+			_, message, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			// Parse the message
+			var req types.RPCRequest
+			require.NoError(t, json.Unmarshal(message, &req))
+
+			var params map[string]string
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+
+			switch req.Method {
+			case unsubscribeMethod, unsubscribeAllMethod:
+				writeResponse(types.NewRPCSuccessResponse(req.ID, &ctypes.ResultUnsubscribe{}))
+			case subscribeMethod:
+				writeResponse(types.NewRPCSuccessResponse(req.ID, &ctypes.ResultSubscribe{}))
+
+				// Send the events
+				id := types.JSONRPCStringID(fmt.Sprintf("%v#event", req.ID))
+				for _, event := range events {
+					writeResponse(types.NewRPCSuccessResponse(id, &ctypes.ResultEvent{
+						Query: params["query"],
+						Event: event,
+					}))
+				}
+
+				if cancel {
+					writeResponse(types.RPCInternalError(id, fmt.Errorf("subscription was canceled")))
+				}
+			default:
+				t.Errorf("unexpected method %s", req.Method)
+
+				return
+			}
+		}
+	}
+}
+
+func newEventsTestClient(t *testing.T, events []bfttypes.TMEvent, cancel bool) *RPCClient {
+	t.Helper()
+
+	server := createTestServer(t, eventsWSHandler(t, events, cancel))
+	client, err := NewWSClient("ws" + strings.TrimPrefix(server.URL, "http"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, client.Close())
+	})
+
+	return client
+}
+
+// readEvents reads the events of the subscription, until it is closed
+func readEvents(t *testing.T, sub *Subscription) []ctypes.ResultEvent {
+	t.Helper()
+
+	var events []ctypes.ResultEvent
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+
+			events = append(events, event)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the events")
+		}
+	}
+}
+
+func TestRPCClient_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	const query = "tm.event = 'Tx'"
+
+	var (
+		events = []bfttypes.TMEvent{
+			bfttypes.EventTx{Result: bfttypes.TxResult{Height: 1}},
+			bfttypes.EventTx{Result: bfttypes.TxResult{Height: 2}},
+		}
+		expectedEvents = []ctypes.ResultEvent{
+			{Query: query, Event: events[0]},
+			{Query: query, Event: events[1]},
+		}
+	)
+
+	t.Run("canceled by the node", func(t *testing.T) {
+		t.Parallel()
+
+		client := newEventsTestClient(t, events, true)
+
+		sub, err := client.Subscribe(query)
+		require.NoError(t, err)
+		assert.Equal(t, query, sub.Query)
+
+		assert.Equal(t, expectedEvents, readEvents(t, sub))
+		assert.ErrorContains(t, sub.Err(), "subscription was canceled")
+	})
+
+	t.Run("unsubscribed", func(t *testing.T) {
+		t.Parallel()
+
+		client := newEventsTestClient(t, events, false)
+
+		sub, err := client.Subscribe(query)
+		require.NoError(t, err)
+
+		for _, expectedEvent := range expectedEvents {
+			assert.Equal(t, expectedEvent, <-sub.Events())
+		}
+
+		require.NoError(t, client.Unsubscribe(query))
+
+		assert.Empty(t, readEvents(t, sub))
+		assert.NoError(t, sub.Err())
+	})
+
+	t.Run("unsubscribed from all", func(t *testing.T) {
+		t.Parallel()
+
+		client := newEventsTestClient(t, nil, false)
+
+		subs := make([]*Subscription, 0, 2)
+		for _, q := range []string{query, "tm.event = 'NewBlock'"} {
+			sub, err := client.Subscribe(q)
+			require.NoError(t, err)
+
+			subs = append(subs, sub)
+		}
+
+		require.NoError(t, client.UnsubscribeAll())
+
+		for _, sub := range subs {
+			assert.Empty(t, readEvents(t, sub))
+			assert.NoError(t, sub.Err())
+		}
+	})
+
+	t.Run("not supported over HTTP", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewHTTPClient("http://127.0.0.1:26657")
+		require.NoError(t, err)
+
+		_, err = client.Subscribe(query)
+		assert.ErrorIs(t, err, errSubscriptionsNotSupported)
+	})
+}
+
+func TestRPCClient_Subscribe_ResultEvent(t *testing.T) {
+	t.Parallel()
+
+	// The events are amino encoded with their type
+	result, err := amino.MarshalJSON(&ctypes.ResultEvent{
+		Query: "tm.event = 'NewBlock'",
+		Event: bfttypes.EventNewBlock{Block: &bfttypes.Block{}},
+	})
+	require.NoError(t, err)
+
+	event, err := unmarshalResponseBytes[ctypes.ResultEvent](result)
+	require.NoError(t, err)
+	assert.IsType(t, bfttypes.EventNewBlock{}, event.Event)
+}
@@ -133,3 +133,11 @@ func (c *Local) Validators(height *int64) (*ctypes.ResultValidators, error) {
 func (c *Local) Tx(hash []byte) (*ctypes.ResultTx, error) {
 	return core.Tx(c.ctx, hash)
 }
+
+func (c *Local) TxSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	return core.TxSearch(c.ctx, query, page, perPage, orderBy)
+}
+
+func (c *Local) BlockSearch(query string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	return core.BlockSearch(c.ctx, query, page, perPage, orderBy)
+}
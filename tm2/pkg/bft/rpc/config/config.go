@@ -12,6 +12,10 @@ import (
 
 const (
 	defaultConfigDir = "config"
+
+	// DefaultSubscriptionBufferSize is the default number of events buffered
+	// for each subscription.
+	DefaultSubscriptionBufferSize = 100
 )
 
 // RPCConfig defines the configuration options for the Tendermint RPC server
@@ -62,6 +66,18 @@ type RPCConfig struct {
 	// See https://github.com/gnolang/gno/tm2/pkg/bft/issues/3435
 	TimeoutBroadcastTxCommit time.Duration `json:"timeout_broadcast_tx_commit" toml:"timeout_broadcast_tx_commit" comment:"How long to wait for a tx to be committed during /broadcast_tx_commit.\n WARNING: Using a value larger than 10s will result in increasing the\n global HTTP write timeout, which applies to all connections and endpoints.\n See https://github.com/tendermint/classic/issues/3435"`
 
+	// Maximum number of unique clients that can subscribe to events over
+	// the websocket, and of subscriptions per client.
+	// 0 - unlimited.
+	MaxSubscriptionClients    int `json:"max_subscription_clients" toml:"max_subscription_clients" comment:"Maximum number of unique clients that can subscribe to events over the websocket.\n 0 - unlimited."`
+	MaxSubscriptionsPerClient int `json:"max_subscriptions_per_client" toml:"max_subscriptions_per_client" comment:"Maximum number of unique queries a given client can subscribe to.\n 0 - unlimited."`
+
+	// Number of events buffered for each subscription. A subscription whose
+	// client doesn't read its events fast enough to keep the buffer from
+	// overflowing is canceled.
+	// 0 - the default of 100.
+	SubscriptionBufferSize int `json:"subscription_buffer_size" toml:"subscription_buffer_size" comment:"Number of events buffered for each subscription.\n A subscription whose client doesn't read its events fast enough\n to keep the buffer from overflowing is canceled.\n 0 - the default of 100."`
+
 	// Maximum size of request body, in bytes
 	MaxBodyBytes int64 `json:"max_body_bytes" toml:"max_body_bytes" comment:"Maximum size of request body, in bytes"`
 
@@ -100,6 +116,10 @@ func DefaultRPCConfig() *RPCConfig {
 
 		TimeoutBroadcastTxCommit: 10 * time.Second,
 
+		MaxSubscriptionClients:    100,
+		MaxSubscriptionsPerClient: 5,
+		SubscriptionBufferSize:    DefaultSubscriptionBufferSize,
+
 		MaxBodyBytes:   int64(1000000), // 1MB
 		MaxHeaderBytes: 1 << 20,        // same as the net/http default
 
@@ -129,6 +149,15 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.TimeoutBroadcastTxCommit < 0 {
 		return errors.New("timeout_broadcast_tx_commit can't be negative")
 	}
+	if cfg.MaxSubscriptionClients < 0 {
+		return errors.New("max_subscription_clients can't be negative")
+	}
+	if cfg.MaxSubscriptionsPerClient < 0 {
+		return errors.New("max_subscriptions_per_client can't be negative")
+	}
+	if cfg.SubscriptionBufferSize < 0 {
+		return errors.New("subscription_buffer_size can't be negative")
+	}
 	if cfg.MaxBodyBytes < 0 {
 		return errors.New("max_body_bytes can't be negative")
 	}
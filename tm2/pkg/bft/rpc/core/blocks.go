@@ -2,11 +2,14 @@ package core
 
 import (
 	"fmt"
+	"slices"
 
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
 )
 
 // Get block headers for minHeight <= height <= maxHeight.
@@ -63,7 +66,8 @@ import (
 //					}
 //				}
 //			],
-//			"last_height": "5493"
+//			"last_height": "5493",
+//			"lowest_height": "1"
 //		},
 //		"id": "",
 //		"jsonrpc": "2.0"
@@ -71,12 +75,14 @@ import (
 //
 // ```
 //
+// The blocks below the lowest height were pruned.
+//
 // <aside class="notice">Returns at most 20 items.</aside>
 func BlockchainInfo(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.ResultBlockchainInfo, error) {
 	// maximum 20 block metas
 	const limit int64 = 20
 	var err error
-	minHeight, maxHeight, err = filterMinMax(blockStore.Height(), minHeight, maxHeight, limit)
+	minHeight, maxHeight, err = filterMinMax(blockStore.Base(), blockStore.Height(), minHeight, maxHeight, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -89,16 +95,18 @@ func BlockchainInfo(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.
 	}
 
 	return &ctypes.ResultBlockchainInfo{
-		LastHeight: blockStore.Height(),
-		BlockMetas: blockMetas,
+		LastHeight:   blockStore.Height(),
+		LowestHeight: blockStore.Base(),
+		BlockMetas:   blockMetas,
 	}, nil
 }
 
 // error if either low or high are negative or low > high
 // if low is 0 it defaults to 1, if high is 0 it defaults to height (block height).
+// low is raised to base (the lowest block height), if necessary.
 // limit sets the maximum amounts of values included within [low,high] (inclusive),
 // increasing low as necessary.
-func filterMinMax(height, low, high, limit int64) (int64, int64, error) {
+func filterMinMax(base, height, low, high, limit int64) (int64, int64, error) {
 	// filter negatives
 	if low < 0 || high < 0 {
 		return low, high, fmt.Errorf("heights must be non-negative")
@@ -112,7 +120,8 @@ func filterMinMax(height, low, high, limit int64) (int64, int64, error) {
 		high = height
 	}
 
-	// limit high to the height
+	// limit low to the base, and high to the height
+	low = max(base, low)
 	high = min(height, high)
 
 	// limit low to within `limit` of max
@@ -127,6 +136,7 @@ func filterMinMax(height, low, high, limit int64) (int64, int64, error) {
 
 // Get block at a given height.
 // If no height is provided, it will fetch the latest block.
+// If the block was pruned, the error reports the lowest height available.
 //
 // ```shell
 // curl 'localhost:26657/block?height=10'
@@ -240,6 +250,9 @@ func Block(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlock, error)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkNotPruned(height); err != nil {
+		return nil, err
+	}
 
 	blockMeta := blockStore.LoadBlockMeta(height)
 	block := blockStore.LoadBlock(height)
@@ -331,6 +344,9 @@ func Commit(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultCommit, erro
 	if err != nil {
 		return nil, err
 	}
+	if err := checkNotPruned(height); err != nil {
+		return nil, err
+	}
 
 	header := blockStore.LoadBlockMeta(height).Header
 
@@ -404,6 +420,12 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	if err != nil {
 		return nil, err
 	}
+	// The results of InitChain (at height 0) are never pruned
+	if height > 0 {
+		if err := checkNotPruned(height); err != nil {
+			return nil, err
+		}
+	}
 
 	results, err := sm.LoadABCIResponses(stateDB, height)
 	if err != nil {
@@ -417,6 +439,81 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	return res, nil
 }
 
+// BlockSearch allows you to search for the blocks matching a query.
+//
+// The query selects the blocks by their attributes, with conditions joined by
+// AND (see the events/query package): block.height, and <type>.<key> for the
+// attributes of the events emitted at the beginning and the end of the block.
+//
+// The blocks are only indexed by the kv tx event store,
+// and the pruned blocks are not returned.
+//
+// ```shell
+// curl "localhost:26657/block_search?query=\"block.height>=5\"&order_by=\"desc\""
+// ```
+//
+// ```go
+// client := client.NewHTTPClient("tcp://0.0.0.0:26657")
+// res, err := client.BlockSearch("block.height >= 5", 1, 30, "desc")
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                           |
+// |-----------+--------+---------+----------+---------------------------------------|
+// | query     | string | ""      | true     | Query                                 |
+// | page      | int    | 1       | false    | Page number (1-based)                 |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100) |
+// | order_by  | string | "asc"   | false    | Order by height (asc or desc)         |
+func BlockSearch(_ *rpctypes.Context, q string, page, perPage int, orderBy string) (*ctypes.ResultBlockSearch, error) {
+	store, ok := txEventStore.(eventstore.IndexedEventStore)
+	if !ok {
+		return nil, errIndexingDisabled
+	}
+
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	heights, err := store.SearchBlocks(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search blocks, %w", err)
+	}
+
+	// Skip the pruned blocks
+	base := blockStore.Base()
+	heights = slices.DeleteFunc(heights, func(height int64) bool {
+		return height < base
+	})
+
+	totalCount := len(heights)
+
+	heights, err = paginate(heights, page, perPage, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the blocks of the page
+	blocks := make([]*ctypes.ResultBlock, 0, len(heights))
+	for _, height := range heights {
+		block := blockStore.LoadBlock(height)
+		if block == nil {
+			return nil, fmt.Errorf("unable to load block %d", height)
+		}
+
+		blocks = append(blocks, &ctypes.ResultBlock{
+			BlockMeta: blockStore.LoadBlockMeta(height),
+			Block:     block,
+		})
+	}
+
+	return &ctypes.ResultBlockSearch{
+		Blocks:     blocks,
+		TotalCount: totalCount,
+	}, nil
+}
+
 func getHeight(currentHeight int64, heightPtr *int64) (int64, error) {
 	return getHeightWithMin(currentHeight, heightPtr, 1)
 }
@@ -434,3 +531,13 @@ func getHeightWithMin(currentHeight int64, heightPtr *int64, minVal int64) (int6
 	}
 	return currentHeight, nil
 }
+
+// checkNotPruned returns an error if the block at the height was pruned,
+// with the lowest height available
+func checkNotPruned(height int64) error {
+	if base := blockStore.Base(); height < base {
+		return fmt.Errorf("height %d is not available, lowest height is %d", height, base)
+	}
+
+	return nil
+}
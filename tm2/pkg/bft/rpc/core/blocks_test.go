@@ -4,6 +4,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -46,7 +52,7 @@ func TestBlockchainInfo(t *testing.T) {
 
 	for i, c := range cases {
 		caseString := fmt.Sprintf("test %d failed", i)
-		minVal, maxVal, err := filterMinMax(c.height, c.minVal, c.maxVal, c.limit)
+		minVal, maxVal, err := filterMinMax(0, c.height, c.minVal, c.maxVal, c.limit)
 		if c.wantErr {
 			require.Error(t, err, caseString)
 		} else {
@@ -54,6 +60,15 @@ func TestBlockchainInfo(t *testing.T) {
 			require.Equal(t, 1+maxVal-minVal, c.resultLength, caseString)
 		}
 	}
+
+	// The pruned blocks are excluded
+	minVal, maxVal, err := filterMinMax(10, 20, 0, 0, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), minVal)
+	assert.Equal(t, int64(20), maxVal)
+
+	_, _, err = filterMinMax(10, 20, 1, 5, 20)
+	assert.Error(t, err)
 }
 
 func TestGetHeight(t *testing.T) {
@@ -92,3 +107,102 @@ func TestGetHeight(t *testing.T) {
 func int64Ptr(v int64) *int64 {
 	return &v
 }
+
+func TestPrunedBlocksHandlers(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	SetLogger(log.NewTestingLogger(t))
+	SetBlockStore(&mockBlockStore{
+		baseFn: func() int64 {
+			return 10
+		},
+		heightFn: func() int64 {
+			return 20
+		},
+		loadBlockFn: func(h int64) *types.Block {
+			return &types.Block{Header: types.Header{Height: h}}
+		},
+		loadBlockMetaFn: func(h int64) *types.BlockMeta {
+			return &types.BlockMeta{Header: types.Header{Height: h}}
+		},
+	})
+
+	t.Run("blockchain info", func(t *testing.T) {
+		result, err := BlockchainInfo(nil, 0, 0)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(20), result.LastHeight)
+		assert.Equal(t, int64(10), result.LowestHeight)
+		require.Len(t, result.BlockMetas, 11)
+		assert.Equal(t, int64(10), result.BlockMetas[10].Header.Height)
+	})
+
+	t.Run("block", func(t *testing.T) {
+		result, err := Block(nil, int64Ptr(10))
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), result.Block.Height)
+
+		_, err = Block(nil, int64Ptr(9))
+		assert.ErrorContains(t, err, "lowest height is 10")
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		_, err := Commit(nil, int64Ptr(9))
+		assert.ErrorContains(t, err, "lowest height is 10")
+	})
+}
+
+func TestBlockSearchHandler(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	store := kv.NewTxEventStore(memdb.NewMemDB())
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, store.AppendBlock(types.EventNewBlock{
+			Block: &types.Block{Header: types.Header{Height: height}},
+		}))
+	}
+
+	SetBlockStore(&mockBlockStore{
+		heightFn: func() int64 {
+			return 5
+		},
+		loadBlockFn: func(h int64) *types.Block {
+			return &types.Block{Header: types.Header{Height: h}}
+		},
+		loadBlockMetaFn: func(h int64) *types.BlockMeta {
+			return &types.BlockMeta{Header: types.Header{Height: h}}
+		},
+	})
+	SetTxEventStore(store)
+
+	t.Run("blocks found", func(t *testing.T) {
+		result, err := BlockSearch(nil, "block.height > 2", 0, 0, "desc")
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, result.TotalCount)
+		require.Len(t, result.Blocks, 3)
+
+		for i, block := range result.Blocks {
+			assert.Equal(t, int64(5-i), block.Block.Height)
+			assert.Equal(t, int64(5-i), block.BlockMeta.Header.Height)
+		}
+	})
+
+	t.Run("blocks paginated", func(t *testing.T) {
+		result, err := BlockSearch(nil, "block.height > 0", 2, 2, "asc")
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, result.TotalCount)
+		require.Len(t, result.Blocks, 2)
+		assert.Equal(t, int64(3), result.Blocks[0].Block.Height)
+		assert.Equal(t, int64(4), result.Blocks[1].Block.Height)
+	})
+
+	t.Run("indexing disabled", func(t *testing.T) {
+		SetTxEventStore(null.NewNullEventStore())
+		defer SetTxEventStore(store)
+
+		_, err := BlockSearch(nil, "block.height > 0", 0, 0, "")
+		assert.ErrorIs(t, err, errIndexingDisabled)
+	})
+}
@@ -95,6 +95,7 @@ Available endpoints:
 Endpoints that require arguments:
 /abci_query?path=_&data=_&prove=_
 /block?height=_
+/block_search?query=_&page=_&per_page=_&order_by=_
 /blockchain?minHeight=_&maxHeight=_
 /broadcast_tx_async?tx=_
 /broadcast_tx_commit?tx=_
@@ -103,6 +104,7 @@ Endpoints that require arguments:
 /dial_seeds?seeds=_
 /dial_persistent_peers?persistent_peers=_
 /tx?hash=_&prove=_
+/tx_search?query=_&page=_&per_page=_&order_by=_
 /unsafe_start_cpu_profiler?filename=_
 /unsafe_write_heap_profile?filename=_
 ```
@@ -0,0 +1,298 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+)
+
+var (
+	errNoWSConnection     = errors.New("subscriptions are only available over the websocket")
+	errAlreadySubscribed  = errors.New("already subscribed to the query")
+	errNotSubscribed      = errors.New("not subscribed to the query")
+	errTooManyClients     = errors.New("max subscription clients reached")
+	errTooManySubs        = errors.New("max subscriptions per client reached")
+	errSubscriptionCancel = errors.New("subscription was canceled: the client is not reading its events fast enough")
+)
+
+// Subscribe for events via WebSocket.
+//
+// The query selects the events by their attributes, with conditions joined
+// by AND (see the events/query package). Every event has a tm.event
+// attribute, which is NewBlock, Tx or ValidatorSetUpdates. The NewBlock events
+// also have block.height, and the Tx events tx.hash, tx.height and tx.index,
+// along with the <type>.<key> attributes of the events emitted by the block or
+// the transaction (e.g. by std.Emit).
+//
+// The events matching the query are sent to the client as responses to the
+// subscription, with the id of the subscription request followed by
+// "#event". The subscription is canceled, with an error response, if the
+// client doesn't read its events fast enough, and when it disconnects.
+//
+// ```go
+// client, err := client.NewWSClient("ws://0.0.0.0:26657/websocket")
+//
+//	if err != nil {
+//	  // handle error
+//	}
+//
+// defer client.Close()
+// events, err := client.Subscribe(ctx, "tm.event = 'Tx' AND tx.height = 5")
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {},
+//		"id": "",
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description |
+// |-----------+--------+---------+----------+-------------|
+// | query     | string | ""      | true     | Query       |
+func Subscribe(ctx *rpctypes.Context, q string) (*ctypes.ResultSubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errNoWSConnection
+	}
+
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := ctx.RemoteAddr()
+	sub, err := gSubscriptions.add(addr, parsed)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Subscribe to query", "remote", addr, "query", q)
+
+	id := rpctypes.JSONRPCStringID(fmt.Sprintf("%v#event", ctx.JSONReq.ID))
+	go sub.run(ctx.Context(), ctx.WSConn, id)
+
+	return &ctypes.ResultSubscribe{}, nil
+}
+
+// Unsubscribe from events via WebSocket.
+//
+// ```go
+// err := client.Unsubscribe(ctx, "tm.event = 'Tx' AND tx.height = 5")
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {},
+//		"id": "",
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description |
+// |-----------+--------+---------+----------+-------------|
+// | query     | string | ""      | true     | Query       |
+func Unsubscribe(ctx *rpctypes.Context, q string) (*ctypes.ResultUnsubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errNoWSConnection
+	}
+
+	addr := ctx.RemoteAddr()
+	if !gSubscriptions.remove(addr, q) {
+		return nil, errNotSubscribed
+	}
+	logger.Info("Unsubscribe from query", "remote", addr, "query", q)
+
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// Unsubscribe from all events via WebSocket.
+//
+// ```go
+// err := client.UnsubscribeAll(ctx)
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {},
+//		"id": "",
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+func UnsubscribeAll(ctx *rpctypes.Context) (*ctypes.ResultUnsubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errNoWSConnection
+	}
+
+	addr := ctx.RemoteAddr()
+	gSubscriptions.removeAll(addr)
+	logger.Info("Unsubscribe from all queries", "remote", addr)
+
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// ----------------------------------------
+// subscriptions
+
+var gSubscriptions = &subscriptions{
+	clients: make(map[string]map[string]*subscription),
+}
+
+// subscriptions keeps the subscriptions of the websocket clients, by remote
+// address and query.
+type subscriptions struct {
+	mtx     sync.Mutex
+	clients map[string]map[string]*subscription
+}
+
+// add subscribes the client addr to the events matching q, within the limits
+// of the config.
+func (s *subscriptions) add(addr string, q *query.Query) (*subscription, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	subs, ok := s.clients[addr]
+	switch {
+	case !ok && config.MaxSubscriptionClients > 0 && len(s.clients) >= config.MaxSubscriptionClients:
+		return nil, errTooManyClients
+	case config.MaxSubscriptionsPerClient > 0 && len(subs) >= config.MaxSubscriptionsPerClient:
+		return nil, errTooManySubs
+	}
+	if _, ok := subs[q.String()]; ok {
+		return nil, errAlreadySubscribed
+	}
+	if !ok {
+		subs = make(map[string]*subscription)
+		s.clients[addr] = subs
+	}
+
+	bufferSize := config.SubscriptionBufferSize
+	if bufferSize == 0 {
+		bufferSize = cfg.DefaultSubscriptionBufferSize
+	}
+	sub := &subscription{
+		addr:       addr,
+		query:      q,
+		listenerID: fmt.Sprintf("rpc-subscription#%s#%s", addr, q),
+		quit:       make(chan struct{}),
+	}
+	// NOTE: the event switch removes the listener, and closes the channel,
+	// when the buffer is full.
+	sub.events = events.SubscribeFilteredOn(evsw, sub.listenerID, func(ev events.Event) bool {
+		attrs := types.EventAttributes(ev)
+		return attrs != nil && q.Matches(attrs)
+	}, make(chan events.Event, bufferSize))
+	subs[q.String()] = sub
+
+	return sub, nil
+}
+
+// remove cancels the subscription of the client addr to the query q.
+func (s *subscriptions) remove(addr, q string) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sub, ok := s.clients[addr][q]
+	if ok {
+		s.removeLocked(sub)
+	}
+	return ok
+}
+
+// removeAll cancels all the subscriptions of the client addr.
+func (s *subscriptions) removeAll(addr string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, sub := range s.clients[addr] {
+		s.removeLocked(sub)
+	}
+}
+
+// removeSub cancels the subscription, unless it was already.
+func (s *subscriptions) removeSub(sub *subscription) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.clients[sub.addr][sub.query.String()] == sub {
+		s.removeLocked(sub)
+	}
+}
+
+func (s *subscriptions) removeLocked(sub *subscription) {
+	evsw.RemoveListener(sub.listenerID)
+	close(sub.quit)
+
+	subs := s.clients[sub.addr]
+	delete(subs, sub.query.String())
+	if len(subs) == 0 {
+		delete(s.clients, sub.addr)
+	}
+}
+
+// subscription is a subscription of a websocket client to the events
+// matching its query.
+type subscription struct {
+	addr       string
+	query      *query.Query
+	listenerID string
+	events     <-chan events.Event
+	quit       chan struct{} // closed when the subscription is removed
+}
+
+// run writes the events of the subscription to the connection, until the
+// subscription is removed, or the connection is closed.
+func (sub *subscription) run(ctx context.Context, conn rpctypes.WSRPCConnection, id rpctypes.JSONRPCID) {
+	defer gSubscriptions.removeSub(sub)
+
+	for {
+		select {
+		case ev, ok := <-sub.events:
+			if !ok {
+				// The buffer overflowed, the client is too slow.
+				logger.Info("Cancel subscription of slow client", "remote", sub.addr, "query", sub.query)
+				conn.TryWriteRPCResponses(rpctypes.RPCResponses{
+					rpctypes.RPCInternalError(id, errSubscriptionCancel),
+				})
+				return
+			}
+
+			conn.WriteRPCResponses(rpctypes.RPCResponses{
+				rpctypes.NewRPCSuccessResponse(id, &ctypes.ResultEvent{
+					Query: sub.query.String(),
+					Event: ev,
+				}),
+			})
+		case <-sub.quit:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
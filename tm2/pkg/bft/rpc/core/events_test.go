@@ -0,0 +1,232 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// mockWSConn is a websocket connection which passes the responses to a
+// channel
+type mockWSConn struct {
+	addr      string
+	responses chan rpctypes.RPCResponses
+	ctx       context.Context
+}
+
+func newMockWSConn(t *testing.T, addr string, capacity int) *mockWSConn {
+	t.Helper()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	t.Cleanup(cancelFn)
+
+	return &mockWSConn{
+		addr:      addr,
+		responses: make(chan rpctypes.RPCResponses, capacity),
+		ctx:       ctx,
+	}
+}
+
+func (c *mockWSConn) GetRemoteAddr() string    { return c.addr }
+func (c *mockWSConn) Context() context.Context { return c.ctx }
+
+func (c *mockWSConn) WriteRPCResponses(resp rpctypes.RPCResponses) {
+	select {
+	case c.responses <- resp:
+	case <-c.ctx.Done():
+	}
+}
+
+func (c *mockWSConn) TryWriteRPCResponses(resp rpctypes.RPCResponses) bool {
+	select {
+	case c.responses <- resp:
+		return true
+	default:
+		return false
+	}
+}
+
+// setupEvents sets the event switch and the config of the subscriptions
+func setupEvents(t *testing.T, config cfg.RPCConfig) events.EventSwitch {
+	t.Helper()
+
+	sw := events.NewEventSwitch()
+	require.NoError(t, sw.Start())
+	t.Cleanup(func() { sw.Stop() })
+
+	SetEventSwitch(sw)
+	SetConfig(config)
+	SetLogger(log.NewTestingLogger(t))
+
+	gSubscriptions = &subscriptions{
+		clients: make(map[string]map[string]*subscription),
+	}
+
+	// The connections are closed first, wait for their subscriptions
+	// to be removed
+	t.Cleanup(func() {
+		require.Eventually(t, func() bool {
+			return numSubscriptionClients() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	return sw
+}
+
+func numSubscriptionClients() int {
+	gSubscriptions.mtx.Lock()
+	defer gSubscriptions.mtx.Unlock()
+
+	return len(gSubscriptions.clients)
+}
+
+func wsContext(conn *mockWSConn, id string) *rpctypes.Context {
+	return &rpctypes.Context{
+		JSONReq: &rpctypes.RPCRequest{ID: rpctypes.JSONRPCStringID(id)},
+		WSConn:  conn,
+	}
+}
+
+func readEvent(t *testing.T, conn *mockWSConn) rpctypes.RPCResponse {
+	t.Helper()
+
+	select {
+	case resp := <-conn.responses:
+		require.Len(t, resp, 1)
+		return resp[0]
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the event")
+	}
+
+	return rpctypes.RPCResponse{}
+}
+
+func TestSubscribe(t *testing.T) {
+	sw := setupEvents(t, *cfg.DefaultRPCConfig())
+	conn := newMockWSConn(t, "client", 10)
+
+	const query = "tm.event = 'NewBlock' AND block.height > 1"
+	_, err := Subscribe(wsContext(conn, "1"), query)
+	require.NoError(t, err)
+
+	_, err = Subscribe(wsContext(conn, "2"), query)
+	assert.ErrorIs(t, err, errAlreadySubscribed)
+	_, err = Subscribe(wsContext(conn, "3"), "tm.event =")
+	assert.Error(t, err)
+	_, err = Subscribe(&rpctypes.Context{}, query)
+	assert.ErrorIs(t, err, errNoWSConnection)
+
+	// Only the matching events are sent
+	sw.FireEvent(types.EventNewBlock{Block: &types.Block{Header: types.Header{Height: 1}}})
+	sw.FireEvent(types.EventTx{})
+	sw.FireEvent(types.EventNewBlock{Block: &types.Block{Header: types.Header{Height: 2}}})
+
+	resp := readEvent(t, conn)
+	assert.Equal(t, rpctypes.JSONRPCStringID("1#event"), resp.ID)
+	require.Nil(t, resp.Error)
+
+	var result ctypes.ResultEvent
+	require.NoError(t, amino.UnmarshalJSON(resp.Result, &result))
+	assert.Equal(t, query, result.Query)
+	assert.Equal(t, int64(2), result.Event.(types.EventNewBlock).Block.Height)
+
+	// No more events after unsubscribing
+	_, err = Unsubscribe(wsContext(conn, "4"), query)
+	require.NoError(t, err)
+	_, err = Unsubscribe(wsContext(conn, "5"), query)
+	assert.ErrorIs(t, err, errNotSubscribed)
+
+	sw.FireEvent(types.EventNewBlock{Block: &types.Block{Header: types.Header{Height: 3}}})
+	select {
+	case resp := <-conn.responses:
+		t.Fatalf("unexpected response %v", resp)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSubscribeLimits(t *testing.T) {
+	config := *cfg.DefaultRPCConfig()
+	config.MaxSubscriptionClients = 1
+	config.MaxSubscriptionsPerClient = 2
+	setupEvents(t, config)
+
+	conn := newMockWSConn(t, "client", 10)
+	_, err := Subscribe(wsContext(conn, "1"), "tm.event = 'NewBlock'")
+	require.NoError(t, err)
+	_, err = Subscribe(wsContext(conn, "2"), "tm.event = 'Tx'")
+	require.NoError(t, err)
+	_, err = Subscribe(wsContext(conn, "3"), "tm.event = 'ValidatorSetUpdates'")
+	assert.ErrorIs(t, err, errTooManySubs)
+
+	other := newMockWSConn(t, "other", 10)
+	_, err = Subscribe(wsContext(other, "1"), "tm.event = 'NewBlock'")
+	assert.ErrorIs(t, err, errTooManyClients)
+
+	// The subscriptions of the client are freed
+	_, err = UnsubscribeAll(wsContext(conn, "4"))
+	require.NoError(t, err)
+	_, err = Subscribe(wsContext(other, "2"), "tm.event = 'NewBlock'")
+	assert.NoError(t, err)
+}
+
+func TestSubscribeSlowClient(t *testing.T) {
+	config := *cfg.DefaultRPCConfig()
+	config.SubscriptionBufferSize = 1
+	sw := setupEvents(t, config)
+
+	conn := newMockWSConn(t, "client", 20)
+	const query = "tm.event = 'Tx'"
+	_, err := Subscribe(wsContext(conn, "1"), query)
+	require.NoError(t, err)
+
+	for range 10 {
+		sw.FireEvent(types.EventTx{})
+	}
+
+	// The events are not read fast enough, so the buffer overflows
+	// and the subscription is canceled
+	var resp rpctypes.RPCResponse
+	for resp.Error == nil {
+		resp = readEvent(t, conn)
+	}
+	assert.Equal(t, rpctypes.JSONRPCStringID("1#event"), resp.ID)
+	assert.Contains(t, resp.Error.Data, errSubscriptionCancel.Error())
+
+	// The client can subscribe again
+	assert.Eventually(t, func() bool {
+		_, err := Subscribe(wsContext(conn, "2"), query)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSubscribeClosedConnection(t *testing.T) {
+	sw := setupEvents(t, *cfg.DefaultRPCConfig())
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	conn := newMockWSConn(t, "client", 10)
+	conn.ctx = ctx
+
+	const query = "tm.event = 'Tx'"
+	_, err := Subscribe(wsContext(conn, "1"), query)
+	require.NoError(t, err)
+
+	// The subscription is removed with the connection
+	cancelFn()
+	assert.Eventually(t, func() bool {
+		return numSubscriptionClients() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	sw.FireEvent(types.EventTx{})
+	assert.Empty(t, conn.responses)
+}
@@ -22,8 +22,8 @@ import (
 // DeliverTx results.
 //
 // If you want to be sure that the transaction is included in a block, you can
-// subscribe for the result using JSONRPC via a websocket. See
-// https://tendermint.com/docs/app-dev/subscribing-to-events-via-websocket.html
+// subscribe for the result using JSONRPC via a websocket, with the query
+// "tm.event = 'Tx' AND tx.hash = '<HASH>'" (see Subscribe).
 // If you haven't received anything after a couple of blocks, resend it. If the
 // same happens again, send it to some other node. A few reasons why it could
 // happen:
@@ -88,8 +88,8 @@ func BroadcastTxAsync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadca
 // Returns with the response from CheckTx. Does not wait for DeliverTx result.
 //
 // If you want to be sure that the transaction is included in a block, you can
-// subscribe for the result using JSONRPC via a websocket. See
-// https://tendermint.com/docs/app-dev/subscribing-to-events-via-websocket.html
+// subscribe for the result using JSONRPC via a websocket, with the query
+// "tm.event = 'Tx' AND tx.hash = '<HASH>'" (see Subscribe).
 // If you haven't received anything after a couple of blocks, resend it. If the
 // same happens again, send it to some other node. A few reasons why it could
 // happen:
@@ -164,8 +164,7 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 //
 // IMPORTANT: use only for testing and development. In production, use
 // BroadcastTxSync or BroadcastTxAsync. You can subscribe for the transaction
-// result using JSONRPC via a websocket. See
-// https://tendermint.com/docs/app-dev/subscribing-to-events-via-websocket.html
+// result using JSONRPC via a websocket (see Subscribe).
 //
 // CONTRACT: only returns error if mempool.CheckTx() errs or if we timeout
 // waiting for tx to commit.
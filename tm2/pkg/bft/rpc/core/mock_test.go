@@ -3,6 +3,7 @@ package core
 import "github.com/gnolang/gno/tm2/pkg/bft/types"
 
 type (
+	baseDelegate            func() int64
 	heightDelegate          func() int64
 	loadBlockMetaDelegate   func(int64) *types.BlockMeta
 	loadBlockDelegate       func(int64) *types.Block
@@ -10,10 +11,12 @@ type (
 	loadBlockCommitDelegate func(int64) *types.Commit
 	loadSeenCommitDelegate  func(int64) *types.Commit
 
-	saveBlockDelegate func(*types.Block, *types.PartSet, *types.Commit)
+	saveBlockDelegate   func(*types.Block, *types.PartSet, *types.Commit)
+	pruneBlocksDelegate func(int64) (uint64, error)
 )
 
 type mockBlockStore struct {
+	baseFn            baseDelegate
 	heightFn          heightDelegate
 	loadBlockMetaFn   loadBlockMetaDelegate
 	loadBlockFn       loadBlockDelegate
@@ -21,6 +24,15 @@ type mockBlockStore struct {
 	loadBlockCommitFn loadBlockCommitDelegate
 	loadSeenCommitFn  loadSeenCommitDelegate
 	saveBlockFn       saveBlockDelegate
+	pruneBlocksFn     pruneBlocksDelegate
+}
+
+func (m *mockBlockStore) Base() int64 {
+	if m.baseFn != nil {
+		return m.baseFn()
+	}
+
+	return 0
 }
 
 func (m *mockBlockStore) Height() int64 {
@@ -76,3 +88,11 @@ func (m *mockBlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet
 		m.saveBlockFn(block, blockParts, seenCommit)
 	}
 }
+
+func (m *mockBlockStore) PruneBlocks(height int64) (uint64, error) {
+	if m.pruneBlocksFn != nil {
+		return m.pruneBlocksFn(height)
+	}
+
+	return 0, nil
+}
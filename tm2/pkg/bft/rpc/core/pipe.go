@@ -10,6 +10,7 @@ import (
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
@@ -60,6 +61,7 @@ var (
 	consensusState Consensus
 	p2pPeers       peers
 	p2pTransport   transport
+	txEventStore   eventstore.TxEventStore
 
 	// objects
 	pubKey        crypto.PubKey
@@ -82,6 +84,10 @@ func SetBlockStore(bs sm.BlockStore) {
 	blockStore = bs
 }
 
+func SetTxEventStore(store eventstore.TxEventStore) {
+	txEventStore = store
+}
+
 func SetMempool(mem mempl.Mempool) {
 	mempool = mem
 }
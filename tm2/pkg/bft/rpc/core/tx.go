@@ -1,11 +1,20 @@
 package core
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+)
+
+var (
+	errIndexingDisabled = errors.New("transaction indexing is disabled, see the kv tx event store")
+	errInvalidOrderBy   = errors.New("order_by must be asc, desc or empty")
 )
 
 // Tx allows you to query the transaction results. `nil` could mean the
@@ -19,48 +28,156 @@ func Tx(_ *rpctypes.Context, hash []byte) (*ctypes.ResultTx, error) {
 	}
 
 	// Sanity check the block height
-	height, err := getHeight(blockStore.Height(), &resultIndex.BlockNum)
+	if _, err := getHeight(blockStore.Height(), &resultIndex.BlockNum); err != nil {
+		return nil, err
+	}
+
+	return loadTx(resultIndex.BlockNum, resultIndex.TxIndex)
+}
+
+// TxSearch allows you to search for the transactions matching a query.
+//
+// The query selects the transactions by their attributes, with conditions
+// joined by AND (see the events/query package):
+//
+//   - tx.hash, tx.height and tx.index, the location of the transaction
+//   - tx.signer, the bech32 addresses of its signers
+//   - message.type and message.route, the types and routes of its messages
+//   - <type>.<key>, the attributes of the events it emitted (std.Emit),
+//     and <type>.pkg_path, the path of the emitting realm
+//
+// The transactions are only indexed by the kv tx event store,
+// and the transactions of the pruned blocks are not returned.
+//
+// ```shell
+// curl "localhost:26657/tx_search?query=\"tx.height>=5 AND message.type='exec'\"&order_by=\"desc\""
+// ```
+//
+// ```go
+// client := client.NewHTTPClient("tcp://0.0.0.0:26657")
+// res, err := client.TxSearch("tx.signer = 'g1...'", 1, 30, "asc")
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                |
+// |-----------+--------+---------+----------+--------------------------------------------|
+// | query     | string | ""      | true     | Query                                      |
+// | page      | int    | 1       | false    | Page number (1-based)                      |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100)      |
+// | order_by  | string | "asc"   | false    | Order by height and index (asc or desc)    |
+func TxSearch(_ *rpctypes.Context, q string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	store, ok := txEventStore.(eventstore.IndexedEventStore)
+	if !ok {
+		return nil, errIndexingDisabled
+	}
+
+	parsed, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	locs, err := store.SearchTxs(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search transactions, %w", err)
+	}
+
+	// Skip the transactions of the pruned blocks
+	base := blockStore.Base()
+	locs = slices.DeleteFunc(locs, func(loc eventstore.TxLocation) bool {
+		return loc.Height < base
+	})
+
+	totalCount := len(locs)
+
+	locs, err = paginate(locs, page, perPage, orderBy)
 	if err != nil {
 		return nil, err
 	}
 
+	// Load the transactions of the page
+	txs := make([]*ctypes.ResultTx, 0, len(locs))
+	for _, loc := range locs {
+		tx, err := loadTx(loc.Height, loc.Index)
+		if err != nil {
+			return nil, err
+		}
+
+		txs = append(txs, tx)
+	}
+
+	return &ctypes.ResultTxSearch{
+		Txs:        txs,
+		TotalCount: totalCount,
+	}, nil
+}
+
+// loadTx loads the transaction with its result, from the block store
+// and the ABCI responses of the block
+func loadTx(height int64, index uint32) (*ctypes.ResultTx, error) {
 	// Load the block
 	block := blockStore.LoadBlock(height)
+	if block == nil {
+		return nil, fmt.Errorf("unable to load block %d", height)
+	}
+
 	numTxs := len(block.Txs)
 
-	if int(resultIndex.TxIndex) > numTxs || numTxs == 0 {
+	if int(index) >= numTxs {
 		return nil, fmt.Errorf(
 			"unable to get block transaction for block %d, index %d",
-			resultIndex.BlockNum,
-			resultIndex.TxIndex,
+			height,
+			index,
 		)
 	}
 
-	rawTx := block.Txs[resultIndex.TxIndex]
+	rawTx := block.Txs[index]
 
 	// Fetch the block results
-	blockResults, err := sm.LoadABCIResponses(stateDB, resultIndex.BlockNum)
+	blockResults, err := sm.LoadABCIResponses(stateDB, height)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load block results, %w", err)
 	}
 
 	// Grab the block deliver response
-	if len(blockResults.DeliverTxs) < int(resultIndex.TxIndex) {
+	if len(blockResults.DeliverTxs) <= int(index) {
 		return nil, fmt.Errorf(
 			"unable to get deliver result for block %d, index %d",
-			resultIndex.BlockNum,
-			resultIndex.TxIndex,
+			height,
+			index,
 		)
 	}
 
-	deliverResponse := blockResults.DeliverTxs[resultIndex.TxIndex]
+	deliverResponse := blockResults.DeliverTxs[index]
 
 	// Craft the response
 	return &ctypes.ResultTx{
-		Hash:     hash,
-		Height:   resultIndex.BlockNum,
-		Index:    resultIndex.TxIndex,
+		Hash:     rawTx.Hash(),
+		Height:   height,
+		Index:    index,
 		TxResult: deliverResponse,
 		Tx:       rawTx,
 	}, nil
 }
+
+// paginate orders the ascending search results, and returns the given page
+func paginate[T any](results []T, page, perPage int, orderBy string) ([]T, error) {
+	switch orderBy {
+	case "", "asc":
+	case "desc":
+		slices.Reverse(results)
+	default:
+		return nil, errInvalidOrderBy
+	}
+
+	perPage = validatePerPage(perPage)
+	page, err := validatePage(page, perPage, len(results))
+	if err != nil {
+		return nil, err
+	}
+
+	start := min((page-1)*perPage, len(results))
+	end := min(start+perPage, len(results))
+
+	return results[start:end], nil
+}
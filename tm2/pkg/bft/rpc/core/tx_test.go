@@ -6,6 +6,8 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -220,3 +222,104 @@ func TestTxHandler(t *testing.T) {
 		assert.ErrorContains(t, err, "unable to load block results")
 	})
 }
+
+func TestTxSearchHandler(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	var (
+		height = int64(10)
+
+		txs = []types.Tx{
+			types.Tx("tx 1"),
+			types.Tx("tx 2"),
+			types.Tx("tx 3"),
+		}
+
+		responses = &state.ABCIResponses{
+			DeliverTxs: []abci.ResponseDeliverTx{
+				{GasWanted: 1},
+				{GasWanted: 2},
+				{GasWanted: 3},
+			},
+		}
+	)
+
+	// Prepare the DB, block store and event store
+	sdb := memdb.NewMemDB()
+	sdb.Set(state.CalcABCIResponsesKey(height), responses.Bytes())
+
+	store := kv.NewTxEventStore(memdb.NewMemDB())
+	for i, tx := range txs {
+		require.NoError(t, store.Append(types.TxResult{
+			Height:   height,
+			Index:    uint32(i),
+			Tx:       tx,
+			Response: responses.DeliverTxs[i],
+		}))
+	}
+
+	SetStateDB(sdb)
+	SetBlockStore(&mockBlockStore{
+		heightFn: func() int64 {
+			return height
+		},
+		loadBlockFn: func(h int64) *types.Block {
+			require.Equal(t, height, h)
+
+			return &types.Block{
+				Data: types.Data{
+					Txs: txs,
+				},
+			}
+		},
+	})
+	SetTxEventStore(store)
+
+	t.Run("txs found", func(t *testing.T) {
+		result, err := TxSearch(nil, "tx.height = 10", 0, 0, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, result.TotalCount)
+		require.Len(t, result.Txs, 3)
+
+		for i, tx := range result.Txs {
+			assert.Equal(t, txs[i], tx.Tx)
+			assert.Equal(t, txs[i].Hash(), tx.Hash)
+			assert.Equal(t, height, tx.Height)
+			assert.Equal(t, uint32(i), tx.Index)
+			assert.Equal(t, responses.DeliverTxs[i], tx.TxResult)
+		}
+	})
+
+	t.Run("txs paginated in descending order", func(t *testing.T) {
+		result, err := TxSearch(nil, "tx.height = 10", 2, 2, "desc")
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, result.TotalCount)
+		require.Len(t, result.Txs, 1)
+		assert.Equal(t, txs[0], result.Txs[0].Tx)
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		_, err := TxSearch(nil, "tx.height = 10", 3, 2, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid order", func(t *testing.T) {
+		_, err := TxSearch(nil, "tx.height = 10", 0, 0, "height")
+		assert.ErrorIs(t, err, errInvalidOrderBy)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := TxSearch(nil, "tx.height", 0, 0, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("indexing disabled", func(t *testing.T) {
+		SetTxEventStore(null.NewNullEventStore())
+		defer SetTxEventStore(store)
+
+		_, err := TxSearch(nil, "tx.height = 10", 0, 0, "")
+		assert.ErrorIs(t, err, errIndexingDisabled)
+	})
+}
@@ -16,8 +16,9 @@ import (
 
 // List of blocks
 type ResultBlockchainInfo struct {
-	LastHeight int64              `json:"last_height"`
-	BlockMetas []*types.BlockMeta `json:"block_metas"`
+	LastHeight   int64              `json:"last_height"`
+	LowestHeight int64              `json:"lowest_height"`
+	BlockMetas   []*types.BlockMeta `json:"block_metas"`
 }
 
 // Genesis file
@@ -31,6 +32,12 @@ type ResultBlock struct {
 	Block     *types.Block     `json:"block"`
 }
 
+// Result of searching for blocks
+type ResultBlockSearch struct {
+	Blocks     []*ResultBlock `json:"blocks"`
+	TotalCount int            `json:"total_count"`
+}
+
 // Commit and Header
 type ResultCommit struct {
 	types.SignedHeader `json:"signed_header"`
@@ -200,9 +207,12 @@ type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
 	ResultHealth             struct{}
+	ResultSubscribe          struct{}
+	ResultUnsubscribe        struct{}
 )
 
 // Event data from a subscription
 type ResultEvent struct {
+	Query string        `json:"query"`
 	Event types.TMEvent `json:"event"`
 }
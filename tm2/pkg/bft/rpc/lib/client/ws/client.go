@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"log/slog"
+	"strings"
 	"sync"
 
 	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
@@ -22,6 +23,41 @@ var (
 
 type responseCh chan<- types.RPCResponses
 
+// subscriptionIDSuffix is the suffix the server adds to the ID of a
+// subscription request, to send the events of the subscription
+const subscriptionIDSuffix = "#event"
+
+// subscriptionBufferSize is the number of responses buffered for a
+// subscription, which can be received before the subscription request's own
+// response
+const subscriptionBufferSize = 100
+
+// Subscription receives the responses the server sends for a subscription,
+// with the ID of the subscription request followed by "#event"
+type Subscription struct {
+	id        string
+	responses chan types.RPCResponse
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Responses returns the channel of the subscription responses
+func (s *Subscription) Responses() <-chan types.RPCResponse {
+	return s.responses
+}
+
+// Done returns a channel which is closed when the subscription is removed,
+// or the client is closed
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
 // Client is a WebSocket client implementation
 type Client struct {
 	ctx           context.Context
@@ -34,6 +70,9 @@ type Client struct {
 
 	requestMap    map[string]responseCh
 	requestMapMux sync.Mutex
+
+	subscriptions    map[string]*Subscription
+	subscriptionsMux sync.Mutex
 }
 
 // NewClient initializes and creates a new WS RPC client
@@ -45,10 +84,11 @@ func NewClient(rpcURL string, opts ...Option) (*Client, error) {
 	}
 
 	c := &Client{
-		conn:       conn,
-		requestMap: make(map[string]responseCh),
-		backlog:    make(chan any, 1),
-		logger:     log.NewNoopLogger(),
+		conn:          conn,
+		requestMap:    make(map[string]responseCh),
+		subscriptions: make(map[string]*Subscription),
+		backlog:       make(chan any, 1),
+		logger:        log.NewNoopLogger(),
 	}
 
 	ctx, cancelFn := context.WithCancelCause(context.Background())
@@ -153,6 +193,43 @@ func (c *Client) SendBatch(ctx context.Context, requests types.RPCRequests) (typ
 	}
 }
 
+// Subscribe sends the subscription request to the server, and returns its
+// response, with the subscription receiving the responses the server then
+// sends for it. The subscription is registered before the request is sent,
+// so that no response is missed, and removed if the request fails
+func (c *Client) Subscribe(ctx context.Context, request types.RPCRequest) (*Subscription, *types.RPCResponse, error) {
+	sub := &Subscription{
+		id:        request.ID.String() + subscriptionIDSuffix,
+		responses: make(chan types.RPCResponse, subscriptionBufferSize),
+		done:      make(chan struct{}),
+	}
+
+	c.subscriptionsMux.Lock()
+	c.subscriptions[sub.id] = sub
+	c.subscriptionsMux.Unlock()
+
+	response, err := c.SendRequest(ctx, request)
+	if err != nil || response.Error != nil {
+		c.Unsubscribe(sub)
+
+		return nil, response, err
+	}
+
+	return sub, response, nil
+}
+
+// Unsubscribe removes the subscription, which stops receiving responses.
+// It doesn't notify the server
+func (c *Client) Unsubscribe(sub *Subscription) {
+	c.subscriptionsMux.Lock()
+	if c.subscriptions[sub.id] == sub {
+		delete(c.subscriptions, sub.id)
+	}
+	c.subscriptionsMux.Unlock()
+
+	sub.close()
+}
+
 // generateIDHash generates a unique hash from the given IDs
 func generateIDHash(ids ...string) string {
 	hash := fnv.New128()
@@ -231,6 +308,13 @@ func (c *Client) runReadRoutine(ctx context.Context) {
 				continue
 			}
 
+			// Responses of subscriptions have their own listeners
+			if response.ID != nil && strings.HasSuffix(response.ID.String(), subscriptionIDSuffix) {
+				c.handleSubscriptionResponse(ctx, response)
+
+				continue
+			}
+
 			// This is a single response, generate the unique ID
 			responseHash = generateIDHash(response.ID.String())
 			responses = types.RPCResponses{response}
@@ -271,6 +355,27 @@ func (c *Client) runReadRoutine(ctx context.Context) {
 	}
 }
 
+// handleSubscriptionResponse passes the response to its subscription.
+// It blocks until the subscription takes it, so that a slow subscriber
+// slows down the server, which cancels the subscription if it lags too much
+func (c *Client) handleSubscriptionResponse(ctx context.Context, response types.RPCResponse) {
+	c.subscriptionsMux.Lock()
+	sub := c.subscriptions[response.ID.String()]
+	c.subscriptionsMux.Unlock()
+
+	if sub == nil {
+		c.logger.Debug("subscription not found", "id", response.ID)
+
+		return
+	}
+
+	select {
+	case sub.responses <- response:
+	case <-sub.done:
+	case <-ctx.Done():
+	}
+}
+
 // Close closes the WS client
 func (c *Client) Close() error {
 	return c.closeWithCause(nil)
@@ -281,5 +386,13 @@ func (c *Client) Close() error {
 func (c *Client) closeWithCause(err error) error {
 	c.cancelCauseFn(err)
 
+	// Close the subscriptions
+	c.subscriptionsMux.Lock()
+	for id, sub := range c.subscriptions {
+		delete(c.subscriptions, id)
+		sub.close()
+	}
+	c.subscriptionsMux.Unlock()
+
 	return c.conn.Close()
 }
@@ -21,6 +21,11 @@ type (
 		AppHeight  int64
 	}
 
+	AppBlockHeightTooLowError struct {
+		CoreBase  int64
+		AppHeight int64
+	}
+
 	LastStateMismatchError struct {
 		Height int64
 		Core   []byte
@@ -61,6 +66,10 @@ func (e AppBlockHeightTooHighError) Error() string {
 	return fmt.Sprintf("App block height (%d) is higher than core (%d)", e.AppHeight, e.CoreHeight)
 }
 
+func (e AppBlockHeightTooLowError) Error() string {
+	return fmt.Sprintf("App block height (%d) is lower than the core base (%d), the blocks to replay were pruned", e.AppHeight, e.CoreBase)
+}
+
 func (e LastStateMismatchError) Error() string {
 	return fmt.Sprintf("Latest tendermint block (%d) LastAppHash (%X) does not match app's AppHash (%X)", e.Height, e.Core, e.App)
 }
@@ -0,0 +1,253 @@
+package kv
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+var _ eventstore.IndexedEventStore = (*TxEventStore)(nil)
+
+const (
+	EventStoreType = "kv"
+)
+
+// Keys of the transaction attributes indexed along with
+// the event attributes (see types.EventAttributes)
+const (
+	TxSignerKey = "tx.signer"     // the bech32 addresses of the signers
+	MsgTypeKey  = "message.type"  // the types of the messages
+	MsgRouteKey = "message.route" // the routes of the messages
+)
+
+// Prefixes of the index keys
+const (
+	prefixTxIdx  = byte(0x01)
+	prefixBlkIdx = byte(0x02)
+)
+
+// TxEventStore is the implementation of a transaction event store
+// that indexes the transactions and blocks in a key-value database,
+// by their attributes.
+//
+// An attribute of a transaction is stored as the key
+// 0x01 | len(key) key | len(value) value | height | index,
+// and an attribute of a block as 0x02 | len(key) key | len(value) value | height,
+// so the locations with an attribute value are ordered
+type TxEventStore struct {
+	db dbm.DB
+}
+
+// NewTxEventStore creates a new key-value tx event store
+// on the given database, which it owns
+func NewTxEventStore(db dbm.DB) *TxEventStore {
+	return &TxEventStore{
+		db: db,
+	}
+}
+
+// Start starts the key-value transaction event store
+func (t *TxEventStore) Start() error {
+	return nil
+}
+
+// Stop stops the key-value transaction event store, by closing the database
+func (t *TxEventStore) Stop() error {
+	return t.db.Close()
+}
+
+// GetType returns the key-value transaction event store type
+func (t *TxEventStore) GetType() string {
+	return EventStoreType
+}
+
+// Append indexes the transaction by its attributes
+func (t *TxEventStore) Append(result types.TxResult) error {
+	attrs := types.EventAttributes(types.EventTx{Result: result})
+	addTxAttributes(attrs, result.Tx)
+
+	loc := make([]byte, 12)
+	binary.BigEndian.PutUint64(loc, uint64(result.Height))
+	binary.BigEndian.PutUint32(loc[8:], result.Index)
+
+	t.index(prefixTxIdx, attrs, loc)
+
+	return nil
+}
+
+// AppendBlock indexes the block by its attributes
+func (t *TxEventStore) AppendBlock(block types.EventNewBlock) error {
+	if block.Block == nil {
+		return fmt.Errorf("missing block")
+	}
+
+	loc := make([]byte, 8)
+	binary.BigEndian.PutUint64(loc, uint64(block.Block.Height))
+
+	t.index(prefixBlkIdx, types.EventAttributes(block), loc)
+
+	return nil
+}
+
+// SearchTxs returns the locations of the transactions
+// matching the query, in ascending order
+func (t *TxEventStore) SearchTxs(q *query.Query) ([]eventstore.TxLocation, error) {
+	matches := search(t.db, prefixTxIdx, q, 12, func(loc []byte) eventstore.TxLocation {
+		return eventstore.TxLocation{
+			Height: int64(binary.BigEndian.Uint64(loc)),
+			Index:  binary.BigEndian.Uint32(loc[8:]),
+		}
+	})
+
+	slices.SortFunc(matches, func(a, b eventstore.TxLocation) int {
+		if a.Height != b.Height {
+			return cmp.Compare(a.Height, b.Height)
+		}
+
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	return matches, nil
+}
+
+// SearchBlocks returns the heights of the blocks
+// matching the query, in ascending order
+func (t *TxEventStore) SearchBlocks(q *query.Query) ([]int64, error) {
+	matches := search(t.db, prefixBlkIdx, q, 8, func(loc []byte) int64 {
+		return int64(binary.BigEndian.Uint64(loc))
+	})
+
+	slices.Sort(matches)
+
+	return matches, nil
+}
+
+// index writes the attribute keys of the location
+func (t *TxEventStore) index(prefix byte, attrs map[string][]string, loc []byte) {
+	batch := t.db.NewBatch()
+	defer batch.Close()
+
+	for key, values := range attrs {
+		for _, value := range values {
+			batch.Set(indexKey(prefix, key, value, loc), []byte{})
+		}
+	}
+
+	batch.WriteSync()
+}
+
+// search returns the locations whose attributes match all the conditions
+// of the query, in no particular order
+func search[T comparable](
+	db dbm.DB,
+	prefix byte,
+	q *query.Query,
+	locSize int,
+	parseLoc func([]byte) T,
+) []T {
+	var matches map[T]struct{}
+
+	for _, c := range q.Conditions() {
+		condMatches := make(map[T]struct{})
+
+		// Only the values equal to a string operand need to be scanned
+		scanPrefix := attributeKey(prefix, c.Key)
+		exact := false
+		if operand, ok := c.Operand.(string); ok && c.Op == query.OpEqual {
+			scanPrefix = indexKey(prefix, c.Key, operand, nil)
+			exact = true
+		}
+
+		it := dbm.IteratePrefix(db, scanPrefix)
+		for ; it.Valid(); it.Next() {
+			value, loc, ok := parseIndexKey(it.Key(), len(c.Key), locSize)
+			if !ok {
+				continue
+			}
+
+			if !exact && !c.Matches([]string{value}) {
+				continue
+			}
+
+			match := parseLoc(loc)
+			if _, found := matches[match]; matches == nil || found {
+				condMatches[match] = struct{}{}
+			}
+		}
+		it.Close()
+
+		matches = condMatches
+		if len(matches) == 0 {
+			break
+		}
+	}
+
+	result := make([]T, 0, len(matches))
+	for match := range matches {
+		result = append(result, match)
+	}
+
+	return result
+}
+
+// attributeKey returns the prefix of the index keys of the attribute
+func attributeKey(prefix byte, key string) []byte {
+	bz := []byte{prefix}
+	bz = binary.AppendUvarint(bz, uint64(len(key)))
+
+	return append(bz, key...)
+}
+
+// indexKey returns the index key of the attribute value at the location
+func indexKey(prefix byte, key, value string, loc []byte) []byte {
+	bz := attributeKey(prefix, key)
+	bz = binary.AppendUvarint(bz, uint64(len(value)))
+	bz = append(bz, value...)
+
+	return append(bz, loc...)
+}
+
+// parseIndexKey parses the value and location of an index key
+func parseIndexKey(bz []byte, keyLen, locSize int) (string, []byte, bool) {
+	// Skip the prefix and the attribute key
+	bz = bz[1:]
+	_, n := binary.Uvarint(bz)
+	if n <= 0 || len(bz) < n+keyLen {
+		return "", nil, false
+	}
+	bz = bz[n+keyLen:]
+
+	valueLen, n := binary.Uvarint(bz)
+	if n <= 0 || uint64(len(bz)-n) != valueLen+uint64(locSize) {
+		return "", nil, false
+	}
+
+	return string(bz[n : n+int(valueLen)]), bz[n+int(valueLen):], true
+}
+
+// addTxAttributes adds the signers, and the types and routes of the messages
+// of the transaction, if it is a std.Tx
+func addTxAttributes(attrs map[string][]string, rawTx types.Tx) {
+	var tx std.Tx
+	if err := amino.Unmarshal(rawTx, &tx); err != nil {
+		return
+	}
+
+	for _, signer := range tx.GetSigners() {
+		attrs[TxSignerKey] = append(attrs[TxSignerKey], crypto.AddressToBech32(signer))
+	}
+
+	for _, msg := range tx.GetMsgs() {
+		attrs[MsgTypeKey] = append(attrs[MsgTypeKey], msg.Type())
+		attrs[MsgRouteKey] = append(attrs[MsgRouteKey], msg.Route())
+	}
+}
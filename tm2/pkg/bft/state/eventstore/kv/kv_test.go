@@ -0,0 +1,207 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transferEvent is an attributed test event
+type transferEvent struct {
+	to     string
+	amount int
+}
+
+func (transferEvent) AssertABCIEvent() {}
+
+func (e transferEvent) QueryAttributes() map[string][]string {
+	return map[string][]string{
+		"transfer.to":     {e.to},
+		"transfer.amount": {fmt.Sprint(e.amount)},
+	}
+}
+
+// newTestTx generates a test bank send transaction
+func newTestTx(t *testing.T, from crypto.Address) types.Tx {
+	t.Helper()
+
+	tx := std.Tx{
+		Msgs: []std.Msg{
+			bank.MsgSend{
+				FromAddress: from,
+				ToAddress:   crypto.AddressFromPreimage([]byte("to")),
+				Amount:      std.NewCoins(std.NewCoin("ugnot", 10)),
+			},
+		},
+		Signatures: []std.Signature{{}},
+	}
+
+	bz, err := amino.Marshal(tx)
+	require.NoError(t, err)
+
+	return bz
+}
+
+// loc returns the tx location at the given height and index
+func loc(height int64, index uint32) eventstore.TxLocation {
+	return eventstore.TxLocation{Height: height, Index: index}
+}
+
+func TestTxEventStore_SearchTxs(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.AddressFromPreimage([]byte("alice"))
+		bob   = crypto.AddressFromPreimage([]byte("bob"))
+	)
+
+	results := []types.TxResult{
+		{
+			Height: 1,
+			Index:  0,
+			Tx:     newTestTx(t, alice),
+			Response: abci.ResponseDeliverTx{
+				ResponseBase: abci.ResponseBase{
+					Events: []abci.Event{transferEvent{"g1bob", 5}},
+				},
+			},
+		},
+		{
+			Height: 1,
+			Index:  1,
+			Tx:     newTestTx(t, bob),
+			Response: abci.ResponseDeliverTx{
+				ResponseBase: abci.ResponseBase{
+					Events: []abci.Event{
+						transferEvent{"g1alice", 20},
+						abci.EventString("not attributed"),
+					},
+				},
+			},
+		},
+		{
+			Height: 12,
+			Index:  0,
+			Tx:     types.Tx("not a std tx"),
+		},
+	}
+
+	store := NewTxEventStore(memdb.NewMemDB())
+	for _, result := range results {
+		require.NoError(t, store.Append(result))
+	}
+
+	testTable := []struct {
+		query    string
+		expected []eventstore.TxLocation
+	}{
+		{"tm.event = 'Tx'", []eventstore.TxLocation{loc(1, 0), loc(1, 1), loc(12, 0)}},
+		{"tx.height = 1", []eventstore.TxLocation{loc(1, 0), loc(1, 1)}},
+		{"tx.height > 1", []eventstore.TxLocation{loc(12, 0)}},
+		{"tx.height >= 1 AND tx.height < 12", []eventstore.TxLocation{loc(1, 0), loc(1, 1)}},
+		{fmt.Sprintf("tx.hash = '%X'", results[1].Tx.Hash()), []eventstore.TxLocation{loc(1, 1)}},
+		{fmt.Sprintf("tx.signer = '%s'", crypto.AddressToBech32(alice)), []eventstore.TxLocation{loc(1, 0)}},
+		{"message.type = 'send' AND message.route = 'bank'", []eventstore.TxLocation{loc(1, 0), loc(1, 1)}},
+		{"transfer.to = 'g1alice'", []eventstore.TxLocation{loc(1, 1)}},
+		{"transfer.to = 'g1'", nil},
+		{"transfer.to CONTAINS 'g1'", []eventstore.TxLocation{loc(1, 0), loc(1, 1)}},
+		{"transfer.amount > 10", []eventstore.TxLocation{loc(1, 1)}},
+		{"transfer.amount EXISTS AND tx.index = 0", []eventstore.TxLocation{loc(1, 0)}},
+		{"transfer.from EXISTS", nil},
+		{"tx.height = 1 AND tx.height = 12", nil},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			locs, err := store.SearchTxs(query.MustParse(testCase.query))
+			require.NoError(t, err)
+
+			if testCase.expected == nil {
+				assert.Empty(t, locs)
+
+				return
+			}
+
+			assert.Equal(t, testCase.expected, locs)
+		})
+	}
+}
+
+func TestTxEventStore_SearchBlocks(t *testing.T) {
+	t.Parallel()
+
+	store := NewTxEventStore(memdb.NewMemDB())
+	for height := int64(1); height <= 20; height++ {
+		block := types.EventNewBlock{
+			Block: &types.Block{Header: types.Header{Height: height}},
+		}
+		if height%10 == 0 {
+			block.ResultEndBlock.Events = []abci.Event{transferEvent{"g1alice", int(height)}}
+		}
+
+		require.NoError(t, store.AppendBlock(block))
+	}
+
+	assert.Error(t, store.AppendBlock(types.EventNewBlock{}))
+
+	testTable := []struct {
+		query    string
+		expected []int64
+	}{
+		{"block.height = 5", []int64{5}},
+		{"block.height > 17", []int64{18, 19, 20}},
+		{"transfer.to = 'g1alice'", []int64{10, 20}},
+		{"transfer.to = 'g1alice' AND block.height < 15", []int64{10}},
+		{"tx.height EXISTS", nil},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			heights, err := store.SearchBlocks(query.MustParse(testCase.query))
+			require.NoError(t, err)
+
+			if testCase.expected == nil {
+				assert.Empty(t, heights)
+
+				return
+			}
+
+			assert.Equal(t, testCase.expected, heights)
+		})
+	}
+}
+
+func TestIndexKey(t *testing.T) {
+	t.Parallel()
+
+	loc := []byte{1, 2, 3}
+
+	// Values with separators, or prefixes of other values,
+	// are not mistaken for each other
+	for _, value := range []string{"", "a", "a/b", "ab"} {
+		key := indexKey(prefixTxIdx, "key/1", value, loc)
+
+		parsedValue, parsedLoc, ok := parseIndexKey(key, len("key/1"), len(loc))
+		require.True(t, ok)
+		assert.Equal(t, value, parsedValue)
+		assert.Equal(t, loc, parsedLoc)
+	}
+
+	_, _, ok := parseIndexKey(indexKey(prefixTxIdx, "key", "value", loc), 3, 4)
+	assert.False(t, ok)
+}
@@ -1,6 +1,9 @@
 package eventstore
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events/query"
+)
 
 const (
 	StatusOn  = "on"
@@ -22,3 +25,28 @@ type TxEventStore interface {
 	// to the event store
 	Append(result types.TxResult) error
 }
+
+// IndexedEventStore is a transaction event store that indexes
+// the transactions and blocks by their event attributes
+// (see types.EventAttributes), so they can be searched
+type IndexedEventStore interface {
+	TxEventStore
+
+	// AppendBlock analyzes and appends a single block
+	// to the event store
+	AppendBlock(block types.EventNewBlock) error
+
+	// SearchTxs returns the locations of the transactions
+	// matching the query, in ascending order
+	SearchTxs(q *query.Query) ([]TxLocation, error)
+
+	// SearchBlocks returns the heights of the blocks
+	// matching the query, in ascending order
+	SearchBlocks(q *query.Query) ([]int64, error)
+}
+
+// TxLocation is the location of a transaction in the chain
+type TxLocation struct {
+	Height int64  // the height of the block
+	Index  uint32 // the index of the transaction in the block
+}
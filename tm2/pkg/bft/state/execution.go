@@ -36,12 +36,33 @@ type BlockExecutor struct {
 	// manage the mempool lock during commit
 	// and update both with block results after commit.
 	mempool mempl.Mempool
+	evpool  EvidencePool
+
+	// prunes the old blocks, if set
+	pruner *Pruner
 
 	logger *slog.Logger
 }
 
 type BlockExecutorOption func(executor *BlockExecutor)
 
+// WithEvidencePool sets the evidence pool of the BlockExecutor, from which
+// the proposed blocks take their evidence. Without it, the BlockExecutor
+// proposes no evidence.
+func WithEvidencePool(evpool EvidencePool) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.evpool = evpool
+	}
+}
+
+// WithPruner sets the pruner of the BlockExecutor, which is updated
+// after each committed block. Without it, no blocks are pruned.
+func WithPruner(pruner *Pruner) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.pruner = pruner
+	}
+}
+
 // NewBlockExecutor returns a new BlockExecutor with a NopEventBus.
 // Call SetEventBus to provide one.
 func NewBlockExecutor(db dbm.DB, logger *slog.Logger, proxyApp appconn.Consensus, mempool mempl.Mempool, options ...BlockExecutorOption) *BlockExecutor {
@@ -50,6 +71,7 @@ func NewBlockExecutor(db dbm.DB, logger *slog.Logger, proxyApp appconn.Consensus
 		proxyApp: proxyApp,
 		evsw:     events.NilEventSwitch(),
 		mempool:  mempool,
+		evpool:   MockEvidencePool{},
 		logger:   logger,
 	}
 
@@ -68,7 +90,8 @@ func (blockExec *BlockExecutor) SetEventSwitch(evsw events.EventSwitch) {
 	blockExec.evsw = evsw
 }
 
-// CreateProposalBlock calls state.MakeBlock with txs from the mempool.
+// CreateProposalBlock calls state.MakeBlock with txs from the mempool, and
+// evidence from the evidence pool.
 func (blockExec *BlockExecutor) CreateProposalBlock(
 	height int64,
 	state State, commit *types.Commit,
@@ -77,9 +100,23 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	maxDataBytes := state.ConsensusParams.Block.MaxDataBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
 
+	// Fetch a limited amount of valid evidence
+	maxNumEvidence, _ := types.MaxEvidencePerBlock(maxDataBytes)
+	evidence := blockExec.evpool.PendingEvidence(maxNumEvidence)
+
 	txs := blockExec.mempool.ReapMaxBytesMaxGas(maxDataBytes, maxGas)
 
-	return state.MakeBlock(height, txs, commit, proposerAddr)
+	return state.MakeBlock(height, txs, commit, evidence, proposerAddr)
+}
+
+// ValidateBlock validates the given block against the given state, and its
+// evidence against the validator sets of the state database and the evidence
+// pool.
+func (blockExec *BlockExecutor) ValidateBlock(state State, block *types.Block) error {
+	if err := state.ValidateBlock(block); err != nil {
+		return err
+	}
+	return validateEvidence(blockExec.evpool, blockExec.db, state, block)
 }
 
 // ApplyBlock validates the block against the state, executes it against the app,
@@ -88,7 +125,7 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 // from outside this package to process and commit an entire block.
 // It takes a blockID to avoid recomputing the parts hash.
 func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, block *types.Block) (State, error) {
-	if err := state.ValidateBlock(block); err != nil {
+	if err := blockExec.ValidateBlock(state, block); err != nil {
 		return state, InvalidBlockError(err)
 	}
 
@@ -133,11 +170,14 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 	}
 
 	// Lock mempool, commit app state, update mempoool.
-	appHash, err := blockExec.Commit(state, block, abciResponses.DeliverTxs)
+	appHash, retainHeight, err := blockExec.Commit(state, block, abciResponses.DeliverTxs)
 	if err != nil {
 		return state, fmt.Errorf("Commit failed for application: %w", err)
 	}
 
+	// Update evpool with the block and state.
+	blockExec.evpool.Update(block, state)
+
 	fail.Fail() // XXX
 
 	// Update the app hash and save the state.
@@ -146,6 +186,11 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 
 	fail.Fail() // XXX
 
+	// Prune the blocks no longer retained, in the background.
+	if blockExec.pruner != nil {
+		blockExec.pruner.Update(block.Height, retainHeight)
+	}
+
 	// Events are fired after everything else.
 	// NOTE: if we crash between Commit and Save, events wont be fired during replay
 	fireEvents(blockExec.evsw, block, abciResponses)
@@ -155,7 +200,8 @@ func (blockExec *BlockExecutor) ApplyBlock(state State, blockID types.BlockID, b
 
 // Commit locks the mempool, runs the ABCI Commit message, and updates the
 // mempool.
-// It returns the result of calling abci.Commit (the AppHash), and an error.
+// It returns the result of calling abci.Commit (the AppHash and the
+// RetainHeight), and an error.
 // The Mempool must be locked during commit and update because state is
 // typically reset on Commit and old txs must be replayed against committed
 // state before new txs are run in the mempool, lest they be invalid.
@@ -163,7 +209,7 @@ func (blockExec *BlockExecutor) Commit(
 	state State,
 	block *types.Block,
 	deliverTxResponses []abci.ResponseDeliverTx,
-) ([]byte, error) {
+) ([]byte, int64, error) {
 	blockExec.mempool.Lock()
 	defer blockExec.mempool.Unlock()
 
@@ -172,7 +218,7 @@ func (blockExec *BlockExecutor) Commit(
 	err := blockExec.mempool.FlushAppConn()
 	if err != nil {
 		blockExec.logger.Error("Client error during mempool.FlushAppConn", "err", err)
-		return nil, err
+		return nil, 0, err
 	}
 
 	// Commit block, get hash back
@@ -182,7 +228,7 @@ func (blockExec *BlockExecutor) Commit(
 			"Client error during proxyAppConn.CommitSync",
 			"err", err,
 		)
-		return nil, err
+		return nil, 0, err
 	}
 	// ResponseCommit has no error code - just data
 
@@ -202,7 +248,7 @@ func (blockExec *BlockExecutor) Commit(
 		state.ConsensusParams.Block.MaxTxBytes,
 	)
 
-	return res.Data, err
+	return res.Data, res.RetainHeight, err
 }
 
 // ---------------------------------------------------------
@@ -247,6 +293,7 @@ func execBlockOnProxyApp(
 		Hash:           block.Hash(),
 		Header:         block.Header.Copy(),
 		LastCommitInfo: &commitInfo,
+		Violations:     getBeginBlockViolations(block, stateDB),
 	})
 	if err != nil {
 		logger.Error("Error in proxyAppConn.BeginBlock", "err", err)
@@ -317,6 +364,36 @@ func getBeginBlockLastCommitInfo(block *types.Block, stateDB dbm.DB) abci.LastCo
 	return commitInfo
 }
 
+// getBeginBlockViolations returns the violations of the validators proven by
+// the evidence of the block.
+func getBeginBlockViolations(block *types.Block, stateDB dbm.DB) []abci.Violation {
+	var violations []abci.Violation
+	for _, ev := range block.Evidence.Evidence {
+		// NOTE: the evidence was verified against this validator set
+		// by ValidateBlock.
+		valset, err := LoadValidators(stateDB, ev.Height())
+		if err != nil {
+			panic(err) // shouldn't happen
+		}
+		_, val := valset.GetByAddress(ev.Address())
+		if val == nil {
+			panic(fmt.Sprintf("evidence of non-validator %X at height %d", ev.Address(), ev.Height())) // shouldn't happen
+		}
+		violations = append(violations, abci.Violation{
+			Evidence: ev,
+			Validators: []abci.Validator{{
+				Address: val.Address,
+				PubKey:  val.PubKey,
+				Power:   val.VotingPower,
+			}},
+			Height:           ev.Height(),
+			Time:             block.Time,
+			TotalVotingPower: valset.TotalVotingPower(),
+		})
+	}
+	return violations
+}
+
 func validateValidatorUpdates(abciUpdates []abci.ValidatorUpdate,
 	params abci.ValidatorParams,
 ) error {
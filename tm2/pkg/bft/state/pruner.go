@@ -0,0 +1,124 @@
+package state
+
+import (
+	"log/slog"
+	"sync"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/service"
+)
+
+// pruneBatchSize is the number of heights pruned at once
+const pruneBatchSize = 1000
+
+// Pruner is a service which removes, in the background, the blocks
+// (and their ABCI responses) the node no longer needs to keep.
+//
+// After each committed block, the blocks below the retain height are pruned.
+// The retain height keeps the last retainBlocks blocks, or more if the
+// application asks to retain older blocks (with the RetainHeight of Commit).
+type Pruner struct {
+	service.BaseService
+
+	stateDB      dbm.DB
+	blockStore   BlockStore
+	retainBlocks int64
+
+	mtx             sync.Mutex
+	height          int64 // the last committed height
+	appRetainHeight int64 // the RetainHeight of the application, if any
+
+	pruneCh chan struct{}
+}
+
+// NewPruner creates a new pruner, keeping the last retainBlocks (> 0) blocks
+func NewPruner(stateDB dbm.DB, blockStore BlockStore, retainBlocks int64, logger *slog.Logger) *Pruner {
+	p := &Pruner{
+		stateDB:      stateDB,
+		blockStore:   blockStore,
+		retainBlocks: retainBlocks,
+		pruneCh:      make(chan struct{}, 1),
+	}
+	p.BaseService = *service.NewBaseService(logger, "Pruner", p)
+
+	return p
+}
+
+func (p *Pruner) OnStart() error {
+	go p.pruneRoutine()
+
+	return nil
+}
+
+// Update sets the last committed height, and the RetainHeight returned by
+// the application when committing it, and schedules the pruning.
+// It does not block.
+func (p *Pruner) Update(height, appRetainHeight int64) {
+	p.mtx.Lock()
+	p.height = height
+	p.appRetainHeight = appRetainHeight
+	p.mtx.Unlock()
+
+	// The pruning of the previous heights may still be running
+	select {
+	case p.pruneCh <- struct{}{}:
+	default:
+	}
+}
+
+// RetainHeight returns the lowest height to retain,
+// or 0 if no height was committed yet
+func (p *Pruner) RetainHeight() int64 {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.height == 0 {
+		return 0
+	}
+
+	retainHeight := max(p.height-p.retainBlocks+1, 1)
+	if p.appRetainHeight > 0 {
+		retainHeight = min(retainHeight, p.appRetainHeight)
+	}
+
+	return retainHeight
+}
+
+func (p *Pruner) pruneRoutine() {
+	for {
+		select {
+		case <-p.Quit():
+			return
+		case <-p.pruneCh:
+			p.prune()
+		}
+	}
+}
+
+// prune removes the blocks below the retain height, in batches
+func (p *Pruner) prune() {
+	retainHeight := p.RetainHeight()
+
+	for base := p.blockStore.Base(); base > 0 && base < retainHeight; base = p.blockStore.Base() {
+		select {
+		case <-p.Quit():
+			return
+		default:
+		}
+
+		to := min(base+pruneBatchSize, retainHeight)
+
+		// The ABCI responses are pruned first, as the transaction
+		// result indexes are found from the blocks
+		pruneABCIResponses(p.stateDB, base, to, p.blockStore)
+
+		pruned, err := p.blockStore.PruneBlocks(to)
+		if err != nil {
+			p.Logger.Error("unable to prune blocks", "height", to, "err", err)
+
+			return
+		}
+
+		p.Logger.Debug("pruned blocks", "pruned", pruned, "base", to)
+	}
+}
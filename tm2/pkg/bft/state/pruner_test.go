@@ -0,0 +1,99 @@
+package state
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/store"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+func TestPruner_RetainHeight(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name                    string
+		height, appRetainHeight int64
+		expected                int64
+	}{
+		{"nothing committed", 0, 0, 0},
+		{"fewer blocks than retained", 5, 0, 1},
+		{"retained blocks", 30, 0, 21},
+		{"older blocks retained by the app", 30, 15, 15},
+		{"app retain height above the retained blocks", 30, 25, 21},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewPruner(memdb.NewMemDB(), nil, 10, nil)
+			if testCase.height > 0 {
+				p.Update(testCase.height, testCase.appRetainHeight)
+			}
+
+			assert.Equal(t, testCase.expected, p.RetainHeight())
+		})
+	}
+}
+
+func TestPruner_Prune(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stateDB    = memdb.NewMemDB()
+		blockStore = store.NewBlockStore(memdb.NewMemDB())
+
+		// The tx of block 5 is included again in block 25
+		txFn = func(height int64) types.Tx {
+			if height == 25 {
+				height = 5
+			}
+
+			return types.Tx(fmt.Sprintf("tx-%d", height))
+		}
+	)
+
+	for height := int64(1); height <= 30; height++ {
+		tx := txFn(height)
+		block := types.MakeBlock(height, []types.Tx{tx}, new(types.Commit), nil)
+		blockStore.SaveBlock(block, block.MakePartSet(types.BlockPartSizeBytes), new(types.Commit))
+
+		SaveABCIResponses(stateDB, height, NewABCIResponses(block))
+		saveTxResultIndex(stateDB, tx.Hash(), TxResultIndex{BlockNum: height})
+	}
+
+	p := NewPruner(stateDB, blockStore, 10, log.NewTestingLogger(t))
+	require.NoError(t, p.Start())
+	t.Cleanup(func() { p.Stop() })
+
+	p.Update(30, 0)
+	require.Eventually(t, func() bool {
+		return blockStore.Base() == 21
+	}, 5*time.Second, 10*time.Millisecond)
+
+	for height := int64(1); height <= 30; height++ {
+		_, err := LoadABCIResponses(stateDB, height)
+		_, txErr := LoadTxResultIndex(stateDB, txFn(height).Hash())
+
+		if height < 21 && height != 5 {
+			assert.Nil(t, blockStore.LoadBlock(height))
+			assert.Error(t, err)
+			assert.Error(t, txErr)
+
+			continue
+		}
+
+		if height >= 21 {
+			assert.NotNil(t, blockStore.LoadBlock(height))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, txErr)
+	}
+}
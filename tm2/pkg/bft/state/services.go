@@ -14,6 +14,7 @@ import (
 
 // BlockStoreRPC is the block store interface used by the RPC.
 type BlockStoreRPC interface {
+	Base() int64
 	Height() int64
 
 	LoadBlockMeta(height int64) *types.BlockMeta
@@ -28,4 +29,32 @@ type BlockStoreRPC interface {
 type BlockStore interface {
 	BlockStoreRPC
 	SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit)
+	PruneBlocks(height int64) (uint64, error)
 }
+
+//------------------------------------------------------
+// evidence pool
+
+// EvidencePool defines the EvidencePool interface used by the ConsensusState
+// and the BlockExecutor.
+type EvidencePool interface {
+	// PendingEvidence returns up to maxNum of the evidence not yet committed,
+	// or all of it if maxNum is -1.
+	PendingEvidence(maxNum int64) []types.Evidence
+	// AddEvidence verifies, and adds, the evidence to the pool.
+	AddEvidence(types.Evidence) error
+	// Update removes the evidence committed in the block, and the evidence
+	// which is too old for the state.
+	Update(*types.Block, State)
+	// IsCommitted indicates if this evidence was already committed in a block.
+	IsCommitted(types.Evidence) bool
+}
+
+// MockEvidencePool is an empty implementation of EvidencePool, useful for
+// testing, and the default of the BlockExecutor.
+type MockEvidencePool struct{}
+
+func (m MockEvidencePool) PendingEvidence(int64) []types.Evidence { return nil }
+func (m MockEvidencePool) AddEvidence(types.Evidence) error       { return nil }
+func (m MockEvidencePool) Update(*types.Block, State)             {}
+func (m MockEvidencePool) IsCommitted(types.Evidence) bool        { return false }
@@ -118,6 +118,16 @@ func saveState(db dbm.DB, state State, key []byte) {
 	db.SetSync(key, state.Bytes())
 }
 
+// BootstrapState saves a state restored by state sync, along with the
+// validator sets of its last and current heights, which are otherwise
+// saved as the blocks are committed.
+func BootstrapState(db dbm.DB, state State) {
+	height := state.LastBlockHeight
+	saveValidatorsInfo(db, height, height, state.LastValidators)
+	saveValidatorsInfo(db, height+1, height+1, state.Validators)
+	SaveState(db, state)
+}
+
 // ------------------------------------------------------------------------
 
 // ABCIResponses retains the responses
@@ -185,6 +195,32 @@ func SaveABCIResponses(db dbm.DB, height int64, abciResponses *ABCIResponses) {
 	db.Set(CalcABCIResponsesKey(height), abciResponses.Bytes())
 }
 
+// pruneABCIResponses removes the ABCIResponses of the heights in [from, to),
+// and the result indexes of the transactions of the blocks at these heights.
+func pruneABCIResponses(db dbm.DB, from, to int64, blocks BlockStoreRPC) {
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	for height := from; height < to; height++ {
+		batch.Delete(CalcABCIResponsesKey(height))
+
+		block := blocks.LoadBlock(height)
+		if block == nil {
+			continue
+		}
+
+		for _, tx := range block.Txs {
+			// A transaction included again later is indexed at its last height
+			index, err := LoadTxResultIndex(db, tx.Hash())
+			if err == nil && index.BlockNum == height {
+				batch.Delete(CalcTxResultKey(tx.Hash()))
+			}
+		}
+	}
+
+	batch.WriteSync()
+}
+
 // TxResultIndex keeps the result index information for a transaction
 type TxResultIndex struct {
 	BlockNum int64  // the block number the tx was contained in
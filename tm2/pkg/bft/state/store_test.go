@@ -57,6 +57,40 @@ func TestStoreLoadValidators(t *testing.T) {
 	assert.NotZero(t, loadedVals.Size())
 }
 
+func TestBootstrapState(t *testing.T) {
+	t.Parallel()
+
+	stateDB := memdb.NewMemDB()
+
+	lastVals, _ := types.RandValidatorSet(1, 10)
+	vals, _ := types.RandValidatorSet(2, 10)
+	nextVals, _ := types.RandValidatorSet(3, 10)
+
+	state := sm.State{
+		ChainID:                          "test-chain",
+		LastBlockHeight:                  100,
+		LastValidators:                   lastVals,
+		Validators:                       vals,
+		NextValidators:                   nextVals,
+		LastHeightValidatorsChanged:      102,
+		ConsensusParams:                  types.DefaultConsensusParams(),
+		LastHeightConsensusParamsChanged: 101,
+	}
+	sm.BootstrapState(stateDB, state)
+
+	assert.Equal(t, int64(100), sm.LoadState(stateDB).LastBlockHeight)
+
+	for height, expected := range map[int64]*types.ValidatorSet{100: lastVals, 101: vals, 102: nextVals} {
+		loaded, err := sm.LoadValidators(stateDB, height)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Hash(), loaded.Hash(), "height %d", height)
+	}
+
+	params, err := sm.LoadConsensusParams(stateDB, 101)
+	require.NoError(t, err)
+	assert.Equal(t, state.ConsensusParams, params)
+}
+
 func BenchmarkLoadValidators(b *testing.B) {
 	const valSetSize = 100
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
 )
 
 // -----------------------------------------------------
@@ -145,7 +146,6 @@ func (state State) ValidateBlock(block *types.Block) error {
 	return nil
 }
 
-/*
 // VerifyEvidence verifies the evidence fully by checking:
 // - it is sufficiently recent (MaxAge)
 // - it is from a key who was a validator at the given height
@@ -155,7 +155,7 @@ func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error
 	height := state.LastBlockHeight
 
 	evidenceAge := height - evidence.Height()
-	maxAge := state.ConsensusParams.Evidence.MaxAge
+	maxAge := state.EvidenceMaxAge()
 	if evidenceAge > maxAge {
 		return fmt.Errorf("Evidence from height %d is too old. Min height is %d",
 			evidence.Height(), height-maxAge)
@@ -186,4 +186,38 @@ func VerifyEvidence(stateDB dbm.DB, state State, evidence types.Evidence) error
 
 	return nil
 }
-*/
+
+// EvidenceMaxAge returns the max age of the evidence of the consensus params
+// of the state, or the default one for chains started without it.
+func (state State) EvidenceMaxAge() int64 {
+	if params := state.ConsensusParams.Evidence; params != nil {
+		return params.MaxAge
+	}
+	return types.DefaultEvidenceParams().MaxAge
+}
+
+// validateEvidence validates the evidence of the block, which must not exceed
+// the max evidence per block, be verified by VerifyEvidence, and not be
+// committed yet.
+func validateEvidence(evpool EvidencePool, stateDB dbm.DB, state State, block *types.Block) error {
+	// Limit the amount of evidence
+	maxNumEvidence, _ := types.MaxEvidencePerBlock(state.ConsensusParams.Block.MaxDataBytes)
+	numEvidence := int64(len(block.Evidence.Evidence))
+	if numEvidence > maxNumEvidence {
+		return types.NewErrEvidenceOverflow(maxNumEvidence, numEvidence)
+	}
+
+	// Validate all evidence.
+	for i, ev := range block.Evidence.Evidence {
+		if err := VerifyEvidence(stateDB, state, ev); err != nil {
+			return types.NewErrEvidenceInvalid(ev, err)
+		}
+		if block.Evidence.Evidence[:i].Has(ev) {
+			return types.NewErrEvidenceInvalid(ev, errors.New("duplicate evidence in the block"))
+		}
+		if evpool.IsCommitted(ev) {
+			return types.NewErrEvidenceInvalid(ev, errors.New("evidence was already committed"))
+		}
+	}
+	return nil
+}
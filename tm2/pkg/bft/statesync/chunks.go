@@ -0,0 +1,132 @@
+package statesync
+
+import (
+	"context"
+	"sync"
+
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+// chunkWindow is the number of chunks fetched ahead of the applied chunk
+const chunkWindow = 16
+
+// chunk is a fetched snapshot chunk
+type chunk struct {
+	Index  uint32
+	Chunk  []byte
+	Sender p2pTypes.ID
+}
+
+// chunkQueue keeps track of the chunks of the snapshot being restored.
+// The chunks are fetched concurrently, and applied in order
+type chunkQueue struct {
+	mtx sync.Mutex
+
+	chunks   uint32
+	applied  uint32              // the index of the next chunk to apply
+	fetched  map[uint32]*chunk   // fetched chunks, not yet applied
+	fetching map[uint32]struct{} // chunks being fetched
+
+	added chan struct{} // closed when a chunk is added
+}
+
+func newChunkQueue(chunks uint32) *chunkQueue {
+	return &chunkQueue{
+		chunks:   chunks,
+		fetched:  make(map[uint32]*chunk),
+		fetching: make(map[uint32]struct{}),
+		added:    make(chan struct{}),
+	}
+}
+
+// allocate returns the index of the next chunk to fetch, within the fetch
+// window. It returns false if there are no chunks to fetch for now
+func (q *chunkQueue) allocate() (uint32, bool) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for index := q.applied; index < q.chunks && index < q.applied+chunkWindow; index++ {
+		if _, ok := q.fetched[index]; ok {
+			continue
+		}
+		if _, ok := q.fetching[index]; ok {
+			continue
+		}
+
+		q.fetching[index] = struct{}{}
+		return index, true
+	}
+
+	return 0, false
+}
+
+// add adds a fetched chunk
+func (q *chunkQueue) add(c *chunk) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	delete(q.fetching, c.Index)
+	if c.Index < q.applied {
+		return
+	}
+
+	q.fetched[c.Index] = c
+
+	close(q.added)
+	q.added = make(chan struct{})
+}
+
+// release releases a chunk which could not be fetched,
+// so it is allocated again
+func (q *chunkQueue) release(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	delete(q.fetching, index)
+}
+
+// discard discards the given fetched chunk, which is then fetched again.
+// The applied chunks are never discarded
+func (q *chunkQueue) discard(index uint32) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	delete(q.fetched, index)
+}
+
+// next waits for the next chunk to apply
+func (q *chunkQueue) next(ctx context.Context) (*chunk, error) {
+	for {
+		q.mtx.Lock()
+		c, ok := q.fetched[q.applied]
+		added := q.added
+		q.mtx.Unlock()
+
+		if ok {
+			return c, nil
+		}
+
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// markApplied marks the next chunk as applied
+func (q *chunkQueue) markApplied() {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	delete(q.fetched, q.applied)
+	q.applied++
+}
+
+// done returns true if all the chunks are applied
+func (q *chunkQueue) done() bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	return q.applied == q.chunks
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// StateSyncConfig
+
+// StateSyncConfig defines the configuration of state sync, which bootstraps
+// a new node from an application state snapshot of its peers, instead of
+// replaying all the blocks. The snapshot state is verified from a trusted
+// block, with light client verification.
+type StateSyncConfig struct {
+	Enable bool `json:"enable" toml:"enable" comment:"Bootstrap a new node from a state snapshot of its peers, instead of replaying all the blocks.\n Only new nodes, without any state, are state synced"`
+
+	// The trusted block, from which the snapshot state is verified
+	TrustHeight int64  `json:"trust_height" toml:"trust_height" comment:"Height and hash of a trusted block, from which the snapshot state is verified"`
+	TrustHash   string `json:"trust_hash" toml:"trust_hash"`
+
+	TrustPeriod         time.Duration `json:"trust_period" toml:"trust_period" comment:"Period during which the validators of the trusted block are trusted,\n it should be well below the unbonding period"`
+	DiscoveryTime       time.Duration `json:"discovery_time" toml:"discovery_time" comment:"Time spent discovering the snapshots of the peers"`
+	ChunkRequestTimeout time.Duration `json:"chunk_request_timeout" toml:"chunk_request_timeout" comment:"Timeout of a snapshot chunk request, before it is requested from another peer"`
+}
+
+// DefaultStateSyncConfig returns a default configuration for state sync
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:              false,
+		TrustPeriod:         168 * time.Hour, // 1 week
+		DiscoveryTime:       15 * time.Second,
+		ChunkRequestTimeout: 10 * time.Second,
+	}
+}
+
+// TestStateSyncConfig returns a configuration for testing state sync
+func TestStateSyncConfig() *StateSyncConfig {
+	cfg := DefaultStateSyncConfig()
+	cfg.DiscoveryTime = 1 * time.Second
+	cfg.ChunkRequestTimeout = 2 * time.Second
+	return cfg
+}
+
+// TrustHashBytes returns the decoded trusted block hash
+func (cfg *StateSyncConfig) TrustHashBytes() []byte {
+	// Decoding errors are caught by ValidateBasic
+	bz, _ := hex.DecodeString(cfg.TrustHash)
+	return bz
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.DiscoveryTime < 0 {
+		return errors.New("discovery_time can't be negative")
+	}
+	if cfg.ChunkRequestTimeout < 0 {
+		return errors.New("chunk_request_timeout can't be negative")
+	}
+	if !cfg.Enable {
+		return nil
+	}
+
+	// The trusted block is required to verify the snapshots
+	if cfg.TrustHeight <= 0 {
+		return errors.New("trust_height must be set when state sync is enabled")
+	}
+	if bz, err := hex.DecodeString(cfg.TrustHash); err != nil || len(bz) == 0 {
+		return errors.New("trust_hash must be a hex encoded block hash when state sync is enabled")
+	}
+	if cfg.TrustPeriod <= 0 {
+		return errors.New("trust_period must be positive when state sync is enabled")
+	}
+	if cfg.ChunkRequestTimeout == 0 {
+		return errors.New("chunk_request_timeout must be positive when state sync is enabled")
+	}
+	return nil
+}
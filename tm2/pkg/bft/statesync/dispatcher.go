@@ -0,0 +1,105 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+var (
+	errSendFailed     = errors.New("unable to send request to peer")
+	errRequestTimeout = errors.New("request timed out")
+)
+
+type requestKind byte
+
+const (
+	lightBlockRequest requestKind = iota
+	paramsRequest
+	chunkRequest
+)
+
+// requestKey identifies a request sent to a peer,
+// which is matched with the response of the peer
+type requestKey struct {
+	kind   requestKind
+	peerID p2pTypes.ID
+	height int64
+	format uint32
+	index  uint32
+}
+
+// dispatcher sends requests to the peers, and waits for their responses
+type dispatcher struct {
+	mtx     sync.Mutex
+	pending map[requestKey]chan StateSyncMessage
+}
+
+func newDispatcher() *dispatcher {
+	return &dispatcher{
+		pending: make(map[requestKey]chan StateSyncMessage),
+	}
+}
+
+// request sends the request message to the peer, and returns its response
+func (d *dispatcher) request(
+	ctx context.Context,
+	peer p2p.PeerConn,
+	chID byte,
+	key requestKey,
+	msg StateSyncMessage,
+	timeout time.Duration,
+) (StateSyncMessage, error) {
+	ch := make(chan StateSyncMessage, 1)
+
+	d.mtx.Lock()
+	d.pending[key] = ch
+	d.mtx.Unlock()
+
+	defer func() {
+		d.mtx.Lock()
+		delete(d.pending, key)
+		d.mtx.Unlock()
+	}()
+
+	if !peer.Send(chID, amino.MustMarshalAny(msg)) {
+		return nil, errSendFailed
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-timer.C:
+		return nil, errRequestTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// respond delivers the response of a peer to its pending request.
+// It returns false if there is no such request
+func (d *dispatcher) respond(key requestKey, msg StateSyncMessage) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	ch, ok := d.pending[key]
+	if !ok {
+		return false
+	}
+
+	select {
+	case ch <- msg:
+	default:
+		// Duplicate response
+	}
+
+	return true
+}
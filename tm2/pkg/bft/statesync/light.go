@@ -0,0 +1,244 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+var (
+	errTrustHashMismatch = errors.New("light block does not match the trusted hash")
+	errTrustExpired      = errors.New("trusted light block is outside of the trust period")
+	errNoTrustedBlock    = errors.New("no trusted light block")
+)
+
+// LightBlock is a signed block header, with the validator set which signed it.
+// Light blocks are verified from a trusted block, without the block contents.
+type LightBlock struct {
+	Header       *types.Header
+	Commit       *types.Commit
+	ValidatorSet *types.ValidatorSet
+}
+
+// Height returns the height of the light block
+func (lb *LightBlock) Height() int64 {
+	if lb.Header == nil {
+		return 0
+	}
+	return lb.Header.Height
+}
+
+// ValidateBasic checks that the commit and the validator set
+// are the ones of the header.
+//
+// NOTE: This does not check the signatures of the commit.
+func (lb *LightBlock) ValidateBasic(chainID string) error {
+	if lb.ValidatorSet == nil || len(lb.ValidatorSet.Validators) == 0 {
+		return errors.New("light block is missing its validator set")
+	}
+
+	sh := types.SignedHeader{Header: lb.Header, Commit: lb.Commit}
+	if err := sh.ValidateBasic(chainID); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(lb.Header.ValidatorsHash, lb.ValidatorSet.Hash()) {
+		return fmt.Errorf("validator set %X does not match the header validators hash %X",
+			lb.ValidatorSet.Hash(), lb.Header.ValidatorsHash)
+	}
+	return nil
+}
+
+// lightBlockFetcher fetches the (unverified) light block at the given height
+type lightBlockFetcher func(ctx context.Context, height int64) (*LightBlock, error)
+
+// verifier verifies light blocks from a trusted light block.
+//
+// Later blocks are verified forward, skipping blocks as long as more than
+// 2/3 of the trusted validators signed the later block, and bisecting
+// otherwise. Earlier blocks are verified backward, following the hash links
+// of the headers.
+type verifier struct {
+	chainID     string
+	trustPeriod time.Duration
+	fetch       lightBlockFetcher
+	now         func() time.Time
+
+	verified map[int64]*LightBlock
+}
+
+func newVerifier(chainID string, trustPeriod time.Duration, fetch lightBlockFetcher) *verifier {
+	return &verifier{
+		chainID:     chainID,
+		trustPeriod: trustPeriod,
+		fetch:       fetch,
+		now:         time.Now,
+		verified:    make(map[int64]*LightBlock),
+	}
+}
+
+// trusted returns true if the verifier has a trusted light block
+func (v *verifier) trusted() bool {
+	return len(v.verified) > 0
+}
+
+// trust fetches the light block at the given height, and trusts it
+// if it matches the given hash
+func (v *verifier) trust(ctx context.Context, height int64, hash []byte) error {
+	lb, err := v.lightBlock(ctx, height)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(lb.Header.Hash(), hash) {
+		return fmt.Errorf("%w: got %X, expected %X", errTrustHashMismatch, lb.Header.Hash(), hash)
+	}
+
+	// The validators of the trusted block must have signed it
+	if err := lb.ValidatorSet.VerifyCommit(v.chainID, lb.Commit.BlockID, height, lb.Commit); err != nil {
+		return fmt.Errorf("invalid trusted light block commit: %w", err)
+	}
+
+	if expires := lb.Header.Time.Add(v.trustPeriod); !expires.After(v.now()) {
+		return fmt.Errorf("%w: block time %v, trust period %v", errTrustExpired, lb.Header.Time, v.trustPeriod)
+	}
+
+	v.verified[height] = lb
+
+	return nil
+}
+
+// verify returns the verified light block at the given height
+func (v *verifier) verify(ctx context.Context, height int64) (*LightBlock, error) {
+	if !v.trusted() {
+		return nil, errNoTrustedBlock
+	}
+
+	if lb, ok := v.verified[height]; ok {
+		return lb, nil
+	}
+
+	// Start from the closest verified blocks
+	var below, above *LightBlock
+	for h, lb := range v.verified {
+		if h < height && (below == nil || h > below.Height()) {
+			below = lb
+		}
+		if h > height && (above == nil || h < above.Height()) {
+			above = lb
+		}
+	}
+
+	if below != nil {
+		return v.verifyForward(ctx, below, height)
+	}
+
+	return v.verifyBackward(ctx, above, height)
+}
+
+// verifyForward verifies the light block at the given height,
+// from an earlier trusted light block
+func (v *verifier) verifyForward(ctx context.Context, trusted *LightBlock, height int64) (*LightBlock, error) {
+	target, err := v.lightBlock(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []*LightBlock{target}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+
+		err := v.verifyNext(trusted, next)
+		switch {
+		case err == nil:
+			v.verified[next.Height()] = next
+			trusted = next
+			pending = pending[:len(pending)-1]
+		case types.IsErrTooMuchChange(err) && next.Height() > trusted.Height()+1:
+			// The validator set changed too much to skip to the block,
+			// verify a block in between first
+			lb, err := v.lightBlock(ctx, (trusted.Height()+next.Height())/2)
+			if err != nil {
+				return nil, err
+			}
+
+			pending = append(pending, lb)
+		default:
+			return nil, fmt.Errorf("unable to verify light block %d: %w", next.Height(), err)
+		}
+	}
+
+	return target, nil
+}
+
+// verifyNext verifies the untrusted light block from a trusted earlier one
+func (v *verifier) verifyNext(trusted, untrusted *LightBlock) error {
+	if !untrusted.Header.Time.After(trusted.Header.Time) {
+		return fmt.Errorf("block time %v is not after the trusted block time %v",
+			untrusted.Header.Time, trusted.Header.Time)
+	}
+
+	var (
+		height  = untrusted.Height()
+		commit  = untrusted.Commit
+		blockID = commit.BlockID
+	)
+
+	if height != trusted.Height()+1 {
+		// More than 2/3 of the trusted validators must have signed the block
+		return trusted.ValidatorSet.VerifyFutureCommit(untrusted.ValidatorSet, v.chainID, blockID, height, commit)
+	}
+
+	// Adjacent blocks are linked, and signed by the expected validators
+	if !bytes.Equal(untrusted.Header.LastBlockID.Hash, trusted.Header.Hash()) {
+		return errors.New("header is not linked to the trusted header")
+	}
+	if !bytes.Equal(untrusted.Header.ValidatorsHash, trusted.Header.NextValidatorsHash) {
+		return errors.New("validators do not match the trusted next validators")
+	}
+
+	return untrusted.ValidatorSet.VerifyCommit(v.chainID, blockID, height, commit)
+}
+
+// verifyBackward verifies the light block at the given height,
+// from a later trusted light block
+func (v *verifier) verifyBackward(ctx context.Context, trusted *LightBlock, height int64) (*LightBlock, error) {
+	for trusted.Height() > height {
+		lb, err := v.lightBlock(ctx, trusted.Height()-1)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(lb.Header.Hash(), trusted.Header.LastBlockID.Hash) {
+			return nil, fmt.Errorf("light block %d is not linked to the trusted header", lb.Height())
+		}
+
+		v.verified[lb.Height()] = lb
+		trusted = lb
+	}
+
+	return trusted, nil
+}
+
+// lightBlock fetches the light block at the given height,
+// and checks it is consistent
+func (v *verifier) lightBlock(ctx context.Context, height int64) (*LightBlock, error) {
+	lb, err := v.fetch(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	if lb.Height() != height {
+		return nil, fmt.Errorf("got light block %d, expected %d", lb.Height(), height)
+	}
+
+	if err := lb.ValidateBasic(v.chainID); err != nil {
+		return nil, fmt.Errorf("invalid light block %d: %w", height, err)
+	}
+
+	return lb, nil
+}
@@ -0,0 +1,313 @@
+package statesync
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/random"
+)
+
+const testChainID = "test-chain"
+
+var testGenesisTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// testValidators is a validator set, with its private validators
+type testValidators struct {
+	set      *types.ValidatorSet
+	privVals []types.PrivValidator
+}
+
+func newTestValidators(count int) testValidators {
+	set, privVals := types.RandValidatorSet(count, 10)
+
+	return testValidators{
+		set:      set,
+		privVals: privVals,
+	}
+}
+
+// makeLightChain generates a chain of light blocks, starting at height 1.
+// The block at each height is signed by the validators at the same index
+func makeLightChain(t *testing.T, vals []testValidators, params abci.ConsensusParams) map[int64]*LightBlock {
+	t.Helper()
+
+	var (
+		chain  = make(map[int64]*LightBlock, len(vals))
+		lastID types.BlockID
+	)
+
+	for i, v := range vals {
+		height := int64(i + 1)
+
+		next := v
+		if i+1 < len(vals) {
+			next = vals[i+1]
+		}
+
+		header := &types.Header{
+			ChainID:            testChainID,
+			Height:             height,
+			Time:               testGenesisTime.Add(time.Duration(height) * time.Second),
+			LastBlockID:        lastID,
+			ValidatorsHash:     v.set.Hash(),
+			NextValidatorsHash: next.set.Hash(),
+			ConsensusHash:      params.Hash(),
+			AppHash:            fmt.Appendf(nil, "app-hash-%d", height),
+		}
+
+		blockID := types.BlockID{
+			Hash: header.Hash(),
+			PartsHeader: types.PartSetHeader{
+				Total: 1,
+				Hash:  random.RandBytes(32),
+			},
+		}
+
+		chain[height] = &LightBlock{
+			Header:       header,
+			Commit:       signCommit(t, v, blockID, header),
+			ValidatorSet: v.set,
+		}
+		lastID = blockID
+	}
+
+	return chain
+}
+
+// signCommit returns the commit of the block, signed by all the validators
+func signCommit(t *testing.T, vals testValidators, blockID types.BlockID, header *types.Header) *types.Commit {
+	t.Helper()
+
+	voteSet := types.NewVoteSet(testChainID, header.Height, 0, types.PrecommitType, vals.set)
+
+	for _, privVal := range vals.privVals {
+		addr := privVal.GetPubKey().Address()
+		index, _ := vals.set.GetByAddress(addr)
+
+		vote := &types.Vote{
+			ValidatorAddress: addr,
+			ValidatorIndex:   index,
+			Height:           header.Height,
+			Round:            0,
+			Type:             types.PrecommitType,
+			BlockID:          blockID,
+			Timestamp:        header.Time,
+		}
+		require.NoError(t, privVal.SignVote(testChainID, vote))
+
+		_, err := voteSet.AddVote(vote)
+		require.NoError(t, err)
+	}
+
+	return voteSet.MakeCommit()
+}
+
+// repeatValidators returns the validator set repeated for count heights
+func repeatValidators(v testValidators, count int) []testValidators {
+	vals := make([]testValidators, count)
+	for i := range vals {
+		vals[i] = v
+	}
+
+	return vals
+}
+
+// newTestVerifier returns a verifier fetching from the chain,
+// which records the fetched heights
+func newTestVerifier(chain map[int64]*LightBlock, fetched *[]int64) *verifier {
+	v := newVerifier(testChainID, time.Hour, func(_ context.Context, height int64) (*LightBlock, error) {
+		if fetched != nil {
+			*fetched = append(*fetched, height)
+		}
+
+		lb, ok := chain[height]
+		if !ok {
+			return nil, errNoLightBlock
+		}
+
+		return lb, nil
+	})
+	v.now = func() time.Time { return testGenesisTime.Add(time.Minute) }
+
+	return v
+}
+
+func TestVerifier_Trust(t *testing.T) {
+	t.Parallel()
+
+	chain := makeLightChain(t, repeatValidators(newTestValidators(3), 3), abci.ConsensusParams{})
+
+	t.Run("valid trusted block", func(t *testing.T) {
+		t.Parallel()
+
+		v := newTestVerifier(chain, nil)
+
+		_, err := v.verify(context.Background(), 2)
+		assert.ErrorIs(t, err, errNoTrustedBlock)
+
+		require.NoError(t, v.trust(context.Background(), 2, chain[2].Header.Hash()))
+		assert.True(t, v.trusted())
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		v := newTestVerifier(chain, nil)
+
+		err := v.trust(context.Background(), 2, chain[1].Header.Hash())
+		assert.ErrorIs(t, err, errTrustHashMismatch)
+		assert.False(t, v.trusted())
+	})
+
+	t.Run("expired trusted block", func(t *testing.T) {
+		t.Parallel()
+
+		v := newTestVerifier(chain, nil)
+		v.now = func() time.Time { return testGenesisTime.Add(2 * time.Hour) }
+
+		err := v.trust(context.Background(), 2, chain[2].Header.Hash())
+		assert.ErrorIs(t, err, errTrustExpired)
+	})
+
+	t.Run("unavailable trusted block", func(t *testing.T) {
+		t.Parallel()
+
+		v := newTestVerifier(chain, nil)
+
+		err := v.trust(context.Background(), 10, chain[2].Header.Hash())
+		assert.ErrorIs(t, err, errNoLightBlock)
+	})
+}
+
+func TestVerifier_Forward(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain   = makeLightChain(t, repeatValidators(newTestValidators(4), 20), abci.ConsensusParams{})
+		fetched []int64
+		v       = newTestVerifier(chain, &fetched)
+	)
+
+	require.NoError(t, v.trust(context.Background(), 1, chain[1].Header.Hash()))
+
+	lb, err := v.verify(context.Background(), 20)
+	require.NoError(t, err)
+	assert.Equal(t, chain[20], lb)
+
+	// The validators didn't change, so the intermediate blocks are skipped
+	assert.Equal(t, []int64{1, 20}, fetched)
+}
+
+func TestVerifier_Bisection(t *testing.T) {
+	t.Parallel()
+
+	// The validators change entirely at height 6
+	var (
+		vals    = append(repeatValidators(newTestValidators(3), 5), repeatValidators(newTestValidators(3), 5)...)
+		chain   = makeLightChain(t, vals, abci.ConsensusParams{})
+		fetched []int64
+		v       = newTestVerifier(chain, &fetched)
+	)
+
+	require.NoError(t, v.trust(context.Background(), 1, chain[1].Header.Hash()))
+
+	lb, err := v.verify(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, chain[10], lb)
+
+	// The validator change was verified from the adjacent block
+	assert.Contains(t, fetched, int64(5))
+	assert.Contains(t, fetched, int64(6))
+}
+
+func TestVerifier_Backward(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chain = makeLightChain(t, repeatValidators(newTestValidators(3), 10), abci.ConsensusParams{})
+		v     = newTestVerifier(chain, nil)
+	)
+
+	require.NoError(t, v.trust(context.Background(), 10, chain[10].Header.Hash()))
+
+	lb, err := v.verify(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, chain[5], lb)
+
+	// The blocks in between are verified as well
+	for height := int64(5); height <= 10; height++ {
+		assert.Contains(t, v.verified, height)
+	}
+}
+
+func TestVerifier_Invalid(t *testing.T) {
+	t.Parallel()
+
+	var (
+		honest = newTestValidators(3)
+		chain  = makeLightChain(t, repeatValidators(honest, 10), abci.ConsensusParams{})
+	)
+
+	t.Run("tampered header", func(t *testing.T) {
+		t.Parallel()
+
+		tampered := *chain[10].Header
+		tampered.AppHash = []byte("forged")
+
+		forged := maps.Clone(chain)
+		forged[10] = &LightBlock{
+			Header:       &tampered,
+			Commit:       chain[10].Commit,
+			ValidatorSet: chain[10].ValidatorSet,
+		}
+
+		v := newTestVerifier(forged, nil)
+		require.NoError(t, v.trust(context.Background(), 1, chain[1].Header.Hash()))
+
+		_, err := v.verify(context.Background(), 10)
+		assert.ErrorContains(t, err, "invalid light block")
+	})
+
+	t.Run("forged chain", func(t *testing.T) {
+		t.Parallel()
+
+		// Other validators sign a forged chain, from height 6
+		forgedChain := makeLightChain(
+			t,
+			append(repeatValidators(honest, 5), repeatValidators(newTestValidators(3), 5)...),
+			abci.ConsensusParams{},
+		)
+
+		forged := maps.Clone(chain)
+		for height := int64(6); height <= 10; height++ {
+			forged[height] = forgedChain[height]
+		}
+
+		v := newTestVerifier(forged, nil)
+		require.NoError(t, v.trust(context.Background(), 1, chain[1].Header.Hash()))
+
+		_, err := v.verify(context.Background(), 10)
+		assert.ErrorContains(t, err, "unable to verify light block")
+	})
+
+	t.Run("backward unlinked block", func(t *testing.T) {
+		t.Parallel()
+
+		forged := maps.Clone(chain)
+		forged[9] = makeLightChain(t, repeatValidators(honest, 9), abci.ConsensusParams{})[9]
+
+		v := newTestVerifier(forged, nil)
+		require.NoError(t, v.trust(context.Background(), 10, chain[10].Header.Hash()))
+
+		_, err := v.verify(context.Background(), 5)
+		assert.ErrorContains(t, err, "not linked")
+	})
+}
@@ -0,0 +1,174 @@
+package statesync
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+)
+
+// StateSyncMessage is a message sent or received by the Reactor.
+type StateSyncMessage interface {
+	ValidateBasic() error
+}
+
+func decodeMsg(bz []byte) (msg StateSyncMessage, err error) {
+	if len(bz) > maxMsgSize {
+		return msg, fmt.Errorf("msg exceeds max size (%d > %d)", len(bz), maxMsgSize)
+	}
+	err = amino.Unmarshal(bz, &msg)
+	return
+}
+
+// -------------------------------------
+
+// snapshotsRequestMessage requests the recent snapshots of a peer
+type snapshotsRequestMessage struct{}
+
+func (m *snapshotsRequestMessage) ValidateBasic() error {
+	return nil
+}
+
+func (m *snapshotsRequestMessage) String() string {
+	return "[snapshotsRequestMessage]"
+}
+
+// snapshotsResponseMessage advertises a snapshot of the peer,
+// one message is sent per snapshot
+type snapshotsResponseMessage struct {
+	Snapshot *abci.Snapshot
+}
+
+func (m *snapshotsResponseMessage) ValidateBasic() error {
+	if m.Snapshot == nil {
+		return errors.New("missing snapshot")
+	}
+	if m.Snapshot.Height <= 0 {
+		return errors.New("invalid snapshot height")
+	}
+	if m.Snapshot.Chunks == 0 {
+		return errors.New("snapshot has no chunks")
+	}
+	return nil
+}
+
+func (m *snapshotsResponseMessage) String() string {
+	return fmt.Sprintf("[snapshotsResponseMessage %v/%v]", m.Snapshot.Height, m.Snapshot.Format)
+}
+
+// -------------------------------------
+
+type chunkRequestMessage struct {
+	Height int64
+	Format uint32
+	Index  uint32
+}
+
+func (m *chunkRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid snapshot height")
+	}
+	return nil
+}
+
+func (m *chunkRequestMessage) String() string {
+	return fmt.Sprintf("[chunkRequestMessage %v/%v/%v]", m.Height, m.Format, m.Index)
+}
+
+// chunkResponseMessage contains the requested chunk,
+// or is Missing if the peer doesn't have it
+type chunkResponseMessage struct {
+	Height  int64
+	Format  uint32
+	Index   uint32
+	Chunk   []byte
+	Missing bool
+}
+
+func (m *chunkResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid snapshot height")
+	}
+	if m.Missing && len(m.Chunk) > 0 {
+		return errors.New("missing chunk with contents")
+	}
+	return nil
+}
+
+func (m *chunkResponseMessage) String() string {
+	return fmt.Sprintf("[chunkResponseMessage %v/%v/%v missing=%v]", m.Height, m.Format, m.Index, m.Missing)
+}
+
+// -------------------------------------
+
+type lightBlockRequestMessage struct {
+	Height int64
+}
+
+func (m *lightBlockRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	return nil
+}
+
+func (m *lightBlockRequestMessage) String() string {
+	return fmt.Sprintf("[lightBlockRequestMessage %v]", m.Height)
+}
+
+// lightBlockResponseMessage contains the requested light block,
+// which is nil if the peer doesn't have it
+type lightBlockResponseMessage struct {
+	Height     int64
+	LightBlock *LightBlock
+}
+
+func (m *lightBlockResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	if m.LightBlock != nil && m.LightBlock.Height() != m.Height {
+		return fmt.Errorf("light block height mismatch: %v vs %v", m.LightBlock.Height(), m.Height)
+	}
+	return nil
+}
+
+func (m *lightBlockResponseMessage) String() string {
+	return fmt.Sprintf("[lightBlockResponseMessage %v]", m.Height)
+}
+
+// -------------------------------------
+
+type paramsRequestMessage struct {
+	Height int64
+}
+
+func (m *paramsRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	return nil
+}
+
+func (m *paramsRequestMessage) String() string {
+	return fmt.Sprintf("[paramsRequestMessage %v]", m.Height)
+}
+
+// paramsResponseMessage contains the consensus params at the requested height,
+// which are nil if the peer doesn't have them
+type paramsResponseMessage struct {
+	Height          int64
+	ConsensusParams *abci.ConsensusParams
+}
+
+func (m *paramsResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("invalid height")
+	}
+	return nil
+}
+
+func (m *paramsResponseMessage) String() string {
+	return fmt.Sprintf("[paramsResponseMessage %v]", m.Height)
+}
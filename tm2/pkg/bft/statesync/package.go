@@ -0,0 +1,26 @@
+package statesync
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync",
+	"tm",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	abci.Package,
+	types.Package,
+).WithTypes(
+	&snapshotsRequestMessage{}, "SnapshotsRequest",
+	&snapshotsResponseMessage{}, "SnapshotsResponse",
+	&chunkRequestMessage{}, "ChunkRequest",
+	&chunkResponseMessage{}, "ChunkResponse",
+	&lightBlockRequestMessage{}, "LightBlockRequest",
+	&lightBlockResponseMessage{}, "LightBlockResponse",
+	&paramsRequestMessage{}, "ParamsRequest",
+	&paramsResponseMessage{}, "ParamsResponse",
+	LightBlock{}, "LightBlock",
+))
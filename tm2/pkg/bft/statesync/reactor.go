@@ -0,0 +1,313 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+)
+
+const (
+	// SnapshotChannel is a channel for snapshot discovery
+	SnapshotChannel = byte(0x60)
+	// ChunkChannel is a channel for snapshot chunks
+	ChunkChannel = byte(0x61)
+	// LightBlockChannel is a channel for light blocks and consensus params,
+	// used to verify the snapshots
+	LightBlockChannel = byte(0x62)
+
+	maxMsgSize = 16 * 1024 * 1024 // 16MB, big enough for snapshot chunks
+
+	// recentSnapshots is the number of recent snapshots advertised to peers
+	recentSnapshots = 10
+)
+
+var errAlreadySyncing = errors.New("state sync already in progress")
+
+// Reactor serves the snapshots of the application, along with the light
+// blocks needed to verify them, and restores the application state
+// from the snapshots of the peers when syncing (state sync).
+type Reactor struct {
+	p2p.BaseReactor
+
+	cfg        *config.StateSyncConfig
+	conn       appconn.Snapshot
+	connQuery  appconn.Query
+	stateDB    dbm.DB
+	blockStore sm.BlockStoreRPC
+
+	mtx    sync.RWMutex
+	syncer *syncer // set while syncing
+}
+
+// NewReactor returns a new state sync reactor
+func NewReactor(
+	cfg *config.StateSyncConfig,
+	conn appconn.Snapshot,
+	connQuery appconn.Query,
+	stateDB dbm.DB,
+	blockStore sm.BlockStoreRPC,
+) *Reactor {
+	ssR := &Reactor{
+		cfg:        cfg,
+		conn:       conn,
+		connQuery:  connQuery,
+		stateDB:    stateDB,
+		blockStore: blockStore,
+	}
+	ssR.BaseReactor = *p2p.NewBaseReactor("StateSyncReactor", ssR)
+	return ssR
+}
+
+// GetChannels implements Reactor
+func (ssR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  SnapshotChannel,
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: maxMsgSize,
+		},
+		{
+			ID:                  ChunkChannel,
+			Priority:            3,
+			SendQueueCapacity:   4,
+			RecvMessageCapacity: maxMsgSize,
+		},
+		{
+			ID:                  LightBlockChannel,
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: maxMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor by requesting the snapshots of the peer,
+// when syncing
+func (ssR *Reactor) AddPeer(peer p2p.PeerConn) {
+	if ssR.getSyncer() == nil {
+		return
+	}
+
+	peer.Send(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+}
+
+// RemovePeer implements Reactor by removing the snapshots of the peer
+func (ssR *Reactor) RemovePeer(peer p2p.PeerConn, _ any) {
+	if syncer := ssR.getSyncer(); syncer != nil {
+		syncer.snapshots.removePeer(peer.ID())
+	}
+}
+
+// Receive implements Reactor
+func (ssR *Reactor) Receive(chID byte, src p2p.PeerConn, msgBytes []byte) {
+	msg, err := decodeMsg(msgBytes)
+	if err != nil {
+		ssR.Logger.Error("Error decoding message", "src", src, "chId", chID, "msg", msg, "err", err)
+		ssR.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	if err = msg.ValidateBasic(); err != nil {
+		ssR.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
+		ssR.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	ssR.Logger.Debug("Receive", "src", src, "chID", chID, "msg", msg)
+
+	switch msg := msg.(type) {
+	case *snapshotsRequestMessage:
+		ssR.respondSnapshots(src)
+	case *chunkRequestMessage:
+		ssR.respondChunk(src, msg)
+	case *lightBlockRequestMessage:
+		ssR.respondLightBlock(src, msg)
+	case *paramsRequestMessage:
+		ssR.respondParams(src, msg)
+	case *snapshotsResponseMessage:
+		if syncer := ssR.getSyncer(); syncer != nil {
+			if syncer.snapshots.add(src.ID(), msg.Snapshot) {
+				ssR.Logger.Info("Discovered snapshot", "peer", src.ID(), "height", msg.Snapshot.Height, "format", msg.Snapshot.Format)
+			}
+		}
+	case *chunkResponseMessage:
+		ssR.respond(requestKey{
+			kind:   chunkRequest,
+			peerID: src.ID(),
+			height: msg.Height,
+			format: msg.Format,
+			index:  msg.Index,
+		}, msg)
+	case *lightBlockResponseMessage:
+		ssR.respond(requestKey{kind: lightBlockRequest, peerID: src.ID(), height: msg.Height}, msg)
+	case *paramsResponseMessage:
+		ssR.respond(requestKey{kind: paramsRequest, peerID: src.ID(), height: msg.Height}, msg)
+	default:
+		ssR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+	}
+}
+
+// Sync restores the application state from the snapshots of the peers,
+// verified from the trusted block of the configuration. It returns the state
+// and the verified light block at the snapshot height, from which the node
+// keeps syncing.
+func (ssR *Reactor) Sync(ctx context.Context, genesis sm.State) (sm.State, *LightBlock, error) {
+	ssR.mtx.Lock()
+	if ssR.syncer != nil {
+		ssR.mtx.Unlock()
+		return sm.State{}, nil, errAlreadySyncing
+	}
+
+	syncer := newSyncer(ssR.Logger, ssR.cfg, ssR.conn, ssR.connQuery, genesis, ssR.peers)
+	ssR.syncer = syncer
+	ssR.mtx.Unlock()
+
+	defer func() {
+		ssR.mtx.Lock()
+		ssR.syncer = nil
+		ssR.mtx.Unlock()
+	}()
+
+	discover := func() {
+		ssR.Switch.Broadcast(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+	}
+
+	ssR.Logger.Info("Starting state sync", "trust_height", ssR.cfg.TrustHeight)
+
+	state, lb, err := syncer.sync(ctx, discover)
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+
+	ssR.Logger.Info("State sync complete", "height", state.LastBlockHeight, "app_hash", state.AppHash)
+
+	return state, lb, nil
+}
+
+func (ssR *Reactor) getSyncer() *syncer {
+	ssR.mtx.RLock()
+	defer ssR.mtx.RUnlock()
+
+	return ssR.syncer
+}
+
+func (ssR *Reactor) peers() []p2p.PeerConn {
+	return ssR.Switch.Peers().List()
+}
+
+// respond delivers a response to the pending request of the syncer
+func (ssR *Reactor) respond(key requestKey, msg StateSyncMessage) {
+	syncer := ssR.getSyncer()
+	if syncer == nil || !syncer.dispatcher.respond(key, msg) {
+		ssR.Logger.Debug("Received unsolicited response", "peer", key.peerID, "msg", msg)
+	}
+}
+
+// respondSnapshots advertises the recent snapshots of the application
+func (ssR *Reactor) respondSnapshots(src p2p.PeerConn) {
+	res, err := ssR.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		ssR.Logger.Error("Unable to list snapshots", "err", err)
+		return
+	}
+	if res.Error != nil {
+		ssR.Logger.Error("Unable to list snapshots", "err", res.Error)
+		return
+	}
+
+	for i, snapshot := range res.Snapshots {
+		if i == recentSnapshots {
+			break
+		}
+
+		src.Send(SnapshotChannel, amino.MustMarshalAny(&snapshotsResponseMessage{Snapshot: snapshot}))
+	}
+}
+
+// respondChunk sends the requested snapshot chunk, or a missing chunk
+// response if the application doesn't have it
+func (ssR *Reactor) respondChunk(src p2p.PeerConn, msg *chunkRequestMessage) {
+	res, err := ssR.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		ssR.Logger.Error("Unable to load snapshot chunk", "height", msg.Height, "chunk", msg.Index, "err", err)
+		return
+	}
+
+	resp := &chunkResponseMessage{
+		Height:  msg.Height,
+		Format:  msg.Format,
+		Index:   msg.Index,
+		Chunk:   res.Chunk,
+		Missing: res.Error != nil || len(res.Chunk) == 0,
+	}
+	if resp.Missing {
+		resp.Chunk = nil
+	}
+
+	src.Send(ChunkChannel, amino.MustMarshalAny(resp))
+}
+
+// respondLightBlock sends the requested light block, if the node has it
+func (ssR *Reactor) respondLightBlock(src p2p.PeerConn, msg *lightBlockRequestMessage) {
+	src.Send(LightBlockChannel, amino.MustMarshalAny(&lightBlockResponseMessage{
+		Height:     msg.Height,
+		LightBlock: ssR.loadLightBlock(msg.Height),
+	}))
+}
+
+// loadLightBlock loads the light block at the given height,
+// or returns nil if the node doesn't have it
+func (ssR *Reactor) loadLightBlock(height int64) *LightBlock {
+	meta := ssR.blockStore.LoadBlockMeta(height)
+	if meta == nil {
+		return nil
+	}
+
+	// The canonical commit is saved with the next block,
+	// use the seen commit for the latest block
+	commit := ssR.blockStore.LoadBlockCommit(height)
+	if commit == nil {
+		commit = ssR.blockStore.LoadSeenCommit(height)
+	}
+	if commit == nil {
+		return nil
+	}
+
+	vals, err := sm.LoadValidators(ssR.stateDB, height)
+	if err != nil {
+		return nil
+	}
+
+	return &LightBlock{
+		Header:       &meta.Header,
+		Commit:       commit,
+		ValidatorSet: vals,
+	}
+}
+
+// respondParams sends the consensus params at the requested height,
+// if the node has them
+func (ssR *Reactor) respondParams(src p2p.PeerConn, msg *paramsRequestMessage) {
+	resp := &paramsResponseMessage{Height: msg.Height}
+
+	if params, err := sm.LoadConsensusParams(ssR.stateDB, msg.Height); err == nil {
+		resp.ConsensusParams = &params
+	}
+
+	src.Send(LightBlockChannel, amino.MustMarshalAny(resp))
+}
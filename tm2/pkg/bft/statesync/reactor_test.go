@@ -0,0 +1,319 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abcicli "github.com/gnolang/gno/tm2/pkg/bft/abci/client"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	p2pTesting "github.com/gnolang/gno/tm2/pkg/internal/p2p"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pcfg "github.com/gnolang/gno/tm2/pkg/p2p/config"
+)
+
+// testP2PConfig returns a configuration for testing the peer-to-peer layer
+func testP2PConfig() *p2pcfg.P2PConfig {
+	cfg := p2pcfg.DefaultP2PConfig()
+	cfg.ListenAddress = "tcp://0.0.0.0:26656"
+	cfg.FlushThrottleTimeout = 10 * time.Millisecond
+
+	return cfg
+}
+
+// snapshotApp serves snapshots, and restores them
+type snapshotApp struct {
+	abci.BaseApplication
+
+	mtx       sync.Mutex
+	snapshots []*abci.Snapshot
+	chunks    map[int64][][]byte
+
+	// restore state
+	offered  *abci.Snapshot
+	appHash  []byte
+	restored []byte
+	height   int64
+}
+
+func (app *snapshotApp) Info(abci.RequestInfo) (res abci.ResponseInfo) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	res.LastBlockHeight = app.height
+	res.LastBlockAppHash = app.appHash
+	return
+}
+
+func (app *snapshotApp) ListSnapshots(abci.RequestListSnapshots) abci.ResponseListSnapshots {
+	return abci.ResponseListSnapshots{Snapshots: app.snapshots}
+}
+
+func (app *snapshotApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk) {
+	chunks := app.chunks[req.Height]
+	if int(req.Chunk) < len(chunks) {
+		res.Chunk = chunks[req.Chunk]
+	}
+	return
+}
+
+func (app *snapshotApp) OfferSnapshot(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	app.offered = req.Snapshot
+	app.appHash = req.AppHash
+	app.restored = nil
+	return
+}
+
+func (app *snapshotApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+	app.mtx.Lock()
+	defer app.mtx.Unlock()
+
+	app.restored = append(app.restored, req.Chunk...)
+	if req.Index == app.offered.Chunks-1 {
+		app.height = app.offered.Height
+	}
+	return
+}
+
+// fakeBlockStore serves the blocks of a light chain
+type fakeBlockStore struct {
+	sm.BlockStoreRPC
+
+	chain map[int64]*LightBlock
+}
+
+func (bs *fakeBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
+	lb, ok := bs.chain[height]
+	if !ok {
+		return nil
+	}
+
+	return &types.BlockMeta{BlockID: lb.Commit.BlockID, Header: *lb.Header}
+}
+
+func (bs *fakeBlockStore) LoadBlockCommit(height int64) *types.Commit {
+	lb, ok := bs.chain[height]
+	if !ok {
+		return nil
+	}
+
+	return lb.Commit
+}
+
+func (bs *fakeBlockStore) LoadSeenCommit(int64) *types.Commit {
+	return nil
+}
+
+// newTestConns returns the snapshot and query connections of the app
+func newTestConns(app abci.Application) (appconn.Snapshot, appconn.Query) {
+	client := abcicli.NewLocalClient(new(sync.Mutex), app)
+
+	return appconn.NewSnapshot(client), appconn.NewQuery(client)
+}
+
+func TestReactor_Sync(t *testing.T) {
+	t.Parallel()
+
+	const height = 5
+
+	var (
+		vals   = newTestValidators(1)
+		params = types.DefaultConsensusParams()
+		chain  = makeLightChain(t, repeatValidators(vals, height+2), params)
+		chunks = [][]byte{[]byte("chunk-0"), []byte("chunk-1"), []byte("chunk-2")}
+
+		// The serving node is at the snapshot height
+		servingState = sm.State{
+			ChainID:                          testChainID,
+			LastBlockHeight:                  height,
+			LastValidators:                   vals.set,
+			Validators:                       vals.set,
+			NextValidators:                   vals.set,
+			LastHeightValidatorsChanged:      height + 2,
+			ConsensusParams:                  params,
+			LastHeightConsensusParamsChanged: height + 1,
+		}
+		stateDB = memdb.NewMemDB()
+	)
+
+	sm.BootstrapState(stateDB, servingState)
+
+	// The higher snapshot can't be verified, and is rejected
+	servingApp := &snapshotApp{
+		snapshots: []*abci.Snapshot{
+			{Height: height + 1, Format: 1, Chunks: 1, Hash: []byte("unverifiable")},
+			{Height: height, Format: 1, Chunks: uint32(len(chunks)), Hash: []byte("snapshot")},
+		},
+		chunks: map[int64][][]byte{
+			height + 1: {[]byte("unverifiable")},
+			height:     chunks,
+		},
+	}
+	servingConn, servingQuery := newTestConns(servingApp)
+
+	syncingApp := &snapshotApp{}
+	syncingConn, syncingQuery := newTestConns(syncingApp)
+
+	cfg := config.TestStateSyncConfig()
+	cfg.Enable = true
+	cfg.TrustHeight = height
+	cfg.TrustHash = hex.EncodeToString(chain[height].Header.Hash())
+	cfg.TrustPeriod = 100 * 365 * 24 * time.Hour
+	cfg.DiscoveryTime = 200 * time.Millisecond
+
+	reactors := []*Reactor{
+		NewReactor(cfg, servingConn, servingQuery, stateDB, &fakeBlockStore{chain: chain}),
+		NewReactor(cfg, syncingConn, syncingQuery, memdb.NewMemDB(), &fakeBlockStore{}),
+	}
+
+	options := make(map[int][]p2p.SwitchOption)
+	for i, reactor := range reactors {
+		reactor.SetLogger(log.NewNoopLogger().With("node", i))
+		options[i] = []p2p.SwitchOption{p2p.WithReactor("STATESYNC", reactor)}
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelFn()
+
+	p2pTesting.MakeConnectedPeers(t, ctx, p2pTesting.TestingConfig{
+		Count:         len(reactors),
+		P2PCfg:        testP2PConfig(),
+		SwitchOptions: options,
+		Channels:      []byte{SnapshotChannel, ChunkChannel, LightBlockChannel},
+	})
+	t.Cleanup(func() {
+		for _, r := range reactors {
+			assert.NoError(t, r.Stop())
+		}
+	})
+
+	genesis := sm.State{ChainID: testChainID}
+
+	state, lb, err := reactors[1].Sync(ctx, genesis)
+	require.NoError(t, err)
+
+	// The app restored the verified snapshot
+	assert.Equal(t, bytes.Join(chunks, nil), syncingApp.restored)
+	assert.Equal(t, chain[height+1].Header.AppHash, syncingApp.appHash)
+
+	// The state is built from the verified light blocks
+	assert.Equal(t, int64(height), state.LastBlockHeight)
+	assert.Equal(t, chain[height].Commit.BlockID, state.LastBlockID)
+	assert.Equal(t, chain[height].Header.Time, state.LastBlockTime)
+	assert.Equal(t, chain[height+1].Header.AppHash, state.AppHash)
+	assert.Equal(t, vals.set.Hash(), state.Validators.Hash())
+	assert.Equal(t, params, state.ConsensusParams)
+	assert.Equal(t, chain[height].Header.Hash(), lb.Header.Hash())
+	assert.Equal(t, chain[height].Commit.Hash(), lb.Commit.Hash())
+
+	// Only one sync can run at a time, the sync is done
+	assert.Nil(t, reactors[1].getSyncer())
+}
+
+func TestReactor_SyncCancel(t *testing.T) {
+	t.Parallel()
+
+	// No peers, so no snapshots are discovered
+	cfg := config.TestStateSyncConfig()
+	cfg.Enable = true
+	cfg.TrustHeight = 1
+	cfg.TrustHash = hex.EncodeToString([]byte("hash"))
+	cfg.DiscoveryTime = 50 * time.Millisecond
+
+	conn, query := newTestConns(&snapshotApp{})
+	reactor := NewReactor(cfg, conn, query, memdb.NewMemDB(), &fakeBlockStore{})
+
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	p2pTesting.MakeConnectedPeers(t, ctx, p2pTesting.TestingConfig{
+		Count:         1,
+		P2PCfg:        testP2PConfig(),
+		SwitchOptions: map[int][]p2p.SwitchOption{0: {p2p.WithReactor("STATESYNC", reactor)}},
+		Channels:      []byte{SnapshotChannel, ChunkChannel, LightBlockChannel},
+	})
+	t.Cleanup(func() {
+		assert.NoError(t, reactor.Stop())
+	})
+
+	syncCtx, syncCancelFn := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer syncCancelFn()
+
+	_, _, err := reactor.Sync(syncCtx, sm.State{ChainID: testChainID})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMessages_ValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		msg   StateSyncMessage
+		valid bool
+	}{
+		{"snapshots request", &snapshotsRequestMessage{}, true},
+		{"snapshot response", &snapshotsResponseMessage{Snapshot: &abci.Snapshot{Height: 1, Chunks: 1}}, true},
+		{"missing snapshot", &snapshotsResponseMessage{}, false},
+		{"snapshot without chunks", &snapshotsResponseMessage{Snapshot: &abci.Snapshot{Height: 1}}, false},
+		{"chunk request", &chunkRequestMessage{Height: 1}, true},
+		{"chunk request invalid height", &chunkRequestMessage{}, false},
+		{"chunk response", &chunkResponseMessage{Height: 1, Chunk: []byte("chunk")}, true},
+		{"missing chunk with contents", &chunkResponseMessage{Height: 1, Chunk: []byte("chunk"), Missing: true}, false},
+		{"light block request", &lightBlockRequestMessage{Height: 1}, true},
+		{"light block request invalid height", &lightBlockRequestMessage{Height: -1}, false},
+		{"light block response", &lightBlockResponseMessage{Height: 1, LightBlock: &LightBlock{Header: &types.Header{Height: 1}}}, true},
+		{"light block height mismatch", &lightBlockResponseMessage{Height: 1, LightBlock: &LightBlock{Header: &types.Header{Height: 2}}}, false},
+		{"params request", &paramsRequestMessage{Height: 1}, true},
+		{"params response invalid height", &paramsResponseMessage{}, false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.msg.ValidateBasic()
+			if testCase.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMessages_Encoding(t *testing.T) {
+	t.Parallel()
+
+	chain := makeLightChain(t, repeatValidators(newTestValidators(2), 2), types.DefaultConsensusParams())
+
+	for _, msg := range []StateSyncMessage{
+		&snapshotsRequestMessage{},
+		&chunkResponseMessage{Height: 1, Index: 2, Chunk: []byte("chunk")},
+		&lightBlockResponseMessage{Height: 2, LightBlock: chain[2]},
+	} {
+		t.Run(fmt.Sprintf("%T", msg), func(t *testing.T) {
+			t.Parallel()
+
+			decoded, err := decodeMsg(amino.MustMarshalAny(msg))
+			require.NoError(t, err)
+			assert.Equal(t, amino.MustMarshalAny(msg), amino.MustMarshalAny(decoded))
+		})
+	}
+}
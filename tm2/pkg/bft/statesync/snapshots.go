@@ -0,0 +1,152 @@
+package statesync
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+// snapshotKey uniquely identifies a snapshot
+type snapshotKey struct {
+	height int64
+	format uint32
+	hash   string
+}
+
+func keyOf(snapshot *abci.Snapshot) snapshotKey {
+	return snapshotKey{
+		height: snapshot.Height,
+		format: snapshot.Format,
+		hash:   string(snapshot.Hash),
+	}
+}
+
+// snapshotPool keeps track of the snapshots advertised by the peers
+type snapshotPool struct {
+	mtx sync.Mutex
+
+	snapshots map[snapshotKey]*abci.Snapshot
+	peers     map[snapshotKey]map[p2pTypes.ID]struct{} // the peers which have the snapshot
+
+	rejected      map[snapshotKey]struct{}
+	rejectedPeers map[p2pTypes.ID]struct{}
+}
+
+func newSnapshotPool() *snapshotPool {
+	return &snapshotPool{
+		snapshots:     make(map[snapshotKey]*abci.Snapshot),
+		peers:         make(map[snapshotKey]map[p2pTypes.ID]struct{}),
+		rejected:      make(map[snapshotKey]struct{}),
+		rejectedPeers: make(map[p2pTypes.ID]struct{}),
+	}
+}
+
+// add adds the snapshot advertised by the given peer.
+// It returns true if the snapshot is new
+func (p *snapshotPool) add(peerID p2pTypes.ID, snapshot *abci.Snapshot) bool {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := keyOf(snapshot)
+	if _, rejected := p.rejected[key]; rejected {
+		return false
+	}
+	if _, rejected := p.rejectedPeers[peerID]; rejected {
+		return false
+	}
+
+	if _, ok := p.peers[key]; !ok {
+		p.peers[key] = make(map[p2pTypes.ID]struct{})
+	}
+	p.peers[key][peerID] = struct{}{}
+
+	if _, ok := p.snapshots[key]; ok {
+		return false
+	}
+	p.snapshots[key] = snapshot
+
+	return true
+}
+
+// best returns the best snapshot to restore, or nil if there is none.
+// Higher snapshots are preferred, then the snapshots with the most peers
+func (p *snapshotPool) best() *abci.Snapshot {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	candidates := make([]*abci.Snapshot, 0, len(p.snapshots))
+	for key, snapshot := range p.snapshots {
+		if len(p.peers[key]) > 0 {
+			candidates = append(candidates, snapshot)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Height != b.Height {
+			return a.Height > b.Height
+		}
+		return len(p.peers[keyOf(a)]) > len(p.peers[keyOf(b)])
+	})
+
+	return candidates[0]
+}
+
+// peersOf returns the peers which have the snapshot, in random order
+func (p *snapshotPool) peersOf(snapshot *abci.Snapshot) []p2pTypes.ID {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	peers := make([]p2pTypes.ID, 0, len(p.peers[keyOf(snapshot)]))
+	for peerID := range p.peers[keyOf(snapshot)] {
+		peers = append(peers, peerID)
+	}
+
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	return peers
+}
+
+// reject removes the snapshot, which is never added again
+func (p *snapshotPool) reject(snapshot *abci.Snapshot) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	key := keyOf(snapshot)
+	p.rejected[key] = struct{}{}
+
+	delete(p.snapshots, key)
+	delete(p.peers, key)
+}
+
+// rejectPeer removes the snapshots of the peer, which are never added again
+func (p *snapshotPool) rejectPeer(peerID p2pTypes.ID) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.rejectedPeers[peerID] = struct{}{}
+	p.removePeerLocked(peerID)
+}
+
+// removePeer removes the snapshots of a disconnected peer
+func (p *snapshotPool) removePeer(peerID p2pTypes.ID) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.removePeerLocked(peerID)
+}
+
+func (p *snapshotPool) removePeerLocked(peerID p2pTypes.ID) {
+	for _, peers := range p.peers {
+		delete(peers, peerID)
+	}
+}
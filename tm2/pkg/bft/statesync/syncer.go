@@ -0,0 +1,462 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+const (
+	// chunkFetchers is the number of chunks fetched concurrently
+	chunkFetchers = 4
+
+	// lightBlockTimeout is the timeout of a light block or params request
+	lightBlockTimeout = 10 * time.Second
+
+	// allocateInterval is the interval at which the chunk fetchers
+	// check for new chunks to fetch, when the fetch window is full
+	allocateInterval = 100 * time.Millisecond
+)
+
+var (
+	// errRejectSnapshot is returned when the snapshot can't be restored,
+	// in which case another snapshot is restored
+	errRejectSnapshot = errors.New("snapshot rejected")
+
+	// errRetrySnapshot is returned when the snapshot can't be restored for now,
+	// in which case it is restored again after discovering more peers
+	errRetrySnapshot = errors.New("snapshot unavailable")
+
+	errNoLightBlock = errors.New("no peer has the light block")
+)
+
+// syncer restores the application state from the snapshot of a peer,
+// and builds the matching consensus state from verified light blocks
+type syncer struct {
+	logger    *slog.Logger
+	cfg       *config.StateSyncConfig
+	conn      appconn.Snapshot
+	connQuery appconn.Query
+	genesis   sm.State
+	peers     func() []p2p.PeerConn
+
+	snapshots  *snapshotPool
+	dispatcher *dispatcher
+	verifier   *verifier
+}
+
+func newSyncer(
+	logger *slog.Logger,
+	cfg *config.StateSyncConfig,
+	conn appconn.Snapshot,
+	connQuery appconn.Query,
+	genesis sm.State,
+	peers func() []p2p.PeerConn,
+) *syncer {
+	s := &syncer{
+		logger:     logger,
+		cfg:        cfg,
+		conn:       conn,
+		connQuery:  connQuery,
+		genesis:    genesis,
+		peers:      peers,
+		snapshots:  newSnapshotPool(),
+		dispatcher: newDispatcher(),
+	}
+	s.verifier = newVerifier(genesis.ChainID, cfg.TrustPeriod, s.fetchLightBlock)
+
+	return s
+}
+
+// sync discovers the snapshots of the peers, and restores the best one.
+// It returns the state and the verified light block at the snapshot height
+func (s *syncer) sync(ctx context.Context, discover func()) (sm.State, *LightBlock, error) {
+	discovering := true
+
+	for {
+		if discovering {
+			discover()
+
+			select {
+			case <-time.After(s.cfg.DiscoveryTime):
+			case <-ctx.Done():
+				return sm.State{}, nil, ctx.Err()
+			}
+		}
+		discovering = true
+
+		if !s.verifier.trusted() {
+			err := s.verifier.trust(ctx, s.cfg.TrustHeight, s.cfg.TrustHashBytes())
+			if err != nil {
+				if ctx.Err() != nil {
+					return sm.State{}, nil, ctx.Err()
+				}
+
+				s.logger.Error("Unable to verify the trusted block", "height", s.cfg.TrustHeight, "err", err)
+				continue
+			}
+		}
+
+		snapshot := s.snapshots.best()
+		if snapshot == nil {
+			s.logger.Info("No snapshots found, discovering")
+			continue
+		}
+
+		s.logger.Info("Restoring snapshot", "height", snapshot.Height, "format", snapshot.Format, "chunks", snapshot.Chunks)
+
+		state, lb, err := s.syncSnapshot(ctx, snapshot)
+		switch {
+		case err == nil:
+			return state, lb, nil
+		case ctx.Err() != nil:
+			return sm.State{}, nil, ctx.Err()
+		case errors.Is(err, errRejectSnapshot):
+			s.logger.Info("Snapshot rejected", "height", snapshot.Height, "format", snapshot.Format, "err", err)
+			s.snapshots.reject(snapshot)
+
+			// Restore the next best snapshot right away
+			discovering = false
+		case errors.Is(err, errRetrySnapshot):
+			s.logger.Info("Snapshot unavailable, discovering", "height", snapshot.Height, "format", snapshot.Format, "err", err)
+		default:
+			return sm.State{}, nil, err
+		}
+	}
+}
+
+// syncSnapshot verifies and restores the snapshot
+func (s *syncer) syncSnapshot(ctx context.Context, snapshot *abci.Snapshot) (sm.State, *LightBlock, error) {
+	// The app hash of the snapshot is in the next header, and the validators
+	// of the next blocks are needed to build the state
+	lbs := make([]*LightBlock, 3)
+	for i := range lbs {
+		lb, err := s.verifier.verify(ctx, snapshot.Height+int64(i))
+		if err != nil {
+			return sm.State{}, nil, fmt.Errorf("%w: %w", errRejectSnapshot, err)
+		}
+
+		lbs[i] = lb
+	}
+
+	params, err := s.fetchParams(ctx, snapshot.Height+1, lbs[1].Header.ConsensusHash)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: %w", errRejectSnapshot, err)
+	}
+
+	appHash := lbs[1].Header.AppHash
+
+	res, err := s.conn.OfferSnapshotSync(abci.RequestOfferSnapshot{
+		Snapshot: snapshot,
+		AppHash:  appHash,
+	})
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("unable to offer snapshot: %w", err)
+	}
+	if res.Error != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: %v", errRejectSnapshot, res.Error)
+	}
+
+	if err := s.applyChunks(ctx, snapshot); err != nil {
+		return sm.State{}, nil, err
+	}
+
+	// Make sure the app restored the verified state
+	info, err := s.connQuery.InfoSync(abci.RequestInfo{})
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("unable to query app info: %w", err)
+	}
+	if info.LastBlockHeight != snapshot.Height {
+		return sm.State{}, nil, fmt.Errorf("restored app height %d does not match the snapshot height %d",
+			info.LastBlockHeight, snapshot.Height)
+	}
+	if !bytes.Equal(info.LastBlockAppHash, appHash) {
+		return sm.State{}, nil, fmt.Errorf("restored app hash %X does not match the verified app hash %X",
+			info.LastBlockAppHash, appHash)
+	}
+
+	return s.buildState(lbs, params), lbs[0], nil
+}
+
+// buildState builds the state after the block at the snapshot height,
+// from the verified light blocks at the snapshot height and the next two
+func (s *syncer) buildState(lbs []*LightBlock, params abci.ConsensusParams) sm.State {
+	var (
+		last   = lbs[0]
+		next   = lbs[1]
+		height = last.Height()
+		state  = s.genesis
+	)
+
+	state.LastBlockHeight = height
+	state.LastBlockTotalTx = last.Header.TotalTxs
+	state.LastBlockID = last.Commit.BlockID
+	state.LastBlockTime = last.Header.Time
+
+	state.LastValidators = last.ValidatorSet
+	state.Validators = next.ValidatorSet
+	state.NextValidators = lbs[2].ValidatorSet
+	state.LastHeightValidatorsChanged = height + 2
+
+	state.ConsensusParams = params
+	state.LastHeightConsensusParamsChanged = height + 1
+
+	state.AppVersion = next.Header.AppVersion
+	state.AppHash = next.Header.AppHash
+	state.LastResultsHash = next.Header.LastResultsHash
+
+	return state
+}
+
+// applyChunks fetches the chunks of the offered snapshot,
+// and applies them in order
+func (s *syncer) applyChunks(ctx context.Context, snapshot *abci.Snapshot) error {
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	var (
+		queue     = newChunkQueue(snapshot.Chunks)
+		fetchErrs = make(chan error, chunkFetchers)
+	)
+
+	// A failed fetch aborts the restore
+	fail := func(err error) {
+		fetchErrs <- err
+		cancelFn()
+	}
+
+	for range chunkFetchers {
+		go s.fetchChunks(ctx, snapshot, queue, fail)
+	}
+
+	for !queue.done() {
+		c, err := queue.next(ctx)
+		if err != nil {
+			select {
+			case fetchErr := <-fetchErrs:
+				return fetchErr
+			default:
+				return err
+			}
+		}
+
+		res, err := s.conn.ApplySnapshotChunkSync(abci.RequestApplySnapshotChunk{
+			Index:  c.Index,
+			Chunk:  c.Chunk,
+			Sender: string(c.Sender),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to apply snapshot chunk %d: %w", c.Index, err)
+		}
+		if res.Error != nil {
+			return fmt.Errorf("%w: unable to apply chunk %d: %v", errRejectSnapshot, c.Index, res.Error)
+		}
+
+		for _, sender := range res.RejectSenders {
+			s.logger.Info("Rejecting snapshot peer", "peer", sender)
+			s.snapshots.rejectPeer(p2pTypes.ID(sender))
+		}
+
+		refetch := false
+		for _, index := range res.RefetchChunks {
+			queue.discard(index)
+			refetch = refetch || index == c.Index
+		}
+
+		if refetch {
+			continue
+		}
+
+		queue.markApplied()
+		s.logger.Debug("Applied snapshot chunk", "height", snapshot.Height, "chunk", c.Index, "chunks", snapshot.Chunks)
+	}
+
+	return nil
+}
+
+// fetchChunks fetches the chunks allocated from the queue,
+// until all of them are fetched
+func (s *syncer) fetchChunks(ctx context.Context, snapshot *abci.Snapshot, queue *chunkQueue, fail func(error)) {
+	for {
+		index, ok := queue.allocate()
+		if !ok {
+			if queue.done() {
+				return
+			}
+
+			select {
+			case <-time.After(allocateInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		c, err := s.fetchChunk(ctx, snapshot, index)
+		if err != nil {
+			queue.release(index)
+
+			if ctx.Err() == nil {
+				fail(err)
+			}
+
+			return
+		}
+
+		queue.add(c)
+	}
+}
+
+// fetchChunk fetches a chunk from the peers which have the snapshot
+func (s *syncer) fetchChunk(ctx context.Context, snapshot *abci.Snapshot, index uint32) (*chunk, error) {
+	peers := s.connectedPeers()
+
+	for _, peerID := range s.snapshots.peersOf(snapshot) {
+		peer, ok := peers[peerID]
+		if !ok {
+			s.snapshots.removePeer(peerID)
+			continue
+		}
+
+		key := requestKey{
+			kind:   chunkRequest,
+			peerID: peerID,
+			height: snapshot.Height,
+			format: snapshot.Format,
+			index:  index,
+		}
+
+		msg := &chunkRequestMessage{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Index:  index,
+		}
+
+		resp, err := s.dispatcher.request(ctx, peer, ChunkChannel, key, msg, s.cfg.ChunkRequestTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			s.logger.Debug("Unable to fetch snapshot chunk", "peer", peerID, "chunk", index, "err", err)
+			continue
+		}
+
+		res := resp.(*chunkResponseMessage)
+		if res.Missing {
+			s.logger.Debug("Peer is missing snapshot chunk", "peer", peerID, "chunk", index)
+			continue
+		}
+
+		return &chunk{
+			Index:  index,
+			Chunk:  res.Chunk,
+			Sender: peerID,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: no peer sent chunk %d", errRetrySnapshot, index)
+}
+
+// fetchLightBlock fetches the light block at the given height,
+// from the first peer which has it
+func (s *syncer) fetchLightBlock(ctx context.Context, height int64) (*LightBlock, error) {
+	for _, peer := range s.shuffledPeers() {
+		key := requestKey{
+			kind:   lightBlockRequest,
+			peerID: peer.ID(),
+			height: height,
+		}
+
+		resp, err := s.dispatcher.request(ctx, peer, LightBlockChannel, key, &lightBlockRequestMessage{Height: height}, lightBlockTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			s.logger.Debug("Unable to fetch light block", "peer", peer.ID(), "height", height, "err", err)
+			continue
+		}
+
+		lb := resp.(*lightBlockResponseMessage).LightBlock
+		if lb == nil {
+			continue
+		}
+
+		if err := lb.ValidateBasic(s.genesis.ChainID); err != nil {
+			s.logger.Info("Peer sent an invalid light block", "peer", peer.ID(), "height", height, "err", err)
+			continue
+		}
+
+		return lb, nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", errNoLightBlock, height)
+}
+
+// fetchParams fetches the consensus params at the given height, which must
+// match the verified consensus hash of the header at that height
+func (s *syncer) fetchParams(ctx context.Context, height int64, consensusHash []byte) (abci.ConsensusParams, error) {
+	for _, peer := range s.shuffledPeers() {
+		key := requestKey{
+			kind:   paramsRequest,
+			peerID: peer.ID(),
+			height: height,
+		}
+
+		resp, err := s.dispatcher.request(ctx, peer, LightBlockChannel, key, &paramsRequestMessage{Height: height}, lightBlockTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return abci.ConsensusParams{}, ctx.Err()
+			}
+
+			s.logger.Debug("Unable to fetch consensus params", "peer", peer.ID(), "height", height, "err", err)
+			continue
+		}
+
+		params := resp.(*paramsResponseMessage).ConsensusParams
+		if params == nil {
+			continue
+		}
+
+		if !bytes.Equal(params.Hash(), consensusHash) {
+			s.logger.Info("Peer sent invalid consensus params", "peer", peer.ID(), "height", height)
+			continue
+		}
+
+		return *params, nil
+	}
+
+	return abci.ConsensusParams{}, fmt.Errorf("no peer has the consensus params at height %d", height)
+}
+
+// connectedPeers returns the connected peers, by ID
+func (s *syncer) connectedPeers() map[p2pTypes.ID]p2p.PeerConn {
+	peers := make(map[p2pTypes.ID]p2p.PeerConn)
+	for _, peer := range s.peers() {
+		peers[peer.ID()] = peer
+	}
+
+	return peers
+}
+
+// shuffledPeers returns the connected peers, in random order
+func (s *syncer) shuffledPeers() []p2p.PeerConn {
+	peers := append([]p2p.PeerConn(nil), s.peers()...)
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	return peers
+}
@@ -29,6 +29,7 @@ type BlockStore struct {
 	db dbm.DB
 
 	mtx    sync.RWMutex
+	base   int64
 	height int64
 }
 
@@ -36,12 +37,26 @@ type BlockStore struct {
 // initialized to the last height that was committed to the DB.
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
+	if bsjson.Base == 0 && bsjson.Height > 0 {
+		// Block stores saved before pruning start at the first block
+		bsjson.Base = 1
+	}
 	return &BlockStore{
+		base:   bsjson.Base,
 		height: bsjson.Height,
 		db:     db,
 	}
 }
 
+// Base returns the first known contiguous block height,
+// or 0 for an empty block store.
+// A bootstrapped block store has no block yet, its base is above its height.
+func (bs *BlockStore) Base() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -61,6 +76,10 @@ func (bs *BlockStore) LoadBlock(height int64) *types.Block {
 	buf := []byte{}
 	for i := range blockMeta.BlockID.PartsHeader.Total {
 		part := bs.LoadBlockPart(height, i)
+		if part == nil {
+			// The block is being pruned
+			return nil
+		}
 		buf = append(buf, part.Bytes...)
 	}
 	err := amino.UnmarshalSized(buf, block)
@@ -176,15 +195,100 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
 
 	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
+	bs.mtx.Lock()
+	if bs.base == 0 {
+		bs.base = height
+	}
+	bs.height = height
+	BlockStoreStateJSON{Base: bs.base, Height: height}.Save(bs.db)
+	bs.mtx.Unlock()
+
+	// Flush
+	bs.db.SetSync(nil, nil)
+}
+
+// Bootstrap initializes an empty block store at the height of the given
+// block meta, once the state of the height is restored by state sync.
+// The block meta and the seen commit of the height are saved, but not its
+// block, so the next block saved is at the following height, the new base.
+func (bs *BlockStore) Bootstrap(blockMeta *types.BlockMeta, seenCommit *types.Commit) error {
+	if height := bs.Height(); height != 0 {
+		return fmt.Errorf("cannot bootstrap a block store at height %v", height)
+	}
+	height := blockMeta.Header.Height
+	if height <= 0 {
+		return fmt.Errorf("invalid bootstrap height %v", height)
+	}
+
+	bs.db.Set(calcBlockMetaKey(height), amino.MustMarshal(blockMeta))
+	bs.db.Set(calcSeenCommitKey(height), amino.MustMarshal(seenCommit))
 
-	// Done!
 	bs.mtx.Lock()
+	bs.base = height + 1
 	bs.height = height
+	BlockStoreStateJSON{Base: bs.base, Height: height}.Save(bs.db)
 	bs.mtx.Unlock()
 
 	// Flush
 	bs.db.SetSync(nil, nil)
+
+	return nil
+}
+
+// PruneBlocks removes the blocks (and their parts and commits) below the
+// given height, and returns the number of pruned blocks.
+// The blocks are removed in ascending order, so the block store stays
+// contiguous if pruning is interrupted.
+func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
+	if height <= 0 {
+		return 0, fmt.Errorf("height must be greater than 0")
+	}
+	if h := bs.Height(); height > h {
+		return 0, fmt.Errorf("cannot prune beyond the latest height %v", h)
+	}
+	base := bs.Base()
+	if height < base {
+		return 0, fmt.Errorf("cannot prune to height %v, it is lower than the base height %v", height, base)
+	}
+
+	// saveBase persists the new base, once the blocks below it are removed
+	saveBase := func(batch dbm.Batch, base int64) {
+		batch.WriteSync()
+		batch.Close()
+
+		bs.mtx.Lock()
+		bs.base = base
+		BlockStoreStateJSON{Base: base, Height: bs.height}.Save(bs.db)
+		bs.mtx.Unlock()
+	}
+
+	var (
+		pruned uint64
+		batch  = bs.db.NewBatch()
+	)
+	for h := base; h < height; h++ {
+		meta := bs.LoadBlockMeta(h)
+		if meta == nil { // assume already deleted
+			continue
+		}
+
+		batch.Delete(calcBlockMetaKey(h))
+		for i := range meta.BlockID.PartsHeader.Total {
+			batch.Delete(calcBlockPartKey(h, i))
+		}
+		batch.Delete(calcBlockCommitKey(h - 1))
+		batch.Delete(calcSeenCommitKey(h))
+		pruned++
+
+		// Flush every 1000 blocks, to avoid huge batches
+		if pruned%1000 == 0 {
+			saveBase(batch, h+1)
+			batch = bs.db.NewBatch()
+		}
+	}
+	saveBase(batch, height)
+
+	return pruned, nil
 }
 
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
@@ -219,6 +323,7 @@ var blockStoreKey = []byte("blockStore")
 
 // BlockStoreStateJSON is the block store state JSON structure.
 type BlockStoreStateJSON struct {
+	Base   int64 `json:"base"`
 	Height int64 `json:"height"`
 }
 
@@ -41,7 +41,7 @@ func makeTxs(height int64) (txs []types.Tx) {
 }
 
 func makeBlock(height int64, state sm.State, lastCommit *types.Commit) *types.Block {
-	block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, state.Validators.GetProposer().Address)
+	block, _ := state.MakeBlock(height, makeTxs(height), lastCommit, nil, state.Validators.GetProposer().Address)
 	return block
 }
 
@@ -421,6 +421,102 @@ func TestBlockFetchAtHeight(t *testing.T) {
 	require.Nil(t, blockAtHeightPlus2, "expecting an unsuccessful load of Height()+2")
 }
 
+func TestPruneBlocks(t *testing.T) {
+	t.Parallel()
+
+	bs, _ := freshBlockStore()
+	assert.Equal(t, int64(0), bs.Base())
+
+	_, err := bs.PruneBlocks(1)
+	require.Error(t, err, "nothing to prune in an empty store")
+
+	saveBlock := func(height int64) {
+		block := newBlock(types.Header{Height: height}, makeTestCommit(height-1, tmtime.Now()))
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(height, tmtime.Now()))
+	}
+	for h := int64(1); h <= 1500; h++ {
+		saveBlock(h)
+	}
+	assert.Equal(t, int64(1), bs.Base())
+	assert.Equal(t, int64(1500), bs.Height())
+
+	_, err = bs.PruneBlocks(0)
+	require.Error(t, err)
+	_, err = bs.PruneBlocks(1501)
+	require.Error(t, err, "cannot prune beyond the latest height")
+
+	pruned, err := bs.PruneBlocks(1200)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1199), pruned)
+	assert.Equal(t, int64(1200), bs.Base())
+	assert.Equal(t, int64(1500), bs.Height())
+
+	// The base is persisted
+	assert.Equal(t, BlockStoreStateJSON{Base: 1200, Height: 1500}, LoadBlockStoreStateJSON(bs.db))
+	assert.Equal(t, int64(1200), NewBlockStore(bs.db).Base())
+
+	for _, h := range []int64{1, 600, 1199} {
+		assert.Nil(t, bs.LoadBlock(h))
+		assert.Nil(t, bs.LoadBlockMeta(h))
+		assert.Nil(t, bs.LoadBlockPart(h, 0))
+		assert.Nil(t, bs.LoadBlockCommit(h-1))
+		assert.Nil(t, bs.LoadSeenCommit(h))
+	}
+
+	for _, h := range []int64{1200, 1500} {
+		assert.NotNil(t, bs.LoadBlock(h))
+		assert.NotNil(t, bs.LoadBlockCommit(h-1))
+		assert.NotNil(t, bs.LoadSeenCommit(h))
+	}
+
+	// Pruning below the base fails, pruning up to it is a no-op
+	_, err = bs.PruneBlocks(1199)
+	require.Error(t, err)
+	pruned, err = bs.PruneBlocks(1200)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), pruned)
+
+	// The new blocks are saved above the base
+	saveBlock(1501)
+	assert.Equal(t, int64(1200), bs.Base())
+	assert.Equal(t, int64(1501), bs.Height())
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Parallel()
+
+	bs, _ := freshBlockStore()
+
+	header := types.Header{Height: 100, ChainID: "block_test"}
+	blockMeta := &types.BlockMeta{
+		BlockID: types.BlockID{Hash: []byte("hash"), PartsHeader: types.PartSetHeader{Total: 1}},
+		Header:  header,
+	}
+	seenCommit := makeTestCommit(100, tmtime.Now())
+
+	require.Error(t, bs.Bootstrap(&types.BlockMeta{}, seenCommit), "invalid height")
+	require.NoError(t, bs.Bootstrap(blockMeta, seenCommit))
+
+	// The store is at the bootstrap height, without any block
+	assert.Equal(t, int64(100), bs.Height())
+	assert.Equal(t, int64(101), bs.Base())
+	assert.Equal(t, BlockStoreStateJSON{Base: 101, Height: 100}, LoadBlockStoreStateJSON(bs.db))
+
+	assert.Equal(t, blockMeta, bs.LoadBlockMeta(100))
+	assert.NotNil(t, bs.LoadSeenCommit(100))
+	assert.Nil(t, bs.LoadBlock(100))
+
+	// A bootstrapped store can't be bootstrapped again
+	require.Error(t, bs.Bootstrap(blockMeta, seenCommit))
+
+	// The blocks are saved from the next height
+	block := newBlock(types.Header{Height: 101}, seenCommit)
+	bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(101, tmtime.Now()))
+	assert.Equal(t, int64(101), bs.Height())
+	assert.Equal(t, int64(101), bs.Base())
+	assert.NotNil(t, bs.LoadBlock(101))
+}
+
 func doFn(fn func() (any, error)) (res any, err error, panicErr error) {
 	defer func() {
 		if r := recover(); r != nil {
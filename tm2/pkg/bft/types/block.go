@@ -20,7 +20,8 @@ type Block struct {
 	mtx        sync.Mutex
 	Header     `json:"header"`
 	Data       `json:"data"`
-	LastCommit *Commit `json:"last_commit"`
+	LastCommit *Commit      `json:"last_commit"`
+	Evidence   EvidenceData `json:"evidence"`
 }
 
 // ValidateBasic performs basic validation that doesn't involve state data.
@@ -97,6 +98,22 @@ func (b *Block) ValidateBasic() error {
 		)
 	}
 
+	// Validate the evidence and its hash.
+	if err := ValidateHash(b.EvidenceHash); err != nil {
+		return fmt.Errorf("wrong Header.EvidenceHash: %w", err)
+	}
+	if !bytes.Equal(b.EvidenceHash, b.Evidence.Hash()) {
+		return fmt.Errorf("wrong Header.EvidenceHash. Expected %v, got %v",
+			b.Evidence.Hash(),
+			b.EvidenceHash,
+		)
+	}
+	for i, ev := range b.Evidence.Evidence {
+		if err := ev.ValidateBasic(); err != nil {
+			return fmt.Errorf("invalid evidence (#%d): %w", i, err)
+		}
+	}
+
 	// Basic validation of hashes related to application data.
 	// Will validate fully against state in state#ValidateBlock.
 	if err := ValidateHash(b.ValidatorsHash); err != nil {
@@ -124,6 +141,9 @@ func (b *Block) fillHeader() {
 	if b.DataHash == nil {
 		b.DataHash = b.Data.Hash()
 	}
+	if b.EvidenceHash == nil {
+		b.EvidenceHash = b.Evidence.Hash()
+	}
 }
 
 // Hash computes and returns the block hash.
@@ -196,10 +216,12 @@ func (b *Block) StringIndented(indent string) string {
 %s  %v
 %s  %v
 %s  %v
+%s  %v
 %s}#%v`,
 		indent, b.Header.StringIndented(indent+"  "),
 		indent, b.Data.StringIndented(indent+"  "),
 		indent, b.LastCommit.StringIndented(indent+"  "),
+		indent, b.Evidence.StringIndented(indent+"  "),
 		indent, b.Hash())
 }
 
@@ -244,6 +266,10 @@ type Header struct {
 
 	// consensus info
 	ProposerAddress Address `json:"proposer_address"` // original proposer of the block
+
+	// hash of the evidence, last to keep the encoding of the other fields,
+	// and only part of the hash of the header when not empty
+	EvidenceHash []byte `json:"evidence_hash"` // evidence included in the block
 }
 
 // Implements abci.Header
@@ -255,7 +281,7 @@ func (h *Header) GetTime() time.Time { return h.Time }
 // MakeBlock returns a new block with an empty header, except what can be
 // computed from itself.
 // It populates the same set of fields validated by ValidateBasic.
-func MakeBlock(height int64, txs []Tx, lastCommit *Commit) *Block {
+func MakeBlock(height int64, txs []Tx, lastCommit *Commit, evidence []Evidence) *Block {
 	block := &Block{
 		Header: Header{
 			Height:   height,
@@ -266,6 +292,7 @@ func MakeBlock(height int64, txs []Tx, lastCommit *Commit) *Block {
 			Txs: txs,
 		},
 		LastCommit: lastCommit,
+		Evidence:   EvidenceData{Evidence: evidence},
 	}
 	block.fillHeader()
 	return block
@@ -309,7 +336,7 @@ func (h *Header) Hash() []byte {
 	if h == nil || len(h.ValidatorsHash) == 0 {
 		return nil
 	}
-	return merkle.SimpleHashFromByteSlices([][]byte{
+	fields := [][]byte{
 		bytesOrNil(h.Version),
 		bytesOrNil(h.ChainID),
 		bytesOrNil(h.Height),
@@ -326,7 +353,13 @@ func (h *Header) Hash() []byte {
 		bytesOrNil(h.AppHash),
 		bytesOrNil(h.LastResultsHash),
 		bytesOrNil(h.ProposerAddress),
-	})
+	}
+	// The evidence hash is only part of the hash of blocks with evidence,
+	// so that the hashes of the blocks made before it are kept.
+	if len(h.EvidenceHash) > 0 {
+		fields = append(fields, bytesOrNil(h.EvidenceHash))
+	}
+	return merkle.SimpleHashFromByteSlices(fields)
 }
 
 // StringIndented returns a string representation of the header
@@ -351,6 +384,7 @@ func (h *Header) StringIndented(indent string) string {
 %s  Consensus:      %v
 %s  Results:        %v
 %s  Proposer:       %v
+%s  Evidence:       %v
 %s}#%v`,
 		indent, h.Version,
 		indent, h.ChainID,
@@ -368,6 +402,7 @@ func (h *Header) StringIndented(indent string) string {
 		indent, h.ConsensusHash,
 		indent, h.LastResultsHash,
 		indent, h.ProposerAddress,
+		indent, h.EvidenceHash,
 		indent, h.Hash())
 }
 
@@ -726,6 +761,44 @@ func (data *Data) StringIndented(indent string) string {
 		indent, data.hash)
 }
 
+//-----------------------------------------------------------------------------
+
+// EvidenceData contains any evidence of malicious wrong-doing by validators
+type EvidenceData struct {
+	Evidence EvidenceList `json:"evidence"`
+
+	// Volatile
+	hash []byte
+}
+
+// Hash returns the hash of the data.
+func (data *EvidenceData) Hash() []byte {
+	if data.hash == nil {
+		data.hash = data.Evidence.Hash()
+	}
+	return data.hash
+}
+
+// StringIndented returns a string representation of the evidence.
+func (data *EvidenceData) StringIndented(indent string) string {
+	if data == nil {
+		return "nil-Evidence"
+	}
+	evStrings := make([]string, min(len(data.Evidence), 21))
+	for i, ev := range data.Evidence {
+		if i == 20 {
+			evStrings[i] = fmt.Sprintf("... (%v total)", len(data.Evidence))
+			break
+		}
+		evStrings[i] = fmt.Sprintf("Evidence:%v", ev)
+	}
+	return fmt.Sprintf(`EvidenceData{
+%s  %v
+%s}#%v`,
+		indent, strings.Join(evStrings, "\n"+indent+"  "),
+		indent, data.hash)
+}
+
 //--------------------------------------------------------------------------------
 
 // BlockID defines the unique ID of a block as its Hash and its PartSetHeader
@@ -4,6 +4,7 @@ import (
 	// it is ok to use math/rand here: we do not need a cryptographically secure random
 	// number generator here and we can run the tests a bit faster
 	"crypto/rand"
+	"fmt"
 	"math"
 	"testing"
 	"time"
@@ -61,7 +62,7 @@ func TestBlockValidateBasic(t *testing.T) {
 		t.Run(tc.testName, func(t *testing.T) {
 			t.Parallel()
 
-			block := MakeBlock(h, txs, commit)
+			block := MakeBlock(h, txs, commit, nil)
 			block.ProposerAddress = valSet.GetProposer().Address
 			tc.malleateBlock(block)
 			err = block.ValidateBasic()
@@ -74,7 +75,7 @@ func TestBlockHash(t *testing.T) {
 	t.Parallel()
 
 	assert.Nil(t, (*Block)(nil).Hash())
-	assert.Nil(t, MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil).Hash())
+	assert.Nil(t, MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil).Hash())
 }
 
 func TestBlockMakePartSet(t *testing.T) {
@@ -82,7 +83,7 @@ func TestBlockMakePartSet(t *testing.T) {
 
 	assert.Nil(t, (*Block)(nil).MakePartSet(2))
 
-	partSet := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil).MakePartSet(1024)
+	partSet := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil).MakePartSet(1024)
 	assert.NotNil(t, partSet)
 	assert.Equal(t, 1, partSet.Total())
 }
@@ -98,7 +99,7 @@ func TestBlockHashesTo(t *testing.T) {
 	commit, err := MakeCommit(lastID, h-1, 1, voteSet, vals)
 	require.NoError(t, err)
 
-	block := MakeBlock(h, []Tx{Tx("Hello World")}, commit)
+	block := MakeBlock(h, []Tx{Tx("Hello World")}, commit, nil)
 	block.ValidatorsHash = valSet.Hash()
 	assert.False(t, block.HashesTo([]byte{}))
 	assert.False(t, block.HashesTo([]byte("something else")))
@@ -108,7 +109,7 @@ func TestBlockHashesTo(t *testing.T) {
 func TestBlockSize(t *testing.T) {
 	t.Parallel()
 
-	size := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil).Size()
+	size := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil).Size()
 	if size <= 0 {
 		t.Fatal("Size of the block is zero or negative")
 	}
@@ -121,7 +122,7 @@ func TestBlockString(t *testing.T) {
 	assert.Equal(t, "nil-Block", (*Block)(nil).StringIndented(""))
 	assert.Equal(t, "nil-Block", (*Block)(nil).StringShort())
 
-	block := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil)
+	block := MakeBlock(int64(3), []Tx{Tx("Hello World")}, nil, nil)
 	assert.NotEqual(t, "nil-Block", block.String())
 	assert.NotEqual(t, "nil-Block", block.StringIndented(""))
 	assert.NotEqual(t, "nil-Block", block.StringShort())
@@ -155,6 +156,40 @@ func TestNilHeaderHashDoesntCrash(t *testing.T) {
 	assert.Equal(t, (new(Header)).Hash(), nilBytes)
 }
 
+func TestHeaderHashEvidence(t *testing.T) {
+	t.Parallel()
+
+	h := Header{
+		Version:            "v1.0.0-rc.0",
+		ChainID:            "test-chain",
+		Height:             3,
+		Time:               time.Unix(1000, 0).UTC(),
+		NumTxs:             1,
+		TotalTxs:           10,
+		AppVersion:         "v0.0.0-test",
+		LastBlockID:        makeBlockID(tmhash.Sum([]byte("last_block")), 1, tmhash.Sum([]byte("last_parts"))),
+		LastCommitHash:     tmhash.Sum([]byte("last_commit_hash")),
+		DataHash:           tmhash.Sum([]byte("data_hash")),
+		ValidatorsHash:     tmhash.Sum([]byte("validators_hash")),
+		NextValidatorsHash: tmhash.Sum([]byte("next_validators_hash")),
+		ConsensusHash:      tmhash.Sum([]byte("consensus_hash")),
+		AppHash:            tmhash.Sum([]byte("app_hash")),
+		LastResultsHash:    tmhash.Sum([]byte("last_results_hash")),
+		ProposerAddress:    crypto.AddressFromPreimage([]byte("proposer_address")),
+	}
+
+	// The hash of a block without evidence is the one from before the
+	// evidence hash.
+	hash := h.Hash()
+	assert.Equal(t, "6796DEF2D2A539939B8C4DE7DFD8CFBAFC470C66D6190576AF3E584F47F5708E", fmt.Sprintf("%X", hash))
+	h.EvidenceHash = EvidenceList{}.Hash()
+	assert.Equal(t, hash, h.Hash())
+
+	// The evidence of a block is part of its hash.
+	h.EvidenceHash = tmhash.Sum([]byte("evidence_hash"))
+	assert.NotEqual(t, hash, h.Hash())
+}
+
 func TestNilDataHashDoesntCrash(t *testing.T) {
 	t.Parallel()
 
@@ -245,12 +280,13 @@ func TestHeaderByteSize(t *testing.T) {
 		AppHash:            tmhash.Sum([]byte("app_hash")),
 		LastResultsHash:    tmhash.Sum([]byte("last_results_hash")),
 		ProposerAddress:    crypto.AddressFromPreimage([]byte("proposer_address")),
+		EvidenceHash:       tmhash.Sum([]byte("evidence_hash")),
 	}
 
 	bz, err := amino.MarshalSized(h)
 	require.NoError(t, err)
 
-	assert.EqualValues(t, 647, len(bz))
+	assert.EqualValues(t, 682, len(bz))
 }
 
 func randCommit() *Commit {
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+	"strconv"
+
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/events"
 )
@@ -49,3 +52,84 @@ type EventString string
 type EventValidatorSetUpdates struct {
 	ValidatorUpdates []abci.ValidatorUpdate `json:"validator_updates"`
 }
+
+// ----------------------------------------
+// Event queries
+
+// Reserved keys of the event attributes, by which the events can be queried
+// (see the events/query package).
+const (
+	EventTypeKey   = "tm.event"
+	BlockHeightKey = "block.height"
+	TxHashKey      = "tx.hash"
+	TxHeightKey    = "tx.height"
+	TxIndexKey     = "tx.index"
+)
+
+// Values of EventTypeKey.
+const (
+	EventNewBlockType            = "NewBlock"
+	EventTxType                  = "Tx"
+	EventValidatorSetUpdatesType = "ValidatorSetUpdates"
+)
+
+// EventAttributes returns the attributes of the event by which it can be
+// queried, or nil if the event can't be queried. The attributes of the
+// abci.AttributedEvents emitted by a transaction, or by the begin and end of a
+// block, are those of its EventTx, or EventNewBlock.
+func EventAttributes(event events.Event) map[string][]string {
+	switch ev := event.(type) {
+	case EventNewBlock:
+		attrs := map[string][]string{
+			EventTypeKey:   {EventNewBlockType},
+			BlockHeightKey: {strconv.FormatInt(ev.Block.Height, 10)},
+		}
+		addABCIEventAttributes(attrs, ev.ResultBeginBlock.Events)
+		addABCIEventAttributes(attrs, ev.ResultEndBlock.Events)
+
+		return attrs
+	case EventTx:
+		attrs := map[string][]string{
+			EventTypeKey: {EventTxType},
+			TxHashKey:    {fmt.Sprintf("%X", ev.Result.Tx.Hash())},
+			TxHeightKey:  {strconv.FormatInt(ev.Result.Height, 10)},
+			TxIndexKey:   {strconv.FormatUint(uint64(ev.Result.Index), 10)},
+		}
+		addABCIEventAttributes(attrs, ev.Result.Response.Events)
+
+		return attrs
+	case EventValidatorSetUpdates:
+		return map[string][]string{
+			EventTypeKey: {EventValidatorSetUpdatesType},
+		}
+	default:
+		return nil
+	}
+}
+
+// addABCIEventAttributes adds the attributes of the abci.AttributedEvents to
+// attrs. The reserved keys are not overwritten.
+func addABCIEventAttributes(attrs map[string][]string, evs []abci.Event) {
+	for _, ev := range evs {
+		attributed, ok := ev.(abci.AttributedEvent)
+		if !ok {
+			continue
+		}
+
+		for key, values := range attributed.QueryAttributes() {
+			if isReservedEventKey(key) {
+				continue
+			}
+			attrs[key] = append(attrs[key], values...)
+		}
+	}
+}
+
+func isReservedEventKey(key string) bool {
+	switch key {
+	case EventTypeKey, BlockHeightKey, TxHashKey, TxHeightKey, TxIndexKey:
+		return true
+	default:
+		return false
+	}
+}
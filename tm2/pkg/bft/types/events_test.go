@@ -0,0 +1,113 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// attributedEvent is an abci.AttributedEvent with fixed attributes
+type attributedEvent map[string][]string
+
+func (attributedEvent) AssertABCIEvent() {}
+
+func (e attributedEvent) QueryAttributes() map[string][]string {
+	return e
+}
+
+func TestEventAttributes(t *testing.T) {
+	t.Parallel()
+
+	tx := Tx("tx")
+
+	testCases := []struct {
+		name     string
+		event    TMEvent
+		expected map[string][]string
+	}{
+		{
+			"new block",
+			EventNewBlock{Block: &Block{Header: Header{Height: 10}}},
+			map[string][]string{
+				EventTypeKey:   {EventNewBlockType},
+				BlockHeightKey: {"10"},
+			},
+		},
+		{
+			"tx",
+			EventTx{Result: TxResult{Height: 10, Index: 2, Tx: tx}},
+			map[string][]string{
+				EventTypeKey: {EventTxType},
+				TxHashKey:    {fmt.Sprintf("%X", tx.Hash())},
+				TxHeightKey:  {"10"},
+				TxIndexKey:   {"2"},
+			},
+		},
+		{
+			"new block with events",
+			EventNewBlock{
+				Block: &Block{Header: Header{Height: 10}},
+				ResultBeginBlock: abci.ResponseBeginBlock{
+					ResponseBase: abci.ResponseBase{
+						Events: []abci.Event{attributedEvent{"begin.key": {"1"}}},
+					},
+				},
+				ResultEndBlock: abci.ResponseEndBlock{
+					Events: []abci.Event{
+						attributedEvent{"end.key": {"2"}, "begin.key": {"3"}},
+						abci.EventString("not attributed"),
+					},
+				},
+			},
+			map[string][]string{
+				EventTypeKey:   {EventNewBlockType},
+				BlockHeightKey: {"10"},
+				"begin.key":    {"1", "3"},
+				"end.key":      {"2"},
+			},
+		},
+		{
+			"tx with events",
+			EventTx{Result: TxResult{
+				Height: 10,
+				Tx:     tx,
+				Response: abci.ResponseDeliverTx{
+					ResponseBase: abci.ResponseBase{
+						Events: []abci.Event{
+							// The reserved keys can't be overwritten
+							attributedEvent{"transfer.to": {"g1alice"}, TxHeightKey: {"11"}},
+						},
+					},
+				},
+			}},
+			map[string][]string{
+				EventTypeKey:  {EventTxType},
+				TxHashKey:     {fmt.Sprintf("%X", tx.Hash())},
+				TxHeightKey:   {"10"},
+				TxIndexKey:    {"0"},
+				"transfer.to": {"g1alice"},
+			},
+		},
+		{
+			"validator set updates",
+			EventValidatorSetUpdates{},
+			map[string][]string{
+				EventTypeKey: {EventValidatorSetUpdatesType},
+			},
+		},
+		{
+			"not queryable",
+			EventNewBlockHeader{},
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, EventAttributes(tc.event))
+		})
+	}
+}
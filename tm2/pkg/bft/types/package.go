@@ -26,7 +26,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		Block{},
 		Header{},
 		Data{},
-		// EvidenceData{},
+		EvidenceData{},
 		Commit{},
 		BlockID{},
 		CommitSig{},
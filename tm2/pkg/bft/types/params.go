@@ -28,6 +28,9 @@ const (
 
 	// BlockTimeIotaMS is the block time iota (in ms)
 	BlockTimeIotaMS int64 = 100 // ms
+
+	// EvidenceMaxAge is the max age of the evidence (in blocks)
+	EvidenceMaxAge int64 = 100000 // 27.8 hrs at 1block/s
 )
 
 var validatorPubKeyTypeURLs = map[string]struct{}{
@@ -38,6 +41,7 @@ func DefaultConsensusParams() abci.ConsensusParams {
 	return abci.ConsensusParams{
 		Block:     DefaultBlockParams(),
 		Validator: DefaultValidatorParams(),
+		Evidence:  DefaultEvidenceParams(),
 	}
 }
 
@@ -56,6 +60,12 @@ func DefaultValidatorParams() *abci.ValidatorParams {
 	}}
 }
 
+func DefaultEvidenceParams() *abci.EvidenceParams {
+	return &abci.EvidenceParams{
+		MaxAge: EvidenceMaxAge,
+	}
+}
+
 func ValidateConsensusParams(params abci.ConsensusParams) error {
 	if params.Block.MaxTxBytes <= 0 {
 		return errors.New("Block.MaxTxBytes must be greater than 0. Got %d",
@@ -76,6 +86,13 @@ func ValidateConsensusParams(params abci.ConsensusParams) error {
 			params.Block.TimeIotaMS)
 	}
 
+	// NOTE: Evidence may be nil for chains started without it,
+	// in which case the default evidence params apply.
+	if params.Evidence != nil && params.Evidence.MaxAge <= 0 {
+		return errors.New("Evidence.MaxAge must be greater than 0. Got %d",
+			params.Evidence.MaxAge)
+	}
+
 	if len(params.Validator.PubKeyTypeURLs) == 0 {
 		return errors.New("len(Validator.PubKeyTypeURLs) must be greater than 0")
 	}
@@ -36,6 +36,10 @@ func TestConsensusParamsValidation(t *testing.T) {
 		9: {makeParams(1, 1024, 0, 10, []string{}), false},
 		// test invalid pubkey type provided
 		10: {makeParams(1, 1024, 0, 10, []string{"potatoes make good pubkeys"}), false},
+		// test evidence params
+		11: {withEvidenceParams(makeParams(1, 1024, 0, 10, valEd25519), 1), true},
+		12: {withEvidenceParams(makeParams(1, 1024, 0, 10, valEd25519), 0), false},
+		13: {withEvidenceParams(makeParams(1, 1024, 0, 10, valEd25519), -1), false},
 	}
 	for i, tc := range testCases {
 		if tc.valid {
@@ -64,6 +68,11 @@ func makeParams(
 	}
 }
 
+func withEvidenceParams(params abci.ConsensusParams, maxAge int64) abci.ConsensusParams {
+	params.Evidence = &abci.EvidenceParams{MaxAge: maxAge}
+	return params
+}
+
 func TestConsensusParamsHash(t *testing.T) {
 	t.Parallel()
 
@@ -123,6 +132,14 @@ func TestConsensusParamsUpdate(t *testing.T) {
 			},
 			makeParams(100, 1024, 200, 10, valSecp256k1),
 		},
+		// evidence updates
+		{
+			makeParams(1, 1024, 2, 10, valEd25519),
+			abci.ConsensusParams{
+				Evidence: &abci.EvidenceParams{MaxAge: 10},
+			},
+			withEvidenceParams(makeParams(1, 1024, 2, 10, valEd25519), 10),
+		},
 	}
 	for _, tc := range testCases {
 		assert.Equal(t, tc.updatedParams, tc.params.Update(tc.updates))
@@ -18,6 +18,7 @@ type MakeTxCfg struct {
 
 	GasWanted int64
 	GasFee    string
+	FeePayer  string
 	Memo      string
 
 	Broadcast bool
@@ -79,6 +80,13 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"gas payment fee",
 	)
 
+	fs.StringVar(
+		&c.FeePayer,
+		"fee-payer",
+		"",
+		"address of the account paying the fee, which must have granted a fee allowance to the signer",
+	)
+
 	fs.StringVar(
 		&c.Memo,
 		"memo",
@@ -116,6 +116,7 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 		Signatures: nil,
 		Memo:       cfg.RootCfg.Memo,
 	}
+	tx.Fee.FeePayer = crypto.Bech32Address(cfg.RootCfg.FeePayer)
 
 	if cfg.RootCfg.Broadcast {
 		err := ExecSignAndBroadcast(cfg.RootCfg, args, tx, io)
@@ -356,6 +356,42 @@ func TestDBIteratorNonemptyBeginAfter(t *testing.T) {
 	}
 }
 
+func TestBackendsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	for _, dbType := range db.BackendList() {
+		t.Run(string(dbType), func(t *testing.T) {
+			t.Parallel()
+
+			withDB(t, dbType, func(d db.DB) {
+				snapshotter, ok := d.(db.Snapshotter)
+				if !ok {
+					t.Skip("snapshots are not supported")
+				}
+
+				d.Set(int642Bytes(1), bz("a"))
+				d.Set(int642Bytes(2), bz("b"))
+
+				snap := snapshotter.Snapshot()
+				defer snap.Close()
+
+				// Later writes are not seen by the snapshot.
+				d.Set(int642Bytes(1), bz("c"))
+				d.Delete(int642Bytes(2))
+				d.Set(int642Bytes(3), bz("d"))
+
+				assert.Equal(t, bz("a"), snap.Get(int642Bytes(1)))
+				assert.True(t, snap.Has(int642Bytes(2)))
+				assert.Nil(t, snap.Get(int642Bytes(3)))
+				verifyIterator(t, snap.Iterator(nil, nil), []int64{1, 2}, "snapshot iterator")
+				assert.Panics(t, func() { snap.Set(int642Bytes(4), bz("e")) })
+
+				assert.Equal(t, bz("c"), d.Get(int642Bytes(1)))
+			})
+		})
+	}
+}
+
 func TestDBBatchWrite(t *testing.T) {
 	t.Parallel()
 
@@ -23,7 +23,10 @@ func init() {
 	db.InternalRegisterDBCreator(db.GoLevelDBBackend, dbCreator, false)
 }
 
-var _ db.DB = (*GoLevelDB)(nil)
+var (
+	_ db.DB          = (*GoLevelDB)(nil)
+	_ db.Snapshotter = (*GoLevelDB)(nil)
+)
 
 type GoLevelDB struct {
 	db *leveldb.DB
@@ -207,6 +210,100 @@ func (db *GoLevelDB) ReverseIterator(start, end []byte) db.Iterator {
 	return newGoLevelDBIterator(itr, start, end, true)
 }
 
+// Implements db.Snapshotter.
+func (db *GoLevelDB) Snapshot() db.DB {
+	snap, err := db.db.GetSnapshot()
+	if err != nil {
+		panic(err)
+	}
+	return &goLevelDBSnapshot{snap: snap}
+}
+
+// goLevelDBSnapshot is a read-only snapshot of a GoLevelDB.
+type goLevelDBSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+var _ db.DB = (*goLevelDBSnapshot)(nil)
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Get(key []byte) []byte {
+	key = internal.NonNilBytes(key)
+	res, err := s.snap.Get(key, nil)
+	if err != nil {
+		if goerrors.Is(err, errors.ErrNotFound) {
+			return nil
+		}
+		panic(err)
+	}
+	return res
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Has(key []byte) bool {
+	return s.Get(key) != nil
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Set(key []byte, value []byte) {
+	panic("Cannot mutate *goLevelDBSnapshot by calling .Set()")
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) SetSync(key []byte, value []byte) {
+	panic("Cannot mutate *goLevelDBSnapshot by calling .SetSync()")
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Delete(key []byte) {
+	panic("Cannot mutate *goLevelDBSnapshot by calling .Delete()")
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) DeleteSync(key []byte) {
+	panic("Cannot mutate *goLevelDBSnapshot by calling .DeleteSync()")
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Iterator(start, end []byte) db.Iterator {
+	itr := s.snap.NewIterator(nil, nil)
+	return newGoLevelDBIterator(itr, start, end, false)
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) ReverseIterator(start, end []byte) db.Iterator {
+	itr := s.snap.NewIterator(nil, nil)
+	return newGoLevelDBIterator(itr, start, end, true)
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) NewBatch() db.Batch {
+	panic("Cannot mutate *goLevelDBSnapshot by calling .NewBatch()")
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Close() error {
+	s.snap.Release()
+	return nil
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Print() {
+	fmt.Print("(snapshot) ")
+	itr := s.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		key := colors.DefaultColoredBytesN(itr.Key(), 50)
+		value := colors.DefaultColoredBytesN(itr.Value(), 100)
+		fmt.Printf("%v: %v\n", key, value)
+	}
+}
+
+// Implements DB.
+func (s *goLevelDBSnapshot) Stats() map[string]string {
+	return map[string]string{"database.type": "goLevelDBSnapshot"}
+}
+
 type goLevelDBIterator struct {
 	source    iterator.Iterator
 	start     []byte
@@ -16,7 +16,10 @@ func init() {
 	}, false)
 }
 
-var _ dbm.DB = (*MemDB)(nil)
+var (
+	_ dbm.DB          = (*MemDB)(nil)
+	_ dbm.Snapshotter = (*MemDB)(nil)
+)
 
 type MemDB struct {
 	mtx sync.Mutex
@@ -122,6 +125,18 @@ func (db *MemDB) Close() error {
 	return nil
 }
 
+// Implements dbm.Snapshotter.
+func (db *MemDB) Snapshot() dbm.DB {
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+
+	snapshot := NewMemDB()
+	for key, value := range db.db {
+		snapshot.db[key] = value
+	}
+	return dbm.NewImmutableDB(snapshot)
+}
+
 // Implements DB.
 func (db *MemDB) Print() {
 	db.mtx.Lock()
@@ -118,3 +118,14 @@ type Iterator interface {
 	// Close releases the Iterator.
 	Close()
 }
+
+// ----------------------------------------
+// Snapshotter
+
+// Snapshotter is implemented by the DBs which can take snapshots.
+type Snapshotter interface {
+	// Snapshot returns a read-only view of the DB at the time of the call,
+	// which is not affected by the later writes. Its mutation operations
+	// panic, and it must be closed once it is no longer used.
+	Snapshot() DB
+}
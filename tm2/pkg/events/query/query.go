@@ -0,0 +1,317 @@
+// Package query implements the small query language used to filter events,
+// by the event subscriptions and the searches of the RPC.
+//
+// A query is a list of conditions on the attributes of an event, which must
+// all hold for the event to match:
+//
+//	tm.event = 'Tx' AND tx.height >= 5 AND transfer.to EXISTS
+//
+// The operators are =, <, <=, >, >=, CONTAINS and EXISTS. The operand of =
+// and CONTAINS is a 'quoted string' or a number, the operand of the
+// comparison operators is a number, and EXISTS has no operand.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operator is the operator of a condition.
+type Operator uint8
+
+const (
+	OpEqual        Operator = iota // =
+	OpLess                         // <
+	OpLessEqual                    // <=
+	OpGreater                      // >
+	OpGreaterEqual                 // >=
+	OpContains                     // CONTAINS
+	OpExists                       // EXISTS
+)
+
+var opStrings = [...]string{
+	OpEqual:        "=",
+	OpLess:         "<",
+	OpLessEqual:    "<=",
+	OpGreater:      ">",
+	OpGreaterEqual: ">=",
+	OpContains:     "CONTAINS",
+	OpExists:       "EXISTS",
+}
+
+func (op Operator) String() string {
+	if int(op) < len(opStrings) {
+		return opStrings[op]
+	}
+	return fmt.Sprintf("Operator(%d)", op)
+}
+
+var errEmptyQuery = errors.New("empty query")
+
+// Condition is a condition on the values of the attribute Key.
+type Condition struct {
+	Key     string
+	Op      Operator
+	Operand any // string, int64 or float64, nil for OpExists
+}
+
+// String returns the condition in the query language.
+func (c Condition) String() string {
+	switch operand := c.Operand.(type) {
+	case nil:
+		return fmt.Sprintf("%s %s", c.Key, c.Op)
+	case string:
+		return fmt.Sprintf("%s %s '%s'", c.Key, c.Op, operand)
+	default:
+		return fmt.Sprintf("%s %s %v", c.Key, c.Op, operand)
+	}
+}
+
+// Matches returns true if any of the values satisfies the condition.
+func (c Condition) Matches(values []string) bool {
+	if c.Op == OpExists {
+		return len(values) > 0
+	}
+	for _, value := range values {
+		if c.matchesValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Condition) matchesValue(value string) bool {
+	if operand, ok := c.Operand.(string); ok {
+		switch c.Op {
+		case OpEqual:
+			return value == operand
+		case OpContains:
+			return strings.Contains(value, operand)
+		default:
+			return false
+		}
+	}
+	if c.Op == OpContains {
+		return strings.Contains(value, fmt.Sprint(c.Operand))
+	}
+
+	cmp, ok := compareNumber(value, c.Operand)
+	if !ok {
+		return false
+	}
+	switch c.Op {
+	case OpEqual:
+		return cmp == 0
+	case OpLess:
+		return cmp < 0
+	case OpLessEqual:
+		return cmp <= 0
+	case OpGreater:
+		return cmp > 0
+	case OpGreaterEqual:
+		return cmp >= 0
+	default:
+		return false
+	}
+}
+
+// compareNumber compares the number value with the operand, and returns
+// false if value is not a number.
+func compareNumber(value string, operand any) (int, bool) {
+	if n, ok := operand.(int64); ok {
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			switch {
+			case v < n:
+				return -1, true
+			case v > n:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	var n float64
+	switch operand := operand.(type) {
+	case int64:
+		n = float64(operand)
+	case float64:
+		n = operand
+	default:
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	switch {
+	case v < n:
+		return -1, true
+	case v > n:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// Query is a parsed query.
+type Query struct {
+	str        string
+	conditions []Condition
+}
+
+// MustParse parses the query, and panics if it is not valid.
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Parse parses the query s.
+func Parse(s string) (*Query, error) {
+	p := &parser{input: s}
+	conditions, err := p.parseConditions()
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", s, err)
+	}
+	return &Query{
+		str:        s,
+		conditions: conditions,
+	}, nil
+}
+
+// String returns the original query.
+func (q *Query) String() string {
+	return q.str
+}
+
+// Conditions returns the conditions of the query.
+func (q *Query) Conditions() []Condition {
+	return q.conditions
+}
+
+// Matches returns true if the attributes, a map of keys to their values,
+// satisfy all the conditions of the query.
+func (q *Query) Matches(attrs map[string][]string) bool {
+	for _, c := range q.conditions {
+		if !c.Matches(attrs[c.Key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ----------------------------------------
+// parser
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) parseConditions() ([]Condition, error) {
+	var conditions []Condition
+	for {
+		c, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+
+		p.skipSpaces()
+		if p.pos == len(p.input) {
+			return conditions, nil
+		}
+		if word := p.readWord(); word != "AND" {
+			return nil, fmt.Errorf("expected AND at %d, got %q", p.pos-len(word), word)
+		}
+	}
+}
+
+func (p *parser) parseCondition() (Condition, error) {
+	p.skipSpaces()
+	if p.pos == len(p.input) {
+		return Condition{}, errEmptyQuery
+	}
+	key := p.readWord()
+	if key == "" {
+		return Condition{}, fmt.Errorf("expected a key at %d", p.pos)
+	}
+
+	p.skipSpaces()
+	op, err := p.readOperator()
+	if err != nil {
+		return Condition{}, err
+	}
+	if op == OpExists {
+		return Condition{Key: key, Op: op}, nil
+	}
+
+	p.skipSpaces()
+	operand, err := p.readOperand()
+	if err != nil {
+		return Condition{}, err
+	}
+	if _, ok := operand.(string); ok && op != OpEqual && op != OpContains {
+		return Condition{}, fmt.Errorf("operator %s of key %s expects a number", op, key)
+	}
+	return Condition{Key: key, Op: op, Operand: operand}, nil
+}
+
+func (p *parser) readOperator() (Operator, error) {
+	start := p.pos
+	for _, op := range []Operator{OpLessEqual, OpGreaterEqual, OpEqual, OpLess, OpGreater} {
+		if strings.HasPrefix(p.input[p.pos:], op.String()) {
+			p.pos += len(op.String())
+			return op, nil
+		}
+	}
+	switch word := p.readWord(); word {
+	case "CONTAINS":
+		return OpContains, nil
+	case "EXISTS":
+		return OpExists, nil
+	default:
+		return 0, fmt.Errorf("expected an operator at %d, got %q", start, word)
+	}
+}
+
+func (p *parser) readOperand() (any, error) {
+	start := p.pos
+	if p.pos < len(p.input) && p.input[p.pos] == '\'' {
+		end := strings.IndexByte(p.input[p.pos+1:], '\'')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string at %d", start)
+		}
+		p.pos += end + 2
+		return p.input[start+1 : p.pos-1], nil
+	}
+
+	word := p.readWord()
+	if n, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("expected a 'string' or a number at %d, got %q", start, word)
+}
+
+// readWord reads until the next space, quote or operator.
+func (p *parser) readWord() string {
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(" \t\n'=<>", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
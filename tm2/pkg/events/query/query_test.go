@@ -0,0 +1,117 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		query      string
+		conditions []Condition
+		valid      bool
+	}{
+		{"tm.event = 'Tx'", []Condition{{"tm.event", OpEqual, "Tx"}}, true},
+		{"tm.event='Tx'", []Condition{{"tm.event", OpEqual, "Tx"}}, true},
+		{
+			"tm.event = 'Tx' AND tx.height >= 5 AND transfer.to EXISTS",
+			[]Condition{
+				{"tm.event", OpEqual, "Tx"},
+				{"tx.height", OpGreaterEqual, int64(5)},
+				{"transfer.to", OpExists, nil},
+			},
+			true,
+		},
+		{
+			"a < 1 AND b <= 2.5 AND c > -3 AND d = 4 AND e CONTAINS 'x y'",
+			[]Condition{
+				{"a", OpLess, int64(1)},
+				{"b", OpLessEqual, 2.5},
+				{"c", OpGreater, int64(-3)},
+				{"d", OpEqual, int64(4)},
+				{"e", OpContains, "x y"},
+			},
+			true,
+		},
+		{"", nil, false},
+		{"  ", nil, false},
+		{"tm.event", nil, false},
+		{"tm.event = Tx", nil, false},
+		{"tm.event = 'Tx", nil, false},
+		{"tm.event = 'Tx' OR tx.height = 1", nil, false},
+		{"tm.event = 'Tx' AND", nil, false},
+		{"tx.height > '5'", nil, false},
+		{"= 'Tx'", nil, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(tc.query)
+			if !tc.valid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.query, q.String())
+			assert.Equal(t, tc.conditions, q.Conditions())
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	attrs := map[string][]string{
+		"tm.event":      {"Tx"},
+		"tx.height":     {"10"},
+		"transfer.to":   {"g1alice", "g1bob"},
+		"transfer.rate": {"0.5"},
+	}
+
+	testCases := []struct {
+		query   string
+		matches bool
+	}{
+		{"tm.event = 'Tx'", true},
+		{"tm.event = 'NewBlock'", false},
+		{"tx.height = 10", true},
+		{"tx.height = '10'", true},
+		{"tx.height < 10", false},
+		{"tx.height <= 10", true},
+		{"tx.height > 9.5", true},
+		{"tx.height >= 11", false},
+		{"transfer.to = 'g1bob'", true},
+		{"transfer.to CONTAINS 'ali'", true},
+		{"transfer.to CONTAINS 'carol'", false},
+		{"transfer.rate < 1", true},
+		{"transfer.to > 1", false}, // not a number
+		{"transfer.to EXISTS", true},
+		{"transfer.from EXISTS", false},
+		{"tm.event = 'Tx' AND tx.height = 10 AND transfer.to = 'g1alice'", true},
+		{"tm.event = 'Tx' AND tx.height = 11", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.matches, MustParse(tc.query).Matches(attrs))
+		})
+	}
+}
+
+func TestConditionString(t *testing.T) {
+	t.Parallel()
+
+	q := MustParse("tm.event='Tx' AND tx.height>=5 AND a EXISTS")
+
+	strs := make([]string, 0, len(q.Conditions()))
+	for _, c := range q.Conditions() {
+		strs = append(strs, c.String())
+	}
+	assert.Equal(t, []string{"tm.event = 'Tx'", "tx.height >= 5", "a EXISTS"}, strs)
+}
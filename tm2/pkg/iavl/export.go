@@ -0,0 +1,156 @@
+package iavl
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// importBatchSize is the number of imported nodes written at once
+const importBatchSize = 10000
+
+// ExportNode is a node of an exported tree version.
+//
+// The nodes are exported in post-order (children first), so the importer
+// can rebuild the exact same tree shape, and thus the same tree hash.
+type ExportNode struct {
+	Key     []byte
+	Value   []byte // only set for leaf nodes
+	Version int64
+	Height  int8
+}
+
+// Export calls fn with the nodes of the tree, in post-order.
+// Any error returned by fn stops the export.
+func (t *ImmutableTree) Export(fn func(*ExportNode) error) error {
+	if t.root == nil {
+		return nil
+	}
+
+	return t.exportNode(t.root, fn)
+}
+
+func (t *ImmutableTree) exportNode(node *Node, fn func(*ExportNode) error) error {
+	if !node.isLeaf() {
+		if err := t.exportNode(node.getLeftNode(t), fn); err != nil {
+			return err
+		}
+
+		if err := t.exportNode(node.getRightNode(t), fn); err != nil {
+			return err
+		}
+	}
+
+	return fn(&ExportNode{
+		Key:     node.key,
+		Value:   node.value,
+		Version: node.version,
+		Height:  node.height,
+	})
+}
+
+// Importer rebuilds a tree version from the nodes of ImmutableTree.Export.
+// The nodes are saved as they are added, and the version is loaded in the
+// tree once the import is committed.
+type Importer struct {
+	tree    *MutableTree
+	version int64
+	stack   []*Node // the added nodes without a parent yet
+	added   int
+}
+
+// Import returns an importer of the given version in the tree, which must be empty.
+func (tree *MutableTree) Import(version int64) (*Importer, error) {
+	if version <= 0 {
+		return nil, errors.New("imported version must be greater than 0")
+	}
+
+	if latest := tree.ndb.getLatestVersion(); latest > 0 {
+		return nil, errors.New("found existing version %d, the tree must be empty", latest)
+	}
+
+	return &Importer{
+		tree:    tree,
+		version: version,
+	}, nil
+}
+
+// Add adds the next exported node to the imported tree.
+func (i *Importer) Add(exportNode *ExportNode) error {
+	if exportNode == nil {
+		return errors.New("node cannot be nil")
+	}
+
+	if exportNode.Version > i.version {
+		return fmt.Errorf("node version %d is greater than the imported version %d", exportNode.Version, i.version)
+	}
+
+	node := &Node{
+		key:     exportNode.Key,
+		value:   exportNode.Value,
+		version: exportNode.Version,
+		height:  exportNode.Height,
+		size:    1,
+	}
+
+	if node.height < 0 {
+		return fmt.Errorf("invalid node height %d", node.height)
+	}
+
+	if !node.isLeaf() {
+		// The children of an inner node are the last added nodes
+		if len(i.stack) < 2 {
+			return fmt.Errorf("missing children of inner node %X", node.key)
+		}
+
+		left, right := i.stack[len(i.stack)-2], i.stack[len(i.stack)-1]
+		if node.height != max(left.height, right.height)+1 {
+			return fmt.Errorf("invalid height %d for inner node %X", node.height, node.key)
+		}
+
+		if bytes.Compare(left.key, right.key) >= 0 {
+			return fmt.Errorf("unordered children of inner node %X", node.key)
+		}
+
+		node.value = nil
+		node.size = left.size + right.size
+		node.leftHash = left.hash
+		node.rightHash = right.hash
+
+		i.stack = i.stack[:len(i.stack)-2]
+	}
+
+	node._hash()
+	i.tree.ndb.SaveNode(node)
+	i.stack = append(i.stack, node)
+
+	// Write the nodes in batches, to avoid huge batches
+	i.added++
+	if i.added%importBatchSize == 0 {
+		i.tree.ndb.Commit()
+	}
+
+	return nil
+}
+
+// Commit saves the root of the imported version, and loads it in the tree.
+func (i *Importer) Commit() error {
+	var rootHash []byte
+
+	switch len(i.stack) {
+	case 0:
+		rootHash = []byte{} // empty tree
+	case 1:
+		rootHash = i.stack[0].hash
+	default:
+		return fmt.Errorf("invalid import, found %d nodes without a parent", len(i.stack))
+	}
+
+	i.tree.ndb.saveImportedRoot(rootHash, i.version)
+	i.tree.ndb.Commit()
+
+	_, err := i.tree.LoadVersion(i.version)
+
+	return err
+}
@@ -0,0 +1,165 @@
+package iavl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// exportTree exports the given tree version
+func exportTree(t *testing.T, tree *MutableTree, version int64) []*ExportNode {
+	t.Helper()
+
+	iTree, err := tree.GetImmutable(version)
+	require.NoError(t, err)
+
+	var nodes []*ExportNode
+	require.NoError(t, iTree.Export(func(node *ExportNode) error {
+		nodes = append(nodes, node)
+
+		return nil
+	}))
+
+	return nodes
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+
+	// Save a few versions, with updates and removals,
+	// so the tree shape depends on its history
+	for version := range 5 {
+		for i := range 50 {
+			tree.Set(fmt.Appendf(nil, "key-%d", (i*7+version*13)%100), fmt.Appendf(nil, "value-%d-%d", version, i))
+		}
+		for i := range 10 {
+			tree.Remove(fmt.Appendf(nil, "key-%d", (i*11+version)%100))
+		}
+
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	exported, err := tree.GetImmutable(3)
+	require.NoError(t, err)
+
+	nodes := exportTree(t, tree, 3)
+	require.NotEmpty(t, nodes)
+
+	// Import the version in a new tree
+	imported := NewMutableTree(memdb.NewMemDB(), 0)
+
+	importer, err := imported.Import(3)
+	require.NoError(t, err)
+
+	for _, node := range nodes {
+		require.NoError(t, importer.Add(node))
+	}
+	require.NoError(t, importer.Commit())
+
+	assert.Equal(t, int64(3), imported.Version())
+	assert.Equal(t, exported.Hash(), imported.Hash())
+	assert.Equal(t, exported.String(), imported.ImmutableTree.String())
+
+	// The imported tree can be updated
+	imported.Set([]byte("new"), []byte("value"))
+
+	_, version, err := imported.SaveVersion()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), version)
+
+	// Only empty trees can be imported into
+	_, err = imported.Import(10)
+	assert.Error(t, err)
+}
+
+func TestImport_EmptyTree(t *testing.T) {
+	t.Parallel()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+
+	importer, err := tree.Import(5)
+	require.NoError(t, err)
+	require.NoError(t, importer.Commit())
+
+	assert.Equal(t, int64(5), tree.Version())
+	assert.Nil(t, tree.Hash())
+}
+
+func TestImport_InvalidNodes(t *testing.T) {
+	t.Parallel()
+
+	leaf := func(key string) *ExportNode {
+		return &ExportNode{Key: []byte(key), Value: []byte("value"), Version: 1}
+	}
+
+	testTable := []struct {
+		name  string
+		nodes []*ExportNode
+	}{
+		{"nil node", []*ExportNode{nil}},
+		{"version above the imported version", []*ExportNode{{Key: []byte("a"), Value: []byte("a"), Version: 3}}},
+		{"inner node without children", []*ExportNode{leaf("a"), {Key: []byte("b"), Version: 1, Height: 1}}},
+		{"invalid inner node height", []*ExportNode{leaf("a"), leaf("b"), {Key: []byte("b"), Version: 1, Height: 2}}},
+		{"unordered children", []*ExportNode{leaf("b"), leaf("a"), {Key: []byte("b"), Version: 1, Height: 1}}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(2)
+			require.NoError(t, err)
+
+			var addErr error
+			for _, node := range testCase.nodes {
+				if addErr = importer.Add(node); addErr != nil {
+					break
+				}
+			}
+
+			assert.Error(t, addErr)
+		})
+	}
+
+	// Nodes without a parent are not a tree
+	importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(2)
+	require.NoError(t, err)
+	require.NoError(t, importer.Add(leaf("a")))
+	require.NoError(t, importer.Add(leaf("b")))
+	assert.Error(t, importer.Commit())
+}
+
+func TestIsNodeDBEntry(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+	tree := NewMutableTree(db, 0)
+
+	for version := range 3 {
+		for i := range 20 {
+			tree.Set(fmt.Appendf(nil, "key-%d", i), fmt.Appendf(nil, "value-%d", version))
+		}
+
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	// All the tree entries are recognized
+	itr := db.Iterator(nil, nil)
+	for ; itr.Valid(); itr.Next() {
+		assert.True(t, IsNodeDBEntry(itr.Key(), itr.Value()), "key %X", itr.Key())
+	}
+	itr.Close()
+
+	// Other entries are not
+	assert.False(t, IsNodeDBEntry([]byte("node:main.gno"), []byte("value")))
+	assert.False(t, IsNodeDBEntry(append([]byte{'n'}, make([]byte, hashSize)...), []byte("value")))
+	assert.False(t, IsNodeDBEntry([]byte("oid:123"), []byte("value")))
+}
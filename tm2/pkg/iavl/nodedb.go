@@ -385,6 +385,39 @@ func (ndb *nodeDB) saveRoot(hash []byte, version int64) error {
 	return nil
 }
 
+// saveImportedRoot creates an entry on disk for the root of an imported
+// version, which doesn't follow the latest version.
+func (ndb *nodeDB) saveImportedRoot(hash []byte, version int64) {
+	ndb.mtx.Lock()
+	defer ndb.mtx.Unlock()
+
+	ndb.batch.Set(ndb.rootKey(version), hash)
+	ndb.updateLatestVersion(version)
+}
+
+// IsNodeDBEntry returns true if the given key and value are an entry of an
+// IAVL node db, ie. a node, an orphan or a root.
+// It is used to tell the tree entries apart from other entries of a shared db.
+func IsNodeDBEntry(key, value []byte) bool {
+	switch {
+	case len(key) == nodeKeyFormat.length && key[0] == nodeKeyFormat.prefix:
+		// The key of a node is its hash
+		node, err := MakeNode(value)
+		if err != nil {
+			return false
+		}
+
+		return bytes.Equal(node._hash(), key[1:])
+	case len(key) == orphanKeyFormat.length && key[0] == orphanKeyFormat.prefix:
+		// The value of an orphan is the hash at the end of its key
+		return bytes.Equal(value, key[len(key)-hashSize:])
+	case len(key) == rootKeyFormat.length && key[0] == rootKeyFormat.prefix:
+		return len(value) == 0 || len(value) == hashSize
+	default:
+		return false
+	}
+}
+
 // ----------- Utility and test functions // -----------
 
 func (ndb *nodeDB) leafNodes() []*Node {
@@ -25,3 +25,14 @@ type BeginTxHook func(ctx Context) Context
 // EndTxHook is a BaseApp-specific hook, called after all the messages in a
 // transaction have terminated.
 type EndTxHook func(ctx Context, result Result)
+
+// RestoreHook is a BaseApp-specific hook, called after the state is restored
+// from a state sync snapshot, to reload any state the application keeps
+// outside of the stores.
+type RestoreHook func(ctx Context)
+
+// RestoreVerifier is a BaseApp-specific hook, called once the state is
+// restored from a state sync snapshot, before it is saved. It verifies the
+// entries of the base store, which are not covered by the app hash, against
+// the state committed in the IAVL stores.
+type RestoreVerifier func(ctx Context) error
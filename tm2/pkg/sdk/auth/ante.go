@@ -37,7 +37,7 @@ type AnteOptions struct {
 
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
 // numbers, checks signatures & account numbers, and deducts fees from the first
-// signer.
+// signer, or from the fee payer of the tx with a fee grant to the first signer.
 func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer SignatureVerificationGasConsumer, opts AnteOptions) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx std.Tx, simulate bool,
@@ -120,9 +120,19 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			return newCtx, res, true
 		}
 
+		// fetch the fee payer, who must have granted a fee allowance to the
+		// first signer
+		feePayerAcc := signerAccs[0]
+		if tx.Fee.FeePayer != "" {
+			feePayerAcc, res = GetFeePayerAcc(newCtx, ak, tx)
+			if !res.IsOK() {
+				return newCtx, res, true
+			}
+		}
+
 		// deduct the fees
 		if !tx.Fee.GasFee.IsZero() {
-			res = DeductFees(bank, newCtx, signerAccs[0], ak.FeeCollectorAddress(ctx), std.Coins{tx.Fee.GasFee})
+			res = DeductFees(bank, newCtx, feePayerAcc, ak.FeeCollectorAddress(ctx), std.Coins{tx.Fee.GasFee})
 			if !res.IsOK() {
 				return newCtx, res, true
 			}
@@ -176,6 +186,20 @@ func GetSignerAcc(ctx sdk.Context, ak AccountKeeper, addr crypto.Address) (std.A
 	return nil, abciResult(std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", addr)))
 }
 
+// GetFeePayerAcc returns the account of the fee payer of tx, after using the
+// fee grant of the fee payer to the first signer of tx to pay its fees.
+func GetFeePayerAcc(ctx sdk.Context, ak AccountKeeper, tx std.Tx) (std.Account, sdk.Result) {
+	feePayer, err := crypto.AddressFromBech32(string(tx.Fee.FeePayer))
+	if err != nil {
+		return nil, abciResult(std.ErrInvalidAddress(fmt.Sprintf("invalid fee payer %q", tx.Fee.FeePayer)))
+	}
+	err = ak.UseFeeGrant(ctx, feePayer, tx.GetSigners()[0], std.Coins{tx.Fee.GasFee}, tx.GetMsgs())
+	if err != nil {
+		return nil, abciResult(err)
+	}
+	return GetSignerAcc(ctx, ak, feePayer)
+}
+
 // ValidateSigCount validates that the transaction has a valid cumulative total
 // amount of signatures.
 func ValidateSigCount(tx std.Tx, params Params) sdk.Result {
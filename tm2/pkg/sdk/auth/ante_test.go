@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -352,6 +353,80 @@ func TestAnteHandlerFees(t *testing.T) {
 	require.Equal(t, env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"), int64(0))
 }
 
+// Test logic around fees paid with a fee grant.
+func TestAnteHandlerFeeGrant(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	ctx := env.ctx.WithBlockHeader(&bft.Header{Height: 1, ChainID: "test-chain-id", Time: time.Unix(1000, 0)})
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+
+	// set the accounts; the grantee has no coins
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	env.acck.SetAccount(ctx, acc1)
+	acc2 := env.acck.NewAccountWithAddress(ctx, addr2)
+	acc2.SetCoins(std.NewCoins(std.NewCoin("atom", 1000)))
+	env.acck.SetAccount(ctx, acc2)
+
+	// msg and signatures
+	var tx std.Tx
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	privs, accnums := []crypto.PrivKey{priv1}, []uint64{0}
+	fee := tu.NewTestFee()
+	fee.FeePayer = addr2.Bech32()
+
+	// no fee grant
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// expired fee grant
+	env.acck.SetFeeGrant(ctx, FeeGrant{Granter: addr2, Grantee: addr1, Expiration: 1000})
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// fee grant which does not allow the msg
+	env.acck.SetFeeGrant(ctx, FeeGrant{Granter: addr2, Grantee: addr1, AllowedMsgs: []MsgFilter{{Route: "bank"}}})
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+	env.acck.SetFeeGrant(ctx, FeeGrant{Granter: addr2, Grantee: addr1, AllowedMsgs: []MsgFilter{{Route: "TestMsg", Target: "foo"}}})
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// the granter pays the fees, and the spend limit is decremented
+	env.acck.SetFeeGrant(ctx, FeeGrant{
+		Granter:     addr2,
+		Grantee:     addr1,
+		SpendLimit:  std.NewCoins(std.NewCoin("atom", 200)),
+		Expiration:  2000,
+		AllowedMsgs: []MsgFilter{{Route: "TestMsg"}},
+	})
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, int64(850), env.acck.GetAccount(ctx, addr2).GetCoins().AmountOf("atom"))
+	require.Equal(t, int64(0), env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"))
+	require.Equal(t, uint64(1), env.acck.GetAccount(ctx, addr1).GetSequence())
+	require.Equal(t, int64(50), env.acck.GetFeeGrant(ctx, addr2, addr1).SpendLimit.AmountOf("atom"))
+
+	// the spend limit is exceeded
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{1}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.InsufficientFundsError{})
+
+	// the fee grant is removed once its spend limit is exhausted
+	env.acck.SetFeeGrant(ctx, FeeGrant{Granter: addr2, Grantee: addr1, SpendLimit: std.NewCoins(std.NewCoin("atom", 150))})
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, int64(700), env.acck.GetAccount(ctx, addr2).GetCoins().AmountOf("atom"))
+	require.Nil(t, env.acck.GetFeeGrant(ctx, addr2, addr1))
+
+	// the fee payer is part of the signed bytes
+	acc1 = env.acck.GetAccount(ctx, addr1)
+	acc1.SetCoins(std.NewCoins(std.NewCoin("atom", 150)))
+	env.acck.SetAccount(ctx, acc1)
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{2}, fee)
+	tx.Fee.FeePayer = ""
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+}
+
 // Test logic around memo gas consumption.
 func TestAnteHandlerMemoGas(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// FeeGrant is an allowance, set with MsgGrantFee, of Granter to pay the fees
+// of the transactions of Grantee. A transaction of the grantee uses it by
+// setting its fee payer to the granter (see std.Fee).
+type FeeGrant struct {
+	Granter     crypto.Address `json:"granter" yaml:"granter"`
+	Grantee     crypto.Address `json:"grantee" yaml:"grantee"`
+	SpendLimit  std.Coins      `json:"spend_limit" yaml:"spend_limit"`   // fees which can still be paid; no limit, if empty.
+	Expiration  int64          `json:"expiration" yaml:"expiration"`     // unix time in seconds; never expires, if zero.
+	AllowedMsgs []MsgFilter    `json:"allowed_msgs" yaml:"allowed_msgs"` // messages which can be paid for; any, if empty.
+}
+
+// ValidateBasic does a simple validation check that doesn't require access to
+// any other information.
+func (fg FeeGrant) ValidateBasic() error {
+	if fg.Granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if fg.Grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	if fg.Granter == fg.Grantee {
+		return std.ErrInvalidAddress("cannot grant a fee allowance to self")
+	}
+	if !fg.SpendLimit.IsValid() {
+		return std.ErrInvalidCoins(fg.SpendLimit.String())
+	}
+	if fg.Expiration < 0 {
+		return std.ErrUnknownRequest(fmt.Sprintf("invalid expiration %d", fg.Expiration))
+	}
+	for _, filter := range fg.AllowedMsgs {
+		if filter.Route == "" {
+			return std.ErrUnknownRequest("missing route of allowed message")
+		}
+	}
+	return nil
+}
+
+// IsExpired returns true if the grant is expired at t.
+func (fg FeeGrant) IsExpired(t time.Time) bool {
+	return fg.Expiration != 0 && t.Unix() >= fg.Expiration
+}
+
+// Allows returns true if the fees of a transaction with msgs can be paid with
+// the grant.
+func (fg FeeGrant) Allows(msgs []std.Msg) bool {
+	if len(fg.AllowedMsgs) == 0 {
+		return true
+	}
+	for _, msg := range msgs {
+		if !slices.ContainsFunc(fg.AllowedMsgs, func(filter MsgFilter) bool {
+			return filter.Matches(msg)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// MsgFilter matches messages by route, type and target; for instance, the
+// filter {"vm", "exec", "gno.land/r/foo"} only matches the calls to
+// gno.land/r/foo.
+type MsgFilter struct {
+	Route  string `json:"route" yaml:"route"`
+	Type   string `json:"type" yaml:"type"`     // any type of the route, if empty.
+	Target string `json:"target" yaml:"target"` // any target, if empty.
+}
+
+// Matches returns true if msg is matched by the filter.
+func (filter MsgFilter) Matches(msg std.Msg) bool {
+	if msg.Route() != filter.Route {
+		return false
+	}
+	if filter.Type != "" && msg.Type() != filter.Type {
+		return false
+	}
+	if filter.Target == "" {
+		return true
+	}
+	tmsg, ok := msg.(TargetedMsg)
+	return ok && tmsg.Target() == filter.Target
+}
+
+// TargetedMsg is a message with a target, like the realm called by a message
+// of the vm, to which a MsgFilter can restrict a fee grant.
+type TargetedMsg interface {
+	std.Msg
+	Target() string
+}
+
+// GetFeeGrant returns the fee grant of granter to grantee, or nil if there is
+// none.
+func (ak AccountKeeper) GetFeeGrant(ctx sdk.Context, granter, grantee crypto.Address) *FeeGrant {
+	stor := ctx.GasStore(ak.key)
+	bz := stor.Get(FeeGrantStoreKey(granter, grantee))
+	if bz == nil {
+		return nil
+	}
+	grant := new(FeeGrant)
+	amino.MustUnmarshal(bz, grant)
+	return grant
+}
+
+// SetFeeGrant sets the fee grant of grant.Granter to grant.Grantee, replacing
+// any previous one.
+func (ak AccountKeeper) SetFeeGrant(ctx sdk.Context, grant FeeGrant) {
+	stor := ctx.GasStore(ak.key)
+	stor.Set(FeeGrantStoreKey(grant.Granter, grant.Grantee), amino.MustMarshal(grant))
+}
+
+// RemoveFeeGrant removes the fee grant of granter to grantee.
+func (ak AccountKeeper) RemoveFeeGrant(ctx sdk.Context, granter, grantee crypto.Address) {
+	stor := ctx.GasStore(ak.key)
+	stor.Delete(FeeGrantStoreKey(granter, grantee))
+}
+
+// IterateFeeGrants iterates over the fee grants of granter.
+func (ak AccountKeeper) IterateFeeGrants(ctx sdk.Context, granter crypto.Address, process func(FeeGrant) (stop bool)) {
+	stor := ctx.GasStore(ak.key)
+	iter := store.PrefixIterator(stor, FeeGrantsStoreKey(granter))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var grant FeeGrant
+		amino.MustUnmarshal(iter.Value(), &grant)
+		if process(grant) {
+			return
+		}
+	}
+}
+
+// UseFeeGrant checks that granter can pay fees for a transaction of grantee
+// with msgs, and decrements the spend limit of its grant; the grant is removed
+// once its spend limit is exhausted.
+func (ak AccountKeeper) UseFeeGrant(ctx sdk.Context, granter, grantee crypto.Address, fees std.Coins, msgs []std.Msg) error {
+	grant := ak.GetFeeGrant(ctx, granter, grantee)
+	if grant == nil {
+		return std.ErrUnauthorized(fmt.Sprintf("no fee grant from %s to %s", granter, grantee))
+	}
+	if grant.IsExpired(ctx.BlockTime()) {
+		return std.ErrUnauthorized(fmt.Sprintf("fee grant from %s to %s is expired", granter, grantee))
+	}
+	if !grant.Allows(msgs) {
+		return std.ErrUnauthorized(fmt.Sprintf("fee grant from %s to %s does not allow the messages of the transaction", granter, grantee))
+	}
+	if grant.SpendLimit.Empty() || fees.IsZero() {
+		return nil
+	}
+
+	left := grant.SpendLimit.SubUnsafe(fees)
+	if !left.IsValid() {
+		return std.ErrInsufficientFunds(
+			fmt.Sprintf("insufficient fee grant to pay for fees; %s < %s", grant.SpendLimit, fees),
+		)
+	}
+	if left.Empty() {
+		ak.RemoveFeeGrant(ctx, granter, grantee)
+		return nil
+	}
+	grant.SpendLimit = left
+	ak.SetFeeGrant(ctx, *grant)
+	return nil
+}
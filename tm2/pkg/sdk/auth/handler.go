@@ -25,9 +25,44 @@ func NewHandler(acck AccountKeeper, gpKpr GasPriceKeeper) authHandler {
 }
 
 func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
-	// no messages supported yet.
-	errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
-	return abciResult(std.ErrUnknownRequest(errMsg))
+	switch msg := msg.(type) {
+	case MsgGrantFee:
+		return ah.handleMsgGrantFee(ctx, msg)
+
+	case MsgRevokeFee:
+		return ah.handleMsgRevokeFee(ctx, msg)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgGrantFee.
+func (ah authHandler) handleMsgGrantFee(ctx sdk.Context, msg MsgGrantFee) sdk.Result {
+	grant := msg.FeeGrant()
+	if grant.IsExpired(ctx.BlockTime()) {
+		return abciResult(std.ErrUnknownRequest(
+			fmt.Sprintf("fee grant expiration %d is in the past", grant.Expiration)))
+	}
+
+	// Create the account of the grantee if it doesn't exist yet, so that a
+	// new user without coins can sign its first transactions.
+	if ah.acck.GetAccount(ctx, grant.Grantee) == nil {
+		ah.acck.SetAccount(ctx, ah.acck.NewAccountWithAddress(ctx, grant.Grantee))
+	}
+	ah.acck.SetFeeGrant(ctx, grant)
+	return sdk.Result{}
+}
+
+// Handle MsgRevokeFee.
+func (ah authHandler) handleMsgRevokeFee(ctx sdk.Context, msg MsgRevokeFee) sdk.Result {
+	if ah.acck.GetFeeGrant(ctx, msg.Granter, msg.Grantee) == nil {
+		return abciResult(std.ErrUnknownRequest(
+			fmt.Sprintf("no fee grant from %s to %s", msg.Granter, msg.Grantee)))
+	}
+	ah.acck.RemoveFeeGrant(ctx, msg.Granter, msg.Grantee)
+	return sdk.Result{}
 }
 
 //----------------------------------------
@@ -37,6 +72,8 @@ func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 const (
 	QueryAccount  = "accounts"
 	QueryGasPrice = "gasprice"
+	QueryFeeGrant = "feegrants"
+	QueryVesting  = "vesting"
 )
 
 func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -45,6 +82,10 @@ func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Re
 		return ah.queryAccount(ctx, req)
 	case QueryGasPrice:
 		return ah.queryGasPrice(ctx, req)
+	case QueryFeeGrant:
+		return ah.queryFeeGrant(ctx, req)
+	case QueryVesting:
+		return ah.queryVesting(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown auth query endpoint"))
@@ -95,6 +136,82 @@ func (ah authHandler) queryGasPrice(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// queryFeeGrant fetch the fee grant of a granter to a grantee, or all the fee
+// grants of a granter if the grantee is omitted.
+// Granter and grantee addresses are passed as path components.
+func (ah authHandler) queryFeeGrant(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addrs from path.
+	b32granter := thirdPart(req.Path)
+	granter, err := crypto.AddressFromBech32(b32granter)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress(
+				"invalid query address " + b32granter))
+		return
+	}
+	var result any
+	if b32grantee := fourthPart(req.Path); b32grantee != "" {
+		grantee, err := crypto.AddressFromBech32(b32grantee)
+		if err != nil {
+			res = sdk.ABCIResponseQueryFromError(
+				std.ErrInvalidAddress(
+					"invalid query address " + b32grantee))
+			return
+		}
+		result = ah.acck.GetFeeGrant(ctx, granter, grantee)
+	} else {
+		grants := []FeeGrant{}
+		ah.acck.IterateFeeGrants(ctx, granter, func(grant FeeGrant) bool {
+			grants = append(grants, grant)
+			return false
+		})
+		result = grants
+	}
+
+	bz, err := amino.MarshalJSONIndent(result, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// queryVesting fetch the vesting status of an account at the last block.
+// Account address are passed as path component.
+func (ah authHandler) queryVesting(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addr from path.
+	b32addr := thirdPart(req.Path)
+	addr, err := crypto.AddressFromBech32(b32addr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress(
+				"invalid query address " + b32addr))
+		return
+	}
+
+	acc := ah.acck.GetAccount(ctx, addr)
+	if acc == nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrUnknownAddress(fmt.Sprintf("account %s does not exist", addr)))
+		return
+	}
+
+	bz, err := amino.MarshalJSONIndent(
+		NewVestingStatus(acc, ctx.BlockTime()),
+		"", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
 //----------------------------------------
 // misc
 
@@ -117,3 +234,13 @@ func thirdPart(path string) string {
 		return parts[2]
 	}
 }
+
+// returns the fourth component of a path.
+func fourthPart(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 {
+		return ""
+	} else {
+		return parts[3]
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -89,6 +90,99 @@ func TestQueryGasPrice(t *testing.T) {
 	require.True(t, gp == gp2)
 }
 
+func TestHandlerFeeGrant(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, env.gk)
+	ctx := env.ctx.WithBlockHeader(&bft.Header{Height: 1, ChainID: "test-chain-id", Time: time.Unix(1000, 0)})
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+
+	// the fee grant can't be already expired
+	msg := NewMsgGrantFee(granter, grantee, std.NewCoins(std.NewCoin("foo", 10)), 1000, nil)
+	res := h.Process(ctx, msg)
+	require.False(t, res.IsOK())
+	require.Nil(t, env.acck.GetFeeGrant(ctx, granter, grantee))
+
+	// the account of the grantee is created with the fee grant
+	msg.Expiration = 2000
+	res = h.Process(ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, msg.FeeGrant(), *env.acck.GetFeeGrant(ctx, granter, grantee))
+	require.NotNil(t, env.acck.GetAccount(ctx, grantee))
+
+	// a new fee grant replaces the previous one
+	msg = NewMsgGrantFee(granter, grantee, nil, 0, []MsgFilter{{Route: "vm", Type: "exec", Target: "gno.land/r/foo"}})
+	res = h.Process(ctx, msg)
+	require.True(t, res.IsOK(), res.Log)
+	require.Equal(t, msg.FeeGrant(), *env.acck.GetFeeGrant(ctx, granter, grantee))
+
+	res = h.Process(ctx, NewMsgRevokeFee(granter, grantee))
+	require.True(t, res.IsOK(), res.Log)
+	require.Nil(t, env.acck.GetFeeGrant(ctx, granter, grantee))
+	res = h.Process(ctx, NewMsgRevokeFee(granter, grantee))
+	require.False(t, res.IsOK())
+}
+
+func TestQueryFeeGrant(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, env.gk)
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+
+	query := func(path string) []byte {
+		t.Helper()
+		res := h.Query(env.ctx, abci.RequestQuery{Path: path})
+		require.Nil(t, res.Error)
+		return res.Data
+	}
+	path := fmt.Sprintf("auth/%s/%s/%s", QueryFeeGrant, granter, grantee)
+	require.Equal(t, "null", string(query(path)))
+
+	grant := FeeGrant{Granter: granter, Grantee: grantee, SpendLimit: std.NewCoins(std.NewCoin("foo", 10))}
+	env.acck.SetFeeGrant(env.ctx, grant)
+	var grant2 FeeGrant
+	require.NoError(t, amino.UnmarshalJSON(query(path), &grant2))
+	require.Equal(t, grant, grant2)
+
+	// all the fee grants of the granter
+	var grants []FeeGrant
+	require.NoError(t, amino.UnmarshalJSON(query(fmt.Sprintf("auth/%s/%s", QueryFeeGrant, granter)), &grants))
+	require.Equal(t, []FeeGrant{grant}, grants)
+
+	res := h.Query(env.ctx, abci.RequestQuery{Path: fmt.Sprintf("auth/%s/%s", QueryFeeGrant, "foo")})
+	require.Error(t, res.Error)
+}
+
+func TestQueryVesting(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, env.gk)
+	_, _, addr := tu.KeyTestPubAddr()
+
+	req := abci.RequestQuery{Path: fmt.Sprintf("auth/%s/%s", QueryVesting, addr)}
+	res := h.Query(env.ctx, req)
+	require.Error(t, res.Error) // the account doesn't exist
+
+	// an account which doesn't vest has all its coins spendable
+	acc := env.acck.NewAccountWithAddress(env.ctx, addr)
+	acc.SetCoins(std.NewCoins(std.NewCoin("foo", 10)))
+	env.acck.SetAccount(env.ctx, acc)
+
+	res = h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	var status VestingStatus
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &status))
+	require.Equal(t, addr, status.Address)
+	require.Nil(t, status.Schedule)
+	require.True(t, status.Locked.IsZero())
+	require.Equal(t, acc.GetCoins(), status.Spendable)
+}
+
 func TestQuerierRouteNotFound(t *testing.T) {
 	t.Parallel()
 
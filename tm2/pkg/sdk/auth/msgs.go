@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// RouterKey is the name of the auth module
+const RouterKey = ModuleName
+
+// MsgGrantFee - grant a fee allowance to an account, replacing any previous one
+type MsgGrantFee struct {
+	Granter     crypto.Address `json:"granter" yaml:"granter"`
+	Grantee     crypto.Address `json:"grantee" yaml:"grantee"`
+	SpendLimit  std.Coins      `json:"spend_limit" yaml:"spend_limit"`
+	Expiration  int64          `json:"expiration" yaml:"expiration"`
+	AllowedMsgs []MsgFilter    `json:"allowed_msgs" yaml:"allowed_msgs"`
+}
+
+var _ std.Msg = MsgGrantFee{}
+
+// NewMsgGrantFee - construct a msg to grant a fee allowance.
+func NewMsgGrantFee(granter, grantee crypto.Address, spendLimit std.Coins, expiration int64, allowedMsgs []MsgFilter) MsgGrantFee {
+	return MsgGrantFee{
+		Granter:     granter,
+		Grantee:     grantee,
+		SpendLimit:  spendLimit,
+		Expiration:  expiration,
+		AllowedMsgs: allowedMsgs,
+	}
+}
+
+// FeeGrant returns the fee grant set by the msg.
+func (msg MsgGrantFee) FeeGrant() FeeGrant {
+	return FeeGrant{
+		Granter:     msg.Granter,
+		Grantee:     msg.Grantee,
+		SpendLimit:  msg.SpendLimit,
+		Expiration:  msg.Expiration,
+		AllowedMsgs: msg.AllowedMsgs,
+	}
+}
+
+// Route Implements Msg.
+func (msg MsgGrantFee) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgGrantFee) Type() string { return "grant_fee" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgGrantFee) ValidateBasic() error {
+	return msg.FeeGrant().ValidateBasic()
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgGrantFee) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgGrantFee) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
+
+// MsgRevokeFee - revoke a fee allowance granted to an account
+type MsgRevokeFee struct {
+	Granter crypto.Address `json:"granter" yaml:"granter"`
+	Grantee crypto.Address `json:"grantee" yaml:"grantee"`
+}
+
+var _ std.Msg = MsgRevokeFee{}
+
+// NewMsgRevokeFee - construct a msg to revoke a fee allowance.
+func NewMsgRevokeFee(granter, grantee crypto.Address) MsgRevokeFee {
+	return MsgRevokeFee{Granter: granter, Grantee: grantee}
+}
+
+// Route Implements Msg.
+func (msg MsgRevokeFee) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRevokeFee) Type() string { return "revoke_fee" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRevokeFee) ValidateBasic() error {
+	if msg.Granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if msg.Grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRevokeFee) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRevokeFee) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
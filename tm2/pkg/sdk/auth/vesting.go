@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// VestingStatus is the status of the vesting coins of an account at a given
+// time, as returned by the vesting query.
+type VestingStatus struct {
+	Address         crypto.Address      `json:"address" yaml:"address"`
+	Schedule        std.VestingSchedule `json:"schedule" yaml:"schedule"` // nil, if the account doesn't vest.
+	OriginalVesting std.Coins           `json:"original_vesting" yaml:"original_vesting"`
+	Vested          std.Coins           `json:"vested" yaml:"vested"`
+	Locked          std.Coins           `json:"locked" yaml:"locked"`
+	Spendable       std.Coins           `json:"spendable" yaml:"spendable"`
+}
+
+// NewVestingStatus returns the vesting status of acc at blockTime.
+func NewVestingStatus(acc std.Account, blockTime time.Time) VestingStatus {
+	status := VestingStatus{
+		Address:   acc.GetAddress(),
+		Locked:    std.LockedCoins(acc, blockTime),
+		Spendable: std.SpendableCoins(acc, blockTime),
+	}
+	if vacc, ok := acc.(std.VestingAccount); ok && vacc.GetVestingSchedule() != nil {
+		status.Schedule = vacc.GetVestingSchedule()
+		status.OriginalVesting = status.Schedule.GetOriginalVesting()
+		status.Vested = status.Schedule.VestedCoins(blockTime)
+	}
+	return status
+}
@@ -2,4 +2,17 @@ package bank
 
 const (
 	ModuleName = "bank"
+
+	// SupplyStoreKeyPrefix prefix for supply-by-denom store
+	SupplyStoreKeyPrefix = "/s/"
+
+	// SupplyInitStoreKey is set once the supply is initialized from the
+	// coins of the accounts; see BankKeeper.InitSupply.
+	SupplyInitStoreKey = "/supply_init"
 )
+
+// SupplyStoreKey turns a denom to the key used to get its total supply from
+// the store.
+func SupplyStoreKey(denom string) []byte {
+	return append([]byte(SupplyStoreKeyPrefix), []byte(denom)...)
+}
@@ -5,12 +5,15 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
 )
 
 // RegisterInvariants registers the bank module invariants
-func RegisterInvariants(ir sdk.InvariantRegistry, acck auth.AccountKeeper) {
+func RegisterInvariants(ir sdk.InvariantRegistry, acck auth.AccountKeeper, bank BankKeeperI) {
 	ir.RegisterRoute(ModuleName, "nonnegative-outstanding",
 		NonnegativeBalanceInvariant(acck))
+	ir.RegisterRoute(ModuleName, "total-supply",
+		TotalSupplyInvariant(acck, bank))
 }
 
 // NonnegativeBalanceInvariant checks that all accounts in the application have non-negative balances
@@ -35,3 +38,25 @@ func NonnegativeBalanceInvariant(acck auth.AccountKeeper) sdk.Invariant {
 			fmt.Sprintf("amount of negative accounts found %d\n%s", count, msg)), broken
 	}
 }
+
+// TotalSupplyInvariant checks that the total supply of the coins equals the
+// sum of the coins of all the accounts.
+func TotalSupplyInvariant(acck auth.AccountKeeper, bank BankKeeperI) sdk.Invariant {
+	return func(ctx sdk.Context) (string, bool) {
+		expected := std.Coins{}
+		acck.IterateAccounts(ctx, func(acc std.Account) bool {
+			expected = expected.Add(acc.GetCoins())
+			return false
+		})
+		supply := bank.GetSupply(ctx)
+		broken := len(expected) != len(supply)
+		for _, coin := range expected {
+			if supply.AmountOf(coin.Denom) != coin.Amount {
+				broken = true
+			}
+		}
+
+		return sdk.FormatInvariant(ModuleName, "total-supply",
+			fmt.Sprintf("\tsum of accounts coins: %s\n\tsupply tracked: %s\n", expected, supply)), broken
+	}
+}
@@ -1,14 +1,17 @@
 package bank
 
 import (
+	"encoding/binary"
 	"fmt"
 	"log/slog"
 
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/overflow"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
 	"github.com/gnolang/gno/tm2/pkg/sdk/params"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
 // bank.Keeper defines a module interface that facilitates the transfer of
@@ -26,6 +29,8 @@ type BankKeeperI interface {
 
 	InitGenesis(ctx sdk.Context, data GenesisState)
 	GetParams(ctx sdk.Context) Params
+	GetSupply(ctx sdk.Context) std.Coins
+	InitSupply(ctx sdk.Context)
 }
 
 var _ BankKeeperI = &BankKeeper{}
@@ -35,15 +40,18 @@ var _ BankKeeperI = &BankKeeper{}
 type BankKeeper struct {
 	ViewKeeper
 
+	// The (unexposed) key used to access the supply from the Context.
+	key  store.StoreKey
 	acck auth.AccountKeeper
 	// The keeper used to store parameters
 	prmk params.ParamsKeeperI
 }
 
 // NewBankKeeper returns a new BankKeeper.
-func NewBankKeeper(acck auth.AccountKeeper, pk params.ParamsKeeperI) BankKeeper {
+func NewBankKeeper(key store.StoreKey, acck auth.AccountKeeper, pk params.ParamsKeeperI) BankKeeper {
 	return BankKeeper{
 		ViewKeeper: NewViewKeeper(acck),
+		key:        key,
 		acck:       acck,
 		prmk:       pk,
 	}
@@ -90,7 +98,7 @@ func (bank BankKeeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs
 		if !bank.canSendCoins(ctx, in.Address, in.Coins) {
 			return std.RestrictedTransferError{}
 		}
-		_, err := bank.SubtractCoins(ctx, in.Address, in.Coins)
+		_, err := bank.subtractCoins(ctx, in.Address, in.Coins)
 		if err != nil {
 			return err
 		}
@@ -106,7 +114,7 @@ func (bank BankKeeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs
 	}
 
 	for _, out := range outputs {
-		_, err := bank.AddCoins(ctx, out.Address, out.Coins)
+		_, err := bank.addCoins(ctx, out.Address, out.Coins)
 		if err != nil {
 			return err
 		}
@@ -164,12 +172,12 @@ func (bank BankKeeper) sendCoins(
 	toAddr crypto.Address,
 	amt std.Coins,
 ) error {
-	_, err := bank.SubtractCoins(ctx, fromAddr, amt)
+	_, err := bank.subtractCoins(ctx, fromAddr, amt)
 	if err != nil {
 		return err
 	}
 
-	_, err = bank.AddCoins(ctx, toAddr, amt)
+	_, err = bank.addCoins(ctx, toAddr, amt)
 	if err != nil {
 		return err
 	}
@@ -191,37 +199,65 @@ func (bank BankKeeper) sendCoins(
 	return nil
 }
 
-// SubtractCoins subtracts amt from the coins at the addr.
-//
-// CONTRACT: If the account is a vesting account, the amount has to be spendable.
+// SubtractCoins subtracts amt from the coins at the addr, and from the supply:
+// the coins are burnt.
+// If the account is a vesting account, the amount has to be spendable.
 func (bank BankKeeper) SubtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error) {
+	newCoins, err := bank.subtractCoins(ctx, addr, amt)
+	if err != nil {
+		return nil, err
+	}
+	bank.subtractSupply(ctx, amt)
+	return newCoins, nil
+}
+
+func (bank BankKeeper) subtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error) {
 	if !amt.IsValid() {
 		return nil, std.ErrInvalidCoins(amt.String())
 	}
 
 	oldCoins := std.NewCoins()
+	spendableCoins := std.NewCoins()
 	acc := bank.acck.GetAccount(ctx, addr)
 	if acc != nil {
 		oldCoins = acc.GetCoins()
+		spendableCoins = std.SpendableCoins(acc, ctx.BlockTime())
 	}
 
-	newCoins := oldCoins.SubUnsafe(amt)
-	if !newCoins.IsValid() {
-		err := std.ErrInsufficientCoins(
-			fmt.Sprintf("insufficient account funds; %s < %s", oldCoins, amt),
+	if !spendableCoins.SubUnsafe(amt).IsValid() {
+		if !oldCoins.SubUnsafe(amt).IsValid() {
+			return nil, std.ErrInsufficientCoins(
+				fmt.Sprintf("insufficient account funds; %s < %s", oldCoins, amt),
+			)
+		}
+		return nil, std.ErrInsufficientCoins(
+			fmt.Sprintf("insufficient spendable funds; %s < %s (locked: %s)",
+				spendableCoins, amt, std.LockedCoins(acc, ctx.BlockTime())),
 		)
-		return nil, err
 	}
-	err := bank.SetCoins(ctx, addr, newCoins)
+	newCoins := oldCoins.SubUnsafe(amt)
+	err := bank.setCoins(ctx, addr, newCoins)
 
 	return newCoins, err
 }
 
-// AddCoins adds amt to the coins at the addr.
+// AddCoins adds amt to the coins at the addr, and to the supply: the coins
+// are minted.
 func (bank BankKeeper) AddCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error) {
 	if !amt.IsValid() {
 		return nil, std.ErrInvalidCoins(amt.String())
 	}
+	// the supply is checked for overflows before the coins are added.
+	if err := bank.addSupply(ctx, amt); err != nil {
+		return amt, err
+	}
+	return bank.addCoins(ctx, addr, amt)
+}
+
+func (bank BankKeeper) addCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error) {
+	if !amt.IsValid() {
+		return nil, std.ErrInvalidCoins(amt.String())
+	}
 
 	oldCoins := bank.GetCoins(ctx, addr)
 	newCoins := oldCoins.Add(amt)
@@ -232,16 +268,30 @@ func (bank BankKeeper) AddCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 		)
 	}
 
-	err := bank.SetCoins(ctx, addr, newCoins)
+	err := bank.setCoins(ctx, addr, newCoins)
 	return newCoins, err
 }
 
-// SetCoins sets the coins at the addr.
+// SetCoins sets the coins at the addr, and updates the supply with the coins
+// minted or burnt.
 func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) error {
 	if !amt.IsValid() {
 		return std.ErrInvalidCoins(amt.String())
 	}
 
+	oldCoins := bank.GetCoins(ctx, addr)
+	if err := bank.setCoins(ctx, addr, amt); err != nil {
+		return err
+	}
+	bank.subtractSupply(ctx, oldCoins)
+	return bank.addSupply(ctx, amt)
+}
+
+func (bank BankKeeper) setCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) error {
+	if !amt.IsValid() {
+		return std.ErrInvalidCoins(amt.String())
+	}
+
 	acc := bank.acck.GetAccount(ctx, addr)
 	if acc == nil {
 		acc = bank.acck.NewAccountWithAddress(ctx, addr)
@@ -256,6 +306,94 @@ func (bank BankKeeper) SetCoins(ctx sdk.Context, addr crypto.Address, amt std.Co
 	return nil
 }
 
+// InitSupply initializes the supply with the sum of the coins of all the
+// accounts, unless it is already initialized. It is called at genesis, and
+// at the first block of the chains which ran before the supply was tracked.
+func (bank BankKeeper) InitSupply(ctx sdk.Context) {
+	stor := ctx.GasStore(bank.key)
+	if stor.Has([]byte(SupplyInitStoreKey)) {
+		return
+	}
+
+	// Clear the supply tracked so far, e.g. by the genesis balances.
+	var denoms []string
+	iter := store.PrefixIterator(stor, []byte(SupplyStoreKeyPrefix))
+	for ; iter.Valid(); iter.Next() {
+		denoms = append(denoms, string(iter.Key()[len(SupplyStoreKeyPrefix):]))
+	}
+	iter.Close()
+	for _, denom := range denoms {
+		bank.setSupplyOf(ctx, denom, 0)
+	}
+
+	supply := std.Coins{}
+	bank.acck.IterateAccounts(ctx, func(acc std.Account) bool {
+		supply = supply.Add(acc.GetCoins())
+		return false
+	})
+	for _, coin := range supply {
+		bank.setSupplyOf(ctx, coin.Denom, coin.Amount)
+	}
+	stor.Set([]byte(SupplyInitStoreKey), []byte{1})
+}
+
+// GetSupply returns the total supply of all the coins.
+func (bank BankKeeper) GetSupply(ctx sdk.Context) std.Coins {
+	stor := ctx.GasStore(bank.key)
+	iter := store.PrefixIterator(stor, []byte(SupplyStoreKeyPrefix))
+	defer iter.Close()
+
+	supply := std.Coins{}
+	for ; iter.Valid(); iter.Next() {
+		denom := string(iter.Key()[len(SupplyStoreKeyPrefix):])
+		supply = append(supply, std.NewCoin(denom, int64(binary.BigEndian.Uint64(iter.Value()))))
+	}
+	return supply
+}
+
+// GetSupplyOf returns the total supply of the coins of denom.
+func (bank BankKeeper) GetSupplyOf(ctx sdk.Context, denom string) int64 {
+	stor := ctx.GasStore(bank.key)
+	bz := stor.Get(SupplyStoreKey(denom))
+	if bz == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+func (bank BankKeeper) setSupplyOf(ctx sdk.Context, denom string, amount int64) {
+	stor := ctx.GasStore(bank.key)
+	if amount == 0 {
+		stor.Delete(SupplyStoreKey(denom))
+		return
+	}
+	stor.Set(SupplyStoreKey(denom), binary.BigEndian.AppendUint64(nil, uint64(amount)))
+}
+
+// addSupply adds the minted coins amt to the supply. It returns an error,
+// leaving the supply unchanged, if the supply of a denom would overflow.
+func (bank BankKeeper) addSupply(ctx sdk.Context, amt std.Coins) error {
+	supply := make([]int64, len(amt))
+	for i, coin := range amt {
+		sum, ok := overflow.Add(bank.GetSupplyOf(ctx, coin.Denom), coin.Amount)
+		if !ok {
+			return std.ErrInvalidCoins(fmt.Sprintf("supply of %s overflows", coin.Denom))
+		}
+		supply[i] = sum
+	}
+	for i, coin := range amt {
+		bank.setSupplyOf(ctx, coin.Denom, supply[i])
+	}
+	return nil
+}
+
+// subtractSupply subtracts the burnt coins amt from the supply.
+func (bank BankKeeper) subtractSupply(ctx sdk.Context, amt std.Coins) {
+	for _, coin := range amt {
+		bank.setSupplyOf(ctx, coin.Denom, bank.GetSupplyOf(ctx, coin.Denom)-coin.Amount)
+	}
+}
+
 // ----------------------------------------
 // ViewKeeper
 
@@ -292,6 +430,16 @@ func (view ViewKeeper) GetCoins(ctx sdk.Context, addr crypto.Address) std.Coins
 	return acc.GetCoins()
 }
 
+// SpendableCoins returns the coins at the addr which are not locked by a
+// vesting schedule.
+func (view ViewKeeper) SpendableCoins(ctx sdk.Context, addr crypto.Address) std.Coins {
+	acc := view.acck.GetAccount(ctx, addr)
+	if acc == nil {
+		return std.NewCoins()
+	}
+	return std.SpendableCoins(acc, ctx.BlockTime())
+}
+
 // HasCoins returns whether or not an account has at least amt coins.
 func (view ViewKeeper) HasCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) bool {
 	return view.GetCoins(ctx, addr).IsAllGTE(amt)
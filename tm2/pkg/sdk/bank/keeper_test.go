@@ -1,6 +1,7 @@
 package bank
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -184,3 +185,82 @@ func TestSetRestrictedDenoms(t *testing.T) {
 	params = bankk.GetParams(ctx)
 	require.Empty(t, params.RestrictedDenoms)
 }
+
+func TestKeeperSupply(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+
+	ir := sdk.NewInvariantRouter()
+	RegisterInvariants(ir, env.acck, env.bankk)
+	require.Empty(t, ir.CheckInvariants(ctx))
+
+	// minted coins are added to the supply.
+	require.NoError(t, env.bankk.SetCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 10))))
+	_, err := env.bankk.AddCoins(ctx, addr2, std.NewCoins(std.NewCoin("foocoin", 5), std.NewCoin("barcoin", 3)))
+	require.NoError(t, err)
+	require.True(t, env.bankk.GetSupply(ctx).IsEqual(std.NewCoins(std.NewCoin("foocoin", 15), std.NewCoin("barcoin", 3))))
+
+	// transfers don't change the supply.
+	require.NoError(t, env.bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 4))))
+	require.Equal(t, int64(15), env.bankk.GetSupplyOf(ctx, "foocoin"))
+
+	// burnt coins are subtracted from the supply.
+	_, err = env.bankk.SubtractCoins(ctx, addr2, std.NewCoins(std.NewCoin("barcoin", 3)))
+	require.NoError(t, err)
+	require.NoError(t, env.bankk.SetCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 1))))
+	require.True(t, env.bankk.GetSupply(ctx).IsEqual(std.NewCoins(std.NewCoin("foocoin", 10))))
+	require.Empty(t, ir.CheckInvariants(ctx))
+
+	// coins set on an account without the bank keeper break the invariant.
+	acc := env.acck.GetAccount(ctx, addr)
+	acc.SetCoins(std.NewCoins(std.NewCoin("foocoin", 2)))
+	env.acck.SetAccount(ctx, acc)
+	broken := ir.CheckInvariants(ctx)
+	require.Len(t, broken, 1)
+	require.Contains(t, broken[0], "bank: total-supply invariant")
+
+	// the supply can't overflow.
+	addr3 := crypto.AddressFromPreimage([]byte("addr3"))
+	_, err = env.bankk.AddCoins(ctx, addr3, std.NewCoins(std.NewCoin("foocoin", math.MaxInt64)))
+	require.ErrorIs(t, err, std.InvalidCoinsError{})
+	require.True(t, env.bankk.GetCoins(ctx, addr3).IsZero())
+	require.Equal(t, int64(10), env.bankk.GetSupplyOf(ctx, "foocoin"))
+}
+
+func TestKeeperInitSupply(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx
+
+	ir := sdk.NewInvariantRouter()
+	RegisterInvariants(ir, env.acck, env.bankk)
+
+	// accounts with coins, from before the supply was tracked.
+	for i, coins := range []std.Coins{
+		std.NewCoins(std.NewCoin("foocoin", 10)),
+		std.NewCoins(std.NewCoin("foocoin", 5), std.NewCoin("barcoin", 3)),
+	} {
+		acc := env.acck.NewAccountWithAddress(ctx, crypto.AddressFromPreimage([]byte{byte(i)}))
+		acc.SetCoins(coins)
+		env.acck.SetAccount(ctx, acc)
+	}
+	require.Len(t, ir.CheckInvariants(ctx), 1)
+
+	// the supply is initialized from the accounts.
+	env.bankk.InitSupply(ctx)
+	require.True(t, env.bankk.GetSupply(ctx).IsEqual(std.NewCoins(std.NewCoin("foocoin", 15), std.NewCoin("barcoin", 3))))
+	require.Empty(t, ir.CheckInvariants(ctx))
+
+	// and only once.
+	_, err := env.bankk.AddCoins(ctx, crypto.AddressFromPreimage([]byte("addr")), std.NewCoins(std.NewCoin("foocoin", 1)))
+	require.NoError(t, err)
+	env.bankk.InitSupply(ctx)
+	require.Equal(t, int64(16), env.bankk.GetSupplyOf(ctx, "foocoin"))
+	require.Empty(t, ir.CheckInvariants(ctx))
+}
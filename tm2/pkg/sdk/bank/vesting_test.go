@@ -0,0 +1,97 @@
+package bank_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/sdk/params"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+)
+
+// vestingAccount is a std.VestingAccount for tests, the concrete vesting
+// accounts being defined by the applications.
+type vestingAccount struct {
+	std.BaseAccount
+	Vesting std.VestingSchedule
+}
+
+var _ = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank_test",
+	"bank_test",
+	amino.GetCallersDirname(),
+).WithDependencies(
+	std.Package,
+).WithTypes(
+	&vestingAccount{}, "VestingAccount",
+))
+
+func (acc *vestingAccount) GetVestingSchedule() std.VestingSchedule {
+	return acc.Vesting
+}
+
+func (acc *vestingAccount) SetVestingSchedule(vesting std.VestingSchedule) error {
+	acc.Vesting = vesting
+	return nil
+}
+
+func TestBankKeeperVesting(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+	authCapKey := store.NewStoreKey("authCapKey")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(authCapKey, iavl.StoreConstructor, db)
+	ms.LoadLatestVersion()
+	baseCtx := sdk.NewContext(sdk.RunTxModeDeliver, ms, &bft.Header{ChainID: "test-chain-id"}, log.NewNoopLogger())
+	atTime := func(sec int64) sdk.Context {
+		return baseCtx.WithBlockHeader(&bft.Header{ChainID: "test-chain-id", Time: time.Unix(sec, 0)})
+	}
+
+	prmk := params.NewParamsKeeper(authCapKey)
+	acck := auth.NewAccountKeeper(authCapKey, prmk.ForModule(auth.ModuleName), std.ProtoBaseAccount)
+	bankk := bank.NewBankKeeper(authCapKey, acck, prmk.ForModule(bank.ModuleName))
+	prmk.Register(auth.ModuleName, acck)
+	prmk.Register(bank.ModuleName, bankk)
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	acc := &vestingAccount{
+		BaseAccount: std.NewBaseAccountWithAddress(addr),
+		Vesting:     std.NewContinuousVesting(std.NewCoins(std.NewCoin("foocoin", 100)), 1000, 2000),
+	}
+	acc.SetCoins(std.NewCoins(std.NewCoin("foocoin", 100), std.NewCoin("barcoin", 10)))
+	acck.SetAccount(baseCtx, acc)
+
+	// all the vesting coins are locked before the start time
+	ctx := atTime(500)
+	require.True(t, bankk.SpendableCoins(ctx, addr).IsEqual(std.NewCoins(std.NewCoin("barcoin", 10))))
+	require.Error(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 1))))
+	require.NoError(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("barcoin", 10))))
+
+	// the vesting coins are unlocked linearly
+	ctx = atTime(1250)
+	require.True(t, bankk.SpendableCoins(ctx, addr).IsEqual(std.NewCoins(std.NewCoin("foocoin", 25))))
+	require.Error(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 26))))
+	require.NoError(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 25))))
+
+	// received coins are spendable
+	require.NoError(t, bankk.SendCoins(ctx, addr2, addr, std.NewCoins(std.NewCoin("foocoin", 5))))
+	require.True(t, bankk.SpendableCoins(ctx, addr).IsEqual(std.NewCoins(std.NewCoin("foocoin", 5))))
+
+	// all the coins are spendable after the end time
+	ctx = atTime(2000)
+	require.True(t, bankk.SpendableCoins(ctx, addr).IsEqual(bankk.GetCoins(ctx, addr)))
+	require.NoError(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 80))))
+}
@@ -1,6 +1,8 @@
 package sdk
 
 import (
+	"bytes"
+	goerrors "errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -12,10 +14,12 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // Key to store the consensus params in the main store.
@@ -44,6 +48,13 @@ type BaseApp struct {
 
 	beginTxHook BeginTxHook // BaseApp-specific hook run before running transaction messages.
 	endTxHook   EndTxHook   // BaseApp-specific hook run after running transaction messages.
+	restoreHook RestoreHook // hook run after the state is restored from a snapshot
+
+	// verifies the base store entries restored from a snapshot
+	restoreVerifier RestoreVerifier
+
+	// state sync snapshots, nil if not supported
+	snapshots *snapshots.Manager
 
 	// --------------------
 	// Volatile state
@@ -508,12 +519,40 @@ func handleQueryCustom(app *BaseApp, path []string, req abci.RequestQuery) (res
 	// cache wrap the commit-multistore for safety
 	// XXX RunTxModeQuery?
 	ctx := NewContext(RunTxModeCheck, cacheMS, app.checkState.ctx.BlockHeader(), app.logger).WithMinGasPrices(app.minGasPrices)
+	if req.Prove {
+		if queryable, ok := app.cms.(store.Queryable); ok {
+			ctx = ctx.WithValue(StoreProverContextKey{}, storeProver(queryable, req.Height))
+		}
+	}
 
 	// Passes the query to the handler.
 	res = handler.Query(ctx, req)
+	if req.Prove {
+		res.Height = req.Height
+	}
 	return
 }
 
+// storeProver returns a StoreProver querying the proofs of the committed
+// stores at the given height.
+func storeProver(queryable store.Queryable, height int64) StoreProver {
+	return func(storeKey store.StoreKey, key []byte) ([]byte, *merkle.Proof, error) {
+		res := queryable.Query(abci.RequestQuery{
+			Path:   "/" + storeKey.Name() + "/key",
+			Data:   key,
+			Height: height,
+			Prove:  true,
+		})
+		if res.Error != nil {
+			return nil, nil, res.Error
+		}
+		if res.Proof == nil {
+			return nil, nil, errors.New("no proof for key %X: %s", key, res.Log)
+		}
+		return res.Value, res.Proof, nil
+	}
+}
+
 func (app *BaseApp) validateHeight(req abci.RequestBeginBlock) error {
 	if req.Header.GetHeight() < 1 {
 		return fmt.Errorf("invalid height: %d", req.Header.GetHeight())
@@ -945,11 +984,159 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	// empty/reset the deliver state
 	app.deliverState = nil
 
+	// Take the state sync snapshot of the height, if any.
+	// NOTE: the snapshot is written in the background, from a snapshot
+	// of the dbs taken here, as the base store is not versioned.
+	if height := header.GetHeight(); app.snapshots != nil && app.snapshots.ShouldCreate(height) {
+		if err := app.snapshots.Create(height); err != nil {
+			app.logger.Error("unable to create snapshot", "height", height, "err", err)
+		}
+	}
+
 	// return.
 	res.Data = commitID.Hash
 	return
 }
 
+// ----------------------------------------------------------------------------
+// State Sync
+
+// ListSnapshots implements the ABCI interface.
+func (app *BaseApp) ListSnapshots(req abci.RequestListSnapshots) (res abci.ResponseListSnapshots) {
+	if app.snapshots == nil {
+		return
+	}
+
+	snapshots, err := app.snapshots.List()
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+
+	res.Snapshots = snapshots
+	return
+}
+
+// OfferSnapshot implements the ABCI interface. The snapshot is accepted
+// only if the app state is empty, and the snapshot format is supported.
+func (app *BaseApp) OfferSnapshot(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot) {
+	if app.snapshots == nil {
+		res.Error = ABCIError(errors.New("snapshots are not supported"))
+		return
+	}
+
+	if height := app.LastBlockHeight(); height != 0 {
+		res.Error = ABCIError(errors.New("cannot restore a snapshot, found existing state at height %d", height))
+		return
+	}
+
+	if req.Snapshot == nil {
+		res.Error = ABCIError(errors.New("nil snapshot"))
+		return
+	}
+
+	if err := app.snapshots.Offer(req.Snapshot, req.AppHash, app.verifyRestoredState(req.Snapshot.Height)); err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+
+	return
+}
+
+// verifyRestoredState returns the function which verifies the state restored
+// from the snapshot of the given height, before it is saved. The app hash only
+// covers the IAVL stores: the entries of the base store are verified by the
+// restore verifier, except for the last header, which is only used to set up
+// the check state until the next block.
+func (app *BaseApp) verifyRestoredState(height int64) func(store.MultiStore) error {
+	return func(ms store.MultiStore) error {
+		headerBz := ms.GetStore(app.baseKey).Get(mainLastHeaderKey)
+		if headerBz == nil {
+			return errors.New("missing last header")
+		}
+
+		header := &bft.Header{}
+		if err := amino.Unmarshal(headerBz, header); err != nil {
+			return errors.Wrap(err, "invalid last header")
+		}
+
+		if header.Height != height {
+			return errors.New("last header height %d does not match the snapshot height %d", header.Height, height)
+		}
+
+		if app.restoreVerifier == nil {
+			return nil
+		}
+
+		return app.restoreVerifier(NewContext(RunTxModeDeliver, ms, header, app.logger))
+	}
+}
+
+// IsBaseAppKey returns true if key is the key of an entry of the BaseApp in
+// the base store, e.g. for restore verifiers to tell them apart.
+func IsBaseAppKey(key []byte) bool {
+	return bytes.Equal(key, mainLastHeaderKey)
+}
+
+// LoadSnapshotChunk implements the ABCI interface.
+func (app *BaseApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk) {
+	if app.snapshots == nil {
+		res.Error = ABCIError(errors.New("snapshots are not supported"))
+		return
+	}
+
+	chunk, err := app.snapshots.LoadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+
+	res.Chunk = chunk
+	return
+}
+
+// ApplySnapshotChunk implements the ABCI interface. Once the last chunk is
+// applied, the restored state is verified against the app hash and by the
+// restore verifier, and the app is initialized from the restored state.
+func (app *BaseApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+	if app.snapshots == nil {
+		res.Error = ABCIError(errors.New("snapshots are not supported"))
+		return
+	}
+
+	done, err := app.snapshots.ApplyChunk(req.Index, req.Chunk)
+	if goerrors.Is(err, snapshots.ErrInvalidChunk) {
+		// Fetch the chunk again, from another peer
+		app.logger.Info("invalid snapshot chunk", "index", req.Index, "sender", req.Sender)
+
+		res.RefetchChunks = []uint32{req.Index}
+		res.RejectSenders = []string{req.Sender}
+		return
+	}
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+
+	if !done {
+		return
+	}
+
+	if err := app.initFromMainStore(); err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+
+	if app.restoreHook != nil {
+		ms := app.cms.MultiCacheWrap()
+		app.restoreHook(NewContext(RunTxModeDeliver, ms, app.checkState.ctx.BlockHeader(), app.logger))
+		ms.MultiWrite()
+	}
+
+	app.logger.Info("restored state from snapshot", "height", app.LastBlockHeight())
+	return
+}
+
 // halt attempts to gracefully shutdown the node via SIGINT and SIGTERM falling
 // back on os.Exit if both fail.
 func (app *BaseApp) halt() {
@@ -977,6 +1164,10 @@ func (app *BaseApp) Close() error {
 		return nil
 	}
 
+	if app.snapshots != nil {
+		app.snapshots.Wait()
+	}
+
 	app.logger.Info("Closing application.db")
 
 	if err := app.db.Close(); err != nil {
@@ -3,6 +3,7 @@ package sdk
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -22,6 +23,8 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 var (
@@ -1232,6 +1235,56 @@ func TestQuery(t *testing.T) {
 	require.Equal(t, value, res.Value)
 }
 
+func TestQueryCustomProve(t *testing.T) {
+	t.Parallel()
+
+	key, value := []byte("hello"), []byte("goodbye")
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, testHandler{
+			process: func(ctx Context, msg Msg) Result {
+				ctx.Store(mainKey).Set(key, value)
+				return Result{}
+			},
+			query: func(ctx Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+				prover, ok := ctx.Value(StoreProverContextKey{}).(StoreProver)
+				if !ok {
+					res.Data = []byte("no prover")
+					return
+				}
+				var err error
+				res.Value, res.Proof, err = prover(mainKey, key)
+				if err != nil {
+					res.Error = ABCIError(std.ErrInternal(err.Error()))
+				}
+				return
+			},
+		})
+	}
+
+	app := setupBaseApp(t, routerOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+	for i := range 2 {
+		header := &bft.Header{ChainID: "test-chain", Height: app.LastBlockHeight() + 1}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		resTx := app.Deliver(newTxCounter(int64(i), 0))
+		require.True(t, resTx.IsOK(), fmt.Sprintf("%v", resTx))
+		app.EndBlock(abci.RequestEndBlock{})
+		app.Commit()
+	}
+
+	// Only the queries requesting a proof have a prover.
+	res := app.Query(abci.RequestQuery{Path: routeMsgCounter})
+	require.Equal(t, "no prover", string(res.Data))
+
+	res = app.Query(abci.RequestQuery{Path: routeMsgCounter, Prove: true})
+	require.Nil(t, res.Error)
+	require.Equal(t, value, res.Value)
+	require.Equal(t, app.LastBlockHeight(), res.Height)
+	prt := rootmulti.DefaultProofRuntime()
+	require.NoError(t, prt.VerifyValue(res.Proof, app.LastCommitID().Hash, "/main/hello", value))
+	require.Error(t, prt.VerifyValue(res.Proof, app.LastCommitID().Hash, "/main/hello", []byte("hello")))
+}
+
 func TestGetMaximumBlockGas(t *testing.T) {
 	app := setupBaseApp(t)
 
@@ -1247,3 +1300,109 @@ func TestGetMaximumBlockGas(t *testing.T) {
 	app.setConsensusParams(&abci.ConsensusParams{Block: &abci.BlockParams{MaxGas: -5000000}})
 	require.Panics(t, func() { app.getMaximumBlockGas() })
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	app := setupBaseApp(t, SetSnapshotOptions(t.TempDir(), snapshots.Options{Interval: 2, KeepRecent: 1}))
+
+	k, v := []byte("key"), []byte("value")
+
+	var lastCommit abci.ResponseCommit
+	for height := int64(1); height <= 2; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.deliverState.ms.GetStore(mainKey).Set(k, v)
+		app.deliverState.ms.GetStore(baseKey).Set(k, v)
+		lastCommit = app.Commit()
+		require.Nil(t, lastCommit.Error)
+	}
+
+	// A snapshot was taken at height 2, and written in the background
+	app.snapshots.Wait()
+
+	listRes := app.ListSnapshots(abci.RequestListSnapshots{})
+	require.Nil(t, listRes.Error)
+	require.Len(t, listRes.Snapshots, 1)
+
+	snapshot := listRes.Snapshots[0]
+	assert.Equal(t, int64(2), snapshot.Height)
+
+	// Snapshots are only restored in empty apps
+	offerRes := app.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: lastCommit.Data})
+	assert.NotNil(t, offerRes.Error)
+
+	// Snapshots must be supported to be restored
+	offerRes = setupBaseApp(t).OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: lastCommit.Data})
+	assert.NotNil(t, offerRes.Error)
+
+	restoreApp := func(t *testing.T, appHash []byte, verifyErr error) (*BaseApp, abci.ResponseApplySnapshotChunk, bool) {
+		t.Helper()
+
+		var restored bool
+
+		restoredApp := newBaseApp(t.Name(), memdb.NewMemDB(), SetSnapshotOptions(t.TempDir(), snapshots.Options{}))
+		restoredApp.SetRestoreVerifier(func(ctx Context) error {
+			// The base store entries are verified before the state is saved
+			assert.Equal(t, v, ctx.Store(baseKey).Get(k))
+			assert.Equal(t, int64(2), ctx.BlockHeight())
+			assert.Zero(t, restoredApp.LastBlockHeight())
+
+			return verifyErr
+		})
+		restoredApp.SetRestoreHook(func(ctx Context) {
+			restored = true
+
+			assert.Equal(t, v, ctx.Store(mainKey).Get(k))
+		})
+		require.NoError(t, restoredApp.LoadLatestVersion())
+
+		offerRes := restoredApp.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: snapshot, AppHash: appHash})
+		require.Nil(t, offerRes.Error)
+
+		var applyRes abci.ResponseApplySnapshotChunk
+		for i := range snapshot.Chunks {
+			loadRes := app.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+				Height: snapshot.Height,
+				Format: snapshot.Format,
+				Chunk:  i,
+			})
+			require.Nil(t, loadRes.Error)
+
+			// Invalid chunks are fetched again
+			invalidRes := restoredApp.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: []byte("invalid"), Sender: "peer"})
+			require.Nil(t, invalidRes.Error)
+			assert.Equal(t, []uint32{i}, invalidRes.RefetchChunks)
+			assert.Equal(t, []string{"peer"}, invalidRes.RejectSenders)
+
+			applyRes = restoredApp.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: loadRes.Chunk})
+		}
+
+		return restoredApp, applyRes, restored
+	}
+
+	// The restored app hash must match the expected one
+	_, applyRes, restored := restoreApp(t, []byte("invalid app hash"), nil)
+	assert.NotNil(t, applyRes.Error)
+	assert.False(t, restored)
+
+	// The restored state must pass the restore verifier
+	rejectedApp, applyRes, restored := restoreApp(t, lastCommit.Data, errors.New("forged entry"))
+	assert.NotNil(t, applyRes.Error)
+	assert.False(t, restored)
+	assert.Zero(t, rejectedApp.LastBlockHeight())
+	assert.Nil(t, rejectedApp.cms.GetStore(baseKey).Get(k))
+
+	restoredApp, applyRes, restored := restoreApp(t, lastCommit.Data, nil)
+	require.Nil(t, applyRes.Error)
+	assert.True(t, restored)
+
+	assert.Equal(t, app.LastCommitID(), restoredApp.LastCommitID())
+	assert.Equal(t, int64(2), restoredApp.checkState.ctx.BlockHeight())
+
+	// The restored app keeps going from the restored height
+	header := &bft.Header{ChainID: "test-chain", Height: 3}
+	restoredApp.BeginBlock(abci.RequestBeginBlock{Header: header})
+	restoredApp.Commit()
+	assert.Equal(t, int64(3), restoredApp.LastBlockHeight())
+}
@@ -14,6 +14,9 @@ import (
 var (
 	ErrInvalidMinGasPrices  = errors.New("invalid min gas prices")
 	ErrInvalidPruneStrategy = errors.New("invalid prune strategy")
+
+	ErrInvalidSnapshotInterval   = errors.New("snapshot interval cannot be negative")
+	ErrInvalidSnapshotKeepRecent = errors.New("snapshot keep recent must be positive")
 )
 
 // AppConfig defines the configuration options for the Application
@@ -23,13 +26,21 @@ type AppConfig struct {
 
 	// The enforced state pruning stategy for the app
 	PruneStrategy types.PruneStrategy `json:"prune_strategy" toml:"prune_strategy" comment:"State pruning strategy [everything, nothing, syncable]"`
+
+	// The block interval at which state sync snapshots are taken, 0 to disable them
+	SnapshotInterval int64 `json:"snapshot_interval" toml:"snapshot_interval" comment:"Block interval at which state sync snapshots are taken (0 to disable)"`
+
+	// The number of most recent state sync snapshots to keep
+	SnapshotKeepRecent int `json:"snapshot_keep_recent" toml:"snapshot_keep_recent" comment:"Number of recent state sync snapshots to keep"`
 }
 
 // DefaultAppConfig returns a default configuration for the application
 func DefaultAppConfig() *AppConfig {
 	return &AppConfig{
-		MinGasPrices:  "",
-		PruneStrategy: types.PruneSyncableStrategy,
+		MinGasPrices:       "",
+		PruneStrategy:      types.PruneSyncableStrategy,
+		SnapshotInterval:   0,
+		SnapshotKeepRecent: 2,
 	}
 }
 
@@ -50,5 +61,13 @@ func (cfg *AppConfig) ValidateBasic() error {
 		return fmt.Errorf("%w: %q", ErrInvalidPruneStrategy, cfg.PruneStrategy)
 	}
 
+	if cfg.SnapshotInterval < 0 {
+		return ErrInvalidSnapshotInterval
+	}
+
+	if cfg.SnapshotKeepRecent <= 0 {
+		return ErrInvalidSnapshotKeepRecent
+	}
+
 	return nil
 }
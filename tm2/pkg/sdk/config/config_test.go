@@ -65,6 +65,24 @@ func TestConfig_ValidateBasic(t *testing.T) {
 		assert.NoError(t, cfg.ValidateBasic())
 	})
 
+	t.Run("invalid snapshot interval", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.SnapshotInterval = -1
+
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshotInterval)
+	})
+
+	t.Run("invalid snapshot keep recent", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.SnapshotKeepRecent = 0
+
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshotKeepRecent)
+	})
+
 	t.Run("valid default config", func(t *testing.T) {
 		t.Parallel()
 
@@ -20,3 +20,56 @@ type InvariantRegistry interface {
 func FormatInvariant(module, name, msg string) string {
 	return fmt.Sprintf("%s: %s invariant\n%s\n", module, name, msg)
 }
+
+// InvariantRoute is an invariant registered by a module under a route.
+type InvariantRoute struct {
+	ModuleName string
+	Route      string
+	Invar      Invariant
+}
+
+// FullRoute returns the route of the invariant prefixed by its module name.
+func (ir InvariantRoute) FullRoute() string {
+	return ir.ModuleName + "/" + ir.Route
+}
+
+// InvariantRouter is an InvariantRegistry which checks the invariants
+// registered by the modules.
+type InvariantRouter struct {
+	routes []InvariantRoute
+}
+
+var _ InvariantRegistry = &InvariantRouter{}
+
+// NewInvariantRouter returns a new InvariantRouter without invariants.
+func NewInvariantRouter() *InvariantRouter {
+	return &InvariantRouter{}
+}
+
+// RegisterRoute implements InvariantRegistry.
+func (ir *InvariantRouter) RegisterRoute(moduleName, route string, invar Invariant) {
+	for _, r := range ir.routes {
+		if r.ModuleName == moduleName && r.Route == route {
+			panic(fmt.Sprintf("invariant %s/%s already registered", moduleName, route))
+		}
+	}
+	ir.routes = append(ir.routes, InvariantRoute{moduleName, route, invar})
+}
+
+// Routes returns the registered invariants, in the order of their
+// registration.
+func (ir *InvariantRouter) Routes() []InvariantRoute {
+	return ir.routes
+}
+
+// CheckInvariants runs the registered invariants against the state of ctx,
+// without modifying it, and returns the messages of the broken ones.
+func (ir *InvariantRouter) CheckInvariants(ctx Context) (broken []string) {
+	for _, r := range ir.routes {
+		cctx, _ := ctx.CacheContext() // discarded.
+		if msg, isBroken := r.Invar(cctx); isBroken {
+			broken = append(broken, msg)
+		}
+	}
+	return broken
+}
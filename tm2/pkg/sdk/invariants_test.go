@@ -0,0 +1,42 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+)
+
+func TestInvariantRouter(t *testing.T) {
+	db := memdb.NewMemDB()
+	key := store.NewStoreKey("test")
+	ms := store.NewCommitMultiStore(db)
+	ms.MountStoreWithDB(key, iavl.StoreConstructor, db)
+	require.NoError(t, ms.LoadLatestVersion())
+	ctx := NewContext(RunTxModeDeliver, ms, &bft.Header{ChainID: "test-chain-id"}, log.NewNoopLogger())
+
+	ir := NewInvariantRouter()
+	ir.RegisterRoute("foo", "ok", func(ctx Context) (string, bool) {
+		return FormatInvariant("foo", "ok", ""), false
+	})
+	ir.RegisterRoute("foo", "broken", func(ctx Context) (string, bool) {
+		// the invariants can't modify the state.
+		ctx.Store(key).Set([]byte("key"), []byte("value"))
+		return FormatInvariant("foo", "broken", "oops"), true
+	})
+	require.Len(t, ir.Routes(), 2)
+	require.Equal(t, "foo/broken", ir.Routes()[1].FullRoute())
+
+	// require panic on duplicate route
+	require.Panics(t, func() {
+		ir.RegisterRoute("foo", "ok", nil)
+	})
+
+	require.Equal(t, []string{"foo: broken invariant\noops\n"}, ir.CheckInvariants(ctx))
+	require.False(t, ctx.Store(key).Has([]byte("key")))
+}
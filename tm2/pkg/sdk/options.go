@@ -5,6 +5,7 @@ import (
 
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // File for storing in-package BaseApp optional functions,
@@ -29,6 +30,14 @@ func SetMinGasPrices(gasPricesStr string) func(*BaseApp) {
 	return func(bap *BaseApp) { bap.setMinGasPrices(gasPrices) }
 }
 
+// SetSnapshotOptions returns an option that sets the state sync snapshot
+// options of the app, keeping the snapshots in dir.
+func SetSnapshotOptions(dir string, opts snapshots.Options) func(*BaseApp) {
+	return func(bap *BaseApp) {
+		bap.snapshots = snapshots.NewManager(dir, bap.cms, opts, bap.logger)
+	}
+}
+
 func (app *BaseApp) SetName(name string) {
 	if app.sealed {
 		panic("SetName() on sealed BaseApp")
@@ -99,3 +108,17 @@ func (app *BaseApp) SetEndTxHook(endTx EndTxHook) {
 	}
 	app.endTxHook = endTx
 }
+
+func (app *BaseApp) SetRestoreHook(restore RestoreHook) {
+	if app.sealed {
+		panic("SetRestoreHook() on sealed BaseApp")
+	}
+	app.restoreHook = restore
+}
+
+func (app *BaseApp) SetRestoreVerifier(verify RestoreVerifier) {
+	if app.sealed {
+		panic("SetRestoreVerifier() on sealed BaseApp")
+	}
+	app.restoreVerifier = verify
+}
@@ -2,7 +2,9 @@ package sdk
 
 import (
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
 )
 
 // Router provides handlers for each transaction type.
@@ -20,6 +22,14 @@ type Handler interface {
 	Query(ctx Context, req abci.RequestQuery) abci.ResponseQuery
 }
 
+// StoreProver returns the value of key in the committed store of storeKey,
+// with its merkle proof up to the app hash, at the height of a query.
+type StoreProver func(storeKey store.StoreKey, key []byte) (value []byte, proof *merkle.Proof, err error)
+
+// StoreProverContextKey is the context key of the [StoreProver] of the
+// custom queries which request a proof.
+type StoreProverContextKey struct{}
+
 // Result is the union of ResponseDeliverTx and ResponseCheckTx plus events.
 type Result struct {
 	abci.ResponseBase
@@ -12,6 +12,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 
 	// Account
 	&BaseAccount{}, "BaseAccount",
+	// Vesting
+	ContinuousVesting{}, "ContinuousVesting",
+	PeriodicVesting{}, "PeriodicVesting",
+	VestingPeriod{}, "VestingPeriod",
 	// Coin
 	&Coin{}, "Coin",
 	// GasPrice
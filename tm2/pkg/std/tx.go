@@ -19,7 +19,8 @@ var (
 )
 
 // Tx is a standard way to wrap a Msg with Fee and Signatures.
-// NOTE: the first signature is the fee payer (Signatures must not be nil).
+// NOTE: the first signature is the fee payer (Signatures must not be nil),
+// unless Fee.FeePayer is set.
 type Tx struct {
 	Msgs       []Msg       `json:"msg" yaml:"msg"`
 	Fee        Fee         `json:"fee" yaml:"fee"`
@@ -50,6 +51,11 @@ func (tx Tx) ValidateBasic() error {
 	if !tx.Fee.GasFee.IsValid() {
 		return ErrInsufficientFee(fmt.Sprintf("invalid fee %s amount provided", tx.Fee.GasFee))
 	}
+	if tx.Fee.FeePayer != "" {
+		if _, err := crypto.AddressFromBech32(string(tx.Fee.FeePayer)); err != nil {
+			return ErrInvalidAddress(fmt.Sprintf("invalid fee payer %q: %v", tx.Fee.FeePayer, err))
+		}
+	}
 	if len(stdSigs) == 0 {
 		return ErrNoSignatures("no signers")
 	}
@@ -123,9 +129,13 @@ func (tx Tx) GetSignBytes(chainID string, accountNumber uint64, sequence uint64)
 // Fee includes the amount of coins paid in fees and the maximum
 // gas to be used by the transaction. The ratio yields an effective "gasprice",
 // which must be above some miminum to be accepted into the mempool.
+//
+// The fee is paid by the first signer, or by FeePayer if it is set, which must
+// have granted a fee allowance to the first signer (see auth.FeeGrant).
 type Fee struct {
-	GasWanted int64 `json:"gas_wanted" yaml:"gas_wanted"`
-	GasFee    Coin  `json:"gas_fee" yaml:"gas_fee"`
+	GasWanted int64                `json:"gas_wanted" yaml:"gas_wanted"`
+	GasFee    Coin                 `json:"gas_fee" yaml:"gas_fee"`
+	FeePayer  crypto.Bech32Address `json:"fee_payer,omitempty" yaml:"fee_payer,omitempty"`
 }
 
 // NewFee returns a new instance of Fee
@@ -0,0 +1,236 @@
+package std
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// VestingAccount is an account whose coins are partially locked until they
+// vest, according to its vesting schedule.
+type VestingAccount interface {
+	Account
+
+	GetVestingSchedule() VestingSchedule // nil if the account doesn't vest.
+	SetVestingSchedule(VestingSchedule) error
+}
+
+// VestingSchedule defines how the original vesting coins of an account are
+// unlocked over time.
+type VestingSchedule interface {
+	GetOriginalVesting() Coins
+	GetStartTime() int64
+	GetEndTime() int64
+
+	// VestedCoins returns the part of the original vesting coins which is
+	// unlocked at blockTime.
+	VestedCoins(blockTime time.Time) Coins
+
+	ValidateBasic() error
+	String() string
+}
+
+// LockedCoins returns the coins of acc which are still locked at blockTime;
+// none, if acc is not a vesting account.
+func LockedCoins(acc Account, blockTime time.Time) Coins {
+	vacc, ok := acc.(VestingAccount)
+	if !ok || vacc.GetVestingSchedule() == nil {
+		return nil
+	}
+	schedule := vacc.GetVestingSchedule()
+	return schedule.GetOriginalVesting().SubUnsafe(schedule.VestedCoins(blockTime))
+}
+
+// SpendableCoins returns the coins of acc which are not locked at blockTime.
+func SpendableCoins(acc Account, blockTime time.Time) Coins {
+	coins := acc.GetCoins()
+	locked := LockedCoins(acc, blockTime)
+	if locked.Empty() {
+		return coins
+	}
+	// The account can hold less than its locked coins, once it has spent
+	// coins which were received in addition to its vesting coins.
+	spendable := Coins{}
+	for _, coin := range coins {
+		if amt := coin.Amount - locked.AmountOf(coin.Denom); amt > 0 {
+			spendable = append(spendable, NewCoin(coin.Denom, amt))
+		}
+	}
+	return spendable
+}
+
+//----------------------------------------
+// ContinuousVesting
+
+var _ VestingSchedule = ContinuousVesting{}
+
+// ContinuousVesting unlocks the original vesting coins linearly, from
+// StartTime to EndTime.
+type ContinuousVesting struct {
+	OriginalVesting Coins `json:"original_vesting" yaml:"original_vesting"`
+	StartTime       int64 `json:"start_time" yaml:"start_time"` // unix time in seconds.
+	EndTime         int64 `json:"end_time" yaml:"end_time"`     // unix time in seconds.
+}
+
+// NewContinuousVesting returns a new ContinuousVesting of originalVesting,
+// from startTime to endTime.
+func NewContinuousVesting(originalVesting Coins, startTime, endTime int64) ContinuousVesting {
+	return ContinuousVesting{
+		OriginalVesting: originalVesting,
+		StartTime:       startTime,
+		EndTime:         endTime,
+	}
+}
+
+// GetOriginalVesting - Implements VestingSchedule.
+func (cv ContinuousVesting) GetOriginalVesting() Coins {
+	return cv.OriginalVesting
+}
+
+// GetStartTime - Implements VestingSchedule.
+func (cv ContinuousVesting) GetStartTime() int64 {
+	return cv.StartTime
+}
+
+// GetEndTime - Implements VestingSchedule.
+func (cv ContinuousVesting) GetEndTime() int64 {
+	return cv.EndTime
+}
+
+// VestedCoins - Implements VestingSchedule.
+func (cv ContinuousVesting) VestedCoins(blockTime time.Time) Coins {
+	now := blockTime.Unix()
+	switch {
+	case now <= cv.StartTime:
+		return nil
+	case now >= cv.EndTime:
+		return cv.OriginalVesting
+	}
+
+	// vested = original * elapsed / duration, computed with big ints so that
+	// large amounts don't overflow.
+	elapsed := big.NewInt(now - cv.StartTime)
+	duration := big.NewInt(cv.EndTime - cv.StartTime)
+	vested := Coins{}
+	for _, coin := range cv.OriginalVesting {
+		amt := new(big.Int).Mul(big.NewInt(coin.Amount), elapsed)
+		amt.Quo(amt, duration)
+		if amt.Sign() > 0 {
+			vested = append(vested, NewCoin(coin.Denom, amt.Int64()))
+		}
+	}
+	return vested
+}
+
+// ValidateBasic - Implements VestingSchedule.
+func (cv ContinuousVesting) ValidateBasic() error {
+	if !cv.OriginalVesting.IsValid() || cv.OriginalVesting.Empty() {
+		return fmt.Errorf("invalid original vesting %q", cv.OriginalVesting)
+	}
+	if cv.StartTime < 0 || cv.EndTime <= cv.StartTime {
+		return fmt.Errorf("invalid vesting times: start %d, end %d", cv.StartTime, cv.EndTime)
+	}
+	return nil
+}
+
+// String implements fmt.Stringer
+func (cv ContinuousVesting) String() string {
+	return fmt.Sprintf("ContinuousVesting{%s from %d to %d}",
+		cv.OriginalVesting, cv.StartTime, cv.EndTime)
+}
+
+//----------------------------------------
+// PeriodicVesting
+
+var _ VestingSchedule = PeriodicVesting{}
+
+// PeriodicVesting unlocks the amount of each of its periods at the end of the
+// period, the periods following each other from StartTime.
+type PeriodicVesting struct {
+	StartTime int64           `json:"start_time" yaml:"start_time"` // unix time in seconds.
+	Periods   []VestingPeriod `json:"periods" yaml:"periods"`
+}
+
+// VestingPeriod is a period of a PeriodicVesting.
+type VestingPeriod struct {
+	Length int64 `json:"length" yaml:"length"` // in seconds.
+	Amount Coins `json:"amount" yaml:"amount"`
+}
+
+// NewPeriodicVesting returns a new PeriodicVesting of periods, from
+// startTime.
+func NewPeriodicVesting(startTime int64, periods []VestingPeriod) PeriodicVesting {
+	return PeriodicVesting{
+		StartTime: startTime,
+		Periods:   periods,
+	}
+}
+
+// GetOriginalVesting - Implements VestingSchedule.
+func (pv PeriodicVesting) GetOriginalVesting() Coins {
+	var total Coins
+	for _, period := range pv.Periods {
+		total = total.Add(period.Amount)
+	}
+	return total
+}
+
+// GetStartTime - Implements VestingSchedule.
+func (pv PeriodicVesting) GetStartTime() int64 {
+	return pv.StartTime
+}
+
+// GetEndTime - Implements VestingSchedule.
+func (pv PeriodicVesting) GetEndTime() int64 {
+	end := pv.StartTime
+	for _, period := range pv.Periods {
+		end += period.Length
+	}
+	return end
+}
+
+// VestedCoins - Implements VestingSchedule.
+func (pv PeriodicVesting) VestedCoins(blockTime time.Time) Coins {
+	now := blockTime.Unix()
+	var vested Coins
+	end := pv.StartTime
+	for _, period := range pv.Periods {
+		end += period.Length
+		if now < end {
+			break
+		}
+		vested = vested.Add(period.Amount)
+	}
+	return vested
+}
+
+// ValidateBasic - Implements VestingSchedule.
+func (pv PeriodicVesting) ValidateBasic() error {
+	if pv.StartTime < 0 {
+		return fmt.Errorf("invalid vesting start time %d", pv.StartTime)
+	}
+	if len(pv.Periods) == 0 {
+		return errors.New("no vesting periods")
+	}
+	for i, period := range pv.Periods {
+		if period.Length <= 0 {
+			return fmt.Errorf("invalid length %d of vesting period %d", period.Length, i)
+		}
+		if !period.Amount.IsValid() || period.Amount.Empty() {
+			return fmt.Errorf("invalid amount %q of vesting period %d", period.Amount, i)
+		}
+	}
+	return nil
+}
+
+// String implements fmt.Stringer
+func (pv PeriodicVesting) String() string {
+	periods := make([]string, len(pv.Periods))
+	for i, period := range pv.Periods {
+		periods[i] = fmt.Sprintf("%s after %ds", period.Amount, period.Length)
+	}
+	return fmt.Sprintf("PeriodicVesting{from %d: %s}",
+		pv.StartTime, strings.Join(periods, ", "))
+}
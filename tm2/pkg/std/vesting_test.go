@@ -0,0 +1,75 @@
+package std
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContinuousVesting(t *testing.T) {
+	t.Parallel()
+
+	cv := NewContinuousVesting(NewCoins(NewCoin("atom", 1_000_000_000_000_000), NewCoin("foo", 3)), 1000, 4000)
+	require.NoError(t, cv.ValidateBasic())
+
+	assert.True(t, cv.VestedCoins(time.Unix(1000, 0)).IsZero())
+	assert.Equal(t, NewCoins(NewCoin("atom", 333_333_333_333_333), NewCoin("foo", 1)), cv.VestedCoins(time.Unix(2000, 0)))
+	assert.Equal(t, NewCoins(NewCoin("atom", 666_666_666_666_666), NewCoin("foo", 2)), cv.VestedCoins(time.Unix(3000, 0)))
+	assert.Equal(t, cv.OriginalVesting, cv.VestedCoins(time.Unix(5000, 0)))
+
+	assert.Error(t, NewContinuousVesting(cv.OriginalVesting, 1000, 1000).ValidateBasic())
+	assert.Error(t, NewContinuousVesting(nil, 1000, 2000).ValidateBasic())
+}
+
+func TestPeriodicVesting(t *testing.T) {
+	t.Parallel()
+
+	pv := NewPeriodicVesting(1000, []VestingPeriod{
+		{Length: 100, Amount: NewCoins(NewCoin("atom", 10))},
+		{Length: 200, Amount: NewCoins(NewCoin("atom", 20), NewCoin("foo", 5))},
+	})
+	require.NoError(t, pv.ValidateBasic())
+	assert.Equal(t, NewCoins(NewCoin("atom", 30), NewCoin("foo", 5)), pv.GetOriginalVesting())
+	assert.Equal(t, int64(1300), pv.GetEndTime())
+
+	assert.True(t, pv.VestedCoins(time.Unix(1099, 0)).IsZero())
+	assert.Equal(t, NewCoins(NewCoin("atom", 10)), pv.VestedCoins(time.Unix(1100, 0)))
+	assert.Equal(t, NewCoins(NewCoin("atom", 10)), pv.VestedCoins(time.Unix(1299, 0)))
+	assert.Equal(t, pv.GetOriginalVesting(), pv.VestedCoins(time.Unix(1300, 0)))
+
+	assert.Error(t, NewPeriodicVesting(1000, nil).ValidateBasic())
+	assert.Error(t, NewPeriodicVesting(1000, []VestingPeriod{{Length: 0, Amount: NewCoins(NewCoin("atom", 1))}}).ValidateBasic())
+}
+
+func TestSpendableCoins(t *testing.T) {
+	t.Parallel()
+
+	acc := &vestingTestAccount{
+		BaseAccount: BaseAccount{Coins: NewCoins(NewCoin("atom", 60), NewCoin("foo", 10))},
+		Vesting:     NewContinuousVesting(NewCoins(NewCoin("atom", 100)), 1000, 2000),
+	}
+
+	// locked coins which have been spent are not spendable either.
+	assert.Equal(t, NewCoins(NewCoin("atom", 100)), LockedCoins(acc, time.Unix(1000, 0)))
+	assert.Equal(t, NewCoins(NewCoin("foo", 10)), SpendableCoins(acc, time.Unix(1000, 0)))
+	assert.Equal(t, NewCoins(NewCoin("atom", 10), NewCoin("foo", 10)), SpendableCoins(acc, time.Unix(1500, 0)))
+	assert.Equal(t, acc.Coins, SpendableCoins(acc, time.Unix(2000, 0)))
+
+	// not a vesting account
+	assert.Nil(t, LockedCoins(&acc.BaseAccount, time.Unix(1000, 0)))
+	assert.Equal(t, acc.Coins, SpendableCoins(&acc.BaseAccount, time.Unix(1000, 0)))
+}
+
+type vestingTestAccount struct {
+	BaseAccount
+	Vesting VestingSchedule
+}
+
+func (acc *vestingTestAccount) GetVestingSchedule() VestingSchedule { return acc.Vesting }
+
+func (acc *vestingTestAccount) SetVestingSchedule(vesting VestingSchedule) error {
+	acc.Vesting = vesting
+	return nil
+}
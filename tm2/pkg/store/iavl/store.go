@@ -152,6 +152,28 @@ func (st *Store) VersionExists(version int64) bool {
 	return st.tree.VersionExists(version)
 }
 
+// Export calls fn with the nodes of the tree at the given version,
+// to write a snapshot. See iavl.ImmutableTree.Export.
+func (st *Store) Export(version int64, fn func(*iavl.ExportNode) error) error {
+	tree, err := st.tree.GetImmutable(version)
+	if err != nil {
+		return err
+	}
+
+	return tree.Export(fn)
+}
+
+// Import returns an importer of the given version, to restore a snapshot.
+// The store must be empty.
+func (st *Store) Import(version int64) (*iavl.Importer, error) {
+	tree, ok := st.tree.(*iavl.MutableTree)
+	if !ok {
+		return nil, goerrors.New("cannot import into an immutable store")
+	}
+
+	return tree.Import(version)
+}
+
 // Implements Store.
 func (st *Store) CacheWrap() types.Store {
 	return cache.New(st)
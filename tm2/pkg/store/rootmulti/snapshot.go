@@ -0,0 +1,383 @@
+package rootmulti
+
+import (
+	"bufio"
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	iavltree "github.com/gnolang/gno/tm2/pkg/iavl"
+
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// maxSnapshotItemSize is the maximum size of a snapshot item
+const maxSnapshotItemSize = 128 * 1024 * 1024
+
+// snapshotItem is an item of a multistore snapshot.
+//
+// The items of each store start with an item with the store name,
+// followed by the IAVL nodes of the store, or by the key-value pairs
+// of the stores which are not IAVL stores.
+type snapshotItem struct {
+	Store string
+	Node  *iavltree.ExportNode
+	Pair  *types.KVPair
+}
+
+// Implements CommitMultiStore.
+func (ms *multiStore) Snapshot() (types.StoreSnapshot, error) {
+	height := ms.lastCommitID.Version
+	if height == 0 {
+		return nil, errors.New("no committed version to snapshot")
+	}
+
+	// Take the db snapshots, once for each db. They are taken together,
+	// without any write in between, so they are consistent.
+	snapshots := make(map[dbm.DB]dbm.DB)
+	snapshotDB := func(db dbm.DB) (dbm.DB, error) {
+		if snap, ok := snapshots[db]; ok {
+			return snap, nil
+		}
+
+		snapshotter, ok := db.(dbm.Snapshotter)
+		if !ok {
+			return nil, fmt.Errorf("db %T doesn't support snapshots", db)
+		}
+
+		snap := snapshotter.Snapshot()
+		snapshots[db] = snap
+
+		return snap, nil
+	}
+
+	snapshot := &storeSnapshot{
+		ms: &multiStore{
+			storeOpts:    ms.storeOpts,
+			storesParams: make(map[types.StoreKey]storeParams, len(ms.storesParams)),
+			stores:       make(map[types.StoreKey]types.CommitStore),
+			keysByName:   ms.keysByName,
+		},
+		height: height,
+	}
+	snapshot.ms.storeOpts.Immutable = true
+
+	err := func() error {
+		db, err := snapshotDB(ms.db)
+		if err != nil {
+			return err
+		}
+		snapshot.ms.db = db
+
+		for key, params := range ms.storesParams {
+			if params.db != nil {
+				if params.db, err = snapshotDB(params.db); err != nil {
+					return err
+				}
+			}
+			snapshot.ms.storesParams[key] = params
+		}
+
+		return nil
+	}()
+
+	for _, snap := range snapshots {
+		snapshot.dbs = append(snapshot.dbs, snap)
+	}
+	if err != nil {
+		snapshot.Close()
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// storeSnapshot is the state of a multistore at a height, read from
+// snapshots of its dbs
+type storeSnapshot struct {
+	ms     *multiStore // the multistore over the db snapshots
+	height int64
+	dbs    []dbm.DB // the db snapshots
+}
+
+var _ types.StoreSnapshot = (*storeSnapshot)(nil)
+
+// Implements StoreSnapshot.
+func (s *storeSnapshot) Height() int64 {
+	return s.height
+}
+
+// Implements StoreSnapshot.
+func (s *storeSnapshot) Write(w io.Writer) error {
+	if err := s.ms.LoadVersion(s.height); err != nil {
+		return fmt.Errorf("unable to load the snapshot height %d: %w", s.height, err)
+	}
+
+	return s.ms.export(w)
+}
+
+// Implements StoreSnapshot.
+func (s *storeSnapshot) Close() error {
+	for _, db := range s.dbs {
+		db.Close()
+	}
+	s.dbs = nil
+
+	return nil
+}
+
+// export writes the state of the stores at the loaded height to w
+func (ms *multiStore) export(w io.Writer) error {
+	height := ms.lastCommitID.Version
+
+	bw := bufio.NewWriter(w)
+	writeItem := func(item snapshotItem) error {
+		_, err := amino.MarshalSizedWriter(bw, item)
+		return err
+	}
+
+	for _, key := range ms.sortedKeys() {
+		if err := writeItem(snapshotItem{Store: key.Name()}); err != nil {
+			return err
+		}
+
+		switch store := ms.stores[key].(type) {
+		case *iavl.Store:
+			err := store.Export(height, func(node *iavltree.ExportNode) error {
+				return writeItem(snapshotItem{Node: node})
+			})
+			if err != nil {
+				return fmt.Errorf("unable to export store %s: %w", key.Name(), err)
+			}
+		default:
+			if err := ms.exportPairs(key, store, writeItem); err != nil {
+				return fmt.Errorf("unable to export store %s: %w", key.Name(), err)
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// exportPairs writes the key-value pairs of a store which isn't an IAVL store
+func (ms *multiStore) exportPairs(key types.StoreKey, store types.Store, writeItem func(snapshotItem) error) error {
+	// The entries of the IAVL stores sharing the same db are skipped,
+	// as they are exported with their own store
+	shared := ms.sharesIAVLDB(key)
+
+	itr := store.Iterator(nil, nil)
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		if shared && iavltree.IsNodeDBEntry(itr.Key(), itr.Value()) {
+			continue
+		}
+
+		if err := writeItem(snapshotItem{Pair: &types.KVPair{Key: itr.Key(), Value: itr.Value()}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sharesIAVLDB returns true if the given store is mounted on the same db
+// as an IAVL store
+func (ms *multiStore) sharesIAVLDB(key types.StoreKey) bool {
+	db := ms.storesParams[key].db
+	if db == nil {
+		return false
+	}
+
+	for otherKey, params := range ms.storesParams {
+		if otherKey == key || params.db != db {
+			continue
+		}
+
+		if _, ok := ms.stores[otherKey].(*iavl.Store); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Implements CommitMultiStore.
+func (ms *multiStore) Restore(height int64, hash []byte, r io.Reader, verify func(types.MultiStore) error) error {
+	if height <= 0 {
+		return fmt.Errorf("invalid snapshot height %d", height)
+	}
+
+	if latest := ms.lastCommitID.Version; latest != 0 {
+		return fmt.Errorf("found existing version %d, the stores must be empty", latest)
+	}
+
+	if err := ms.restore(height, hash, r, verify); err != nil {
+		// Don't leave any restored entry behind
+		if clearErr := ms.clear(); clearErr != nil {
+			return goerrors.Join(err, clearErr)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// restore restores the empty stores, see Restore
+func (ms *multiStore) restore(height int64, hash []byte, r io.Reader, verify func(types.MultiStore) error) error {
+	var (
+		br       = bufio.NewReader(r)
+		store    types.CommitStore
+		shared   bool // whether store shares its db with an IAVL store
+		importer *iavltree.Importer
+	)
+
+	// commitImporter finishes the import of the current IAVL store, if any
+	commitImporter := func() error {
+		if importer == nil {
+			return nil
+		}
+
+		err := importer.Commit()
+		importer = nil
+
+		return err
+	}
+
+	for {
+		var item snapshotItem
+
+		_, err := amino.UnmarshalSizedReader(br, &item, maxSnapshotItemSize)
+		if goerrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "unable to read snapshot item")
+		}
+
+		switch {
+		case item.Store != "":
+			if err := commitImporter(); err != nil {
+				return err
+			}
+
+			key, ok := ms.keysByName[item.Store]
+			if !ok {
+				return fmt.Errorf("unknown store %s", item.Store)
+			}
+			store = ms.stores[key]
+			shared = ms.sharesIAVLDB(key)
+
+			if iavlStore, ok := store.(*iavl.Store); ok {
+				importer, err = iavlStore.Import(height)
+				if err != nil {
+					return fmt.Errorf("unable to import store %s: %w", item.Store, err)
+				}
+			}
+		case item.Node != nil:
+			if importer == nil {
+				return errors.New("unexpected IAVL node, outside of an IAVL store")
+			}
+
+			if err := importer.Add(item.Node); err != nil {
+				return err
+			}
+		case item.Pair != nil:
+			if store == nil || importer != nil {
+				return errors.New("unexpected key-value pair, outside of a non-IAVL store")
+			}
+
+			// The entries of the IAVL stores sharing the db are not
+			// exported with the pairs, and must not be overwritten
+			if shared && iavltree.IsNodeDBEntry(item.Pair.Key, item.Pair.Value) {
+				return errors.New("unexpected IAVL node entry, in a store sharing an IAVL db")
+			}
+
+			store.Set(item.Pair.Key, item.Pair.Value)
+		default:
+			return errors.New("invalid empty snapshot item")
+		}
+	}
+
+	if err := commitImporter(); err != nil {
+		return err
+	}
+
+	// The restored state must be the committed one. Only the IAVL stores
+	// are part of its hash, the others are checked by verify.
+	storeInfos := make([]storeInfo, 0, len(ms.stores))
+	for key, store := range ms.stores {
+		si := storeInfo{}
+		si.Name = key.Name()
+		si.Core.CommitID = store.LastCommitID()
+		storeInfos = append(storeInfos, si)
+	}
+
+	cInfo := commitInfo{
+		Version:    height,
+		StoreInfos: storeInfos,
+	}
+	if restored := cInfo.Hash(); !bytes.Equal(restored, hash) {
+		return fmt.Errorf("restored hash %X does not match the expected hash %X", restored, hash)
+	}
+
+	if verify != nil {
+		if err := verify(ms.MultiCacheWrap()); err != nil {
+			return fmt.Errorf("unable to verify the restored state: %w", err)
+		}
+	}
+
+	// Save the commit info of the restored version, as Commit does
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+	setCommitInfo(batch, height, cInfo)
+	setLatestVersion(batch, height)
+	batch.WriteSync()
+
+	return ms.LoadVersion(height)
+}
+
+// clear deletes the entries of the stores, and loads them empty again
+func (ms *multiStore) clear() error {
+	for _, params := range ms.storesParams {
+		db := ms.storeDB(params)
+
+		// The db can't be written while it is iterated
+		var keys [][]byte
+		itr := db.Iterator(nil, nil)
+		for ; itr.Valid(); itr.Next() {
+			keys = append(keys, itr.Key())
+		}
+		itr.Close()
+
+		batch := db.NewBatch()
+		for _, key := range keys {
+			batch.Delete(key)
+		}
+		batch.WriteSync()
+		batch.Close()
+	}
+
+	return ms.LoadVersion(0)
+}
+
+// sortedKeys returns the keys of the mounted stores, sorted by name
+func (ms *multiStore) sortedKeys() []types.StoreKey {
+	keys := make([]types.StoreKey, 0, len(ms.stores))
+	for key := range ms.stores {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name() < keys[j].Name()
+	})
+
+	return keys
+}
@@ -0,0 +1,217 @@
+package rootmulti
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	iavltree "github.com/gnolang/gno/tm2/pkg/iavl"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// newSharedMultiStore returns a multistore with an IAVL store and a
+// db adapter store mounted on the same db, and a separate IAVL store.
+// The previous versions are pruned on commit.
+func newSharedMultiStore(t *testing.T, db dbm.DB) *multiStore {
+	t.Helper()
+
+	ms := NewMultiStore(db)
+	ms.storeOpts = types.StoreOptions{PruningOptions: types.PruneEverything}
+	ms.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(types.NewStoreKey("base"), dbadapter.StoreConstructor, db)
+	ms.MountStoreWithDB(types.NewStoreKey("other"), iavl.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	return ms
+}
+
+// storeEntries returns the entries of the given store, without the
+// entries of the IAVL store sharing its db
+func storeEntries(ms *multiStore, name string) map[string]string {
+	entries := make(map[string]string)
+
+	itr := ms.getStoreByName(name).Iterator(nil, nil)
+	defer itr.Close()
+
+	for ; itr.Valid(); itr.Next() {
+		if iavltree.IsNodeDBEntry(itr.Key(), itr.Value()) {
+			continue
+		}
+
+		entries[string(itr.Key())] = string(itr.Value())
+	}
+
+	return entries
+}
+
+func TestMultiStoreSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	ms := newSharedMultiStore(t, memdb.NewMemDB())
+
+	var lastCommitID types.CommitID
+	for version := range 3 {
+		for i := range 30 {
+			key, value := fmt.Appendf(nil, "key-%d", i), fmt.Appendf(nil, "value-%d-%d", version, i)
+
+			ms.getStoreByName("main").Set(key, value)
+			ms.getStoreByName("base").Set(key, value)
+
+			if i%3 == 0 {
+				ms.getStoreByName("other").Set(key, value)
+			}
+		}
+		ms.getStoreByName("main").Delete([]byte("key-7"))
+
+		lastCommitID = ms.Commit()
+	}
+
+	snapshot, err := ms.Snapshot()
+	require.NoError(t, err)
+	defer snapshot.Close()
+
+	assert.Equal(t, lastCommitID.Version, snapshot.Height())
+
+	names := []string{"main", "base", "other"}
+	entries := make(map[string]map[string]string, len(names))
+	for _, name := range names {
+		entries[name] = storeEntries(ms, name)
+	}
+
+	// The snapshot isn't affected by the later commits, which prune
+	// its version
+	for _, name := range names {
+		ms.getStoreByName(name).Set([]byte("key-1"), []byte("later"))
+	}
+	ms.getStoreByName("main").Delete([]byte("key-2"))
+	ms.Commit()
+	ms.Commit()
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Write(&buf))
+
+	var (
+		restored = newSharedMultiStore(t, memdb.NewMemDB())
+		verified bool
+	)
+
+	verify := func(vms types.MultiStore) error {
+		verified = true
+
+		// The restored entries are verified before the version is saved
+		assert.Zero(t, restored.LastCommitID().Version)
+		assert.Equal(t, []byte("value-2-0"), vms.GetStore(restored.keysByName["base"]).Get([]byte("key-0")))
+
+		return nil
+	}
+
+	require.NoError(t, restored.Restore(lastCommitID.Version, lastCommitID.Hash, bytes.NewReader(buf.Bytes()), verify))
+
+	assert.True(t, verified)
+	assert.Equal(t, lastCommitID, restored.LastCommitID())
+
+	for _, name := range names {
+		assert.Equal(t, entries[name], storeEntries(restored, name), "store %s", name)
+	}
+
+	// The restored store keeps committing from the restored version
+	restored.getStoreByName("main").Set([]byte("new"), []byte("value"))
+	assert.Equal(t, lastCommitID.Version+1, restored.Commit().Version)
+
+	// Only empty stores can be restored
+	assert.Error(t, restored.Restore(lastCommitID.Version, lastCommitID.Hash, bytes.NewReader(buf.Bytes()), nil))
+}
+
+func TestMultiStoreSnapshot_NoVersion(t *testing.T) {
+	t.Parallel()
+
+	ms := newSharedMultiStore(t, memdb.NewMemDB())
+
+	_, err := ms.Snapshot()
+	assert.Error(t, err)
+}
+
+func TestMultiStoreRestore_InvalidSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ms := newSharedMultiStore(t, memdb.NewMemDB())
+	ms.getStoreByName("main").Set([]byte("key"), []byte("value"))
+	commitID := ms.Commit()
+
+	snapshot, err := ms.Snapshot()
+	require.NoError(t, err)
+	defer snapshot.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Write(&buf))
+
+	// Truncated snapshot
+	restored := newSharedMultiStore(t, memdb.NewMemDB())
+	assert.Error(t, restored.Restore(commitID.Version, commitID.Hash, bytes.NewReader(buf.Bytes()[:buf.Len()-1]), nil))
+
+	// Unknown store
+	other := NewMultiStore(memdb.NewMemDB())
+	other.MountStoreWithDB(types.NewStoreKey("main"), iavl.StoreConstructor, nil)
+	require.NoError(t, other.LoadLatestVersion())
+	assert.Error(t, other.Restore(commitID.Version, commitID.Hash, bytes.NewReader(buf.Bytes()), nil))
+}
+
+func TestMultiStoreRestore_Rejected(t *testing.T) {
+	t.Parallel()
+
+	ms := newSharedMultiStore(t, memdb.NewMemDB())
+	ms.getStoreByName("main").Set([]byte("key"), []byte("value"))
+	ms.getStoreByName("base").Set([]byte("key"), []byte("value"))
+	commitID := ms.Commit()
+
+	snapshot, err := ms.Snapshot()
+	require.NoError(t, err)
+	defer snapshot.Close()
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Write(&buf))
+
+	var (
+		db       = memdb.NewMemDB()
+		restored = newSharedMultiStore(t, db)
+	)
+
+	// requireEmpty checks that nothing is left of a rejected restore
+	requireEmpty := func(t *testing.T) {
+		t.Helper()
+
+		assert.True(t, restored.LastCommitID().IsZero())
+
+		itr := db.Iterator(nil, nil)
+		defer itr.Close()
+		if itr.Valid() {
+			t.Fatalf("found entry %q", itr.Key())
+		}
+	}
+
+	// The restored state must match the hash
+	err = restored.Restore(commitID.Version, []byte("hash"), bytes.NewReader(buf.Bytes()), nil)
+	assert.ErrorContains(t, err, "does not match the expected hash")
+	requireEmpty(t)
+
+	// The restored state must pass the verification
+	err = restored.Restore(commitID.Version, commitID.Hash, bytes.NewReader(buf.Bytes()), func(types.MultiStore) error {
+		return errors.New("forged")
+	})
+	assert.ErrorContains(t, err, "forged")
+	requireEmpty(t)
+
+	// The store can still be restored
+	require.NoError(t, restored.Restore(commitID.Version, commitID.Hash, bytes.NewReader(buf.Bytes()), nil))
+	assert.Equal(t, commitID, restored.LastCommitID())
+	assert.Equal(t, []byte("value"), restored.getStoreByName("base").Get([]byte("key")))
+}
@@ -334,12 +334,7 @@ func parsePath(path string) (storeName string, subpath string, err serrors.Error
 // ----------------------------------------
 
 func (ms *multiStore) constructStore(params storeParams) (store types.CommitStore, err error) {
-	var db dbm.DB
-	if params.db != nil {
-		db = dbm.NewPrefixDB(params.db, []byte("s/_/"))
-	} else {
-		db = dbm.NewPrefixDB(ms.db, []byte("s/k:"+params.key.Name()+"/"))
-	}
+	db := ms.storeDB(params)
 	opts := ms.storeOpts
 
 	// XXX: use these:
@@ -349,6 +344,14 @@ func (ms *multiStore) constructStore(params storeParams) (store types.CommitStor
 	return store, nil
 }
 
+// storeDB returns the db of the entries of a store
+func (ms *multiStore) storeDB(params storeParams) dbm.DB {
+	if params.db != nil {
+		return dbm.NewPrefixDB(params.db, []byte("s/_/"))
+	}
+	return dbm.NewPrefixDB(ms.db, []byte("s/k:"+params.key.Name()+"/"))
+}
+
 func (ms *multiStore) nameToKey(name string) types.StoreKey {
 	for key := range ms.storesParams {
 		if key.Name() == name {
@@ -0,0 +1,382 @@
+// Package snapshots manages the state sync snapshots of a CommitMultiStore.
+//
+// A snapshot of a height is the multistore snapshot stream of the height,
+// split into chunks which are kept on disk, in <dir>/<height>/<format>/.
+package snapshots
+
+import (
+	"bytes"
+	"crypto/sha256"
+	goerrors "errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+const (
+	// Format is the format of the snapshots created by the manager
+	Format uint32 = 1
+
+	// ChunkSize is the maximum size of a snapshot chunk
+	ChunkSize = 4 * 1024 * 1024
+
+	// maxChunks is the maximum number of chunks of an offered snapshot
+	maxChunks = 100_000
+
+	snapshotFile = "snapshot"
+	restoreDir   = "restore"
+)
+
+var (
+	ErrUnknownFormat      = goerrors.New("unknown snapshot format")
+	ErrInvalidMetadata    = goerrors.New("invalid snapshot metadata")
+	ErrNoRestore          = goerrors.New("no snapshot is being restored")
+	ErrUnexpectedChunk    = goerrors.New("unexpected snapshot chunk")
+	ErrSnapshotNotFound   = goerrors.New("snapshot not found")
+	ErrSnapshotInProgress = goerrors.New("a snapshot is already being created")
+)
+
+// Options are the snapshot options of the manager
+type Options struct {
+	Interval   int64 // the block interval of the snapshots, 0 to disable them
+	KeepRecent int   // the number of recent snapshots to keep
+}
+
+// Metadata is the metadata of a snapshot created by the manager
+type Metadata struct {
+	ChunkHashes [][]byte // the SHA-256 hashes of the chunks
+}
+
+// Manager creates the snapshots of a multistore, and restores
+// the multistore from snapshots.
+type Manager struct {
+	dir    string
+	ms     types.CommitMultiStore
+	opts   Options
+	logger *slog.Logger
+
+	mux     sync.Mutex
+	restore *restore // the snapshot being restored, if any
+
+	creating atomic.Bool    // set while a snapshot is being written
+	wg       sync.WaitGroup // waits for the snapshot being written
+}
+
+// NewManager creates a new snapshot manager, keeping the snapshots in dir
+func NewManager(dir string, ms types.CommitMultiStore, opts Options, logger *slog.Logger) *Manager {
+	return &Manager{
+		dir:    dir,
+		ms:     ms,
+		opts:   opts,
+		logger: logger,
+	}
+}
+
+// ShouldCreate returns true if a snapshot should be taken at the given height
+func (m *Manager) ShouldCreate(height int64) bool {
+	return m.opts.Interval > 0 && height > 0 && height%m.opts.Interval == 0
+}
+
+// Create takes the snapshot of the latest height of the multistore, which
+// must be the given height, and writes it in the background, so the later
+// commits aren't blocked. The old snapshots are pruned once it is written.
+// It returns ErrSnapshotInProgress if a snapshot is still being written.
+func (m *Manager) Create(height int64) error {
+	if !m.creating.CompareAndSwap(false, true) {
+		return ErrSnapshotInProgress
+	}
+
+	snapshot, err := m.ms.Snapshot()
+	if err != nil {
+		m.creating.Store(false)
+
+		return fmt.Errorf("unable to take snapshot: %w", err)
+	}
+
+	if snapshot.Height() != height {
+		snapshot.Close()
+		m.creating.Store(false)
+
+		return fmt.Errorf("invalid snapshot height %d, the latest height is %d", height, snapshot.Height())
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.creating.Store(false)
+		defer snapshot.Close()
+
+		if _, err := m.write(snapshot); err != nil {
+			m.logger.Error("unable to create snapshot", "height", height, "err", err)
+
+			return
+		}
+
+		m.logger.Info("created snapshot", "height", height)
+	}()
+
+	return nil
+}
+
+// Wait waits for the snapshot being written, if any
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// write writes the given multistore snapshot to disk, and prunes
+// the old snapshots
+func (m *Manager) write(storeSnapshot types.StoreSnapshot) (*abci.Snapshot, error) {
+	var (
+		height = storeSnapshot.Height()
+		dir    = m.snapshotDir(height, Format)
+		tmpDir = dir + ".tmp"
+	)
+
+	// Clean up any leftover of a previous attempt
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create snapshot directory: %w", err)
+	}
+
+	cw := &chunkWriter{
+		dir:    tmpDir,
+		stream: sha256.New(),
+	}
+
+	if err := storeSnapshot.Write(cw); err != nil {
+		_ = os.RemoveAll(tmpDir)
+
+		return nil, fmt.Errorf("unable to write snapshot: %w", err)
+	}
+
+	if err := cw.Close(); err != nil {
+		_ = os.RemoveAll(tmpDir)
+
+		return nil, err
+	}
+
+	metadata, err := amino.Marshal(Metadata{ChunkHashes: cw.hashes})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &abci.Snapshot{
+		Height:   height,
+		Format:   Format,
+		Chunks:   uint32(len(cw.hashes)),
+		Hash:     cw.stream.Sum(nil),
+		Metadata: metadata,
+	}
+
+	if err := osm.WriteFileAtomic(filepath.Join(tmpDir, snapshotFile), amino.MustMarshal(snapshot), 0o644); err != nil {
+		return nil, err
+	}
+
+	// The snapshot is only visible to the readers once it is complete
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return nil, fmt.Errorf("unable to save snapshot: %w", err)
+	}
+
+	if err := m.prune(); err != nil {
+		m.logger.Error("unable to prune snapshots", "err", err)
+	}
+
+	return snapshot, nil
+}
+
+// List returns the available snapshots, from the most recent one
+func (m *Manager) List() ([]*abci.Snapshot, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.list()
+}
+
+func (m *Manager) list() ([]*abci.Snapshot, error) {
+	heights, err := m.heights()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*abci.Snapshot, 0, len(heights))
+	for _, height := range heights {
+		snapshot, err := m.load(height, Format)
+		if goerrors.Is(err, ErrSnapshotNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// LoadChunk returns the given chunk of a snapshot
+func (m *Manager) LoadChunk(height int64, format, chunk uint32) ([]byte, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	snapshot, err := m.load(height, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if chunk >= snapshot.Chunks {
+		return nil, fmt.Errorf("invalid chunk %d, the snapshot has %d chunks", chunk, snapshot.Chunks)
+	}
+
+	return os.ReadFile(chunkPath(m.snapshotDir(height, format), chunk))
+}
+
+// heights returns the snapshot heights on disk, from the most recent one
+func (m *Manager) heights() ([]int64, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	heights := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		height, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil || !entry.IsDir() {
+			continue // not a snapshot
+		}
+
+		heights = append(heights, height)
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	return heights, nil
+}
+
+// load loads the given snapshot from disk
+func (m *Manager) load(height int64, format uint32) (*abci.Snapshot, error) {
+	bz, err := os.ReadFile(filepath.Join(m.snapshotDir(height, format), snapshotFile))
+	if os.IsNotExist(err) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot abci.Snapshot
+	if err := amino.Unmarshal(bz, &snapshot); err != nil {
+		return nil, fmt.Errorf("unable to load snapshot %d: %w", height, err)
+	}
+
+	return &snapshot, nil
+}
+
+// prune removes the snapshots beyond the most recent ones to keep
+func (m *Manager) prune() error {
+	heights, err := m.heights()
+	if err != nil {
+		return err
+	}
+
+	keep := max(m.opts.KeepRecent, 1)
+	if len(heights) <= keep {
+		return nil
+	}
+
+	for _, height := range heights[keep:] {
+		if err := os.RemoveAll(filepath.Join(m.dir, strconv.FormatInt(height, 10))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) snapshotDir(height int64, format uint32) string {
+	return filepath.Join(m.dir, strconv.FormatInt(height, 10), strconv.FormatUint(uint64(format), 10))
+}
+
+func chunkPath(dir string, chunk uint32) string {
+	return filepath.Join(dir, strconv.FormatUint(uint64(chunk), 10))
+}
+
+// chunkWriter splits the written stream into chunk files
+type chunkWriter struct {
+	dir    string
+	buf    bytes.Buffer
+	hashes [][]byte
+	stream hash.Hash // the hash of the whole stream
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.stream.Write(p)
+
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), ChunkSize-w.buf.Len())
+		w.buf.Write(p[:n])
+		p = p[n:]
+		written += n
+
+		if w.buf.Len() == ChunkSize {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Close writes the last chunk
+func (w *chunkWriter) Close() error {
+	if w.buf.Len() == 0 && len(w.hashes) > 0 {
+		return nil
+	}
+
+	return w.flush()
+}
+
+func (w *chunkWriter) flush() error {
+	chunk := w.buf.Bytes()
+
+	if err := os.WriteFile(chunkPath(w.dir, uint32(len(w.hashes))), chunk, 0o644); err != nil {
+		return fmt.Errorf("unable to write snapshot chunk: %w", err)
+	}
+
+	hash := sha256.Sum256(chunk)
+	w.hashes = append(w.hashes, hash[:])
+	w.buf.Reset()
+
+	return nil
+}
+
+var _ io.WriteCloser = (*chunkWriter)(nil)
@@ -0,0 +1,180 @@
+package snapshots
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/random"
+
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var testKey = types.NewStoreKey("main")
+
+// newMultiStore returns a new multistore with a single IAVL store
+func newMultiStore(t *testing.T) types.CommitMultiStore {
+	t.Helper()
+
+	ms := rootmulti.NewMultiStore(memdb.NewMemDB())
+	ms.MountStoreWithDB(testKey, iavl.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	return ms
+}
+
+// commitRandom commits random values, big enough to span several chunks
+func commitRandom(t *testing.T, ms types.CommitMultiStore, count int) types.CommitID {
+	t.Helper()
+
+	for i := range count {
+		ms.GetStore(testKey).Set(fmt.Appendf(nil, "key-%d", i), random.RandBytes(64*1024))
+	}
+
+	return ms.Commit()
+}
+
+// createSnapshot creates the snapshot of the given height, and waits for it
+func createSnapshot(t *testing.T, manager *Manager, height int64) *abci.Snapshot {
+	t.Helper()
+
+	require.NoError(t, manager.Create(height))
+	manager.Wait()
+
+	snapshots, err := manager.List()
+	require.NoError(t, err)
+	require.NotEmpty(t, snapshots)
+	require.Equal(t, height, snapshots[0].Height)
+
+	return snapshots[0]
+}
+
+func TestManager_CreateList(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ms      = newMultiStore(t)
+		manager = NewManager(t.TempDir(), ms, Options{Interval: 10, KeepRecent: 2}, log.NewNoopLogger())
+	)
+
+	assert.False(t, manager.ShouldCreate(0))
+	assert.False(t, manager.ShouldCreate(5))
+	assert.True(t, manager.ShouldCreate(20))
+
+	for range 3 {
+		commitID := commitRandom(t, ms, 10)
+
+		snapshot := createSnapshot(t, manager, commitID.Version)
+		assert.Equal(t, Format, snapshot.Format)
+	}
+
+	// Only the latest height can be snapshotted
+	assert.Error(t, manager.Create(2))
+
+	// Only the most recent snapshots are kept
+	snapshots, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(3), snapshots[0].Height)
+	assert.Equal(t, int64(2), snapshots[1].Height)
+
+	// The chunks can be loaded
+	for i := range snapshots[0].Chunks {
+		chunk, err := manager.LoadChunk(3, Format, i)
+		require.NoError(t, err)
+		assert.NotEmpty(t, chunk)
+	}
+
+	_, err = manager.LoadChunk(3, Format, snapshots[0].Chunks)
+	assert.Error(t, err)
+
+	_, err = manager.LoadChunk(1, Format, 0)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestManager_Restore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ms      = newMultiStore(t)
+		manager = NewManager(t.TempDir(), ms, Options{Interval: 1, KeepRecent: 1}, log.NewNoopLogger())
+	)
+
+	// Spread the state over several chunks
+	commitID := commitRandom(t, ms, 2*ChunkSize/(64*1024))
+
+	snapshot := createSnapshot(t, manager, commitID.Version)
+	require.Greater(t, snapshot.Chunks, uint32(1))
+
+	var (
+		restored        = newMultiStore(t)
+		restoredManager = NewManager(t.TempDir(), restored, Options{}, log.NewNoopLogger())
+	)
+
+	// Chunks can't be applied without an offered snapshot
+	_, err := restoredManager.ApplyChunk(0, []byte("chunk"))
+	assert.ErrorIs(t, err, ErrNoRestore)
+
+	require.NoError(t, restoredManager.Offer(snapshot, commitID.Hash, nil))
+
+	// Chunks are applied in order
+	_, err = restoredManager.ApplyChunk(1, []byte("chunk"))
+	assert.ErrorIs(t, err, ErrUnexpectedChunk)
+
+	// Chunks must match their hashes
+	_, err = restoredManager.ApplyChunk(0, []byte("chunk"))
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	for i := range snapshot.Chunks {
+		chunk, err := manager.LoadChunk(snapshot.Height, snapshot.Format, i)
+		require.NoError(t, err)
+
+		done, err := restoredManager.ApplyChunk(i, chunk)
+		require.NoError(t, err)
+		assert.Equal(t, i == snapshot.Chunks-1, done)
+	}
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+}
+
+func TestManager_OfferInvalid(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ms      = newMultiStore(t)
+		manager = NewManager(t.TempDir(), ms, Options{Interval: 1, KeepRecent: 1}, log.NewNoopLogger())
+	)
+
+	snapshot := createSnapshot(t, manager, commitRandom(t, ms, 1).Version)
+
+	testTable := []struct {
+		name        string
+		modify      func(s *abci.Snapshot)
+		expectedErr error
+	}{
+		{"unknown format", func(s *abci.Snapshot) { s.Format = 2 }, ErrUnknownFormat},
+		{"invalid height", func(s *abci.Snapshot) { s.Height = 0 }, ErrInvalidMetadata},
+		{"no chunks", func(s *abci.Snapshot) { s.Chunks = 0 }, ErrInvalidMetadata},
+		{"chunk count mismatch", func(s *abci.Snapshot) { s.Chunks++ }, ErrInvalidMetadata},
+		{"invalid metadata", func(s *abci.Snapshot) { s.Metadata = []byte("metadata") }, ErrInvalidMetadata},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			offered := *snapshot
+			testCase.modify(&offered)
+
+			m := NewManager(t.TempDir(), newMultiStore(t), Options{}, log.NewNoopLogger())
+			assert.ErrorIs(t, m.Offer(&offered, nil, nil), testCase.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package snapshots
+
+import (
+	"bytes"
+	"crypto/sha256"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// ErrInvalidChunk is returned when an applied chunk doesn't match its hash
+var ErrInvalidChunk = goerrors.New("invalid snapshot chunk")
+
+// restore is the state of a snapshot being restored
+type restore struct {
+	snapshot *abci.Snapshot
+	hashes   [][]byte // the expected chunk hashes
+	next     uint32   // the index of the next chunk to apply
+
+	appHash []byte                       // the expected hash of the restored state
+	verify  func(types.MultiStore) error // verifies the restored state
+}
+
+// Offer starts the restore of the given snapshot, aborting any restore
+// in progress. The chunks of the snapshot are then applied in order
+// with ApplyChunk. The restored state must match appHash, and pass
+// verify, see CommitMultiStore.Restore.
+func (m *Manager) Offer(snapshot *abci.Snapshot, appHash []byte, verify func(types.MultiStore) error) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if snapshot == nil {
+		return fmt.Errorf("%w: nil snapshot", ErrInvalidMetadata)
+	}
+
+	if snapshot.Format != Format {
+		return fmt.Errorf("%w: %d", ErrUnknownFormat, snapshot.Format)
+	}
+
+	if snapshot.Height <= 0 {
+		return fmt.Errorf("%w: invalid height %d", ErrInvalidMetadata, snapshot.Height)
+	}
+
+	if snapshot.Chunks == 0 || snapshot.Chunks > maxChunks {
+		return fmt.Errorf("%w: invalid chunk count %d", ErrInvalidMetadata, snapshot.Chunks)
+	}
+
+	var metadata Metadata
+	if err := amino.Unmarshal(snapshot.Metadata, &metadata); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+
+	if len(metadata.ChunkHashes) != int(snapshot.Chunks) {
+		return fmt.Errorf(
+			"%w: found %d chunk hashes for %d chunks",
+			ErrInvalidMetadata,
+			len(metadata.ChunkHashes),
+			snapshot.Chunks,
+		)
+	}
+
+	// Start from a clean restore directory
+	dir := m.restoreDir()
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create restore directory: %w", err)
+	}
+
+	m.restore = &restore{
+		snapshot: snapshot,
+		hashes:   metadata.ChunkHashes,
+		appHash:  appHash,
+		verify:   verify,
+	}
+
+	return nil
+}
+
+// ApplyChunk applies the next chunk of the snapshot being restored.
+// Once the last chunk is applied, the multistore is restored
+// and done is true.
+//
+// ErrInvalidChunk is returned if the chunk doesn't match its hash,
+// in which case the chunk can be applied again from another source.
+func (m *Manager) ApplyChunk(index uint32, chunk []byte) (done bool, err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.restore == nil {
+		return false, ErrNoRestore
+	}
+
+	if index != m.restore.next {
+		return false, fmt.Errorf("%w: got chunk %d, expected chunk %d", ErrUnexpectedChunk, index, m.restore.next)
+	}
+
+	hash := sha256.Sum256(chunk)
+	if !bytes.Equal(hash[:], m.restore.hashes[index]) {
+		return false, fmt.Errorf("%w: chunk %d does not match its hash", ErrInvalidChunk, index)
+	}
+
+	if err := os.WriteFile(chunkPath(m.restoreDir(), index), chunk, 0o644); err != nil {
+		return false, fmt.Errorf("unable to save snapshot chunk: %w", err)
+	}
+
+	m.restore.next++
+	if m.restore.next < m.restore.snapshot.Chunks {
+		return false, nil
+	}
+
+	// All the chunks are there, restore the multistore
+	rs := m.restore
+	m.restore = nil
+
+	defer os.RemoveAll(m.restoreDir())
+
+	r := &chunkReader{
+		dir:    m.restoreDir(),
+		chunks: rs.snapshot.Chunks,
+	}
+	defer r.Close()
+
+	if err := m.ms.Restore(rs.snapshot.Height, rs.appHash, r, rs.verify); err != nil {
+		return false, fmt.Errorf("unable to restore snapshot %d: %w", rs.snapshot.Height, err)
+	}
+
+	return true, nil
+}
+
+func (m *Manager) restoreDir() string {
+	return filepath.Join(m.dir, restoreDir)
+}
+
+// chunkReader reads the chunk files of a snapshot as a single stream,
+// opening them one at a time
+type chunkReader struct {
+	dir     string
+	chunks  uint32
+	next    uint32
+	current *os.File
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next == r.chunks {
+				return 0, io.EOF
+			}
+
+			f, err := os.Open(chunkPath(r.dir, r.next))
+			if err != nil {
+				return 0, err
+			}
+
+			r.current = f
+			r.next++
+		}
+
+		n, err := r.current.Read(p)
+		if goerrors.Is(err, io.EOF) {
+			r.current.Close()
+			r.current = nil
+
+			if n == 0 {
+				continue
+			}
+
+			return n, nil
+		}
+
+		return n, err
+	}
+}
+
+// Close closes the current chunk file, if any
+func (r *chunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	err := r.current.Close()
+	r.current = nil
+
+	return err
+}
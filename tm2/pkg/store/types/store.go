@@ -3,6 +3,7 @@ package types
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
@@ -132,6 +133,34 @@ type CommitMultiStore interface {
 	// (height). An error is returned if any store cannot be loaded. This
 	// should only be used for querying and iterating at past heights.
 	MultiImmutableCacheWrapWithVersion(version int64) (MultiStore, error)
+
+	// Snapshot takes a snapshot of the state of the stores at the latest
+	// height, for state sync. The snapshot isn't affected by the later
+	// commits, and can be written in the background. It requires the dbs
+	// of the stores to implement dbm.Snapshotter.
+	Snapshot() (StoreSnapshot, error)
+
+	// Restore restores the empty stores from a snapshot at the given height,
+	// and loads the restored version. The hash of the restored version must
+	// be the given one, and the restored state must pass verify, if not nil,
+	// which checks the stores that aren't part of the hash. On failure, the
+	// restored entries are deleted.
+	Restore(height int64, hash []byte, r io.Reader, verify func(MultiStore) error) error
+}
+
+// StoreSnapshot is the state of the stores of a CommitMultiStore at a
+// height, taken by CommitMultiStore.Snapshot.
+type StoreSnapshot interface {
+	// Height returns the height of the state.
+	Height() int64
+
+	// Write writes the state to w, in the format read by
+	// CommitMultiStore.Restore.
+	Write(w io.Writer) error
+
+	// Close releases the snapshot. It must be called once the snapshot
+	// is no longer used.
+	Close() error
 }
 
 // CommitID contains the tree version number and its merkle root.